@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +31,8 @@ func (r *RootCmd) create() *serpent.Command {
 		parameterFlags     workspaceParameterFlags
 		autoUpdates        string
 		copyParametersFrom string
+		presetName         string
+		fromSnapshot       string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -40,9 +44,21 @@ func (r *RootCmd) create() *serpent.Command {
 				Description: "Create a workspace for another user (if you have permission)",
 				Command:     "coder create <username>/<workspace_name>",
 			},
+			example{
+				Description: "Create a workspace with the parameter values of a preset declared by the template",
+				Command:     "coder create <workspace_name> --template <template_name> --preset \"Small Go dev\"",
+			},
+			example{
+				Description: "Create a workspace with the data of a snapshot of another workspace",
+				Command:     "coder create <workspace_name> --from-snapshot <source_workspace>/<snapshot_name>",
+			},
 		),
 		Middleware: serpent.Chain(r.InitClient(client)),
 		Handler: func(inv *serpent.Invocation) error {
+			if presetName != "" && copyParametersFrom != "" {
+				return xerrors.New("--preset cannot be used together with --copy-parameters-from")
+			}
+
 			organization, err := CurrentOrganization(r, inv, client)
 			if err != nil {
 				return err
@@ -93,6 +109,24 @@ func (r *RootCmd) create() *serpent.Command {
 				templateName = sourceWorkspace.TemplateName
 			}
 
+			var snapshot codersdk.WorkspaceSnapshot
+			if fromSnapshot != "" {
+				snapshot, err = namedWorkspaceSnapshot(inv.Context(), client, fromSnapshot)
+				if err != nil {
+					return err
+				}
+				snapshotWorkspace, err := client.Workspace(inv.Context(), snapshot.WorkspaceID)
+				if err != nil {
+					return xerrors.Errorf("get snapshot workspace: %w", err)
+				}
+				if templateName == "" {
+					_, _ = fmt.Fprintf(inv.Stdout, "Coder will use the same template %q as the snapshot workspace.\n", snapshotWorkspace.TemplateName)
+					templateName = snapshotWorkspace.TemplateName
+				} else if templateName != snapshotWorkspace.TemplateName {
+					return xerrors.Errorf("snapshot %q was taken from a workspace of template %q", snapshot.Name, snapshotWorkspace.TemplateName)
+				}
+			}
+
 			var template codersdk.Template
 			var templateVersionID uuid.UUID
 			if templateName == "" {
@@ -136,7 +170,6 @@ func (r *RootCmd) create() *serpent.Command {
 				}
 
 				template = templateByName[option]
-				templateVersionID = template.ActiveVersionID
 			} else if sourceWorkspace.LatestBuild.TemplateVersionID != uuid.Nil {
 				template, err = client.Template(inv.Context(), sourceWorkspace.TemplateID)
 				if err != nil {
@@ -148,7 +181,15 @@ func (r *RootCmd) create() *serpent.Command {
 				if err != nil {
 					return xerrors.Errorf("get template by name: %w", err)
 				}
-				templateVersionID = template.ActiveVersionID
+			}
+			if templateVersionID == uuid.Nil {
+				// The owner may be assigned to a release channel of the
+				// template that uses another version than the active one.
+				activeVersion, err := client.TemplateActiveVersionForUser(inv.Context(), template.ID, workspaceOwner)
+				if err != nil {
+					return xerrors.Errorf("get active template version: %w", err)
+				}
+				templateVersionID = activeVersion.TemplateVersionID
 			}
 
 			var schedSpec *string
@@ -173,6 +214,21 @@ func (r *RootCmd) create() *serpent.Command {
 				}
 			}
 
+			var preset codersdk.Preset
+			var presetParameters []codersdk.WorkspaceBuildParameter
+			if presetName != "" {
+				preset, err = findPreset(inv, client, templateVersionID, presetName)
+				if err != nil {
+					return err
+				}
+				for _, parameter := range preset.Parameters {
+					presetParameters = append(presetParameters, codersdk.WorkspaceBuildParameter{
+						Name:  parameter.Name,
+						Value: parameter.Value,
+					})
+				}
+			}
+
 			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
 				Action:            WorkspaceCreate,
 				TemplateVersionID: templateVersionID,
@@ -182,6 +238,7 @@ func (r *RootCmd) create() *serpent.Command {
 				RichParameters:    cliBuildParameters,
 
 				SourceWorkspaceParameters: sourceWorkspaceParameters,
+				PresetParameters:          presetParameters,
 			})
 			if err != nil {
 				return xerrors.Errorf("prepare build: %w", err)
@@ -207,6 +264,11 @@ func (r *RootCmd) create() *serpent.Command {
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
 				AutomaticUpdates:    codersdk.AutomaticUpdates(autoUpdates),
+				// The preset's values were already resolved above, but the
+				// preset is sent too so the server validates it against the
+				// template version.
+				TemplateVersionPresetID: preset.ID,
+				SnapshotID:              snapshot.ID,
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -259,12 +321,43 @@ func (r *RootCmd) create() *serpent.Command {
 			Description: "Specify the source workspace name to copy parameters from.",
 			Value:       serpent.StringOf(&copyParametersFrom),
 		},
+		serpent.Option{
+			Flag:        "preset",
+			Env:         "CODER_WORKSPACE_PRESET",
+			Description: "Specify the name of a preset of the template to take parameter values from. Parameter values given by other flags take precedence.",
+			Value:       serpent.StringOf(&presetName),
+		},
+		serpent.Option{
+			Flag:        "from-snapshot",
+			Env:         "CODER_WORKSPACE_FROM_SNAPSHOT",
+			Description: "Specify a snapshot to restore the data of the workspace from, as <workspace>/<snapshot>. The workspace is created from the template of the snapshot.",
+			Value:       serpent.StringOf(&fromSnapshot),
+		},
 		cliui.SkipPromptOption(),
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
 	return cmd
 }
 
+// findPreset returns the preset of the template version with the given name.
+func findPreset(inv *serpent.Invocation, client *codersdk.Client, templateVersionID uuid.UUID, name string) (codersdk.Preset, error) {
+	presets, err := client.TemplateVersionPresets(inv.Context(), templateVersionID)
+	if err != nil {
+		return codersdk.Preset{}, xerrors.Errorf("get template version presets: %w", err)
+	}
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+		names = append(names, strconv.Quote(preset.Name))
+	}
+	if len(names) == 0 {
+		return codersdk.Preset{}, xerrors.Errorf("preset %q not found: the template does not declare any presets", name)
+	}
+	return codersdk.Preset{}, xerrors.Errorf("preset %q not found, available presets: %s", name, strings.Join(names, ", "))
+}
+
 type prepWorkspaceBuildArgs struct {
 	Action            WorkspaceCLIAction
 	TemplateVersionID uuid.UUID
@@ -272,6 +365,7 @@ type prepWorkspaceBuildArgs struct {
 
 	LastBuildParameters       []codersdk.WorkspaceBuildParameter
 	SourceWorkspaceParameters []codersdk.WorkspaceBuildParameter
+	PresetParameters          []codersdk.WorkspaceBuildParameter
 
 	PromptBuildOptions bool
 	BuildOptions       []codersdk.WorkspaceBuildParameter
@@ -307,6 +401,7 @@ func prepWorkspaceBuild(inv *serpent.Invocation, client *codersdk.Client, args p
 	resolver := new(ParameterResolver).
 		WithLastBuildParameters(args.LastBuildParameters).
 		WithSourceWorkspaceParameters(args.SourceWorkspaceParameters).
+		WithPresetParameters(args.PresetParameters).
 		WithPromptBuildOptions(args.PromptBuildOptions).
 		WithBuildOptions(args.BuildOptions).
 		WithPromptRichParameters(args.PromptRichParameters).
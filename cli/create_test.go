@@ -536,6 +536,77 @@ func TestCreateWithRichParameters(t *testing.T) {
 	})
 }
 
+func TestCreateWithPreset(t *testing.T) {
+	t.Parallel()
+
+	echoResponses := &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "cpu", Description: "Number of CPUs", Mutable: true},
+						{Name: "image", Description: "Container image", Mutable: true},
+					},
+					Presets: []*proto.Preset{{
+						Name: "Small Go dev",
+						Parameters: []*proto.PresetParameter{
+							{Name: "cpu", Value: "2"},
+							{Name: "image", Value: "golang"},
+						},
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	}
+
+	t.Run("Preset", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, echoResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		// Parameters given by flags take precedence over the preset.
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name,
+			"--preset", "Small Go dev", "--parameter", "image=rust", "-y")
+		clitest.SetupConfig(t, member, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("has been created")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		workspace, err := member.WorkspaceByOwnerAndName(ctx, codersdk.Me, "my-workspace", codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		buildParameters, err := member.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+			{Name: "cpu", Value: "2"},
+			{Name: "image", Value: "rust"},
+		}, buildParameters)
+	})
+
+	t.Run("UnknownPreset", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, echoResponses)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		inv, root := clitest.New(t, "create", "my-workspace", "--template", template.Name, "--preset", "GPU ML")
+		clitest.SetupConfig(t, member, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, `preset "GPU ML" not found, available presets: "Small Go dev"`)
+	})
+}
+
 func TestCreateValidateRichParameters(t *testing.T) {
 	t.Parallel()
 
@@ -73,6 +73,7 @@ func workspaceListRowFromWorkspace(now time.Time, workspace codersdk.Workspace)
 func (r *RootCmd) list() *serpent.Command {
 	var (
 		filter    cliui.WorkspaceFilter
+		shared    bool
 		formatter = cliui.NewOutputFormatter(
 			cliui.TableFormat(
 				[]workspaceListRow{},
@@ -102,11 +103,26 @@ func (r *RootCmd) list() *serpent.Command {
 			r.InitClient(client),
 		),
 		Handler: func(inv *serpent.Invocation) error {
-			res, err := queryConvertWorkspaces(inv.Context(), client, filter.Filter(), workspaceListRowFromWorkspace)
+			workspaceFilter := filter.Filter()
+			if shared {
+				workspaceFilter = codersdk.WorkspaceFilter{Shared: true}
+			}
+			// Only list the workspaces of the selected organization, if the
+			// user selected one.
+			organization, err := r.SelectedOrganization()
+			if err != nil {
+				return err
+			}
+			workspaceFilter.Organization = organization
+			res, err := queryConvertWorkspaces(inv.Context(), client, workspaceFilter, workspaceListRowFromWorkspace)
 			if err != nil {
 				return err
 			}
 
+			if len(res) == 0 && shared {
+				pretty.Fprintf(inv.Stderr, cliui.DefaultStyles.Prompt, "No workspaces are shared with you.\n")
+				return nil
+			}
 			if len(res) == 0 {
 				pretty.Fprintf(inv.Stderr, cliui.DefaultStyles.Prompt, "No workspaces found! Create one:\n")
 				_, _ = fmt.Fprintln(inv.Stderr)
@@ -124,6 +140,13 @@ func (r *RootCmd) list() *serpent.Command {
 			return err
 		},
 	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "shared",
+			Description: "Only list workspaces other users shared with you. Takes precedence over --all and --search.",
+			Value:       serpent.BoolOf(&shared),
+		},
+	}
 	filter.AttachOptions(&cmd.Options)
 	formatter.AttachOptions(&cmd.Options)
 	return cmd
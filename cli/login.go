@@ -101,6 +101,13 @@ func (r *RootCmd) loginWithPassword(
 	}
 
 	sessionToken := resp.SessionToken
+	if resp.TOTPTicket != "" {
+		sessionToken, err = loginWithTOTP(inv, client, resp)
+		if err != nil {
+			return err
+		}
+	}
+
 	config := r.createConfig()
 	err = config.Session().Write(sessionToken)
 	if err != nil {
@@ -124,6 +131,51 @@ func (r *RootCmd) loginWithPassword(
 	return nil
 }
 
+// loginWithTOTP finishes a password login that needs a second factor,
+// enrolling the user first if the deployment requires it.
+func loginWithTOTP(inv *serpent.Invocation, client *codersdk.Client, resp codersdk.LoginWithPasswordResponse) (string, error) {
+	ctx := inv.Context()
+	text := "Enter the " + pretty.Sprint(cliui.DefaultStyles.Field, "authentication code") + " from your app, or a recovery code:"
+	if resp.TOTPEnrollmentRequired {
+		enrollment, err := client.LoginTOTPEnroll(ctx, codersdk.LoginTOTPEnrollRequest{
+			Ticket: resp.TOTPTicket,
+		})
+		if err != nil {
+			return "", xerrors.Errorf("enroll in totp: %w", err)
+		}
+		_, _ = fmt.Fprintf(inv.Stdout,
+			"This deployment requires a second factor. Add this key to your authenticator app:\n\n\t%s\n\nOr import this URL:\n\n\t%s\n\n",
+			pretty.Sprint(cliui.DefaultStyles.Code, enrollment.Secret),
+			enrollment.URL,
+		)
+		text = "Enter the " + pretty.Sprint(cliui.DefaultStyles.Field, "authentication code") + " from your app:"
+	}
+
+	code, err := cliui.Prompt(inv, cliui.PromptOptions{
+		Text:     text,
+		Validate: cliui.ValidateNotEmpty,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("authentication code prompt: %w", err)
+	}
+
+	totpResp, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+		Ticket: resp.TOTPTicket,
+		Code:   code,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("login with totp: %w", err)
+	}
+
+	if len(totpResp.RecoveryCodes) > 0 {
+		_, _ = fmt.Fprintf(inv.Stdout,
+			"\nStore these recovery codes somewhere safe. Each can be used once if you lose your authenticator app:\n\n\t%s\n\n",
+			strings.Join(totpResp.RecoveryCodes, "\n\t"),
+		)
+	}
+	return totpResp.SessionToken, nil
+}
+
 func (r *RootCmd) login() *serpent.Command {
 	const firstUserTrialEnv = "CODER_FIRST_USER_TRIAL"
 
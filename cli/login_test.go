@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 )
@@ -148,6 +151,31 @@ func TestLogin(t *testing.T) {
 		w.RequireSuccess()
 	})
 
+	t.Run("InitialUserRequireTOTP", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireTOTP = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		inv, _ := clitest.New(
+			t, "login", client.URL.String(),
+			"--first-user-username", "testuser", "--first-user-email", "user@coder.com",
+			"--first-user-password", "SomeSecurePassword!", "--first-user-trial",
+		)
+		pty := ptytest.New(t).Attach(inv)
+		w := clitest.StartWithWaiter(t, inv)
+		pty.ExpectMatch("authenticator app")
+		secret := regexp.MustCompile(`[A-Z2-7]{32}`).FindString(pty.ExpectRegexMatch(`[A-Z2-7]{32}`))
+		code, err := userpassword.TOTPCode(secret, time.Now())
+		require.NoError(t, err)
+		pty.ExpectMatch("authentication code")
+		pty.WriteLine(code)
+		pty.ExpectMatch("recovery codes")
+		pty.ExpectMatch("Welcome to Coder")
+		w.RequireSuccess()
+	})
+
 	t.Run("InitialUserTTYConfirmPasswordFailAndReprompt", func(t *testing.T) {
 		t.Parallel()
 		ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) roles() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:     "roles",
+		Short:   "Manage custom roles",
+		Aliases: []string{"role"},
+		Long: "Custom roles grant sets of permissions in addition to the built in roles. Permissions are given as " +
+			"\"<resource>:<action>\", where both can be \"*\", and are denied instead when prefixed with \"!\". " +
+			"Custom roles are assigned like the built in ones, from the dashboard or the API.\n\n" +
+			formatExamples(
+				example{
+					Description: "Create a site role that can push template versions but not delete templates",
+					Command:     "coder roles create template-operator --site-permission template:read,template:create,template:update --user-permission file:create,file:read",
+				},
+				example{
+					Description: "Create a role for the current organization",
+					Command:     "coder roles create template-operator --org-role --org-permission template:read,template:update",
+				},
+			),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.rolesList(),
+			r.rolesCreate(),
+			r.rolesEdit(),
+			r.rolesDelete(),
+		},
+	}
+
+	return cmd
+}
+
+type roleRow struct {
+	// For json format:
+	Role codersdk.CustomRole `table:"-"`
+
+	// For table format:
+	Name                    string `json:"-" table:"name,default_sort"`
+	DisplayName             string `json:"-" table:"display name"`
+	SitePermissions         string `json:"-" table:"site permissions"`
+	OrganizationPermissions string `json:"-" table:"organization permissions"`
+	UserPermissions         string `json:"-" table:"user permissions"`
+}
+
+func (r *RootCmd) rolesList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]roleRow{}, nil),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Short:   "List the custom roles",
+		Aliases: []string{"ls"},
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			roles, err := client.CustomRoles(ctx)
+			if err != nil {
+				return xerrors.Errorf("get custom roles: %w", err)
+			}
+
+			rows := make([]roleRow, 0, len(roles))
+			for _, role := range roles {
+				rows = append(rows, roleRow{
+					Role:                    role,
+					Name:                    role.Name,
+					DisplayName:             role.DisplayName,
+					SitePermissions:         formatPermissions(role.SitePermissions),
+					OrganizationPermissions: formatPermissions(role.OrganizationPermissions),
+					UserPermissions:         formatPermissions(role.UserPermissions),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) rolesCreate() *serpent.Command {
+	var (
+		displayName string
+		orgRole     bool
+		site        []string
+		org         []string
+		user        []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <name>",
+		Short: "Create a custom role",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			req := codersdk.CreateCustomRoleRequest{
+				Name:        inv.Args[0],
+				DisplayName: displayName,
+			}
+			if orgRole {
+				organization, err := CurrentOrganization(r, inv, client)
+				if err != nil {
+					return xerrors.Errorf("get current organization: %w", err)
+				}
+				req.OrganizationID = &organization.ID
+			}
+			var err error
+			if req.SitePermissions, err = parsePermissions(site); err != nil {
+				return err
+			}
+			if req.OrganizationPermissions, err = parsePermissions(org); err != nil {
+				return err
+			}
+			if req.UserPermissions, err = parsePermissions(user); err != nil {
+				return err
+			}
+
+			role, err := client.CreateCustomRole(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("create custom role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Created role %s, assign it as %s.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, role.DisplayName),
+				pretty.Sprint(cliui.DefaultStyles.Code, role.Name),
+			)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "The name of the role shown in the dashboard. Defaults to the name of the role.",
+			Value:       serpent.StringOf(&displayName),
+		},
+		rolesOrgRoleOption(&orgRole),
+	}
+	cmd.Options = append(cmd.Options, rolesPermissionOptions(&site, &org, &user)...)
+	return cmd
+}
+
+func (r *RootCmd) rolesEdit() *serpent.Command {
+	var (
+		displayName string
+		orgRole     bool
+		site        []string
+		org         []string
+		user        []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "edit <name>",
+		Short: "Edit the display name and permissions of a custom role",
+		Long: "The given permission flags replace the permissions of that kind. Pass an empty value to remove all of them. " +
+			"The change applies to all users with the role.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			name, err := roleArgName(r, inv, client, orgRole)
+			if err != nil {
+				return err
+			}
+			role, err := client.CustomRole(ctx, name)
+			if err != nil {
+				return xerrors.Errorf("get custom role: %w", err)
+			}
+
+			req := codersdk.UpdateCustomRoleRequest{
+				DisplayName:             role.DisplayName,
+				SitePermissions:         role.SitePermissions,
+				OrganizationPermissions: role.OrganizationPermissions,
+				UserPermissions:         role.UserPermissions,
+			}
+			if displayName != "" {
+				req.DisplayName = displayName
+			}
+			if inv.ParsedFlags().Changed("site-permission") {
+				if req.SitePermissions, err = parsePermissions(site); err != nil {
+					return err
+				}
+			}
+			if inv.ParsedFlags().Changed("org-permission") {
+				if req.OrganizationPermissions, err = parsePermissions(org); err != nil {
+					return err
+				}
+			}
+			if inv.ParsedFlags().Changed("user-permission") {
+				if req.UserPermissions, err = parsePermissions(user); err != nil {
+					return err
+				}
+			}
+
+			role, err = client.UpdateCustomRole(ctx, name, req)
+			if err != nil {
+				return xerrors.Errorf("update custom role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated role %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, role.Name))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "The name of the role shown in the dashboard.",
+			Value:       serpent.StringOf(&displayName),
+		},
+		rolesOrgRoleOption(&orgRole),
+	}
+	cmd.Options = append(cmd.Options, rolesPermissionOptions(&site, &org, &user)...)
+	return cmd
+}
+
+func (r *RootCmd) rolesDelete() *serpent.Command {
+	var orgRole bool
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <name>",
+		Short: "Delete a custom role",
+		Long:  "The role is unassigned from all users that have it.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			rolesOrgRoleOption(&orgRole),
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			name, err := roleArgName(r, inv, client, orgRole)
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete role %s and unassign it from all users?", pretty.Sprint(cliui.DefaultStyles.Code, name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteCustomRole(ctx, name)
+			if err != nil {
+				return xerrors.Errorf("delete custom role: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(
+				inv.Stdout, "Deleted role "+pretty.Sprint(cliui.DefaultStyles.Keyword, name)+" at "+cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func rolesOrgRoleOption(orgRole *bool) serpent.Option {
+	return serpent.Option{
+		Flag:        "org-role",
+		Description: "Use a role of the current organization instead of a site role.",
+		Value:       serpent.BoolOf(orgRole),
+	}
+}
+
+func rolesPermissionOptions(site, org, user *[]string) []serpent.Option {
+	return []serpent.Option{
+		{
+			Flag:        "site-permission",
+			Description: "Permissions on all resources of the deployment. Only site roles have them.",
+			Value:       serpent.StringArrayOf(site),
+		},
+		{
+			Flag:        "org-permission",
+			Description: "Permissions on the resources of the organization of the role. Only organization roles have them.",
+			Value:       serpent.StringArrayOf(org),
+		},
+		{
+			Flag:        "user-permission",
+			Description: "Permissions on the resources owned by the users with the role.",
+			Value:       serpent.StringArrayOf(user),
+		},
+	}
+}
+
+// roleArgName returns the name a role argument is assigned with. Roles of the
+// current organization are suffixed with its ID.
+func roleArgName(r *RootCmd, inv *serpent.Invocation, client *codersdk.Client, orgRole bool) (string, error) {
+	if !orgRole {
+		return inv.Args[0], nil
+	}
+	organization, err := CurrentOrganization(r, inv, client)
+	if err != nil {
+		return "", xerrors.Errorf("get current organization: %w", err)
+	}
+	return inv.Args[0] + ":" + organization.ID.String(), nil
+}
+
+// parsePermissions parses permissions of the form "<resource>:<action>",
+// prefixed with "!" to deny them.
+func parsePermissions(values []string) ([]codersdk.Permission, error) {
+	permissions := make([]codersdk.Permission, 0, len(values))
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		negate := strings.HasPrefix(value, "!")
+		resource, action, ok := strings.Cut(strings.TrimPrefix(value, "!"), ":")
+		if !ok || resource == "" || action == "" {
+			return nil, xerrors.Errorf("invalid permission %q, expected <resource>:<action>", value)
+		}
+		permissions = append(permissions, codersdk.Permission{
+			Negate:       negate,
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       action,
+		})
+	}
+	return permissions, nil
+}
+
+func formatPermissions(permissions []codersdk.Permission) string {
+	formatted := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		value := string(permission.ResourceType) + ":" + permission.Action
+		if permission.Negate {
+			value = "!" + value
+		}
+		formatted = append(formatted, value)
+	}
+	return strings.Join(formatted, ", ")
+}
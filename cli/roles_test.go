@@ -0,0 +1,88 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "roles", "create", "template-operator",
+		"--display-name", "Template Operator",
+		"--site-permission", "template:read,template:update",
+		"--site-permission", "!template:delete",
+		"--user-permission", "file:*",
+	)
+	clitest.SetupConfig(t, client, root)
+	err := inv.Run()
+	require.NoError(t, err)
+
+	role, err := client.CustomRole(ctx, "template-operator")
+	require.NoError(t, err)
+	require.Equal(t, "Template Operator", role.DisplayName)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+		{ResourceType: codersdk.ResourceTemplate, Action: "update"},
+		{Negate: true, ResourceType: codersdk.ResourceTemplate, Action: "delete"},
+	}, role.SitePermissions)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceFile, Action: "*"},
+	}, role.UserPermissions)
+
+	inv, root = clitest.New(t, "roles", "create", "template-operator", "--org-role", "--org-permission", "template:read")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.NoError(t, err)
+	orgRole, err := client.CustomRole(ctx, "template-operator:"+owner.OrganizationID.String())
+	require.NoError(t, err)
+	require.Equal(t, &owner.OrganizationID, orgRole.OrganizationID)
+
+	// Only the given permissions are replaced.
+	inv, root = clitest.New(t, "roles", "edit", "template-operator", "--site-permission", "template:read")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.NoError(t, err)
+	role, err = client.CustomRole(ctx, "template-operator")
+	require.NoError(t, err)
+	require.Equal(t, "Template Operator", role.DisplayName)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+	}, role.SitePermissions)
+	require.Len(t, role.UserPermissions, 1)
+
+	var buf bytes.Buffer
+	inv, root = clitest.New(t, "roles", "list")
+	clitest.SetupConfig(t, client, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "Template Operator")
+	require.Contains(t, buf.String(), "template:read")
+	require.Contains(t, buf.String(), "template-operator:"+owner.OrganizationID.String())
+
+	inv, root = clitest.New(t, "roles", "delete", "template-operator", "--org-role", "--yes")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.NoError(t, err)
+	roles, err := client.CustomRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, "template-operator", roles[0].Name)
+
+	inv, root = clitest.New(t, "roles", "create", "invalid", "--site-permission", "template")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, `invalid permission "template"`)
+}
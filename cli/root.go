@@ -90,6 +90,8 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.roles(),
+		r.sessions(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
@@ -109,14 +111,19 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.rename(),
 		r.restart(),
 		r.schedules(),
+		r.share(),
 		r.show(),
+		r.snapshots(),
 		r.speedtest(),
 		r.ssh(),
 		r.start(),
 		r.stat(),
 		r.stop(),
+		r.transfer(),
 		r.unfavorite(),
+		r.unshare(),
 		r.update(),
+		r.workspaces(),
 
 		// Hidden
 		r.gitssh(),
@@ -455,9 +462,8 @@ func (r *RootCmd) Command(subcommands []*serpent.Command) (*serpent.Command, err
 			Flag:          varOrganizationSelect,
 			FlagShorthand: "z",
 			Env:           "CODER_ORGANIZATION",
-			Description:   "Select which organization (uuid or name) to use This overrides what is present in the config file.",
+			Description:   "Select which organization (uuid or name) to use. This overrides what is present in the config file.",
 			Value:         serpent.StringOf(&r.organizationSelect),
-			Hidden:        true,
 			Group:         globalGroup,
 		},
 		{
@@ -633,15 +639,28 @@ func (r *RootCmd) createAgentClient() (*agentsdk.Client, error) {
 }
 
 // CurrentOrganization returns the currently active organization for the authenticated user.
-func CurrentOrganization(r *RootCmd, inv *serpent.Invocation, client *codersdk.Client) (codersdk.Organization, error) {
+// SelectedOrganization returns the name or ID of the organization the user
+// selected with the flag or in the config file, or an empty string if they
+// didn't select one.
+func (r *RootCmd) SelectedOrganization() (string, error) {
+	if r.organizationSelect != "" {
+		return r.organizationSelect, nil
+	}
 	conf := r.createConfig()
-	selected := r.organizationSelect
-	if selected == "" && conf.Organization().Exists() {
-		org, err := conf.Organization().Read()
-		if err != nil {
-			return codersdk.Organization{}, xerrors.Errorf("read selected organization from config file %q: %w", conf.Organization(), err)
-		}
-		selected = org
+	if !conf.Organization().Exists() {
+		return "", nil
+	}
+	org, err := conf.Organization().Read()
+	if err != nil {
+		return "", xerrors.Errorf("read selected organization from config file %q: %w", conf.Organization(), err)
+	}
+	return org, nil
+}
+
+func CurrentOrganization(r *RootCmd, inv *serpent.Invocation, client *codersdk.Client) (codersdk.Organization, error) {
+	selected, err := r.SelectedOrganization()
+	if err != nil {
+		return codersdk.Organization{}, err
 	}
 
 	// Verify the org exists and the user is a member
@@ -74,6 +74,8 @@ import (
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/loginlimit"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics/insights"
@@ -166,24 +168,27 @@ func createOIDCConfig(ctx context.Context, vals *codersdk.DeploymentValues) (*co
 		Verifier: oidcProvider.Verifier(&oidc.Config{
 			ClientID: vals.OIDC.ClientID.String(),
 		}),
-		EmailDomain:         vals.OIDC.EmailDomain,
-		AllowSignups:        vals.OIDC.AllowSignups.Value(),
-		UsernameField:       vals.OIDC.UsernameField.String(),
-		EmailField:          vals.OIDC.EmailField.String(),
-		AuthURLParams:       vals.OIDC.AuthURLParams.Value,
-		IgnoreUserInfo:      vals.OIDC.IgnoreUserInfo.Value(),
-		GroupField:          vals.OIDC.GroupField.String(),
-		GroupFilter:         vals.OIDC.GroupRegexFilter.Value(),
-		GroupAllowList:      groupAllowList,
-		CreateMissingGroups: vals.OIDC.GroupAutoCreate.Value(),
-		GroupMapping:        vals.OIDC.GroupMapping.Value,
-		UserRoleField:       vals.OIDC.UserRoleField.String(),
-		UserRoleMapping:     vals.OIDC.UserRoleMapping.Value,
-		UserRolesDefault:    vals.OIDC.UserRolesDefault.GetSlice(),
-		SignInText:          vals.OIDC.SignInText.String(),
-		SignupsDisabledText: vals.OIDC.SignupsDisabledText.String(),
-		IconURL:             vals.OIDC.IconURL.String(),
-		IgnoreEmailVerified: vals.OIDC.IgnoreEmailVerified.Value(),
+		EmailDomain:               vals.OIDC.EmailDomain,
+		AllowSignups:              vals.OIDC.AllowSignups.Value(),
+		UsernameField:             vals.OIDC.UsernameField.String(),
+		EmailField:                vals.OIDC.EmailField.String(),
+		AuthURLParams:             vals.OIDC.AuthURLParams.Value,
+		IgnoreUserInfo:            vals.OIDC.IgnoreUserInfo.Value(),
+		GroupField:                vals.OIDC.GroupField.String(),
+		GroupFilter:               vals.OIDC.GroupRegexFilter.Value(),
+		GroupAllowList:            groupAllowList,
+		CreateMissingGroups:       vals.OIDC.GroupAutoCreate.Value(),
+		GroupMapping:              vals.OIDC.GroupMapping.Value,
+		UserRoleField:             vals.OIDC.UserRoleField.String(),
+		UserRoleMapping:           vals.OIDC.UserRoleMapping.Value,
+		UserRolesDefault:          vals.OIDC.UserRolesDefault.GetSlice(),
+		OrganizationField:         vals.OIDC.OrganizationField.String(),
+		OrganizationMapping:       vals.OIDC.OrganizationMapping.Value,
+		OrganizationAssignDefault: vals.OIDC.OrganizationAssignDefault.Value(),
+		SignInText:                vals.OIDC.SignInText.String(),
+		SignupsDisabledText:       vals.OIDC.SignupsDisabledText.String(),
+		IconURL:                   vals.OIDC.IconURL.String(),
+		IgnoreEmailVerified:       vals.OIDC.IgnoreEmailVerified.Value(),
 	}, nil
 }
 
@@ -596,6 +601,14 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if httpServers.TLSConfig != nil {
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
+			options.LoginLimit = loginlimit.Policy{
+				Window:          vals.LoginLimit.FailureWindow.Value(),
+				FreeAttempts:    vals.LoginLimit.FreeAttempts.Value(),
+				BaseDelay:       vals.LoginLimit.BaseDelay.Value(),
+				MaxDelay:        vals.LoginLimit.MaxDelay.Value(),
+				MaxFailures:     vals.LoginLimit.LockoutThreshold.Value(),
+				LockoutDuration: vals.LoginLimit.LockoutDuration.Value(),
+			}
 
 			if vals.StrictTransportSecurity > 0 {
 				options.StrictTransportSecurityCfg, err = httpmw.HSTSConfigOptions(
@@ -869,6 +882,28 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.SwaggerEndpoint = vals.Swagger.Enable.Value()
 			}
 
+			// Notifications are queued in the database and delivered by the
+			// manager. Without a configured delivery method they're dropped.
+			notificationsMethod, err := notifications.ParseMethod(vals.Notifications.Method.String())
+			if err != nil {
+				return xerrors.Errorf("parse notifications method: %w", err)
+			}
+			options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+			notificationsManager, err := notifications.NewManager(vals.Notifications, options.Database, logger.Named("notifications"))
+			if err != nil {
+				logger.Warn(ctx, "notifications are disabled", slog.F("method", notificationsMethod), slog.Error(err))
+			} else {
+				enqueuer, err := notifications.NewStoreEnqueuer(notificationsMethod, options.Database, vals.AccessURL.Value(), logger.Named("notifications"))
+				if err != nil {
+					return xerrors.Errorf("create notifications enqueuer: %w", err)
+				}
+				options.NotificationsEnqueuer = enqueuer
+				notificationsManager.Run(ctx)
+				defer func() {
+					_ = notificationsManager.Close()
+				}()
+			}
+
 			batcher, closeBatcher, err := batchstats.New(ctx,
 				batchstats.WithLogger(options.Logger.Named("batchstats")),
 				batchstats.WithStore(options.Database),
@@ -944,6 +979,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			var provisionerdWaitGroup sync.WaitGroup
 			defer provisionerdWaitGroup.Wait()
 			provisionerdMetrics := provisionerd.NewMetrics(options.PrometheusRegistry)
+			var moduleCache *terraform.ModuleCache
+			if size := vals.Provisioner.ModuleCacheSize.Value(); size > 0 && !vals.Provisioner.DaemonsEcho.Value() {
+				moduleCache, err = terraform.NewModuleCache(
+					filepath.Join(cacheDir, "modules"), size<<20, logger.Named("module_cache"), options.PrometheusRegistry,
+				)
+				if err != nil {
+					return xerrors.Errorf("create module cache: %w", err)
+				}
+			}
 			for i := int64(0); i < vals.Provisioner.Daemons.Value(); i++ {
 				suffix := fmt.Sprintf("%d", i)
 				// The suffix is added to the hostname, so we may need to trim to fit into
@@ -952,7 +996,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				name := fmt.Sprintf("%s-%s", hostname, suffix)
 				daemonCacheDir := filepath.Join(cacheDir, fmt.Sprintf("provisioner-%d", i))
 				daemon, err := newProvisionerDaemon(
-					ctx, coderAPI, provisionerdMetrics, logger, vals, daemonCacheDir, errCh, &provisionerdWaitGroup, name,
+					ctx, coderAPI, provisionerdMetrics, logger, vals, daemonCacheDir, moduleCache, errCh, &provisionerdWaitGroup, name,
 				)
 				if err != nil {
 					return xerrors.Errorf("create provisioner daemon: %w", err)
@@ -965,7 +1009,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger, options.Database)
+			purger := dbpurge.New(ctx, logger, options.Database, options.DeploymentValues)
 			defer purger.Close()
 
 			// Updates workspace usage
@@ -1037,7 +1081,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
-				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C)
+				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, options.NotificationsEnqueuer, logger, autobuildTicker.C)
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(vals.JobHangDetectorInterval.Value())
@@ -1337,6 +1381,7 @@ func newProvisionerDaemon(
 	logger slog.Logger,
 	cfg *codersdk.DeploymentValues,
 	cacheDir string,
+	moduleCache *terraform.ModuleCache,
 	errCh chan error,
 	wg *sync.WaitGroup,
 	name string,
@@ -1414,8 +1459,9 @@ func newProvisionerDaemon(
 					Logger:        logger.Named("terraform"),
 					WorkDirectory: workDir,
 				},
-				CachePath: tfDir,
-				Tracer:    tracer,
+				CachePath:   tfDir,
+				ModuleCache: moduleCache,
+				Tracer:      tracer,
 			})
 			if err != nil && !xerrors.Is(err, context.Canceled) {
 				select {
@@ -1426,6 +1472,48 @@ func newProvisionerDaemon(
 		}()
 
 		connector[string(database.ProvisionerTypeTerraform)] = sdkproto.NewDRPCProvisionerClient(terraformClient)
+
+		// OpenTofu is downloaded on first use, so serving it costs nothing
+		// until a template requires it.
+		tofuDir := filepath.Join(cacheDir, "tofu")
+		err = os.MkdirAll(tofuDir, 0o700)
+		if err != nil {
+			return nil, xerrors.Errorf("mkdir opentofu dir: %w", err)
+		}
+
+		tofuClient, tofuServer := drpc.MemTransportPipe()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			_ = tofuClient.Close()
+			_ = tofuServer.Close()
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+
+			err := terraform.Serve(ctx, &terraform.ServeOptions{
+				ServeOptions: &provisionersdk.ServeOptions{
+					Listener:      tofuServer,
+					Logger:        logger.Named("opentofu"),
+					WorkDirectory: workDir,
+				},
+				Engine:      terraform.EngineOpenTofu,
+				CachePath:   tofuDir,
+				ModuleCache: moduleCache,
+				Tracer:      tracer,
+			})
+			if err != nil && !xerrors.Is(err, context.Canceled) {
+				select {
+				case errCh <- err:
+				default:
+				}
+			}
+		}()
+
+		connector[string(database.ProvisionerTypeOpentofu)] = sdkproto.NewDRPCProvisionerClient(tofuClient)
 	}
 
 	return provisionerd.New(func(dialCtx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
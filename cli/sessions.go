@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) sessions() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "sessions",
+		Short: "Manage browser sessions, CLI logins and tokens",
+		Long: "Sessions are the API keys a user is logged in with. Revoking a session logs it out on all replicas.\n" + formatExamples(
+			example{
+				Description: "List your sessions",
+				Command:     "coder sessions ls",
+			},
+			example{
+				Description: "Log out all of your other sessions, e.g. after losing a laptop",
+				Command:     "coder sessions revoke --all-others",
+			},
+			example{
+				Description: "Log a user out everywhere (requires the Owner role)",
+				Command:     "coder sessions revoke --all-others --user alice --include-tokens",
+			},
+		),
+		Aliases: []string{"session"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.listSessions(),
+			r.revokeSessions(),
+		},
+	}
+	return cmd
+}
+
+type sessionListRow struct {
+	// For JSON format:
+	codersdk.UserSession `table:"-"`
+
+	// For table format:
+	ID        string    `json:"-" table:"id"`
+	Type      string    `json:"-" table:"type"`
+	Name      string    `json:"-" table:"name"`
+	IPAddress string    `json:"-" table:"ip address"`
+	UserAgent string    `json:"-" table:"user agent"`
+	LastUsed  time.Time `json:"-" table:"last used,default_sort"`
+	ExpiresAt time.Time `json:"-" table:"expires at"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	Current   bool      `json:"-" table:"current"`
+}
+
+func sessionListRowFromSession(session codersdk.UserSession) sessionListRow {
+	sessionType := string(session.Type)
+	if session.Impersonated {
+		sessionType += " (impersonated)"
+	}
+	return sessionListRow{
+		UserSession: session,
+		ID:          session.ID,
+		Type:        sessionType,
+		Name:        session.TokenName,
+		IPAddress:   session.IPAddress,
+		UserAgent:   session.UserAgent,
+		LastUsed:    session.LastUsed,
+		ExpiresAt:   session.ExpiresAt,
+		CreatedAt:   session.CreatedAt,
+		Current:     session.Current,
+	}
+}
+
+func (r *RootCmd) listSessions() *serpent.Command {
+	var (
+		user      string
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]sessionListRow{}, []string{"id", "type", "ip address", "user agent", "last used", "expires at", "current"}),
+			cliui.JSONFormat(),
+		)
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List active sessions",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			sessions, err := client.UserSessions(inv.Context(), user)
+			if err != nil {
+				return xerrors.Errorf("list sessions: %w", err)
+			}
+
+			rows := make([]sessionListRow, len(sessions))
+			for i, session := range sessions {
+				rows[i] = sessionListRowFromSession(session)
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "List the sessions of another user. Requires the Owner role.",
+			Default:     codersdk.Me,
+			Value:       serpent.StringOf(&user),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) revokeSessions() *serpent.Command {
+	var (
+		user          string
+		allOthers     bool
+		includeTokens bool
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "revoke [id]",
+		Short: "Revoke a session, or all sessions but the current one",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if allOthers == (len(inv.Args) == 1) {
+				return xerrors.New("specify either a session ID or --all-others")
+			}
+
+			if !allOthers {
+				err := client.RevokeUserSession(inv.Context(), user, inv.Args[0])
+				if err != nil {
+					return xerrors.Errorf("revoke session: %w", err)
+				}
+				cliui.Infof(inv.Stdout, "Session has been revoked.")
+				return nil
+			}
+
+			text := "Are you sure you want to revoke all of your other sessions?"
+			if user != codersdk.Me {
+				text = fmt.Sprintf("Are you sure you want to revoke all other sessions of %s?", user)
+			}
+			_, err := cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      text,
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			res, err := client.RevokeOtherUserSessions(inv.Context(), user, includeTokens)
+			if err != nil {
+				return xerrors.Errorf("revoke sessions: %w", err)
+			}
+			cliui.Infof(inv.Stdout, "Revoked %d sessions.", res.Revoked)
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Revoke the sessions of another user. Requires the Owner role. Revoking all of their sessions logs them out everywhere.",
+			Default:     codersdk.Me,
+			Value:       serpent.StringOf(&user),
+		},
+		{
+			Flag:        "all-others",
+			Description: "Revoke all sessions except the one running this command.",
+			Value:       serpent.BoolOf(&allOthers),
+		},
+		{
+			Flag:        "include-tokens",
+			Description: "Also revoke tokens when using --all-others.",
+			Value:       serpent.BoolOf(&includeTokens),
+		},
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
@@ -0,0 +1,98 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ListAndRevoke", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		key, err := memberClient.CreateAPIKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		other := codersdk.New(client.URL)
+		other.SetSessionToken(key.Key)
+		keyID := strings.Split(key.Key, "-")[0]
+
+		inv, root := clitest.New(t, "sessions", "ls", "--output=json")
+		clitest.SetupConfig(t, memberClient, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		var sessions []codersdk.UserSession
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &sessions))
+		require.Len(t, sessions, 2)
+
+		inv, root = clitest.New(t, "sessions", "ls")
+		clitest.SetupConfig(t, memberClient, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "USER AGENT")
+		require.Contains(t, buf.String(), keyID)
+
+		inv, root = clitest.New(t, "sessions", "revoke", keyID)
+		clitest.SetupConfig(t, memberClient, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		_, err = other.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("ForceLogout", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "revoke", "--all-others", "--include-tokens", "--user", member.Username, "--yes")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "Revoked 1 sessions")
+
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+		_, err = client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+
+	t.Run("NoTarget", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "sessions", "revoke")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "specify either a session ID or --all-others")
+	})
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+type workspaceACLRow struct {
+	Type string                 `json:"type" table:"type"`
+	Name string                 `json:"name" table:"name,default_sort"`
+	Role codersdk.WorkspaceRole `json:"role" table:"role"`
+}
+
+func (r *RootCmd) share() *serpent.Command {
+	var (
+		users     []string
+		groups    []string
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]workspaceACLRow{}, nil),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "share <workspace>",
+		Short:       "Share a workspace with other users and groups",
+		Long: "Users and groups granted the \"use\" role can connect to the workspace, its terminal and its apps. " +
+			"The \"admin\" role additionally allows starting, stopping and updating it. " +
+			"Without --user or --group, the users and groups the workspace is shared with are listed.\n\n" +
+			formatExamples(
+				example{
+					Description: "Let a user connect to your workspace",
+					Command:     "coder share my-workspace --user alice",
+				},
+				example{
+					Description: "Let a group manage your workspace",
+					Command:     "coder share my-workspace --group on-call:admin",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			ws, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			if len(users) == 0 && len(groups) == 0 {
+				acl, err := client.WorkspaceACL(ctx, ws.ID)
+				if err != nil {
+					return xerrors.Errorf("get workspace ACL: %w", err)
+				}
+				rows := make([]workspaceACLRow, 0, len(acl.Users)+len(acl.Groups))
+				for _, user := range acl.Users {
+					rows = append(rows, workspaceACLRow{Type: "user", Name: user.Username, Role: user.Role})
+				}
+				for _, group := range acl.Groups {
+					rows = append(rows, workspaceACLRow{Type: "group", Name: group.Name, Role: group.Role})
+				}
+				out, err := formatter.Format(ctx, rows)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(inv.Stdout, out)
+				return err
+			}
+
+			req, err := workspaceACLRequest(users, groups, true)
+			if err != nil {
+				return err
+			}
+			if err := client.UpdateWorkspaceACL(ctx, ws.ID, req); err != nil {
+				return xerrors.Errorf("update workspace ACL: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q shared.\n", ws.Name)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Share the workspace with a user, as <username>[:use|admin]. The role defaults to use.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Share the workspace with a group, as <name>[:use|admin]. The role defaults to use.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) unshare() *serpent.Command {
+	var (
+		users  []string
+		groups []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "unshare <workspace>",
+		Short:       "Stop sharing a workspace with users and groups",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			if len(users) == 0 && len(groups) == 0 {
+				return xerrors.New("at least one --user or --group must be specified")
+			}
+			ws, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			req, err := workspaceACLRequest(users, groups, false)
+			if err != nil {
+				return err
+			}
+			if err := client.UpdateWorkspaceACL(ctx, ws.ID, req); err != nil {
+				return xerrors.Errorf("update workspace ACL: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q is no longer shared with the given users and groups.\n", ws.Name)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Stop sharing the workspace with a user.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Stop sharing the workspace with a group.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+	}
+	return cmd
+}
+
+// workspaceACLRequest builds an ACL update from user and group names. When
+// share is false, the entries are removed from the ACL.
+func workspaceACLRequest(users, groups []string, share bool) (codersdk.UpdateWorkspaceACL, error) {
+	req := codersdk.UpdateWorkspaceACL{
+		UserPerms:  map[string]codersdk.WorkspaceRole{},
+		GroupPerms: map[string]codersdk.WorkspaceRole{},
+	}
+	for _, entry := range users {
+		name, role, err := parseWorkspaceACLEntry(entry, share)
+		if err != nil {
+			return req, err
+		}
+		req.UserPerms[name] = role
+	}
+	for _, entry := range groups {
+		name, role, err := parseWorkspaceACLEntry(entry, share)
+		if err != nil {
+			return req, err
+		}
+		req.GroupPerms[name] = role
+	}
+	return req, nil
+}
+
+func parseWorkspaceACLEntry(entry string, share bool) (string, codersdk.WorkspaceRole, error) {
+	if !share {
+		return entry, codersdk.WorkspaceRoleDeleted, nil
+	}
+	name, role, ok := strings.Cut(entry, ":")
+	if !ok {
+		return name, codersdk.WorkspaceRoleUse, nil
+	}
+	switch codersdk.WorkspaceRole(role) {
+	case codersdk.WorkspaceRoleUse, codersdk.WorkspaceRoleAdmin:
+		return name, codersdk.WorkspaceRole(role), nil
+	default:
+		return "", "", xerrors.Errorf("invalid role %q for %q, must be one of: use, admin", role, name)
+	}
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestShareUnshare(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db           = coderdtest.NewWithDatabase(t, nil)
+		owner                = coderdtest.CreateFirstUser(t, client)
+		memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		sharedClient, shared = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ws                   = dbfake.WorkspaceBuild(t, db, database.Workspace{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+	)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "share", ws.Workspace.Name, "--user", shared.Username+":admin")
+	clitest.SetupConfig(t, memberClient, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.Run()
+	require.NoError(t, err)
+
+	acl, err := memberClient.WorkspaceACL(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, acl.Users, 1)
+	require.Equal(t, shared.ID, acl.Users[0].ID)
+	require.Equal(t, codersdk.WorkspaceRoleAdmin, acl.Users[0].Role)
+
+	// Without flags, the ACL is listed.
+	buf.Reset()
+	inv, root = clitest.New(t, "share", ws.Workspace.Name)
+	clitest.SetupConfig(t, memberClient, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), shared.Username)
+	require.Contains(t, buf.String(), "admin")
+
+	// The shared user finds the workspace with list --shared.
+	buf.Reset()
+	inv, root = clitest.New(t, "list", "--shared", "--output", "json")
+	clitest.SetupConfig(t, sharedClient, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), ws.Workspace.ID.String())
+
+	buf.Reset()
+	inv, root = clitest.New(t, "unshare", ws.Workspace.Name, "--user", shared.Username)
+	clitest.SetupConfig(t, memberClient, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+
+	acl, err = memberClient.WorkspaceACL(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, acl.Users)
+
+	inv, root = clitest.New(t, "share", ws.Workspace.Name, "--user", shared.Username+":owner")
+	clitest.SetupConfig(t, memberClient, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, "invalid role")
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) snapshots() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "snapshots",
+		Short:       "Manage the snapshots of a workspace",
+		Aliases:     []string{"snapshot"},
+		Long: "A snapshot stops the workspace and tells its template to snapshot the data of the workspace, such as its volumes. " +
+			"New workspaces of the template can be restored from the snapshot with \"coder create --from-snapshot\".\n\n" +
+			formatExamples(
+				example{
+					Description: "Snapshot a workspace",
+					Command:     "coder snapshots create my-workspace datasets",
+				},
+				example{
+					Description: "Create a workspace from the snapshot",
+					Command:     "coder create my-clone --from-snapshot my-workspace/datasets",
+				},
+			),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.snapshotsList(),
+			r.snapshotsCreate(),
+			r.snapshotsDelete(),
+		},
+	}
+
+	return cmd
+}
+
+type snapshotRow struct {
+	// For json format:
+	Snapshot codersdk.WorkspaceSnapshot `table:"-"`
+
+	// For table format:
+	Name      string    `json:"-" table:"name"`
+	Status    string    `json:"-" table:"status"`
+	Artifacts string    `json:"-" table:"artifacts"`
+	CreatedAt time.Time `json:"-" table:"created at,default_sort"`
+}
+
+func (r *RootCmd) snapshotsList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]snapshotRow{}, nil),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Short:   "List the snapshots of a workspace",
+		Aliases: []string{"ls"},
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace snapshots: %w", err)
+			}
+
+			rows := make([]snapshotRow, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				artifacts := make([]string, 0, len(snapshot.Artifacts))
+				for name := range snapshot.Artifacts {
+					artifacts = append(artifacts, name)
+				}
+				sort.Strings(artifacts)
+				rows = append(rows, snapshotRow{
+					Snapshot:  snapshot,
+					Name:      snapshot.Name,
+					Status:    string(snapshot.Status),
+					Artifacts: strings.Join(artifacts, ", "),
+					CreatedAt: snapshot.CreatedAt,
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) snapshotsCreate() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <workspace> <snapshot>",
+		Short: "Snapshot a workspace",
+		Long:  "The workspace is stopped while the snapshot is taken. Start it again with \"coder start\".",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			if workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionStart {
+				_, err = cliui.Prompt(inv, cliui.PromptOptions{
+					Text:      fmt.Sprintf("Stop workspace %s to take the snapshot?", pretty.Sprint(cliui.DefaultStyles.Code, workspace.Name)),
+					IsConfirm: true,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+				Name: inv.Args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("create workspace snapshot: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, snapshot.WorkspaceBuildID)
+			if err != nil {
+				return xerrors.Errorf("watch build: %w", err)
+			}
+
+			snapshot, err = client.WorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+			if err != nil {
+				return xerrors.Errorf("get workspace snapshot: %w", err)
+			}
+			if len(snapshot.Artifacts) == 0 {
+				cliui.Warn(inv.Stderr, "The template didn't return any snapshot artifacts, so workspaces can't be restored from this snapshot.")
+			}
+
+			_, _ = fmt.Fprintf(
+				inv.Stdout,
+				"\nThe %s snapshot of %s has been created at %s!\n",
+				cliui.Keyword(snapshot.Name),
+				cliui.Keyword(workspace.Name),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) snapshotsDelete() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <workspace> <snapshot>",
+		Short: "Delete a snapshot",
+		Long: "Only the record of the snapshot is deleted. The artifacts of the snapshot are kept, " +
+			"and workspaces restored from it keep using them.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete snapshot %s of workspace %s?", pretty.Sprint(cliui.DefaultStyles.Code, inv.Args[1]), pretty.Sprint(cliui.DefaultStyles.Code, workspace.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteWorkspaceSnapshot(ctx, workspace.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("delete workspace snapshot: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(
+				inv.Stdout, "Deleted snapshot "+pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[1])+" at "+cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// namedWorkspaceSnapshot fetches and returns a snapshot by an identifier of
+// the form "workspace/snapshot", where workspace is anything namedWorkspace
+// accepts.
+func namedWorkspaceSnapshot(ctx context.Context, client *codersdk.Client, identifier string) (codersdk.WorkspaceSnapshot, error) {
+	index := strings.LastIndex(identifier, "/")
+	if index <= 0 || index == len(identifier)-1 {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("invalid snapshot %q, expected <workspace>/<snapshot>", identifier)
+	}
+	workspace, err := namedWorkspace(ctx, client, identifier[:index])
+	if err != nil {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("get snapshot workspace: %w", err)
+	}
+	snapshot, err := client.WorkspaceSnapshot(ctx, workspace.ID, identifier[index+1:])
+	if err != nil {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("get workspace snapshot: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name: "home",
+						Type: "example_volume",
+						Metadata: []*proto.Resource_Metadata{{
+							Key:   "snapshot.home",
+							Value: "snap-123",
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "snapshots", "create", workspace.Name, "datasets", "--yes")
+	clitest.SetupConfig(t, member, root)
+	err := inv.Run()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	inv, root = clitest.New(t, "snapshots", "list", workspace.Name)
+	clitest.SetupConfig(t, member, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "datasets")
+	require.Contains(t, buf.String(), "succeeded")
+	require.Contains(t, buf.String(), "home")
+
+	inv, root = clitest.New(t, "create", "clone", "--from-snapshot", workspace.Name+"/datasets", "--yes")
+	clitest.SetupConfig(t, member, root)
+	err = inv.Run()
+	require.NoError(t, err)
+
+	clone, err := member.WorkspaceByOwnerAndName(ctx, codersdk.Me, "clone", codersdk.WorkspaceOptions{})
+	require.NoError(t, err)
+	require.Equal(t, template.ID, clone.TemplateID)
+
+	inv, root = clitest.New(t, "snapshots", "delete", workspace.Name, "datasets", "--yes")
+	clitest.SetupConfig(t, member, root)
+	err = inv.Run()
+	require.NoError(t, err)
+
+	snapshots, err := member.WorkspaceSnapshots(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
@@ -5,6 +5,9 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
@@ -21,6 +24,9 @@ func (r *RootCmd) state() *serpent.Command {
 		Children: []*serpent.Command{
 			r.statePull(),
 			r.statePush(),
+			r.stateList(),
+			r.stateDiff(),
+			r.stateRollback(),
 		},
 	}
 	return cmd
@@ -139,3 +145,220 @@ func (r *RootCmd) statePush() *serpent.Command {
 	}
 	return cmd
 }
+
+type stateListRow struct {
+	codersdk.WorkspaceBuildState `table:"-"`
+
+	// For table format:
+	BuildNumber int32     `json:"-" table:"build,default_sort"`
+	Transition  string    `json:"-" table:"transition"`
+	Status      string    `json:"-" table:"status"`
+	Size        string    `json:"-" table:"size"`
+	Hash        string    `json:"-" table:"hash"`
+	CreatedAt   time.Time `json:"-" table:"created at"`
+}
+
+func stateListRowFromState(state codersdk.WorkspaceBuildState) stateListRow {
+	row := stateListRow{
+		WorkspaceBuildState: state,
+		BuildNumber:         state.BuildNumber,
+		Transition:          string(state.Transition),
+		Status:              string(state.Status),
+		Size:                "-",
+		Hash:                "-",
+		CreatedAt:           state.CreatedAt,
+	}
+	if state.Size > 0 {
+		row.Size = fmt.Sprintf("%d B", state.Size)
+		row.Hash = state.Hash
+		if len(row.Hash) > 12 {
+			row.Hash = row.Hash[:12]
+		}
+	}
+	return row
+}
+
+func (r *RootCmd) stateList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]stateListRow{}, []string{"build", "transition", "status", "size", "hash", "created at"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the Terraform state stored for each build of a workspace.",
+		Long:    "Builds without a size have no stored state, either because the build produced none or because the state was purged.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			states, err := client.WorkspaceBuildStates(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list states: %w", err)
+			}
+
+			rows := make([]stateListRow, len(states))
+			for i, state := range states {
+				rows[i] = stateListRowFromState(state)
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) stateDiff() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "diff <workspace> <from-build> [to-build]",
+		Short: "Compare the Terraform state of two builds of a workspace.",
+		Long:  "Lists the resources that were added (+), removed (-) or changed (~) between the builds. Compares against the latest build if no build to compare to is given.",
+		Middleware: serpent.Chain(
+			serpent.RequireRangeArgs(2, 3),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			from, err := namedWorkspaceBuild(inv, client, inv.Args[0], inv.Args[1])
+			if err != nil {
+				return err
+			}
+			to := workspace.LatestBuild
+			if len(inv.Args) > 2 {
+				to, err = namedWorkspaceBuild(inv, client, inv.Args[0], inv.Args[2])
+				if err != nil {
+					return err
+				}
+			}
+
+			diff, err := client.WorkspaceBuildStateDiff(inv.Context(), to.ID, from.ID)
+			if err != nil {
+				return xerrors.Errorf("compare states: %w", err)
+			}
+
+			if len(diff.Added)+len(diff.Removed)+len(diff.Changed) == 0 {
+				_, _ = fmt.Fprintf(inv.Stdout, "The state of build #%d and build #%d is identical.\n", from.BuildNumber, to.BuildNumber)
+				return nil
+			}
+			for _, address := range diff.Added {
+				_, _ = fmt.Fprintf(inv.Stdout, "+ %s\n", address)
+			}
+			for _, address := range diff.Removed {
+				_, _ = fmt.Fprintf(inv.Stdout, "- %s\n", address)
+			}
+			for _, address := range diff.Changed {
+				_, _ = fmt.Fprintf(inv.Stdout, "~ %s\n", address)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) stateRollback() *serpent.Command {
+	var transition string
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "rollback <workspace> <build>",
+		Short: "Restore the Terraform state of a previous build as a new build.",
+		Long:  "Starts a new build of the template version of the given build using its state, so the state matches the template. The build starts or stops the workspace like the restored build did, unless --transition is set. Only the state of the most recent builds of a workspace is retained.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			target, err := namedWorkspaceBuild(inv, client, inv.Args[0], inv.Args[1])
+			if err != nil {
+				return err
+			}
+			if target.ID == workspace.LatestBuild.ID {
+				return xerrors.Errorf("build #%d is already the latest build", target.BuildNumber)
+			}
+
+			state, err := client.WorkspaceBuildState(inv.Context(), target.ID)
+			if err != nil {
+				return err
+			}
+			if len(state) == 0 {
+				return xerrors.Errorf("build #%d has no stored state, it may have been purged", target.BuildNumber)
+			}
+
+			// Never take the transition from the latest build, which may be
+			// a failed delete that would destroy the workspace again.
+			buildTransition := target.Transition
+			if transition != "" {
+				buildTransition = codersdk.WorkspaceTransition(transition)
+			}
+			if buildTransition == codersdk.WorkspaceTransitionDelete {
+				return xerrors.Errorf("build #%d deleted the workspace, set --transition to start or stop to restore its state", target.BuildNumber)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Restore the state of build #%d to %s?", target.BuildNumber, cliui.Keyword(workspace.FullName())),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+				TemplateVersionID: target.TemplateVersionID,
+				Transition:        buildTransition,
+				ProvisionerState:  state,
+			})
+			if err != nil {
+				return err
+			}
+			return cliui.WorkspaceBuild(inv.Context(), inv.Stderr, client, build.ID)
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "transition",
+			Description: "Start or stop the workspace with the restored state. Defaults to the transition of the restored build.",
+			Value: serpent.EnumOf(&transition,
+				string(codersdk.WorkspaceTransitionStart),
+				string(codersdk.WorkspaceTransitionStop),
+			),
+		},
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
+
+// namedWorkspaceBuild fetches a build of a workspace by its build number.
+func namedWorkspaceBuild(inv *serpent.Invocation, client *codersdk.Client, identifier string, buildNumber string) (codersdk.WorkspaceBuild, error) {
+	if _, err := strconv.ParseUint(buildNumber, 10, 32); err != nil {
+		return codersdk.WorkspaceBuild{}, xerrors.Errorf("invalid build number %q", buildNumber)
+	}
+	owner, name, err := splitNamedWorkspace(identifier)
+	if err != nil {
+		return codersdk.WorkspaceBuild{}, err
+	}
+	return client.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(inv.Context(), owner, name, buildNumber)
+}
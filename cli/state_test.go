@@ -2,6 +2,7 @@ package cli_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,7 +10,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 
 	"github.com/stretchr/testify/require"
@@ -17,7 +21,9 @@ import (
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestStatePull(t *testing.T) {
@@ -159,3 +165,188 @@ func TestStatePush(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestStateList(t *testing.T) {
+	t.Parallel()
+	client, store := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        taUser.ID,
+	}).
+		Seed(database.WorkspaceBuild{BuildNumber: 1, ProvisionerState: []byte("first state")}).
+		Do()
+	_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+		Seed(database.WorkspaceBuild{BuildNumber: 2}).
+		Do()
+
+	inv, root := clitest.New(t, "state", "list", first.Workspace.Name, "--output", "json")
+	var out bytes.Buffer
+	inv.Stdout = &out
+	clitest.SetupConfig(t, templateAdmin, root)
+	err := inv.Run()
+	require.NoError(t, err)
+
+	var states []codersdk.WorkspaceBuildState
+	require.NoError(t, json.Unmarshal(out.Bytes(), &states))
+	require.Len(t, states, 2)
+	require.EqualValues(t, 2, states[0].BuildNumber)
+	require.Zero(t, states[0].Size)
+	require.EqualValues(t, 1, states[1].BuildNumber)
+	require.EqualValues(t, len("first state"), states[1].Size)
+	require.NotEmpty(t, states[1].Hash)
+}
+
+func TestStateDiff(t *testing.T) {
+	t.Parallel()
+	client, store := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        taUser.ID,
+	}).
+		Seed(database.WorkspaceBuild{
+			BuildNumber:      1,
+			ProvisionerState: []byte(`{"resources":[{"mode":"managed","type":"docker_volume","name":"home","instances":[{"attributes":{}}]}]}`),
+		}).
+		Do()
+	_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+		Seed(database.WorkspaceBuild{
+			BuildNumber:      2,
+			ProvisionerState: []byte(`{"resources":[{"mode":"managed","type":"coder_agent","name":"main","instances":[{"attributes":{}}]}]}`),
+		}).
+		Do()
+
+	inv, root := clitest.New(t, "state", "diff", first.Workspace.Name, "1")
+	var out bytes.Buffer
+	inv.Stdout = &out
+	clitest.SetupConfig(t, templateAdmin, root)
+	err := inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "+ coder_agent.main")
+	require.Contains(t, out.String(), "- docker_volume.home")
+}
+
+func TestStateRollback(t *testing.T) {
+	t.Parallel()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		wantState := []byte("good state")
+		first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        taUser.ID,
+		}).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, ProvisionerState: wantState}).
+			Do()
+		// The corrupted build is of a newer template version, which the
+		// restored state may not match.
+		_ = dbfake.TemplateVersion(t, store).
+			Seed(database.TemplateVersion{
+				OrganizationID: owner.OrganizationID,
+				TemplateID:     uuid.NullUUID{UUID: first.Template.ID, Valid: true},
+				CreatedBy:      taUser.ID,
+			}).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+			Seed(database.WorkspaceBuild{BuildNumber: 2, ProvisionerState: []byte("corrupted state")}).
+			Do()
+
+		// No provisioner daemon is running, so the command waits for the
+		// build until the test is done.
+		inv, root := clitest.New(t, "state", "rollback", first.Workspace.Name, "1", "--yes")
+		clitest.SetupConfig(t, templateAdmin, root)
+		_ = clitest.StartWithWaiter(t, inv)
+
+		//nolint:gocritic // Reading the state directly from the database.
+		ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort))
+		require.Eventually(t, func() bool {
+			build, err := store.GetLatestWorkspaceBuildByWorkspaceID(ctx, first.Workspace.ID)
+			return err == nil && build.BuildNumber == 3 && bytes.Equal(build.ProvisionerState, wantState) &&
+				build.TemplateVersionID == first.Build.TemplateVersionID
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("LatestDelete", func(t *testing.T) {
+		t.Parallel()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        taUser.ID,
+		}).
+			Seed(database.WorkspaceBuild{BuildNumber: 1, ProvisionerState: []byte("good state")}).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+			Seed(database.WorkspaceBuild{
+				BuildNumber:      2,
+				Transition:       database.WorkspaceTransitionDelete,
+				ProvisionerState: []byte("partially deleted"),
+			}).
+			Do()
+
+		inv, root := clitest.New(t, "state", "rollback", first.Workspace.Name, "1", "--yes")
+		clitest.SetupConfig(t, templateAdmin, root)
+		_ = clitest.StartWithWaiter(t, inv)
+
+		// The restored build starts the workspace instead of repeating the
+		// failed delete.
+		//nolint:gocritic // Reading the build directly from the database.
+		ctx := dbauthz.AsSystemRestricted(testutil.Context(t, testutil.WaitShort))
+		require.Eventually(t, func() bool {
+			build, err := store.GetLatestWorkspaceBuildByWorkspaceID(ctx, first.Workspace.ID)
+			return err == nil && build.BuildNumber == 3 && build.Transition == database.WorkspaceTransitionStart
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("DeleteBuild", func(t *testing.T) {
+		t.Parallel()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        taUser.ID,
+		}).
+			Seed(database.WorkspaceBuild{
+				BuildNumber:      1,
+				Transition:       database.WorkspaceTransitionDelete,
+				ProvisionerState: []byte("some state"),
+			}).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+			Seed(database.WorkspaceBuild{BuildNumber: 2, ProvisionerState: []byte("other state")}).
+			Do()
+
+		inv, root := clitest.New(t, "state", "rollback", first.Workspace.Name, "1", "--yes")
+		clitest.SetupConfig(t, templateAdmin, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "deleted the workspace")
+	})
+
+	t.Run("PurgedState", func(t *testing.T) {
+		t.Parallel()
+		client, store := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, taUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        taUser.ID,
+		}).
+			Seed(database.WorkspaceBuild{BuildNumber: 1}).
+			Do()
+		_ = dbfake.WorkspaceBuild(t, store, first.Workspace).
+			Seed(database.WorkspaceBuild{BuildNumber: 2, ProvisionerState: []byte("some state")}).
+			Do()
+
+		inv, root := clitest.New(t, "state", "rollback", first.Workspace.Name, "1", "--yes")
+		clitest.SetupConfig(t, templateAdmin, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "no stored state")
+	})
+}
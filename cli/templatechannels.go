@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) templateChannels() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:     "channels",
+		Short:   "Manage the release channels of a template",
+		Aliases: []string{"channel"},
+		Long: "Release channels point at a version of the template. Workspaces of the users and groups assigned to a channel " +
+			"are created, updated and required to use that version instead of the active one.\n\n" +
+			formatExamples(
+				example{
+					Description: "Put a group on a canary version of a template",
+					Command:     "coder templates channels create my-template canary --version my-version --group platform",
+				},
+				example{
+					Description: "Move the canary channel to a newer version",
+					Command:     "coder templates channels edit my-template canary --version my-next-version",
+				},
+			),
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.templateChannelsList(),
+			r.templateChannelsCreate(),
+			r.templateChannelsEdit(),
+			r.templateChannelsDelete(),
+		},
+	}
+
+	return cmd
+}
+
+type templateChannelRow struct {
+	// For json format:
+	Channel codersdk.TemplateReleaseChannel `table:"-"`
+
+	// For table format:
+	Name    string `json:"-" table:"name,default_sort"`
+	Version string `json:"-" table:"version"`
+	Users   string `json:"-" table:"users"`
+	Groups  string `json:"-" table:"groups"`
+}
+
+func (r *RootCmd) templateChannelsList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]templateChannelRow{}, nil),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "list <template>",
+		Short: "List the release channels of a template",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := CurrentOrganization(r, inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			channels, err := client.TemplateReleaseChannels(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("get template release channels: %w", err)
+			}
+
+			rows := make([]templateChannelRow, 0, len(channels))
+			for _, channel := range channels {
+				users := make([]string, 0, len(channel.UserIDs))
+				for _, userID := range channel.UserIDs {
+					user, err := client.User(ctx, userID.String())
+					if err != nil {
+						return xerrors.Errorf("get user %q: %w", userID, err)
+					}
+					users = append(users, user.Username)
+				}
+				groups := make([]string, 0, len(channel.GroupIDs))
+				for _, groupID := range channel.GroupIDs {
+					// The group endpoints are only available with a license,
+					// so fall back to the ID.
+					name := groupID.String()
+					if group, err := client.Group(ctx, groupID); err == nil {
+						name = group.Name
+					}
+					groups = append(groups, name)
+				}
+				rows = append(rows, templateChannelRow{
+					Channel: channel,
+					Name:    channel.Name,
+					Version: channel.TemplateVersionName,
+					Users:   strings.Join(users, ", "),
+					Groups:  strings.Join(groups, ", "),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templateChannelsCreate() *serpent.Command {
+	var (
+		versionName string
+		users       []string
+		groups      []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <template> <channel>",
+		Short: "Create a release channel for a template",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := CurrentOrganization(r, inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			version, err := client.TemplateVersionByName(ctx, template.ID, versionName)
+			if err != nil {
+				return xerrors.Errorf("get template version by name: %w", err)
+			}
+			userIDs, err := channelUserIDs(ctx, client, users)
+			if err != nil {
+				return err
+			}
+			groupIDs, err := channelGroupIDs(ctx, client, organization.ID, groups)
+			if err != nil {
+				return err
+			}
+
+			channel, err := client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+				Name:              inv.Args[1],
+				TemplateVersionID: version.ID,
+				UserIDs:           userIDs,
+				GroupIDs:          groupIDs,
+			})
+			if err != nil {
+				return xerrors.Errorf("create template release channel: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Created release channel %s of template %s using version %s.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, channel.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, channel.TemplateVersionName),
+			)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "version",
+			Description: "The name of the template version the channel uses.",
+			Value:       serpent.StringOf(&versionName),
+			Required:    true,
+		},
+		{
+			Flag:        "user",
+			Description: "Assign a user to the channel.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Assign a group to the channel.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) templateChannelsEdit() *serpent.Command {
+	var (
+		versionName string
+		users       []string
+		groups      []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "edit <template> <channel>",
+		Short: "Edit the version and assignments of a release channel",
+		Long:  "The given --user and --group flags replace the users or groups assigned to the channel. Pass an empty value to remove all of them.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := CurrentOrganization(r, inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+
+			var req codersdk.UpdateTemplateReleaseChannelRequest
+			if versionName != "" {
+				version, err := client.TemplateVersionByName(ctx, template.ID, versionName)
+				if err != nil {
+					return xerrors.Errorf("get template version by name: %w", err)
+				}
+				req.TemplateVersionID = &version.ID
+			}
+			if inv.ParsedFlags().Changed("user") {
+				userIDs, err := channelUserIDs(ctx, client, users)
+				if err != nil {
+					return err
+				}
+				req.UserIDs = &userIDs
+			}
+			if inv.ParsedFlags().Changed("group") {
+				groupIDs, err := channelGroupIDs(ctx, client, organization.ID, groups)
+				if err != nil {
+					return err
+				}
+				req.GroupIDs = &groupIDs
+			}
+
+			channel, err := client.UpdateTemplateReleaseChannel(ctx, template.ID, inv.Args[1], req)
+			if err != nil {
+				return xerrors.Errorf("update template release channel: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Updated release channel %s of template %s using version %s.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, channel.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, channel.TemplateVersionName),
+			)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "version",
+			Description: "The name of the template version the channel uses.",
+			Value:       serpent.StringOf(&versionName),
+		},
+		{
+			Flag:        "user",
+			Description: "Assign a user to the channel.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Assign a group to the channel.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) templateChannelsDelete() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <template> <channel>",
+		Short: "Delete a release channel",
+		Long:  "The users and groups assigned to the channel go back to the active version of the template.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := CurrentOrganization(r, inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete release channel %s of template %s?", pretty.Sprint(cliui.DefaultStyles.Code, inv.Args[1]), pretty.Sprint(cliui.DefaultStyles.Code, template.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteTemplateReleaseChannel(ctx, template.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("delete template release channel: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(
+				inv.Stdout, "Deleted release channel "+pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[1])+" at "+cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// channelUserIDs resolves the usernames given to a channel command.
+func channelUserIDs(ctx context.Context, client *codersdk.Client, usernames []string) ([]uuid.UUID, error) {
+	userIDs := make([]uuid.UUID, 0, len(usernames))
+	for _, username := range usernames {
+		if username == "" {
+			continue
+		}
+		user, err := client.User(ctx, username)
+		if err != nil {
+			return nil, xerrors.Errorf("get user %q: %w", username, err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// channelGroupIDs resolves the group names given to a channel command.
+func channelGroupIDs(ctx context.Context, client *codersdk.Client, orgID uuid.UUID, names []string) ([]uuid.UUID, error) {
+	groupIDs := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		group, err := client.GroupByOrgAndName(ctx, orgID, name)
+		if err != nil {
+			return nil, xerrors.Errorf("get group %q: %w", name, err)
+		}
+		groupIDs = append(groupIDs, group.ID)
+	}
+	return groupIDs, nil
+}
@@ -0,0 +1,66 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateChannels(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	canary := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "templates", "channels", "create", template.Name, "canary", "--version", canary.Name, "--user", member.Username)
+	clitest.SetupConfig(t, client, root)
+	err := inv.Run()
+	require.NoError(t, err)
+
+	activeVersion, err := client.TemplateActiveVersionForUser(ctx, template.ID, member.Username)
+	require.NoError(t, err)
+	require.Equal(t, canary.ID, activeVersion.TemplateVersionID)
+
+	var buf bytes.Buffer
+	inv, root = clitest.New(t, "templates", "channels", "list", template.Name)
+	clitest.SetupConfig(t, client, root)
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "canary")
+	require.Contains(t, buf.String(), canary.Name)
+	require.Contains(t, buf.String(), member.Username)
+
+	// An empty value removes the users of the channel.
+	inv, root = clitest.New(t, "templates", "channels", "edit", template.Name, "canary", "--user", "")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.NoError(t, err)
+
+	channels, err := client.TemplateReleaseChannels(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Empty(t, channels[0].UserIDs)
+	require.Equal(t, canary.ID, channels[0].TemplateVersionID)
+
+	inv, root = clitest.New(t, "templates", "channels", "delete", template.Name, "canary", "--yes")
+	clitest.SetupConfig(t, client, root)
+	err = inv.Run()
+	require.NoError(t, err)
+
+	channels, err = client.TemplateReleaseChannels(ctx, template.ID)
+	require.NoError(t, err)
+	require.Empty(t, channels)
+}
@@ -36,6 +36,7 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		maxWorkspaces                  int64
 	)
 	client := new(codersdk.Client)
 
@@ -166,6 +167,12 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				disableEveryoneGroup = disableEveryone
 			}
 
+			var maxWorkspacesLimit *int32
+			if userSetOption(inv, "max-workspaces") {
+				limit := int32(maxWorkspaces)
+				maxWorkspacesLimit = &limit
+			}
+
 			req := codersdk.UpdateTemplateMeta{
 				Name:               name,
 				DisplayName:        displayName,
@@ -189,6 +196,7 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				RequireActiveVersion:           requireActiveVersion,
 				DeprecationMessage:             deprecated,
 				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				MaxWorkspaces:                  maxWorkspacesLimit,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -322,6 +330,11 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Value:   serpent.BoolOf(&disableEveryone),
 			Default: "false",
 		},
+		{
+			Flag:        "max-workspaces",
+			Description: "The maximum number of workspaces that can be created from the template. 0 means no limit.",
+			Value:       serpent.Int64Of(&maxWorkspaces),
+		},
 		cliui.SkipPromptOption(),
 	}
 
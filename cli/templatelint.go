@@ -0,0 +1,118 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/serpent"
+)
+
+func (*RootCmd) templateLint() *serpent.Command {
+	var (
+		directory      string
+		failOnWarnings bool
+		formatter      = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				diagnostics, ok := data.([]codersdk.TemplateVersionDiagnostic)
+				if !ok {
+					return nil, xerrors.Errorf("expected []codersdk.TemplateVersionDiagnostic, got %T", data)
+				}
+				return formatTemplateDiagnostics(diagnostics), nil
+			}),
+			cliui.JSONFormat(),
+		)
+	)
+	cmd := &serpent.Command{
+		Use:   "lint",
+		Short: "Check a template for mistakes without uploading it",
+		Long: "Statically checks the Terraform files of a template for problems that would otherwise only be found on import or when a workspace is built. Exits with an error if any errors are found.\n" + formatExamples(
+			example{
+				Description: "Check the template in the current directory, failing on warnings too",
+				Command:     "coder templates lint --fail-on-warnings",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+		),
+		Options: serpent.OptionSet{
+			{
+				Flag:          "directory",
+				FlagShorthand: "d",
+				Description:   "Specify the directory of the template to check.",
+				Default:       ".",
+				Value:         serpent.StringOf(&directory),
+			},
+			{
+				Flag:        "fail-on-warnings",
+				Description: "Exit with an error if any warnings are found.",
+				Value:       serpent.BoolOf(&failOnWarnings),
+			},
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			dir, err := filepath.Abs(directory)
+			if err != nil {
+				return xerrors.Errorf("resolve directory: %w", err)
+			}
+			if _, err := os.Stat(dir); err != nil {
+				return xerrors.Errorf("stat directory: %w", err)
+			}
+
+			lintDiagnostics, err := terraform.Lint(dir)
+			if err != nil {
+				return err
+			}
+			var (
+				diagnostics              = make([]codersdk.TemplateVersionDiagnostic, 0, len(lintDiagnostics))
+				errorCount, warningCount int
+			)
+			for _, d := range lintDiagnostics {
+				severity := codersdk.TemplateVersionDiagnosticSeverityWarning
+				if d.Severity == proto.Diagnostic_ERROR {
+					severity = codersdk.TemplateVersionDiagnosticSeverityError
+					errorCount++
+				} else {
+					warningCount++
+				}
+				diagnostics = append(diagnostics, codersdk.TemplateVersionDiagnostic{
+					Severity: severity,
+					Code:     d.Code,
+					Summary:  d.Summary,
+					Detail:   d.Detail,
+					Filename: d.Filename,
+					Line:     int(d.Line),
+				})
+			}
+
+			out, err := formatter.Format(inv.Context(), diagnostics)
+			if err != nil {
+				return err
+			}
+			if out != "" {
+				_, _ = fmt.Fprintln(inv.Stdout, out)
+			}
+
+			switch {
+			case errorCount > 0:
+				return xerrors.Errorf("found %d error(s) and %d warning(s) in %s", errorCount, warningCount, prettyDirectoryPath(dir))
+			case warningCount > 0 && failOnWarnings:
+				return xerrors.Errorf("found %d warning(s) in %s", warningCount, prettyDirectoryPath(dir))
+			case warningCount > 0:
+				cliui.Warnf(inv.Stderr, "Found %d warning(s) in %s.", warningCount, prettyDirectoryPath(dir))
+			default:
+				cliui.Infof(inv.Stderr, "No problems found in %s.", prettyDirectoryPath(dir))
+			}
+			return nil
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
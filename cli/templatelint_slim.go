@@ -0,0 +1,21 @@
+//go:build slim
+
+package cli
+
+import "github.com/coder/serpent"
+
+func (*RootCmd) templateLint() *serpent.Command {
+	root := &serpent.Command{
+		Use:   "lint",
+		Short: "Check a template for mistakes without uploading it",
+		// We accept RawArgs so all commands and flags are accepted.
+		RawArgs: true,
+		Hidden:  true,
+		Handler: func(inv *serpent.Invocation) error {
+			SlimUnsupported(inv.Stderr, "templates lint")
+			return nil
+		},
+	}
+
+	return root
+}
@@ -0,0 +1,89 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestTemplateLint(t *testing.T) {
+	t.Parallel()
+
+	writeTemplate := func(t *testing.T, content string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0o600))
+		return dir
+	}
+	const unattachedAgent = `resource "coder_agent" "dev" {
+  os   = "linux"
+  arch = "amd64"
+}
+`
+
+	t.Run("Example", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		inv, _ := clitest.New(t, "templates", "init", "--id", "docker", dir)
+		clitest.Run(t, inv)
+
+		inv, _ = clitest.New(t, "templates", "lint", "--fail-on-warnings", "-d", dir)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.Run(t, inv)
+		require.Empty(t, stdout.String())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+		dir := writeTemplate(t, unattachedAgent+`
+resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+  slug     = "Code Server"
+}
+`)
+		inv, _ := clitest.New(t, "templates", "lint", "-d", dir)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.ErrorContains(t, err, "found 1 error(s) and 1 warning(s)")
+		require.Contains(t, stdout.String(), "main.tf:1: warning: coder_agent.dev is not used by any resource [agent-not-attached]")
+		require.Contains(t, stdout.String(), "main.tf:8: error: coder_app.code has an invalid slug")
+	})
+
+	t.Run("Warnings", func(t *testing.T) {
+		t.Parallel()
+		dir := writeTemplate(t, unattachedAgent)
+
+		inv, _ := clitest.New(t, "templates", "lint", "-d", dir)
+		clitest.Run(t, inv)
+
+		inv, _ = clitest.New(t, "templates", "lint", "--fail-on-warnings", "-d", dir)
+		err := inv.Run()
+		require.ErrorContains(t, err, "found 1 warning(s)")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		dir := writeTemplate(t, unattachedAgent)
+
+		inv, _ := clitest.New(t, "templates", "lint", "-o", "json", "-d", dir)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.Run(t, inv)
+
+		var diagnostics []codersdk.TemplateVersionDiagnostic
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &diagnostics))
+		require.Len(t, diagnostics, 1)
+		require.Equal(t, codersdk.TemplateVersionDiagnosticSeverityWarning, diagnostics[0].Severity)
+		require.Equal(t, "agent-not-attached", diagnostics[0].Code)
+		require.Equal(t, "main.tf", diagnostics[0].Filename)
+		require.Equal(t, 1, diagnostics[0].Line)
+	})
+}
@@ -26,6 +26,7 @@ import (
 func (r *RootCmd) templatePush() *serpent.Command {
 	var (
 		versionName          string
+		engine               string
 		provisioner          string
 		workdir              string
 		variablesFile        string
@@ -71,9 +72,12 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				createTemplate = true
 			}
 
-			err = uploadFlags.checkForLockfile(inv)
-			if err != nil {
-				return xerrors.Errorf("check for lockfile: %w", err)
+			// Manifests have no providers to lock.
+			if engine != string(codersdk.ProvisionerTypeKubernetes) {
+				err = uploadFlags.checkForLockfile(inv)
+				if err != nil {
+					return xerrors.Errorf("check for lockfile: %w", err)
+				}
 			}
 
 			message := uploadFlags.templateMessage(inv)
@@ -108,6 +112,12 @@ func (r *RootCmd) templatePush() *serpent.Command {
 				return err
 			}
 
+			// The hidden test flag takes precedence so tests can use the
+			// echo provisioner.
+			if provisioner == "" {
+				provisioner = engine
+			}
+
 			args := createValidTemplateVersionArgs{
 				Message:            message,
 				Client:             client,
@@ -164,7 +174,6 @@ func (r *RootCmd) templatePush() *serpent.Command {
 		{
 			Flag:        "test.provisioner",
 			Description: "Customize the provisioner backend.",
-			Default:     "terraform",
 			Value:       serpent.StringOf(&provisioner),
 			// This is for testing!
 			Hidden: true,
@@ -192,6 +201,12 @@ func (r *RootCmd) templatePush() *serpent.Command {
 			Description: "Alias of --variable.",
 			Value:       serpent.StringArrayOf(&commandLineVariables),
 		},
+		{
+			Flag:        "engine",
+			Description: "Specify the engine that executes the template. Builds are only assigned to provisioner daemons that run this engine. Constrain its version with required_version in the terraform block. Templates executed with kubernetes consist of Kubernetes manifests instead.",
+			Default:     "terraform",
+			Value:       serpent.EnumOf(&engine, string(codersdk.ProvisionerTypeTerraform), string(codersdk.ProvisionerTypeOpenTofu), string(codersdk.ProvisionerTypeKubernetes)),
+		},
 		{
 			Flag:        "provisioner-tag",
 			Description: "Specify a set of tags to target provisioner daemons.",
@@ -420,6 +435,17 @@ func createValidTemplateVersion(inv *serpent.Invocation, args createValidTemplat
 		return nil, xerrors.New(version.Job.Error)
 	}
 
+	// Warnings don't fail the import, so they would go unnoticed in the logs.
+	// Older deployments don't store diagnostics, so errors are ignored.
+	diagnostics, err := client.TemplateVersionDiagnostics(inv.Context(), version.ID)
+	if err == nil && len(diagnostics) > 0 {
+		lines := make([]string, 0, len(diagnostics))
+		for _, d := range diagnostics {
+			lines = append(lines, formatTemplateDiagnostic(d))
+		}
+		cliui.Warn(inv.Stderr, "The template has problems that may break workspaces:", lines...)
+	}
+
 	resources, err := client.TemplateVersionResources(inv.Context(), version.ID)
 	if err != nil {
 		return nil, err
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,17 +37,41 @@ func (r *RootCmd) templates() *serpent.Command {
 			r.templateEdit(),
 			r.templateInit(),
 			r.templateList(),
+			r.templateLint(),
 			r.templatePush(),
 			r.templateVersions(),
 			r.templateDelete(),
 			r.templatePull(),
 			r.archiveTemplateVersions(),
+			r.templateChannels(),
 		},
 	}
 
 	return cmd
 }
 
+// formatTemplateDiagnostic formats a template diagnostic on a single line, in
+// the style of compiler output.
+func formatTemplateDiagnostic(d codersdk.TemplateVersionDiagnostic) string {
+	return fmt.Sprintf("%s:%d: %s: %s [%s]", d.Filename, d.Line, d.Severity, d.Summary, d.Code)
+}
+
+// formatTemplateDiagnostics formats template diagnostics with their details
+// indented below them.
+func formatTemplateDiagnostics(diagnostics []codersdk.TemplateVersionDiagnostic) string {
+	var out strings.Builder
+	for i, d := range diagnostics {
+		if i > 0 {
+			_, _ = out.WriteString("\n")
+		}
+		_, _ = out.WriteString(formatTemplateDiagnostic(d))
+		if d.Detail != "" {
+			_, _ = out.WriteString("\n    " + d.Detail)
+		}
+	}
+	return out.String()
+}
+
 func selectTemplate(inv *serpent.Invocation, client *codersdk.Client, organization codersdk.Organization) (codersdk.Template, error) {
 	var empty codersdk.Template
 	ctx := inv.Context()
@@ -22,6 +22,10 @@ func (r *RootCmd) tokens() *serpent.Command {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token for CI that can only start and stop workspaces",
+				Command:     "coder tokens create --name ci --scope workspace:start-stop",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -48,6 +52,8 @@ func (r *RootCmd) createToken() *serpent.Command {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		scope         string
+		user          string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -58,8 +64,9 @@ func (r *RootCmd) createToken() *serpent.Command {
 			r.InitClient(client),
 		),
 		Handler: func(inv *serpent.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			res, err := client.CreateToken(inv.Context(), user, codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
+				Scope:     codersdk.APIKeyScope(scope),
 				TokenName: name,
 			})
 			if err != nil {
@@ -87,11 +94,33 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description:   "Specify a human-readable name.",
 			Value:         serpent.StringOf(&name),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Restrict the token to a subset of your permissions.",
+			Default:     string(codersdk.APIKeyScopeAll),
+			Value:       serpent.EnumOf(&scope, tokenScopes()...),
+		},
+		{
+			Flag:        "user",
+			Env:         "CODER_TOKEN_USER",
+			Description: "Create the token for another user, such as a service account. Requires permission to manage the user's tokens.",
+			Default:     codersdk.Me,
+			Value:       serpent.StringOf(&user),
+		},
 	}
 
 	return cmd
 }
 
+func tokenScopes() []string {
+	scopes := make([]string, 0, len(codersdk.APIKeyScopes))
+	for _, scope := range codersdk.APIKeyScopes {
+		scopes = append(scopes, string(scope))
+	}
+	return scopes
+}
+
 // tokenListRow is the type provided to the OutputFormatter.
 type tokenListRow struct {
 	// For JSON format:
@@ -100,6 +129,7 @@ type tokenListRow struct {
 	// For table format:
 	ID        string    `json:"-" table:"id,default_sort"`
 	TokenName string    `json:"token_name" table:"name"`
+	Scope     string    `json:"-" table:"scope"`
 	LastUsed  time.Time `json:"-" table:"last used"`
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
@@ -111,6 +141,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
+		Scope:     string(token.Scope),
 		LastUsed:  token.LastUsed,
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
@@ -120,7 +151,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 
 func (r *RootCmd) listTokens() *serpent.Command {
 	// we only display the 'owner' column if the --all argument is passed in
-	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
+	defaultCols := []string{"id", "name", "scope", "last used", "expires at", "created at"}
 	if slices.Contains(os.Args, "-a") || slices.Contains(os.Args, "--all") {
 		defaultCols = append(defaultCols, "owner")
 	}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) transfer() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "transfer <workspace> <new owner>",
+		Short:       "Transfer a workspace to another user",
+		Long: "Only administrators can transfer workspaces. The new owner must be able to use the template of " +
+			"the workspace and have enough quota for it. The workspace is rebuilt, so resources that reference " +
+			"the owner in the template pick up the new owner.\n\n" +
+			formatExamples(
+				example{
+					Description: "Hand the workspace of a departing user to a teammate",
+					Command:     "coder transfer alice/my-workspace bob",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Transfer %s from %s to %s?", cliui.Keyword(workspace.Name), cliui.Keyword(workspace.OwnerName), cliui.Keyword(inv.Args[1])),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			workspace, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+				Owner: inv.Args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("transfer workspace: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, workspace.LatestBuild.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(
+				inv.Stdout,
+				"\nThe %s workspace has been transferred to %s at %s!\n",
+				cliui.Keyword(workspace.Name), cliui.Keyword(workspace.OwnerName),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
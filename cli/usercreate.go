@@ -17,11 +17,12 @@ import (
 
 func (r *RootCmd) userCreate() *serpent.Command {
 	var (
-		email        string
-		username     string
-		password     string
-		disableLogin bool
-		loginType    string
+		email          string
+		username       string
+		password       string
+		disableLogin   bool
+		loginType      string
+		serviceAccount bool
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -67,6 +68,12 @@ func (r *RootCmd) userCreate() *serpent.Command {
 			} else if loginType != "" {
 				userLoginType = codersdk.LoginType(loginType)
 			}
+			if serviceAccount {
+				if password != "" || (loginType != "" && userLoginType != codersdk.LoginTypeNone) {
+					return xerrors.New("Service accounts cannot have a password or another login type")
+				}
+				userLoginType = codersdk.LoginTypeNone
+			}
 
 			if password == "" && userLoginType == codersdk.LoginTypePassword {
 				// Generate a random password
@@ -82,11 +89,20 @@ func (r *RootCmd) userCreate() *serpent.Command {
 				Password:       password,
 				OrganizationID: organization.ID,
 				UserLoginType:  userLoginType,
+				ServiceAccount: serviceAccount,
 			})
 			if err != nil {
 				return err
 			}
 
+			if serviceAccount {
+				_, _ = fmt.Fprintln(inv.Stderr, `A new service account has been created!
+It cannot log in. Create a token for it with:
+
+`+pretty.Sprint(cliui.DefaultStyles.Code, "coder tokens create --user "+username))
+				return nil
+			}
+
 			authenticationMethod := ""
 			switch codersdk.LoginType(strings.ToLower(string(userLoginType))) {
 			case codersdk.LoginTypePassword:
@@ -150,6 +166,12 @@ Create a workspace  `+pretty.Sprint(cliui.DefaultStyles.Code, "coder create")+`!
 				)),
 			Value: serpent.StringOf(&loginType),
 		},
+		{
+			Flag: "service-account",
+			Description: "Create a non-human user for automation. Service accounts cannot log in, only authenticate with API tokens, " +
+				"and do not count towards licensed seats.",
+			Value: serpent.BoolOf(&serviceAccount),
+		},
 	}
 	return cmd
 }
@@ -1,12 +1,15 @@
 package cli_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 )
 
@@ -37,4 +40,37 @@ func TestUserCreate(t *testing.T) {
 		}
 		<-doneChan
 	})
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		inv, root := clitest.New(t, "users", "create", "--service-account", "--username", "ci", "--email", "ci@coder.com")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+		pty.ExpectMatch("coder tokens create --user ci")
+
+		user, err := client.User(context.Background(), "ci")
+		require.NoError(t, err)
+		require.True(t, user.IsServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+
+		inv, root = clitest.New(t, "tokens", "create", "--user", "ci", "--name", "ci-token")
+		clitest.SetupConfig(t, client, root)
+		err = inv.Run()
+		require.NoError(t, err)
+		tokens, err := client.Tokens(context.Background(), "ci", codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+	})
+
+	t.Run("ServiceAccountPassword", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		inv, root := clitest.New(t, "users", "create", "--service-account", "--username", "ci", "--email", "ci@coder.com", "--password", "SomeSecurePassword!")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "Service accounts cannot have a password")
+	})
 }
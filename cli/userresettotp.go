@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) userResetTOTP() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "reset-totp <username|user_id>",
+		Short: "Remove a user's TOTP second factor, e.g. after they lose their authenticator app.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			user, err := client.User(ctx, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+
+			err = client.ResetTOTP(ctx, user.ID.String(), codersdk.ResetTOTPRequest{})
+			if err != nil {
+				return xerrors.Errorf("reset totp: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr,
+				"Successfully reset TOTP for "+pretty.Sprint(cliui.DefaultStyles.Keyword, user.Username)+".",
+			)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,47 @@
+package cli_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserResetTOTP(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+	member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	enrollment, err := member.EnrollTOTP(ctx, codersdk.Me)
+	require.NoError(t, err)
+	code, err := userpassword.TOTPCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "users", "reset-totp", user.Username)
+	clitest.SetupConfig(t, userAdmin, root)
+	pty := ptytest.New(t).Attach(inv)
+	errC := make(chan error)
+	go func() {
+		errC <- inv.Run()
+	}()
+	require.NoError(t, <-errC)
+	pty.ExpectMatch(user.Username)
+
+	status, err := member.UserTOTP(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, status.Enabled)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) userUnlock() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "unlock <username|user_id>",
+		Short: "Lift a user's lockout after too many failed login attempts.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			user, err := client.User(ctx, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+
+			err = client.UnlockUserLogin(ctx, user.ID.String())
+			if err != nil {
+				return xerrors.Errorf("unlock user: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stderr,
+				"Successfully unlocked "+pretty.Sprint(cliui.DefaultStyles.Keyword, user.Username)+".",
+			)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,59 @@
+package cli_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/loginlimit"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserUnlock(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		LoginLimit: loginlimit.Policy{
+			Window:          time.Hour,
+			MaxFailures:     2,
+			LockoutDuration: time.Hour,
+		},
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+	_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	anonClient := codersdk.New(client.URL)
+	for i := 0; i < 2; i++ {
+		_, err := anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "wrong-password",
+		})
+		require.Error(t, err)
+	}
+	_, err := client.UserLoginLockout(ctx, user.Username)
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "users", "unlock", user.Username)
+	clitest.SetupConfig(t, userAdmin, root)
+	pty := ptytest.New(t).Attach(inv)
+	errC := make(chan error)
+	go func() {
+		errC <- inv.Run()
+	}()
+	require.NoError(t, <-errC)
+	pty.ExpectMatch(user.Username)
+
+	_, err = client.UserLoginLockout(ctx, user.Username)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+type workspaceBulkRow struct {
+	Workspace string                             `json:"workspace" table:"workspace,default_sort"`
+	Status    codersdk.WorkspaceBulkResultStatus `json:"status" table:"status"`
+	Error     string                             `json:"error" table:"error"`
+}
+
+func workspaceBulkRows(operation codersdk.WorkspaceBulkOperation, filter func(codersdk.WorkspaceBulkResultStatus) bool) []workspaceBulkRow {
+	rows := make([]workspaceBulkRow, 0, len(operation.Workspaces))
+	for _, ws := range operation.Workspaces {
+		if filter != nil && !filter(ws.Status) {
+			continue
+		}
+		rows = append(rows, workspaceBulkRow{
+			Workspace: ws.OwnerName + "/" + ws.WorkspaceName,
+			Status:    ws.Status,
+			Error:     ws.Error,
+		})
+	}
+	return rows
+}
+
+func (r *RootCmd) workspaces() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "workspaces",
+		Short:       "Manage many workspaces at once",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.workspacesBulk(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) workspacesBulk() *serpent.Command {
+	var (
+		filter       cliui.WorkspaceFilter
+		concurrency  int64
+		dryRun       bool
+		pollInterval time.Duration
+		formatter    = cliui.NewOutputFormatter(
+			cliui.TableFormat([]workspaceBulkRow{}, nil),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "bulk <start|stop|update|delete>",
+		Short: "Start, stop, update or delete every workspace matching a search query",
+		Long: "The builds run on the server, at most --concurrency at a time. Workspaces with nothing to do, " +
+			"such as stopped workspaces when stopping, are skipped. Interrupting the command doesn't stop the " +
+			"operation.\n\n" +
+			formatExamples(
+				example{
+					Description: "Preview which workspaces of a template would be updated",
+					Command:     "coder workspaces bulk update --search 'template:docker' --dry-run",
+				},
+				example{
+					Description: "Stop all workspaces of a user",
+					Command:     "coder workspaces bulk stop --search 'owner:alice'",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			action := codersdk.WorkspaceBulkAction(inv.Args[0])
+			switch action {
+			case codersdk.WorkspaceBulkActionStart, codersdk.WorkspaceBulkActionStop,
+				codersdk.WorkspaceBulkActionUpdate, codersdk.WorkspaceBulkActionDelete:
+			default:
+				return xerrors.Errorf("invalid action %q, must be one of: start, stop, update, delete", action)
+			}
+			req := codersdk.CreateWorkspaceBulkOperationRequest{
+				Action:      action,
+				Query:       filter.Filter().FilterQuery,
+				Concurrency: int32(concurrency),
+				DryRun:      true,
+			}
+
+			preview, err := client.CreateWorkspaceBulkOperation(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("preview bulk operation: %w", err)
+			}
+			if dryRun {
+				out, err := formatter.Format(ctx, workspaceBulkRows(preview, nil))
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(inv.Stdout, out)
+				return err
+			}
+
+			pending := preview.Counts()[codersdk.WorkspaceBulkResultStatusPending]
+			if pending == 0 {
+				_, _ = fmt.Fprintf(inv.Stdout, "None of the %d matching workspaces need to %s.\n", len(preview.Workspaces), action)
+				return nil
+			}
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Run %s on %s workspaces?", cliui.Keyword(string(action)), cliui.Keyword(fmt.Sprint(pending))),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			req.DryRun = false
+			operation, err := client.CreateWorkspaceBulkOperation(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("create bulk operation: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Started bulk operation %s.\n", operation.ID)
+
+			ticker := time.NewTicker(pollInterval)
+			defer ticker.Stop()
+			var last string
+			for !operation.Status.Done() {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
+				operation, err = client.WorkspaceBulkOperation(ctx, operation.ID)
+				if err != nil {
+					return xerrors.Errorf("get bulk operation: %w", err)
+				}
+				progress := workspaceBulkProgress(operation)
+				if progress != last {
+					_, _ = fmt.Fprintln(inv.Stdout, progress)
+					last = progress
+				}
+			}
+
+			failed := workspaceBulkRows(operation, func(status codersdk.WorkspaceBulkResultStatus) bool {
+				return status == codersdk.WorkspaceBulkResultStatusFailed
+			})
+			if len(failed) == 0 {
+				_, _ = fmt.Fprintf(inv.Stdout, "\nThe bulk operation finished with status %s at %s!\n", cliui.Keyword(string(operation.Status)), cliui.Timestamp(time.Now()))
+				return nil
+			}
+			out, err := formatter.Format(ctx, failed)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return xerrors.Errorf("%d workspaces failed to %s", len(failed), action)
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "concurrency",
+			Description: "The maximum number of builds in progress at once, up to 100.",
+			Default:     "10",
+			Value:       serpent.Int64Of(&concurrency),
+		},
+		{
+			Flag:        "dry-run",
+			Description: "List the workspaces the operation would act on without building them.",
+			Value:       serpent.BoolOf(&dryRun),
+		},
+		{
+			Flag:        "poll-interval",
+			Description: "How often to check the progress of the operation.",
+			Default:     "2s",
+			Value:       serpent.DurationOf(&pollInterval),
+			Hidden:      true,
+		},
+		cliui.SkipPromptOption(),
+	}
+	filter.AttachOptions(&cmd.Options)
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func workspaceBulkProgress(operation codersdk.WorkspaceBulkOperation) string {
+	counts := operation.Counts()
+	done := counts[codersdk.WorkspaceBulkResultStatusSucceeded] +
+		counts[codersdk.WorkspaceBulkResultStatusFailed] +
+		counts[codersdk.WorkspaceBulkResultStatusSkipped]
+	return fmt.Sprintf("%d/%d done: %d succeeded, %d failed, %d skipped, %d building",
+		done, len(operation.Workspaces),
+		counts[codersdk.WorkspaceBulkResultStatusSucceeded],
+		counts[codersdk.WorkspaceBulkResultStatusFailed],
+		counts[codersdk.WorkspaceBulkResultStatusSkipped],
+		counts[codersdk.WorkspaceBulkResultStatusRunning],
+	)
+}
@@ -0,0 +1,83 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspacesBulk(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon:       true,
+		WorkspaceBulkOperationInterval: testutil.IntervalFast,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspaces := make([]codersdk.Workspace, 0, 2)
+	for i := 0; i < 2; i++ {
+		workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		workspaces = append(workspaces, workspace)
+	}
+
+	// A dry run lists the workspaces without stopping them.
+	inv, root := clitest.New(t, "workspaces", "bulk", "stop", "--dry-run")
+	clitest.SetupConfig(t, memberClient, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.Run()
+	require.NoError(t, err)
+	for _, workspace := range workspaces {
+		require.Contains(t, buf.String(), workspace.Name)
+	}
+	require.Contains(t, buf.String(), string(codersdk.WorkspaceBulkResultStatusPending))
+
+	inv, root = clitest.New(t, "workspaces", "bulk", "stop", "--concurrency", "1", "--poll-interval", "100ms")
+	clitest.SetupConfig(t, memberClient, root)
+	pty := ptytest.New(t).Attach(inv)
+	doneChan := make(chan struct{})
+	go func() {
+		defer close(doneChan)
+		err := inv.Run()
+		assert.NoError(t, err)
+	}()
+	pty.ExpectMatch("Run stop on 2 workspaces?")
+	pty.WriteLine("yes")
+	pty.ExpectMatch("2/2 done: 2 succeeded")
+	pty.ExpectMatch("finished with status completed")
+	<-doneChan
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	for _, workspace := range workspaces {
+		workspace, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+	}
+
+	// Nothing is left to stop.
+	inv, root = clitest.New(t, "workspaces", "bulk", "stop", "--yes")
+	clitest.SetupConfig(t, memberClient, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "None of the 2 matching workspaces need to stop.")
+
+	inv, root = clitest.New(t, "workspaces", "bulk", "restart")
+	clitest.SetupConfig(t, memberClient, root)
+	err = inv.Run()
+	require.ErrorContains(t, err, "invalid action")
+}
@@ -56,9 +56,19 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
+	if !scope.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid scope %q.", createToken.Scope),
+			Validations: []codersdk.ValidationError{{
+				Field:  "scope",
+				Detail: fmt.Sprintf("Must be one of %v.", database.AllAPIKeyScopeValues()),
+			}},
+		})
+		return
+	}
 
 	// default lifetime is 30 days
 	lifeTime := 30 * 24 * time.Hour
@@ -72,7 +82,7 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		tokenName = createToken.TokenName
 	}
 
-	err := api.validateAPIKeyLifetime(lifeTime)
+	err := api.validateAPIKeyLifetime(user, lifeTime)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Failed to validate create API key request.",
@@ -89,6 +99,8 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		Scope:           scope,
 		LifetimeSeconds: int64(lifeTime.Seconds()),
 		TokenName:       tokenName,
+		RemoteAddr:      r.RemoteAddr,
+		UserAgent:       r.UserAgent(),
 	})
 	if err != nil {
 		if database.IsUniqueViolation(err, database.UniqueIndexAPIKeyName) {
@@ -131,6 +143,7 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 		DefaultLifetime: api.DeploymentValues.SessionDuration.Value(),
 		LoginType:       database.LoginTypePassword,
 		RemoteAddr:      r.RemoteAddr,
+		UserAgent:       r.UserAgent(),
 		// All api generated keys will last 1 week. Browser login tokens have
 		// a shorter life.
 		ExpiresAt:       dbtime.Now().Add(lifeTime),
@@ -332,6 +345,7 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.SessionTracker.Revoked(ctx, api.Pubsub, keyID)
 
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
@@ -345,29 +359,34 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} codersdk.TokenConfig
 // @Router /users/{user}/keys/tokens/tokenconfig [get]
 func (api *API) tokenConfig(rw http.ResponseWriter, r *http.Request) {
-	values, err := api.DeploymentValues.WithoutSecrets()
-	if err != nil {
-		httpapi.InternalServerError(rw, err)
-		return
-	}
+	user := httpmw.UserParam(r)
 
 	httpapi.Write(
 		r.Context(), rw, http.StatusOK,
 		codersdk.TokenConfig{
-			MaxTokenLifetime: values.MaxTokenLifetime.Value(),
+			MaxTokenLifetime: api.maxTokenLifetime(user),
 		},
 	)
 }
 
-func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
+// maxTokenLifetime returns the longest lifetime the user's tokens may have.
+// Service accounts have their own limit.
+func (api *API) maxTokenLifetime(user database.User) time.Duration {
+	if user.IsServiceAccount {
+		return api.DeploymentValues.MaxServiceAccountTokenLifetime.Value()
+	}
+	return api.DeploymentValues.MaxTokenLifetime.Value()
+}
+
+func (api *API) validateAPIKeyLifetime(user database.User, lifetime time.Duration) error {
 	if lifetime <= 0 {
 		return xerrors.New("lifetime must be positive number greater than 0")
 	}
 
-	if lifetime > api.DeploymentValues.MaxTokenLifetime.Value() {
+	if maxLifetime := api.maxTokenLifetime(user); lifetime > maxLifetime {
 		return xerrors.Errorf(
 			"lifetime must be less than %v",
-			api.DeploymentValues.MaxTokenLifetime,
+			maxLifetime,
 		)
 	}
 
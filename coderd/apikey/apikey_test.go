@@ -104,6 +104,17 @@ func TestGenerate(t *testing.T) {
 				Scope:           "",
 			},
 		},
+		{
+			name: "Impersonation",
+			params: apikey.CreateParams{
+				UserID:          uuid.New(),
+				LoginType:       database.LoginTypePassword,
+				DefaultLifetime: time.Duration(0),
+				LifetimeSeconds: int64((30 * time.Minute).Seconds()),
+				RemoteAddr:      "1.2.3.4",
+				ImpersonatorID:  uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -169,6 +180,7 @@ func TestGenerate(t *testing.T) {
 			if tc.params.LoginType != "" {
 				assert.Equal(t, tc.params.LoginType, key.LoginType)
 			}
+			assert.Equal(t, tc.params.ImpersonatorID, key.ImpersonatorID)
 		})
 	}
 }
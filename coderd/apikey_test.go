@@ -85,6 +85,114 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenScopes(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	scopedClient := func(t *testing.T, scope codersdk.APIKeyScope) *codersdk.Client {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: scope,
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		return scoped
+	}
+
+	t.Run("WorkspaceStartStop", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scoped := scopedClient(t, codersdk.APIKeyScopeWorkspaceStartStop)
+
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+
+		build, err := scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, scoped, build.ID)
+		build, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, scoped, build.ID)
+
+		// The token can't be used for anything else, even though the user
+		// is an owner.
+		_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.Error(t, err)
+		err = scoped.DeleteTemplate(ctx, template.ID)
+		require.Error(t, err)
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+
+		// Creating a workspace would spend quota, so it is not allowed.
+		_, err = scoped.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "scoped",
+		})
+		require.Error(t, err)
+		workspaces, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+
+		// A dry run does not create a workspace, so the CLI can still
+		// preview a start.
+		_, err = scoped.CreateTemplateVersionDryRun(ctx, version.ID, codersdk.CreateTemplateVersionDryRunRequest{
+			WorkspaceName: workspace.Name,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("TemplatePush", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scoped := scopedClient(t, codersdk.APIKeyScopeTemplatePush)
+
+		pushed := coderdtest.CreateTemplateVersion(t, scoped, owner.OrganizationID, nil, func(r *codersdk.CreateTemplateVersionRequest) {
+			r.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, scoped, pushed.ID)
+		err := scoped.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: pushed.ID,
+		})
+		require.NoError(t, err)
+
+		err = scoped.DeleteTemplate(ctx, template.ID)
+		require.Error(t, err)
+		_, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		workspaces, err := scoped.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: "workspace:delete",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -144,6 +252,48 @@ func TestTokenUserSetMaxLifetime(t *testing.T) {
 	require.ErrorContains(t, err, "lifetime must be less")
 }
 
+func TestTokenServiceAccountMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	dc := coderdtest.DeploymentValues(t)
+	dc.MaxTokenLifetime = serpent.Duration(time.Hour * 24 * 7)
+	dc.MaxServiceAccountTokenLifetime = serpent.Duration(time.Hour * 24 * 365)
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dc,
+	})
+	first := coderdtest.CreateFirstUser(t, client)
+	serviceAccount, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		OrganizationID: first.OrganizationID,
+		Email:          "ci@coder.com",
+		Username:       "ci",
+		ServiceAccount: true,
+	})
+	require.NoError(t, err)
+
+	cfg, err := client.GetTokenConfig(ctx, serviceAccount.Username)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour*24*365, cfg.MaxTokenLifetime)
+
+	// Service accounts may exceed the max lifetime of human users.
+	_, err = client.CreateToken(ctx, serviceAccount.Username, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 300,
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateToken(ctx, serviceAccount.Username, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 400,
+	})
+	require.ErrorContains(t, err, "lifetime must be less")
+
+	// Human users are still limited.
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 300,
+	})
+	require.ErrorContains(t, err, "lifetime must be less")
+}
+
 func TestSessionExpiry(t *testing.T) {
 	t.Parallel()
 
@@ -20,7 +20,6 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
-	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -45,7 +44,7 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	queryStr := r.URL.Query().Get("q")
-	filter, errs := searchquery.AuditLogs(queryStr)
+	filter, errs := searchquery.AuditLogs(ctx, api.Database, queryStr)
 	if len(errs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid audit search query.",
@@ -192,16 +191,25 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 					Username:  dblog.UserUsername.String,
 					AvatarURL: dblog.UserAvatarUrl.String,
 				},
-				Email:     dblog.UserEmail.String,
-				CreatedAt: dblog.UserCreatedAt.Time,
-				Status:    codersdk.UserStatus(dblog.UserStatus.UserStatus),
+				Email:            dblog.UserEmail.String,
+				CreatedAt:        dblog.UserCreatedAt.Time,
+				Status:           codersdk.UserStatus(dblog.UserStatus.UserStatus),
+				IsServiceAccount: dblog.UserIsServiceAccount.Bool,
 			},
 			Roles: []codersdk.Role{},
 		}
 
 		for _, roleName := range dblog.UserRoles {
-			rbacRole, _ := rbac.RoleByName(roleName)
-			user.Roles = append(user.Roles, db2sdk.Role(rbacRole))
+			user.Roles = append(user.Roles, db2sdk.RoleByName(roleName))
+		}
+	}
+
+	var impersonator *codersdk.MinimalUser
+	if dblog.ImpersonatorID.Valid && dblog.ImpersonatorUsername.Valid {
+		impersonator = &codersdk.MinimalUser{
+			ID:        dblog.ImpersonatorID.UUID,
+			Username:  dblog.ImpersonatorUsername.String,
+			AvatarURL: dblog.ImpersonatorAvatarUrl.String,
 		}
 	}
 
@@ -249,6 +257,7 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 		StatusCode:       dblog.StatusCode,
 		AdditionalFields: dblog.AdditionalFields,
 		User:             user,
+		Impersonator:     impersonator,
 		Description:      auditLogDescription(dblog),
 		ResourceLink:     resourceLink,
 		IsDeleted:        isDeleted,
@@ -349,6 +358,38 @@ func (api *API) auditLogIsResourceDeleted(ctx context.Context, alog database.Get
 			api.Logger.Error(ctx, "unable to fetch oauth2 app secret", slog.Error(err))
 		}
 		return false
+	case database.ResourceTypeWebhook:
+		_, err := api.Database.GetWebhookByID(ctx, alog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch webhook", slog.Error(err))
+		}
+		return false
+	case database.ResourceTypeOIDCSyncRule:
+		_, err := api.Database.GetOIDCSyncRuleByID(ctx, alog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch oidc sync rule", slog.Error(err))
+		}
+		return false
+	case database.ResourceTypeSCIMToken:
+		_, err := api.Database.GetSCIMTokenByID(ctx, alog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch scim token", slog.Error(err))
+		}
+		return false
+	case database.ResourceTypeUserLoginLockout:
+		_, err := api.Database.GetUserLoginLockoutByUserID(ctx, alog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch user login lockout", slog.Error(err))
+		}
+		return false
 	default:
 		return false
 	}
@@ -360,7 +401,7 @@ func (api *API) auditLogResourceLink(ctx context.Context, alog database.GetAudit
 		return fmt.Sprintf("/templates/%s",
 			alog.ResourceTarget)
 
-	case database.ResourceTypeUser:
+	case database.ResourceTypeUser, database.ResourceTypeUserLoginLockout:
 		return fmt.Sprintf("/users?filter=%s",
 			alog.ResourceTarget)
 
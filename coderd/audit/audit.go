@@ -24,6 +24,13 @@ type AdditionalFields struct {
 	WorkspaceID    uuid.UUID            `json:"workspace_id"`
 }
 
+// LoginFields flag a password login from an IP address or user agent the user
+// has not logged in from before.
+type LoginFields struct {
+	NewIP        bool `json:"new_ip"`
+	NewUserAgent bool `json:"new_user_agent"`
+}
+
 func NewNop() Auditor {
 	return nop{}
 }
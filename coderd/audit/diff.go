@@ -21,7 +21,12 @@ type Auditable interface {
 		database.AuditOAuthConvertState |
 		database.HealthSettings |
 		database.OAuth2ProviderApp |
-		database.OAuth2ProviderAppSecret
+		database.OAuth2ProviderAppSecret |
+		database.Webhook |
+		database.CustomRole |
+		database.OIDCSyncRule |
+		database.SCIMToken |
+		database.AuditableUserLoginLockout
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
@@ -103,6 +103,16 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.OAuth2ProviderAppSecret:
 		return typed.DisplaySecret
+	case database.Webhook:
+		return typed.Name
+	case database.CustomRole:
+		return typed.Name
+	case database.OIDCSyncRule:
+		return typed.Claim
+	case database.SCIMToken:
+		return typed.Name
+	case database.AuditableUserLoginLockout:
+		return typed.Username
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceTarget", tgt))
 	}
@@ -140,6 +150,16 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.OAuth2ProviderAppSecret:
 		return typed.ID
+	case database.Webhook:
+		return typed.ID
+	case database.CustomRole:
+		return typed.ID
+	case database.OIDCSyncRule:
+		return typed.ID
+	case database.SCIMToken:
+		return typed.ID
+	case database.AuditableUserLoginLockout:
+		return typed.UserID
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceID", tgt))
 	}
@@ -175,6 +195,16 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeOauth2ProviderApp
 	case database.OAuth2ProviderAppSecret:
 		return database.ResourceTypeOauth2ProviderAppSecret
+	case database.Webhook:
+		return database.ResourceTypeWebhook
+	case database.CustomRole:
+		return database.ResourceTypeCustomRole
+	case database.OIDCSyncRule:
+		return database.ResourceTypeOIDCSyncRule
+	case database.SCIMToken:
+		return database.ResourceTypeSCIMToken
+	case database.AuditableUserLoginLockout:
+		return database.ResourceTypeUserLoginLockout
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceType", typed))
 	}
@@ -211,6 +241,16 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 		return false
 	case database.OAuth2ProviderAppSecret:
 		return false
+	case database.Webhook:
+		return false
+	case database.CustomRole:
+		return false
+	case database.OIDCSyncRule:
+		return false
+	case database.SCIMToken:
+		return false
+	case database.AuditableUserLoginLockout:
+		return false
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceRequiresOrgID", tgt))
 	}
@@ -279,10 +319,14 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			p.AdditionalFields = json.RawMessage("{}")
 		}
 
-		var userID uuid.UUID
+		var (
+			userID         uuid.UUID
+			impersonatorID uuid.NullUUID
+		)
 		key, ok := httpmw.APIKeyOptional(p.Request)
 		if ok {
 			userID = key.UserID
+			impersonatorID = key.ImpersonatorID
 		} else if req.UserID != uuid.Nil {
 			userID = req.UserID
 		} else {
@@ -313,6 +357,7 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			RequestID:        httpmw.RequestID(p.Request),
 			AdditionalFields: p.AdditionalFields,
 			OrganizationID:   requireOrgID[T](logCtx, p.OrganizationID, p.Log),
+			ImpersonatorID:   impersonatorID,
 		}
 		err := p.Audit.Export(ctx, auditLog)
 		if err != nil {
@@ -3,6 +3,7 @@ package autobuild
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -19,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
@@ -32,6 +34,7 @@ type Executor struct {
 	templateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
 	accessControlStore    *atomic.Pointer[dbauthz.AccessControlStore]
 	auditor               *atomic.Pointer[audit.Auditor]
+	notificationsEnqueuer notifications.Enqueuer
 	log                   slog.Logger
 	tick                  <-chan time.Time
 	statsCh               chan<- Stats
@@ -45,7 +48,7 @@ type Stats struct {
 }
 
 // New returns a new wsactions executor.
-func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *atomic.Pointer[schedule.TemplateScheduleStore], auditor *atomic.Pointer[audit.Auditor], acs *atomic.Pointer[dbauthz.AccessControlStore], log slog.Logger, tick <-chan time.Time) *Executor {
+func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *atomic.Pointer[schedule.TemplateScheduleStore], auditor *atomic.Pointer[audit.Auditor], acs *atomic.Pointer[dbauthz.AccessControlStore], enqueuer notifications.Enqueuer, log slog.Logger, tick <-chan time.Time) *Executor {
 	le := &Executor{
 		//nolint:gocritic // Autostart has a limited set of permissions.
 		ctx:                   dbauthz.AsAutostart(ctx),
@@ -56,6 +59,7 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *
 		log:                   log.Named("autobuild"),
 		auditor:               auditor,
 		accessControlStore:    acs,
+		notificationsEnqueuer: enqueuer,
 	}
 	return le
 }
@@ -140,6 +144,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			err := func() error {
 				var job *database.ProvisionerJob
 				var auditLog *auditParams
+				var notification *pendingNotification
 				err := e.db.InTx(func(tx database.Store) error {
 					// Re-check eligibility since the first check was outside the
 					// transaction and the workspace settings may have changed.
@@ -229,6 +234,15 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							slog.F("time_til_dormant", templateSchedule.TimeTilDormant),
 							slog.F("since_last_used_at", time.Since(ws.LastUsedAt)),
 						)
+
+						notification = &pendingNotification{
+							userID:   ws.OwnerID,
+							template: notifications.TemplateWorkspaceDormant,
+							labels: map[string]string{
+								"workspace": ws.Name,
+								"reason":    "it was not used for " + formatDuration(templateSchedule.TimeTilDormant),
+							},
+						}
 					}
 
 					if reason == database.BuildReasonAutodelete {
@@ -236,6 +250,15 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							slog.F("dormant_at", ws.DormantAt.Time),
 							slog.F("time_til_dormant_autodelete", templateSchedule.TimeTilDormantAutoDelete),
 						)
+
+						notification = &pendingNotification{
+							userID:   ws.OwnerID,
+							template: notifications.TemplateWorkspaceDeleted,
+							labels: map[string]string{
+								"workspace": ws.Name,
+								"reason":    "it was dormant for longer than " + formatDuration(templateSchedule.TimeTilDormantAutoDelete),
+							},
+						}
 					}
 
 					if nextTransition == "" {
@@ -275,6 +298,14 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("post provisioner job to pubsub: %w", err)
 					}
 				}
+				if notification != nil {
+					// Only notify once the transaction has committed so the
+					// owner is never told about a transition that didn't happen.
+					_, err = e.notificationsEnqueuer.Enqueue(e.ctx, notification.userID, notification.template, notification.labels, "autobuild")
+					if err != nil {
+						log.Warn(e.ctx, "failed to enqueue workspace notification", slog.F("template", notification.template), slog.Error(err))
+					}
+				}
 				return nil
 			}()
 			if err != nil {
@@ -298,6 +329,28 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	return stats
 }
 
+// pendingNotification is a notification to send once a workspace
+// transition has been committed.
+type pendingNotification struct {
+	userID   uuid.UUID
+	template string
+	labels   map[string]string
+}
+
+// formatDuration renders whole days as "N days" and falls back to the
+// standard duration format otherwise.
+func formatDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d >= day && d%day == 0 {
+		days := d / day
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return d.String()
+}
+
 // getNextTransition returns the next eligible transition for the workspace
 // as well as the reason for why it is transitioning. It is possible
 // for this function to return a nil error as well as an empty transition.
@@ -55,17 +55,24 @@ import (
 	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/loginlimit"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/portsharing"
+	"github.com/coder/coder/v2/coderd/prebuilds"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/sessiontracker"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/coderd/workspacebulk"
 	"github.com/coder/coder/v2/coderd/workspaceusage"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
@@ -149,6 +156,9 @@ type Options struct {
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	AccessControlStore          *atomic.Pointer[dbauthz.AccessControlStore]
+	// CustomRoleStore loads the custom roles. If set, the Authorizer must
+	// already expand custom roles with it, see rbac.WithCustomRoles.
+	CustomRoleStore *rolestore.Store
 	// AppSecurityKey is the crypto key used to sign and encrypt tokens related to
 	// workspace applications. It consists of both a signing and encryption key.
 	AppSecurityKey workspaceapps.SecurityKey
@@ -169,6 +179,9 @@ type Options struct {
 	APIRateLimit   int
 	LoginRateLimit int
 	FilesRateLimit int
+	// LoginLimit throttles and locks out password logins after repeated
+	// failures. Defaults to loginlimit.DefaultPolicy.
+	LoginLimit loginlimit.Policy
 
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
@@ -204,6 +217,17 @@ type Options struct {
 	DatabaseRolluper *dbrollup.Rolluper
 	// WorkspaceUsageTracker tracks workspace usage by the CLI.
 	WorkspaceUsageTracker *workspaceusage.Tracker
+	// NotificationsEnqueuer queues notifications for delivery to users.
+	NotificationsEnqueuer notifications.Enqueuer
+	// WebhookPublisher sends events to the webhooks configured by
+	// administrators.
+	WebhookPublisher webhooks.Publisher
+	// PrebuildsReconcileInterval is how often the prebuilt workspaces of
+	// templates are created and deleted. Defaults to 15 seconds.
+	PrebuildsReconcileInterval time.Duration
+	// WorkspaceBulkOperationInterval is how often bulk workspace operations
+	// check on their builds and start new ones. Defaults to 5 seconds.
+	WorkspaceBulkOperationInterval time.Duration
 }
 
 // @title Coder API
@@ -249,6 +273,12 @@ func New(options *Options) *API {
 	if options.Authorizer == nil {
 		options.Authorizer = rbac.NewCachingAuthorizer(options.PrometheusRegistry)
 	}
+	if options.CustomRoleStore == nil {
+		// Custom roles are loaded with the database before it is wrapped with
+		// dbauthz, as they are needed to authorize.
+		options.CustomRoleStore = rolestore.New(options.Database, options.Logger.Named("rolestore"))
+		options.Authorizer = rbac.WithCustomRoles(options.Authorizer, options.CustomRoleStore)
+	}
 
 	if options.AccessControlStore == nil {
 		options.AccessControlStore = &atomic.Pointer[dbauthz.AccessControlStore]{}
@@ -295,6 +325,9 @@ func New(options *Options) *API {
 	if options.FilesRateLimit == 0 {
 		options.FilesRateLimit = 12
 	}
+	if options.LoginLimit == (loginlimit.Policy{}) {
+		options.LoginLimit = loginlimit.DefaultPolicy
+	}
 	if options.PrometheusRegistry == nil {
 		options.PrometheusRegistry = prometheus.NewRegistry()
 	}
@@ -310,6 +343,12 @@ func New(options *Options) *API {
 	if options.Auditor == nil {
 		options.Auditor = audit.NewNop()
 	}
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+	}
+	if options.WebhookPublisher == nil {
+		options.WebhookPublisher = webhooks.NewStorePublisher(options.Database, options.Pubsub, options.Logger)
+	}
 	if options.SSHConfig.HostnamePrefix == "" {
 		options.SSHConfig.HostnamePrefix = "coder."
 	}
@@ -432,7 +471,27 @@ func New(options *Options) *API {
 		),
 		dbRolluper:            options.DatabaseRolluper,
 		workspaceUsageTracker: options.WorkspaceUsageTracker,
+		customRoleStore:       options.CustomRoleStore,
+		SessionTracker:        sessiontracker.New(options.Logger.Named("sessiontracker")),
+		webhookDispatcher:     webhooks.NewDispatcher(options.Database, options.Pubsub, options.Logger, webhooks.DispatcherOptions{}),
+		prebuildsReconciler: prebuilds.NewReconciler(options.Database, options.Pubsub, options.Logger, prebuilds.ReconcilerOptions{
+			Interval: options.PrebuildsReconcileInterval,
+		}),
+		workspaceBulkRunner: workspacebulk.NewRunner(options.Database, options.Pubsub, options.Authorizer, options.Logger, workspacebulk.RunnerOptions{
+			Interval: options.WorkspaceBulkOperationInterval,
+		}),
+	}
+	api.customRoleStoreCancel, err = options.CustomRoleStore.Subscribe(options.Pubsub)
+	if err != nil {
+		panic(xerrors.Errorf("subscribe to custom role changes: %w", err))
+	}
+	api.sessionTrackerCancel, err = api.SessionTracker.Subscribe(options.Pubsub)
+	if err != nil {
+		panic(xerrors.Errorf("subscribe to revoked API keys: %w", err))
 	}
+	api.webhookDispatcher.Run(ctx)
+	api.prebuildsReconciler.Run(ctx)
+	api.workspaceBulkRunner.Run(ctx)
 
 	api.AppearanceFetcher.Store(&appearance.DefaultFetcher)
 	api.PortSharer.Store(&portsharing.DefaultPortSharer)
@@ -570,6 +629,7 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		Sessions:                      api.SessionTracker,
 	})
 	// Same as above but it redirects to the login page.
 	apiKeyMiddlewareRedirect := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -580,6 +640,7 @@ func New(options *Options) *API {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		Sessions:                      api.SessionTracker,
 	})
 	// Same as the first but it's optional.
 	apiKeyMiddlewareOptional := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -590,6 +651,7 @@ func New(options *Options) *API {
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		Sessions:                      api.SessionTracker,
 	})
 
 	// API rate limit middleware. The counter is local and not shared between
@@ -704,7 +766,9 @@ func New(options *Options) *API {
 			httpmw.AsAuthzSystem(httpmw.ExtractOAuth2ProviderApp(options.Database)),
 		)
 		r.Route("/authorize", func(r chi.Router) {
-			r.Use(apiKeyMiddlewareRedirect)
+			// Authorizing an app mints tokens for the user, which an
+			// impersonator must not do.
+			r.Use(apiKeyMiddlewareRedirect, httpmw.BlockImpersonation)
 			r.Get("/", api.getOAuth2ProviderAppAuthorize())
 		})
 		r.Route("/tokens", func(r chi.Router) {
@@ -815,11 +879,15 @@ func New(options *Options) *API {
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
-						r.Use(
-							httpmw.ExtractOrganizationMemberParam(options.Database),
-						)
-						r.Put("/roles", api.putMemberRoles)
-						r.Post("/workspaces", api.postWorkspacesByOrganization)
+						r.With(httpmw.ExtractUserParam(options.Database)).Post("/", api.postOrganizationMember)
+						r.Group(func(r chi.Router) {
+							r.Use(
+								httpmw.ExtractOrganizationMemberParam(options.Database),
+							)
+							r.Delete("/", api.deleteOrganizationMember)
+							r.Put("/roles", api.putMemberRoles)
+							r.Post("/workspaces", api.postWorkspacesByOrganization)
+						})
 					})
 				})
 			})
@@ -839,6 +907,16 @@ func New(options *Options) *API {
 				r.Patch("/", api.patchActiveTemplateVersion)
 				r.Get("/{templateversionname}", api.templateVersionByName)
 			})
+			r.Route("/channels", func(r chi.Router) {
+				r.Get("/", api.templateReleaseChannels)
+				r.Post("/", api.postTemplateReleaseChannel)
+				r.Route("/{channel}", func(r chi.Router) {
+					r.Use(httpmw.ExtractTemplateReleaseChannelParam(options.Database))
+					r.Patch("/", api.patchTemplateReleaseChannel)
+					r.Delete("/", api.deleteTemplateReleaseChannel)
+				})
+			})
+			r.With(httpmw.ExtractUserParam(options.Database)).Get("/users/{user}/active-version", api.templateActiveVersionForUser)
 		})
 		r.Route("/templateversions/{templateversion}", func(r chi.Router) {
 			r.Use(
@@ -857,6 +935,8 @@ func New(options *Options) *API {
 			r.Get("/rich-parameters", api.templateVersionRichParameters)
 			r.Get("/external-auth", api.templateVersionExternalAuth)
 			r.Get("/variables", api.templateVersionVariables)
+			r.Get("/diagnostics", api.templateVersionDiagnostics)
+			r.Get("/presets", api.templateVersionPresets)
 			r.Get("/resources", api.templateVersionResources)
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
@@ -880,6 +960,10 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Route("/login/totp", func(r chi.Router) {
+					r.Post("/", api.postLoginTOTP)
+					r.Post("/enroll", api.postLoginTOTPEnroll)
+				})
 				r.Route("/oauth2", func(r chi.Router) {
 					r.Route("/github", func(r chi.Router) {
 						r.Use(
@@ -906,30 +990,57 @@ func New(options *Options) *API {
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.assignableSiteRoles)
 				})
+				r.Route("/oidc/sync-rules", func(r chi.Router) {
+					r.Get("/", api.oidcSyncRules)
+					r.Post("/", api.postOIDCSyncRule)
+					r.Post("/dry-run", api.postOIDCSyncDryRun)
+					r.Route("/{rule}", func(r chi.Router) {
+						r.Use(httpmw.ExtractOIDCSyncRuleParam(options.Database))
+						r.Get("/", api.oidcSyncRule)
+						r.Put("/", api.putOIDCSyncRule)
+						r.Delete("/", api.deleteOIDCSyncRule)
+					})
+				})
 				r.Route("/{user}", func(r chi.Router) {
 					r.Use(httpmw.ExtractUserParam(options.Database))
-					r.Post("/convert-login", api.postConvertLoginType)
+					r.With(httpmw.BlockImpersonation).Post("/convert-login", api.postConvertLoginType)
 					r.Delete("/", api.deleteUser)
 					r.Get("/", api.userByName)
 					r.Get("/autofill-parameters", api.userAutofillParameters)
 					r.Get("/login-type", api.userLoginType)
+					r.Route("/login-lockout", func(r chi.Router) {
+						r.Get("/", api.userLoginLockout)
+						r.Delete("/", api.deleteUserLoginLockout)
+					})
 					r.Put("/profile", api.putUserProfile)
 					r.Route("/status", func(r chi.Router) {
 						r.Put("/suspend", api.putSuspendUserAccount())
 						r.Put("/activate", api.putActivateUserAccount())
 					})
 					r.Put("/appearance", api.putUserAppearanceSettings)
+					r.Route("/notifications", func(r chi.Router) {
+						r.Get("/preferences", api.userNotificationPreferences)
+						r.Put("/preferences", api.putUserNotificationPreferences)
+					})
 					r.Route("/password", func(r chi.Router) {
+						r.Use(httpmw.BlockImpersonation)
 						r.Put("/", api.putUserPassword)
 					})
+					r.Route("/totp", func(r chi.Router) {
+						r.Use(httpmw.BlockImpersonation)
+						r.Get("/", api.userTOTP)
+						r.Post("/", api.postUserTOTP)
+						r.Delete("/", api.deleteUserTOTP)
+						r.Post("/verify", api.postUserTOTPVerify)
+					})
 					// These roles apply to the site wide permissions.
-					r.Put("/roles", api.putUserRoles)
+					r.With(httpmw.BlockImpersonation).Put("/roles", api.putUserRoles)
 					r.Get("/roles", api.userRoles)
 
 					r.Route("/keys", func(r chi.Router) {
-						r.Post("/", api.postAPIKey)
+						r.With(httpmw.BlockImpersonation).Post("/", api.postAPIKey)
 						r.Route("/tokens", func(r chi.Router) {
-							r.Post("/", api.postToken)
+							r.With(httpmw.BlockImpersonation).Post("/", api.postToken)
 							r.Get("/", api.tokens)
 							r.Get("/tokenconfig", api.tokenConfig)
 							r.Route("/{keyname}", func(r chi.Router) {
@@ -938,9 +1049,21 @@ func New(options *Options) *API {
 						})
 						r.Route("/{keyid}", func(r chi.Router) {
 							r.Get("/", api.apiKeyByID)
-							r.Delete("/", api.deleteAPIKey)
+							r.With(httpmw.BlockImpersonation).Delete("/", api.deleteAPIKey)
 						})
 					})
+					r.Route("/impersonations", func(r chi.Router) {
+						r.Use(httpmw.BlockImpersonation)
+						r.Post("/", api.postUserImpersonation)
+						r.Get("/", api.userImpersonations)
+						r.Delete("/{keyid}", api.deleteUserImpersonation)
+					})
+					r.Route("/sessions", func(r chi.Router) {
+						r.Use(httpmw.BlockImpersonation)
+						r.Get("/", api.userSessions)
+						r.Delete("/", api.deleteUserSessions)
+						r.Delete("/{keyid}", api.deleteUserSession)
+					})
 
 					r.Route("/organizations", func(r chi.Router) {
 						r.Get("/", api.organizationsByUser)
@@ -951,7 +1074,7 @@ func New(options *Options) *API {
 						r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
-					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.With(httpmw.BlockImpersonation).Put("/gitsshkey", api.regenerateGitSSHKey)
 				})
 			})
 		})
@@ -1020,6 +1143,10 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.Route("/bulk", func(r chi.Router) {
+				r.Post("/", api.postWorkspaceBulkOperation)
+				r.Get("/{bulkoperation}", api.workspaceBulkOperation)
+			})
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -1029,6 +1156,16 @@ func New(options *Options) *API {
 				r.Route("/builds", func(r chi.Router) {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
+					r.Get("/states", api.workspaceBuildStates)
+				})
+				r.Route("/snapshots", func(r chi.Router) {
+					r.Get("/", api.workspaceSnapshots)
+					r.Post("/", api.postWorkspaceSnapshot)
+					r.Route("/{snapshot}", func(r chi.Router) {
+						r.Use(httpmw.ExtractWorkspaceSnapshotParam(options.Database))
+						r.Get("/", api.workspaceSnapshot)
+						r.Delete("/", api.deleteWorkspaceSnapshot)
+					})
 				})
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
@@ -1043,6 +1180,9 @@ func New(options *Options) *API {
 				r.Put("/favorite", api.putFavoriteWorkspace)
 				r.Delete("/favorite", api.deleteFavoriteWorkspace)
 				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
+				r.Put("/owner", api.putWorkspaceOwner)
+				r.Get("/acl", api.workspaceACL)
+				r.Patch("/acl", api.patchWorkspaceACL)
 				r.Get("/resolve-autostart", api.resolveAutostart)
 				r.Route("/port-share", func(r chi.Router) {
 					r.Use(
@@ -1066,6 +1206,7 @@ func New(options *Options) *API {
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResourcesDeprecated)
 			r.Get("/state", api.workspaceBuildState)
+			r.Get("/state/diff", api.workspaceBuildStateDiff)
 		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -1158,6 +1299,32 @@ func New(options *Options) *API {
 				})
 			})
 		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Use(httpmw.ExtractWebhookParam(options.Database))
+				r.Get("/", api.webhook)
+				r.Patch("/", api.patchWebhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Route("/deliveries", func(r chi.Router) {
+					r.Get("/", api.webhookDeliveries)
+					r.Post("/{delivery}/redeliver", api.postWebhookRedelivery)
+				})
+			})
+		})
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.customRoles)
+			r.Post("/", api.postCustomRole)
+			r.Route("/{role}", func(r chi.Router) {
+				r.Use(httpmw.ExtractCustomRoleParam(options.Database))
+				r.Get("/", api.customRole)
+				r.Put("/", api.putCustomRole)
+				r.Delete("/", api.deleteCustomRole)
+			})
+		})
 	})
 
 	if options.SwaggerEndpoint {
@@ -1239,6 +1406,8 @@ type API struct {
 	PortSharer         atomic.Pointer[portsharing.PortSharer]
 
 	HTTPAuth *HTTPAuthorizer
+	// SessionTracker ends the requests of revoked API keys.
+	SessionTracker *sessiontracker.Tracker
 
 	// APIHandler serves "/api/v2"
 	APIHandler chi.Router
@@ -1272,6 +1441,12 @@ type API struct {
 	// stats. This is used to provide insights in the WebUI.
 	dbRolluper            *dbrollup.Rolluper
 	workspaceUsageTracker *workspaceusage.Tracker
+	webhookDispatcher     *webhooks.Dispatcher
+	prebuildsReconciler   *prebuilds.Reconciler
+	workspaceBulkRunner   *workspacebulk.Runner
+	customRoleStore       *rolestore.Store
+	customRoleStoreCancel func()
+	sessionTrackerCancel  func()
 }
 
 // Close waits for all WebSocket connections to drain before returning.
@@ -1311,6 +1486,11 @@ func (api *API) Close() error {
 	}
 	_ = api.agentProvider.Close()
 	api.workspaceUsageTracker.Close()
+	_ = api.webhookDispatcher.Close()
+	_ = api.prebuildsReconciler.Close()
+	_ = api.workspaceBulkRunner.Close()
+	api.customRoleStoreCancel()
+	api.sessionTrackerCancel()
 	return nil
 }
 
@@ -1364,7 +1544,7 @@ func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name st
 		OrganizationID: defaultOrg.ID,
 		CreatedAt:      dbtime.Now(),
 		Provisioners: []database.ProvisionerType{
-			database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform,
+			database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform, database.ProvisionerTypeOpentofu,
 		},
 		Tags:       provisionersdk.MutateTags(uuid.Nil, nil),
 		LastSeenAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
@@ -1396,6 +1576,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name st
 		api.TemplateScheduleStore,
 		api.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
+		api.NotificationsEnqueuer,
+		api.WebhookPublisher,
 		provisionerdserver.Options{
 			OIDCConfig:          api.OIDCConfig,
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
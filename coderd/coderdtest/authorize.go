@@ -41,7 +41,12 @@ func AssertRBAC(t *testing.T, api *coderd.API, client *codersdk.Client) RBACAsse
 	if client.SessionToken() == "" {
 		t.Fatal("client must be logged in")
 	}
-	recorder, ok := api.Authorizer.(*RecordingAuthorizer)
+	authz := api.Authorizer
+	// coderd wraps the Authorizer to expand custom roles.
+	if wrapper, ok := authz.(interface{ Unwrap() rbac.Authorizer }); ok {
+		authz = wrapper.Unwrap()
+	}
+	recorder, ok := authz.(*RecordingAuthorizer)
 	if !ok {
 		t.Fatal("expected RecordingAuthorizer")
 	}
@@ -63,7 +63,10 @@ import (
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/loginlimit"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/unhanger"
@@ -122,6 +125,7 @@ type Options struct {
 	APIRateLimit   int
 	LoginRateLimit int
 	FilesRateLimit int
+	LoginLimit     loginlimit.Policy
 
 	// IncludeProvisionerDaemon when true means to start an in-memory provisionerD
 	IncludeProvisionerDaemon    bool
@@ -152,6 +156,9 @@ type Options struct {
 	DatabaseRolluper                   *dbrollup.Rolluper
 	WorkspaceUsageTrackerFlush         chan int
 	WorkspaceUsageTrackerTick          chan time.Time
+	NotificationsEnqueuer              notifications.Enqueuer
+	PrebuildsReconcileInterval         time.Duration
+	WorkspaceBulkOperationInterval     time.Duration
 }
 
 // New constructs a codersdk client connected to an in-memory API instance.
@@ -240,6 +247,11 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	var acs dbauthz.AccessControlStore = dbauthz.AGPLTemplateAccessControlStore{}
 	accessControlStore.Store(&acs)
 
+	// The database is wrapped with dbauthz before coderd.New, so custom roles
+	// must be expanded with the unwrapped database here instead.
+	customRoleStore := rolestore.New(options.Database, options.Logger.Named("rolestore"))
+	options.Authorizer = rbac.WithCustomRoles(options.Authorizer, customRoleStore)
+
 	options.Database = dbauthz.New(options.Database, options.Authorizer, *options.Logger, accessControlStore)
 
 	// Some routes expect a deployment ID, so just make sure one exists.
@@ -293,6 +305,10 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	}
 	auditor.Store(&options.Auditor)
 
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	lifecycleExecutor := autobuild.NewExecutor(
 		ctx,
@@ -301,6 +317,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 		&templateScheduleStore,
 		&auditor,
 		accessControlStore,
+		options.NotificationsEnqueuer,
 		*options.Logger,
 		options.AutobuildTicker,
 	).WithStatsChannel(options.AutobuildStats)
@@ -469,7 +486,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			APIRateLimit:                       options.APIRateLimit,
 			LoginRateLimit:                     options.LoginRateLimit,
 			FilesRateLimit:                     options.FilesRateLimit,
+			LoginLimit:                         options.LoginLimit,
 			Authorizer:                         options.Authorizer,
+			CustomRoleStore:                    customRoleStore,
 			Telemetry:                          telemetry.NewNoop(),
 			TemplateScheduleStore:              &templateScheduleStore,
 			AccessControlStore:                 accessControlStore,
@@ -496,6 +515,9 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			NewTicker:                          options.NewTicker,
 			DatabaseRolluper:                   options.DatabaseRolluper,
 			WorkspaceUsageTracker:              wuTracker,
+			NotificationsEnqueuer:              options.NotificationsEnqueuer,
+			PrebuildsReconcileInterval:         options.PrebuildsReconcileInterval,
+			WorkspaceBulkOperationInterval:     options.WorkspaceBulkOperationInterval,
 		}
 }
 
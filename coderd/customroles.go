@@ -0,0 +1,337 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get custom roles
+// @ID get-custom-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /roles [get]
+func (api *API) customRoles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	roles, err := api.Database.GetCustomRoles(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.CustomRoles(roles))
+}
+
+// @Summary Get custom role by name
+// @ID get-custom-role-by-name
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name, suffixed with :<organization_id> for organization roles"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [get]
+func (api *API) customRole(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	role := httpmw.CustomRoleParam(r)
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.CustomRole(role))
+}
+
+// @Summary Create custom role
+// @ID create-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /roles [post]
+func (api *API) postCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if rbac.IsBuiltInRole(req.Name) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a built in role.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "Custom roles must not use the name of a built in role.",
+			}},
+		})
+		return
+	}
+
+	var organizationID uuid.NullUUID
+	if req.OrganizationID != nil {
+		_, err := api.Database.GetOrganizationByID(ctx, *req.OrganizationID)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Organization %q does not exist.", req.OrganizationID.String()),
+				Validations: []codersdk.ValidationError{{
+					Field:  "organization_id",
+					Detail: "Must be the ID of an existing organization.",
+				}},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		organizationID = uuid.NullUUID{UUID: *req.OrganizationID, Valid: true}
+	}
+
+	site, org, user, validations := customRolePermissions(organizationID.Valid, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid custom role permissions.",
+			Validations: validations,
+		})
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Name
+	}
+
+	role, err := api.Database.InsertCustomRole(ctx, database.InsertCustomRoleParams{
+		ID:              uuid.New(),
+		Name:            req.Name,
+		DisplayName:     displayName,
+		OrganizationID:  organizationID,
+		SitePermissions: site,
+		OrgPermissions:  org,
+		UserPermissions: user,
+		CreatedAt:       dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A custom role named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.customRoleStore.Changed(ctx, api.Pubsub)
+	aReq.New = role
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.CustomRole(role))
+}
+
+// @Summary Update custom role
+// @Description Replaces the display name and permissions of a custom role.
+// @Description The change applies to all users with the role.
+// @ID update-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role name, suffixed with :<organization_id> for organization roles"
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [put]
+func (api *API) putCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = httpmw.CustomRoleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = role
+	defer commitAudit()
+
+	var req codersdk.UpdateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	site, org, user, validations := customRolePermissions(role.OrganizationID.Valid, req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid custom role permissions.",
+			Validations: validations,
+		})
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = role.Name
+	}
+
+	updated, err := api.Database.UpdateCustomRole(ctx, database.UpdateCustomRoleParams{
+		ID:              role.ID,
+		DisplayName:     displayName,
+		SitePermissions: site,
+		OrgPermissions:  org,
+		UserPermissions: user,
+		UpdatedAt:       dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.customRoleStore.Changed(ctx, api.Pubsub)
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.CustomRole(updated))
+}
+
+// @Summary Delete custom role
+// @Description Deletes a custom role and unassigns it from all users.
+// @ID delete-custom-role
+// @Security CoderSessionToken
+// @Tags Members
+// @Param role path string true "Role name, suffixed with :<organization_id> for organization roles"
+// @Success 204
+// @Router /roles/{role} [delete]
+func (api *API) deleteCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = httpmw.CustomRoleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = role
+	defer commitAudit()
+
+	err := api.Database.DeleteCustomRole(ctx, role.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.customRoleStore.Changed(ctx, api.Pubsub)
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// customRolePermissions validates the permissions of a custom role and
+// converts them for the database. Site permissions are only allowed for site
+// roles, and organization permissions only for organization roles.
+func customRolePermissions(orgRole bool, site, org, user []codersdk.Permission) (database.CustomRolePermissions, database.CustomRolePermissions, database.CustomRolePermissions, []codersdk.ValidationError) {
+	var validations []codersdk.ValidationError
+	if orgRole && len(site) > 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "site_permissions",
+			Detail: "Organization roles must not have site permissions.",
+		})
+	}
+	if !orgRole && len(org) > 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "organization_permissions",
+			Detail: "Site roles must not have organization permissions.",
+		})
+	}
+
+	convert := func(field string, permissions []codersdk.Permission) database.CustomRolePermissions {
+		converted := make(database.CustomRolePermissions, 0, len(permissions))
+		for _, permission := range permissions {
+			if !validCustomRoleResource(string(permission.ResourceType)) {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  field,
+					Detail: fmt.Sprintf("Unknown resource type %q.", permission.ResourceType),
+				})
+			}
+			if !validCustomRoleAction(permission.Action) {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  field,
+					Detail: fmt.Sprintf("Unknown action %q.", permission.Action),
+				})
+			}
+			converted = append(converted, rbac.Permission{
+				Negate:       permission.Negate,
+				ResourceType: string(permission.ResourceType),
+				Action:       rbac.Action(permission.Action),
+			})
+		}
+		return converted
+	}
+	return convert("site_permissions", site), convert("organization_permissions", org), convert("user_permissions", user), validations
+}
+
+func validCustomRoleResource(resourceType string) bool {
+	for _, resource := range rbac.AllResources() {
+		if resource.Type == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func validCustomRoleAction(action string) bool {
+	if action == rbac.WildcardSymbol {
+		return true
+	}
+	for _, valid := range rbac.AllActions() {
+		if string(valid) == action {
+			return true
+		}
+	}
+	return false
+}
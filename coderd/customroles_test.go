@@ -0,0 +1,201 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TemplateOperator", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "template-operator",
+			DisplayName: "Template Operator",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+				{ResourceType: codersdk.ResourceTemplate, Action: "create"},
+				{ResourceType: codersdk.ResourceTemplate, Action: "update"},
+			},
+			UserPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceFile, Action: "create"},
+				{ResourceType: codersdk.ResourceFile, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "template-operator", role.Name)
+		require.Nil(t, role.OrganizationID)
+
+		// Custom roles are assignable by owners.
+		siteRoles, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, siteRoles, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: role.Name, DisplayName: role.DisplayName},
+			Assignable: true,
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		operatorClient, operator := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, role.Name)
+		require.Contains(t, operator.Roles, codersdk.Role{Name: role.Name, DisplayName: role.Name})
+
+		// Operators push new versions...
+		pushed := coderdtest.CreateTemplateVersion(t, operatorClient, owner.OrganizationID, nil, func(r *codersdk.CreateTemplateVersionRequest) {
+			r.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, operatorClient, pushed.ID)
+		err = operatorClient.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: pushed.ID,
+		})
+		require.NoError(t, err)
+
+		// ...but can't delete templates.
+		err = operatorClient.DeleteTemplate(ctx, template.ID)
+		require.Error(t, err)
+		_, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+
+		// Updates apply to the users with the role right away.
+		_, err = client.UpdateCustomRole(ctx, role.Name, codersdk.UpdateCustomRoleRequest{
+			DisplayName: role.DisplayName,
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+			},
+			UserPermissions: role.UserPermissions,
+		})
+		require.NoError(t, err)
+		err = operatorClient.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.Error(t, err)
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, pushed.ID, template.ActiveVersionID)
+
+		// Deleting the role unassigns it.
+		err = client.DeleteCustomRole(ctx, role.Name)
+		require.NoError(t, err)
+		operator, err = client.User(ctx, operator.ID.String())
+		require.NoError(t, err)
+		require.NotContains(t, operator.Roles, codersdk.Role{Name: role.Name, DisplayName: role.Name})
+		_, err = client.CustomRole(ctx, role.Name)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("OrganizationRole", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:           "template-operator",
+			OrganizationID: &owner.OrganizationID,
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "update"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, rbac.RoleName("template-operator", owner.OrganizationID.String()), role.Name)
+		require.Equal(t, "template-operator", role.DisplayName)
+
+		got, err := client.CustomRole(ctx, role.Name)
+		require.NoError(t, err)
+		require.Equal(t, role, got)
+
+		orgRoles, err := client.ListOrganizationRoles(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Contains(t, orgRoles, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: role.Name, DisplayName: role.DisplayName},
+			Assignable: true,
+		})
+		siteRoles, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		for _, siteRole := range siteRoles {
+			require.NotEqual(t, role.Name, siteRole.Name)
+		}
+
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		updated, err := client.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, member.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.NoError(t, err)
+		require.Contains(t, updated.Roles, codersdk.Role{Name: role.Name, DisplayName: "template-operator"})
+
+		// A site role with the same name is a different role.
+		_, err = client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "template-operator"})
+		require.NoError(t, err)
+		all, err := client.CustomRoles(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		for _, req := range []codersdk.CreateCustomRoleRequest{
+			{Name: rbac.RoleTemplateAdmin()},
+			{Name: "bad name"},
+			{Name: "unknown-resource", SitePermissions: []codersdk.Permission{{ResourceType: "unknown", Action: "read"}}},
+			{Name: "unknown-action", SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceTemplate, Action: "execute"}}},
+			{Name: "site-with-org", OrganizationPermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceTemplate, Action: "read"}}},
+			{Name: "org-with-site", OrganizationID: &owner.OrganizationID, SitePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceTemplate, Action: "read"}}},
+		} {
+			_, err := client.CreateCustomRole(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, req.Name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), req.Name)
+		}
+
+		_, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "operator"})
+		require.NoError(t, err)
+		_, err = client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "operator"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		role, err := client.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "operator"})
+		require.NoError(t, err)
+
+		// Only owners may assign custom roles, as they may grant anything.
+		userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err = userAdmin.UpdateUserRoles(ctx, member.ID.String(), codersdk.UpdateRoles{Roles: []string{role.Name}})
+		require.Error(t, err)
+
+		_, err = userAdmin.CreateCustomRole(ctx, codersdk.CreateCustomRoleRequest{Name: "escalate"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -132,13 +132,14 @@ func ReducedUser(user database.User) codersdk.ReducedUser {
 			Username:  user.Username,
 			AvatarURL: user.AvatarURL,
 		},
-		Email:           user.Email,
-		Name:            user.Name,
-		CreatedAt:       user.CreatedAt,
-		LastSeenAt:      user.LastSeenAt,
-		Status:          codersdk.UserStatus(user.Status),
-		LoginType:       codersdk.LoginType(user.LoginType),
-		ThemePreference: user.ThemePreference,
+		Email:            user.Email,
+		Name:             user.Name,
+		CreatedAt:        user.CreatedAt,
+		LastSeenAt:       user.LastSeenAt,
+		Status:           codersdk.UserStatus(user.Status),
+		LoginType:        codersdk.LoginType(user.LoginType),
+		ThemePreference:  user.ThemePreference,
+		IsServiceAccount: user.IsServiceAccount,
 	}
 }
 
@@ -154,8 +155,7 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, RoleByName(roleName))
 	}
 
 	return convertedUser
@@ -169,14 +169,15 @@ func Users(users []database.User, organizationIDs map[uuid.UUID][]uuid.UUID) []c
 
 func Group(group database.Group, members []database.User) codersdk.Group {
 	return codersdk.Group{
-		ID:             group.ID,
-		Name:           group.Name,
-		DisplayName:    group.DisplayName,
-		OrganizationID: group.OrganizationID,
-		AvatarURL:      group.AvatarURL,
-		Members:        ReducedUsers(members),
-		QuotaAllowance: int(group.QuotaAllowance),
-		Source:         codersdk.GroupSource(group.Source),
+		ID:                   group.ID,
+		Name:                 group.Name,
+		DisplayName:          group.DisplayName,
+		OrganizationID:       group.OrganizationID,
+		AvatarURL:            group.AvatarURL,
+		Members:              ReducedUsers(members),
+		QuotaAllowance:       int(group.QuotaAllowance),
+		Source:               codersdk.GroupSource(group.Source),
+		MaxWorkspacesPerUser: int(group.MaxWorkspacesPerUser),
 	}
 }
 
@@ -187,6 +188,81 @@ func Role(role rbac.Role) codersdk.Role {
 	}
 }
 
+// RoleByName converts an assigned role. Custom roles are unknown to the rbac
+// package, so they are displayed by their name without the organization.
+func RoleByName(roleName string) codersdk.Role {
+	rbacRole, err := rbac.RoleByName(roleName)
+	if err == nil {
+		return Role(rbacRole)
+	}
+	name, _, err := rbac.RoleSplit(roleName)
+	if err != nil {
+		name = roleName
+	}
+	return codersdk.Role{
+		DisplayName: name,
+		Name:        roleName,
+	}
+}
+
+func CustomRole(role database.CustomRole) codersdk.CustomRole {
+	converted := codersdk.CustomRole{
+		ID:                      role.ID,
+		Name:                    role.Name,
+		DisplayName:             role.DisplayName,
+		SitePermissions:         Permissions(role.SitePermissions),
+		OrganizationPermissions: Permissions(role.OrgPermissions),
+		UserPermissions:         Permissions(role.UserPermissions),
+		CreatedAt:               role.CreatedAt,
+		UpdatedAt:               role.UpdatedAt,
+	}
+	if role.OrganizationID.Valid {
+		converted.Name = rbac.RoleName(role.Name, role.OrganizationID.UUID.String())
+		converted.OrganizationID = &role.OrganizationID.UUID
+	}
+	return converted
+}
+
+func CustomRoles(roles []database.CustomRole) []codersdk.CustomRole {
+	return List(roles, CustomRole)
+}
+
+func OIDCSyncRule(rule database.OIDCSyncRule) codersdk.OIDCSyncRule {
+	converted := codersdk.OIDCSyncRule{
+		ID:                rule.ID,
+		Claim:             rule.Claim,
+		Pattern:           rule.Pattern,
+		SiteRoles:         rule.SiteRoles,
+		OrganizationRoles: rule.OrganizationRoles,
+		CreatedAt:         rule.CreatedAt,
+		UpdatedAt:         rule.UpdatedAt,
+	}
+	if converted.SiteRoles == nil {
+		converted.SiteRoles = []string{}
+	}
+	if converted.OrganizationRoles == nil {
+		converted.OrganizationRoles = []string{}
+	}
+	if rule.OrganizationID.Valid {
+		converted.OrganizationID = &rule.OrganizationID.UUID
+	}
+	return converted
+}
+
+func OIDCSyncRules(rules []database.OIDCSyncRule) []codersdk.OIDCSyncRule {
+	return List(rules, OIDCSyncRule)
+}
+
+func Permissions(permissions []rbac.Permission) []codersdk.Permission {
+	return List(permissions, func(permission rbac.Permission) codersdk.Permission {
+		return codersdk.Permission{
+			Negate:       permission.Negate,
+			ResourceType: codersdk.RBACResource(permission.ResourceType),
+			Action:       string(permission.Action),
+		}
+	})
+}
+
 func TemplateInsightsParameters(parameterRows []database.GetTemplateParameterInsightsRow) ([]codersdk.TemplateParameterUsage, error) {
 	// Use a stable sort, similarly to how we would sort in the query, note that
 	// we don't sort in the query because order varies depending on the table
@@ -291,6 +367,54 @@ func OAuth2ProviderApps(accessURL *url.URL, dbApps []database.OAuth2ProviderApp)
 	})
 }
 
+func Webhook(webhook database.Webhook) codersdk.Webhook {
+	events := make([]codersdk.WebhookEvent, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, codersdk.WebhookEvent(event))
+	}
+	return codersdk.Webhook{
+		ID:        webhook.ID,
+		Name:      webhook.Name,
+		URL:       webhook.Url,
+		Events:    events,
+		HasSecret: webhook.Secret != "",
+		Enabled:   webhook.Enabled,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func Webhooks(webhooks []database.Webhook) []codersdk.Webhook {
+	return List(webhooks, Webhook)
+}
+
+func WebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	sdk := codersdk.WebhookDelivery{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          codersdk.WebhookEvent(delivery.Event),
+		Payload:        delivery.Payload,
+		Status:         codersdk.WebhookDeliveryStatus(delivery.Status),
+		AttemptCount:   delivery.AttemptCount,
+		ResponseStatus: delivery.ResponseStatus.Int32,
+		ResponseBody:   delivery.ResponseBody.String,
+		Error:          delivery.Error.String,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+	if delivery.NextAttemptAfter.Valid {
+		sdk.NextAttemptAfter = &delivery.NextAttemptAfter.Time
+	}
+	if delivery.DeliveredAt.Valid {
+		sdk.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	return sdk
+}
+
+func WebhookDeliveries(deliveries []database.WebhookDelivery) []codersdk.WebhookDelivery {
+	return List(deliveries, WebhookDelivery)
+}
+
 func convertDisplayApps(apps []database.DisplayApp) []codersdk.DisplayApp {
 	dapps := make([]codersdk.DisplayApp, 0, len(apps))
 	for _, app := range apps {
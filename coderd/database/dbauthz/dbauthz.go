@@ -224,6 +224,30 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	// See prebuilds package.
+	subjectPrebuildsReconciler = rbac.Subject{
+		FriendlyName: "Prebuilds Reconciler",
+		ID:           uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Name:        "prebuildsreconciler",
+				DisplayName: "Prebuilds Reconciler Daemon",
+				Site: rbac.Permissions(map[string][]rbac.Action{
+					rbac.ResourceSystem.Type:            {rbac.WildcardSymbol},
+					rbac.ResourceTemplate.Type:          {rbac.ActionRead},
+					rbac.ResourceUser.Type:              {rbac.ActionRead},
+					rbac.ResourceOrganization.Type:      {rbac.ActionRead},
+					rbac.ResourceProvisionerDaemon.Type: {rbac.ActionRead},
+					rbac.ResourceWorkspace.Type:         {rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWorkspaceBuild.Type:    {rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSystemRestricted = rbac.Subject{
 		FriendlyName: "System",
 		ID:           uuid.Nil.String(),
@@ -232,21 +256,23 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate, rbac.ActionRead},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceProvisionerDaemon.Type:  {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceBuild.Type:     {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
-					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWildcard.Type:               {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                 {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                  {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceRoleAssignment.Type:         {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSCIMToken.Type:              {rbac.ActionUpdate},
+					rbac.ResourceSystem.Type:                 {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:           {rbac.ActionCreate, rbac.ActionRead},
+					rbac.ResourceOrganizationMember.Type:     {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceOrgRoleAssignment.Type:      {rbac.ActionCreate},
+					rbac.ResourceProvisionerDaemon.Type:      {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceUser.Type:                   {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:               {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceWorkspace.Type:              {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceBuild.Type:         {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceBulkOperation.Type: {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceExecution.Type:     {rbac.ActionCreate},
+					rbac.ResourceWorkspaceProxy.Type:         {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -274,6 +300,12 @@ func AsHangDetector(ctx context.Context) context.Context {
 	return context.WithValue(ctx, authContextKey{}, subjectHangDetector)
 }
 
+// AsPrebuildsReconciler returns a context with an actor that has permissions
+// required for maintaining prebuilt workspaces.
+func AsPrebuildsReconciler(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectPrebuildsReconciler)
+}
+
 // AsSystemRestricted returns a context with an actor that has permissions
 // required for various system operations (login, logout, metrics cache).
 func AsSystemRestricted(ctx context.Context) context.Context {
@@ -531,6 +563,29 @@ func (q *querier) InTx(function func(querier database.Store) error, txOpts *sql.
 	}, txOpts)
 }
 
+// authorizeTemplateReleaseChannel authorizes an action on the release channels
+// of a template. Channels are part of their template, so reading them requires
+// reading the template and changing them requires updating it.
+func (q *querier) authorizeTemplateReleaseChannel(ctx context.Context, action rbac.Action, templateID uuid.UUID) error {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
+// authorizeWorkspaceSnapshot authorizes an action on the snapshots of a
+// workspace. Snapshots belong to their workspace, so reading them requires
+// reading the workspace and taking, restoring or deleting them requires
+// updating it.
+func (q *querier) authorizeWorkspaceSnapshot(ctx context.Context, action rbac.Action, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, action, workspace)
+}
+
 // authorizeReadFile is a hotfix for the fact that file permissions are
 // independent of template permissions. This function checks if the user has
 // update access to any of the file's templates.
@@ -578,7 +633,7 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 		}
 
 		// All roles should be valid roles
-		if _, err := rbac.RoleByName(r); err != nil {
+		if _, err := rbac.RoleByName(r); err != nil && !q.isCustomRole(ctx, r) {
 			return xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
@@ -604,6 +659,25 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	return nil
 }
 
+// isCustomRole returns true if the role name is a custom role. The actor does
+// not need to be able to read custom roles to assign them.
+func (q *querier) isCustomRole(ctx context.Context, roleName string) bool {
+	name, orgID, err := rbac.RoleSplit(roleName)
+	if err != nil {
+		return false
+	}
+	arg := database.GetCustomRoleByNameParams{Name: name}
+	if orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			return false
+		}
+		arg.OrganizationID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	_, err = q.db.GetCustomRoleByName(ctx, arg)
+	return err == nil
+}
+
 func (q *querier) SoftDeleteTemplateByID(ctx context.Context, id uuid.UUID) error {
 	deleteF := func(ctx context.Context, id uuid.UUID) error {
 		return q.db.UpdateTemplateDeletedByID(ctx, database.UpdateTemplateDeletedByIDParams{
@@ -658,6 +732,13 @@ func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireNotificationMessages(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -666,6 +747,13 @@ func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.Acquir
 	return q.db.AcquireProvisionerJob(ctx, arg)
 }
 
+func (q *querier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	fetch := func(ctx context.Context, arg database.ActivityBumpWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
@@ -702,6 +790,16 @@ func (q *querier) BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg databa
 	return q.db.BatchUpdateWorkspaceLastUsedAt(ctx, arg)
 }
 
+func (q *querier) ClaimWorkspacePrebuild(ctx context.Context, arg database.ClaimWorkspacePrebuildParams) (database.Workspace, error) {
+	// Claiming a prebuild is the same as creating a workspace for the new
+	// owner, so it needs the same permission.
+	obj := rbac.ResourceWorkspace.InOrg(arg.OrganizationID).WithOwner(arg.OwnerID.String())
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, obj); err != nil {
+		return database.Workspace{}, err
+	}
+	return q.db.ClaimWorkspacePrebuild(ctx, arg)
+}
+
 func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -768,6 +866,13 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceCustomRole); err != nil {
+		return err
+	}
+	return q.db.DeleteCustomRole(ctx, id)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return deleteQ(q.log, q.auth, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -843,6 +948,20 @@ func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Contex
 	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
 }
 
+func (q *querier) DeleteOIDCSyncRule(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOIDCSyncRule); err != nil {
+		return err
+	}
+	return q.db.DeleteOIDCSyncRule(ctx, id)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNotificationMessages(ctx)
+}
+
 func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -850,6 +969,20 @@ func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return q.db.DeleteOldProvisionerDaemons(ctx)
 }
 
+func (q *querier) DeleteOldUserLoginAttempts(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldUserLoginAttempts(ctx)
+}
+
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -864,6 +997,31 @@ func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentStats(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceBuildProvisionerStates(ctx context.Context, keep int32) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceBuildProvisionerStates(ctx, keep)
+}
+
+func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	return deleteQ(q.log, q.auth, func(ctx context.Context, arg database.DeleteOrganizationMemberParams) (database.OrganizationMember, error) {
+		return q.db.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+	}, q.db.DeleteOrganizationMember)(ctx, arg)
+}
+
+func (q *querier) DeleteOtherAPIKeysByUserID(ctx context.Context, arg database.DeleteOtherAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	err := q.authorizeContext(ctx, rbac.ActionDelete,
+		rbac.ResourceAPIKey.WithOwner(arg.UserID.String()))
+	if err != nil {
+		return nil, err
+	}
+	return q.db.DeleteOtherAPIKeysByUserID(ctx, arg)
+}
+
 func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -871,6 +1029,13 @@ func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt tim
 	return q.db.DeleteReplicasUpdatedBefore(ctx, updatedAt)
 }
 
+func (q *querier) DeleteSCIMToken(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSCIMToken); err != nil {
+		return err
+	}
+	return q.db.DeleteSCIMToken(ctx, id)
+}
+
 func (q *querier) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return database.DeleteTailnetAgentRow{}, err
@@ -906,6 +1071,49 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) error {
+	channel, err := q.db.GetTemplateReleaseChannelByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionUpdate, channel.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateReleaseChannelByID(ctx, id)
+}
+
+func (q *querier) DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserLoginFailures(ctx, userID)
+}
+
+func (q *querier) DeleteUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserLoginLockoutByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, u.UserDataRBACObject()); err != nil {
+		return err
+	}
+	return q.db.DeleteUserTOTPByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceWebhook); err != nil {
+		return err
+	}
+	return q.db.DeleteWebhookByID(ctx, id)
+}
+
 func (q *querier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -933,6 +1141,42 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx)
+}
+
+func (q *querier) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionUpdate, snapshot.WorkspaceID); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceSnapshotByID(ctx, id)
+}
+
+func (q *querier) EnableUserTOTP(ctx context.Context, arg database.EnableUserTOTPParams) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.EnableUserTOTP(ctx, arg)
+}
+
+func (q *querier) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) (database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.NotificationMessage{}, err
+	}
+	return q.db.EnqueueNotificationMessage(ctx, arg)
+}
+
 func (q *querier) FavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
 	fetch := func(ctx context.Context, id uuid.UUID) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, id)
@@ -960,6 +1204,10 @@ func (q *querier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Tim
 	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysLastUsedAfter)(ctx, lastUsed)
 }
 
+func (q *querier) GetActiveAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetActiveAPIKeysByUserID)(ctx, userID)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -1030,6 +1278,20 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetCustomRoleByName(ctx context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceCustomRole); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.GetCustomRoleByName(ctx, arg)
+}
+
+func (q *querier) GetCustomRoles(ctx context.Context) ([]database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceCustomRole); err != nil {
+		return nil, err
+	}
+	return q.db.GetCustomRoles(ctx)
+}
+
 func (q *querier) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1163,6 +1425,10 @@ func (q *querier) GetHungProvisionerJobs(ctx context.Context, hungSince time.Tim
 	return q.db.GetHungProvisionerJobs(ctx, hungSince)
 }
 
+func (q *querier) GetImpersonationAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetImpersonationAPIKeysByUserID)(ctx, userID)
+}
+
 func (q *querier) GetJFrogXrayScanByWorkspaceAndAgentID(ctx context.Context, arg database.GetJFrogXrayScanByWorkspaceAndAgentIDParams) (database.JfrogXrayScan, error) {
 	if _, err := fetch(q.log, q.auth, q.db.GetWorkspaceByID)(ctx, arg.WorkspaceID); err != nil {
 		return database.JfrogXrayScan{}, err
@@ -1215,11 +1481,25 @@ func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) GetLoginFailureStats(ctx context.Context, arg database.GetLoginFailureStatsParams) (database.GetLoginFailureStatsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetLoginFailureStatsRow{}, err
+	}
+	return q.db.GetLoginFailureStats(ctx, arg)
+}
+
 func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationMessagesByStatus(ctx, arg)
+}
+
 func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return database.OAuth2ProviderApp{}, err
@@ -1292,6 +1572,20 @@ func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return q.db.GetOAuthSigningKey(ctx)
 }
 
+func (q *querier) GetOIDCSyncRuleByID(ctx context.Context, id uuid.UUID) (database.OIDCSyncRule, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOIDCSyncRule); err != nil {
+		return database.OIDCSyncRule{}, err
+	}
+	return q.db.GetOIDCSyncRuleByID(ctx, id)
+}
+
+func (q *querier) GetOIDCSyncRules(ctx context.Context) ([]database.OIDCSyncRule, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOIDCSyncRule); err != nil {
+		return nil, err
+	}
+	return q.db.GetOIDCSyncRules(ctx)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -1346,6 +1640,20 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPendingProvisionerJobEngineVersionConstraints(ctx context.Context, organizationID uuid.UUID) ([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingProvisionerJobEngineVersionConstraints(ctx, organizationID)
+}
+
+func (q *querier) GetPrebuildPresets(ctx context.Context) ([]database.GetPrebuildPresetsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPrebuildPresets(ctx)
+}
+
 func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	// An actor can read the previous template version if they can read the related template.
 	// If no linked template exists, we check if the actor can read *a* template.
@@ -1367,6 +1675,10 @@ func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.Provisi
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetProvisionerDaemonsByOrganization)(ctx, organizationID)
+}
+
 func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	job, err := q.db.GetProvisionerJobByID(ctx, id)
 	if err != nil {
@@ -1424,20 +1736,20 @@ func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.Ge
 	return q.db.GetProvisionerLogsAfterID(ctx, arg)
 }
 
-func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
+func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, arg database.GetQuotaAllowanceForUserParams) (int64, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(arg.UserID))
 	if err != nil {
 		return -1, err
 	}
-	return q.db.GetQuotaAllowanceForUser(ctx, userID)
+	return q.db.GetQuotaAllowanceForUser(ctx, arg)
 }
 
-func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
+func (q *querier) GetQuotaConsumedForUser(ctx context.Context, arg database.GetQuotaConsumedForUserParams) (int64, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(arg.OwnerID))
 	if err != nil {
 		return -1, err
 	}
-	return q.db.GetQuotaConsumedForUser(ctx, userID)
+	return q.db.GetQuotaConsumedForUser(ctx, arg)
 }
 
 func (q *querier) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
@@ -1454,6 +1766,34 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) GetRunningWorkspaceBulkOperations(ctx context.Context) ([]database.WorkspaceBulkOperation, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetRunningWorkspaceBulkOperations(ctx)
+}
+
+func (q *querier) GetSCIMTokenByID(ctx context.Context, id uuid.UUID) (database.SCIMToken, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSCIMToken); err != nil {
+		return database.SCIMToken{}, err
+	}
+	return q.db.GetSCIMTokenByID(ctx, id)
+}
+
+func (q *querier) GetSCIMTokenByPrefix(ctx context.Context, secretPrefix []byte) (database.SCIMToken, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSCIMToken); err != nil {
+		return database.SCIMToken{}, err
+	}
+	return q.db.GetSCIMTokenByPrefix(ctx, secretPrefix)
+}
+
+func (q *querier) GetSCIMTokens(ctx context.Context) ([]database.SCIMToken, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSCIMToken); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMTokens(ctx)
+}
+
 func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetServiceBanner(ctx)
@@ -1494,6 +1834,21 @@ func (q *querier) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID)
 	return q.db.GetTailnetTunnelPeerIDs(ctx, srcID)
 }
 
+func (q *querier) GetTemplateActiveVersionForUser(ctx context.Context, arg database.GetTemplateActiveVersionForUserParams) (database.GetTemplateActiveVersionForUserRow, error) {
+	// An actor can read the version of any user if they can read the template.
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionRead, arg.TemplateID); err != nil {
+		return database.GetTemplateActiveVersionForUserRow{}, err
+	}
+	return q.db.GetTemplateActiveVersionForUser(ctx, arg)
+}
+
+func (q *querier) GetTemplateActiveVersionsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetTemplateActiveVersionsByWorkspaceIDsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateActiveVersionsByWorkspaceIDs(ctx, ids)
+}
+
 func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	// Used by TemplateAppInsights endpoint
 	// For auditors, check read template_insights, and fall back to update template.
@@ -1626,6 +1981,31 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) (database.TemplateReleaseChannel, error) {
+	channel, err := q.db.GetTemplateReleaseChannelByID(ctx, id)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionRead, channel.TemplateID); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return channel, nil
+}
+
+func (q *querier) GetTemplateReleaseChannelByTemplateIDAndName(ctx context.Context, arg database.GetTemplateReleaseChannelByTemplateIDAndNameParams) (database.TemplateReleaseChannel, error) {
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionRead, arg.TemplateID); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return q.db.GetTemplateReleaseChannelByTemplateIDAndName(ctx, arg)
+}
+
+func (q *querier) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionRead, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateReleaseChannelsByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	// Used by dbrollup tests, use same safe-guard as other insights endpoints.
 	// For auditors, check read template_insights, and fall back to update template.
@@ -1700,6 +2080,29 @@ func (q *querier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg
 	return tv, nil
 }
 
+func (q *querier) GetTemplateVersionDiagnostics(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionDiagnostic, error) {
+	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var object rbac.Objecter
+	template, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		object = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		object = tv.RBACObject(template)
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, object); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionDiagnostics(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionParameter, error) {
 	// An actor can read template version parameters if they can read the related template.
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
@@ -1724,6 +2127,39 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionPresetByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionPreset, error) {
+	preset, err := q.db.GetTemplateVersionPresetByID(ctx, id)
+	if err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	// An actor can read a preset if they can read the template version it belongs to.
+	if _, err := q.GetTemplateVersionByID(ctx, preset.TemplateVersionID); err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	return preset, nil
+}
+
+func (q *querier) GetTemplateVersionPresetParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	if _, err := q.GetTemplateVersionByID(ctx, templateVersionID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPresetParameters(ctx, templateVersionID)
+}
+
+func (q *querier) GetTemplateVersionPresetParametersByPresetID(ctx context.Context, templateVersionPresetID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	if _, err := q.GetTemplateVersionPresetByID(ctx, templateVersionPresetID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPresetParametersByPresetID(ctx, templateVersionPresetID)
+}
+
+func (q *querier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	if _, err := q.GetTemplateVersionByID(ctx, templateVersionID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPresets(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
 	if err != nil {
@@ -1880,6 +2316,42 @@ func (q *querier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([
 	return q.db.GetUserLinksByUserID(ctx, userID)
 }
 
+func (q *querier) GetUserLoginHistorySummary(ctx context.Context, arg database.GetUserLoginHistorySummaryParams) (database.GetUserLoginHistorySummaryRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetUserLoginHistorySummaryRow{}, err
+	}
+	return q.db.GetUserLoginHistorySummary(ctx, arg)
+}
+
+func (q *querier) GetUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) (database.UserLoginLockout, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID)); err != nil {
+		return database.UserLoginLockout{}, err
+	}
+	return q.db.GetUserLoginLockoutByUserID(ctx, userID)
+}
+
+func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, u.UserDataRBACObject()); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserNotificationPreferences(ctx, userID)
+}
+
+func (q *querier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.GetUserTOTPByUserID(ctx, userID)
+}
+
 func (q *querier) GetUserWorkspaceBuildParameters(ctx context.Context, params database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	u, err := q.db.GetUserByID(ctx, params.OwnerID)
 	if err != nil {
@@ -1912,6 +2384,34 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.GetWebhookByID(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.GetWebhookDeliveryByID(ctx, id)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhooks(ctx)
+}
+
 func (q *querier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	// This is a system function
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -2108,6 +2608,13 @@ func (q *querier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuil
 	return q.db.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
 }
 
+func (q *querier) GetWorkspaceBuildStatesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceBuildStatesByWorkspaceIDRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildStatesByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
 		return nil, err
@@ -2125,6 +2632,17 @@ func (q *querier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceBulkOperationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBulkOperation, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceBulkOperationByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceBulkOperationWorkspaces(ctx context.Context, operationID uuid.UUID) ([]database.GetWorkspaceBulkOperationWorkspacesRow, error) {
+	if _, err := q.GetWorkspaceBulkOperationByID(ctx, operationID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBulkOperationWorkspaces(ctx, operationID)
+}
+
 func (q *querier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.GetWorkspaceByAgentIDRow, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByAgentID)(ctx, agentID)
 }
@@ -2141,6 +2659,40 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceCountForTemplate(ctx context.Context, templateID uuid.UUID) (int64, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return -1, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, template); err != nil {
+		return -1, err
+	}
+	return q.db.GetWorkspaceCountForTemplate(ctx, templateID)
+}
+
+func (q *querier) GetWorkspaceCountForUser(ctx context.Context, arg database.GetWorkspaceCountForUserParams) (int64, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(arg.OwnerID))
+	if err != nil {
+		return -1, err
+	}
+	return q.db.GetWorkspaceCountForUser(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceLimitForUser(ctx context.Context, arg database.GetWorkspaceLimitForUserParams) (int32, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(arg.UserID))
+	if err != nil {
+		return -1, err
+	}
+	return q.db.GetWorkspaceLimitForUser(ctx, arg)
+}
+
+func (q *querier) GetWorkspacePrebuilds(ctx context.Context) ([]database.GetWorkspacePrebuildsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePrebuilds(ctx)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -2255,6 +2807,49 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSnapshotByBuildID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceSnapshot, error) {
+	snapshot, err := q.db.GetWorkspaceSnapshotByBuildID(ctx, buildID)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionRead, snapshot.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (q *querier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionRead, snapshot.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (q *querier) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionRead, arg.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return q.db.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceSnapshotRestoreByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceSnapshotRestore, error) {
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionRead, workspaceID); err != nil {
+		return database.WorkspaceSnapshotRestore{}, err
+	}
+	return q.db.GetWorkspaceSnapshotRestoreByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionRead, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2289,6 +2884,13 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceCustomRole); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.InsertCustomRole(ctx, arg)
+}
+
 func (q *querier) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2380,6 +2982,13 @@ func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database
 	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
 }
 
+func (q *querier) InsertOIDCSyncRule(ctx context.Context, arg database.InsertOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOIDCSyncRule); err != nil {
+		return database.OIDCSyncRule{}, err
+	}
+	return q.db.InsertOIDCSyncRule(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -2419,6 +3028,13 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSCIMToken(ctx context.Context, arg database.InsertSCIMTokenParams) (database.SCIMToken, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSCIMToken); err != nil {
+		return database.SCIMToken{}, err
+	}
+	return q.db.InsertSCIMToken(ctx, arg)
+}
+
 func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	obj := rbac.ResourceTemplate.InOrg(arg.OrganizationID)
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, obj); err != nil {
@@ -2427,6 +3043,13 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplateReleaseChannel(ctx context.Context, arg database.InsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionUpdate, arg.TemplateID); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return q.db.InsertTemplateReleaseChannel(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -2450,6 +3073,13 @@ func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.Insert
 	return q.db.InsertTemplateVersion(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionDiagnostic(ctx context.Context, arg database.InsertTemplateVersionDiagnosticParams) (database.TemplateVersionDiagnostic, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.TemplateVersionDiagnostic{}, err
+	}
+	return q.db.InsertTemplateVersionDiagnostic(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.TemplateVersionParameter{}, err
@@ -2457,6 +3087,20 @@ func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg databa
 	return q.db.InsertTemplateVersionParameter(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	return q.db.InsertTemplateVersionPreset(ctx, arg)
+}
+
+func (q *querier) InsertTemplateVersionPresetParameters(ctx context.Context, arg database.InsertTemplateVersionPresetParametersParams) ([]database.TemplateVersionPresetParameter, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertTemplateVersionPresetParameters(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -2493,6 +3137,27 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertUserLoginAttempt(ctx context.Context, arg database.InsertUserLoginAttemptParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertUserLoginAttempt(ctx, arg)
+}
+
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWebhook); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.InsertWebhook(ctx, arg)
+}
+
+func (q *querier) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.InsertWebhookDelivery(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -2594,9 +3259,18 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 
 		// If the template requires the active version we need to check if
 		// the user is a template admin. If they aren't and are attempting
-		// to use a non-active version then we must fail the request.
+		// to use a non-active version then we must fail the request. The
+		// active version of the workspace owner depends on their release
+		// channel.
 		if accessControl.RequireActiveVersion {
-			if arg.TemplateVersionID != t.ActiveVersionID {
+			active, err := q.db.GetTemplateActiveVersionForUser(ctx, database.GetTemplateActiveVersionForUserParams{
+				UserID:     w.OwnerID,
+				TemplateID: t.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("get active version for owner: %w", err)
+			}
+			if arg.TemplateVersionID != active.ActiveVersionID {
 				if err = q.authorizeContext(ctx, rbac.ActionUpdate, t); err != nil {
 					return xerrors.Errorf("cannot use non-active version: %w", err)
 				}
@@ -2619,7 +3293,7 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 		return err
 	}
 
-	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace.WorkspaceBuildRBAC(build.Transition))
 	if err != nil {
 		return err
 	}
@@ -2627,6 +3301,25 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBulkOperation(ctx context.Context, arg database.InsertWorkspaceBulkOperationParams) (database.WorkspaceBulkOperation, error) {
+	obj := rbac.ResourceWorkspaceBulkOperation.WithOwner(arg.InitiatorID.String())
+	return insert(q.log, q.auth, obj, q.db.InsertWorkspaceBulkOperation)(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceBulkOperationWorkspaces(ctx context.Context, arg database.InsertWorkspaceBulkOperationWorkspacesParams) error {
+	fetch := func(ctx context.Context, arg database.InsertWorkspaceBulkOperationWorkspacesParams) (database.WorkspaceBulkOperation, error) {
+		return q.db.GetWorkspaceBulkOperationByID(ctx, arg.OperationID)
+	}
+	return fetchAndExec(q.log, q.auth, rbac.ActionUpdate, fetch, q.db.InsertWorkspaceBulkOperationWorkspaces)(ctx, arg)
+}
+
+func (q *querier) InsertWorkspacePrebuild(ctx context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspacePrebuild(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -2645,6 +3338,20 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionUpdate, arg.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return q.db.InsertWorkspaceSnapshot(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSnapshotRestore(ctx context.Context, arg database.InsertWorkspaceSnapshotRestoreParams) (database.WorkspaceSnapshotRestore, error) {
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionUpdate, arg.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshotRestore{}, err
+	}
+	return q.db.InsertWorkspaceSnapshotRestore(ctx, arg)
+}
+
 func (q *querier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
 	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
 	if err != nil {
@@ -2659,6 +3366,34 @@ func (q *querier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID
 	return q.db.ListWorkspaceAgentPortShares(ctx, workspaceID)
 }
 
+func (q *querier) MarkNotificationMessageFailed(ctx context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageFailed(ctx, arg)
+}
+
+func (q *querier) MarkNotificationMessageSent(ctx context.Context, arg database.MarkNotificationMessageSentParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageSent(ctx, arg)
+}
+
+func (q *querier) MarkWebhookDeliveryFailed(ctx context.Context, arg database.MarkWebhookDeliveryFailedParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkWebhookDeliveryFailed(ctx, arg)
+}
+
+func (q *querier) MarkWebhookDeliverySucceeded(ctx context.Context, arg database.MarkWebhookDeliverySucceededParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkWebhookDeliverySucceeded(ctx, arg)
+}
+
 func (q *querier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
 	template, err := q.db.GetTemplateByID(ctx, templateID)
 	if err != nil {
@@ -2728,6 +3463,13 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceCustomRole); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.UpdateCustomRole(ctx, arg)
+}
+
 func (q *querier) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		return q.db.GetExternalAuthLink(ctx, database.GetExternalAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -2791,6 +3533,13 @@ func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg dat
 	return q.db.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
 }
 
+func (q *querier) UpdateOIDCSyncRule(ctx context.Context, arg database.UpdateOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceOIDCSyncRule); err != nil {
+		return database.OIDCSyncRule{}, err
+	}
+	return q.db.UpdateOIDCSyncRule(ctx, arg)
+}
+
 func (q *querier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon); err != nil {
 		return err
@@ -2806,6 +3555,13 @@ func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.Upd
 	return q.db.UpdateProvisionerJobByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerJobEngineVersionConstraintByID(ctx context.Context, arg database.UpdateProvisionerJobEngineVersionConstraintByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobEngineVersionConstraintByID(ctx, arg)
+}
+
 func (q *querier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	job, err := q.db.GetProvisionerJobByID(ctx, arg.ID)
 	if err != nil {
@@ -2888,6 +3644,13 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateSCIMTokenLastUsedAt(ctx context.Context, arg database.UpdateSCIMTokenLastUsedAtParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSCIMToken); err != nil {
+		return err
+	}
+	return q.db.UpdateSCIMTokenLastUsedAt(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -2923,6 +3686,17 @@ func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.Updat
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMetaByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateReleaseChannelByID(ctx context.Context, arg database.UpdateTemplateReleaseChannelByIDParams) (database.TemplateReleaseChannel, error) {
+	channel, err := q.db.GetTemplateReleaseChannelByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	if err := q.authorizeTemplateReleaseChannel(ctx, rbac.ActionUpdate, channel.TemplateID); err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+	return q.db.UpdateTemplateReleaseChannelByID(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -3120,6 +3894,24 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpdateUserTOTPLastUsedStep(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceWebhook); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.UpdateWebhookByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -3127,6 +3919,16 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	// Like templates, updating the ACL uses the ActionCreate action, which
+	// the ACL itself never grants. Only the owner and administrators may
+	// share a workspace.
+	return fetchAndExec(q.log, q.auth, rbac.ActionCreate, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3264,6 +4066,20 @@ func (q *querier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context,
 	return q.db.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceBulkOperationStatus(ctx context.Context, arg database.UpdateWorkspaceBulkOperationStatusParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceBulkOperationStatusParams) (database.WorkspaceBulkOperation, error) {
+		return q.db.GetWorkspaceBulkOperationByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceBulkOperationStatus)(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceBulkOperationWorkspace(ctx context.Context, arg database.UpdateWorkspaceBulkOperationWorkspaceParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceBulkOperationWorkspaceParams) (database.WorkspaceBulkOperation, error) {
+		return q.db.GetWorkspaceBulkOperationByID(ctx, arg.OperationID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceBulkOperationWorkspace)(ctx, arg)
+}
+
 // Deprecated: Use SoftDeleteWorkspaceByID
 func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	// TODO deleteQ me, placeholder for database.Store
@@ -3288,6 +4104,22 @@ func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.Up
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	// Transferring a workspace removes it from its owner and creates it for
+	// the new owner.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, workspace); err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID).WithOwner(arg.OwnerID.String())); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceOwnerByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -3302,6 +4134,17 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeWorkspaceSnapshot(ctx, rbac.ActionUpdate, snapshot.WorkspaceID); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceSnapshotArtifactsByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -3386,7 +4229,7 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 }
 
 func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
-	res := rbac.ResourceProvisionerDaemon.All()
+	res := rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID)
 	if arg.Tags[provisionersdk.TagScope] == provisionersdk.ScopeUser {
 		res.Owner = arg.Tags[provisionersdk.TagOwner]
 	}
@@ -3452,6 +4295,35 @@ func (q *querier) UpsertTemplateUsageStats(ctx context.Context) error {
 	return q.db.UpsertTemplateUsageStats(ctx)
 }
 
+func (q *querier) UpsertUserLoginLockout(ctx context.Context, arg database.UpsertUserLoginLockoutParams) (database.UserLoginLockout, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(arg.UserID)); err != nil {
+		return database.UserLoginLockout{}, err
+	}
+	return q.db.UpsertUserLoginLockout(ctx, arg)
+}
+
+func (q *querier) UpsertUserNotificationPreference(ctx context.Context, arg database.UpsertUserNotificationPreferenceParams) (database.NotificationPreference, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.NotificationPreference{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.NotificationPreference{}, err
+	}
+	return q.db.UpsertUserNotificationPreference(ctx, arg)
+}
+
+func (q *querier) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpsertUserTOTP(ctx, arg)
+}
+
 func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -3466,6 +4338,17 @@ func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg databas
 	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
 }
 
+func (q *querier) UseUserTOTPRecoveryCode(ctx context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UseUserTOTPRecoveryCode(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
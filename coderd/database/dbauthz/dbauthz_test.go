@@ -203,6 +203,28 @@ func (s *MethodTestSuite) TestAPIKey() {
 			Asserts(keyA, rbac.ActionRead, keyB, rbac.ActionRead).
 			Returns(slice.New(keyA, keyB))
 	}))
+	s.Run("GetImpersonationAPIKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		owner := dbgen.User(s.T(), db, database.User{})
+		impersonator := uuid.NullUUID{UUID: owner.ID, Valid: true}
+
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, ImpersonatorID: impersonator})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, ImpersonatorID: impersonator, ExpiresAt: dbtime.Now().Add(-time.Hour)})
+
+		check.Args(u.ID).
+			Asserts(key, rbac.ActionRead).
+			Returns(slice.New(key))
+	}))
+	s.Run("GetActiveAPIKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, ExpiresAt: dbtime.Now().Add(-time.Hour)})
+
+		check.Args(u.ID).
+			Asserts(key, rbac.ActionRead).
+			Returns(slice.New(key))
+	}))
 	s.Run("GetAPIKeysLastUsedAfter", s.Subtest(func(db database.Store, check *expects) {
 		a, _ := dbgen.APIKey(s.T(), db, database.APIKey{LastUsed: time.Now().Add(time.Hour)})
 		b, _ := dbgen.APIKey(s.T(), db, database.APIKey{LastUsed: time.Now().Add(time.Hour)})
@@ -591,6 +613,13 @@ func (s *MethodTestSuite) TestOrganization() {
 		check.Args([]uuid.UUID{ma.UserID, mb.UserID}).
 			Asserts(rbac.ResourceUserObject(ma.UserID), rbac.ActionRead, rbac.ResourceUserObject(mb.UserID), rbac.ActionRead)
 	}))
+	s.Run("DeleteOrganizationMember", s.Subtest(func(db database.Store, check *expects) {
+		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{})
+		check.Args(database.DeleteOrganizationMemberParams{
+			OrganizationID: mem.OrganizationID,
+			UserID:         mem.UserID,
+		}).Asserts(mem, rbac.ActionDelete).Returns()
+	}))
 	s.Run("GetOrganizationMemberByUserID", s.Subtest(func(db database.Store, check *expects) {
 		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{})
 		check.Args(database.GetOrganizationMemberByUserIDParams{
@@ -700,6 +729,10 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 }
 
 func (s *MethodTestSuite) TestTemplate() {
+	s.Run("GetWorkspaceCountForTemplate", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns(int64(0))
+	}))
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
 		now := time.Now()
@@ -765,6 +798,49 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionParameter{})
 	}))
+	s.Run("GetTemplateVersionPresets", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		preset := dbgen.TemplateVersionPreset(s.T(), db, database.TemplateVersionPreset{
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionPreset{preset})
+	}))
+	s.Run("GetTemplateVersionPresetByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		preset := dbgen.TemplateVersionPreset(s.T(), db, database.TemplateVersionPreset{
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(preset.ID).Asserts(t1, rbac.ActionRead).Returns(preset)
+	}))
+	s.Run("GetTemplateVersionPresetParameters", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionPresetParameter{})
+	}))
+	s.Run("GetTemplateVersionPresetParametersByPresetID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		preset := dbgen.TemplateVersionPreset(s.T(), db, database.TemplateVersionPreset{
+			TemplateVersionID: tv.ID,
+		})
+		parameters, err := db.InsertTemplateVersionPresetParameters(context.Background(), database.InsertTemplateVersionPresetParametersParams{
+			TemplateVersionPresetID: preset.ID,
+			Names:                   []string{"cpu"},
+			Values:                  []string{"4"},
+		})
+		require.NoError(s.T(), err)
+		check.Args(preset.ID).Asserts(t1, rbac.ActionRead).Returns(parameters)
+	}))
 	s.Run("GetTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -775,6 +851,20 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionVariable{tvv1})
 	}))
+	s.Run("GetTemplateVersionDiagnostics", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		d1, err := db.InsertTemplateVersionDiagnostic(context.Background(), database.InsertTemplateVersionDiagnosticParams{
+			ID:                uuid.New(),
+			TemplateVersionID: tv.ID,
+			Severity:          database.TemplateVersionDiagnosticSeverityWarning,
+			Code:              "agent-not-attached",
+		})
+		require.NoError(s.T(), err)
+		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionDiagnostic{d1})
+	}))
 	s.Run("GetTemplateGroupRoles", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate)
@@ -983,13 +1073,37 @@ func (s *MethodTestSuite) TestUser() {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(rbac.ResourceAPIKey.WithOwner(u.ID.String()), rbac.ActionDelete).Returns()
 	}))
+	s.Run("DeleteOtherAPIKeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		current, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		check.Args(database.DeleteOtherAPIKeysByUserIDParams{
+			UserID:   u.ID,
+			ExceptID: current.ID,
+		}).Asserts(rbac.ResourceAPIKey.WithOwner(u.ID.String()), rbac.ActionDelete).Returns([]database.APIKey{})
+	}))
 	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(int64(0))
+		check.Args(database.GetQuotaAllowanceForUserParams{
+			UserID: u.ID,
+		}).Asserts(u, rbac.ActionRead).Returns(int64(0))
 	}))
 	s.Run("GetQuotaConsumedForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(int64(0))
+		check.Args(database.GetQuotaConsumedForUserParams{
+			OwnerID: u.ID,
+		}).Asserts(u, rbac.ActionRead).Returns(int64(0))
+	}))
+	s.Run("GetWorkspaceCountForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetWorkspaceCountForUserParams{
+			OwnerID: u.ID,
+		}).Asserts(u, rbac.ActionRead).Returns(int64(0))
+	}))
+	s.Run("GetWorkspaceLimitForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetWorkspaceLimitForUserParams{
+			UserID: u.ID,
+		}).Asserts(u, rbac.ActionRead).Returns(int32(0))
 	}))
 	s.Run("GetUserByEmailOrUsername", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
@@ -1360,6 +1474,11 @@ func (s *MethodTestSuite) TestWorkspace() {
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, BuildNumber: 3})
 		check.Args(database.GetWorkspaceBuildsByWorkspaceIDParams{WorkspaceID: ws.ID}).Asserts(ws, rbac.ActionRead) // ordering
 	}))
+	s.Run("GetWorkspaceBuildStatesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, BuildNumber: 1})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
 	s.Run("GetWorkspaceByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
@@ -1420,6 +1539,16 @@ func (s *MethodTestSuite) TestWorkspace() {
 			AutomaticUpdates: database.AutomaticUpdatesNever,
 		}).Asserts(rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), rbac.ActionCreate)
 	}))
+	s.Run("ClaimWorkspacePrebuild", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.ClaimWorkspacePrebuildParams{
+			TemplateVersionPresetID: uuid.New(),
+			OrganizationID:          o.ID,
+			OwnerID:                 u.ID,
+			AutomaticUpdates:        database.AutomaticUpdatesNever,
+		}).Asserts(rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), rbac.ActionCreate).Errors(sql.ErrNoRows)
+	}))
 	s.Run("Start/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
 		t := dbgen.Template(s.T(), db, database.Template{})
 		w := dbgen.Workspace(s.T(), db, database.Workspace{
@@ -1497,7 +1626,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceBuildID: b.ID,
 			Name:             []string{"foo", "bar"},
 			Value:            []string{"baz", "qux"},
-		}).Asserts(w, rbac.ActionUpdate)
+		}).Asserts(w.WorkspaceBuildRBAC(b.Transition), rbac.ActionUpdate)
 	}))
 	s.Run("UpdateWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1507,6 +1636,20 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, rbac.ActionUpdate).Returns(expected)
 	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID: w.ID,
+		}).Asserts(w, rbac.ActionCreate)
+	}))
+	s.Run("UpdateWorkspaceOwnerByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateWorkspaceOwnerByIDParams{
+			ID:      w.ID,
+			OwnerID: u.ID,
+		}).Asserts(w, rbac.ActionDelete, rbac.ResourceWorkspace.InOrg(w.OrganizationID).WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
 	s.Run("UpdateWorkspaceDormantDeletingAt", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceDormantDeletingAtParams{
@@ -1677,6 +1820,17 @@ func (s *MethodTestSuite) TestExtraMethods() {
 		s.NoError(err, "insert provisioner daemon")
 		check.Args().Asserts(d, rbac.ActionRead)
 	}))
+	s.Run("GetProvisionerDaemonsByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		d, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
+			OrganizationID: org.ID,
+			Tags: database.StringMap(map[string]string{
+				provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+			}),
+		})
+		s.NoError(err, "insert provisioner daemon")
+		check.Args(org.ID).Asserts(d, rbac.ActionRead).Returns(slice.New(d))
+	}))
 	s.Run("DeleteOldProvisionerDaemons", s.Subtest(func(db database.Store, check *expects) {
 		_, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
 			Tags: database.StringMap(map[string]string{
@@ -1991,6 +2145,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspaceBuildProvisionerStates", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(int32(10)).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -2110,6 +2267,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		check.Args(database.AcquireProvisionerJobParams{OrganizationID: j.OrganizationID, Types: []database.ProvisionerType{j.Provisioner}, Tags: must(json.Marshal(j.Tags))}).
 			Asserts( /*rbac.ResourceSystem, rbac.ActionUpdate*/ )
 	}))
+	s.Run("GetPendingProvisionerJobEngineVersionConstraints", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			EngineVersionConstraint: "~> 1.5.0",
+		})
+		check.Args(j.OrganizationID).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("UpdateProvisionerJobWithCompleteByID", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
@@ -2125,6 +2288,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			UpdatedAt: time.Now(),
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionUpdate*/ )
 	}))
+	s.Run("UpdateProvisionerJobEngineVersionConstraintByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.UpdateProvisionerJobEngineVersionConstraintByIDParams{
+			ID:                      j.ID,
+			EngineVersionConstraint: "~> 1.5.0",
+			UpdatedAt:               time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("InsertProvisionerJob", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		check.Args(database.InsertProvisionerJobParams{
@@ -2142,13 +2313,16 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionCreate*/ )
 	}))
 	s.Run("UpsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
-		pd := rbac.ResourceProvisionerDaemon.All()
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		pd := rbac.ResourceProvisionerDaemon.InOrg(org.ID)
 		check.Args(database.UpsertProvisionerDaemonParams{
+			OrganizationID: org.ID,
 			Tags: database.StringMap(map[string]string{
 				provisionersdk.TagScope: provisionersdk.ScopeOrganization,
 			}),
 		}).Asserts(pd, rbac.ActionCreate)
 		check.Args(database.UpsertProvisionerDaemonParams{
+			OrganizationID: org.ID,
 			Tags: database.StringMap(map[string]string{
 				provisionersdk.TagScope: provisionersdk.ScopeUser,
 				provisionersdk.TagOwner: "11111111-1111-1111-1111-111111111111",
@@ -2270,6 +2444,38 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("InsertTemplateVersionVariable", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertTemplateVersionVariableParams{}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertTemplateVersionPreset", s.Subtest(func(db database.Store, check *expects) {
+		v := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{})
+		check.Args(database.InsertTemplateVersionPresetParams{
+			ID:                uuid.New(),
+			TemplateVersionID: v.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertWorkspacePrebuild", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspacePrebuildParams{
+			WorkspaceID:             uuid.New(),
+			TemplateVersionPresetID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetPrebuildPresets", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspacePrebuilds", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteWorkspacePrebuildsOfDeletedWorkspaces", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertTemplateVersionPresetParameters", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertTemplateVersionPresetParametersParams{
+			TemplateVersionPresetID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertTemplateVersionDiagnostic", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertTemplateVersionDiagnosticParams{
+			Severity: database.TemplateVersionDiagnosticSeverityWarning,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("UpdateInactiveUsersToDormant", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpdateInactiveUsersToDormantParams{}).Asserts(rbac.ResourceSystem, rbac.ActionCreate).Errors(sql.ErrNoRows)
 	}))
@@ -2334,6 +2540,519 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	}))
 }
 
+func (s *MethodTestSuite) TestNotifications() {
+	s.Run("EnqueueNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.EnqueueNotificationMessageParams{
+			ID:      uuid.New(),
+			UserID:  u.ID,
+			Method:  database.NotificationMethodSmtp,
+			Payload: []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireNotificationMessagesParams{
+			Method: database.NotificationMethodSmtp,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns([]database.NotificationMessage{})
+	}))
+	s.Run("MarkNotificationMessageSent", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageSentParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("MarkNotificationMessageFailed", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageFailedParams{
+			ID:     uuid.New(),
+			Status: database.NotificationMessageStatusTemporaryFailure,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetNotificationMessagesByStatus", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetNotificationMessagesByStatusParams{
+			Status: database.NotificationMessageStatusSent,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetUserNotificationPreferences", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionRead)
+	}))
+	s.Run("UpsertUserNotificationPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserNotificationPreferenceParams{
+			UserID:   u.ID,
+			Template: "workspace-dormant",
+			Disabled: true,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestCustomRoles() {
+	s.Run("GetCustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		roles := []database.CustomRole{
+			dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "first"}),
+			dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "last"}),
+		}
+		check.Args().Asserts(rbac.ResourceCustomRole, rbac.ActionRead).Returns(roles)
+	}))
+	s.Run("GetCustomRoleByName", s.Subtest(func(db database.Store, check *expects) {
+		role := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(database.GetCustomRoleByNameParams{
+			Name: role.Name,
+		}).Asserts(rbac.ResourceCustomRole, rbac.ActionRead).Returns(role)
+	}))
+	s.Run("InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertCustomRoleParams{
+			ID:   uuid.New(),
+			Name: "template-operator",
+		}).Asserts(rbac.ResourceCustomRole, rbac.ActionCreate)
+	}))
+	s.Run("UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		role := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		role.DisplayName = "Updated"
+		check.Args(database.UpdateCustomRoleParams{
+			ID:              role.ID,
+			DisplayName:     role.DisplayName,
+			SitePermissions: role.SitePermissions,
+			OrgPermissions:  role.OrgPermissions,
+			UserPermissions: role.UserPermissions,
+			UpdatedAt:       role.UpdatedAt,
+		}).Asserts(rbac.ResourceCustomRole, rbac.ActionUpdate).Returns(role)
+	}))
+	s.Run("DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		role := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(role.ID).Asserts(rbac.ResourceCustomRole, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOIDCSyncRules() {
+	s.Run("GetOIDCSyncRules", s.Subtest(func(db database.Store, check *expects) {
+		rule := dbgen.OIDCSyncRule(s.T(), db, database.OIDCSyncRule{})
+		check.Args().Asserts(rbac.ResourceOIDCSyncRule, rbac.ActionRead).Returns([]database.OIDCSyncRule{rule})
+	}))
+	s.Run("GetOIDCSyncRuleByID", s.Subtest(func(db database.Store, check *expects) {
+		rule := dbgen.OIDCSyncRule(s.T(), db, database.OIDCSyncRule{})
+		check.Args(rule.ID).Asserts(rbac.ResourceOIDCSyncRule, rbac.ActionRead).Returns(rule)
+	}))
+	s.Run("InsertOIDCSyncRule", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOIDCSyncRuleParams{
+			ID:      uuid.New(),
+			Claim:   "groups",
+			Pattern: "^admins$",
+		}).Asserts(rbac.ResourceOIDCSyncRule, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOIDCSyncRule", s.Subtest(func(db database.Store, check *expects) {
+		rule := dbgen.OIDCSyncRule(s.T(), db, database.OIDCSyncRule{})
+		rule.Pattern = "^admins$"
+		check.Args(database.UpdateOIDCSyncRuleParams{
+			ID:                rule.ID,
+			Claim:             rule.Claim,
+			Pattern:           rule.Pattern,
+			SiteRoles:         rule.SiteRoles,
+			OrganizationRoles: rule.OrganizationRoles,
+			UpdatedAt:         rule.UpdatedAt,
+		}).Asserts(rbac.ResourceOIDCSyncRule, rbac.ActionUpdate).Returns(rule)
+	}))
+	s.Run("DeleteOIDCSyncRule", s.Subtest(func(db database.Store, check *expects) {
+		rule := dbgen.OIDCSyncRule(s.T(), db, database.OIDCSyncRule{})
+		check.Args(rule.ID).Asserts(rbac.ResourceOIDCSyncRule, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestSCIMTokens() {
+	s.Run("GetSCIMTokens", s.Subtest(func(db database.Store, check *expects) {
+		token := dbgen.SCIMToken(s.T(), db, database.SCIMToken{})
+		check.Args().Asserts(rbac.ResourceSCIMToken, rbac.ActionRead).Returns([]database.SCIMToken{token})
+	}))
+	s.Run("GetSCIMTokenByID", s.Subtest(func(db database.Store, check *expects) {
+		token := dbgen.SCIMToken(s.T(), db, database.SCIMToken{})
+		check.Args(token.ID).Asserts(rbac.ResourceSCIMToken, rbac.ActionRead).Returns(token)
+	}))
+	s.Run("GetSCIMTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		token := dbgen.SCIMToken(s.T(), db, database.SCIMToken{})
+		check.Args(token.SecretPrefix).Asserts(rbac.ResourceSCIMToken, rbac.ActionRead).Returns(token)
+	}))
+	s.Run("InsertSCIMToken", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertSCIMTokenParams{
+			ID:           uuid.New(),
+			Name:         "okta",
+			SecretPrefix: []byte("prefix"),
+		}).Asserts(rbac.ResourceSCIMToken, rbac.ActionCreate)
+	}))
+	s.Run("UpdateSCIMTokenLastUsedAt", s.Subtest(func(db database.Store, check *expects) {
+		token := dbgen.SCIMToken(s.T(), db, database.SCIMToken{})
+		check.Args(database.UpdateSCIMTokenLastUsedAtParams{
+			ID:         token.ID,
+			LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSCIMToken, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteSCIMToken", s.Subtest(func(db database.Store, check *expects) {
+		token := dbgen.SCIMToken(s.T(), db, database.SCIMToken{})
+		check.Args(token.ID).Asserts(rbac.ResourceSCIMToken, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestUserLoginAttempts() {
+	s.Run("InsertUserLoginAttempt", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserLoginAttemptParams{
+			ID:        uuid.New(),
+			UserID:    uuid.NullUUID{UUID: u.ID, Valid: true},
+			UserAgent: "test",
+			CreatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetLoginFailureStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetLoginFailureStatsParams{
+			UserID: uuid.New(),
+			Since:  dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetUserLoginHistorySummary", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUserLoginHistorySummaryParams{
+			UserID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteUserLoginFailures", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldUserLoginAttempts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetUserLoginLockoutByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		lockout := dbgen.UserLoginLockout(s.T(), db, database.UserLoginLockout{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), rbac.ActionRead).Returns(lockout)
+	}))
+	s.Run("UpsertUserLoginLockout", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserLoginLockoutParams{
+			UserID:         u.ID,
+			FailedAttempts: 10,
+			CreatedAt:      dbtime.Now(),
+			LockedUntil:    dbtime.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceUserObject(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserLoginLockoutByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserLoginLockout(s.T(), db, database.UserLoginLockout{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserObject(u.ID), rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestUserTOTP() {
+	s.Run("GetUserTOTPByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		totp := dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionRead).Returns(totp)
+	}))
+	s.Run("UpsertUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserTOTPParams{
+			UserID:    u.ID,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			CreatedAt: dbtime.Now(),
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("EnableUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID})
+		check.Args(database.EnableUserTOTPParams{
+			EnabledAt:           sql.NullTime{Time: dbtime.Now(), Valid: true},
+			LastUsedStep:        1,
+			HashedRecoveryCodes: []string{"code"},
+			UserID:              u.ID,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("UpdateUserTOTPLastUsedStep", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID})
+		check.Args(database.UpdateUserTOTPLastUsedStepParams{
+			LastUsedStep: 1,
+			UserID:       u.ID,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("UseUserTOTPRecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{
+			UserID:              u.ID,
+			EnabledAt:           sql.NullTime{Time: dbtime.Now(), Valid: true},
+			HashedRecoveryCodes: []string{"code"},
+		})
+		check.Args(database.UseUserTOTPRecoveryCodeParams{
+			HashedCode: "code",
+			UserID:     u.ID,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserTOTPByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestWebhooks() {
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		webhooks := []database.Webhook{
+			dbgen.Webhook(s.T(), db, database.Webhook{Name: "first"}),
+			dbgen.Webhook(s.T(), db, database.Webhook{Name: "last"}),
+		}
+		check.Args().Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(webhooks)
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(webhook.ID).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(webhook)
+	}))
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID:   uuid.New(),
+			Name: "hook",
+			Url:  "http://localhost",
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("UpdateWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		webhook.Name = "updated"
+		check.Args(database.UpdateWebhookByIDParams{
+			ID:        webhook.ID,
+			Name:      webhook.Name,
+			Url:       webhook.Url,
+			Events:    webhook.Events,
+			Enabled:   webhook.Enabled,
+			UpdatedAt: webhook.UpdatedAt,
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionUpdate).Returns(webhook)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(webhook.ID).Asserts(rbac.ResourceWebhook, rbac.ActionDelete)
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		delivery := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: webhook.ID})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+			LimitOpt:  10,
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns([]database.WebhookDelivery{delivery})
+	}))
+	s.Run("GetWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		delivery := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: webhook.ID})
+		check.Args(delivery.ID).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(delivery)
+	}))
+	s.Run("InsertWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.InsertWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: webhook.ID,
+			Event:     "workspace.created",
+			Payload:   []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireWebhookDeliveriesParams{
+			MaxAttemptCount: 5,
+			Count:           10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns([]database.WebhookDelivery{})
+	}))
+	s.Run("MarkWebhookDeliverySucceeded", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkWebhookDeliverySucceededParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("MarkWebhookDeliveryFailed", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkWebhookDeliveryFailedParams{
+			ID:     uuid.New(),
+			Status: database.WebhookDeliveryStatusTemporaryFailure,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceBulkOperations() {
+	s.Run("InsertWorkspaceBulkOperation", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertWorkspaceBulkOperationParams{
+			ID:          uuid.New(),
+			InitiatorID: u.ID,
+			Action:      database.WorkspaceBulkActionStop,
+		}).Asserts(rbac.ResourceWorkspaceBulkOperation.WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceBulkOperationByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		check.Args(o.ID).Asserts(o, rbac.ActionRead).Returns(o)
+	}))
+	s.Run("GetRunningWorkspaceBulkOperations", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]database.WorkspaceBulkOperation{o})
+	}))
+	s.Run("GetWorkspaceBulkOperationWorkspaces", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		check.Args(o.ID).Asserts(o, rbac.ActionRead).Returns([]database.GetWorkspaceBulkOperationWorkspacesRow{})
+	}))
+	s.Run("InsertWorkspaceBulkOperationWorkspaces", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		w := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		check.Args(database.InsertWorkspaceBulkOperationWorkspacesParams{
+			OperationID:  o.ID,
+			WorkspaceIds: []uuid.UUID{w.ID},
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceBulkOperationWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		check.Args(database.UpdateWorkspaceBulkOperationWorkspaceParams{
+			OperationID: o.ID,
+			WorkspaceID: uuid.New(),
+			Status:      database.WorkspaceBulkResultStatusSkipped,
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceBulkOperationStatus", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.WorkspaceBulkOperation(s.T(), db, database.WorkspaceBulkOperation{InitiatorID: u.ID})
+		check.Args(database.UpdateWorkspaceBulkOperationStatusParams{
+			ID:     o.ID,
+			Status: database.WorkspaceBulkOperationStatusCompleted,
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestTemplateReleaseChannels() {
+	s.Run("InsertTemplateReleaseChannel", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateReleaseChannelParams{
+			ID:                uuid.New(),
+			TemplateID:        t1.ID,
+			Name:              "canary",
+			TemplateVersionID: t1.ActiveVersionID,
+			UserIDs:           []uuid.UUID{},
+			GroupIDs:          []uuid.UUID{},
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("GetTemplateReleaseChannelByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{TemplateID: t1.ID})
+		check.Args(c.ID).Asserts(t1, rbac.ActionRead).Returns(c)
+	}))
+	s.Run("GetTemplateReleaseChannelByTemplateIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{TemplateID: t1.ID})
+		check.Args(database.GetTemplateReleaseChannelByTemplateIDAndNameParams{
+			TemplateID: t1.ID,
+			Name:       c.Name,
+		}).Asserts(t1, rbac.ActionRead).Returns(c)
+	}))
+	s.Run("GetTemplateReleaseChannelsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateReleaseChannel{c})
+	}))
+	s.Run("UpdateTemplateReleaseChannelByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateReleaseChannelByIDParams{
+			ID:                c.ID,
+			TemplateVersionID: c.TemplateVersionID,
+			UserIDs:           []uuid.UUID{},
+			GroupIDs:          []uuid.UUID{},
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateReleaseChannelByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{TemplateID: t1.ID})
+		check.Args(c.ID).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("GetTemplateActiveVersionForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		c := dbgen.TemplateReleaseChannel(s.T(), db, database.TemplateReleaseChannel{
+			TemplateID: t1.ID,
+			UserIDs:    []uuid.UUID{u.ID},
+		})
+		check.Args(database.GetTemplateActiveVersionForUserParams{
+			UserID:     u.ID,
+			TemplateID: t1.ID,
+		}).Asserts(t1, rbac.ActionRead).Returns(database.GetTemplateActiveVersionForUserRow{
+			ActiveVersionID: c.TemplateVersionID,
+			ReleaseChannel:  c.Name,
+		})
+	}))
+	s.Run("GetTemplateActiveVersionsByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceSnapshots() {
+	s.Run("InsertWorkspaceSnapshot", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceSnapshotParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Name:        "datasets",
+			BuildID:     uuid.New(),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(snapshot.ID).Asserts(ws, rbac.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotByBuildID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(snapshot.BuildID).Asserts(ws, rbac.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotByWorkspaceIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams{
+			WorkspaceID: ws.ID,
+			Name:        snapshot.Name,
+		}).Asserts(ws, rbac.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceSnapshot{snapshot})
+	}))
+	s.Run("UpdateWorkspaceSnapshotArtifactsByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+			ID:        snapshot.ID,
+			Artifacts: json.RawMessage(`{"home":"snap-1"}`),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{WorkspaceID: ws.ID})
+		check.Args(snapshot.ID).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceSnapshotRestore", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceSnapshotRestoreParams{
+			WorkspaceID: ws.ID,
+			Artifacts:   json.RawMessage(`{"home":"snap-1"}`),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSnapshotRestoreByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		restore, err := db.InsertWorkspaceSnapshotRestore(context.Background(), database.InsertWorkspaceSnapshotRestoreParams{
+			WorkspaceID: ws.ID,
+			Artifacts:   json.RawMessage(`{"home":"snap-1"}`),
+		})
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns(restore)
+	}))
+}
+
 func (s *MethodTestSuite) TestOAuth2ProviderApps() {
 	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
 		apps := []database.OAuth2ProviderApp{
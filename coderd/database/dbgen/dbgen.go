@@ -59,6 +59,7 @@ func AuditLog(t testing.TB, db database.Store, seed database.AuditLog) database.
 		AdditionalFields: takeFirstSlice(seed.Diff, []byte("{}")),
 		RequestID:        takeFirst(seed.RequestID, uuid.New()),
 		ResourceIcon:     takeFirst(seed.ResourceIcon, ""),
+		ImpersonatorID:   seed.ImpersonatorID,
 	})
 	require.NoError(t, err, "insert audit log")
 	return log
@@ -129,6 +130,8 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
 		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
 		TokenName:       takeFirst(seed.TokenName),
+		ImpersonatorID:  seed.ImpersonatorID,
+		UserAgent:       seed.UserAgent,
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -284,14 +287,15 @@ func WorkspaceBuildParameters(t testing.TB, db database.Store, orig []database.W
 
 func User(t testing.TB, db database.Store, orig database.User) database.User {
 	user, err := db.InsertUser(genCtx, database.InsertUserParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		Email:          takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
-		Username:       takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
-		HashedPassword: takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
-		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-		RBACRoles:      takeFirstSlice(orig.RBACRoles, []string{}),
-		LoginType:      takeFirst(orig.LoginType, database.LoginTypePassword),
+		ID:               takeFirst(orig.ID, uuid.New()),
+		Email:            takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
+		Username:         takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
+		HashedPassword:   takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
+		CreatedAt:        takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:        takeFirst(orig.UpdatedAt, dbtime.Now()),
+		RBACRoles:        takeFirstSlice(orig.RBACRoles, []string{}),
+		LoginType:        takeFirst(orig.LoginType, database.LoginTypePassword),
+		IsServiceAccount: orig.IsServiceAccount,
 	})
 	require.NoError(t, err, "insert user")
 
@@ -357,17 +361,61 @@ func OrganizationMember(t testing.TB, db database.Store, orig database.Organizat
 func Group(t testing.TB, db database.Store, orig database.Group) database.Group {
 	name := takeFirst(orig.Name, namesgenerator.GetRandomName(1))
 	group, err := db.InsertGroup(genCtx, database.InsertGroupParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		Name:           name,
-		DisplayName:    takeFirst(orig.DisplayName, name),
-		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
-		AvatarURL:      takeFirst(orig.AvatarURL, "https://logo.example.com"),
-		QuotaAllowance: takeFirst(orig.QuotaAllowance, 0),
+		ID:                   takeFirst(orig.ID, uuid.New()),
+		Name:                 name,
+		DisplayName:          takeFirst(orig.DisplayName, name),
+		OrganizationID:       takeFirst(orig.OrganizationID, uuid.New()),
+		AvatarURL:            takeFirst(orig.AvatarURL, "https://logo.example.com"),
+		QuotaAllowance:       takeFirst(orig.QuotaAllowance, 0),
+		MaxWorkspacesPerUser: takeFirst(orig.MaxWorkspacesPerUser, 0),
 	})
 	require.NoError(t, err, "insert group")
 	return group
 }
 
+func CustomRole(t testing.TB, db database.Store, orig database.CustomRole) database.CustomRole {
+	name := takeFirst(orig.Name, namesgenerator.GetRandomName(1))
+	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
+		ID:              takeFirst(orig.ID, uuid.New()),
+		Name:            name,
+		DisplayName:     takeFirst(orig.DisplayName, name),
+		OrganizationID:  orig.OrganizationID,
+		SitePermissions: takeFirstSlice(orig.SitePermissions, database.CustomRolePermissions{}),
+		OrgPermissions:  takeFirstSlice(orig.OrgPermissions, database.CustomRolePermissions{}),
+		UserPermissions: takeFirstSlice(orig.UserPermissions, database.CustomRolePermissions{}),
+		CreatedAt:       takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert custom role")
+	return role
+}
+
+func OIDCSyncRule(t testing.TB, db database.Store, orig database.OIDCSyncRule) database.OIDCSyncRule {
+	rule, err := db.InsertOIDCSyncRule(genCtx, database.InsertOIDCSyncRuleParams{
+		ID:                takeFirst(orig.ID, uuid.New()),
+		Claim:             takeFirst(orig.Claim, "groups"),
+		Pattern:           takeFirst(orig.Pattern, ".*"),
+		SiteRoles:         takeFirstSlice(orig.SiteRoles, []string{}),
+		OrganizationID:    orig.OrganizationID,
+		OrganizationRoles: takeFirstSlice(orig.OrganizationRoles, []string{}),
+		CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert oidc sync rule")
+	return rule
+}
+
+func SCIMToken(t testing.TB, db database.Store, orig database.SCIMToken) database.SCIMToken {
+	token, err := db.InsertSCIMToken(genCtx, database.InsertSCIMTokenParams{
+		ID:            takeFirst(orig.ID, uuid.New()),
+		Name:          takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		CreatedAt:     takeFirst(orig.CreatedAt, dbtime.Now()),
+		SecretPrefix:  takeFirstSlice(orig.SecretPrefix, []byte(uuid.NewString())),
+		HashedSecret:  takeFirstSlice(orig.HashedSecret, []byte("hashed-secret")),
+		DisplaySecret: takeFirst(orig.DisplaySecret, "secret"),
+	})
+	require.NoError(t, err, "insert scim token")
+	return token
+}
+
 func GroupMember(t testing.TB, db database.Store, orig database.GroupMember) database.GroupMember {
 	member := database.GroupMember{
 		UserID:  takeFirst(orig.UserID, uuid.New()),
@@ -404,18 +452,19 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 	}
 
 	job, err := db.InsertProvisionerJob(genCtx, database.InsertProvisionerJobParams{
-		ID:             jobID,
-		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-		OrganizationID: takeFirst(orig.OrganizationID, defOrgID, uuid.New()),
-		InitiatorID:    takeFirst(orig.InitiatorID, uuid.New()),
-		Provisioner:    takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
-		StorageMethod:  takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
-		FileID:         takeFirst(orig.FileID, uuid.New()),
-		Type:           takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
-		Input:          takeFirstSlice(orig.Input, []byte("{}")),
-		Tags:           orig.Tags,
-		TraceMetadata:  pqtype.NullRawMessage{},
+		ID:                      jobID,
+		CreatedAt:               takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:               takeFirst(orig.UpdatedAt, dbtime.Now()),
+		OrganizationID:          takeFirst(orig.OrganizationID, defOrgID, uuid.New()),
+		InitiatorID:             takeFirst(orig.InitiatorID, uuid.New()),
+		Provisioner:             takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
+		StorageMethod:           takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
+		FileID:                  takeFirst(orig.FileID, uuid.New()),
+		Type:                    takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
+		Input:                   takeFirstSlice(orig.Input, []byte("{}")),
+		Tags:                    orig.Tags,
+		TraceMetadata:           pqtype.NullRawMessage{},
+		EngineVersionConstraint: orig.EngineVersionConstraint,
 	})
 	require.NoError(t, err, "insert job")
 	if ps != nil {
@@ -612,6 +661,37 @@ func UserLink(t testing.TB, db database.Store, orig database.UserLink) database.
 	return link
 }
 
+func UserLoginLockout(t testing.TB, db database.Store, orig database.UserLoginLockout) database.UserLoginLockout {
+	lockout, err := db.UpsertUserLoginLockout(genCtx, database.UpsertUserLoginLockoutParams{
+		UserID:         takeFirst(orig.UserID, uuid.New()),
+		FailedAttempts: takeFirst(orig.FailedAttempts, 10),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		LockedUntil:    takeFirst(orig.LockedUntil, dbtime.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err, "insert user login lockout")
+	return lockout
+}
+
+func UserTOTP(t testing.TB, db database.Store, orig database.UserTOTP) database.UserTOTP {
+	totp, err := db.UpsertUserTOTP(genCtx, database.UpsertUserTOTPParams{
+		UserID:    takeFirst(orig.UserID, uuid.New()),
+		Secret:    takeFirst(orig.Secret, "JBSWY3DPEHPK3PXP"),
+		CreatedAt: takeFirst(orig.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert user totp")
+	if !orig.EnabledAt.Valid {
+		return totp
+	}
+	totp, err = db.EnableUserTOTP(genCtx, database.EnableUserTOTPParams{
+		EnabledAt:           orig.EnabledAt,
+		LastUsedStep:        orig.LastUsedStep,
+		HashedRecoveryCodes: takeFirstSlice(orig.HashedRecoveryCodes, []string{}),
+		UserID:              totp.UserID,
+	})
+	require.NoError(t, err, "enable user totp")
+	return totp
+}
+
 func ExternalAuthLink(t testing.TB, db database.Store, orig database.ExternalAuthLink) database.ExternalAuthLink {
 	msg := takeFirst(&orig.OAuthExtra, &pqtype.NullRawMessage{})
 	link, err := db.InsertExternalAuthLink(genCtx, database.InsertExternalAuthLinkParams{
@@ -703,6 +783,20 @@ func TemplateVersionParameter(t testing.TB, db database.Store, orig database.Tem
 	return version
 }
 
+func TemplateVersionPreset(t testing.TB, db database.Store, orig database.TemplateVersionPreset) database.TemplateVersionPreset {
+	t.Helper()
+
+	preset, err := db.InsertTemplateVersionPreset(genCtx, database.InsertTemplateVersionPresetParams{
+		ID:                       takeFirst(orig.ID, uuid.New()),
+		TemplateVersionID:        takeFirst(orig.TemplateVersionID, uuid.New()),
+		Name:                     takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		CreatedAt:                takeFirst(orig.CreatedAt, dbtime.Now()),
+		DesiredPrebuildInstances: orig.DesiredPrebuildInstances,
+	})
+	require.NoError(t, err, "insert template version preset")
+	return preset
+}
+
 func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.WorkspaceAgentStat) database.WorkspaceAgentStat {
 	if orig.ConnectionsByProto == nil {
 		orig.ConnectionsByProto = json.RawMessage([]byte("{}"))
@@ -784,6 +878,86 @@ func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth
 	return token
 }
 
+func Webhook(t testing.TB, db database.Store, seed database.Webhook) database.Webhook {
+	webhook, err := db.InsertWebhook(genCtx, database.InsertWebhookParams{
+		ID:        takeFirst(seed.ID, uuid.New()),
+		Name:      takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		Url:       takeFirst(seed.Url, "http://localhost/webhook"),
+		Events:    takeFirstSlice(seed.Events, []string{}),
+		Secret:    takeFirst(seed.Secret, ""),
+		Enabled:   true,
+		CreatedAt: takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func WebhookDelivery(t testing.TB, db database.Store, seed database.WebhookDelivery) database.WebhookDelivery {
+	delivery, err := db.InsertWebhookDelivery(genCtx, database.InsertWebhookDeliveryParams{
+		ID:        takeFirst(seed.ID, uuid.New()),
+		WebhookID: takeFirst(seed.WebhookID, uuid.New()),
+		Event:     takeFirst(seed.Event, "workspace.created"),
+		Payload:   takeFirstSlice(seed.Payload, []byte("{}")),
+		CreatedAt: takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert webhook delivery")
+	return delivery
+}
+
+func WorkspaceBulkOperation(t testing.TB, db database.Store, seed database.WorkspaceBulkOperation) database.WorkspaceBulkOperation {
+	operation, err := db.InsertWorkspaceBulkOperation(genCtx, database.InsertWorkspaceBulkOperationParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		InitiatorID: takeFirst(seed.InitiatorID, uuid.New()),
+		Action:      takeFirst(seed.Action, database.WorkspaceBulkActionStart),
+		Query:       takeFirst(seed.Query, "owner:me"),
+		Concurrency: takeFirst(seed.Concurrency, 10),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace bulk operation")
+	return operation
+}
+
+func TemplateReleaseChannel(t testing.TB, db database.Store, seed database.TemplateReleaseChannel) database.TemplateReleaseChannel {
+	// The columns are not nullable.
+	if seed.UserIDs == nil {
+		seed.UserIDs = []uuid.UUID{}
+	}
+	if seed.GroupIDs == nil {
+		seed.GroupIDs = []uuid.UUID{}
+	}
+	channel, err := db.InsertTemplateReleaseChannel(genCtx, database.InsertTemplateReleaseChannelParams{
+		ID:                takeFirst(seed.ID, uuid.New()),
+		TemplateID:        takeFirst(seed.TemplateID, uuid.New()),
+		Name:              takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		TemplateVersionID: takeFirst(seed.TemplateVersionID, uuid.New()),
+		UserIDs:           seed.UserIDs,
+		GroupIDs:          seed.GroupIDs,
+		CreatedAt:         takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert template release channel")
+	return channel
+}
+
+func WorkspaceSnapshot(t testing.TB, db database.Store, seed database.WorkspaceSnapshot) database.WorkspaceSnapshot {
+	snapshot, err := db.InsertWorkspaceSnapshot(genCtx, database.InsertWorkspaceSnapshotParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		WorkspaceID: takeFirst(seed.WorkspaceID, uuid.New()),
+		Name:        takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		BuildID:     takeFirst(seed.BuildID, uuid.New()),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert workspace snapshot")
+	if len(seed.Artifacts) > 0 {
+		err = db.UpdateWorkspaceSnapshotArtifactsByID(genCtx, database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+			ID:        snapshot.ID,
+			Artifacts: seed.Artifacts,
+		})
+		require.NoError(t, err, "update workspace snapshot artifacts")
+		snapshot.Artifacts = seed.Artifacts
+	}
+	return snapshot
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
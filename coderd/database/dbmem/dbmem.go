@@ -3,7 +3,9 @@ package dbmem
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -140,45 +142,64 @@ type data struct {
 	userLinks           []database.UserLink
 
 	// New tables
-	workspaceAgentStats           []database.WorkspaceAgentStat
-	auditLogs                     []database.AuditLog
-	dbcryptKeys                   []database.DBCryptKey
-	files                         []database.File
-	externalAuthLinks             []database.ExternalAuthLink
-	gitSSHKey                     []database.GitSSHKey
-	groupMembers                  []database.GroupMember
-	groups                        []database.Group
-	jfrogXRayScans                []database.JfrogXrayScan
-	licenses                      []database.License
-	oauth2ProviderApps            []database.OAuth2ProviderApp
-	oauth2ProviderAppSecrets      []database.OAuth2ProviderAppSecret
-	oauth2ProviderAppCodes        []database.OAuth2ProviderAppCode
-	oauth2ProviderAppTokens       []database.OAuth2ProviderAppToken
-	parameterSchemas              []database.ParameterSchema
-	provisionerDaemons            []database.ProvisionerDaemon
-	provisionerJobLogs            []database.ProvisionerJobLog
-	provisionerJobs               []database.ProvisionerJob
-	replicas                      []database.Replica
-	templateVersions              []database.TemplateVersionTable
-	templateVersionParameters     []database.TemplateVersionParameter
-	templateVersionVariables      []database.TemplateVersionVariable
-	templates                     []database.TemplateTable
-	templateUsageStats            []database.TemplateUsageStat
-	workspaceAgents               []database.WorkspaceAgent
-	workspaceAgentMetadata        []database.WorkspaceAgentMetadatum
-	workspaceAgentLogs            []database.WorkspaceAgentLog
-	workspaceAgentLogSources      []database.WorkspaceAgentLogSource
-	workspaceAgentScripts         []database.WorkspaceAgentScript
-	workspaceAgentPortShares      []database.WorkspaceAgentPortShare
-	workspaceApps                 []database.WorkspaceApp
-	workspaceAppStatsLastInsertID int64
-	workspaceAppStats             []database.WorkspaceAppStat
-	workspaceBuilds               []database.WorkspaceBuild
-	workspaceBuildParameters      []database.WorkspaceBuildParameter
-	workspaceResourceMetadata     []database.WorkspaceResourceMetadatum
-	workspaceResources            []database.WorkspaceResource
-	workspaces                    []database.Workspace
-	workspaceProxies              []database.WorkspaceProxy
+	workspaceAgentStats             []database.WorkspaceAgentStat
+	auditLogs                       []database.AuditLog
+	customRoles                     []database.CustomRole
+	dbcryptKeys                     []database.DBCryptKey
+	files                           []database.File
+	externalAuthLinks               []database.ExternalAuthLink
+	gitSSHKey                       []database.GitSSHKey
+	groupMembers                    []database.GroupMember
+	groups                          []database.Group
+	jfrogXRayScans                  []database.JfrogXrayScan
+	licenses                        []database.License
+	notificationMessages            []database.NotificationMessage
+	notificationPreferences         []database.NotificationPreference
+	oauth2ProviderApps              []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets        []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes          []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens         []database.OAuth2ProviderAppToken
+	oidcSyncRules                   []database.OIDCSyncRule
+	scimTokens                      []database.SCIMToken
+	parameterSchemas                []database.ParameterSchema
+	provisionerDaemons              []database.ProvisionerDaemon
+	provisionerJobLogs              []database.ProvisionerJobLog
+	provisionerJobs                 []database.ProvisionerJob
+	replicas                        []database.Replica
+	templateVersions                []database.TemplateVersionTable
+	templateVersionDiagnostics      []database.TemplateVersionDiagnostic
+	templateVersionParameters       []database.TemplateVersionParameter
+	templateVersionPresets          []database.TemplateVersionPreset
+	templateVersionPresetParameters []database.TemplateVersionPresetParameter
+	templateVersionVariables        []database.TemplateVersionVariable
+	templates                       []database.TemplateTable
+	templateReleaseChannels         []database.TemplateReleaseChannel
+	templateUsageStats              []database.TemplateUsageStat
+	userLoginAttempts               []database.UserLoginAttempt
+	userLoginLockouts               []database.UserLoginLockout
+	userTOTPs                       []database.UserTOTP
+	webhooks                        []database.Webhook
+	webhookDeliveries               []database.WebhookDelivery
+	workspaceAgents                 []database.WorkspaceAgent
+	workspaceAgentMetadata          []database.WorkspaceAgentMetadatum
+	workspaceAgentLogs              []database.WorkspaceAgentLog
+	workspaceAgentLogSources        []database.WorkspaceAgentLogSource
+	workspaceAgentScripts           []database.WorkspaceAgentScript
+	workspaceAgentPortShares        []database.WorkspaceAgentPortShare
+	workspaceApps                   []database.WorkspaceApp
+	workspaceAppStatsLastInsertID   int64
+	workspaceAppStats               []database.WorkspaceAppStat
+	workspaceBuilds                 []database.WorkspaceBuild
+	workspaceBuildParameters        []database.WorkspaceBuildParameter
+	workspaceResourceMetadata       []database.WorkspaceResourceMetadatum
+	workspaceResources              []database.WorkspaceResource
+	workspaces                      []database.Workspace
+	workspacePrebuilds              []database.WorkspacePrebuild
+	workspaceBulkOperations         []database.WorkspaceBulkOperation
+	workspaceBulkOperationResults   []database.WorkspaceBulkOperationWorkspace
+	workspaceSnapshots              []database.WorkspaceSnapshot
+	workspaceSnapshotRestores       []database.WorkspaceSnapshotRestore
+	workspaceProxies                []database.WorkspaceProxy
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
 	locks                   map[int64]struct{}
@@ -311,19 +332,20 @@ func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 	rows := make([]database.GetUsersRow, len(users))
 	for i, u := range users {
 		rows[i] = database.GetUsersRow{
-			ID:             u.ID,
-			Email:          u.Email,
-			Username:       u.Username,
-			HashedPassword: u.HashedPassword,
-			CreatedAt:      u.CreatedAt,
-			UpdatedAt:      u.UpdatedAt,
-			Status:         u.Status,
-			RBACRoles:      u.RBACRoles,
-			LoginType:      u.LoginType,
-			AvatarURL:      u.AvatarURL,
-			Deleted:        u.Deleted,
-			LastSeenAt:     u.LastSeenAt,
-			Count:          count,
+			ID:               u.ID,
+			Email:            u.Email,
+			Username:         u.Username,
+			HashedPassword:   u.HashedPassword,
+			CreatedAt:        u.CreatedAt,
+			UpdatedAt:        u.UpdatedAt,
+			Status:           u.Status,
+			RBACRoles:        u.RBACRoles,
+			LoginType:        u.LoginType,
+			AvatarURL:        u.AvatarURL,
+			Deleted:          u.Deleted,
+			LastSeenAt:       u.LastSeenAt,
+			IsServiceAccount: u.IsServiceAccount,
+			Count:            count,
 		}
 	}
 
@@ -436,6 +458,67 @@ func (q *FakeQuerier) getWorkspaceByIDNoLock(_ context.Context, id uuid.UUID) (d
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+// isWorkspaceSharedWithNoLock reports whether the workspace is shared with the
+// user directly or through one of their groups.
+func (q *FakeQuerier) isWorkspaceSharedWithNoLock(workspace database.Workspace, userID uuid.UUID) bool {
+	if workspace.OwnerID == userID {
+		return false
+	}
+	if _, ok := workspace.UserACL[userID.String()]; ok {
+		return true
+	}
+	for _, member := range q.groupMembers {
+		if _, ok := workspace.GroupACL[member.GroupID.String()]; ok && member.UserID == userID {
+			return true
+		}
+	}
+	for _, member := range q.organizationMembers {
+		if _, ok := workspace.GroupACL[member.OrganizationID.String()]; ok && member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// getTemplateActiveVersionForUserNoLock returns the version of the template
+// the workspaces of the user use, and the name of the release channel it comes
+// from, if any.
+func (q *FakeQuerier) getTemplateActiveVersionForUserNoLock(templateID, activeVersionID, userID uuid.UUID) (uuid.UUID, string) {
+	groupIDs := map[uuid.UUID]bool{}
+	for _, member := range q.groupMembers {
+		if member.UserID == userID {
+			groupIDs[member.GroupID] = true
+		}
+	}
+	for _, member := range q.organizationMembers {
+		if member.UserID == userID {
+			groupIDs[member.OrganizationID] = true
+		}
+	}
+
+	var (
+		found  *database.TemplateReleaseChannel
+		direct bool
+	)
+	for i, channel := range q.templateReleaseChannels {
+		if channel.TemplateID != templateID {
+			continue
+		}
+		channelDirect := slices.Contains(channel.UserIDs, userID)
+		if !channelDirect && !slices.ContainsFunc(channel.GroupIDs, func(id uuid.UUID) bool { return groupIDs[id] }) {
+			continue
+		}
+		if found == nil || (channelDirect && !direct) || (channelDirect == direct && channel.Name < found.Name) {
+			found = &q.templateReleaseChannels[i]
+			direct = channelDirect
+		}
+	}
+	if found == nil {
+		return activeVersionID, ""
+	}
+	return found.TemplateVersionID, found.Name
+}
+
 func (q *FakeQuerier) getWorkspaceByAgentIDNoLock(_ context.Context, agentID uuid.UUID) (database.Workspace, error) {
 	var agent database.WorkspaceAgent
 	for _, _agent := range q.workspaceAgents {
@@ -687,6 +770,15 @@ func (q *FakeQuerier) getOrganizationMemberNoLock(orgID uuid.UUID) []database.Or
 	return members
 }
 
+func (q *FakeQuerier) isOrganizationMemberNoLock(userID, orgID uuid.UUID) bool {
+	for _, member := range q.organizationMembers {
+		if member.UserID == userID && member.OrganizationID == orgID {
+			return true
+		}
+	}
+	return false
+}
+
 // getEveryoneGroupMembersNoLock fetches all the users in an organization.
 func (q *FakeQuerier) getEveryoneGroupMembersNoLock(orgID uuid.UUID) []database.User {
 	var (
@@ -898,6 +990,51 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *FakeQuerier) AcquireNotificationMessages(_ context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var acquirable []int
+	for i, msg := range q.notificationMessages {
+		if msg.Method != arg.Method || msg.AttemptCount >= arg.MaxAttemptCount {
+			continue
+		}
+		switch msg.Status {
+		case database.NotificationMessageStatusPending:
+		case database.NotificationMessageStatusTemporaryFailure:
+			if !msg.NextRetryAfter.Valid || msg.NextRetryAfter.Time.After(arg.Now) {
+				continue
+			}
+		case database.NotificationMessageStatusLeased:
+			if !msg.LeasedUntil.Valid || msg.LeasedUntil.Time.After(arg.Now) {
+				continue
+			}
+		default:
+			continue
+		}
+		acquirable = append(acquirable, i)
+	}
+	sort.SliceStable(acquirable, func(i, j int) bool {
+		return q.notificationMessages[acquirable[i]].CreatedAt.Before(q.notificationMessages[acquirable[j]].CreatedAt)
+	})
+
+	acquired := make([]database.NotificationMessage, 0)
+	for _, i := range acquirable {
+		if len(acquired) >= int(arg.Count) {
+			break
+		}
+		q.notificationMessages[i].Status = database.NotificationMessageStatusLeased
+		q.notificationMessages[i].UpdatedAt = arg.Now
+		q.notificationMessages[i].LeasedUntil = arg.LeasedUntil
+		acquired = append(acquired, q.notificationMessages[i])
+	}
+	return acquired, nil
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -943,6 +1080,10 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !tagsSubset(provisionerJob.Tags, tags) {
 			continue
 		}
+		if provisionerJob.EngineVersionConstraint != "" &&
+			!slices.Contains(arg.EngineVersionConstraints, string(provisionerJob.Provisioner)+":"+provisionerJob.EngineVersionConstraint) {
+			continue
+		}
 		provisionerJob.StartedAt = arg.StartedAt
 		provisionerJob.UpdatedAt = arg.StartedAt.Time
 		provisionerJob.WorkerID = arg.WorkerID
@@ -957,6 +1098,51 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) AcquireWebhookDeliveries(_ context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var acquirable []int
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.AttemptCount >= arg.MaxAttemptCount {
+			continue
+		}
+		switch delivery.Status {
+		case database.WebhookDeliveryStatusPending:
+		case database.WebhookDeliveryStatusTemporaryFailure:
+			if !delivery.NextAttemptAfter.Valid || delivery.NextAttemptAfter.Time.After(arg.Now) {
+				continue
+			}
+		case database.WebhookDeliveryStatusLeased:
+			if !delivery.LeasedUntil.Valid || delivery.LeasedUntil.Time.After(arg.Now) {
+				continue
+			}
+		default:
+			continue
+		}
+		acquirable = append(acquirable, i)
+	}
+	sort.SliceStable(acquirable, func(i, j int) bool {
+		return q.webhookDeliveries[acquirable[i]].CreatedAt.Before(q.webhookDeliveries[acquirable[j]].CreatedAt)
+	})
+
+	acquired := make([]database.WebhookDelivery, 0)
+	for _, i := range acquirable {
+		if len(acquired) >= int(arg.Count) {
+			break
+		}
+		q.webhookDeliveries[i].Status = database.WebhookDeliveryStatusLeased
+		q.webhookDeliveries[i].UpdatedAt = arg.Now
+		q.webhookDeliveries[i].LeasedUntil = arg.LeasedUntil
+		acquired = append(acquired, q.webhookDeliveries[i])
+	}
+	return acquired, nil
+}
+
 func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1091,6 +1277,9 @@ func (q *FakeQuerier) ArchiveUnusedTemplateVersions(_ context.Context, arg datab
 	for _, tpl := range q.templates {
 		usedVersions[tpl.ActiveVersionID] = true
 	}
+	for _, channel := range q.templateReleaseChannels {
+		usedVersions[channel.TemplateVersionID] = true
+	}
 
 	var archived []uuid.UUID
 	for i, v := range q.templateVersions {
@@ -1160,6 +1349,72 @@ func (q *FakeQuerier) BatchUpdateWorkspaceLastUsedAt(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) ClaimWorkspacePrebuild(ctx context.Context, arg database.ClaimWorkspacePrebuildParams) (database.Workspace, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var claimed *database.WorkspacePrebuild
+	for i, prebuild := range q.workspacePrebuilds {
+		if prebuild.TemplateVersionPresetID != arg.TemplateVersionPresetID {
+			continue
+		}
+		if claimed != nil && !prebuild.CreatedAt.Before(claimed.CreatedAt) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, prebuild.WorkspaceID)
+		if err != nil || workspace.Deleted || workspace.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil || build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil || job.JobStatus != database.ProvisionerJobStatusSucceeded {
+			continue
+		}
+		claimed = &q.workspacePrebuilds[i]
+	}
+	if claimed == nil {
+		return database.Workspace{}, sql.ErrNoRows
+	}
+
+	for i, workspace := range q.workspaces {
+		if workspace.ID != claimed.WorkspaceID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.OwnerID {
+				continue
+			}
+			if other.Name == arg.Name {
+				return database.Workspace{}, errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.OwnerID
+		workspace.Name = arg.Name
+		workspace.AutostartSchedule = arg.AutostartSchedule
+		workspace.Ttl = arg.Ttl
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		workspace.LastUsedAt = arg.Now
+		workspace.UpdatedAt = arg.Now
+		q.workspaces[i] = workspace
+
+		workspaceID := claimed.WorkspaceID
+		q.workspacePrebuilds = slices.DeleteFunc(q.workspacePrebuilds, func(prebuild database.WorkspacePrebuild) bool {
+			return prebuild.WorkspaceID == workspaceID
+		})
+		return workspace, nil
+	}
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) CleanTailnetCoordinators(_ context.Context) error {
 	return ErrUnimplemented
 }
@@ -1235,6 +1490,38 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteCustomRole(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != id {
+			continue
+		}
+		q.customRoles = slices.Delete(q.customRoles, i, i+1)
+
+		if !role.OrganizationID.Valid {
+			for j, user := range q.users {
+				q.users[j].RBACRoles = slices.DeleteFunc(slices.Clone(user.RBACRoles), func(name string) bool {
+					return name == role.Name
+				})
+			}
+			return nil
+		}
+		assigned := role.Name + ":" + role.OrganizationID.UUID.String()
+		for j, member := range q.organizationMembers {
+			if member.OrganizationID != role.OrganizationID.UUID {
+				continue
+			}
+			q.organizationMembers[j].Roles = slices.DeleteFunc(slices.Clone(member.Roles), func(name string) bool {
+				return name == assigned
+			})
+		}
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) DeleteExternalAuthLink(_ context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1457,6 +1744,34 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Cont
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOIDCSyncRule(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.oidcSyncRules = slices.DeleteFunc(q.oidcSyncRules, func(rule database.OIDCSyncRule) bool {
+		return rule.ID == id
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldNotificationMessages(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	weekAgo := dbtime.Now().Add(-7 * 24 * time.Hour)
+
+	var kept []database.NotificationMessage
+	for _, msg := range q.notificationMessages {
+		final := msg.Status == database.NotificationMessageStatusSent || msg.Status == database.NotificationMessageStatusPermanentFailure
+		if final && msg.UpdatedAt.Before(weekAgo) {
+			continue
+		}
+		kept = append(kept, msg)
+	}
+	q.notificationMessages = kept
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1476,6 +1791,35 @@ func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldUserLoginAttempts(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	cutoff := dbtime.Now().Add(-90 * 24 * time.Hour)
+	q.userLoginAttempts = slices.DeleteFunc(q.userLoginAttempts, func(attempt database.UserLoginAttempt) bool {
+		return attempt.CreatedAt.Before(cutoff)
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWebhookDeliveries(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	weekAgo := dbtime.Now().Add(-7 * 24 * time.Hour)
+
+	var kept []database.WebhookDelivery
+	for _, delivery := range q.webhookDeliveries {
+		final := delivery.Status == database.WebhookDeliveryStatusSucceeded || delivery.Status == database.WebhookDeliveryStatusPermanentFailure
+		if final && delivery.UpdatedAt.Before(weekAgo) {
+			continue
+		}
+		kept = append(kept, delivery)
+	}
+	q.webhookDeliveries = kept
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1521,6 +1865,85 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldWorkspaceBuildProvisionerStates(_ context.Context, keep int32) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// Find the build numbers that should keep their state for each
+	// workspace.
+	buildNumbers := make(map[uuid.UUID][]int32)
+	for _, build := range q.workspaceBuilds {
+		buildNumbers[build.WorkspaceID] = append(buildNumbers[build.WorkspaceID], build.BuildNumber)
+	}
+	minRetained := make(map[uuid.UUID]int32)
+	for workspaceID, numbers := range buildNumbers {
+		slices.SortFunc(numbers, func(a, b int32) int {
+			return slice.Descending(a, b)
+		})
+		if int(keep) < len(numbers) {
+			minRetained[workspaceID] = numbers[keep]
+		}
+	}
+
+	for i, build := range q.workspaceBuilds {
+		cutoff, ok := minRetained[build.WorkspaceID]
+		if !ok || build.BuildNumber > cutoff {
+			continue
+		}
+		build.ProvisionerState = nil
+		q.workspaceBuilds[i] = build
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOrganizationMember(_ context.Context, arg database.DeleteOrganizationMemberParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	orgGroups := make(map[uuid.UUID]struct{})
+	for _, group := range q.groups {
+		if group.OrganizationID == arg.OrganizationID {
+			orgGroups[group.ID] = struct{}{}
+		}
+	}
+	q.groupMembers = slices.DeleteFunc(q.groupMembers, func(member database.GroupMember) bool {
+		_, ok := orgGroups[member.GroupID]
+		return ok && member.UserID == arg.UserID
+	})
+	q.organizationMembers = slices.DeleteFunc(q.organizationMembers, func(member database.OrganizationMember) bool {
+		return member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOtherAPIKeysByUserID(_ context.Context, arg database.DeleteOtherAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deleted := make([]database.APIKey, 0)
+	for i := len(q.apiKeys) - 1; i >= 0; i-- {
+		key := q.apiKeys[i]
+		if key.UserID != arg.UserID || key.ID == arg.ExceptID {
+			continue
+		}
+		if !arg.IncludeTokens && key.LoginType == database.LoginTypeToken {
+			continue
+		}
+		deleted = append(deleted, key)
+		q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
+	}
+	return deleted, nil
+}
+
 func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1534,6 +1957,16 @@ func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time
 	return nil
 }
 
+func (q *FakeQuerier) DeleteSCIMToken(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.scimTokens = slices.DeleteFunc(q.scimTokens, func(token database.SCIMToken) bool {
+		return token.ID == id
+	})
+	return nil
+}
+
 func (*FakeQuerier) DeleteTailnetAgent(context.Context, database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	return database.DeleteTailnetAgentRow{}, ErrUnimplemented
 }
@@ -1564,28 +1997,90 @@ func (*FakeQuerier) DeleteTailnetTunnel(_ context.Context, arg database.DeleteTa
 	return database.DeleteTailnetTunnelRow{}, ErrUnimplemented
 }
 
-func (q *FakeQuerier) DeleteWorkspaceAgentPortShare(_ context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
-	err := validateDatabaseType(arg)
-	if err != nil {
-		return err
-	}
-
+func (q *FakeQuerier) DeleteTemplateReleaseChannelByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for i, share := range q.workspaceAgentPortShares {
-		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
-			q.workspaceAgentPortShares = append(q.workspaceAgentPortShares[:i], q.workspaceAgentPortShares[i+1:]...)
+	for i, channel := range q.templateReleaseChannels {
+		if channel.ID == id {
+			q.templateReleaseChannels = append(q.templateReleaseChannels[:i], q.templateReleaseChannels[i+1:]...)
 			return nil
 		}
 	}
-
 	return nil
 }
 
-func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context, templateID uuid.UUID) error {
-	err := validateDatabaseType(templateID)
-	if err != nil {
+func (q *FakeQuerier) DeleteUserLoginFailures(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userLoginAttempts = slices.DeleteFunc(q.userLoginAttempts, func(attempt database.UserLoginAttempt) bool {
+		return !attempt.Success && attempt.UserID.Valid && attempt.UserID.UUID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserLoginLockoutByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userLoginLockouts = slices.DeleteFunc(q.userLoginLockouts, func(lockout database.UserLoginLockout) bool {
+		return lockout.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserTOTPByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userTOTPs = slices.DeleteFunc(q.userTOTPs, func(totp database.UserTOTP) bool {
+		return totp.UserID == userID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := slices.IndexFunc(q.webhooks, func(webhook database.Webhook) bool {
+		return webhook.ID == id
+	})
+	if index < 0 {
+		return sql.ErrNoRows
+	}
+	q.webhooks = slices.Delete(q.webhooks, index, index+1)
+
+	// Cascade delete the deliveries of the deleted webhook.
+	q.webhookDeliveries = slices.DeleteFunc(q.webhookDeliveries, func(delivery database.WebhookDelivery) bool {
+		return delivery.WebhookID == id
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPortShare(_ context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspaceAgentPortShares = append(q.workspaceAgentPortShares[:i], q.workspaceAgentPortShares[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context, templateID uuid.UUID) error {
+	err := validateDatabaseType(templateID)
+	if err != nil {
 		return err
 	}
 
@@ -1607,6 +2102,81 @@ func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context
 	return nil
 }
 
+func (q *FakeQuerier) DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspacePrebuilds = slices.DeleteFunc(q.workspacePrebuilds, func(prebuild database.WorkspacePrebuild) bool {
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, prebuild.WorkspaceID)
+		return err == nil && workspace.Deleted
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID != id {
+			continue
+		}
+		q.workspaceSnapshots = append(q.workspaceSnapshots[:i], q.workspaceSnapshots[i+1:]...)
+		for j, restore := range q.workspaceSnapshotRestores {
+			if restore.SnapshotID.Valid && restore.SnapshotID.UUID == id {
+				q.workspaceSnapshotRestores[j].SnapshotID = uuid.NullUUID{}
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) EnableUserTOTP(_ context.Context, arg database.EnableUserTOTPParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || totp.EnabledAt.Valid {
+			continue
+		}
+		totp.EnabledAt = arg.EnabledAt
+		totp.LastUsedStep = arg.LastUsedStep
+		totp.HashedRecoveryCodes = slices.Clone(arg.HashedRecoveryCodes)
+		q.userTOTPs[i] = totp
+		return totp, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) EnqueueNotificationMessage(_ context.Context, arg database.EnqueueNotificationMessageParams) (database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationMessage{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	msg := database.NotificationMessage{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Template:  arg.Template,
+		Method:    arg.Method,
+		Status:    database.NotificationMessageStatusPending,
+		Payload:   arg.Payload,
+		CreatedBy: arg.CreatedBy,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.CreatedAt,
+	}
+	q.notificationMessages = append(q.notificationMessages, msg)
+	return msg, nil
+}
+
 func (q *FakeQuerier) FavoriteWorkspace(_ context.Context, arg uuid.UUID) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1696,13 +2266,30 @@ func (q *FakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *FakeQuerier) GetActiveAPIKeysByUserID(_ context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := dbtime.Now()
+	apiKeys := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.UserID == userID && key.ExpiresAt.After(now) {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	slices.SortFunc(apiKeys, func(a, b database.APIKey) int {
+		return b.LastUsed.Compare(a.LastUsed)
+	})
+	return apiKeys, nil
+}
+
 func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
 	active := int64(0)
 	for _, u := range q.users {
-		if u.Status == database.UserStatusActive && !u.Deleted {
+		if u.Status == database.UserStatusActive && !u.Deleted && !u.IsServiceAccount {
 			active++
 		}
 	}
@@ -1796,6 +2383,9 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
 			continue
 		}
+		if arg.OrganizationID != uuid.Nil && alog.OrganizationID != arg.OrganizationID {
+			continue
+		}
 		if arg.Username != "" {
 			user, err := q.getUserByIDNoLock(alog.UserID)
 			if err == nil && !strings.EqualFold(arg.Username, user.Username) {
@@ -1828,27 +2418,38 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 		user, err := q.getUserByIDNoLock(alog.UserID)
 		userValid := err == nil
 
+		var impersonator database.User
+		impersonatorValid := false
+		if alog.ImpersonatorID.Valid {
+			impersonator, err = q.getUserByIDNoLock(alog.ImpersonatorID.UUID)
+			impersonatorValid = err == nil
+		}
+
 		logs = append(logs, database.GetAuditLogsOffsetRow{
-			ID:               alog.ID,
-			RequestID:        alog.RequestID,
-			OrganizationID:   alog.OrganizationID,
-			Ip:               alog.Ip,
-			UserAgent:        alog.UserAgent,
-			ResourceType:     alog.ResourceType,
-			ResourceID:       alog.ResourceID,
-			ResourceTarget:   alog.ResourceTarget,
-			ResourceIcon:     alog.ResourceIcon,
-			Action:           alog.Action,
-			Diff:             alog.Diff,
-			StatusCode:       alog.StatusCode,
-			AdditionalFields: alog.AdditionalFields,
-			UserID:           alog.UserID,
-			UserUsername:     sql.NullString{String: user.Username, Valid: userValid},
-			UserEmail:        sql.NullString{String: user.Email, Valid: userValid},
-			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
-			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
-			UserRoles:        user.RBACRoles,
-			Count:            0,
+			ID:                    alog.ID,
+			RequestID:             alog.RequestID,
+			OrganizationID:        alog.OrganizationID,
+			Ip:                    alog.Ip,
+			UserAgent:             alog.UserAgent,
+			ResourceType:          alog.ResourceType,
+			ResourceID:            alog.ResourceID,
+			ResourceTarget:        alog.ResourceTarget,
+			ResourceIcon:          alog.ResourceIcon,
+			Action:                alog.Action,
+			Diff:                  alog.Diff,
+			StatusCode:            alog.StatusCode,
+			AdditionalFields:      alog.AdditionalFields,
+			UserID:                alog.UserID,
+			UserUsername:          sql.NullString{String: user.Username, Valid: userValid},
+			UserEmail:             sql.NullString{String: user.Email, Valid: userValid},
+			UserCreatedAt:         sql.NullTime{Time: user.CreatedAt, Valid: userValid},
+			UserStatus:            database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
+			UserRoles:             user.RBACRoles,
+			UserIsServiceAccount:  sql.NullBool{Bool: user.IsServiceAccount, Valid: userValid},
+			ImpersonatorID:        alog.ImpersonatorID,
+			ImpersonatorUsername:  sql.NullString{String: impersonator.Username, Valid: impersonatorValid},
+			ImpersonatorAvatarUrl: sql.NullString{String: impersonator.AvatarURL, Valid: impersonatorValid},
+			Count:                 0,
 		})
 
 		if len(logs) >= int(arg.Limit) {
@@ -1907,6 +2508,43 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 	}, nil
 }
 
+func (q *FakeQuerier) GetCustomRoleByName(_ context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID == arg.OrganizationID {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetCustomRoles(_ context.Context) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := slices.Clone(q.customRoles)
+	slices.SortFunc(roles, func(a, b database.CustomRole) int {
+		// Site roles first.
+		if a.OrganizationID.Valid != b.OrganizationID.Valid {
+			if a.OrganizationID.Valid {
+				return 1
+			}
+			return -1
+		}
+		if c := bytes.Compare(a.OrganizationID.UUID[:], b.OrganizationID.UUID[:]); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return roles, nil
+}
+
 func (q *FakeQuerier) GetDBCryptKeys(_ context.Context) ([]database.DBCryptKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2316,6 +2954,23 @@ func (q *FakeQuerier) GetHungProvisionerJobs(_ context.Context, hungSince time.T
 	return hungJobs, nil
 }
 
+func (q *FakeQuerier) GetImpersonationAPIKeysByUserID(_ context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := dbtime.Now()
+	apiKeys := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.UserID == userID && key.ImpersonatorID.Valid && key.ExpiresAt.After(now) {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	slices.SortFunc(apiKeys, func(a, b database.APIKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return apiKeys, nil
+}
+
 func (q *FakeQuerier) GetJFrogXrayScanByWorkspaceAndAgentID(_ context.Context, arg database.GetJFrogXrayScanByWorkspaceAndAgentIDParams) (database.JfrogXrayScan, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2425,6 +3080,39 @@ func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error)
 	return results, nil
 }
 
+func (q *FakeQuerier) GetLoginFailureStats(_ context.Context, arg database.GetLoginFailureStatsParams) (database.GetLoginFailureStatsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetLoginFailureStatsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	row := database.GetLoginFailureStatsRow{
+		LastUserFailure: arg.Since,
+		LastIpFailure:   arg.Since,
+	}
+	for _, attempt := range q.userLoginAttempts {
+		if attempt.Success || !attempt.CreatedAt.After(arg.Since) {
+			continue
+		}
+		if attempt.UserID.Valid && attempt.UserID.UUID == arg.UserID {
+			row.UserFailures++
+			if attempt.CreatedAt.After(row.LastUserFailure) {
+				row.LastUserFailure = attempt.CreatedAt
+			}
+		}
+		if attempt.Ip.IPNet.IP.Equal(arg.Ip.IPNet.IP) {
+			row.IpFailures++
+			if attempt.CreatedAt.After(row.LastIpFailure) {
+				row.LastIpFailure = attempt.CreatedAt
+			}
+		}
+	}
+	return row, nil
+}
+
 func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2436,6 +3124,29 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetNotificationMessagesByStatus(_ context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var out []database.NotificationMessage
+	for _, msg := range q.notificationMessages {
+		if msg.Status == arg.Status {
+			out = append(out, msg)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	if arg.LimitOpt > 0 && len(out) > int(arg.LimitOpt) {
+		out = out[:arg.LimitOpt]
+	}
+	return out, nil
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2589,6 +3300,32 @@ func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	return q.oauthSigningKey, nil
 }
 
+func (q *FakeQuerier) GetOIDCSyncRuleByID(_ context.Context, id uuid.UUID) (database.OIDCSyncRule, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, rule := range q.oidcSyncRules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return database.OIDCSyncRule{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOIDCSyncRules(_ context.Context) ([]database.OIDCSyncRule, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rules := slices.Clone(q.oidcSyncRules)
+	slices.SortFunc(rules, func(a, b database.OIDCSyncRule) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return rules, nil
+}
+
 func (q *FakeQuerier) GetOrganizationByID(_ context.Context, id uuid.UUID) (database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2723,6 +3460,59 @@ func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.U
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetPendingProvisionerJobEngineVersionConstraints(_ context.Context, organizationID uuid.UUID) ([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, 0)
+	for _, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.OrganizationID != organizationID || job.EngineVersionConstraint == "" {
+			continue
+		}
+		row := database.GetPendingProvisionerJobEngineVersionConstraintsRow{
+			Provisioner:             job.Provisioner,
+			EngineVersionConstraint: job.EngineVersionConstraint,
+		}
+		if !slices.Contains(rows, row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetPrebuildPresets(_ context.Context) ([]database.GetPrebuildPresetsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPrebuildPresetsRow, 0)
+	for _, template := range q.templates {
+		if template.Deleted || template.Deprecated != "" {
+			continue
+		}
+		for _, preset := range q.templateVersionPresets {
+			if preset.TemplateVersionID != template.ActiveVersionID || preset.DesiredPrebuildInstances <= 0 {
+				continue
+			}
+			rows = append(rows, database.GetPrebuildPresetsRow{
+				ID:                       preset.ID,
+				TemplateVersionID:        preset.TemplateVersionID,
+				Name:                     preset.Name,
+				CreatedAt:                preset.CreatedAt,
+				DesiredPrebuildInstances: preset.DesiredPrebuildInstances,
+				TemplateID:               template.ID,
+				OrganizationID:           template.OrganizationID,
+			})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].TemplateID != rows[j].TemplateID {
+			return rows[i].TemplateID.String() < rows[j].TemplateID.String()
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetPreviousTemplateVersion(_ context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersion{}, err
@@ -2792,6 +3582,21 @@ func (q *FakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.Provi
 	return out, nil
 }
 
+func (q *FakeQuerier) GetProvisionerDaemonsByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	daemons := make([]database.ProvisionerDaemon, 0)
+	for _, daemon := range q.provisionerDaemons {
+		if daemon.OrganizationID == organizationID {
+			// maps are reference types, so we need to clone them
+			daemon.Tags = maps.Clone(daemon.Tags)
+			daemons = append(daemons, daemon)
+		}
+	}
+	return daemons, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2897,17 +3702,26 @@ func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.
 	return logs, nil
 }
 
-func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UUID) (int64, error) {
+func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, arg database.GetQuotaAllowanceForUserParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
+	if !q.isOrganizationMemberNoLock(arg.UserID, arg.OrganizationID) {
+		return 0, nil
+	}
+
 	var sum int64
 	for _, member := range q.groupMembers {
-		if member.UserID != userID {
+		if member.UserID != arg.UserID {
 			continue
 		}
 		for _, group := range q.groups {
-			if group.ID == member.GroupID {
+			if group.ID == member.GroupID && group.OrganizationID == arg.OrganizationID {
 				sum += int64(group.QuotaAllowance)
 				continue
 			}
@@ -2915,7 +3729,7 @@ func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UU
 	}
 	// Grab the quota for the Everyone group.
 	for _, group := range q.groups {
-		if group.ID == group.OrganizationID {
+		if group.ID == arg.OrganizationID {
 			sum += int64(group.QuotaAllowance)
 			break
 		}
@@ -2923,13 +3737,18 @@ func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UU
 	return sum, nil
 }
 
-func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUID) (int64, error) {
+func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, arg database.GetQuotaConsumedForUserParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
 	var sum int64
 	for _, workspace := range q.workspaces {
-		if workspace.OwnerID != userID {
+		if workspace.OwnerID != arg.OwnerID || workspace.OrganizationID != arg.OrganizationID {
 			continue
 		}
 		if workspace.Deleted {
@@ -2975,22 +3794,73 @@ func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 	return replicas, nil
 }
 
-func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
+func (q *FakeQuerier) GetRunningWorkspaceBulkOperations(_ context.Context) ([]database.WorkspaceBulkOperation, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	if q.serviceBanner == nil {
-		return "", sql.ErrNoRows
+	operations := make([]database.WorkspaceBulkOperation, 0)
+	for _, operation := range q.workspaceBulkOperations {
+		if operation.Status == database.WorkspaceBulkOperationStatusRunning {
+			operations = append(operations, operation)
+		}
 	}
-
-	return string(q.serviceBanner), nil
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.Before(operations[j].CreatedAt)
+	})
+	return operations, nil
 }
 
-func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
-	return nil, ErrUnimplemented
+func (q *FakeQuerier) GetSCIMTokenByID(_ context.Context, id uuid.UUID) (database.SCIMToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.scimTokens {
+		if token.ID == id {
+			return token, nil
+		}
+	}
+	return database.SCIMToken{}, sql.ErrNoRows
 }
 
-func (*FakeQuerier) GetTailnetClientsForAgent(context.Context, uuid.UUID) ([]database.TailnetClient, error) {
+func (q *FakeQuerier) GetSCIMTokenByPrefix(_ context.Context, secretPrefix []byte) (database.SCIMToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.scimTokens {
+		if bytes.Equal(token.SecretPrefix, secretPrefix) {
+			return token, nil
+		}
+	}
+	return database.SCIMToken{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetSCIMTokens(_ context.Context) ([]database.SCIMToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	tokens := slices.Clone(q.scimTokens)
+	slices.SortFunc(tokens, func(a, b database.SCIMToken) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.serviceBanner == nil {
+		return "", sql.ErrNoRows
+	}
+
+	return string(q.serviceBanner), nil
+}
+
+func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
+	return nil, ErrUnimplemented
+}
+
+func (*FakeQuerier) GetTailnetClientsForAgent(context.Context, uuid.UUID) ([]database.TailnetClient, error) {
 	return nil, ErrUnimplemented
 }
 
@@ -3006,6 +3876,50 @@ func (*FakeQuerier) GetTailnetTunnelPeerIDs(context.Context, uuid.UUID) ([]datab
 	return nil, ErrUnimplemented
 }
 
+func (q *FakeQuerier) GetTemplateActiveVersionForUser(_ context.Context, arg database.GetTemplateActiveVersionForUserParams) (database.GetTemplateActiveVersionForUserRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetTemplateActiveVersionForUserRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, template := range q.templates {
+		if template.ID == arg.TemplateID {
+			versionID, channel := q.getTemplateActiveVersionForUserNoLock(template.ID, template.ActiveVersionID, arg.UserID)
+			return database.GetTemplateActiveVersionForUserRow{
+				ActiveVersionID: versionID,
+				ReleaseChannel:  channel,
+			}, nil
+		}
+	}
+	return database.GetTemplateActiveVersionForUserRow{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateActiveVersionsByWorkspaceIDs(_ context.Context, ids []uuid.UUID) ([]database.GetTemplateActiveVersionsByWorkspaceIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := []database.GetTemplateActiveVersionsByWorkspaceIDsRow{}
+	for _, workspace := range q.workspaces {
+		if !slices.Contains(ids, workspace.ID) {
+			continue
+		}
+		for _, template := range q.templates {
+			if template.ID == workspace.TemplateID {
+				versionID, channel := q.getTemplateActiveVersionForUserNoLock(template.ID, template.ActiveVersionID, workspace.OwnerID)
+				rows = append(rows, database.GetTemplateActiveVersionsByWorkspaceIDsRow{
+					WorkspaceID:     workspace.ID,
+					ActiveVersionID: versionID,
+					ReleaseChannel:  channel,
+				})
+			}
+		}
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -3934,6 +4848,51 @@ func (q *FakeQuerier) GetTemplateParameterInsights(ctx context.Context, arg data
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplateReleaseChannelByID(_ context.Context, id uuid.UUID) (database.TemplateReleaseChannel, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, channel := range q.templateReleaseChannels {
+		if channel.ID == id {
+			return channel, nil
+		}
+	}
+	return database.TemplateReleaseChannel{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateReleaseChannelByTemplateIDAndName(_ context.Context, arg database.GetTemplateReleaseChannelByTemplateIDAndNameParams) (database.TemplateReleaseChannel, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == arg.TemplateID && channel.Name == arg.Name {
+			return channel, nil
+		}
+	}
+	return database.TemplateReleaseChannel{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateReleaseChannelsByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	channels := []database.TemplateReleaseChannel{}
+	for _, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == templateID {
+			channels = append(channels, channel)
+		}
+	}
+	slices.SortFunc(channels, func(a, b database.TemplateReleaseChannel) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return channels, nil
+}
+
 func (q *FakeQuerier) GetTemplateUsageStats(_ context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -4002,6 +4961,30 @@ func (q *FakeQuerier) GetTemplateVersionByTemplateIDAndName(_ context.Context, a
 	return database.TemplateVersion{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetTemplateVersionDiagnostics(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionDiagnostic, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	diagnostics := make([]database.TemplateVersionDiagnostic, 0)
+	for _, diagnostic := range q.templateVersionDiagnostics {
+		if diagnostic.TemplateVersionID != templateVersionID {
+			continue
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	sort.Slice(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Code < b.Code
+	})
+	return diagnostics, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionParameter, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4022,6 +5005,72 @@ func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionPresetByID(_ context.Context, id uuid.UUID) (database.TemplateVersionPreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, preset := range q.templateVersionPresets {
+		if preset.ID == id {
+			return preset, nil
+		}
+	}
+	return database.TemplateVersionPreset{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateVersionPresetParameters(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	presetIDs := make(map[uuid.UUID]struct{})
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == templateVersionID {
+			presetIDs[preset.ID] = struct{}{}
+		}
+	}
+	parameters := make([]database.TemplateVersionPresetParameter, 0)
+	for _, parameter := range q.templateVersionPresetParameters {
+		if _, ok := presetIDs[parameter.TemplateVersionPresetID]; ok {
+			parameters = append(parameters, parameter)
+		}
+	}
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return parameters[i].Name < parameters[j].Name
+	})
+	return parameters, nil
+}
+
+func (q *FakeQuerier) GetTemplateVersionPresetParametersByPresetID(_ context.Context, templateVersionPresetID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	parameters := make([]database.TemplateVersionPresetParameter, 0)
+	for _, parameter := range q.templateVersionPresetParameters {
+		if parameter.TemplateVersionPresetID == templateVersionPresetID {
+			parameters = append(parameters, parameter)
+		}
+	}
+	sort.Slice(parameters, func(i, j int) bool {
+		return parameters[i].Name < parameters[j].Name
+	})
+	return parameters, nil
+}
+
+func (q *FakeQuerier) GetTemplateVersionPresets(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	presets := make([]database.TemplateVersionPreset, 0)
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == templateVersionID {
+			presets = append(presets, preset)
+		}
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionVariables(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4482,6 +5531,71 @@ func (q *FakeQuerier) GetUserLinksByUserID(_ context.Context, userID uuid.UUID)
 	return uls, nil
 }
 
+func (q *FakeQuerier) GetUserLoginHistorySummary(_ context.Context, arg database.GetUserLoginHistorySummaryParams) (database.GetUserLoginHistorySummaryRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetUserLoginHistorySummaryRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.GetUserLoginHistorySummaryRow
+	for _, attempt := range q.userLoginAttempts {
+		if !attempt.Success || !attempt.UserID.Valid || attempt.UserID.UUID != arg.UserID {
+			continue
+		}
+		row.HasLogins = true
+		if attempt.Ip.IPNet.IP.Equal(arg.Ip.IPNet.IP) {
+			row.SeenIp = true
+		}
+		if attempt.UserAgent == arg.UserAgent {
+			row.SeenUserAgent = true
+		}
+	}
+	return row, nil
+}
+
+func (q *FakeQuerier) GetUserLoginLockoutByUserID(_ context.Context, userID uuid.UUID) (database.UserLoginLockout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, lockout := range q.userLoginLockouts {
+		if lockout.UserID == userID {
+			return lockout, nil
+		}
+	}
+	return database.UserLoginLockout{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserNotificationPreferences(_ context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var out []database.NotificationPreference
+	for _, pref := range q.notificationPreferences {
+		if pref.UserID == userID {
+			out = append(out, pref)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Template < out[j].Template
+	})
+	return out, nil
+}
+
+func (q *FakeQuerier) GetUserTOTPByUserID(_ context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, totp := range q.userTOTPs {
+		if totp.UserID == userID {
+			return totp, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUserWorkspaceBuildParameters(_ context.Context, params database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4673,6 +5787,64 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetWebhookByID(_ context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhookDeliveriesByWebhookID(_ context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.SortStableFunc(deliveries, func(a, b database.WebhookDelivery) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(deliveries) > int(arg.LimitOpt) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *FakeQuerier) GetWebhookDeliveryByID(_ context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return database.WebhookDelivery{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhooks(_ context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := slices.Clone(q.webhooks)
+	slices.SortFunc(webhooks, func(a, b database.Webhook) int {
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return webhooks, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(_ context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5169,6 +6341,44 @@ func (q *FakeQuerier) GetWorkspaceBuildParameters(_ context.Context, workspaceBu
 	return params, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildStatesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceBuildStatesByWorkspaceIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceBuildStatesByWorkspaceIDRow, 0)
+	for _, build := range q.workspaceBuilds {
+		if build.WorkspaceID != workspaceID {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := database.GetWorkspaceBuildStatesByWorkspaceIDRow{
+			ID:          build.ID,
+			BuildNumber: build.BuildNumber,
+			Transition:  build.Transition,
+			InitiatorID: build.InitiatorID,
+			CreatedAt:   build.CreatedAt,
+			JobStatus:   job.JobStatus,
+			StateSize:   int32(len(build.ProvisionerState)),
+		}
+		if build.ProvisionerState != nil {
+			sum := sha256.Sum256(build.ProvisionerState)
+			row.StateHash = hex.EncodeToString(sum[:])
+		}
+		rows = append(rows, row)
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceBuildStatesByWorkspaceIDRow) int {
+		return slice.Descending(a.BuildNumber, b.BuildNumber)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildsByWorkspaceID(_ context.Context,
 	params database.GetWorkspaceBuildsByWorkspaceIDParams,
 ) ([]database.WorkspaceBuild, error) {
@@ -5244,6 +6454,66 @@ func (q *FakeQuerier) GetWorkspaceBuildsCreatedAfter(_ context.Context, after ti
 	return workspaceBuilds, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBulkOperationByID(_ context.Context, id uuid.UUID) (database.WorkspaceBulkOperation, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, operation := range q.workspaceBulkOperations {
+		if operation.ID == id {
+			return operation, nil
+		}
+	}
+	return database.WorkspaceBulkOperation{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceBulkOperationWorkspaces(ctx context.Context, operationID uuid.UUID) ([]database.GetWorkspaceBulkOperationWorkspacesRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceBulkOperationWorkspacesRow, 0)
+	for _, result := range q.workspaceBulkOperationResults {
+		if result.OperationID != operationID {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, result.WorkspaceID)
+		if err != nil {
+			continue
+		}
+		owner, err := q.getUserByIDNoLock(workspace.OwnerID)
+		if err != nil {
+			continue
+		}
+		row := database.GetWorkspaceBulkOperationWorkspacesRow{
+			OperationID:   result.OperationID,
+			WorkspaceID:   result.WorkspaceID,
+			Status:        result.Status,
+			BuildID:       result.BuildID,
+			Error:         result.Error,
+			UpdatedAt:     result.UpdatedAt,
+			WorkspaceName: workspace.Name,
+			OwnerName:     owner.Username,
+		}
+		if result.BuildID.Valid {
+			build, err := q.getWorkspaceBuildByIDNoLock(ctx, result.BuildID.UUID)
+			if err == nil {
+				job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+				if err == nil {
+					row.JobStatus = database.NullProvisionerJobStatus{ProvisionerJobStatus: job.JobStatus, Valid: true}
+					row.JobError = job.Error
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].OwnerName != rows[j].OwnerName {
+			return rows[i].OwnerName < rows[j].OwnerName
+		}
+		return rows[i].WorkspaceName < rows[j].WorkspaceName
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.GetWorkspaceByAgentIDRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5320,16 +6590,125 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
+func (q *FakeQuerier) GetWorkspaceCountForTemplate(_ context.Context, templateID uuid.UUID) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	cpy := make([]database.WorkspaceProxy, 0, len(q.workspaceProxies))
-
-	for _, p := range q.workspaceProxies {
-		if !p.Deleted {
-			cpy = append(cpy, p)
-		}
+	var count int64
+	for _, workspace := range q.workspaces {
+		if workspace.TemplateID != templateID || workspace.Deleted {
+			continue
+		}
+		if slices.ContainsFunc(q.workspacePrebuilds, func(prebuild database.WorkspacePrebuild) bool {
+			return prebuild.WorkspaceID == workspace.ID
+		}) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceCountForUser(_ context.Context, arg database.GetWorkspaceCountForUserParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, workspace := range q.workspaces {
+		if workspace.OwnerID == arg.OwnerID && workspace.OrganizationID == arg.OrganizationID && !workspace.Deleted {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceLimitForUser(_ context.Context, arg database.GetWorkspaceLimitForUserParams) (int32, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if !q.isOrganizationMemberNoLock(arg.UserID, arg.OrganizationID) {
+		return 0, nil
+	}
+
+	groupIDs := make(map[uuid.UUID]struct{})
+	for _, member := range q.groupMembers {
+		if member.UserID == arg.UserID {
+			groupIDs[member.GroupID] = struct{}{}
+		}
+	}
+
+	var limit int32
+	for _, group := range q.groups {
+		if group.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		_, isMember := groupIDs[group.ID]
+		// The Everyone group applies to all users.
+		if !isMember && group.ID != group.OrganizationID {
+			continue
+		}
+		if group.MaxWorkspacesPerUser > limit {
+			limit = group.MaxWorkspacesPerUser
+		}
+	}
+	return limit, nil
+}
+
+func (q *FakeQuerier) GetWorkspacePrebuilds(ctx context.Context) ([]database.GetWorkspacePrebuildsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspacePrebuildsRow, 0)
+	for _, prebuild := range q.workspacePrebuilds {
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, prebuild.WorkspaceID)
+		if err != nil || workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspacePrebuildsRow{
+			WorkspaceID:             prebuild.WorkspaceID,
+			TemplateVersionPresetID: prebuild.TemplateVersionPresetID,
+			CreatedAt:               prebuild.CreatedAt,
+			OrganizationID:          workspace.OrganizationID,
+			TemplateVersionID:       build.TemplateVersionID,
+			Transition:              build.Transition,
+			JobStatus:               job.JobStatus,
+			CompletedAt:             job.CompletedAt,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].CreatedAt.Before(rows[j].CreatedAt)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	cpy := make([]database.WorkspaceProxy, 0, len(q.workspaceProxies))
+
+	for _, p := range q.workspaceProxies {
+		if !p.Deleted {
+			cpy = append(cpy, p)
+		}
 	}
 	return cpy, nil
 }
@@ -5484,6 +6863,75 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSnapshotByBuildID(_ context.Context, buildID uuid.UUID) (database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.BuildID == buildID {
+			return snapshot, nil
+		}
+	}
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotByWorkspaceIDAndName(_ context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == arg.WorkspaceID && snapshot.Name == arg.Name {
+			return snapshot, nil
+		}
+	}
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotRestoreByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceSnapshotRestore, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, restore := range q.workspaceSnapshotRestores {
+		if restore.WorkspaceID == workspaceID {
+			return restore, nil
+		}
+	}
+	return database.WorkspaceSnapshotRestore{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	snapshots := make([]database.WorkspaceSnapshot, 0)
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == workspaceID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5608,6 +7056,8 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
 		TokenName:       arg.TokenName,
+		ImpersonatorID:  arg.ImpersonatorID,
+		UserAgent:       arg.UserAgent,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -5647,6 +7097,35 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID == arg.OrganizationID {
+			return database.CustomRole{}, errDuplicateKey
+		}
+	}
+
+	role := database.CustomRole{
+		ID:              arg.ID,
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.CreatedAt,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
 func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBCryptKeyParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5765,13 +7244,14 @@ func (q *FakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupPar
 
 	//nolint:gosimple
 	group := database.Group{
-		ID:             arg.ID,
-		Name:           arg.Name,
-		DisplayName:    arg.DisplayName,
-		OrganizationID: arg.OrganizationID,
-		AvatarURL:      arg.AvatarURL,
-		QuotaAllowance: arg.QuotaAllowance,
-		Source:         database.GroupSourceUser,
+		ID:                   arg.ID,
+		Name:                 arg.Name,
+		DisplayName:          arg.DisplayName,
+		OrganizationID:       arg.OrganizationID,
+		AvatarURL:            arg.AvatarURL,
+		QuotaAllowance:       arg.QuotaAllowance,
+		Source:               database.GroupSourceUser,
+		MaxWorkspacesPerUser: arg.MaxWorkspacesPerUser,
 	}
 
 	q.groups = append(q.groups, group)
@@ -5976,6 +7456,29 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg databa
 	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) InsertOIDCSyncRule(_ context.Context, arg database.InsertOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OIDCSyncRule{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	rule := database.OIDCSyncRule{
+		ID:                arg.ID,
+		Claim:             arg.Claim,
+		Pattern:           arg.Pattern,
+		SiteRoles:         arg.SiteRoles,
+		OrganizationID:    arg.OrganizationID,
+		OrganizationRoles: arg.OrganizationRoles,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.CreatedAt,
+	}
+	q.oidcSyncRules = append(q.oidcSyncRules, rule)
+	return rule, nil
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -6003,6 +7506,13 @@ func (q *FakeQuerier) InsertOrganizationMember(_ context.Context, arg database.I
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	for _, member := range q.organizationMembers {
+		if member.OrganizationID == arg.OrganizationID &&
+			member.UserID == arg.UserID {
+			return database.OrganizationMember{}, errDuplicateKey
+		}
+	}
+
 	//nolint:gosimple
 	organizationMember := database.OrganizationMember{
 		OrganizationID: arg.OrganizationID,
@@ -6024,18 +7534,19 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 	defer q.mutex.Unlock()
 
 	job := database.ProvisionerJob{
-		ID:             arg.ID,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		OrganizationID: arg.OrganizationID,
-		InitiatorID:    arg.InitiatorID,
-		Provisioner:    arg.Provisioner,
-		StorageMethod:  arg.StorageMethod,
-		FileID:         arg.FileID,
-		Type:           arg.Type,
-		Input:          arg.Input,
-		Tags:           maps.Clone(arg.Tags),
-		TraceMetadata:  arg.TraceMetadata,
+		ID:                      arg.ID,
+		CreatedAt:               arg.CreatedAt,
+		UpdatedAt:               arg.UpdatedAt,
+		OrganizationID:          arg.OrganizationID,
+		InitiatorID:             arg.InitiatorID,
+		Provisioner:             arg.Provisioner,
+		StorageMethod:           arg.StorageMethod,
+		FileID:                  arg.FileID,
+		Type:                    arg.Type,
+		Input:                   arg.Input,
+		Tags:                    maps.Clone(arg.Tags),
+		TraceMetadata:           arg.TraceMetadata,
+		EngineVersionConstraint: arg.EngineVersionConstraint,
 	}
 	job.JobStatus = provisonerJobStatus(job)
 	q.provisionerJobs = append(q.provisionerJobs, job)
@@ -6095,6 +7606,34 @@ func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *FakeQuerier) InsertSCIMToken(_ context.Context, arg database.InsertSCIMTokenParams) (database.SCIMToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.SCIMToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.scimTokens {
+		if token.Name == arg.Name || bytes.Equal(token.SecretPrefix, arg.SecretPrefix) {
+			return database.SCIMToken{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	token := database.SCIMToken{
+		ID:            arg.ID,
+		Name:          arg.Name,
+		CreatedAt:     arg.CreatedAt,
+		SecretPrefix:  arg.SecretPrefix,
+		HashedSecret:  arg.HashedSecret,
+		DisplaySecret: arg.DisplaySecret,
+	}
+	q.scimTokens = append(q.scimTokens, token)
+	return token, nil
+}
+
 func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTemplateParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6127,6 +7666,35 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 	return nil
 }
 
+func (q *FakeQuerier) InsertTemplateReleaseChannel(_ context.Context, arg database.InsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, channel := range q.templateReleaseChannels {
+		if channel.TemplateID == arg.TemplateID && channel.Name == arg.Name {
+			return database.TemplateReleaseChannel{}, errDuplicateKey
+		}
+	}
+	//nolint:gosimple // Columns are added to the table over time.
+	channel := database.TemplateReleaseChannel{
+		ID:                arg.ID,
+		TemplateID:        arg.TemplateID,
+		Name:              arg.Name,
+		TemplateVersionID: arg.TemplateVersionID,
+		UserIDs:           arg.UserIDs,
+		GroupIDs:          arg.GroupIDs,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.CreatedAt,
+	}
+	q.templateReleaseChannels = append(q.templateReleaseChannels, channel)
+	return channel, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.InsertTemplateVersionParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6156,6 +7724,29 @@ func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.Inse
 	return nil
 }
 
+func (q *FakeQuerier) InsertTemplateVersionDiagnostic(_ context.Context, arg database.InsertTemplateVersionDiagnosticParams) (database.TemplateVersionDiagnostic, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateVersionDiagnostic{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	diagnostic := database.TemplateVersionDiagnostic{
+		ID:                arg.ID,
+		TemplateVersionID: arg.TemplateVersionID,
+		Severity:          arg.Severity,
+		Code:              arg.Code,
+		Summary:           arg.Summary,
+		Detail:            arg.Detail,
+		Filename:          arg.Filename,
+		Line:              arg.Line,
+	}
+	q.templateVersionDiagnostics = append(q.templateVersionDiagnostics, diagnostic)
+	return diagnostic, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionParameter{}, err
@@ -6188,6 +7779,52 @@ func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 	return param, nil
 }
 
+func (q *FakeQuerier) InsertTemplateVersionPreset(_ context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == arg.TemplateVersionID && preset.Name == arg.Name {
+			return database.TemplateVersionPreset{}, errDuplicateKey
+		}
+	}
+	preset := database.TemplateVersionPreset{
+		ID:                       arg.ID,
+		TemplateVersionID:        arg.TemplateVersionID,
+		Name:                     arg.Name,
+		CreatedAt:                arg.CreatedAt,
+		DesiredPrebuildInstances: arg.DesiredPrebuildInstances,
+	}
+	q.templateVersionPresets = append(q.templateVersionPresets, preset)
+	return preset, nil
+}
+
+func (q *FakeQuerier) InsertTemplateVersionPresetParameters(_ context.Context, arg database.InsertTemplateVersionPresetParametersParams) ([]database.TemplateVersionPresetParameter, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	parameters := make([]database.TemplateVersionPresetParameter, 0, len(arg.Names))
+	for index, name := range arg.Names {
+		parameters = append(parameters, database.TemplateVersionPresetParameter{
+			TemplateVersionPresetID: arg.TemplateVersionPresetID,
+			Name:                    name,
+			Value:                   arg.Values[index],
+		})
+	}
+	q.templateVersionPresetParameters = append(q.templateVersionPresetParameters, parameters...)
+	return parameters, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -6241,15 +7878,16 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 	}
 
 	user := database.User{
-		ID:             arg.ID,
-		Email:          arg.Email,
-		HashedPassword: arg.HashedPassword,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		Username:       arg.Username,
-		Status:         database.UserStatusDormant,
-		RBACRoles:      arg.RBACRoles,
-		LoginType:      arg.LoginType,
+		ID:               arg.ID,
+		Email:            arg.Email,
+		HashedPassword:   arg.HashedPassword,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		Username:         arg.Username,
+		Status:           database.UserStatusDormant,
+		RBACRoles:        arg.RBACRoles,
+		LoginType:        arg.LoginType,
+		IsServiceAccount: arg.IsServiceAccount,
 	}
 	q.users = append(q.users, user)
 	return user, nil
@@ -6261,6 +7899,9 @@ func (q *FakeQuerier) InsertUserGroupsByName(_ context.Context, arg database.Ins
 
 	var groupIDs []uuid.UUID
 	for _, group := range q.groups {
+		if group.OrganizationID != arg.OrganizationID || group.Source == database.GroupSourceScim {
+			continue
+		}
 		for _, groupName := range arg.GroupNames {
 			if group.Name == groupName {
 				groupIDs = append(groupIDs, group.ID)
@@ -6304,6 +7945,76 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertUserLoginAttempt(_ context.Context, arg database.InsertUserLoginAttemptParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	q.userLoginAttempts = append(q.userLoginAttempts, database.UserLoginAttempt{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Ip:        arg.Ip,
+		UserAgent: arg.UserAgent,
+		Success:   arg.Success,
+		CreatedAt: arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertWebhook(_ context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	webhook := database.Webhook{
+		ID:        arg.ID,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		Events:    arg.Events,
+		Secret:    arg.Secret,
+		Enabled:   arg.Enabled,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.CreatedAt,
+	}
+	q.webhooks = append(q.webhooks, webhook)
+	return webhook, nil
+}
+
+func (q *FakeQuerier) InsertWebhookDelivery(_ context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	delivery := database.WebhookDelivery{
+		ID:        arg.ID,
+		WebhookID: arg.WebhookID,
+		Event:     arg.Event,
+		Payload:   arg.Payload,
+		Status:    database.WebhookDeliveryStatusPending,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.CreatedAt,
+	}
+	q.webhookDeliveries = append(q.webhookDeliveries, delivery)
+	return delivery, nil
+}
+
 func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -6325,6 +8036,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
 		AutomaticUpdates:  arg.AutomaticUpdates,
+		UserACL:           database.WorkspaceACL{},
+		GroupACL:          database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -6673,6 +8386,76 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceBulkOperation(_ context.Context, arg database.InsertWorkspaceBulkOperationParams) (database.WorkspaceBulkOperation, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBulkOperation{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	operation := database.WorkspaceBulkOperation{
+		ID:          arg.ID,
+		InitiatorID: arg.InitiatorID,
+		Action:      arg.Action,
+		Query:       arg.Query,
+		Concurrency: arg.Concurrency,
+		Status:      database.WorkspaceBulkOperationStatusRunning,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.CreatedAt,
+	}
+	q.workspaceBulkOperations = append(q.workspaceBulkOperations, operation)
+	return operation, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceBulkOperationWorkspaces(_ context.Context, arg database.InsertWorkspaceBulkOperationWorkspacesParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, workspaceID := range arg.WorkspaceIds {
+		for _, result := range q.workspaceBulkOperationResults {
+			if result.OperationID == arg.OperationID && result.WorkspaceID == workspaceID {
+				return errDuplicateKey
+			}
+		}
+		q.workspaceBulkOperationResults = append(q.workspaceBulkOperationResults, database.WorkspaceBulkOperationWorkspace{
+			OperationID: arg.OperationID,
+			WorkspaceID: workspaceID,
+			Status:      database.WorkspaceBulkResultStatusPending,
+			UpdatedAt:   arg.UpdatedAt,
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspacePrebuild(_ context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, prebuild := range q.workspacePrebuilds {
+		if prebuild.WorkspaceID == arg.WorkspaceID {
+			return errDuplicateKey
+		}
+	}
+	q.workspacePrebuilds = append(q.workspacePrebuilds, database.WorkspacePrebuild{
+		WorkspaceID:             arg.WorkspaceID,
+		TemplateVersionPresetID: arg.TemplateVersionPresetID,
+		CreatedAt:               arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -6759,6 +8542,58 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSnapshot(_ context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if (snapshot.WorkspaceID == arg.WorkspaceID && snapshot.Name == arg.Name) || snapshot.BuildID == arg.BuildID {
+			return database.WorkspaceSnapshot{}, errDuplicateKey
+		}
+	}
+	//nolint:gosimple // Columns are added to the table over time.
+	snapshot := database.WorkspaceSnapshot{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		Name:        arg.Name,
+		BuildID:     arg.BuildID,
+		Artifacts:   json.RawMessage("{}"),
+		CreatedAt:   arg.CreatedAt,
+	}
+	q.workspaceSnapshots = append(q.workspaceSnapshots, snapshot)
+	return snapshot, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSnapshotRestore(_ context.Context, arg database.InsertWorkspaceSnapshotRestoreParams) (database.WorkspaceSnapshotRestore, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshotRestore{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, restore := range q.workspaceSnapshotRestores {
+		if restore.WorkspaceID == arg.WorkspaceID {
+			return database.WorkspaceSnapshotRestore{}, errDuplicateKey
+		}
+	}
+	//nolint:gosimple // Columns are added to the table over time.
+	restore := database.WorkspaceSnapshotRestore{
+		WorkspaceID: arg.WorkspaceID,
+		SnapshotID:  arg.SnapshotID,
+		Artifacts:   arg.Artifacts,
+		CreatedAt:   arg.CreatedAt,
+	}
+	q.workspaceSnapshotRestores = append(q.workspaceSnapshotRestores, restore)
+	return restore, nil
+}
+
 func (q *FakeQuerier) ListWorkspaceAgentPortShares(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -6769,8 +8604,110 @@ func (q *FakeQuerier) ListWorkspaceAgentPortShares(_ context.Context, workspaceI
 			shares = append(shares, share)
 		}
 	}
-
-	return shares, nil
+
+	return shares, nil
+}
+
+func (q *FakeQuerier) MarkNotificationMessageFailed(_ context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, msg := range q.notificationMessages {
+		if msg.ID != arg.ID {
+			continue
+		}
+		msg.Status = arg.Status
+		msg.StatusReason = arg.StatusReason
+		msg.AttemptCount++
+		msg.UpdatedAt = arg.UpdatedAt
+		msg.LeasedUntil = sql.NullTime{}
+		msg.NextRetryAfter = arg.NextRetryAfter
+		q.notificationMessages[i] = msg
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) MarkNotificationMessageSent(_ context.Context, arg database.MarkNotificationMessageSentParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, msg := range q.notificationMessages {
+		if msg.ID != arg.ID {
+			continue
+		}
+		msg.Status = database.NotificationMessageStatusSent
+		msg.StatusReason = sql.NullString{}
+		msg.AttemptCount++
+		msg.UpdatedAt = arg.SentAt
+		msg.SentAt = sql.NullTime{Time: arg.SentAt, Valid: true}
+		msg.LeasedUntil = sql.NullTime{}
+		msg.NextRetryAfter = sql.NullTime{}
+		q.notificationMessages[i] = msg
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) MarkWebhookDeliveryFailed(_ context.Context, arg database.MarkWebhookDeliveryFailedParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.Status = arg.Status
+		delivery.AttemptCount++
+		delivery.ResponseStatus = arg.ResponseStatus
+		delivery.ResponseBody = arg.ResponseBody
+		delivery.Error = arg.Error
+		delivery.UpdatedAt = arg.UpdatedAt
+		delivery.LeasedUntil = sql.NullTime{}
+		delivery.NextAttemptAfter = arg.NextAttemptAfter
+		q.webhookDeliveries[i] = delivery
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) MarkWebhookDeliverySucceeded(_ context.Context, arg database.MarkWebhookDeliverySucceededParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.Status = database.WebhookDeliveryStatusSucceeded
+		delivery.AttemptCount++
+		delivery.ResponseStatus = arg.ResponseStatus
+		delivery.ResponseBody = arg.ResponseBody
+		delivery.Error = sql.NullString{}
+		delivery.UpdatedAt = arg.DeliveredAt.Time
+		delivery.DeliveredAt = arg.DeliveredAt
+		delivery.LeasedUntil = sql.NullTime{}
+		delivery.NextAttemptAfter = sql.NullTime{}
+		q.webhookDeliveries[i] = delivery
+		return nil
+	}
+	return nil
 }
 
 func (q *FakeQuerier) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(_ context.Context, templateID uuid.UUID) error {
@@ -6823,9 +8760,16 @@ func (q *FakeQuerier) RemoveUserFromAllGroups(_ context.Context, userID uuid.UUI
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	scimGroups := make(map[uuid.UUID]bool)
+	for _, group := range q.groups {
+		if group.Source == database.GroupSourceScim {
+			scimGroups[group.ID] = true
+		}
+	}
+
 	newMembers := q.groupMembers[:0]
 	for _, member := range q.groupMembers {
-		if member.UserID == userID {
+		if member.UserID == userID && !scimGroups[member.GroupID] {
 			continue
 		}
 		newMembers = append(newMembers, member)
@@ -6940,6 +8884,29 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != arg.ID {
+			continue
+		}
+		role.DisplayName = arg.DisplayName
+		role.SitePermissions = arg.SitePermissions
+		role.OrgPermissions = arg.OrgPermissions
+		role.UserPermissions = arg.UserPermissions
+		role.UpdatedAt = arg.UpdatedAt
+		q.customRoles[i] = role
+		return role, nil
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateExternalAuthLink(_ context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ExternalAuthLink{}, err
@@ -7003,6 +8970,7 @@ func (q *FakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGrou
 			group.Name = arg.Name
 			group.AvatarURL = arg.AvatarURL
 			group.QuotaAllowance = arg.QuotaAllowance
+			group.MaxWorkspacesPerUser = arg.MaxWorkspacesPerUser
 			q.groups[i] = group
 			return group, nil
 		}
@@ -7016,7 +8984,7 @@ func (q *FakeQuerier) UpdateInactiveUsersToDormant(_ context.Context, params dat
 
 	var updated []database.UpdateInactiveUsersToDormantRow
 	for index, user := range q.users {
-		if user.Status == database.UserStatusActive && user.LastSeenAt.Before(params.LastSeenAfter) {
+		if user.Status == database.UserStatusActive && user.LastSeenAt.Before(params.LastSeenAfter) && !user.IsServiceAccount {
 			q.users[index].Status = database.UserStatusDormant
 			q.users[index].UpdatedAt = params.UpdatedAt
 			updated = append(updated, database.UpdateInactiveUsersToDormantRow{
@@ -7122,6 +9090,31 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg d
 	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateOIDCSyncRule(_ context.Context, arg database.UpdateOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OIDCSyncRule{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, rule := range q.oidcSyncRules {
+		if rule.ID != arg.ID {
+			continue
+		}
+		rule.Claim = arg.Claim
+		rule.Pattern = arg.Pattern
+		rule.SiteRoles = arg.SiteRoles
+		rule.OrganizationID = arg.OrganizationID
+		rule.OrganizationRoles = arg.OrganizationRoles
+		rule.UpdatedAt = arg.UpdatedAt
+		q.oidcSyncRules[i] = rule
+		return rule, nil
+	}
+	return database.OIDCSyncRule{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerDaemonLastSeenAt(_ context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7164,6 +9157,26 @@ func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateProvisionerJobEngineVersionConstraintByID(_ context.Context, arg database.UpdateProvisionerJobEngineVersionConstraintByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		job.EngineVersionConstraint = arg.EngineVersionConstraint
+		job.UpdatedAt = arg.UpdatedAt
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobWithCancelByID(_ context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7236,6 +9249,24 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateSCIMTokenLastUsedAt(_ context.Context, arg database.UpdateSCIMTokenLastUsedAtParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, token := range q.scimTokens {
+		if token.ID == arg.ID {
+			q.scimTokens[i].LastUsedAt = arg.LastUsedAt
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7337,6 +9368,7 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.GroupACL = arg.GroupACL
 		tpl.AllowUserCancelWorkspaceJobs = arg.AllowUserCancelWorkspaceJobs
 		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
+		tpl.MaxWorkspaces = arg.MaxWorkspaces
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -7344,6 +9376,28 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateReleaseChannelByID(_ context.Context, arg database.UpdateTemplateReleaseChannelByIDParams) (database.TemplateReleaseChannel, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateReleaseChannel{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, channel := range q.templateReleaseChannels {
+		if channel.ID == arg.ID {
+			channel.TemplateVersionID = arg.TemplateVersionID
+			channel.UserIDs = arg.UserIDs
+			channel.GroupIDs = arg.GroupIDs
+			channel.UpdatedAt = arg.UpdatedAt
+			q.templateReleaseChannels[i] = channel
+			return channel, nil
+		}
+	}
+	return database.TemplateReleaseChannel{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7615,6 +9669,15 @@ func (q *FakeQuerier) UpdateUserProfile(_ context.Context, arg database.UpdateUs
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	for _, user := range q.users {
+		if user.ID == arg.ID || user.Deleted {
+			continue
+		}
+		if strings.EqualFold(user.Email, arg.Email) || strings.EqualFold(user.Username, arg.Username) {
+			return database.User{}, errDuplicateKey
+		}
+	}
+
 	for index, user := range q.users {
 		if user.ID != arg.ID {
 			continue
@@ -7702,6 +9765,56 @@ func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserTOTPLastUsedStep(_ context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || totp.LastUsedStep >= arg.LastUsedStep {
+			continue
+		}
+		totp.LastUsedStep = arg.LastUsedStep
+		q.userTOTPs[i] = totp
+		return totp, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWebhookByID(_ context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.Name == arg.Name && webhook.ID != arg.ID {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	for i, webhook := range q.webhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		webhook.Name = arg.Name
+		webhook.Url = arg.Url
+		webhook.Events = arg.Events
+		webhook.Secret = arg.Secret
+		webhook.Enabled = arg.Enabled
+		webhook.UpdatedAt = arg.UpdatedAt
+		q.webhooks[i] = webhook
+		return webhook, nil
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -7732,6 +9845,27 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.ID == arg.ID {
+			workspace.UserACL = arg.UserACL
+			workspace.GroupACL = arg.GroupACL
+
+			q.workspaces[i] = workspace
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7975,6 +10109,51 @@ func (q *FakeQuerier) UpdateWorkspaceBuildProvisionerStateByID(_ context.Context
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceBulkOperationStatus(_ context.Context, arg database.UpdateWorkspaceBulkOperationStatusParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, operation := range q.workspaceBulkOperations {
+		if operation.ID != arg.ID {
+			continue
+		}
+		operation.Status = arg.Status
+		operation.UpdatedAt = arg.UpdatedAt
+		operation.CompletedAt = arg.CompletedAt
+		q.workspaceBulkOperations[i] = operation
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateWorkspaceBulkOperationWorkspace(_ context.Context, arg database.UpdateWorkspaceBulkOperationWorkspaceParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, result := range q.workspaceBulkOperationResults {
+		if result.OperationID != arg.OperationID || result.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		result.Status = arg.Status
+		result.BuildID = arg.BuildID
+		result.Error = arg.Error
+		result.UpdatedAt = arg.UpdatedAt
+		q.workspaceBulkOperationResults[i] = result
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -8053,6 +10232,38 @@ func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceOwnerByID(_ context.Context, arg database.UpdateWorkspaceOwnerByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.OwnerID {
+				continue
+			}
+			if strings.EqualFold(other.Name, workspace.Name) {
+				return errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.OwnerID
+		workspace.Favorite = false
+		workspace.UpdatedAt = arg.UpdatedAt
+		q.workspaces[i] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -8093,6 +10304,24 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceSnapshotArtifactsByID(_ context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == arg.ID {
+			q.workspaceSnapshots[i].Artifacts = arg.Artifacts
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -8911,6 +11140,89 @@ TemplateUsageStatsInsertLoop:
 	return nil
 }
 
+func (q *FakeQuerier) UpsertUserLoginLockout(_ context.Context, arg database.UpsertUserLoginLockoutParams) (database.UserLoginLockout, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserLoginLockout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	lockout := database.UserLoginLockout{
+		UserID:         arg.UserID,
+		FailedAttempts: arg.FailedAttempts,
+		CreatedAt:      arg.CreatedAt,
+		LockedUntil:    arg.LockedUntil,
+	}
+	for i, existing := range q.userLoginLockouts {
+		if existing.UserID == arg.UserID {
+			q.userLoginLockouts[i] = lockout
+			return lockout, nil
+		}
+	}
+	q.userLoginLockouts = append(q.userLoginLockouts, lockout)
+	return lockout, nil
+}
+
+func (q *FakeQuerier) UpsertUserNotificationPreference(_ context.Context, arg database.UpsertUserNotificationPreferenceParams) (database.NotificationPreference, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationPreference{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, pref := range q.notificationPreferences {
+		if pref.UserID != arg.UserID || pref.Template != arg.Template {
+			continue
+		}
+		pref.Disabled = arg.Disabled
+		pref.UpdatedAt = arg.UpdatedAt
+		q.notificationPreferences[i] = pref
+		return pref, nil
+	}
+
+	pref := database.NotificationPreference{
+		UserID:    arg.UserID,
+		Template:  arg.Template,
+		Disabled:  arg.Disabled,
+		CreatedAt: arg.UpdatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	q.notificationPreferences = append(q.notificationPreferences, pref)
+	return pref, nil
+}
+
+func (q *FakeQuerier) UpsertUserTOTP(_ context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	totp := database.UserTOTP{
+		UserID:              arg.UserID,
+		Secret:              arg.Secret,
+		HashedRecoveryCodes: []string{},
+		CreatedAt:           arg.CreatedAt,
+	}
+	for i, existing := range q.userTOTPs {
+		if existing.UserID == arg.UserID {
+			if existing.EnabledAt.Valid {
+				return database.UserTOTP{}, sql.ErrNoRows
+			}
+			q.userTOTPs[i] = totp
+			return totp, nil
+		}
+	}
+	q.userTOTPs = append(q.userTOTPs, totp)
+	return totp, nil
+}
+
 func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -8942,6 +11254,28 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 	return psl, nil
 }
 
+func (q *FakeQuerier) UseUserTOTPRecoveryCode(_ context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || !totp.EnabledAt.Valid || !slices.Contains(totp.HashedRecoveryCodes, arg.HashedCode) {
+			continue
+		}
+		totp.HashedRecoveryCodes = slices.DeleteFunc(slices.Clone(totp.HashedRecoveryCodes), func(code string) bool {
+			return code == arg.HashedCode
+		})
+		q.userTOTPs[i] = totp
+		return totp, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -9094,7 +11428,7 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 
 	if prepared != nil {
 		// Call this to match the same function calls as the SQL implementation.
-		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWorkspaces())
 		if err != nil {
 			return nil, err
 		}
@@ -9106,6 +11440,10 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 			continue
 		}
 
+		if arg.OrganizationID != uuid.Nil && workspace.OrganizationID != arg.OrganizationID {
+			continue
+		}
+
 		if len(arg.HasParam) > 0 || len(arg.ParamNames) > 0 {
 			build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
 			if err != nil {
@@ -9180,7 +11518,8 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 				return nil, xerrors.Errorf("get template: %w", err)
 			}
 
-			updated := build.TemplateVersionID == template.ActiveVersionID
+			activeVersionID, _ := q.getTemplateActiveVersionForUserNoLock(template.ID, template.ActiveVersionID, workspace.OwnerID)
+			updated := build.TemplateVersionID == activeVersionID
 			if arg.UsingActive.Bool != updated {
 				continue
 			}
@@ -9318,6 +11657,10 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 			}
 		}
 
+		if arg.Shared && !q.isWorkspaceSharedWithNoLock(workspace, arg.RequesterID) {
+			continue
+		}
+
 		// If the filter exists, ensure the object is authorized.
 		if prepared != nil && prepared.Authorize(ctx, workspace.RBACObject()) != nil {
 			continue
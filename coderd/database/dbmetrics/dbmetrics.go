@@ -86,6 +86,13 @@ func (m metricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64)
 	return err
 }
 
+func (m metricsStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireNotificationMessages(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
@@ -93,6 +100,13 @@ func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.Ac
 	return provisionerJob, err
 }
 
+func (m metricsStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	start := time.Now()
 	r0 := m.s.ActivityBumpWorkspace(ctx, arg)
@@ -121,6 +135,13 @@ func (m metricsStore) BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg da
 	return r0
 }
 
+func (m metricsStore) ClaimWorkspacePrebuild(ctx context.Context, arg database.ClaimWorkspacePrebuildParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClaimWorkspacePrebuild(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClaimWorkspacePrebuild").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
@@ -183,6 +204,13 @@ func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	return m.s.DeleteCoordinator(ctx, id)
 }
 
+func (m metricsStore) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteCustomRole(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteCustomRole").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
@@ -253,6 +281,20 @@ func (m metricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Co
 	return r0
 }
 
+func (m metricsStore) DeleteOIDCSyncRule(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOIDCSyncRule(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOIDCSyncRule").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNotificationMessages(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldProvisionerDaemons(ctx)
@@ -260,6 +302,20 @@ func (m metricsStore) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return r0
 }
 
+func (m metricsStore) DeleteOldUserLoginAttempts(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldUserLoginAttempts(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldUserLoginAttempts").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWebhookDeliveries(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx)
@@ -274,6 +330,27 @@ func (m metricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return err
 }
 
+func (m metricsStore) DeleteOldWorkspaceBuildProvisionerStates(ctx context.Context, keep int32) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceBuildProvisionerStates(ctx, keep)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceBuildProvisionerStates").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteOrganizationMember(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOrganizationMember").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOtherAPIKeysByUserID(ctx context.Context, arg database.DeleteOtherAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOtherAPIKeysByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOtherAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	start := time.Now()
 	err := m.s.DeleteReplicasUpdatedBefore(ctx, updatedAt)
@@ -281,6 +358,13 @@ func (m metricsStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt
 	return err
 }
 
+func (m metricsStore) DeleteSCIMToken(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteSCIMToken(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteSCIMToken").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
 	defer m.queryLatencies.WithLabelValues("DeleteTailnetAgent").Observe(time.Since(start).Seconds())
@@ -314,6 +398,41 @@ func (m metricsStore) DeleteTailnetTunnel(ctx context.Context, arg database.Dele
 	return r0, r1
 }
 
+func (m metricsStore) DeleteTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteTemplateReleaseChannelByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteTemplateReleaseChannelByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserLoginFailures(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserLoginFailures").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserLoginLockoutByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserLoginLockoutByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserTOTPByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserTOTPByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteWorkspaceAgentPortShare(ctx, arg)
@@ -328,6 +447,34 @@ func (m metricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Conte
 	return r0
 }
 
+func (m metricsStore) DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePrebuildsOfDeletedWorkspaces").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) EnableUserTOTP(ctx context.Context, arg database.EnableUserTOTPParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.EnableUserTOTP(ctx, arg)
+	m.queryLatencies.WithLabelValues("EnableUserTOTP").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.EnqueueNotificationMessage(ctx, arg)
+	m.queryLatencies.WithLabelValues("EnqueueNotificationMessage").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) FavoriteWorkspace(ctx context.Context, arg uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.FavoriteWorkspace(ctx, arg)
@@ -370,6 +517,13 @@ func (m metricsStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time
 	return apiKeys, err
 }
 
+func (m metricsStore) GetActiveAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveAPIKeysByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetActiveAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetActiveUserCount(ctx context.Context) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetActiveUserCount(ctx)
@@ -440,6 +594,20 @@ func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid
 	return row, err
 }
 
+func (m metricsStore) GetCustomRoleByName(ctx context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoleByName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetCustomRoleByName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoles(ctx context.Context) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoles(ctx)
+	m.queryLatencies.WithLabelValues("GetCustomRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDBCryptKeys(ctx)
@@ -587,6 +755,13 @@ func (m metricsStore) GetHungProvisionerJobs(ctx context.Context, hungSince time
 	return jobs, err
 }
 
+func (m metricsStore) GetImpersonationAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetImpersonationAPIKeysByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetImpersonationAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetJFrogXrayScanByWorkspaceAndAgentID(ctx context.Context, arg database.GetJFrogXrayScanByWorkspaceAndAgentIDParams) (database.JfrogXrayScan, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetJFrogXrayScanByWorkspaceAndAgentID(ctx, arg)
@@ -636,6 +811,13 @@ func (m metricsStore) GetLicenses(ctx context.Context) ([]database.License, erro
 	return licenses, err
 }
 
+func (m metricsStore) GetLoginFailureStats(ctx context.Context, arg database.GetLoginFailureStatsParams) (database.GetLoginFailureStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLoginFailureStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetLoginFailureStats").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	start := time.Now()
 	url, err := m.s.GetLogoURL(ctx)
@@ -643,6 +825,13 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessagesByStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNotificationMessagesByStatus").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
@@ -713,6 +902,20 @@ func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return r0, r1
 }
 
+func (m metricsStore) GetOIDCSyncRuleByID(ctx context.Context, id uuid.UUID) (database.OIDCSyncRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOIDCSyncRuleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOIDCSyncRuleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOIDCSyncRules(ctx context.Context) ([]database.OIDCSyncRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOIDCSyncRules(ctx)
+	m.queryLatencies.WithLabelValues("GetOIDCSyncRules").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByID(ctx, id)
@@ -769,6 +972,20 @@ func (m metricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid
 	return schemas, err
 }
 
+func (m metricsStore) GetPendingProvisionerJobEngineVersionConstraints(ctx context.Context, organizationID uuid.UUID) ([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingProvisionerJobEngineVersionConstraints(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetPendingProvisionerJobEngineVersionConstraints").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetPrebuildPresets(ctx context.Context) ([]database.GetPrebuildPresetsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPrebuildPresets(ctx)
+	m.queryLatencies.WithLabelValues("GetPrebuildPresets").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetPreviousTemplateVersion(ctx, arg)
@@ -783,6 +1000,13 @@ func (m metricsStore) GetProvisionerDaemons(ctx context.Context) ([]database.Pro
 	return daemons, err
 }
 
+func (m metricsStore) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerDaemonsByOrganization(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetProvisionerDaemonsByOrganization").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.GetProvisionerJobByID(ctx, id)
@@ -818,16 +1042,16 @@ func (m metricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg databas
 	return logs, err
 }
 
-func (m metricsStore) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+func (m metricsStore) GetQuotaAllowanceForUser(ctx context.Context, arg database.GetQuotaAllowanceForUserParams) (int64, error) {
 	start := time.Now()
-	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, userID)
+	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetQuotaAllowanceForUser").Observe(time.Since(start).Seconds())
 	return allowance, err
 }
 
-func (m metricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+func (m metricsStore) GetQuotaConsumedForUser(ctx context.Context, arg database.GetQuotaConsumedForUserParams) (int64, error) {
 	start := time.Now()
-	consumed, err := m.s.GetQuotaConsumedForUser(ctx, ownerID)
+	consumed, err := m.s.GetQuotaConsumedForUser(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetQuotaConsumedForUser").Observe(time.Since(start).Seconds())
 	return consumed, err
 }
@@ -846,6 +1070,34 @@ func (m metricsStore) GetReplicasUpdatedAfter(ctx context.Context, updatedAt tim
 	return replicas, err
 }
 
+func (m metricsStore) GetRunningWorkspaceBulkOperations(ctx context.Context) ([]database.WorkspaceBulkOperation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetRunningWorkspaceBulkOperations(ctx)
+	m.queryLatencies.WithLabelValues("GetRunningWorkspaceBulkOperations").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetSCIMTokenByID(ctx context.Context, id uuid.UUID) (database.SCIMToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMTokenByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSCIMTokenByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetSCIMTokenByPrefix(ctx context.Context, secretPrefix []byte) (database.SCIMToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMTokenByPrefix(ctx, secretPrefix)
+	m.queryLatencies.WithLabelValues("GetSCIMTokenByPrefix").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetSCIMTokens(ctx context.Context) ([]database.SCIMToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMTokens(ctx)
+	m.queryLatencies.WithLabelValues("GetSCIMTokens").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetServiceBanner(ctx context.Context) (string, error) {
 	start := time.Now()
 	banner, err := m.s.GetServiceBanner(ctx)
@@ -886,6 +1138,20 @@ func (m metricsStore) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UU
 	return r0, r1
 }
 
+func (m metricsStore) GetTemplateActiveVersionForUser(ctx context.Context, arg database.GetTemplateActiveVersionForUserParams) (database.GetTemplateActiveVersionForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateActiveVersionForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateActiveVersionForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateActiveVersionsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetTemplateActiveVersionsByWorkspaceIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateActiveVersionsByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetTemplateActiveVersionsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
@@ -956,6 +1222,27 @@ func (m metricsStore) GetTemplateParameterInsights(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m metricsStore) GetTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateReleaseChannelByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateReleaseChannelByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateReleaseChannelByTemplateIDAndName(ctx context.Context, arg database.GetTemplateReleaseChannelByTemplateIDAndNameParams) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateReleaseChannelByTemplateIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateReleaseChannelByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateReleaseChannelsByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateReleaseChannelsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateUsageStats(ctx context.Context, arg database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateUsageStats(ctx, arg)
@@ -984,6 +1271,13 @@ func (m metricsStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 	return version, err
 }
 
+func (m metricsStore) GetTemplateVersionDiagnostics(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionDiagnostic, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionDiagnostics(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionDiagnostics").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionParameter, error) {
 	start := time.Now()
 	parameters, err := m.s.GetTemplateVersionParameters(ctx, templateVersionID)
@@ -991,6 +1285,34 @@ func (m metricsStore) GetTemplateVersionParameters(ctx context.Context, template
 	return parameters, err
 }
 
+func (m metricsStore) GetTemplateVersionPresetByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresetByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresetByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateVersionPresetParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresetParameters(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresetParameters").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateVersionPresetParametersByPresetID(ctx context.Context, templateVersionPresetID uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresetParametersByPresetID(ctx, templateVersionPresetID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresetParametersByPresetID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresets(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresets").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variables, err := m.s.GetTemplateVersionVariables(ctx, templateVersionID)
@@ -1096,6 +1418,34 @@ func (m metricsStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID
 	return r0, r1
 }
 
+func (m metricsStore) GetUserLoginHistorySummary(ctx context.Context, arg database.GetUserLoginHistorySummaryParams) (database.GetUserLoginHistorySummaryRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLoginHistorySummary(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserLoginHistorySummary").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) (database.UserLoginLockout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLoginLockoutByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserLoginLockoutByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationPreferences(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserNotificationPreferences").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserTOTPByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserTOTPByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUserWorkspaceBuildParameters(ctx context.Context, ownerID database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserWorkspaceBuildParameters(ctx, ownerID)
@@ -1117,6 +1467,34 @@ func (m metricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]dat
 	return users, err
 }
 
+func (m metricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveryByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndLatestBuildByAuthToken(ctx, authToken)
@@ -1271,6 +1649,13 @@ func (m metricsStore) GetWorkspaceBuildParameters(ctx context.Context, workspace
 	return params, err
 }
 
+func (m metricsStore) GetWorkspaceBuildStatesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceBuildStatesByWorkspaceIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildStatesByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildStatesByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
@@ -1285,6 +1670,20 @@ func (m metricsStore) GetWorkspaceBuildsCreatedAfter(ctx context.Context, create
 	return builds, err
 }
 
+func (m metricsStore) GetWorkspaceBulkOperationByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBulkOperation, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBulkOperationByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBulkOperationByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceBulkOperationWorkspaces(ctx context.Context, operationID uuid.UUID) ([]database.GetWorkspaceBulkOperationWorkspacesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBulkOperationWorkspaces(ctx, operationID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBulkOperationWorkspaces").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.GetWorkspaceByAgentIDRow, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByAgentID(ctx, agentID)
@@ -1313,6 +1712,34 @@ func (m metricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspac
 	return workspace, err
 }
 
+func (m metricsStore) GetWorkspaceCountForTemplate(ctx context.Context, templateID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCountForTemplate(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCountForTemplate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceCountForUser(ctx context.Context, arg database.GetWorkspaceCountForUserParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCountForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCountForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceLimitForUser(ctx context.Context, arg database.GetWorkspaceLimitForUserParams) (int32, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceLimitForUser(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceLimitForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspacePrebuilds(ctx context.Context) ([]database.GetWorkspacePrebuildsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePrebuilds(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspacePrebuilds").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
@@ -1383,6 +1810,41 @@ func (m metricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, cre
 	return resources, err
 }
 
+func (m metricsStore) GetWorkspaceSnapshotByBuildID(ctx context.Context, buildID uuid.UUID) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByBuildID(ctx, buildID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByBuildID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByWorkspaceIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSnapshotRestoreByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceSnapshotRestore, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotRestoreByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotRestoreByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -1425,6 +1887,13 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	return log, err
 }
 
+func (m metricsStore) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	start := time.Now()
 	r0 := m.s.InsertDBCryptKey(ctx, arg)
@@ -1523,6 +1992,13 @@ func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m metricsStore) InsertOIDCSyncRule(ctx context.Context, arg database.InsertOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOIDCSyncRule(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOIDCSyncRule").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1558,6 +2034,13 @@ func (m metricsStore) InsertReplica(ctx context.Context, arg database.InsertRepl
 	return replica, err
 }
 
+func (m metricsStore) InsertSCIMToken(ctx context.Context, arg database.InsertSCIMTokenParams) (database.SCIMToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSCIMToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSCIMToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplate(ctx, arg)
@@ -1565,6 +2048,13 @@ func (m metricsStore) InsertTemplate(ctx context.Context, arg database.InsertTem
 	return err
 }
 
+func (m metricsStore) InsertTemplateReleaseChannel(ctx context.Context, arg database.InsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateReleaseChannel(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateReleaseChannel").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
@@ -1572,6 +2062,13 @@ func (m metricsStore) InsertTemplateVersion(ctx context.Context, arg database.In
 	return err
 }
 
+func (m metricsStore) InsertTemplateVersionDiagnostic(ctx context.Context, arg database.InsertTemplateVersionDiagnosticParams) (database.TemplateVersionDiagnostic, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionDiagnostic(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionDiagnostic").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersionParameter(ctx context.Context, arg database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
 	start := time.Now()
 	parameter, err := m.s.InsertTemplateVersionParameter(ctx, arg)
@@ -1579,6 +2076,20 @@ func (m metricsStore) InsertTemplateVersionParameter(ctx context.Context, arg da
 	return parameter, err
 }
 
+func (m metricsStore) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionPreset(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionPreset").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertTemplateVersionPresetParameters(ctx context.Context, arg database.InsertTemplateVersionPresetParametersParams) ([]database.TemplateVersionPresetParameter, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionPresetParameters(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionPresetParameters").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variable, err := m.s.InsertTemplateVersionVariable(ctx, arg)
@@ -1607,6 +2118,27 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	return link, err
 }
 
+func (m metricsStore) InsertUserLoginAttempt(ctx context.Context, arg database.InsertUserLoginAttemptParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserLoginAttempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserLoginAttempt").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhookDelivery(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhookDelivery").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
@@ -1691,6 +2223,27 @@ func (m metricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg da
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceBulkOperation(ctx context.Context, arg database.InsertWorkspaceBulkOperationParams) (database.WorkspaceBulkOperation, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBulkOperation(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBulkOperation").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceBulkOperationWorkspaces(ctx context.Context, arg database.InsertWorkspaceBulkOperationWorkspacesParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceBulkOperationWorkspaces(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBulkOperationWorkspaces").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertWorkspacePrebuild(ctx context.Context, arg database.InsertWorkspacePrebuildParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspacePrebuild(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspacePrebuild").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
@@ -1712,6 +2265,20 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	return metadata, err
 }
 
+func (m metricsStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSnapshot(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSnapshot").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceSnapshotRestore(ctx context.Context, arg database.InsertWorkspaceSnapshotRestoreParams) (database.WorkspaceSnapshotRestore, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSnapshotRestore(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSnapshotRestore").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListWorkspaceAgentPortShares(ctx, workspaceID)
@@ -1719,6 +2286,34 @@ func (m metricsStore) ListWorkspaceAgentPortShares(ctx context.Context, workspac
 	return r0, r1
 }
 
+func (m metricsStore) MarkNotificationMessageFailed(ctx context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	start := time.Now()
+	r0 := m.s.MarkNotificationMessageFailed(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkNotificationMessageFailed").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) MarkNotificationMessageSent(ctx context.Context, arg database.MarkNotificationMessageSentParams) error {
+	start := time.Now()
+	r0 := m.s.MarkNotificationMessageSent(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkNotificationMessageSent").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) MarkWebhookDeliveryFailed(ctx context.Context, arg database.MarkWebhookDeliveryFailedParams) error {
+	start := time.Now()
+	r0 := m.s.MarkWebhookDeliveryFailed(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkWebhookDeliveryFailed").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) MarkWebhookDeliverySucceeded(ctx context.Context, arg database.MarkWebhookDeliverySucceededParams) error {
+	start := time.Now()
+	r0 := m.s.MarkWebhookDeliverySucceeded(ctx, arg)
+	m.queryLatencies.WithLabelValues("MarkWebhookDeliverySucceeded").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx, templateID)
@@ -1775,6 +2370,13 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	return err
 }
 
+func (m metricsStore) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateExternalAuthLink(ctx, arg)
@@ -1824,6 +2426,13 @@ func (m metricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg
 	return r0, r1
 }
 
+func (m metricsStore) UpdateOIDCSyncRule(ctx context.Context, arg database.UpdateOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOIDCSyncRule(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOIDCSyncRule").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
@@ -1838,6 +2447,13 @@ func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database
 	return err
 }
 
+func (m metricsStore) UpdateProvisionerJobEngineVersionConstraintByID(ctx context.Context, arg database.UpdateProvisionerJobEngineVersionConstraintByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateProvisionerJobEngineVersionConstraintByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerJobEngineVersionConstraintByID").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobWithCancelByID(ctx, arg)
@@ -1859,6 +2475,13 @@ func (m metricsStore) UpdateReplica(ctx context.Context, arg database.UpdateRepl
 	return replica, err
 }
 
+func (m metricsStore) UpdateSCIMTokenLastUsedAt(ctx context.Context, arg database.UpdateSCIMTokenLastUsedAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateSCIMTokenLastUsedAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateSCIMTokenLastUsedAt").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateACLByID(ctx, arg)
@@ -1894,6 +2517,13 @@ func (m metricsStore) UpdateTemplateMetaByID(ctx context.Context, arg database.U
 	return err
 }
 
+func (m metricsStore) UpdateTemplateReleaseChannelByID(ctx context.Context, arg database.UpdateTemplateReleaseChannelByIDParams) (database.TemplateReleaseChannel, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateReleaseChannelByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateReleaseChannelByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateScheduleByID(ctx, arg)
@@ -2006,6 +2636,20 @@ func (m metricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateU
 	return user, err
 }
 
+func (m metricsStore) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserTOTPLastUsedStep(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserTOTPLastUsedStep").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWebhookByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
@@ -2013,6 +2657,13 @@ func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWo
 	return workspace, err
 }
 
+func (m metricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceACLByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceACLByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -2090,6 +2741,20 @@ func (m metricsStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Conte
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceBulkOperationStatus(ctx context.Context, arg database.UpdateWorkspaceBulkOperationStatusParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceBulkOperationStatus(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceBulkOperationStatus").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpdateWorkspaceBulkOperationWorkspace(ctx context.Context, arg database.UpdateWorkspaceBulkOperationWorkspaceParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceBulkOperationWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceBulkOperationWorkspace").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceDeletedByID(ctx, arg)
@@ -2111,6 +2776,13 @@ func (m metricsStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg databas
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceOwnerByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
@@ -2125,6 +2797,13 @@ func (m metricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg datab
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceSnapshotArtifactsByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceSnapshotArtifactsByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
@@ -2255,6 +2934,27 @@ func (m metricsStore) UpsertTemplateUsageStats(ctx context.Context) error {
 	return r0
 }
 
+func (m metricsStore) UpsertUserLoginLockout(ctx context.Context, arg database.UpsertUserLoginLockoutParams) (database.UserLoginLockout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserLoginLockout(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserLoginLockout").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserNotificationPreference(ctx context.Context, arg database.UpsertUserNotificationPreferenceParams) (database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserNotificationPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserNotificationPreference").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserTOTP(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserTOTP").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertWorkspaceAgentPortShare(ctx, arg)
@@ -2262,6 +2962,13 @@ func (m metricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg dat
 	return r0, r1
 }
 
+func (m metricsStore) UseUserTOTPRecoveryCode(ctx context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UseUserTOTPRecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("UseUserTOTPRecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
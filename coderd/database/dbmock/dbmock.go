@@ -58,6 +58,21 @@ func (mr *MockStoreMockRecorder) AcquireLock(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockStore)(nil).AcquireLock), arg0, arg1)
 }
 
+// AcquireNotificationMessages mocks base method.
+func (m *MockStore) AcquireNotificationMessages(arg0 context.Context, arg1 database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireNotificationMessages", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireNotificationMessages indicates an expected call of AcquireNotificationMessages.
+func (mr *MockStoreMockRecorder) AcquireNotificationMessages(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireNotificationMessages", reflect.TypeOf((*MockStore)(nil).AcquireNotificationMessages), arg0, arg1)
+}
+
 // AcquireProvisionerJob mocks base method.
 func (m *MockStore) AcquireProvisionerJob(arg0 context.Context, arg1 database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -73,6 +88,21 @@ func (mr *MockStoreMockRecorder) AcquireProvisionerJob(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireProvisionerJob", reflect.TypeOf((*MockStore)(nil).AcquireProvisionerJob), arg0, arg1)
 }
 
+// AcquireWebhookDeliveries mocks base method.
+func (m *MockStore) AcquireWebhookDeliveries(arg0 context.Context, arg1 database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireWebhookDeliveries indicates an expected call of AcquireWebhookDeliveries.
+func (mr *MockStoreMockRecorder) AcquireWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).AcquireWebhookDeliveries), arg0, arg1)
+}
+
 // ActivityBumpWorkspace mocks base method.
 func (m *MockStore) ActivityBumpWorkspace(arg0 context.Context, arg1 database.ActivityBumpWorkspaceParams) error {
 	m.ctrl.T.Helper()
@@ -131,6 +161,21 @@ func (mr *MockStoreMockRecorder) BatchUpdateWorkspaceLastUsedAt(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdateWorkspaceLastUsedAt", reflect.TypeOf((*MockStore)(nil).BatchUpdateWorkspaceLastUsedAt), arg0, arg1)
 }
 
+// ClaimWorkspacePrebuild mocks base method.
+func (m *MockStore) ClaimWorkspacePrebuild(arg0 context.Context, arg1 database.ClaimWorkspacePrebuildParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWorkspacePrebuild", arg0, arg1)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWorkspacePrebuild indicates an expected call of ClaimWorkspacePrebuild.
+func (mr *MockStoreMockRecorder) ClaimWorkspacePrebuild(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWorkspacePrebuild", reflect.TypeOf((*MockStore)(nil).ClaimWorkspacePrebuild), arg0, arg1)
+}
+
 // CleanTailnetCoordinators mocks base method.
 func (m *MockStore) CleanTailnetCoordinators(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -257,6 +302,20 @@ func (mr *MockStoreMockRecorder) DeleteCoordinator(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoordinator", reflect.TypeOf((*MockStore)(nil).DeleteCoordinator), arg0, arg1)
 }
 
+// DeleteCustomRole mocks base method.
+func (m *MockStore) DeleteCustomRole(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomRole indicates an expected call of DeleteCustomRole.
+func (mr *MockStoreMockRecorder) DeleteCustomRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockStore)(nil).DeleteCustomRole), arg0, arg1)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(arg0 context.Context, arg1 database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -398,6 +457,34 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppTokensByAppAndUserID(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppTokensByAppAndUserID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppTokensByAppAndUserID), arg0, arg1)
 }
 
+// DeleteOIDCSyncRule mocks base method.
+func (m *MockStore) DeleteOIDCSyncRule(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOIDCSyncRule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOIDCSyncRule indicates an expected call of DeleteOIDCSyncRule.
+func (mr *MockStoreMockRecorder) DeleteOIDCSyncRule(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOIDCSyncRule", reflect.TypeOf((*MockStore)(nil).DeleteOIDCSyncRule), arg0, arg1)
+}
+
+// DeleteOldNotificationMessages mocks base method.
+func (m *MockStore) DeleteOldNotificationMessages(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldNotificationMessages", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldNotificationMessages indicates an expected call of DeleteOldNotificationMessages.
+func (mr *MockStoreMockRecorder) DeleteOldNotificationMessages(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNotificationMessages", reflect.TypeOf((*MockStore)(nil).DeleteOldNotificationMessages), arg0)
+}
+
 // DeleteOldProvisionerDaemons mocks base method.
 func (m *MockStore) DeleteOldProvisionerDaemons(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -412,6 +499,34 @@ func (mr *MockStoreMockRecorder) DeleteOldProvisionerDaemons(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerDaemons), arg0)
 }
 
+// DeleteOldUserLoginAttempts mocks base method.
+func (m *MockStore) DeleteOldUserLoginAttempts(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldUserLoginAttempts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldUserLoginAttempts indicates an expected call of DeleteOldUserLoginAttempts.
+func (mr *MockStoreMockRecorder) DeleteOldUserLoginAttempts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldUserLoginAttempts", reflect.TypeOf((*MockStore)(nil).DeleteOldUserLoginAttempts), arg0)
+}
+
+// DeleteOldWebhookDeliveries mocks base method.
+func (m *MockStore) DeleteOldWebhookDeliveries(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWebhookDeliveries", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWebhookDeliveries indicates an expected call of DeleteOldWebhookDeliveries.
+func (mr *MockStoreMockRecorder) DeleteOldWebhookDeliveries(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).DeleteOldWebhookDeliveries), arg0)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -440,6 +555,49 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), arg0)
 }
 
+// DeleteOldWorkspaceBuildProvisionerStates mocks base method.
+func (m *MockStore) DeleteOldWorkspaceBuildProvisionerStates(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceBuildProvisionerStates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceBuildProvisionerStates indicates an expected call of DeleteOldWorkspaceBuildProvisionerStates.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceBuildProvisionerStates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceBuildProvisionerStates", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceBuildProvisionerStates), arg0, arg1)
+}
+
+// DeleteOrganizationMember mocks base method.
+func (m *MockStore) DeleteOrganizationMember(arg0 context.Context, arg1 database.DeleteOrganizationMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganizationMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganizationMember indicates an expected call of DeleteOrganizationMember.
+func (mr *MockStoreMockRecorder) DeleteOrganizationMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationMember", reflect.TypeOf((*MockStore)(nil).DeleteOrganizationMember), arg0, arg1)
+}
+
+// DeleteOtherAPIKeysByUserID mocks base method.
+func (m *MockStore) DeleteOtherAPIKeysByUserID(arg0 context.Context, arg1 database.DeleteOtherAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOtherAPIKeysByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOtherAPIKeysByUserID indicates an expected call of DeleteOtherAPIKeysByUserID.
+func (mr *MockStoreMockRecorder) DeleteOtherAPIKeysByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteOtherAPIKeysByUserID), arg0, arg1)
+}
+
 // DeleteReplicasUpdatedBefore mocks base method.
 func (m *MockStore) DeleteReplicasUpdatedBefore(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
@@ -454,6 +612,20 @@ func (mr *MockStoreMockRecorder) DeleteReplicasUpdatedBefore(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicasUpdatedBefore", reflect.TypeOf((*MockStore)(nil).DeleteReplicasUpdatedBefore), arg0, arg1)
 }
 
+// DeleteSCIMToken mocks base method.
+func (m *MockStore) DeleteSCIMToken(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMToken indicates an expected call of DeleteSCIMToken.
+func (mr *MockStoreMockRecorder) DeleteSCIMToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMToken", reflect.TypeOf((*MockStore)(nil).DeleteSCIMToken), arg0, arg1)
+}
+
 // DeleteTailnetAgent mocks base method.
 func (m *MockStore) DeleteTailnetAgent(arg0 context.Context, arg1 database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	m.ctrl.T.Helper()
@@ -528,6 +700,76 @@ func (mr *MockStoreMockRecorder) DeleteTailnetTunnel(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetTunnel", reflect.TypeOf((*MockStore)(nil).DeleteTailnetTunnel), arg0, arg1)
 }
 
+// DeleteTemplateReleaseChannelByID mocks base method.
+func (m *MockStore) DeleteTemplateReleaseChannelByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateReleaseChannelByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateReleaseChannelByID indicates an expected call of DeleteTemplateReleaseChannelByID.
+func (mr *MockStoreMockRecorder) DeleteTemplateReleaseChannelByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateReleaseChannelByID", reflect.TypeOf((*MockStore)(nil).DeleteTemplateReleaseChannelByID), arg0, arg1)
+}
+
+// DeleteUserLoginFailures mocks base method.
+func (m *MockStore) DeleteUserLoginFailures(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLoginFailures indicates an expected call of DeleteUserLoginFailures.
+func (mr *MockStoreMockRecorder) DeleteUserLoginFailures(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLoginFailures", reflect.TypeOf((*MockStore)(nil).DeleteUserLoginFailures), arg0, arg1)
+}
+
+// DeleteUserLoginLockoutByUserID mocks base method.
+func (m *MockStore) DeleteUserLoginLockoutByUserID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLoginLockoutByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLoginLockoutByUserID indicates an expected call of DeleteUserLoginLockoutByUserID.
+func (mr *MockStoreMockRecorder) DeleteUserLoginLockoutByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLoginLockoutByUserID", reflect.TypeOf((*MockStore)(nil).DeleteUserLoginLockoutByUserID), arg0, arg1)
+}
+
+// DeleteUserTOTPByUserID mocks base method.
+func (m *MockStore) DeleteUserTOTPByUserID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTOTPByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTOTPByUserID indicates an expected call of DeleteUserTOTPByUserID.
+func (mr *MockStoreMockRecorder) DeleteUserTOTPByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTOTPByUserID", reflect.TypeOf((*MockStore)(nil).DeleteUserTOTPByUserID), arg0, arg1)
+}
+
+// DeleteWebhookByID mocks base method.
+func (m *MockStore) DeleteWebhookByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookByID indicates an expected call of DeleteWebhookByID.
+func (mr *MockStoreMockRecorder) DeleteWebhookByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookByID", reflect.TypeOf((*MockStore)(nil).DeleteWebhookByID), arg0, arg1)
+}
+
 // DeleteWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) DeleteWorkspaceAgentPortShare(arg0 context.Context, arg1 database.DeleteWorkspaceAgentPortShareParams) error {
 	m.ctrl.T.Helper()
@@ -556,6 +798,64 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), arg0, arg1)
 }
 
+// DeleteWorkspacePrebuildsOfDeletedWorkspaces mocks base method.
+func (m *MockStore) DeleteWorkspacePrebuildsOfDeletedWorkspaces(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePrebuildsOfDeletedWorkspaces", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePrebuildsOfDeletedWorkspaces indicates an expected call of DeleteWorkspacePrebuildsOfDeletedWorkspaces.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePrebuildsOfDeletedWorkspaces(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePrebuildsOfDeletedWorkspaces", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePrebuildsOfDeletedWorkspaces), arg0)
+}
+
+// DeleteWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) DeleteWorkspaceSnapshotByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceSnapshotByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceSnapshotByID indicates an expected call of DeleteWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceSnapshotByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSnapshotByID), arg0, arg1)
+}
+
+// EnableUserTOTP mocks base method.
+func (m *MockStore) EnableUserTOTP(arg0 context.Context, arg1 database.EnableUserTOTPParams) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTOTP indicates an expected call of EnableUserTOTP.
+func (mr *MockStoreMockRecorder) EnableUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTOTP", reflect.TypeOf((*MockStore)(nil).EnableUserTOTP), arg0, arg1)
+}
+
+// EnqueueNotificationMessage mocks base method.
+func (m *MockStore) EnqueueNotificationMessage(arg0 context.Context, arg1 database.EnqueueNotificationMessageParams) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueNotificationMessage", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueNotificationMessage indicates an expected call of EnqueueNotificationMessage.
+func (mr *MockStoreMockRecorder) EnqueueNotificationMessage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueNotificationMessage", reflect.TypeOf((*MockStore)(nil).EnqueueNotificationMessage), arg0, arg1)
+}
+
 // FavoriteWorkspace mocks base method.
 func (m *MockStore) FavoriteWorkspace(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -645,6 +945,21 @@ func (mr *MockStoreMockRecorder) GetAPIKeysLastUsedAfter(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysLastUsedAfter", reflect.TypeOf((*MockStore)(nil).GetAPIKeysLastUsedAfter), arg0, arg1)
 }
 
+// GetActiveAPIKeysByUserID mocks base method.
+func (m *MockStore) GetActiveAPIKeysByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAPIKeysByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAPIKeysByUserID indicates an expected call of GetActiveAPIKeysByUserID.
+func (mr *MockStoreMockRecorder) GetActiveAPIKeysByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).GetActiveAPIKeysByUserID), arg0, arg1)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -840,6 +1155,36 @@ func (mr *MockStoreMockRecorder) GetAuthorizedWorkspaces(arg0, arg1, arg2 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedWorkspaces", reflect.TypeOf((*MockStore)(nil).GetAuthorizedWorkspaces), arg0, arg1, arg2)
 }
 
+// GetCustomRoleByName mocks base method.
+func (m *MockStore) GetCustomRoleByName(arg0 context.Context, arg1 database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoleByName", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoleByName indicates an expected call of GetCustomRoleByName.
+func (mr *MockStoreMockRecorder) GetCustomRoleByName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoleByName", reflect.TypeOf((*MockStore)(nil).GetCustomRoleByName), arg0, arg1)
+}
+
+// GetCustomRoles mocks base method.
+func (m *MockStore) GetCustomRoles(arg0 context.Context) ([]database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoles", arg0)
+	ret0, _ := ret[0].([]database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoles indicates an expected call of GetCustomRoles.
+func (mr *MockStoreMockRecorder) GetCustomRoles(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoles", reflect.TypeOf((*MockStore)(nil).GetCustomRoles), arg0)
+}
+
 // GetDBCryptKeys mocks base method.
 func (m *MockStore) GetDBCryptKeys(arg0 context.Context) ([]database.DBCryptKey, error) {
 	m.ctrl.T.Helper()
@@ -1155,6 +1500,21 @@ func (mr *MockStoreMockRecorder) GetHungProvisionerJobs(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHungProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetHungProvisionerJobs), arg0, arg1)
 }
 
+// GetImpersonationAPIKeysByUserID mocks base method.
+func (m *MockStore) GetImpersonationAPIKeysByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImpersonationAPIKeysByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImpersonationAPIKeysByUserID indicates an expected call of GetImpersonationAPIKeysByUserID.
+func (mr *MockStoreMockRecorder) GetImpersonationAPIKeysByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImpersonationAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).GetImpersonationAPIKeysByUserID), arg0, arg1)
+}
+
 // GetJFrogXrayScanByWorkspaceAndAgentID mocks base method.
 func (m *MockStore) GetJFrogXrayScanByWorkspaceAndAgentID(arg0 context.Context, arg1 database.GetJFrogXrayScanByWorkspaceAndAgentIDParams) (database.JfrogXrayScan, error) {
 	m.ctrl.T.Helper()
@@ -1260,6 +1620,21 @@ func (mr *MockStoreMockRecorder) GetLicenses(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenses", reflect.TypeOf((*MockStore)(nil).GetLicenses), arg0)
 }
 
+// GetLoginFailureStats mocks base method.
+func (m *MockStore) GetLoginFailureStats(arg0 context.Context, arg1 database.GetLoginFailureStatsParams) (database.GetLoginFailureStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginFailureStats", arg0, arg1)
+	ret0, _ := ret[0].(database.GetLoginFailureStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginFailureStats indicates an expected call of GetLoginFailureStats.
+func (mr *MockStoreMockRecorder) GetLoginFailureStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginFailureStats", reflect.TypeOf((*MockStore)(nil).GetLoginFailureStats), arg0, arg1)
+}
+
 // GetLogoURL mocks base method.
 func (m *MockStore) GetLogoURL(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1275,6 +1650,21 @@ func (mr *MockStoreMockRecorder) GetLogoURL(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
 }
 
+// GetNotificationMessagesByStatus mocks base method.
+func (m *MockStore) GetNotificationMessagesByStatus(arg0 context.Context, arg1 database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationMessagesByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationMessagesByStatus indicates an expected call of GetNotificationMessagesByStatus.
+func (mr *MockStoreMockRecorder) GetNotificationMessagesByStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessagesByStatus", reflect.TypeOf((*MockStore)(nil).GetNotificationMessagesByStatus), arg0, arg1)
+}
+
 // GetOAuth2ProviderAppByID mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
@@ -1425,6 +1815,36 @@ func (mr *MockStoreMockRecorder) GetOAuthSigningKey(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).GetOAuthSigningKey), arg0)
 }
 
+// GetOIDCSyncRuleByID mocks base method.
+func (m *MockStore) GetOIDCSyncRuleByID(arg0 context.Context, arg1 uuid.UUID) (database.OIDCSyncRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCSyncRuleByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OIDCSyncRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCSyncRuleByID indicates an expected call of GetOIDCSyncRuleByID.
+func (mr *MockStoreMockRecorder) GetOIDCSyncRuleByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCSyncRuleByID", reflect.TypeOf((*MockStore)(nil).GetOIDCSyncRuleByID), arg0, arg1)
+}
+
+// GetOIDCSyncRules mocks base method.
+func (m *MockStore) GetOIDCSyncRules(arg0 context.Context) ([]database.OIDCSyncRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOIDCSyncRules", arg0)
+	ret0, _ := ret[0].([]database.OIDCSyncRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOIDCSyncRules indicates an expected call of GetOIDCSyncRules.
+func (mr *MockStoreMockRecorder) GetOIDCSyncRules(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOIDCSyncRules", reflect.TypeOf((*MockStore)(nil).GetOIDCSyncRules), arg0)
+}
+
 // GetOrganizationByID mocks base method.
 func (m *MockStore) GetOrganizationByID(arg0 context.Context, arg1 uuid.UUID) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -1545,6 +1965,36 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), arg0, arg1)
 }
 
+// GetPendingProvisionerJobEngineVersionConstraints mocks base method.
+func (m *MockStore) GetPendingProvisionerJobEngineVersionConstraints(arg0 context.Context, arg1 uuid.UUID) ([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingProvisionerJobEngineVersionConstraints", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetPendingProvisionerJobEngineVersionConstraintsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingProvisionerJobEngineVersionConstraints indicates an expected call of GetPendingProvisionerJobEngineVersionConstraints.
+func (mr *MockStoreMockRecorder) GetPendingProvisionerJobEngineVersionConstraints(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingProvisionerJobEngineVersionConstraints", reflect.TypeOf((*MockStore)(nil).GetPendingProvisionerJobEngineVersionConstraints), arg0, arg1)
+}
+
+// GetPrebuildPresets mocks base method.
+func (m *MockStore) GetPrebuildPresets(arg0 context.Context) ([]database.GetPrebuildPresetsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrebuildPresets", arg0)
+	ret0, _ := ret[0].([]database.GetPrebuildPresetsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrebuildPresets indicates an expected call of GetPrebuildPresets.
+func (mr *MockStoreMockRecorder) GetPrebuildPresets(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrebuildPresets", reflect.TypeOf((*MockStore)(nil).GetPrebuildPresets), arg0)
+}
+
 // GetPreviousTemplateVersion mocks base method.
 func (m *MockStore) GetPreviousTemplateVersion(arg0 context.Context, arg1 database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -1575,11 +2025,26 @@ func (mr *MockStoreMockRecorder) GetProvisionerDaemons(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).GetProvisionerDaemons), arg0)
 }
 
-// GetProvisionerJobByID mocks base method.
-func (m *MockStore) GetProvisionerJobByID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerJob, error) {
+// GetProvisionerDaemonsByOrganization mocks base method.
+func (m *MockStore) GetProvisionerDaemonsByOrganization(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProvisionerJobByID", arg0, arg1)
-	ret0, _ := ret[0].(database.ProvisionerJob)
+	ret := m.ctrl.Call(m, "GetProvisionerDaemonsByOrganization", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerDaemon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerDaemonsByOrganization indicates an expected call of GetProvisionerDaemonsByOrganization.
+func (mr *MockStoreMockRecorder) GetProvisionerDaemonsByOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerDaemonsByOrganization", reflect.TypeOf((*MockStore)(nil).GetProvisionerDaemonsByOrganization), arg0, arg1)
+}
+
+// GetProvisionerJobByID mocks base method.
+func (m *MockStore) GetProvisionerJobByID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobByID", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1651,7 +2116,7 @@ func (mr *MockStoreMockRecorder) GetProvisionerLogsAfterID(arg0, arg1 any) *gomo
 }
 
 // GetQuotaAllowanceForUser mocks base method.
-func (m *MockStore) GetQuotaAllowanceForUser(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+func (m *MockStore) GetQuotaAllowanceForUser(arg0 context.Context, arg1 database.GetQuotaAllowanceForUserParams) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetQuotaAllowanceForUser", arg0, arg1)
 	ret0, _ := ret[0].(int64)
@@ -1666,7 +2131,7 @@ func (mr *MockStoreMockRecorder) GetQuotaAllowanceForUser(arg0, arg1 any) *gomoc
 }
 
 // GetQuotaConsumedForUser mocks base method.
-func (m *MockStore) GetQuotaConsumedForUser(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+func (m *MockStore) GetQuotaConsumedForUser(arg0 context.Context, arg1 database.GetQuotaConsumedForUserParams) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetQuotaConsumedForUser", arg0, arg1)
 	ret0, _ := ret[0].(int64)
@@ -1710,6 +2175,66 @@ func (mr *MockStoreMockRecorder) GetReplicasUpdatedAfter(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicasUpdatedAfter", reflect.TypeOf((*MockStore)(nil).GetReplicasUpdatedAfter), arg0, arg1)
 }
 
+// GetRunningWorkspaceBulkOperations mocks base method.
+func (m *MockStore) GetRunningWorkspaceBulkOperations(arg0 context.Context) ([]database.WorkspaceBulkOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningWorkspaceBulkOperations", arg0)
+	ret0, _ := ret[0].([]database.WorkspaceBulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningWorkspaceBulkOperations indicates an expected call of GetRunningWorkspaceBulkOperations.
+func (mr *MockStoreMockRecorder) GetRunningWorkspaceBulkOperations(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningWorkspaceBulkOperations", reflect.TypeOf((*MockStore)(nil).GetRunningWorkspaceBulkOperations), arg0)
+}
+
+// GetSCIMTokenByID mocks base method.
+func (m *MockStore) GetSCIMTokenByID(arg0 context.Context, arg1 uuid.UUID) (database.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMTokenByID", arg0, arg1)
+	ret0, _ := ret[0].(database.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMTokenByID indicates an expected call of GetSCIMTokenByID.
+func (mr *MockStoreMockRecorder) GetSCIMTokenByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMTokenByID", reflect.TypeOf((*MockStore)(nil).GetSCIMTokenByID), arg0, arg1)
+}
+
+// GetSCIMTokenByPrefix mocks base method.
+func (m *MockStore) GetSCIMTokenByPrefix(arg0 context.Context, arg1 []byte) (database.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMTokenByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(database.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMTokenByPrefix indicates an expected call of GetSCIMTokenByPrefix.
+func (mr *MockStoreMockRecorder) GetSCIMTokenByPrefix(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMTokenByPrefix", reflect.TypeOf((*MockStore)(nil).GetSCIMTokenByPrefix), arg0, arg1)
+}
+
+// GetSCIMTokens mocks base method.
+func (m *MockStore) GetSCIMTokens(arg0 context.Context) ([]database.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMTokens", arg0)
+	ret0, _ := ret[0].([]database.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMTokens indicates an expected call of GetSCIMTokens.
+func (mr *MockStoreMockRecorder) GetSCIMTokens(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMTokens", reflect.TypeOf((*MockStore)(nil).GetSCIMTokens), arg0)
+}
+
 // GetServiceBanner mocks base method.
 func (m *MockStore) GetServiceBanner(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1800,6 +2325,36 @@ func (mr *MockStoreMockRecorder) GetTailnetTunnelPeerIDs(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTailnetTunnelPeerIDs", reflect.TypeOf((*MockStore)(nil).GetTailnetTunnelPeerIDs), arg0, arg1)
 }
 
+// GetTemplateActiveVersionForUser mocks base method.
+func (m *MockStore) GetTemplateActiveVersionForUser(arg0 context.Context, arg1 database.GetTemplateActiveVersionForUserParams) (database.GetTemplateActiveVersionForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateActiveVersionForUser", arg0, arg1)
+	ret0, _ := ret[0].(database.GetTemplateActiveVersionForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateActiveVersionForUser indicates an expected call of GetTemplateActiveVersionForUser.
+func (mr *MockStoreMockRecorder) GetTemplateActiveVersionForUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateActiveVersionForUser", reflect.TypeOf((*MockStore)(nil).GetTemplateActiveVersionForUser), arg0, arg1)
+}
+
+// GetTemplateActiveVersionsByWorkspaceIDs mocks base method.
+func (m *MockStore) GetTemplateActiveVersionsByWorkspaceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetTemplateActiveVersionsByWorkspaceIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateActiveVersionsByWorkspaceIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetTemplateActiveVersionsByWorkspaceIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateActiveVersionsByWorkspaceIDs indicates an expected call of GetTemplateActiveVersionsByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetTemplateActiveVersionsByWorkspaceIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateActiveVersionsByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetTemplateActiveVersionsByWorkspaceIDs), arg0, arg1)
+}
+
 // GetTemplateAppInsights mocks base method.
 func (m *MockStore) GetTemplateAppInsights(arg0 context.Context, arg1 database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -1965,6 +2520,51 @@ func (mr *MockStoreMockRecorder) GetTemplateParameterInsights(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateParameterInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateParameterInsights), arg0, arg1)
 }
 
+// GetTemplateReleaseChannelByID mocks base method.
+func (m *MockStore) GetTemplateReleaseChannelByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateReleaseChannelByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateReleaseChannelByID indicates an expected call of GetTemplateReleaseChannelByID.
+func (mr *MockStoreMockRecorder) GetTemplateReleaseChannelByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateReleaseChannelByID", reflect.TypeOf((*MockStore)(nil).GetTemplateReleaseChannelByID), arg0, arg1)
+}
+
+// GetTemplateReleaseChannelByTemplateIDAndName mocks base method.
+func (m *MockStore) GetTemplateReleaseChannelByTemplateIDAndName(arg0 context.Context, arg1 database.GetTemplateReleaseChannelByTemplateIDAndNameParams) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateReleaseChannelByTemplateIDAndName", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateReleaseChannelByTemplateIDAndName indicates an expected call of GetTemplateReleaseChannelByTemplateIDAndName.
+func (mr *MockStoreMockRecorder) GetTemplateReleaseChannelByTemplateIDAndName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateReleaseChannelByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).GetTemplateReleaseChannelByTemplateIDAndName), arg0, arg1)
+}
+
+// GetTemplateReleaseChannelsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateReleaseChannelsByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateReleaseChannelsByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateReleaseChannelsByTemplateID indicates an expected call of GetTemplateReleaseChannelsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateReleaseChannelsByTemplateID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateReleaseChannelsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateReleaseChannelsByTemplateID), arg0, arg1)
+}
+
 // GetTemplateUsageStats mocks base method.
 func (m *MockStore) GetTemplateUsageStats(arg0 context.Context, arg1 database.GetTemplateUsageStatsParams) ([]database.TemplateUsageStat, error) {
 	m.ctrl.T.Helper()
@@ -2040,6 +2640,21 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionByTemplateIDAndName(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionByTemplateIDAndName", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionByTemplateIDAndName), arg0, arg1)
 }
 
+// GetTemplateVersionDiagnostics mocks base method.
+func (m *MockStore) GetTemplateVersionDiagnostics(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionDiagnostic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionDiagnostics", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionDiagnostic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionDiagnostics indicates an expected call of GetTemplateVersionDiagnostics.
+func (mr *MockStoreMockRecorder) GetTemplateVersionDiagnostics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionDiagnostics", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionDiagnostics), arg0, arg1)
+}
+
 // GetTemplateVersionParameters mocks base method.
 func (m *MockStore) GetTemplateVersionParameters(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionParameter, error) {
 	m.ctrl.T.Helper()
@@ -2055,6 +2670,66 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), arg0, arg1)
 }
 
+// GetTemplateVersionPresetByID mocks base method.
+func (m *MockStore) GetTemplateVersionPresetByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresetByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresetByID indicates an expected call of GetTemplateVersionPresetByID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresetByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresetByID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresetByID), arg0, arg1)
+}
+
+// GetTemplateVersionPresetParameters mocks base method.
+func (m *MockStore) GetTemplateVersionPresetParameters(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresetParameters", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPresetParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresetParameters indicates an expected call of GetTemplateVersionPresetParameters.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresetParameters(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresetParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresetParameters), arg0, arg1)
+}
+
+// GetTemplateVersionPresetParametersByPresetID mocks base method.
+func (m *MockStore) GetTemplateVersionPresetParametersByPresetID(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionPresetParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresetParametersByPresetID", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPresetParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresetParametersByPresetID indicates an expected call of GetTemplateVersionPresetParametersByPresetID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresetParametersByPresetID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresetParametersByPresetID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresetParametersByPresetID), arg0, arg1)
+}
+
+// GetTemplateVersionPresets mocks base method.
+func (m *MockStore) GetTemplateVersionPresets(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresets", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresets indicates an expected call of GetTemplateVersionPresets.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresets(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresets", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresets), arg0, arg1)
+}
+
 // GetTemplateVersionVariables mocks base method.
 func (m *MockStore) GetTemplateVersionVariables(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -2280,6 +2955,66 @@ func (mr *MockStoreMockRecorder) GetUserLinksByUserID(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), arg0, arg1)
 }
 
+// GetUserLoginHistorySummary mocks base method.
+func (m *MockStore) GetUserLoginHistorySummary(arg0 context.Context, arg1 database.GetUserLoginHistorySummaryParams) (database.GetUserLoginHistorySummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLoginHistorySummary", arg0, arg1)
+	ret0, _ := ret[0].(database.GetUserLoginHistorySummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLoginHistorySummary indicates an expected call of GetUserLoginHistorySummary.
+func (mr *MockStoreMockRecorder) GetUserLoginHistorySummary(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginHistorySummary", reflect.TypeOf((*MockStore)(nil).GetUserLoginHistorySummary), arg0, arg1)
+}
+
+// GetUserLoginLockoutByUserID mocks base method.
+func (m *MockStore) GetUserLoginLockoutByUserID(arg0 context.Context, arg1 uuid.UUID) (database.UserLoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLoginLockoutByUserID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLoginLockoutByUserID indicates an expected call of GetUserLoginLockoutByUserID.
+func (mr *MockStoreMockRecorder) GetUserLoginLockoutByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLoginLockoutByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLoginLockoutByUserID), arg0, arg1)
+}
+
+// GetUserNotificationPreferences mocks base method.
+func (m *MockStore) GetUserNotificationPreferences(arg0 context.Context, arg1 uuid.UUID) ([]database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationPreferences indicates an expected call of GetUserNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetUserNotificationPreferences(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetUserNotificationPreferences), arg0, arg1)
+}
+
+// GetUserTOTPByUserID mocks base method.
+func (m *MockStore) GetUserTOTPByUserID(arg0 context.Context, arg1 uuid.UUID) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTOTPByUserID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTOTPByUserID indicates an expected call of GetUserTOTPByUserID.
+func (mr *MockStoreMockRecorder) GetUserTOTPByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTOTPByUserID", reflect.TypeOf((*MockStore)(nil).GetUserTOTPByUserID), arg0, arg1)
+}
+
 // GetUserWorkspaceBuildParameters mocks base method.
 func (m *MockStore) GetUserWorkspaceBuildParameters(arg0 context.Context, arg1 database.GetUserWorkspaceBuildParametersParams) ([]database.GetUserWorkspaceBuildParametersRow, error) {
 	m.ctrl.T.Helper()
@@ -2325,6 +3060,66 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), arg0, arg1)
 }
 
+// GetWebhookByID mocks base method.
+func (m *MockStore) GetWebhookByID(arg0 context.Context, arg1 uuid.UUID) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockStoreMockRecorder) GetWebhookByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockStore)(nil).GetWebhookByID), arg0, arg1)
+}
+
+// GetWebhookDeliveriesByWebhookID mocks base method.
+func (m *MockStore) GetWebhookDeliveriesByWebhookID(arg0 context.Context, arg1 database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesByWebhookID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesByWebhookID indicates an expected call of GetWebhookDeliveriesByWebhookID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveriesByWebhookID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByWebhookID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveriesByWebhookID), arg0, arg1)
+}
+
+// GetWebhookDeliveryByID mocks base method.
+func (m *MockStore) GetWebhookDeliveryByID(arg0 context.Context, arg1 uuid.UUID) (database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryByID indicates an expected call of GetWebhookDeliveryByID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveryByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryByID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveryByID), arg0, arg1)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks(arg0 context.Context) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", arg0)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks), arg0)
+}
+
 // GetWorkspaceAgentAndLatestBuildByAuthToken mocks base method.
 func (m *MockStore) GetWorkspaceAgentAndLatestBuildByAuthToken(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error) {
 	m.ctrl.T.Helper()
@@ -2655,6 +3450,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildParameters(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildParameters), arg0, arg1)
 }
 
+// GetWorkspaceBuildStatesByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceBuildStatesByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.GetWorkspaceBuildStatesByWorkspaceIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildStatesByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceBuildStatesByWorkspaceIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildStatesByWorkspaceID indicates an expected call of GetWorkspaceBuildStatesByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildStatesByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildStatesByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildStatesByWorkspaceID), arg0, arg1)
+}
+
 // GetWorkspaceBuildsByWorkspaceID mocks base method.
 func (m *MockStore) GetWorkspaceBuildsByWorkspaceID(arg0 context.Context, arg1 database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -2685,6 +3495,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildsCreatedAfter(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildsCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceBulkOperationByID mocks base method.
+func (m *MockStore) GetWorkspaceBulkOperationByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceBulkOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBulkOperationByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBulkOperationByID indicates an expected call of GetWorkspaceBulkOperationByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBulkOperationByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBulkOperationByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBulkOperationByID), arg0, arg1)
+}
+
+// GetWorkspaceBulkOperationWorkspaces mocks base method.
+func (m *MockStore) GetWorkspaceBulkOperationWorkspaces(arg0 context.Context, arg1 uuid.UUID) ([]database.GetWorkspaceBulkOperationWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBulkOperationWorkspaces", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceBulkOperationWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBulkOperationWorkspaces indicates an expected call of GetWorkspaceBulkOperationWorkspaces.
+func (mr *MockStoreMockRecorder) GetWorkspaceBulkOperationWorkspaces(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBulkOperationWorkspaces", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBulkOperationWorkspaces), arg0, arg1)
+}
+
 // GetWorkspaceByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceByAgentID(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceByAgentIDRow, error) {
 	m.ctrl.T.Helper()
@@ -2745,6 +3585,66 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), arg0, arg1)
 }
 
+// GetWorkspaceCountForTemplate mocks base method.
+func (m *MockStore) GetWorkspaceCountForTemplate(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCountForTemplate", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCountForTemplate indicates an expected call of GetWorkspaceCountForTemplate.
+func (mr *MockStoreMockRecorder) GetWorkspaceCountForTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCountForTemplate", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCountForTemplate), arg0, arg1)
+}
+
+// GetWorkspaceCountForUser mocks base method.
+func (m *MockStore) GetWorkspaceCountForUser(arg0 context.Context, arg1 database.GetWorkspaceCountForUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCountForUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCountForUser indicates an expected call of GetWorkspaceCountForUser.
+func (mr *MockStoreMockRecorder) GetWorkspaceCountForUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCountForUser", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCountForUser), arg0, arg1)
+}
+
+// GetWorkspaceLimitForUser mocks base method.
+func (m *MockStore) GetWorkspaceLimitForUser(arg0 context.Context, arg1 database.GetWorkspaceLimitForUserParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceLimitForUser", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceLimitForUser indicates an expected call of GetWorkspaceLimitForUser.
+func (mr *MockStoreMockRecorder) GetWorkspaceLimitForUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceLimitForUser", reflect.TypeOf((*MockStore)(nil).GetWorkspaceLimitForUser), arg0, arg1)
+}
+
+// GetWorkspacePrebuilds mocks base method.
+func (m *MockStore) GetWorkspacePrebuilds(arg0 context.Context) ([]database.GetWorkspacePrebuildsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePrebuilds", arg0)
+	ret0, _ := ret[0].([]database.GetWorkspacePrebuildsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePrebuilds indicates an expected call of GetWorkspacePrebuilds.
+func (mr *MockStoreMockRecorder) GetWorkspacePrebuilds(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePrebuilds", reflect.TypeOf((*MockStore)(nil).GetWorkspacePrebuilds), arg0)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(arg0 context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2895,6 +3795,81 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceSnapshotByBuildID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByBuildID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByBuildID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByBuildID indicates an expected call of GetWorkspaceSnapshotByBuildID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByBuildID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByBuildID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByBuildID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByID indicates an expected call of GetWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotByWorkspaceIDAndName mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByWorkspaceIDAndName(arg0 context.Context, arg1 database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByWorkspaceIDAndName", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByWorkspaceIDAndName indicates an expected call of GetWorkspaceSnapshotByWorkspaceIDAndName.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByWorkspaceIDAndName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByWorkspaceIDAndName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByWorkspaceIDAndName), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotRestoreByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotRestoreByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSnapshotRestore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotRestoreByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshotRestore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotRestoreByWorkspaceID indicates an expected call of GetWorkspaceSnapshotRestoreByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotRestoreByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotRestoreByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotRestoreByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID indicates an expected call of GetWorkspaceSnapshotsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotsByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotsByWorkspaceID), arg0, arg1)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -2999,6 +3974,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), arg0, arg1)
 }
 
+// InsertCustomRole mocks base method.
+func (m *MockStore) InsertCustomRole(arg0 context.Context, arg1 database.InsertCustomRoleParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCustomRole", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCustomRole indicates an expected call of InsertCustomRole.
+func (mr *MockStoreMockRecorder) InsertCustomRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCustomRole", reflect.TypeOf((*MockStore)(nil).InsertCustomRole), arg0, arg1)
+}
+
 // InsertDBCryptKey mocks base method.
 func (m *MockStore) InsertDBCryptKey(arg0 context.Context, arg1 database.InsertDBCryptKeyParams) error {
 	m.ctrl.T.Helper()
@@ -3205,6 +4195,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppToken(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppToken", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppToken), arg0, arg1)
 }
 
+// InsertOIDCSyncRule mocks base method.
+func (m *MockStore) InsertOIDCSyncRule(arg0 context.Context, arg1 database.InsertOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOIDCSyncRule", arg0, arg1)
+	ret0, _ := ret[0].(database.OIDCSyncRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOIDCSyncRule indicates an expected call of InsertOIDCSyncRule.
+func (mr *MockStoreMockRecorder) InsertOIDCSyncRule(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOIDCSyncRule", reflect.TypeOf((*MockStore)(nil).InsertOIDCSyncRule), arg0, arg1)
+}
+
 // InsertOrganization mocks base method.
 func (m *MockStore) InsertOrganization(arg0 context.Context, arg1 database.InsertOrganizationParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -3280,6 +4285,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), arg0, arg1)
 }
 
+// InsertSCIMToken mocks base method.
+func (m *MockStore) InsertSCIMToken(arg0 context.Context, arg1 database.InsertSCIMTokenParams) (database.SCIMToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSCIMToken", arg0, arg1)
+	ret0, _ := ret[0].(database.SCIMToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSCIMToken indicates an expected call of InsertSCIMToken.
+func (mr *MockStoreMockRecorder) InsertSCIMToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSCIMToken", reflect.TypeOf((*MockStore)(nil).InsertSCIMToken), arg0, arg1)
+}
+
 // InsertTemplate mocks base method.
 func (m *MockStore) InsertTemplate(arg0 context.Context, arg1 database.InsertTemplateParams) error {
 	m.ctrl.T.Helper()
@@ -3294,6 +4314,21 @@ func (mr *MockStoreMockRecorder) InsertTemplate(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), arg0, arg1)
 }
 
+// InsertTemplateReleaseChannel mocks base method.
+func (m *MockStore) InsertTemplateReleaseChannel(arg0 context.Context, arg1 database.InsertTemplateReleaseChannelParams) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateReleaseChannel", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateReleaseChannel indicates an expected call of InsertTemplateReleaseChannel.
+func (mr *MockStoreMockRecorder) InsertTemplateReleaseChannel(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateReleaseChannel", reflect.TypeOf((*MockStore)(nil).InsertTemplateReleaseChannel), arg0, arg1)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(arg0 context.Context, arg1 database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -3308,6 +4343,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersion(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersion", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersion), arg0, arg1)
 }
 
+// InsertTemplateVersionDiagnostic mocks base method.
+func (m *MockStore) InsertTemplateVersionDiagnostic(arg0 context.Context, arg1 database.InsertTemplateVersionDiagnosticParams) (database.TemplateVersionDiagnostic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionDiagnostic", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionDiagnostic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionDiagnostic indicates an expected call of InsertTemplateVersionDiagnostic.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionDiagnostic(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionDiagnostic", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionDiagnostic), arg0, arg1)
+}
+
 // InsertTemplateVersionParameter mocks base method.
 func (m *MockStore) InsertTemplateVersionParameter(arg0 context.Context, arg1 database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
 	m.ctrl.T.Helper()
@@ -3323,6 +4373,36 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionParameter(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionParameter", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionParameter), arg0, arg1)
 }
 
+// InsertTemplateVersionPreset mocks base method.
+func (m *MockStore) InsertTemplateVersionPreset(arg0 context.Context, arg1 database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionPreset", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionPreset indicates an expected call of InsertTemplateVersionPreset.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionPreset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionPreset", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionPreset), arg0, arg1)
+}
+
+// InsertTemplateVersionPresetParameters mocks base method.
+func (m *MockStore) InsertTemplateVersionPresetParameters(arg0 context.Context, arg1 database.InsertTemplateVersionPresetParametersParams) ([]database.TemplateVersionPresetParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionPresetParameters", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPresetParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionPresetParameters indicates an expected call of InsertTemplateVersionPresetParameters.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionPresetParameters(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionPresetParameters", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionPresetParameters), arg0, arg1)
+}
+
 // InsertTemplateVersionVariable mocks base method.
 func (m *MockStore) InsertTemplateVersionVariable(arg0 context.Context, arg1 database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -3382,6 +4462,50 @@ func (mr *MockStoreMockRecorder) InsertUserLink(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), arg0, arg1)
 }
 
+// InsertUserLoginAttempt mocks base method.
+func (m *MockStore) InsertUserLoginAttempt(arg0 context.Context, arg1 database.InsertUserLoginAttemptParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserLoginAttempt indicates an expected call of InsertUserLoginAttempt.
+func (mr *MockStoreMockRecorder) InsertUserLoginAttempt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLoginAttempt", reflect.TypeOf((*MockStore)(nil).InsertUserLoginAttempt), arg0, arg1)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockStore) InsertWebhook(arg0 context.Context, arg1 database.InsertWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockStoreMockRecorder) InsertWebhook(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockStore)(nil).InsertWebhook), arg0, arg1)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockStore) InsertWebhookDelivery(arg0 context.Context, arg1 database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockStoreMockRecorder) InsertWebhookDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockStore)(nil).InsertWebhookDelivery), arg0, arg1)
+}
+
 // InsertWorkspace mocks base method.
 func (m *MockStore) InsertWorkspace(arg0 context.Context, arg1 database.InsertWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -3557,6 +4681,49 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), arg0, arg1)
 }
 
+// InsertWorkspaceBulkOperation mocks base method.
+func (m *MockStore) InsertWorkspaceBulkOperation(arg0 context.Context, arg1 database.InsertWorkspaceBulkOperationParams) (database.WorkspaceBulkOperation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBulkOperation", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBulkOperation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBulkOperation indicates an expected call of InsertWorkspaceBulkOperation.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBulkOperation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBulkOperation", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBulkOperation), arg0, arg1)
+}
+
+// InsertWorkspaceBulkOperationWorkspaces mocks base method.
+func (m *MockStore) InsertWorkspaceBulkOperationWorkspaces(arg0 context.Context, arg1 database.InsertWorkspaceBulkOperationWorkspacesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBulkOperationWorkspaces", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceBulkOperationWorkspaces indicates an expected call of InsertWorkspaceBulkOperationWorkspaces.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBulkOperationWorkspaces(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBulkOperationWorkspaces", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBulkOperationWorkspaces), arg0, arg1)
+}
+
+// InsertWorkspacePrebuild mocks base method.
+func (m *MockStore) InsertWorkspacePrebuild(arg0 context.Context, arg1 database.InsertWorkspacePrebuildParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspacePrebuild", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspacePrebuild indicates an expected call of InsertWorkspacePrebuild.
+func (mr *MockStoreMockRecorder) InsertWorkspacePrebuild(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspacePrebuild", reflect.TypeOf((*MockStore)(nil).InsertWorkspacePrebuild), arg0, arg1)
+}
+
 // InsertWorkspaceProxy mocks base method.
 func (m *MockStore) InsertWorkspaceProxy(arg0 context.Context, arg1 database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -3602,6 +4769,36 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceSnapshot mocks base method.
+func (m *MockStore) InsertWorkspaceSnapshot(arg0 context.Context, arg1 database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSnapshot indicates an expected call of InsertWorkspaceSnapshot.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSnapshot(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSnapshot", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSnapshot), arg0, arg1)
+}
+
+// InsertWorkspaceSnapshotRestore mocks base method.
+func (m *MockStore) InsertWorkspaceSnapshotRestore(arg0 context.Context, arg1 database.InsertWorkspaceSnapshotRestoreParams) (database.WorkspaceSnapshotRestore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSnapshotRestore", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshotRestore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSnapshotRestore indicates an expected call of InsertWorkspaceSnapshotRestore.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSnapshotRestore(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSnapshotRestore", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSnapshotRestore), arg0, arg1)
+}
+
 // ListWorkspaceAgentPortShares mocks base method.
 func (m *MockStore) ListWorkspaceAgentPortShares(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -3617,6 +4814,62 @@ func (mr *MockStoreMockRecorder) ListWorkspaceAgentPortShares(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceAgentPortShares", reflect.TypeOf((*MockStore)(nil).ListWorkspaceAgentPortShares), arg0, arg1)
 }
 
+// MarkNotificationMessageFailed mocks base method.
+func (m *MockStore) MarkNotificationMessageFailed(arg0 context.Context, arg1 database.MarkNotificationMessageFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationMessageFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationMessageFailed indicates an expected call of MarkNotificationMessageFailed.
+func (mr *MockStoreMockRecorder) MarkNotificationMessageFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationMessageFailed", reflect.TypeOf((*MockStore)(nil).MarkNotificationMessageFailed), arg0, arg1)
+}
+
+// MarkNotificationMessageSent mocks base method.
+func (m *MockStore) MarkNotificationMessageSent(arg0 context.Context, arg1 database.MarkNotificationMessageSentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationMessageSent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationMessageSent indicates an expected call of MarkNotificationMessageSent.
+func (mr *MockStoreMockRecorder) MarkNotificationMessageSent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationMessageSent", reflect.TypeOf((*MockStore)(nil).MarkNotificationMessageSent), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 database.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// MarkWebhookDeliverySucceeded mocks base method.
+func (m *MockStore) MarkWebhookDeliverySucceeded(arg0 context.Context, arg1 database.MarkWebhookDeliverySucceededParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliverySucceeded", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliverySucceeded indicates an expected call of MarkWebhookDeliverySucceeded.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliverySucceeded(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliverySucceeded", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliverySucceeded), arg0, arg1)
+}
+
 // Ping mocks base method.
 func (m *MockStore) Ping(arg0 context.Context) (time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -3746,6 +4999,21 @@ func (mr *MockStoreMockRecorder) UpdateAPIKeyByID(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyByID", reflect.TypeOf((*MockStore)(nil).UpdateAPIKeyByID), arg0, arg1)
 }
 
+// UpdateCustomRole mocks base method.
+func (m *MockStore) UpdateCustomRole(arg0 context.Context, arg1 database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomRole", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCustomRole indicates an expected call of UpdateCustomRole.
+func (mr *MockStoreMockRecorder) UpdateCustomRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomRole", reflect.TypeOf((*MockStore)(nil).UpdateCustomRole), arg0, arg1)
+}
+
 // UpdateExternalAuthLink mocks base method.
 func (m *MockStore) UpdateExternalAuthLink(arg0 context.Context, arg1 database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -3851,6 +5119,21 @@ func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppSecretByID(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppSecretByID), arg0, arg1)
 }
 
+// UpdateOIDCSyncRule mocks base method.
+func (m *MockStore) UpdateOIDCSyncRule(arg0 context.Context, arg1 database.UpdateOIDCSyncRuleParams) (database.OIDCSyncRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOIDCSyncRule", arg0, arg1)
+	ret0, _ := ret[0].(database.OIDCSyncRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOIDCSyncRule indicates an expected call of UpdateOIDCSyncRule.
+func (mr *MockStoreMockRecorder) UpdateOIDCSyncRule(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOIDCSyncRule", reflect.TypeOf((*MockStore)(nil).UpdateOIDCSyncRule), arg0, arg1)
+}
+
 // UpdateProvisionerDaemonLastSeenAt mocks base method.
 func (m *MockStore) UpdateProvisionerDaemonLastSeenAt(arg0 context.Context, arg1 database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	m.ctrl.T.Helper()
@@ -3879,6 +5162,20 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobByID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobByID), arg0, arg1)
 }
 
+// UpdateProvisionerJobEngineVersionConstraintByID mocks base method.
+func (m *MockStore) UpdateProvisionerJobEngineVersionConstraintByID(arg0 context.Context, arg1 database.UpdateProvisionerJobEngineVersionConstraintByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerJobEngineVersionConstraintByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerJobEngineVersionConstraintByID indicates an expected call of UpdateProvisionerJobEngineVersionConstraintByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerJobEngineVersionConstraintByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobEngineVersionConstraintByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobEngineVersionConstraintByID), arg0, arg1)
+}
+
 // UpdateProvisionerJobWithCancelByID mocks base method.
 func (m *MockStore) UpdateProvisionerJobWithCancelByID(arg0 context.Context, arg1 database.UpdateProvisionerJobWithCancelByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3922,6 +5219,20 @@ func (mr *MockStoreMockRecorder) UpdateReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), arg0, arg1)
 }
 
+// UpdateSCIMTokenLastUsedAt mocks base method.
+func (m *MockStore) UpdateSCIMTokenLastUsedAt(arg0 context.Context, arg1 database.UpdateSCIMTokenLastUsedAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSCIMTokenLastUsedAt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSCIMTokenLastUsedAt indicates an expected call of UpdateSCIMTokenLastUsedAt.
+func (mr *MockStoreMockRecorder) UpdateSCIMTokenLastUsedAt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSCIMTokenLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateSCIMTokenLastUsedAt), arg0, arg1)
+}
+
 // UpdateTemplateACLByID mocks base method.
 func (m *MockStore) UpdateTemplateACLByID(arg0 context.Context, arg1 database.UpdateTemplateACLByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3992,6 +5303,21 @@ func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), arg0, arg1)
 }
 
+// UpdateTemplateReleaseChannelByID mocks base method.
+func (m *MockStore) UpdateTemplateReleaseChannelByID(arg0 context.Context, arg1 database.UpdateTemplateReleaseChannelByIDParams) (database.TemplateReleaseChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateReleaseChannelByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateReleaseChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateReleaseChannelByID indicates an expected call of UpdateTemplateReleaseChannelByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateReleaseChannelByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateReleaseChannelByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateReleaseChannelByID), arg0, arg1)
+}
+
 // UpdateTemplateScheduleByID mocks base method.
 func (m *MockStore) UpdateTemplateScheduleByID(arg0 context.Context, arg1 database.UpdateTemplateScheduleByIDParams) error {
 	m.ctrl.T.Helper()
@@ -4225,6 +5551,36 @@ func (mr *MockStoreMockRecorder) UpdateUserStatus(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockStore)(nil).UpdateUserStatus), arg0, arg1)
 }
 
+// UpdateUserTOTPLastUsedStep mocks base method.
+func (m *MockStore) UpdateUserTOTPLastUsedStep(arg0 context.Context, arg1 database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPLastUsedStep", arg0, arg1)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTOTPLastUsedStep indicates an expected call of UpdateUserTOTPLastUsedStep.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPLastUsedStep(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPLastUsedStep", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPLastUsedStep), arg0, arg1)
+}
+
+// UpdateWebhookByID mocks base method.
+func (m *MockStore) UpdateWebhookByID(arg0 context.Context, arg1 database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookByID indicates an expected call of UpdateWebhookByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookByID), arg0, arg1)
+}
+
 // UpdateWorkspace mocks base method.
 func (m *MockStore) UpdateWorkspace(arg0 context.Context, arg1 database.UpdateWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -4240,6 +5596,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), arg0, arg1)
 }
 
+// UpdateWorkspaceACLByID mocks base method.
+func (m *MockStore) UpdateWorkspaceACLByID(arg0 context.Context, arg1 database.UpdateWorkspaceACLByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceACLByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceACLByID indicates an expected call of UpdateWorkspaceACLByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceACLByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceACLByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -4394,6 +5764,34 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildProvisionerStateByID(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildProvisionerStateByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildProvisionerStateByID), arg0, arg1)
 }
 
+// UpdateWorkspaceBulkOperationStatus mocks base method.
+func (m *MockStore) UpdateWorkspaceBulkOperationStatus(arg0 context.Context, arg1 database.UpdateWorkspaceBulkOperationStatusParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBulkOperationStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceBulkOperationStatus indicates an expected call of UpdateWorkspaceBulkOperationStatus.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBulkOperationStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBulkOperationStatus", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBulkOperationStatus), arg0, arg1)
+}
+
+// UpdateWorkspaceBulkOperationWorkspace mocks base method.
+func (m *MockStore) UpdateWorkspaceBulkOperationWorkspace(arg0 context.Context, arg1 database.UpdateWorkspaceBulkOperationWorkspaceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBulkOperationWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceBulkOperationWorkspace indicates an expected call of UpdateWorkspaceBulkOperationWorkspace.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBulkOperationWorkspace(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBulkOperationWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBulkOperationWorkspace), arg0, arg1)
+}
+
 // UpdateWorkspaceDeletedByID mocks base method.
 func (m *MockStore) UpdateWorkspaceDeletedByID(arg0 context.Context, arg1 database.UpdateWorkspaceDeletedByIDParams) error {
 	m.ctrl.T.Helper()
@@ -4437,6 +5835,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceLastUsedAt(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceLastUsedAt), arg0, arg1)
 }
 
+// UpdateWorkspaceOwnerByID mocks base method.
+func (m *MockStore) UpdateWorkspaceOwnerByID(arg0 context.Context, arg1 database.UpdateWorkspaceOwnerByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceOwnerByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceOwnerByID indicates an expected call of UpdateWorkspaceOwnerByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceOwnerByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceOwnerByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceOwnerByID), arg0, arg1)
+}
+
 // UpdateWorkspaceProxy mocks base method.
 func (m *MockStore) UpdateWorkspaceProxy(arg0 context.Context, arg1 database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -4466,6 +5878,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyDeleted(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyDeleted", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyDeleted), arg0, arg1)
 }
 
+// UpdateWorkspaceSnapshotArtifactsByID mocks base method.
+func (m *MockStore) UpdateWorkspaceSnapshotArtifactsByID(arg0 context.Context, arg1 database.UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceSnapshotArtifactsByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceSnapshotArtifactsByID indicates an expected call of UpdateWorkspaceSnapshotArtifactsByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceSnapshotArtifactsByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceSnapshotArtifactsByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceSnapshotArtifactsByID), arg0, arg1)
+}
+
 // UpdateWorkspaceTTL mocks base method.
 func (m *MockStore) UpdateWorkspaceTTL(arg0 context.Context, arg1 database.UpdateWorkspaceTTLParams) error {
 	m.ctrl.T.Helper()
@@ -4738,6 +6164,51 @@ func (mr *MockStoreMockRecorder) UpsertTemplateUsageStats(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateUsageStats", reflect.TypeOf((*MockStore)(nil).UpsertTemplateUsageStats), arg0)
 }
 
+// UpsertUserLoginLockout mocks base method.
+func (m *MockStore) UpsertUserLoginLockout(arg0 context.Context, arg1 database.UpsertUserLoginLockoutParams) (database.UserLoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserLoginLockout", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserLoginLockout indicates an expected call of UpsertUserLoginLockout.
+func (mr *MockStoreMockRecorder) UpsertUserLoginLockout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserLoginLockout", reflect.TypeOf((*MockStore)(nil).UpsertUserLoginLockout), arg0, arg1)
+}
+
+// UpsertUserNotificationPreference mocks base method.
+func (m *MockStore) UpsertUserNotificationPreference(arg0 context.Context, arg1 database.UpsertUserNotificationPreferenceParams) (database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserNotificationPreference", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserNotificationPreference indicates an expected call of UpsertUserNotificationPreference.
+func (mr *MockStoreMockRecorder) UpsertUserNotificationPreference(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserNotificationPreference", reflect.TypeOf((*MockStore)(nil).UpsertUserNotificationPreference), arg0, arg1)
+}
+
+// UpsertUserTOTP mocks base method.
+func (m *MockStore) UpsertUserTOTP(arg0 context.Context, arg1 database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserTOTP indicates an expected call of UpsertUserTOTP.
+func (mr *MockStoreMockRecorder) UpsertUserTOTP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserTOTP", reflect.TypeOf((*MockStore)(nil).UpsertUserTOTP), arg0, arg1)
+}
+
 // UpsertWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) UpsertWorkspaceAgentPortShare(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -4753,6 +6224,21 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), arg0, arg1)
 }
 
+// UseUserTOTPRecoveryCode mocks base method.
+func (m *MockStore) UseUserTOTPRecoveryCode(arg0 context.Context, arg1 database.UseUserTOTPRecoveryCodeParams) (database.UserTOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserTOTPRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(database.UserTOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserTOTPRecoveryCode indicates an expected call of UseUserTOTPRecoveryCode.
+func (mr *MockStoreMockRecorder) UseUserTOTPRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserTOTPRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseUserTOTPRecoveryCode), arg0, arg1)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
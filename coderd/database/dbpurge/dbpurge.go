@@ -12,6 +12,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 const (
@@ -22,7 +23,7 @@ const (
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, vals *codersdk.DeploymentValues) io.Closer {
 	closed := make(chan struct{})
 	logger = logger.Named("dbpurge")
 
@@ -46,6 +47,22 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 		eg.Go(func() error {
 			return db.DeleteOldProvisionerDaemons(ctx)
 		})
+		// The state of builds older than the retained ones is cleared, so
+		// they can no longer be used for a rollback.
+		if keep := vals.Provisioner.StateRetention.Value(); keep > 0 {
+			eg.Go(func() error {
+				return db.DeleteOldWorkspaceBuildProvisionerStates(ctx, int32(keep))
+			})
+		}
+		eg.Go(func() error {
+			return db.DeleteOldNotificationMessages(ctx)
+		})
+		eg.Go(func() error {
+			return db.DeleteOldWebhookDeliveries(ctx)
+		})
+		eg.Go(func() error {
+			return db.DeleteOldUserLoginAttempts(ctx)
+		})
 		err := eg.Wait()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -3,6 +3,7 @@ package dbpurge_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbpurge"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
@@ -31,7 +33,7 @@ func TestMain(m *testing.M) {
 // Ensures no goroutines leak.
 func TestPurge(t *testing.T) {
 	t.Parallel()
-	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbmem.New())
+	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbmem.New(), &codersdk.DeploymentValues{})
 	err := purger.Close()
 	require.NoError(t, err)
 }
@@ -64,7 +66,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	})
 
 	// when
-	closer := dbpurge.New(ctx, logger, db)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{})
 	defer closer.Close()
 
 	// then
@@ -110,7 +112,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 		agent := mustCreateAgentWithLogs(ctx, t, db, user, org, tmpl, tv, now.Add(-8*24*time.Hour), t.Name())
 
 		// when
-		closer := dbpurge.New(ctx, logger, db)
+		closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{})
 		defer closer.Close()
 
 		// then
@@ -135,7 +137,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 		agent := mustCreateAgentWithLogs(ctx, t, db, user, org, tmpl, tv, now.Add(-6*24*time.Hour), t.Name())
 
 		// when
-		closer := dbpurge.New(ctx, logger, db)
+		closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{})
 		defer closer.Close()
 
 		// then
@@ -268,7 +270,7 @@ func TestDeleteOldProvisionerDaemons(t *testing.T) {
 	require.NoError(t, err)
 
 	// when
-	closer := dbpurge.New(ctx, logger, db)
+	closer := dbpurge.New(ctx, logger, db, &codersdk.DeploymentValues{})
 	defer closer.Close()
 
 	// then
@@ -296,3 +298,56 @@ func containsProvisionerDaemon(daemons []database.ProvisionerDaemon, name string
 		return d.Name == name
 	})
 }
+
+func TestDeleteOldWorkspaceBuildProvisionerStates(t *testing.T) {
+	t.Parallel()
+
+	db, _ := dbtestutil.NewDB(t)
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	tv := dbgen.TemplateVersion(t, db, database.TemplateVersion{OrganizationID: org.ID, CreatedBy: user.ID})
+	tmpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, ActiveVersionID: tv.ID, CreatedBy: user.ID})
+	workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID, OrganizationID: org.ID, TemplateID: tmpl.ID})
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	// given
+	// Two more builds than are retained, so the first two lose their state.
+	vals := &codersdk.DeploymentValues{}
+	vals.Provisioner.StateRetention = 25
+	builds := int(vals.Provisioner.StateRetention) + 2
+	for i := 1; i <= builds; i++ {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			JobID:             job.ID,
+			TemplateVersionID: tv.ID,
+			BuildNumber:       int32(i),
+			ProvisionerState:  []byte(fmt.Sprintf("state %d", i)),
+		})
+	}
+
+	// when
+	closer := dbpurge.New(ctx, logger, db, vals)
+	defer closer.Close()
+
+	// then
+	require.Eventually(t, func() bool {
+		states, err := db.GetWorkspaceBuildStatesByWorkspaceID(ctx, workspace.ID)
+		if err != nil || len(states) != builds {
+			return false
+		}
+		for _, state := range states {
+			retained := state.BuildNumber > 2
+			if retained != (state.StateSize > 0) {
+				return false
+			}
+		}
+		return true
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
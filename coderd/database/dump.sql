@@ -2,7 +2,12 @@
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'workspace:read',
+    'workspace:start-stop',
+    'template:read',
+    'template:push',
+    'user:read'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -46,7 +51,8 @@ CREATE TYPE display_app AS ENUM (
 
 CREATE TYPE group_source AS ENUM (
     'user',
-    'oidc'
+    'oidc',
+    'scim'
 );
 
 CREATE TYPE log_level AS ENUM (
@@ -73,6 +79,19 @@ CREATE TYPE login_type AS ENUM (
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
 
+CREATE TYPE notification_message_status AS ENUM (
+    'pending',
+    'leased',
+    'sent',
+    'temporary_failure',
+    'permanent_failure'
+);
+
+CREATE TYPE notification_method AS ENUM (
+    'smtp',
+    'webhook'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -124,7 +143,9 @@ CREATE TYPE provisioner_storage_method AS ENUM (
 
 CREATE TYPE provisioner_type AS ENUM (
     'echo',
-    'terraform'
+    'terraform',
+    'opentofu',
+    'kubernetes'
 );
 
 CREATE TYPE resource_type AS ENUM (
@@ -142,7 +163,12 @@ CREATE TYPE resource_type AS ENUM (
     'convert_login',
     'health_settings',
     'oauth2_provider_app',
-    'oauth2_provider_app_secret'
+    'oauth2_provider_app_secret',
+    'webhook',
+    'custom_role',
+    'oidc_sync_rule',
+    'scim_token',
+    'user_login_lockout'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -155,6 +181,11 @@ CREATE TYPE tailnet_status AS ENUM (
     'lost'
 );
 
+CREATE TYPE template_version_diagnostic_severity AS ENUM (
+    'warning',
+    'error'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -163,6 +194,14 @@ CREATE TYPE user_status AS ENUM (
 
 COMMENT ON TYPE user_status IS 'Defines the users status: active, dormant, or suspended.';
 
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',
+    'leased',
+    'succeeded',
+    'temporary_failure',
+    'permanent_failure'
+);
+
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'created',
     'starting',
@@ -189,6 +228,27 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_bulk_action AS ENUM (
+    'start',
+    'stop',
+    'update',
+    'delete'
+);
+
+CREATE TYPE workspace_bulk_operation_status AS ENUM (
+    'running',
+    'completed',
+    'canceled'
+);
+
+CREATE TYPE workspace_bulk_result_status AS ENUM (
+    'pending',
+    'running',
+    'succeeded',
+    'failed',
+    'skipped'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -381,11 +441,17 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    impersonator_id uuid,
+    user_agent text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.impersonator_id IS 'The owner who minted this key to act as user_id. Keys with an impersonator are short-lived and cannot perform sensitive actions.';
+
+COMMENT ON COLUMN api_keys.user_agent IS 'The user agent of the client that created the key, shown to users to tell their sessions apart.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -401,9 +467,32 @@ CREATE TABLE audit_logs (
     status_code integer NOT NULL,
     additional_fields jsonb NOT NULL,
     request_id uuid NOT NULL,
-    resource_icon text NOT NULL
+    resource_icon text NOT NULL,
+    impersonator_id uuid
 );
 
+COMMENT ON COLUMN audit_logs.impersonator_id IS 'The user who performed the action while impersonating user_id, if any.';
+
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined at runtime in addition to the built in roles. Site roles are assigned to users, organization roles to organization members.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of organization roles, null for site roles.';
+
+COMMENT ON COLUMN custom_roles.org_permissions IS 'Permissions within the organization of the role. Only organization roles have them.';
+
+COMMENT ON COLUMN custom_roles.user_permissions IS 'Permissions on the resources owned by the user that has the role.';
+
 CREATE TABLE dbcrypt_keys (
     number integer NOT NULL,
     active_key_digest text,
@@ -473,13 +562,16 @@ CREATE TABLE groups (
     avatar_url text DEFAULT ''::text NOT NULL,
     quota_allowance integer DEFAULT 0 NOT NULL,
     display_name text DEFAULT ''::text NOT NULL,
-    source group_source DEFAULT 'user'::group_source NOT NULL
+    source group_source DEFAULT 'user'::group_source NOT NULL,
+    max_workspaces_per_user integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN groups.display_name IS 'Display name is a custom, human-friendly group name that user can set. This is not required to be unique and can be the empty string.';
 
 COMMENT ON COLUMN groups.source IS 'Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.';
 
+COMMENT ON COLUMN groups.max_workspaces_per_user IS 'The maximum number of workspaces each member of the group can own. Members of several groups get the highest limit. 0 means the group sets no limit.';
+
 CREATE TABLE jfrog_xray_scans (
     agent_id uuid NOT NULL,
     workspace_id uuid NOT NULL,
@@ -509,6 +601,41 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE notification_messages (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    method notification_method NOT NULL,
+    status notification_message_status DEFAULT 'pending'::notification_message_status NOT NULL,
+    status_reason text,
+    payload jsonb NOT NULL,
+    attempt_count integer DEFAULT 0 NOT NULL,
+    created_by text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    leased_until timestamp with time zone,
+    next_retry_after timestamp with time zone,
+    sent_at timestamp with time zone
+);
+
+COMMENT ON TABLE notification_messages IS 'Queue of notifications waiting to be delivered, and a short history of delivered ones.';
+
+COMMENT ON COLUMN notification_messages.template IS 'Name of the notification template the message was rendered from.';
+
+COMMENT ON COLUMN notification_messages.payload IS 'The rendered message, along with everything needed to deliver it.';
+
+COMMENT ON COLUMN notification_messages.created_by IS 'The component that enqueued the message.';
+
+CREATE TABLE notification_preferences (
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE notification_preferences IS 'Per-user choices about which notifications to receive.';
+
 CREATE TABLE oauth2_provider_app_codes (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -556,6 +683,27 @@ CREATE TABLE oauth2_provider_apps (
 
 COMMENT ON TABLE oauth2_provider_apps IS 'A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.';
 
+CREATE TABLE oidc_sync_rules (
+    id uuid NOT NULL,
+    claim text NOT NULL,
+    pattern text NOT NULL,
+    site_roles text[] DEFAULT '{}'::text[] NOT NULL,
+    organization_id uuid,
+    organization_roles text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE oidc_sync_rules IS 'Rules that assign roles and organization memberships to users whose OIDC claims match a pattern. They are applied on every OIDC login.';
+
+COMMENT ON COLUMN oidc_sync_rules.claim IS 'The name of the claim to match. The claim can be a string or an array of strings.';
+
+COMMENT ON COLUMN oidc_sync_rules.pattern IS 'A regular expression. The rule matches if any value of the claim matches it.';
+
+COMMENT ON COLUMN oidc_sync_rules.organization_id IS 'The organization users matching the rule are made a member of, null if the rule does not grant a membership.';
+
+COMMENT ON COLUMN oidc_sync_rules.organization_roles IS 'Roles in the organization of the rule. Only allowed if the rule has an organization.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -673,11 +821,14 @@ CASE
         WHEN (started_at IS NULL) THEN 'pending'::provisioner_job_status
         ELSE 'running'::provisioner_job_status
     END
-END) STORED NOT NULL
+END) STORED NOT NULL,
+    engine_version_constraint text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN provisioner_jobs.job_status IS 'Computed column to track the status of the job.';
 
+COMMENT ON COLUMN provisioner_jobs.engine_version_constraint IS 'The required_version constraint of the template, only provisioner daemons with a satisfying engine version may acquire the job.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -693,6 +844,20 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE scim_tokens (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL,
+    secret_prefix bytea NOT NULL
+);
+
+COMMENT ON TABLE scim_tokens IS 'Bearer tokens an identity provider uses to authenticate against the SCIM API.';
+
+COMMENT ON COLUMN scim_tokens.display_secret IS 'The tail end of the original secret so secrets can be differentiated.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value character varying(8192) NOT NULL
@@ -741,6 +906,23 @@ CREATE TABLE tailnet_tunnels (
     updated_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE template_release_channels (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    template_version_id uuid NOT NULL,
+    user_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_release_channels IS 'Named versions of a template used by the workspaces of specific users and groups instead of the active version.';
+
+COMMENT ON COLUMN template_release_channels.user_ids IS 'Users assigned to the channel. A user assigned directly takes precedence over an assignment through a group.';
+
+COMMENT ON COLUMN template_release_channels.group_ids IS 'Groups assigned to the channel. If several channels match the groups of a user, the first by name is used.';
+
 CREATE TABLE template_usage_stats (
     start_time timestamp with time zone NOT NULL,
     end_time timestamp with time zone NOT NULL,
@@ -782,6 +964,23 @@ COMMENT ON COLUMN template_usage_stats.jetbrains_mins IS 'Total minutes the user
 
 COMMENT ON COLUMN template_usage_stats.app_usage_mins IS 'Object with app names as keys and total minutes used as values. Null means no app usage was recorded.';
 
+CREATE TABLE template_version_diagnostics (
+    id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    severity template_version_diagnostic_severity NOT NULL,
+    code text NOT NULL,
+    summary text NOT NULL,
+    detail text NOT NULL,
+    filename text NOT NULL,
+    line integer NOT NULL
+);
+
+COMMENT ON TABLE template_version_diagnostics IS 'Problems found by statically checking the source of a template version on import.';
+
+COMMENT ON COLUMN template_version_diagnostics.code IS 'Identifies the check that found the problem.';
+
+COMMENT ON COLUMN template_version_diagnostics.filename IS 'Path of the file relative to the root of the template.';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -835,6 +1034,26 @@ COMMENT ON COLUMN template_version_parameters.display_order IS 'Specifies the or
 
 COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephemeral parameter will not be preserved between consecutive workspace builds.';
 
+CREATE TABLE template_version_preset_parameters (
+    template_version_preset_id uuid NOT NULL,
+    name text NOT NULL,
+    value text NOT NULL
+);
+
+COMMENT ON COLUMN template_version_preset_parameters.name IS 'The name of the template version parameter set by the preset.';
+
+CREATE TABLE template_version_presets (
+    id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    desired_prebuild_instances integer DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of parameter values declared by a template version, which users can pick when creating a workspace.';
+
+COMMENT ON COLUMN template_version_presets.desired_prebuild_instances IS 'The number of workspaces kept built with the preset while its template version is active.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -894,7 +1113,9 @@ CREATE TABLE users (
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
     quiet_hours_schedule text DEFAULT ''::text NOT NULL,
     theme_preference text DEFAULT ''::text NOT NULL,
-    name text DEFAULT ''::text NOT NULL
+    name text DEFAULT ''::text NOT NULL,
+    is_service_account boolean DEFAULT false NOT NULL,
+    CONSTRAINT service_account_login_type CHECK (((NOT is_service_account) OR (login_type = 'none'::login_type)))
 );
 
 COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
@@ -903,6 +1124,8 @@ COMMENT ON COLUMN users.theme_preference IS '"" can be interpreted as "the user
 
 COMMENT ON COLUMN users.name IS 'Name of the Coder user';
 
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users. They cannot log in and only authenticate with API tokens. They are never marked dormant and do not count towards licensed seats.';
+
 CREATE VIEW visible_users AS
  SELECT users.id,
     users.username,
@@ -959,7 +1182,8 @@ CREATE TABLE templates (
     require_active_version boolean DEFAULT false NOT NULL,
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
-    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL
+    max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
+    max_workspaces integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -980,6 +1204,8 @@ COMMENT ON COLUMN templates.autostart_block_days_of_week IS 'A bitmap of days of
 
 COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.';
 
+COMMENT ON COLUMN templates.max_workspaces IS 'The maximum number of workspaces that can be created from the template. 0 means no limit.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -1009,6 +1235,7 @@ CREATE VIEW template_with_users AS
     templates.deprecated,
     templates.activity_bump,
     templates.max_port_sharing_level,
+    templates.max_workspaces,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (templates
@@ -1034,6 +1261,83 @@ COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key us
 
 COMMENT ON COLUMN user_links.debug_context IS 'Debug information includes information like id_token and userinfo claims.';
 
+CREATE TABLE user_login_attempts (
+    id uuid NOT NULL,
+    user_id uuid,
+    ip inet NOT NULL,
+    user_agent text NOT NULL,
+    success boolean NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_login_attempts IS 'Password login attempts, used to slow down brute-force attacks and to detect logins from new IP addresses or user agents.';
+
+COMMENT ON COLUMN user_login_attempts.user_id IS 'NULL when the login did not match a user.';
+
+CREATE TABLE user_login_lockouts (
+    user_id uuid NOT NULL,
+    failed_attempts integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    locked_until timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_login_lockouts IS 'Users blocked from password login after too many failed attempts.';
+
+CREATE TABLE user_totp (
+    user_id uuid NOT NULL,
+    secret text NOT NULL,
+    enabled_at timestamp with time zone,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    hashed_recovery_codes text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_totp IS 'TOTP second factors for password logins.';
+
+COMMENT ON COLUMN user_totp.enabled_at IS 'NULL while the user has not yet confirmed enrollment with a valid code.';
+
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code, so codes cannot be replayed.';
+
+COMMENT ON COLUMN user_totp.hashed_recovery_codes IS 'SHA-256 hashes of the unused single-use recovery codes.';
+
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    status webhook_delivery_status DEFAULT 'pending'::webhook_delivery_status NOT NULL,
+    attempt_count integer DEFAULT 0 NOT NULL,
+    response_status integer,
+    response_body text,
+    error text,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    leased_until timestamp with time zone,
+    next_attempt_after timestamp with time zone,
+    delivered_at timestamp with time zone
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Queue of webhook requests waiting to be sent, and a log of the ones already attempted.';
+
+COMMENT ON COLUMN webhook_deliveries.response_body IS 'The start of the body of the last response, kept to help debug failed deliveries.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    url text NOT NULL,
+    events text[] DEFAULT '{}'::text[] NOT NULL,
+    secret text DEFAULT ''::text NOT NULL,
+    enabled boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that are sent a request whenever one of the subscribed events happens.';
+
+COMMENT ON COLUMN webhooks.events IS 'The events the webhook is subscribed to. An empty list subscribes to every event.';
+
+COMMENT ON COLUMN webhooks.secret IS 'Used to sign request bodies with HMAC-SHA256 so receivers can verify them. Empty if requests are not signed.';
+
 CREATE TABLE workspace_agent_log_sources (
     workspace_agent_id uuid NOT NULL,
     id uuid NOT NULL,
@@ -1288,6 +1592,45 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_bulk_operation_workspaces (
+    operation_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    status workspace_bulk_result_status DEFAULT 'pending'::workspace_bulk_result_status NOT NULL,
+    build_id uuid,
+    error text DEFAULT ''::text NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_bulk_operation_workspaces IS 'The outcome of a bulk operation for each of its workspaces.';
+
+COMMENT ON COLUMN workspace_bulk_operation_workspaces.error IS 'Why the workspace was skipped or its build failed.';
+
+CREATE TABLE workspace_bulk_operations (
+    id uuid NOT NULL,
+    initiator_id uuid NOT NULL,
+    action workspace_bulk_action NOT NULL,
+    query text NOT NULL,
+    concurrency integer NOT NULL,
+    status workspace_bulk_operation_status DEFAULT 'running'::workspace_bulk_operation_status NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_bulk_operations IS 'Builds of many workspaces requested at once, executed in the background with a limited number of builds in progress.';
+
+COMMENT ON COLUMN workspace_bulk_operations.query IS 'The workspace search query that selected the workspaces.';
+
+COMMENT ON COLUMN workspace_bulk_operations.concurrency IS 'The maximum number of builds of the operation in progress at once.';
+
+CREATE TABLE workspace_prebuilds (
+    workspace_id uuid NOT NULL,
+    template_version_preset_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_prebuilds IS 'Workspaces built ahead of time by the prebuilds reconciler that have not been claimed by a user yet.';
+
 CREATE TABLE workspace_proxies (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1357,6 +1700,32 @@ CREATE TABLE workspace_resources (
     daily_cost integer DEFAULT 0 NOT NULL
 );
 
+CREATE TABLE workspace_snapshot_restores (
+    workspace_id uuid NOT NULL,
+    snapshot_id uuid,
+    artifacts jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_snapshot_restores IS 'Workspaces created from a snapshot. The artifacts are passed to every build of the workspace so the template keeps the restored data.';
+
+COMMENT ON COLUMN workspace_snapshot_restores.snapshot_id IS 'The snapshot the workspace was created from, null once the snapshot is deleted.';
+
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    name text NOT NULL,
+    build_id uuid NOT NULL,
+    artifacts jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of the data of a workspace, taken by the template during a stop build.';
+
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The stop build that takes the snapshot.';
+
+COMMENT ON COLUMN workspace_snapshots.artifacts IS 'Map of the artifacts the template returned as "snapshot." prefixed resource metadata, e.g. volume snapshot IDs.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1372,7 +1741,9 @@ CREATE TABLE workspaces (
     dormant_at timestamp with time zone,
     deleting_at timestamp with time zone,
     automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL,
-    favorite boolean DEFAULT false NOT NULL
+    favorite boolean DEFAULT false NOT NULL,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN workspaces.favorite IS 'Favorite is true if the workspace owner has favorited the workspace.';
@@ -1398,6 +1769,9 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY dbcrypt_keys
     ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
 
@@ -1437,6 +1811,12 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, template);
+
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
 
@@ -1461,6 +1841,9 @@ ALTER TABLE ONLY oauth2_provider_apps
 ALTER TABLE ONLY oauth2_provider_apps
     ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY oidc_sync_rules
+    ADD CONSTRAINT oidc_sync_rules_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1488,6 +1871,15 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY scim_tokens
+    ADD CONSTRAINT scim_tokens_name_key UNIQUE (name);
+
+ALTER TABLE ONLY scim_tokens
+    ADD CONSTRAINT scim_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY scim_tokens
+    ADD CONSTRAINT scim_tokens_secret_prefix_key UNIQUE (secret_prefix);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -1509,12 +1901,30 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
 
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_template_id_name_key UNIQUE (template_id, name);
+
 ALTER TABLE ONLY template_usage_stats
     ADD CONSTRAINT template_usage_stats_pkey PRIMARY KEY (start_time, template_id, user_id);
 
+ALTER TABLE ONLY template_version_diagnostics
+    ADD CONSTRAINT template_version_diagnostics_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
+ALTER TABLE ONLY template_version_preset_parameters
+    ADD CONSTRAINT template_version_preset_parameters_pkey PRIMARY KEY (template_version_preset_id, name);
+
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_template_version_id_name_key UNIQUE (template_version_id, name);
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -1530,9 +1940,27 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_login_attempts
+    ADD CONSTRAINT user_login_attempts_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY user_login_lockouts
+    ADD CONSTRAINT user_login_lockouts_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_name_key UNIQUE (name);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 
@@ -1569,9 +1997,18 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_bulk_operation_workspaces
+    ADD CONSTRAINT workspace_bulk_operation_workspaces_pkey PRIMARY KEY (operation_id, workspace_id);
+
+ALTER TABLE ONLY workspace_bulk_operations
+    ADD CONSTRAINT workspace_bulk_operations_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_pkey PRIMARY KEY (workspace_id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
@@ -1587,6 +2024,18 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_snapshot_restores
+    ADD CONSTRAINT workspace_snapshot_restores_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_build_id_key UNIQUE (build_id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_name_key UNIQUE (workspace_id, name);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
@@ -1606,6 +2055,8 @@ CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
 CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING btree (user_id);
@@ -1628,6 +2079,10 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX notification_messages_status_idx ON notification_messages USING btree (status);
+
+CREATE INDEX oidc_sync_rules_organization_id_idx ON oidc_sync_rules USING btree (organization_id);
+
 CREATE UNIQUE INDEX organizations_single_default_org ON organizations USING btree (is_default) WHERE (is_default = true);
 
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
@@ -1642,14 +2097,24 @@ CREATE UNIQUE INDEX template_usage_stats_start_time_template_id_user_id_idx ON t
 
 COMMENT ON INDEX template_usage_stats_start_time_template_id_user_id_idx IS 'Index for primary key.';
 
+CREATE INDEX template_version_diagnostics_template_version_id_idx ON template_version_diagnostics USING btree (template_version_id);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX user_links_linked_id_login_type_idx ON user_links USING btree (linked_id, login_type) WHERE (linked_id <> ''::text);
 
+CREATE INDEX user_login_attempts_ip_created_at_idx ON user_login_attempts USING btree (ip, created_at);
+
+CREATE INDEX user_login_attempts_user_id_created_at_idx ON user_login_attempts USING btree (user_id, created_at);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries USING btree (status);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
 CREATE INDEX workspace_agent_scripts_workspace_agent_id_idx ON workspace_agent_scripts USING btree (workspace_agent_id);
 
 COMMENT ON INDEX workspace_agent_scripts_workspace_agent_id_idx IS 'Foreign key support index for faster lookups';
@@ -1666,6 +2131,10 @@ CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (r
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_bulk_operations_status_idx ON workspace_bulk_operations USING btree (status) WHERE (status = 'running'::workspace_bulk_operation_status);
+
+CREATE INDEX workspace_prebuilds_template_version_preset_id_idx ON workspace_prebuilds USING btree (template_version_preset_id);
+
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
@@ -1692,9 +2161,15 @@ CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW
 
 CREATE TRIGGER trigger_upsert_user_links BEFORE INSERT OR UPDATE ON user_links FOR EACH ROW EXECUTE FUNCTION insert_user_links_fail_if_user_deleted();
 
+ALTER TABLE ONLY api_keys
+    ADD CONSTRAINT api_keys_impersonator_id_fkey FOREIGN KEY (impersonator_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY external_auth_links
     ADD CONSTRAINT git_auth_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -1719,6 +2194,12 @@ ALTER TABLE ONLY jfrog_xray_scans
 ALTER TABLE ONLY jfrog_xray_scans
     ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_codes
     ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 
@@ -1734,6 +2215,9 @@ ALTER TABLE ONLY oauth2_provider_app_tokens
 ALTER TABLE ONLY oauth2_provider_app_tokens
     ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oidc_sync_rules
+    ADD CONSTRAINT oidc_sync_rules_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1767,9 +2251,24 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_release_channels
+    ADD CONSTRAINT template_release_channels_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_diagnostics
+    ADD CONSTRAINT template_version_diagnostics_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_preset_parameters
+    ADD CONSTRAINT template_version_preset_parameters_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -1797,6 +2296,18 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_login_attempts
+    ADD CONSTRAINT user_login_attempts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_login_lockouts
+    ADD CONSTRAINT user_login_lockouts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -1839,12 +2350,42 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_bulk_operation_workspaces
+    ADD CONSTRAINT workspace_bulk_operation_workspaces_build_id_fkey FOREIGN KEY (build_id) REFERENCES workspace_builds(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_bulk_operation_workspaces
+    ADD CONSTRAINT workspace_bulk_operation_workspaces_operation_id_fkey FOREIGN KEY (operation_id) REFERENCES workspace_bulk_operations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_bulk_operation_workspaces
+    ADD CONSTRAINT workspace_bulk_operation_workspaces_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_bulk_operations
+    ADD CONSTRAINT workspace_bulk_operations_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_template_version_preset_id_fkey FOREIGN KEY (template_version_preset_id) REFERENCES template_version_presets(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_prebuilds
+    ADD CONSTRAINT workspace_prebuilds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_snapshot_restores
+    ADD CONSTRAINT workspace_snapshot_restores_snapshot_id_fkey FOREIGN KEY (snapshot_id) REFERENCES workspace_snapshots(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_snapshot_restores
+    ADD CONSTRAINT workspace_snapshot_restores_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_build_id_fkey FOREIGN KEY (build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS template_version_diagnostics;
+
+DROP TYPE IF EXISTS template_version_diagnostic_severity;
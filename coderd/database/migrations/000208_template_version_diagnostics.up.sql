@@ -0,0 +1,24 @@
+CREATE TYPE template_version_diagnostic_severity AS ENUM (
+	'warning',
+	'error'
+);
+
+CREATE TABLE template_version_diagnostics (
+	id uuid NOT NULL,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	severity template_version_diagnostic_severity NOT NULL,
+	code text NOT NULL,
+	summary text NOT NULL,
+	detail text NOT NULL,
+	filename text NOT NULL,
+	line integer NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE template_version_diagnostics IS 'Problems found by statically checking the source of a template version on import.';
+
+COMMENT ON COLUMN template_version_diagnostics.code IS 'Identifies the check that found the problem.';
+
+COMMENT ON COLUMN template_version_diagnostics.filename IS 'Path of the file relative to the root of the template.';
+
+CREATE INDEX template_version_diagnostics_template_version_id_idx ON template_version_diagnostics (template_version_id);
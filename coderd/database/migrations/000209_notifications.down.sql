@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notification_preferences;
+
+DROP TABLE IF EXISTS notification_messages;
+
+DROP TYPE IF EXISTS notification_method;
+
+DROP TYPE IF EXISTS notification_message_status;
@@ -0,0 +1,51 @@
+CREATE TYPE notification_message_status AS ENUM (
+	'pending',
+	'leased',
+	'sent',
+	'temporary_failure',
+	'permanent_failure'
+);
+
+CREATE TYPE notification_method AS ENUM (
+	'smtp',
+	'webhook'
+);
+
+CREATE TABLE notification_messages (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template text NOT NULL,
+	method notification_method NOT NULL,
+	status notification_message_status NOT NULL DEFAULT 'pending'::notification_message_status,
+	status_reason text,
+	payload jsonb NOT NULL,
+	attempt_count integer NOT NULL DEFAULT 0,
+	created_by text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	leased_until timestamp with time zone,
+	next_retry_after timestamp with time zone,
+	sent_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE notification_messages IS 'Queue of notifications waiting to be delivered, and a short history of delivered ones.';
+
+COMMENT ON COLUMN notification_messages.template IS 'Name of the notification template the message was rendered from.';
+
+COMMENT ON COLUMN notification_messages.payload IS 'The rendered message, along with everything needed to deliver it.';
+
+COMMENT ON COLUMN notification_messages.created_by IS 'The component that enqueued the message.';
+
+CREATE INDEX notification_messages_status_idx ON notification_messages (status);
+
+CREATE TABLE notification_preferences (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template text NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, template)
+);
+
+COMMENT ON TABLE notification_preferences IS 'Per-user choices about which notifications to receive.';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP TABLE IF EXISTS webhooks;
+
+DROP TYPE IF EXISTS webhook_delivery_status;
+
+-- It is not possible to drop enum values from enum types, so the UP on
+-- resource_type has "IF NOT EXISTS".
@@ -0,0 +1,54 @@
+CREATE TYPE webhook_delivery_status AS ENUM (
+	'pending',
+	'leased',
+	'succeeded',
+	'temporary_failure',
+	'permanent_failure'
+);
+
+CREATE TABLE webhooks (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	url text NOT NULL,
+	events text[] NOT NULL DEFAULT '{}'::text[],
+	secret text NOT NULL DEFAULT ''::text,
+	enabled boolean NOT NULL DEFAULT true,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT webhooks_name_key UNIQUE (name)
+);
+
+COMMENT ON TABLE webhooks IS 'Endpoints that are sent a request whenever one of the subscribed events happens.';
+
+COMMENT ON COLUMN webhooks.events IS 'The events the webhook is subscribed to. An empty list subscribes to every event.';
+
+COMMENT ON COLUMN webhooks.secret IS 'Used to sign request bodies with HMAC-SHA256 so receivers can verify them. Empty if requests are not signed.';
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL,
+	webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	event text NOT NULL,
+	payload jsonb NOT NULL,
+	status webhook_delivery_status NOT NULL DEFAULT 'pending'::webhook_delivery_status,
+	attempt_count integer NOT NULL DEFAULT 0,
+	response_status integer,
+	response_body text,
+	error text,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	leased_until timestamp with time zone,
+	next_attempt_after timestamp with time zone,
+	delivered_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Queue of webhook requests waiting to be sent, and a log of the ones already attempted.';
+
+COMMENT ON COLUMN webhook_deliveries.response_body IS 'The start of the body of the last response, kept to help debug failed deliveries.';
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status);
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'webhook';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS template_version_preset_parameters;
+
+DROP TABLE IF EXISTS template_version_presets;
@@ -0,0 +1,19 @@
+CREATE TABLE template_version_presets (
+	id uuid NOT NULL,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	CONSTRAINT template_version_presets_template_version_id_name_key UNIQUE (template_version_id, name)
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of parameter values declared by a template version, which users can pick when creating a workspace.';
+
+CREATE TABLE template_version_preset_parameters (
+	template_version_preset_id uuid NOT NULL REFERENCES template_version_presets (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	value text NOT NULL,
+	PRIMARY KEY (template_version_preset_id, name)
+);
+
+COMMENT ON COLUMN template_version_preset_parameters.name IS 'The name of the template version parameter set by the preset.';
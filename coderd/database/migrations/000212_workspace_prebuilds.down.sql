@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workspace_prebuilds;
+
+ALTER TABLE template_version_presets DROP COLUMN IF EXISTS desired_prebuild_instances;
@@ -0,0 +1,14 @@
+ALTER TABLE template_version_presets ADD COLUMN desired_prebuild_instances integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN template_version_presets.desired_prebuild_instances IS 'The number of workspaces kept built with the preset while its template version is active.';
+
+CREATE TABLE workspace_prebuilds (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	template_version_preset_id uuid NOT NULL REFERENCES template_version_presets (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_id)
+);
+
+COMMENT ON TABLE workspace_prebuilds IS 'Workspaces built ahead of time by the prebuilds reconciler that have not been claimed by a user yet.';
+
+CREATE INDEX workspace_prebuilds_template_version_preset_id_idx ON workspace_prebuilds (template_version_preset_id);
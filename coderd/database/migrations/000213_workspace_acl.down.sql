@@ -0,0 +1,3 @@
+ALTER TABLE workspaces
+	DROP COLUMN group_acl,
+	DROP COLUMN user_acl;
@@ -0,0 +1,3 @@
+ALTER TABLE workspaces
+	ADD COLUMN user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+	ADD COLUMN group_acl jsonb DEFAULT '{}'::jsonb NOT NULL;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS workspace_bulk_operation_workspaces;
+
+DROP TABLE IF EXISTS workspace_bulk_operations;
+
+DROP TYPE IF EXISTS workspace_bulk_result_status;
+
+DROP TYPE IF EXISTS workspace_bulk_operation_status;
+
+DROP TYPE IF EXISTS workspace_bulk_action;
@@ -0,0 +1,40 @@
+CREATE TYPE workspace_bulk_action AS ENUM ('start', 'stop', 'update', 'delete');
+
+CREATE TYPE workspace_bulk_operation_status AS ENUM ('running', 'completed', 'canceled');
+
+CREATE TYPE workspace_bulk_result_status AS ENUM ('pending', 'running', 'succeeded', 'failed', 'skipped');
+
+CREATE TABLE workspace_bulk_operations (
+	id uuid NOT NULL,
+	initiator_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	action workspace_bulk_action NOT NULL,
+	query text NOT NULL,
+	concurrency integer NOT NULL,
+	status workspace_bulk_operation_status NOT NULL DEFAULT 'running'::workspace_bulk_operation_status,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	completed_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_bulk_operations IS 'Builds of many workspaces requested at once, executed in the background with a limited number of builds in progress.';
+
+COMMENT ON COLUMN workspace_bulk_operations.query IS 'The workspace search query that selected the workspaces.';
+
+COMMENT ON COLUMN workspace_bulk_operations.concurrency IS 'The maximum number of builds of the operation in progress at once.';
+
+CREATE INDEX workspace_bulk_operations_status_idx ON workspace_bulk_operations (status) WHERE status = 'running'::workspace_bulk_operation_status;
+
+CREATE TABLE workspace_bulk_operation_workspaces (
+	operation_id uuid NOT NULL REFERENCES workspace_bulk_operations (id) ON DELETE CASCADE,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	status workspace_bulk_result_status NOT NULL DEFAULT 'pending'::workspace_bulk_result_status,
+	build_id uuid REFERENCES workspace_builds (id) ON DELETE SET NULL,
+	error text NOT NULL DEFAULT ''::text,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (operation_id, workspace_id)
+);
+
+COMMENT ON TABLE workspace_bulk_operation_workspaces IS 'The outcome of a bulk operation for each of its workspaces.';
+
+COMMENT ON COLUMN workspace_bulk_operation_workspaces.error IS 'Why the workspace was skipped or its build failed.';
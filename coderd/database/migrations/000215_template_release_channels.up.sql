@@ -0,0 +1,18 @@
+CREATE TABLE template_release_channels (
+	id uuid NOT NULL,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	user_ids uuid[] NOT NULL DEFAULT '{}'::uuid[],
+	group_ids uuid[] NOT NULL DEFAULT '{}'::uuid[],
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (template_id, name)
+);
+
+COMMENT ON TABLE template_release_channels IS 'Named versions of a template used by the workspaces of specific users and groups instead of the active version.';
+
+COMMENT ON COLUMN template_release_channels.user_ids IS 'Users assigned to the channel. A user assigned directly takes precedence over an assignment through a group.';
+
+COMMENT ON COLUMN template_release_channels.group_ids IS 'Groups assigned to the channel. If several channels match the groups of a user, the first by name is used.';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS workspace_snapshot_restores;
+DROP TABLE IF EXISTS workspace_snapshots;
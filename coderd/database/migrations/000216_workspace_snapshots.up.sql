@@ -0,0 +1,29 @@
+CREATE TABLE workspace_snapshots (
+	id uuid NOT NULL,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	artifacts jsonb NOT NULL DEFAULT '{}'::jsonb,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (workspace_id, name),
+	UNIQUE (build_id)
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of the data of a workspace, taken by the template during a stop build.';
+
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The stop build that takes the snapshot.';
+
+COMMENT ON COLUMN workspace_snapshots.artifacts IS 'Map of the artifacts the template returned as "snapshot." prefixed resource metadata, e.g. volume snapshot IDs.';
+
+CREATE TABLE workspace_snapshot_restores (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	snapshot_id uuid REFERENCES workspace_snapshots (id) ON DELETE SET NULL,
+	artifacts jsonb NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_id)
+);
+
+COMMENT ON TABLE workspace_snapshot_restores IS 'Workspaces created from a snapshot. The artifacts are passed to every build of the workspace so the template keeps the restored data.';
+
+COMMENT ON COLUMN workspace_snapshot_restores.snapshot_id IS 'The snapshot the workspace was created from, null once the snapshot is deleted.';
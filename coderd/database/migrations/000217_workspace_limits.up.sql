@@ -0,0 +1,24 @@
+ALTER TABLE templates ADD COLUMN max_workspaces integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_workspaces IS 'The maximum number of workspaces that can be created from the template. 0 means no limit.';
+
+ALTER TABLE groups ADD COLUMN max_workspaces_per_user integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN groups.max_workspaces_per_user IS 'The maximum number of workspaces each member of the group can own. Members of several groups get the highest limit. 0 means the group sets no limit.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+	template_with_users
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,25 @@
+CREATE TABLE custom_roles (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	site_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	org_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	user_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined at runtime in addition to the built in roles. Site roles are assigned to users, organization roles to organization members.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of organization roles, null for site roles.';
+
+COMMENT ON COLUMN custom_roles.org_permissions IS 'Permissions within the organization of the role. Only organization roles have them.';
+
+COMMENT ON COLUMN custom_roles.user_permissions IS 'Permissions on the resources owned by the user that has the role.';
+
+-- Site roles have no organization, and NULLs are distinct in unique indexes.
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'custom_role';
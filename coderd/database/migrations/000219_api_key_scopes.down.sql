@@ -0,0 +1,6 @@
+-- Older versions can't authorize the new scopes, so remove the tokens that
+-- use them.
+DELETE FROM api_keys WHERE scope NOT IN ('all', 'application_connect');
+
+-- It is not possible to drop enum values from enum types, so the UP on
+-- api_key_scope has "IF NOT EXISTS".
@@ -0,0 +1,5 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace:read';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace:start-stop';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'template:read';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'template:push';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'user:read';
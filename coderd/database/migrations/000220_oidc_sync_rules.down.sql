@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS oidc_sync_rules;
+
+-- It is not possible to drop enum values from enum types, so the UP on
+-- resource_type has "IF NOT EXISTS".
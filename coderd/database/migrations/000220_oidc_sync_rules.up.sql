@@ -0,0 +1,25 @@
+CREATE TABLE oidc_sync_rules (
+	id uuid NOT NULL,
+	claim text NOT NULL,
+	pattern text NOT NULL,
+	site_roles text[] NOT NULL DEFAULT '{}'::text[],
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	organization_roles text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE oidc_sync_rules IS 'Rules that assign roles and organization memberships to users whose OIDC claims match a pattern. They are applied on every OIDC login.';
+
+COMMENT ON COLUMN oidc_sync_rules.claim IS 'The name of the claim to match. The claim can be a string or an array of strings.';
+
+COMMENT ON COLUMN oidc_sync_rules.pattern IS 'A regular expression. The rule matches if any value of the claim matches it.';
+
+COMMENT ON COLUMN oidc_sync_rules.organization_id IS 'The organization users matching the rule are made a member of, null if the rule does not grant a membership.';
+
+COMMENT ON COLUMN oidc_sync_rules.organization_roles IS 'Roles in the organization of the rule. Only allowed if the rule has an organization.';
+
+CREATE INDEX oidc_sync_rules_organization_id_idx ON oidc_sync_rules (organization_id);
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oidc_sync_rule';
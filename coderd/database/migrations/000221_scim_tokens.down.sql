@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS scim_tokens;
+
+-- It is not possible to drop enum values from enum types, so the UP on
+-- group_source and resource_type have "IF NOT EXISTS".
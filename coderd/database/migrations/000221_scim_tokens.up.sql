@@ -0,0 +1,20 @@
+CREATE TABLE scim_tokens (
+	id uuid NOT NULL,
+	name text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone,
+	hashed_secret bytea NOT NULL,
+	display_secret text NOT NULL,
+	secret_prefix bytea NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (name),
+	UNIQUE (secret_prefix)
+);
+
+COMMENT ON TABLE scim_tokens IS 'Bearer tokens an identity provider uses to authenticate against the SCIM API.';
+
+COMMENT ON COLUMN scim_tokens.display_secret IS 'The tail end of the original secret so secrets can be differentiated.';
+
+ALTER TYPE group_source ADD VALUE IF NOT EXISTS 'scim';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'scim_token';
@@ -0,0 +1,5 @@
+ALTER TABLE audit_logs
+	DROP COLUMN IF EXISTS impersonator_id;
+
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS impersonator_id;
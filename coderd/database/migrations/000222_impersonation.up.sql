@@ -0,0 +1,9 @@
+ALTER TABLE api_keys
+	ADD COLUMN impersonator_id uuid REFERENCES users (id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN api_keys.impersonator_id IS 'The owner who minted this key to act as user_id. Keys with an impersonator are short-lived and cannot perform sensitive actions.';
+
+ALTER TABLE audit_logs
+	ADD COLUMN impersonator_id uuid;
+
+COMMENT ON COLUMN audit_logs.impersonator_id IS 'The user who performed the action while impersonating user_id, if any.';
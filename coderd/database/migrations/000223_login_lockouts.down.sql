@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_login_lockouts;
+
+DROP TABLE IF EXISTS user_login_attempts;
+
+-- It is not possible to drop enum values from enum types, so the UP on
+-- resource_type has "IF NOT EXISTS".
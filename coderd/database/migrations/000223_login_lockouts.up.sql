@@ -0,0 +1,29 @@
+CREATE TABLE user_login_attempts (
+	id uuid NOT NULL,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	ip inet NOT NULL,
+	user_agent text NOT NULL,
+	success boolean NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE user_login_attempts IS 'Password login attempts, used to slow down brute-force attacks and to detect logins from new IP addresses or user agents.';
+
+COMMENT ON COLUMN user_login_attempts.user_id IS 'NULL when the login did not match a user.';
+
+CREATE INDEX user_login_attempts_user_id_created_at_idx ON user_login_attempts USING btree (user_id, created_at);
+
+CREATE INDEX user_login_attempts_ip_created_at_idx ON user_login_attempts USING btree (ip, created_at);
+
+CREATE TABLE user_login_lockouts (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	failed_attempts integer NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	locked_until timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id)
+);
+
+COMMENT ON TABLE user_login_lockouts IS 'Users blocked from password login after too many failed attempts.';
+
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'user_login_lockout';
@@ -0,0 +1,17 @@
+CREATE TABLE user_totp (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	secret text NOT NULL,
+	enabled_at timestamp with time zone,
+	last_used_step bigint NOT NULL DEFAULT 0,
+	hashed_recovery_codes text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id)
+);
+
+COMMENT ON TABLE user_totp IS 'TOTP second factors for password logins.';
+
+COMMENT ON COLUMN user_totp.enabled_at IS 'NULL while the user has not yet confirmed enrollment with a valid code.';
+
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code, so codes cannot be replayed.';
+
+COMMENT ON COLUMN user_totp.hashed_recovery_codes IS 'SHA-256 hashes of the unused single-use recovery codes.';
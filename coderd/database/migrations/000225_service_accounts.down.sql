@@ -0,0 +1,3 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS service_account_login_type;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_service_account;
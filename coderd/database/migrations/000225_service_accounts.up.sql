@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN is_service_account boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users. They cannot log in and only authenticate with API tokens. They are never marked dormant and do not count towards licensed seats.';
+
+ALTER TABLE users ADD CONSTRAINT service_account_login_type CHECK (NOT is_service_account OR login_type = 'none'::login_type);
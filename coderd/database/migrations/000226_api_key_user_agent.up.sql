@@ -0,0 +1,4 @@
+ALTER TABLE api_keys
+	ADD COLUMN user_agent text DEFAULT ''::text NOT NULL;
+
+COMMENT ON COLUMN api_keys.user_agent IS 'The user agent of the client that created the key, shown to users to tell their sessions apart.';
@@ -0,0 +1,2 @@
+ALTER TABLE provisioner_jobs
+	DROP COLUMN IF EXISTS engine_version_constraint;
@@ -0,0 +1,4 @@
+ALTER TABLE provisioner_jobs
+	ADD COLUMN engine_version_constraint text DEFAULT ''::text NOT NULL;
+
+COMMENT ON COLUMN provisioner_jobs.engine_version_constraint IS 'The required_version constraint of the template, only provisioner daemons with a satisfying engine version may acquire the job.';
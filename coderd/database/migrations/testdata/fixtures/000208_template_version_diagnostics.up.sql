@@ -0,0 +1,12 @@
+INSERT INTO template_version_diagnostics
+	(id, template_version_id, severity, code, summary, detail, filename, line)
+VALUES (
+	'e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'920baba5-4c64-4686-8b7d-d1bef5683eae',
+	'warning',
+	'agent-not-attached',
+	'coder_agent.dev is not used by any resource',
+	'',
+	'main.tf',
+	12
+);
@@ -0,0 +1,23 @@
+INSERT INTO notification_messages
+	(id, user_id, template, method, status, payload, created_by, created_at, updated_at)
+VALUES (
+	'f0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+	'workspace-dormant',
+	'smtp',
+	'pending',
+	'{"title": "Workspace \"dev\" marked as dormant", "body": ""}',
+	'autobuild',
+	'2024-04-01 10:23:54+00',
+	'2024-04-01 10:23:54+00'
+);
+
+INSERT INTO notification_preferences
+	(user_id, template, disabled, created_at, updated_at)
+VALUES (
+	'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+	'workspace-deleted',
+	true,
+	'2024-04-01 10:23:54+00',
+	'2024-04-01 10:23:54+00'
+);
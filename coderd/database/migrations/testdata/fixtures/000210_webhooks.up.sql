@@ -0,0 +1,26 @@
+INSERT INTO webhooks
+	(id, name, url, events, secret, created_at, updated_at)
+VALUES (
+	'7ab2b0b6-4b61-4e05-9ad4-7b56a1df4b4c',
+	'chat-bot',
+	'https://chat.example.com/hooks/coder',
+	'{workspace_build.failed,user.created}',
+	'signing-secret',
+	'2024-05-01 10:00:00+00',
+	'2024-05-01 10:00:00+00'
+);
+
+INSERT INTO webhook_deliveries
+	(id, webhook_id, event, payload, status, attempt_count, response_status, created_at, updated_at, delivered_at)
+VALUES (
+	'c3b7ffb0-2d5c-4e37-8f38-2b5ba3e8a1e4',
+	'7ab2b0b6-4b61-4e05-9ad4-7b56a1df4b4c',
+	'user.created',
+	'{"event": "user.created", "data": {}}',
+	'succeeded',
+	1,
+	200,
+	'2024-05-01 10:05:00+00',
+	'2024-05-01 10:05:00+00',
+	'2024-05-01 10:05:00+00'
+);
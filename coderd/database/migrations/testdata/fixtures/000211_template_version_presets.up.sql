@@ -0,0 +1,16 @@
+INSERT INTO template_version_presets
+	(id, template_version_id, name, created_at)
+VALUES (
+	'a2ea1f2c-1b8e-4f4b-9c6a-2d1e0f3b7c55',
+	'920baba5-4c64-4686-8b7d-d1bef5683eae',
+	'Small',
+	'2024-05-01 12:00:00+00'
+);
+
+INSERT INTO template_version_preset_parameters
+	(template_version_preset_id, name, value)
+VALUES (
+	'a2ea1f2c-1b8e-4f4b-9c6a-2d1e0f3b7c55',
+	'cpu',
+	'2'
+);
@@ -0,0 +1,7 @@
+INSERT INTO workspace_prebuilds
+	(workspace_id, template_version_preset_id, created_at)
+VALUES (
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'a2ea1f2c-1b8e-4f4b-9c6a-2d1e0f3b7c55',
+	'2024-05-01 12:00:00+00'
+);
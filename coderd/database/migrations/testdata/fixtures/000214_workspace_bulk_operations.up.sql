@@ -0,0 +1,24 @@
+INSERT INTO workspace_bulk_operations
+	(id, initiator_id, action, query, concurrency, status, created_at, updated_at, completed_at)
+VALUES (
+	'f4b3c1a2-6d5e-4f7a-8b9c-0d1e2f3a4b5c',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'update',
+	'template:docker',
+	10,
+	'completed',
+	'2024-05-01 12:00:00+00',
+	'2024-05-01 12:05:00+00',
+	'2024-05-01 12:05:00+00'
+);
+
+INSERT INTO workspace_bulk_operation_workspaces
+	(operation_id, workspace_id, status, build_id, error, updated_at)
+VALUES (
+	'f4b3c1a2-6d5e-4f7a-8b9c-0d1e2f3a4b5c',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'succeeded',
+	'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+	'',
+	'2024-05-01 12:05:00+00'
+);
@@ -0,0 +1,12 @@
+INSERT INTO template_release_channels
+	(id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at)
+VALUES (
+	'6d2c0b9e-3f1a-4c8e-9b7d-5a4e3f2c1b0a',
+	'4cc1f466-f326-477e-8762-9d0c6781fc56',
+	'canary',
+	'4e681a60-83da-42c2-902e-6535376ebb77',
+	ARRAY['30095c71-380b-457a-8995-97b8ee6e5307']::uuid[],
+	ARRAY['bb640d07-ca8a-4869-b6bc-ae61ebb2fda1']::uuid[],
+	'2024-05-01 12:00:00+00',
+	'2024-05-01 12:00:00+00'
+);
@@ -0,0 +1,19 @@
+INSERT INTO workspace_snapshots
+	(id, workspace_id, name, build_id, artifacts, created_at)
+VALUES (
+	'c4b3a291-8d7e-4f6a-9e5d-3c2b1a0f9e8d',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'datasets',
+	'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+	'{"home": "snap-0123456789abcdef0"}'::jsonb,
+	'2024-05-01 12:00:00+00'
+);
+
+INSERT INTO workspace_snapshot_restores
+	(workspace_id, snapshot_id, artifacts, created_at)
+VALUES (
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'c4b3a291-8d7e-4f6a-9e5d-3c2b1a0f9e8d',
+	'{"home": "snap-0123456789abcdef0"}'::jsonb,
+	'2024-05-01 12:00:00+00'
+);
@@ -0,0 +1,13 @@
+INSERT INTO custom_roles
+	(id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at)
+VALUES (
+	'7b2a4c1e-9d3f-4e8a-b6c5-1f0e9d8c7b6a',
+	'template-operator',
+	'Template Operator',
+	NULL,
+	'[{"negate": false, "resource_type": "template", "action": "read"}, {"negate": false, "resource_type": "template", "action": "update"}]'::jsonb,
+	'[]'::jsonb,
+	'[]'::jsonb,
+	'2024-05-01 12:00:00+00',
+	'2024-05-01 12:00:00+00'
+);
@@ -0,0 +1,12 @@
+INSERT INTO oidc_sync_rules
+	(id, claim, pattern, site_roles, organization_id, organization_roles, created_at, updated_at)
+VALUES (
+	'3c8e5a1f-6b2d-4f7e-9a0c-2d4b6f8e1a3c',
+	'groups',
+	'^platform-admins$',
+	'{template-admin}'::text[],
+	NULL,
+	'{}'::text[],
+	'2024-05-01 12:00:00+00',
+	'2024-05-01 12:00:00+00'
+);
@@ -0,0 +1,11 @@
+INSERT INTO scim_tokens
+	(id, name, created_at, last_used_at, hashed_secret, display_secret, secret_prefix)
+VALUES (
+	'5f1d7c3a-2e4b-4a9c-8d6f-1b3e5a7c9d2f',
+	'okta',
+	'2024-05-01 12:00:00+00',
+	NULL,
+	'\xdeadbeef'::bytea,
+	'abcdef',
+	'\x6f6b7461'::bytea
+);
@@ -0,0 +1,19 @@
+INSERT INTO user_login_attempts
+	(id, user_id, ip, user_agent, success, created_at)
+VALUES (
+	'8a4c2e6f-1b3d-4f5a-9c7e-2d4f6a8b0c1e',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'127.0.0.1',
+	'Mozilla/5.0',
+	false,
+	'2024-05-01 12:00:00+00'
+);
+
+INSERT INTO user_login_lockouts
+	(user_id, failed_attempts, created_at, locked_until)
+VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	10,
+	'2024-05-01 12:00:00+00',
+	'2024-05-01 12:15:00+00'
+);
@@ -0,0 +1,10 @@
+INSERT INTO user_totp
+	(user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at)
+VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'JBSWY3DPEHPK3PXP',
+	'2024-05-01 12:05:00+00',
+	57142857,
+	ARRAY['6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b'],
+	'2024-05-01 12:00:00+00'
+);
@@ -86,6 +86,21 @@ func (g Group) Auditable(users []User) AuditableGroup {
 	}
 }
 
+// AuditableUserLoginLockout is a login lockout along with the username of the
+// locked user, which is used as the audit log target.
+type AuditableUserLoginLockout struct {
+	UserLoginLockout
+	Username string `json:"username"`
+}
+
+// Auditable returns an object that can be used in audit logs.
+func (l UserLoginLockout) Auditable(username string) AuditableUserLoginLockout {
+	return AuditableUserLoginLockout{
+		UserLoginLockout: l,
+		Username:         username,
+	}
+}
+
 const EveryoneGroup = "Everyone"
 
 func (s APIKeyScope) ToRBAC() rbac.ScopeName {
@@ -94,6 +109,16 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 		return rbac.ScopeAll
 	case APIKeyScopeApplicationConnect:
 		return rbac.ScopeApplicationConnect
+	case APIKeyScopeWorkspaceRead:
+		return rbac.ScopeWorkspaceRead
+	case APIKeyScopeWorkspaceStartStop:
+		return rbac.ScopeWorkspaceStartStop
+	case APIKeyScopeTemplateRead:
+		return rbac.ScopeTemplateRead
+	case APIKeyScopeTemplatePush:
+		return rbac.ScopeTemplatePush
+	case APIKeyScopeUserRead:
+		return rbac.ScopeUserRead
 	default:
 		panic("developer error: unknown scope type " + string(s))
 	}
@@ -156,7 +181,9 @@ func (w GetWorkspaceByAgentIDRow) RBACObject() rbac.Object {
 func (w Workspace) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.read()).
+		WithGroupACL(w.GroupACL.read())
 }
 
 func (w Workspace) ExecutionRBAC() rbac.Object {
@@ -168,7 +195,9 @@ func (w Workspace) ExecutionRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceExecution.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.connect()).
+		WithGroupACL(w.GroupACL.connect())
 }
 
 func (w Workspace) ApplicationConnectRBAC() rbac.Object {
@@ -180,7 +209,9 @@ func (w Workspace) ApplicationConnectRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceApplicationConnect.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.connect()).
+		WithGroupACL(w.GroupACL.connect())
 }
 
 func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Object {
@@ -196,7 +227,9 @@ func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Objec
 	return rbac.ResourceWorkspaceBuild.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w Workspace) DormantRBAC() rbac.Object {
@@ -206,6 +239,27 @@ func (w Workspace) DormantRBAC() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+// connect returns the ACL for connecting to the workspace. Everyone the
+// workspace is shared with may connect to it, regardless of their role.
+func (a WorkspaceACL) connect() map[string][]rbac.Action {
+	acl := make(map[string][]rbac.Action, len(a))
+	for id := range a {
+		acl[id] = []rbac.Action{rbac.ActionCreate}
+	}
+	return acl
+}
+
+// read returns the ACL for the workspace itself. Everyone the workspace is
+// shared with may read it, but only the admin role may update it, and only
+// by building it, see WorkspaceBuildRBAC.
+func (a WorkspaceACL) read() map[string][]rbac.Action {
+	acl := make(map[string][]rbac.Action, len(a))
+	for id := range a {
+		acl[id] = []rbac.Action{rbac.ActionRead}
+	}
+	return acl
+}
+
 func (m OrganizationMember) RBACObject() rbac.Object {
 	return rbac.ResourceOrganizationMember.
 		WithID(m.UserID).
@@ -227,7 +281,7 @@ func (o Organization) RBACObject() rbac.Object {
 }
 
 func (p ProvisionerDaemon) RBACObject() rbac.Object {
-	return rbac.ResourceProvisionerDaemon.WithID(p.ID)
+	return rbac.ResourceProvisionerDaemon.WithID(p.ID).InOrg(p.OrganizationID)
 }
 
 func (w WorkspaceProxy) RBACObject() rbac.Object {
@@ -306,6 +360,10 @@ func (a GetOAuth2ProviderAppsByUserIDRow) RBACObject() rbac.Object {
 	return a.OAuth2ProviderApp.RBACObject()
 }
 
+func (o WorkspaceBulkOperation) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspaceBulkOperation.WithID(o.ID).WithOwner(o.InitiatorID.String())
+}
+
 type WorkspaceAgentConnectionStatus struct {
 	Status           WorkspaceAgentStatus `json:"status"`
 	FirstConnectedAt *time.Time           `json:"first_connected_at"`
@@ -365,19 +423,20 @@ func ConvertUserRows(rows []GetUsersRow) []User {
 	users := make([]User, len(rows))
 	for i, r := range rows {
 		users[i] = User{
-			ID:              r.ID,
-			Email:           r.Email,
-			Username:        r.Username,
-			HashedPassword:  r.HashedPassword,
-			CreatedAt:       r.CreatedAt,
-			UpdatedAt:       r.UpdatedAt,
-			Status:          r.Status,
-			RBACRoles:       r.RBACRoles,
-			LoginType:       r.LoginType,
-			AvatarURL:       r.AvatarURL,
-			Deleted:         r.Deleted,
-			LastSeenAt:      r.LastSeenAt,
-			ThemePreference: r.ThemePreference,
+			ID:               r.ID,
+			Email:            r.Email,
+			Username:         r.Username,
+			HashedPassword:   r.HashedPassword,
+			CreatedAt:        r.CreatedAt,
+			UpdatedAt:        r.UpdatedAt,
+			Status:           r.Status,
+			RBACRoles:        r.RBACRoles,
+			LoginType:        r.LoginType,
+			AvatarURL:        r.AvatarURL,
+			Deleted:          r.Deleted,
+			LastSeenAt:       r.LastSeenAt,
+			ThemePreference:  r.ThemePreference,
+			IsServiceAccount: r.IsServiceAccount,
 		}
 	}
 
@@ -403,6 +462,8 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			DeletingAt:        r.DeletingAt,
 			AutomaticUpdates:  r.AutomaticUpdates,
 			Favorite:          r.Favorite,
+			UserACL:           r.UserACL,
+			GroupACL:          r.GroupACL,
 		}
 	}
 
@@ -0,0 +1,26 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestAPIKeyScopes(t *testing.T) {
+	t.Parallel()
+
+	sdkScopes := make([]database.APIKeyScope, 0, len(codersdk.APIKeyScopes))
+	for _, scope := range codersdk.APIKeyScopes {
+		sdkScopes = append(sdkScopes, database.APIKeyScope(scope))
+	}
+	require.ElementsMatch(t, database.AllAPIKeyScopeValues(), sdkScopes)
+
+	for _, scope := range database.AllAPIKeyScopeValues() {
+		expanded, err := scope.ToRBAC().Expand()
+		require.NoError(t, err, scope)
+		require.NotEmpty(t, expanded.Site, scope)
+	}
+}
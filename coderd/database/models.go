@@ -21,6 +21,11 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeWorkspaceRead      APIKeyScope = "workspace:read"
+	APIKeyScopeWorkspaceStartStop APIKeyScope = "workspace:start-stop"
+	APIKeyScopeTemplateRead       APIKeyScope = "template:read"
+	APIKeyScopeTemplatePush       APIKeyScope = "template:push"
+	APIKeyScopeUserRead           APIKeyScope = "user:read"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +66,12 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceRead,
+		APIKeyScopeWorkspaceStartStop,
+		APIKeyScopeTemplateRead,
+		APIKeyScopeTemplatePush,
+		APIKeyScopeUserRead:
 		return true
 	}
 	return false
@@ -71,6 +81,11 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeWorkspaceRead,
+		APIKeyScopeWorkspaceStartStop,
+		APIKeyScopeTemplateRead,
+		APIKeyScopeTemplatePush,
+		APIKeyScopeUserRead,
 	}
 }
 
@@ -411,6 +426,7 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOidc GroupSource = "oidc"
+	GroupSourceScim GroupSource = "scim"
 )
 
 func (e *GroupSource) Scan(src interface{}) error {
@@ -451,7 +467,8 @@ func (ns NullGroupSource) Value() (driver.Value, error) {
 func (e GroupSource) Valid() bool {
 	switch e {
 	case GroupSourceUser,
-		GroupSourceOidc:
+		GroupSourceOidc,
+		GroupSourceScim:
 		return true
 	}
 	return false
@@ -461,6 +478,7 @@ func AllGroupSourceValues() []GroupSource {
 	return []GroupSource{
 		GroupSourceUser,
 		GroupSourceOidc,
+		GroupSourceScim,
 	}
 }
 
@@ -660,6 +678,131 @@ func AllLoginTypeValues() []LoginType {
 	}
 }
 
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending          NotificationMessageStatus = "pending"
+	NotificationMessageStatusLeased           NotificationMessageStatus = "leased"
+	NotificationMessageStatusSent             NotificationMessageStatus = "sent"
+	NotificationMessageStatusTemporaryFailure NotificationMessageStatus = "temporary_failure"
+	NotificationMessageStatusPermanentFailure NotificationMessageStatus = "permanent_failure"
+)
+
+func (e *NotificationMessageStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMessageStatus(s)
+	case string:
+		*e = NotificationMessageStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMessageStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMessageStatus struct {
+	NotificationMessageStatus NotificationMessageStatus `json:"notification_message_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if NotificationMessageStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMessageStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMessageStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMessageStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMessageStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMessageStatus), nil
+}
+
+func (e NotificationMessageStatus) Valid() bool {
+	switch e {
+	case NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusTemporaryFailure,
+		NotificationMessageStatusPermanentFailure:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMessageStatusValues() []NotificationMessageStatus {
+	return []NotificationMessageStatus{
+		NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusTemporaryFailure,
+		NotificationMessageStatusPermanentFailure,
+	}
+}
+
+type NotificationMethod string
+
+const (
+	NotificationMethodSmtp    NotificationMethod = "smtp"
+	NotificationMethodWebhook NotificationMethod = "webhook"
+)
+
+func (e *NotificationMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMethod(s)
+	case string:
+		*e = NotificationMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMethod: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMethod struct {
+	NotificationMethod NotificationMethod `json:"notification_method"`
+	Valid              bool               `json:"valid"` // Valid is true if NotificationMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMethod), nil
+}
+
+func (e NotificationMethod) Valid() bool {
+	switch e {
+	case NotificationMethodSmtp,
+		NotificationMethodWebhook:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMethodValues() []NotificationMethod {
+	return []NotificationMethod{
+		NotificationMethodSmtp,
+		NotificationMethodWebhook,
+	}
+}
+
 type ParameterDestinationScheme string
 
 const (
@@ -1149,8 +1292,10 @@ func AllProvisionerStorageMethodValues() []ProvisionerStorageMethod {
 type ProvisionerType string
 
 const (
-	ProvisionerTypeEcho      ProvisionerType = "echo"
-	ProvisionerTypeTerraform ProvisionerType = "terraform"
+	ProvisionerTypeEcho       ProvisionerType = "echo"
+	ProvisionerTypeTerraform  ProvisionerType = "terraform"
+	ProvisionerTypeOpentofu   ProvisionerType = "opentofu"
+	ProvisionerTypeKubernetes ProvisionerType = "kubernetes"
 )
 
 func (e *ProvisionerType) Scan(src interface{}) error {
@@ -1191,7 +1336,9 @@ func (ns NullProvisionerType) Value() (driver.Value, error) {
 func (e ProvisionerType) Valid() bool {
 	switch e {
 	case ProvisionerTypeEcho,
-		ProvisionerTypeTerraform:
+		ProvisionerTypeTerraform,
+		ProvisionerTypeOpentofu,
+		ProvisionerTypeKubernetes:
 		return true
 	}
 	return false
@@ -1201,6 +1348,8 @@ func AllProvisionerTypeValues() []ProvisionerType {
 	return []ProvisionerType{
 		ProvisionerTypeEcho,
 		ProvisionerTypeTerraform,
+		ProvisionerTypeOpentofu,
+		ProvisionerTypeKubernetes,
 	}
 }
 
@@ -1222,6 +1371,11 @@ const (
 	ResourceTypeHealthSettings          ResourceType = "health_settings"
 	ResourceTypeOauth2ProviderApp       ResourceType = "oauth2_provider_app"
 	ResourceTypeOauth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeWebhook                 ResourceType = "webhook"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeOIDCSyncRule            ResourceType = "oidc_sync_rule"
+	ResourceTypeSCIMToken               ResourceType = "scim_token"
+	ResourceTypeUserLoginLockout        ResourceType = "user_login_lockout"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1275,7 +1429,12 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeConvertLogin,
 		ResourceTypeHealthSettings,
 		ResourceTypeOauth2ProviderApp,
-		ResourceTypeOauth2ProviderAppSecret:
+		ResourceTypeOauth2ProviderAppSecret,
+		ResourceTypeWebhook,
+		ResourceTypeCustomRole,
+		ResourceTypeOIDCSyncRule,
+		ResourceTypeSCIMToken,
+		ResourceTypeUserLoginLockout:
 		return true
 	}
 	return false
@@ -1298,6 +1457,11 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeHealthSettings,
 		ResourceTypeOauth2ProviderApp,
 		ResourceTypeOauth2ProviderAppSecret,
+		ResourceTypeWebhook,
+		ResourceTypeCustomRole,
+		ResourceTypeOIDCSyncRule,
+		ResourceTypeSCIMToken,
+		ResourceTypeUserLoginLockout,
 	}
 }
 
@@ -1417,6 +1581,64 @@ func AllTailnetStatusValues() []TailnetStatus {
 	}
 }
 
+type TemplateVersionDiagnosticSeverity string
+
+const (
+	TemplateVersionDiagnosticSeverityWarning TemplateVersionDiagnosticSeverity = "warning"
+	TemplateVersionDiagnosticSeverityError   TemplateVersionDiagnosticSeverity = "error"
+)
+
+func (e *TemplateVersionDiagnosticSeverity) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionDiagnosticSeverity(s)
+	case string:
+		*e = TemplateVersionDiagnosticSeverity(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionDiagnosticSeverity: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionDiagnosticSeverity struct {
+	TemplateVersionDiagnosticSeverity TemplateVersionDiagnosticSeverity `json:"template_version_diagnostic_severity"`
+	Valid                             bool                              `json:"valid"` // Valid is true if TemplateVersionDiagnosticSeverity is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionDiagnosticSeverity) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionDiagnosticSeverity, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionDiagnosticSeverity.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionDiagnosticSeverity) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionDiagnosticSeverity), nil
+}
+
+func (e TemplateVersionDiagnosticSeverity) Valid() bool {
+	switch e {
+	case TemplateVersionDiagnosticSeverityWarning,
+		TemplateVersionDiagnosticSeverityError:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionDiagnosticSeverityValues() []TemplateVersionDiagnosticSeverity {
+	return []TemplateVersionDiagnosticSeverity{
+		TemplateVersionDiagnosticSeverityWarning,
+		TemplateVersionDiagnosticSeverityError,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -1479,6 +1701,73 @@ func AllUserStatusValues() []UserStatus {
 	}
 }
 
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending          WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusLeased           WebhookDeliveryStatus = "leased"
+	WebhookDeliveryStatusSucceeded        WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusTemporaryFailure WebhookDeliveryStatus = "temporary_failure"
+	WebhookDeliveryStatusPermanentFailure WebhookDeliveryStatus = "permanent_failure"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusLeased,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusTemporaryFailure,
+		WebhookDeliveryStatusPermanentFailure:
+		return true
+	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusLeased,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusTemporaryFailure,
+		WebhookDeliveryStatusPermanentFailure,
+	}
+}
+
 type WorkspaceAgentLifecycleState string
 
 const (
@@ -1686,6 +1975,198 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceBulkAction string
+
+const (
+	WorkspaceBulkActionStart  WorkspaceBulkAction = "start"
+	WorkspaceBulkActionStop   WorkspaceBulkAction = "stop"
+	WorkspaceBulkActionUpdate WorkspaceBulkAction = "update"
+	WorkspaceBulkActionDelete WorkspaceBulkAction = "delete"
+)
+
+func (e *WorkspaceBulkAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBulkAction(s)
+	case string:
+		*e = WorkspaceBulkAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBulkAction: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBulkAction struct {
+	WorkspaceBulkAction WorkspaceBulkAction `json:"workspace_bulk_action"`
+	Valid               bool                `json:"valid"` // Valid is true if WorkspaceBulkAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBulkAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBulkAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBulkAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBulkAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBulkAction), nil
+}
+
+func (e WorkspaceBulkAction) Valid() bool {
+	switch e {
+	case WorkspaceBulkActionStart,
+		WorkspaceBulkActionStop,
+		WorkspaceBulkActionUpdate,
+		WorkspaceBulkActionDelete:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceBulkActionValues() []WorkspaceBulkAction {
+	return []WorkspaceBulkAction{
+		WorkspaceBulkActionStart,
+		WorkspaceBulkActionStop,
+		WorkspaceBulkActionUpdate,
+		WorkspaceBulkActionDelete,
+	}
+}
+
+type WorkspaceBulkOperationStatus string
+
+const (
+	WorkspaceBulkOperationStatusRunning   WorkspaceBulkOperationStatus = "running"
+	WorkspaceBulkOperationStatusCompleted WorkspaceBulkOperationStatus = "completed"
+	WorkspaceBulkOperationStatusCanceled  WorkspaceBulkOperationStatus = "canceled"
+)
+
+func (e *WorkspaceBulkOperationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBulkOperationStatus(s)
+	case string:
+		*e = WorkspaceBulkOperationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBulkOperationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBulkOperationStatus struct {
+	WorkspaceBulkOperationStatus WorkspaceBulkOperationStatus `json:"workspace_bulk_operation_status"`
+	Valid                        bool                         `json:"valid"` // Valid is true if WorkspaceBulkOperationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBulkOperationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBulkOperationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBulkOperationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBulkOperationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBulkOperationStatus), nil
+}
+
+func (e WorkspaceBulkOperationStatus) Valid() bool {
+	switch e {
+	case WorkspaceBulkOperationStatusRunning,
+		WorkspaceBulkOperationStatusCompleted,
+		WorkspaceBulkOperationStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceBulkOperationStatusValues() []WorkspaceBulkOperationStatus {
+	return []WorkspaceBulkOperationStatus{
+		WorkspaceBulkOperationStatusRunning,
+		WorkspaceBulkOperationStatusCompleted,
+		WorkspaceBulkOperationStatusCanceled,
+	}
+}
+
+type WorkspaceBulkResultStatus string
+
+const (
+	WorkspaceBulkResultStatusPending   WorkspaceBulkResultStatus = "pending"
+	WorkspaceBulkResultStatusRunning   WorkspaceBulkResultStatus = "running"
+	WorkspaceBulkResultStatusSucceeded WorkspaceBulkResultStatus = "succeeded"
+	WorkspaceBulkResultStatusFailed    WorkspaceBulkResultStatus = "failed"
+	WorkspaceBulkResultStatusSkipped   WorkspaceBulkResultStatus = "skipped"
+)
+
+func (e *WorkspaceBulkResultStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBulkResultStatus(s)
+	case string:
+		*e = WorkspaceBulkResultStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBulkResultStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBulkResultStatus struct {
+	WorkspaceBulkResultStatus WorkspaceBulkResultStatus `json:"workspace_bulk_result_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if WorkspaceBulkResultStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBulkResultStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBulkResultStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBulkResultStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBulkResultStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBulkResultStatus), nil
+}
+
+func (e WorkspaceBulkResultStatus) Valid() bool {
+	switch e {
+	case WorkspaceBulkResultStatusPending,
+		WorkspaceBulkResultStatusRunning,
+		WorkspaceBulkResultStatusSucceeded,
+		WorkspaceBulkResultStatusFailed,
+		WorkspaceBulkResultStatusSkipped:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceBulkResultStatusValues() []WorkspaceBulkResultStatus {
+	return []WorkspaceBulkResultStatus{
+		WorkspaceBulkResultStatusPending,
+		WorkspaceBulkResultStatusRunning,
+		WorkspaceBulkResultStatusSucceeded,
+		WorkspaceBulkResultStatusFailed,
+		WorkspaceBulkResultStatusSkipped,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1761,6 +2242,10 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// The owner who minted this key to act as user_id. Keys with an impersonator are short-lived and cannot perform sensitive actions.
+	ImpersonatorID uuid.NullUUID `db:"impersonator_id" json:"impersonator_id"`
+	// The user agent of the client that created the key, shown to users to tell their sessions apart.
+	UserAgent string `db:"user_agent" json:"user_agent"`
 }
 
 type AuditLog struct {
@@ -1779,6 +2264,24 @@ type AuditLog struct {
 	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
 	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	// The user who performed the action while impersonating user_id, if any.
+	ImpersonatorID uuid.NullUUID `db:"impersonator_id" json:"impersonator_id"`
+}
+
+// Roles defined at runtime in addition to the built in roles. Site roles are assigned to users, organization roles to organization members.
+type CustomRole struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	// The organization of organization roles, null for site roles.
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	// Permissions within the organization of the role. Only organization roles have them.
+	OrgPermissions CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	// Permissions on the resources owned by the user that has the role.
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
 }
 
 // A table used to store the keys used to encrypt the database.
@@ -1839,6 +2342,8 @@ type Group struct {
 	DisplayName string `db:"display_name" json:"display_name"`
 	// Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.
 	Source GroupSource `db:"source" json:"source"`
+	// The maximum number of workspaces each member of the group can own. Members of several groups get the highest limit. 0 means the group sets no limit.
+	MaxWorkspacesPerUser int32 `db:"max_workspaces_per_user" json:"max_workspaces_per_user"`
 }
 
 type GroupMember struct {
@@ -1864,6 +2369,36 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Queue of notifications waiting to be delivered, and a short history of delivered ones.
+type NotificationMessage struct {
+	ID     uuid.UUID `db:"id" json:"id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// Name of the notification template the message was rendered from.
+	Template     string                    `db:"template" json:"template"`
+	Method       NotificationMethod        `db:"method" json:"method"`
+	Status       NotificationMessageStatus `db:"status" json:"status"`
+	StatusReason sql.NullString            `db:"status_reason" json:"status_reason"`
+	// The rendered message, along with everything needed to deliver it.
+	Payload      json.RawMessage `db:"payload" json:"payload"`
+	AttemptCount int32           `db:"attempt_count" json:"attempt_count"`
+	// The component that enqueued the message.
+	CreatedBy      string       `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at" json:"updated_at"`
+	LeasedUntil    sql.NullTime `db:"leased_until" json:"leased_until"`
+	NextRetryAfter sql.NullTime `db:"next_retry_after" json:"next_retry_after"`
+	SentAt         sql.NullTime `db:"sent_at" json:"sent_at"`
+}
+
+// Per-user choices about which notifications to receive.
+type NotificationPreference struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
 type OAuth2ProviderApp struct {
 	ID          uuid.UUID `db:"id" json:"id"`
@@ -1907,6 +2442,22 @@ type OAuth2ProviderAppToken struct {
 	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
 }
 
+// Rules that assign roles and organization memberships to users whose OIDC claims match a pattern. They are applied on every OIDC login.
+type OIDCSyncRule struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The name of the claim to match. The claim can be a string or an array of strings.
+	Claim string `db:"claim" json:"claim"`
+	// A regular expression. The rule matches if any value of the claim matches it.
+	Pattern   string   `db:"pattern" json:"pattern"`
+	SiteRoles []string `db:"site_roles" json:"site_roles"`
+	// The organization users matching the rule are made a member of, null if the rule does not grant a membership.
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	// Roles in the organization of the rule. Only allowed if the rule has an organization.
+	OrganizationRoles []string  `db:"organization_roles" json:"organization_roles"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1991,6 +2542,8 @@ type ProvisionerJob struct {
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
 	// Computed column to track the status of the job.
 	JobStatus ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	// The required_version constraint of the template, only provisioner daemons with a satisfying engine version may acquire the job.
+	EngineVersionConstraint string `db:"engine_version_constraint" json:"engine_version_constraint"`
 }
 
 type ProvisionerJobLog struct {
@@ -2018,6 +2571,18 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Bearer tokens an identity provider uses to authenticate against the SCIM API.
+type SCIMToken struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	Name         string       `db:"name" json:"name"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	// The tail end of the original secret so secrets can be differentiated.
+	DisplaySecret string `db:"display_secret" json:"display_secret"`
+	SecretPrefix  []byte `db:"secret_prefix" json:"secret_prefix"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -2095,6 +2660,7 @@ type Template struct {
 	Deprecated                    string          `db:"deprecated" json:"deprecated"`
 	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	MaxWorkspaces                 int32           `db:"max_workspaces" json:"max_workspaces"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 }
@@ -2137,6 +2703,22 @@ type TemplateTable struct {
 	Deprecated          string          `db:"deprecated" json:"deprecated"`
 	ActivityBump        int64           `db:"activity_bump" json:"activity_bump"`
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	// The maximum number of workspaces that can be created from the template. 0 means no limit.
+	MaxWorkspaces int32 `db:"max_workspaces" json:"max_workspaces"`
+}
+
+// Named versions of a template used by the workspaces of specific users and groups instead of the active version.
+type TemplateReleaseChannel struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	Name              string    `db:"name" json:"name"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	// Users assigned to the channel. A user assigned directly takes precedence over an assignment through a group.
+	UserIDs []uuid.UUID `db:"user_ids" json:"user_ids"`
+	// Groups assigned to the channel. If several channels match the groups of a user, the first by name is used.
+	GroupIDs  []uuid.UUID `db:"group_ids" json:"group_ids"`
+	CreatedAt time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time   `db:"updated_at" json:"updated_at"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -2185,6 +2767,20 @@ type TemplateVersion struct {
 	CreatedByUsername     string          `db:"created_by_username" json:"created_by_username"`
 }
 
+// Problems found by statically checking the source of a template version on import.
+type TemplateVersionDiagnostic struct {
+	ID                uuid.UUID                         `db:"id" json:"id"`
+	TemplateVersionID uuid.UUID                         `db:"template_version_id" json:"template_version_id"`
+	Severity          TemplateVersionDiagnosticSeverity `db:"severity" json:"severity"`
+	// Identifies the check that found the problem.
+	Code    string `db:"code" json:"code"`
+	Summary string `db:"summary" json:"summary"`
+	Detail  string `db:"detail" json:"detail"`
+	// Path of the file relative to the root of the template.
+	Filename string `db:"filename" json:"filename"`
+	Line     int32  `db:"line" json:"line"`
+}
+
 type TemplateVersionParameter struct {
 	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 	// Parameter name
@@ -2221,6 +2817,23 @@ type TemplateVersionParameter struct {
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
 }
 
+type TemplateVersionPresetParameter struct {
+	TemplateVersionPresetID uuid.UUID `db:"template_version_preset_id" json:"template_version_preset_id"`
+	// The name of the template version parameter set by the preset.
+	Name  string `db:"name" json:"name"`
+	Value string `db:"value" json:"value"`
+}
+
+// Named sets of parameter values declared by a template version, which users can pick when creating a workspace.
+type TemplateVersionPreset struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	// The number of workspaces kept built with the preset while its template version is active.
+	DesiredPrebuildInstances int32 `db:"desired_prebuild_instances" json:"desired_prebuild_instances"`
+}
+
 type TemplateVersionTable struct {
 	ID             uuid.UUID     `db:"id" json:"id"`
 	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
@@ -2275,6 +2888,8 @@ type User struct {
 	ThemePreference string `db:"theme_preference" json:"theme_preference"`
 	// Name of the Coder user
 	Name string `db:"name" json:"name"`
+	// Service accounts are non-human users. They cannot log in and only authenticate with API tokens. They are never marked dormant and do not count towards licensed seats.
+	IsServiceAccount bool `db:"is_service_account" json:"is_service_account"`
 }
 
 type UserLink struct {
@@ -2292,6 +2907,38 @@ type UserLink struct {
 	DebugContext json.RawMessage `db:"debug_context" json:"debug_context"`
 }
 
+// Password login attempts, used to slow down brute-force attacks and to detect logins from new IP addresses or user agents.
+type UserLoginAttempt struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// NULL when the login did not match a user.
+	UserID    uuid.NullUUID `db:"user_id" json:"user_id"`
+	Ip        pqtype.Inet   `db:"ip" json:"ip"`
+	UserAgent string        `db:"user_agent" json:"user_agent"`
+	Success   bool          `db:"success" json:"success"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+// Users blocked from password login after too many failed attempts.
+type UserLoginLockout struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+}
+
+// TOTP second factors for password logins.
+type UserTOTP struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Secret string    `db:"secret" json:"secret"`
+	// NULL while the user has not yet confirmed enrollment with a valid code.
+	EnabledAt sql.NullTime `db:"enabled_at" json:"enabled_at"`
+	// The time step of the last accepted code, so codes cannot be replayed.
+	LastUsedStep int64 `db:"last_used_step" json:"last_used_step"`
+	// SHA-256 hashes of the unused single-use recovery codes.
+	HashedRecoveryCodes []string  `db:"hashed_recovery_codes" json:"hashed_recovery_codes"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -2299,6 +2946,39 @@ type VisibleUser struct {
 	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
 }
 
+// Endpoints that are sent a request whenever one of the subscribed events happens.
+type Webhook struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Name string    `db:"name" json:"name"`
+	Url  string    `db:"url" json:"url"`
+	// The events the webhook is subscribed to. An empty list subscribes to every event.
+	Events []string `db:"events" json:"events"`
+	// Used to sign request bodies with HMAC-SHA256 so receivers can verify them. Empty if requests are not signed.
+	Secret    string    `db:"secret" json:"secret"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Queue of webhook requests waiting to be sent, and a log of the ones already attempted.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `db:"id" json:"id"`
+	WebhookID      uuid.UUID             `db:"webhook_id" json:"webhook_id"`
+	Event          string                `db:"event" json:"event"`
+	Payload        json.RawMessage       `db:"payload" json:"payload"`
+	Status         WebhookDeliveryStatus `db:"status" json:"status"`
+	AttemptCount   int32                 `db:"attempt_count" json:"attempt_count"`
+	ResponseStatus sql.NullInt32         `db:"response_status" json:"response_status"`
+	// The start of the body of the last response, kept to help debug failed deliveries.
+	ResponseBody     sql.NullString `db:"response_body" json:"response_body"`
+	Error            sql.NullString `db:"error" json:"error"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+	LeasedUntil      sql.NullTime   `db:"leased_until" json:"leased_until"`
+	NextAttemptAfter sql.NullTime   `db:"next_attempt_after" json:"next_attempt_after"`
+	DeliveredAt      sql.NullTime   `db:"delivered_at" json:"delivered_at"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -2315,7 +2995,9 @@ type Workspace struct {
 	DeletingAt        sql.NullTime     `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	// Favorite is true if the workspace owner has favorited the workspace.
-	Favorite bool `db:"favorite" json:"favorite"`
+	Favorite bool         `db:"favorite" json:"favorite"`
+	UserACL  WorkspaceACL `db:"user_acl" json:"user_acl"`
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
 }
 
 type WorkspaceAgent struct {
@@ -2524,6 +3206,39 @@ type WorkspaceBuildTable struct {
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
 }
 
+// Builds of many workspaces requested at once, executed in the background with a limited number of builds in progress.
+type WorkspaceBulkOperation struct {
+	ID          uuid.UUID           `db:"id" json:"id"`
+	InitiatorID uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	Action      WorkspaceBulkAction `db:"action" json:"action"`
+	// The workspace search query that selected the workspaces.
+	Query string `db:"query" json:"query"`
+	// The maximum number of builds of the operation in progress at once.
+	Concurrency int32                        `db:"concurrency" json:"concurrency"`
+	Status      WorkspaceBulkOperationStatus `db:"status" json:"status"`
+	CreatedAt   time.Time                    `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time                    `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime                 `db:"completed_at" json:"completed_at"`
+}
+
+// The outcome of a bulk operation for each of its workspaces.
+type WorkspaceBulkOperationWorkspace struct {
+	OperationID uuid.UUID                 `db:"operation_id" json:"operation_id"`
+	WorkspaceID uuid.UUID                 `db:"workspace_id" json:"workspace_id"`
+	Status      WorkspaceBulkResultStatus `db:"status" json:"status"`
+	BuildID     uuid.NullUUID             `db:"build_id" json:"build_id"`
+	// Why the workspace was skipped or its build failed.
+	Error     string    `db:"error" json:"error"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Workspaces built ahead of time by the prebuilds reconciler that have not been claimed by a user yet.
+type WorkspacePrebuild struct {
+	WorkspaceID             uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionPresetID uuid.UUID `db:"template_version_preset_id" json:"template_version_preset_id"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceProxy struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -2567,3 +3282,24 @@ type WorkspaceResourceMetadatum struct {
 	Sensitive           bool           `db:"sensitive" json:"sensitive"`
 	ID                  int64          `db:"id" json:"id"`
 }
+
+// Snapshots of the data of a workspace, taken by the template during a stop build.
+type WorkspaceSnapshot struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	// The stop build that takes the snapshot.
+	BuildID uuid.UUID `db:"build_id" json:"build_id"`
+	// Map of the artifacts the template returned as "snapshot." prefixed resource metadata, e.g. volume snapshot IDs.
+	Artifacts json.RawMessage `db:"artifacts" json:"artifacts"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Workspaces created from a snapshot. The artifacts are passed to every build of the workspace so the template keeps the restored data.
+type WorkspaceSnapshotRestore struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The snapshot the workspace was created from, null once the snapshot is deleted.
+	SnapshotID uuid.NullUUID   `db:"snapshot_id" json:"snapshot_id"`
+	Artifacts  json.RawMessage `db:"artifacts" json:"artifacts"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
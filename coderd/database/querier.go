@@ -17,6 +17,13 @@ type sqlcQuerier interface {
 	// This must be called from within a transaction. The lock will be automatically
 	// released when the transaction ends.
 	AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error
+	// Acquires a batch of messages that are waiting to be delivered with the
+	// given method: new messages, failed messages whose retry is due, and
+	// leased messages whose lease expired without an outcome being recorded.
+	//
+	// SKIP LOCKED is used to jump over locked rows. This prevents
+	// multiple coderd replicas from acquiring the same messages.
+	AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error)
 	// Acquires the lock for a single job that isn't started, completed,
 	// canceled, and that matches an array of provisioner types.
 	//
@@ -24,6 +31,13 @@ type sqlcQuerier interface {
 	// multiple provisioners from acquiring the same jobs. See:
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 	AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error)
+	// Acquires a batch of deliveries that are waiting to be sent: new
+	// deliveries, failed deliveries whose retry is due, and leased deliveries
+	// whose lease expired without an outcome being recorded.
+	//
+	// SKIP LOCKED is used to jump over locked rows. This prevents
+	// multiple coderd replicas from acquiring the same deliveries.
+	AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]WebhookDelivery, error)
 	// Bumps the workspace deadline by the template's configured "activity_bump"
 	// duration (default 1h). If the workspace bump will cross an autostart
 	// threshold, then the bump is autostart + TTL. This is the deadline behavior if
@@ -45,6 +59,9 @@ type sqlcQuerier interface {
 	// referenced by the latest build of a workspace.
 	ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error)
 	BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg BatchUpdateWorkspaceLastUsedAtParams) error
+	// Transfers the oldest ready prebuild of a preset to a new owner. A prebuild
+	// is ready once its latest build started it successfully.
+	ClaimWorkspacePrebuild(ctx context.Context, arg ClaimWorkspacePrebuildParams) (Workspace, error)
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
@@ -54,6 +71,9 @@ type sqlcQuerier interface {
 	DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
+	// Deleting a role also unassigns it, so users are never left with roles that
+	// cannot be expanded.
+	DeleteCustomRole(ctx context.Context, id uuid.UUID) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
@@ -64,23 +84,50 @@ type sqlcQuerier interface {
 	DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error
 	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
 	DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error
+	DeleteOIDCSyncRule(ctx context.Context, id uuid.UUID) error
+	// Delete messages that reached a final state more than a week ago.
+	DeleteOldNotificationMessages(ctx context.Context) error
 	// Delete provisioner daemons that have been created at least a week ago
 	// and have not connected to coderd since a week.
 	// A provisioner daemon with "zeroed" last_seen_at column indicates possible
 	// connectivity issues (no provisioner daemon activity since registration).
 	DeleteOldProvisionerDaemons(ctx context.Context) error
+	// Login history is kept for 90 days to detect logins from new IP addresses or
+	// user agents.
+	DeleteOldUserLoginAttempts(ctx context.Context) error
+	// Delete deliveries that reached a final state more than a week ago.
+	DeleteOldWebhookDeliveries(ctx context.Context) error
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentLogs(ctx context.Context) error
 	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	// Clears the provisioner state of all but the most recent @keep builds of
+	// every workspace. The build rows themselves are retained.
+	DeleteOldWorkspaceBuildProvisionerStates(ctx context.Context, keep int32) error
+	// Removes the user from the organization and from the groups of the
+	// organization.
+	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
+	// Deletes all keys of the user except except_id, returning the deleted keys.
+	// Tokens are only deleted if include_tokens is set.
+	DeleteOtherAPIKeysByUserID(ctx context.Context, arg DeleteOtherAPIKeysByUserIDParams) ([]APIKey, error)
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
+	DeleteSCIMToken(ctx context.Context, id uuid.UUID) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
 	DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error)
 	DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
+	DeleteTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) error
+	DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error
+	DeleteUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteWebhookByID(ctx context.Context, id uuid.UUID) error
 	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
 	DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context, templateID uuid.UUID) error
+	DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx context.Context) error
+	DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error
+	EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (UserTOTP, error)
+	EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) (NotificationMessage, error)
 	FavoriteWorkspace(ctx context.Context, id uuid.UUID) error
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
 	// there is no unique constraint on empty token names
@@ -88,6 +135,10 @@ type sqlcQuerier interface {
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	// Returns the user's unexpired keys, such as browser sessions, CLI logins and
+	// tokens, most recently used first.
+	GetActiveAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+	// Service accounts are not counted, as they do not use a licensed seat.
 	GetActiveUserCount(ctx context.Context) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
@@ -103,6 +154,8 @@ type sqlcQuerier interface {
 	// This function returns roles for authorization purposes. Implied member roles
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
+	GetCustomRoleByName(ctx context.Context, arg GetCustomRoleByNameParams) (CustomRole, error)
+	GetCustomRoles(ctx context.Context) ([]CustomRole, error)
 	GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error)
 	GetDERPMeshKey(ctx context.Context) (string, error)
 	GetDefaultOrganization(ctx context.Context) (Organization, error)
@@ -127,6 +180,8 @@ type sqlcQuerier interface {
 	GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error)
 	GetHealthSettings(ctx context.Context) (string, error)
 	GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error)
+	// Returns the unexpired keys other users have minted to impersonate the user.
+	GetImpersonationAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
 	GetJFrogXrayScanByWorkspaceAndAgentID(ctx context.Context, arg GetJFrogXrayScanByWorkspaceAndAgentIDParams) (JfrogXrayScan, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
@@ -134,7 +189,12 @@ type sqlcQuerier interface {
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
 	GetLicenses(ctx context.Context) ([]License, error)
+	// Counts the failed password logins since @since for the user and for the IP
+	// address, along with the time of the latest failure of each. The times fall
+	// back to @since when there are no failures.
+	GetLoginFailureStats(ctx context.Context, arg GetLoginFailureStatsParams) (GetLoginFailureStatsRow, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error)
 	GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error)
 	GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error)
@@ -145,6 +205,8 @@ type sqlcQuerier interface {
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]GetOAuth2ProviderAppsByUserIDRow, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
+	GetOIDCSyncRuleByID(ctx context.Context, id uuid.UUID) (OIDCSyncRule, error)
+	GetOIDCSyncRules(ctx context.Context) ([]OIDCSyncRule, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
@@ -153,23 +215,47 @@ type sqlcQuerier interface {
 	GetOrganizations(ctx context.Context) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// Returns the distinct engine version constraints of the jobs waiting for a
+	// provisioner daemon, so daemons can check which of them they satisfy before
+	// acquiring a job.
+	GetPendingProvisionerJobEngineVersionConstraints(ctx context.Context, organizationID uuid.UUID) ([]GetPendingProvisionerJobEngineVersionConstraintsRow, error)
+	// Returns the presets of the active version of every template that want
+	// workspaces built ahead of time.
+	GetPrebuildPresets(ctx context.Context) ([]GetPrebuildPresetsRow, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
+	GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error)
 	GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
-	GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error)
-	GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	// Returns the sum of the allowances of the groups of the user in the
+	// organization, including the Everyone group. Users that aren't members of the
+	// organization have no allowance.
+	GetQuotaAllowanceForUser(ctx context.Context, arg GetQuotaAllowanceForUserParams) (int64, error)
+	GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaConsumedForUserParams) (int64, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
+	GetRunningWorkspaceBulkOperations(ctx context.Context) ([]WorkspaceBulkOperation, error)
+	GetSCIMTokenByID(ctx context.Context, id uuid.UUID) (SCIMToken, error)
+	GetSCIMTokenByPrefix(ctx context.Context, secretPrefix []byte) (SCIMToken, error)
+	GetSCIMTokens(ctx context.Context) ([]SCIMToken, error)
 	GetServiceBanner(ctx context.Context) (string, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
 	GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error)
 	GetTailnetTunnelPeerBindings(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerBindingsRow, error)
 	GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerIDsRow, error)
+	// Returns the version of the template that the workspaces of the user use.
+	// This is the version of the release channel the user is assigned to, or the
+	// active version of the template if the user isn't assigned to any channel. A
+	// channel the user is assigned to directly takes precedence over channels
+	// assigned to one of their groups, and ties are broken by channel name.
+	GetTemplateActiveVersionForUser(ctx context.Context, arg GetTemplateActiveVersionForUserParams) (GetTemplateActiveVersionForUserRow, error)
+	// Returns the version of its template that each workspace uses, resolved for
+	// the workspace owner like GetTemplateActiveVersionForUser.
+	GetTemplateActiveVersionsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetTemplateActiveVersionsByWorkspaceIDsRow, error)
 	// GetTemplateAppInsights returns the aggregate usage of each app in a given
 	// timeframe. The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
@@ -204,11 +290,20 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	GetTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) (TemplateReleaseChannel, error)
+	GetTemplateReleaseChannelByTemplateIDAndName(ctx context.Context, arg GetTemplateReleaseChannelByTemplateIDAndNameParams) (TemplateReleaseChannel, error)
+	GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateReleaseChannel, error)
 	GetTemplateUsageStats(ctx context.Context, arg GetTemplateUsageStatsParams) ([]TemplateUsageStat, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
+	GetTemplateVersionDiagnostics(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionDiagnostic, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	GetTemplateVersionPresetByID(ctx context.Context, id uuid.UUID) (TemplateVersionPreset, error)
+	// Returns the parameters of every preset of a template version.
+	GetTemplateVersionPresetParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPresetParameter, error)
+	GetTemplateVersionPresetParametersByPresetID(ctx context.Context, templateVersionPresetID uuid.UUID) ([]TemplateVersionPresetParameter, error)
+	GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error)
 	GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error)
@@ -235,6 +330,12 @@ type sqlcQuerier interface {
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
 	GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error)
+	// Reports whether the user has logged in with a password before, and whether
+	// any of those logins came from the IP address or user agent.
+	GetUserLoginHistorySummary(ctx context.Context, arg GetUserLoginHistorySummaryParams) (GetUserLoginHistorySummaryRow, error)
+	GetUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLoginLockout, error)
+	GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
+	GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (UserTOTP, error)
 	GetUserWorkspaceBuildParameters(ctx context.Context, arg GetUserWorkspaceBuildParametersParams) ([]GetUserWorkspaceBuildParametersRow, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
@@ -242,6 +343,10 @@ type sqlcQuerier interface {
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error)
+	GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
 	GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Context, authToken uuid.UUID) (GetWorkspaceAgentAndLatestBuildByAuthTokenRow, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
 	GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error)
@@ -264,12 +369,29 @@ type sqlcQuerier interface {
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
+	// Returns a summary of the provisioner state stored for every build of a
+	// workspace. The state itself is not returned, as it can be large.
+	GetWorkspaceBuildStatesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]GetWorkspaceBuildStatesByWorkspaceIDRow, error)
 	GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error)
 	GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error)
+	GetWorkspaceBulkOperationByID(ctx context.Context, id uuid.UUID) (WorkspaceBulkOperation, error)
+	// The status of the job of the build started for a workspace tells the
+	// runner when the build has finished.
+	GetWorkspaceBulkOperationWorkspaces(ctx context.Context, operationID uuid.UUID) ([]GetWorkspaceBulkOperationWorkspacesRow, error)
 	GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (GetWorkspaceByAgentIDRow, error)
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	// Prebuilt workspaces that no user has claimed yet don't count towards the
+	// limit of the template.
+	GetWorkspaceCountForTemplate(ctx context.Context, templateID uuid.UUID) (int64, error)
+	GetWorkspaceCountForUser(ctx context.Context, arg GetWorkspaceCountForUserParams) (int64, error)
+	// Returns the highest workspace limit of the groups of the user in the
+	// organization. 0 means none of the groups sets a limit.
+	GetWorkspaceLimitForUser(ctx context.Context, arg GetWorkspaceLimitForUserParams) (int32, error)
+	// Returns every prebuild that has not been claimed yet along with the
+	// status of its latest build.
+	GetWorkspacePrebuilds(ctx context.Context) ([]GetWorkspacePrebuildsRow, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -287,6 +409,11 @@ type sqlcQuerier interface {
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceSnapshotByBuildID(ctx context.Context, buildID uuid.UUID) (WorkspaceSnapshot, error)
+	GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error)
+	GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (WorkspaceSnapshot, error)
+	GetWorkspaceSnapshotRestoreByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceSnapshotRestore, error)
+	GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	// build_params is used to filter by build parameters if present.
 	// It has to be a CTE because the set returning function 'unnest' cannot
@@ -299,6 +426,7 @@ type sqlcQuerier interface {
 	// every member of the org.
 	InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error)
 	InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error
 	InsertDERPMeshKey(ctx context.Context, value string) error
 	InsertDeploymentID(ctx context.Context, value string) error
@@ -317,19 +445,30 @@ type sqlcQuerier interface {
 	InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error)
 	InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error)
 	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
+	InsertOIDCSyncRule(ctx context.Context, arg InsertOIDCSyncRuleParams) (OIDCSyncRule, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertSCIMToken(ctx context.Context, arg InsertSCIMTokenParams) (SCIMToken, error)
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplateReleaseChannel(ctx context.Context, arg InsertTemplateReleaseChannelParams) (TemplateReleaseChannel, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
+	InsertTemplateVersionDiagnostic(ctx context.Context, arg InsertTemplateVersionDiagnosticParams) (TemplateVersionDiagnostic, error)
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
+	InsertTemplateVersionPreset(ctx context.Context, arg InsertTemplateVersionPresetParams) (TemplateVersionPreset, error)
+	InsertTemplateVersionPresetParameters(ctx context.Context, arg InsertTemplateVersionPresetParametersParams) ([]TemplateVersionPresetParameter, error)
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
 	// InsertUserGroupsByName adds a user to all provided groups, if they exist.
+	// Groups provisioned over SCIM are managed by the identity provider and are
+	// skipped.
 	InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
+	InsertUserLoginAttempt(ctx context.Context, arg InsertUserLoginAttemptParams) error
+	InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error)
+	InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
@@ -342,12 +481,23 @@ type sqlcQuerier interface {
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceBulkOperation(ctx context.Context, arg InsertWorkspaceBulkOperationParams) (WorkspaceBulkOperation, error)
+	InsertWorkspaceBulkOperationWorkspaces(ctx context.Context, arg InsertWorkspaceBulkOperationWorkspacesParams) error
+	InsertWorkspacePrebuild(ctx context.Context, arg InsertWorkspacePrebuildParams) error
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
+	InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error)
+	InsertWorkspaceSnapshotRestore(ctx context.Context, arg InsertWorkspaceSnapshotRestoreParams) (WorkspaceSnapshotRestore, error)
 	ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error)
+	MarkNotificationMessageFailed(ctx context.Context, arg MarkNotificationMessageFailedParams) error
+	MarkNotificationMessageSent(ctx context.Context, arg MarkNotificationMessageSentParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error
 	ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate(ctx context.Context, templateID uuid.UUID) error
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
+	// RemoveUserFromAllGroups removes a user from every group except the ones
+	// provisioned over SCIM, which are managed by the identity provider.
 	RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
 	// Non blocking lock. Returns true if the lock was acquired, false otherwise.
@@ -359,6 +509,7 @@ type sqlcQuerier interface {
 	UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error
 	UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
+	UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error)
 	UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error)
 	UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error)
 	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
@@ -366,16 +517,22 @@ type sqlcQuerier interface {
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
 	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
 	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
+	UpdateOIDCSyncRule(ctx context.Context, arg UpdateOIDCSyncRuleParams) (OIDCSyncRule, error)
 	UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
+	// Records the required_version constraint a template import job found in the
+	// template, which workspace builds of the template version inherit.
+	UpdateProvisionerJobEngineVersionConstraintByID(ctx context.Context, arg UpdateProvisionerJobEngineVersionConstraintByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
+	UpdateSCIMTokenLastUsedAt(ctx context.Context, arg UpdateSCIMTokenLastUsedAtParams) error
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
 	UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
+	UpdateTemplateReleaseChannelByID(ctx context.Context, arg UpdateTemplateReleaseChannelByIDParams) (TemplateReleaseChannel, error)
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
@@ -392,7 +549,12 @@ type sqlcQuerier interface {
 	UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	// Only moves forward, so a code is accepted at most once. Returns no rows when
+	// the step was already used.
+	UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (UserTOTP, error)
+	UpdateWebhookByID(ctx context.Context, arg UpdateWebhookByIDParams) (Webhook, error)
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
 	UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error
@@ -404,12 +566,17 @@ type sqlcQuerier interface {
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
 	UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error
 	UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error
+	UpdateWorkspaceBulkOperationStatus(ctx context.Context, arg UpdateWorkspaceBulkOperationStatusParams) error
+	UpdateWorkspaceBulkOperationWorkspace(ctx context.Context, arg UpdateWorkspaceBulkOperationWorkspaceParams) error
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
 	UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg UpdateWorkspaceDormantDeletingAtParams) (Workspace, error)
 	UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWorkspaceLastUsedAtParams) error
+	// Favorites belong to the previous owner, so they are reset.
+	UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) error
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
+	UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg UpdateWorkspaceSnapshotArtifactsByIDParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) error
 	UpsertAppSecurityKey(ctx context.Context, value string) error
@@ -436,7 +603,15 @@ type sqlcQuerier interface {
 	// used to store the data, and the minutes are summed for each user and template
 	// combination. The result is stored in the template_usage_stats table.
 	UpsertTemplateUsageStats(ctx context.Context) error
+	UpsertUserLoginLockout(ctx context.Context, arg UpsertUserLoginLockoutParams) (UserLoginLockout, error)
+	UpsertUserNotificationPreference(ctx context.Context, arg UpsertUserNotificationPreferenceParams) (NotificationPreference, error)
+	// Starts a new enrollment, replacing any earlier enrollment that was not
+	// confirmed. Returns no rows when the user already has TOTP enabled.
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error)
 	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	// Removes a recovery code so it cannot be used again. Returns no rows when the
+	// code does not match.
+	UseUserTOTPRecoveryCode(ctx context.Context, arg UseUserTOTPRecoveryCodeParams) (UserTOTP, error)
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
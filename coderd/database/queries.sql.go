@@ -144,9 +144,65 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 	return err
 }
 
+const deleteOtherAPIKeysByUserID = `-- name: DeleteOtherAPIKeysByUserID :many
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	id != $2 AND
+	($3 :: boolean OR login_type != 'token'::login_type)
+RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
+`
+
+type DeleteOtherAPIKeysByUserIDParams struct {
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	ExceptID      string    `db:"except_id" json:"except_id"`
+	IncludeTokens bool      `db:"include_tokens" json:"include_tokens"`
+}
+
+// Deletes all keys of the user except except_id, returning the deleted keys.
+// Tokens are only deleted if include_tokens is set.
+func (q *sqlQuerier) DeleteOtherAPIKeysByUserID(ctx context.Context, arg DeleteOtherAPIKeysByUserIDParams) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, deleteOtherAPIKeysByUserID, arg.UserID, arg.ExceptID, arg.IncludeTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
 FROM
 	api_keys
 WHERE
@@ -171,13 +227,15 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ImpersonatorID,
+		&i.UserAgent,
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
 FROM
 	api_keys
 WHERE
@@ -210,12 +268,14 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ImpersonatorID,
+		&i.UserAgent,
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -240,6 +300,8 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +317,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -285,6 +347,8 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +364,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -325,6 +389,112 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveAPIKeysByUserID = `-- name: GetActiveAPIKeysByUserID :many
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	expires_at > NOW()
+ORDER BY
+	last_used DESC
+`
+
+// Returns the user's unexpired keys, such as browser sessions, CLI logins and
+// tokens, most recently used first.
+func (q *sqlQuerier) GetActiveAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveAPIKeysByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImpersonationAPIKeysByUserID = `-- name: GetImpersonationAPIKeysByUserID :many
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	impersonator_id IS NOT NULL AND
+	expires_at > NOW()
+ORDER BY
+	created_at DESC
+`
+
+// Returns the unexpired keys other users have minted to impersonate the user.
+func (q *sqlQuerier) GetImpersonationAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getImpersonationAPIKeysByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			&i.ImpersonatorID,
+			&i.UserAgent,
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +523,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		impersonator_id,
+		user_agent
 	)
 VALUES
 	($1,
@@ -362,22 +534,24 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, impersonator_id, user_agent
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID              string        `db:"id" json:"id"`
+	LifetimeSeconds int64         `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret    []byte        `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress       pqtype.Inet   `db:"ip_address" json:"ip_address"`
+	UserID          uuid.UUID     `db:"user_id" json:"user_id"`
+	LastUsed        time.Time     `db:"last_used" json:"last_used"`
+	ExpiresAt       time.Time     `db:"expires_at" json:"expires_at"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at" json:"updated_at"`
+	LoginType       LoginType     `db:"login_type" json:"login_type"`
+	Scope           APIKeyScope   `db:"scope" json:"scope"`
+	TokenName       string        `db:"token_name" json:"token_name"`
+	ImpersonatorID  uuid.NullUUID `db:"impersonator_id" json:"impersonator_id"`
+	UserAgent       string        `db:"user_agent" json:"user_agent"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -394,6 +568,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		arg.ImpersonatorID,
+		arg.UserAgent,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -409,6 +585,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ImpersonatorID,
+		&i.UserAgent,
 	)
 	return i, err
 }
@@ -443,17 +621,21 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
-    audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
+    audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon, audit_logs.impersonator_id,
     users.username AS user_username,
     users.email AS user_email,
     users.created_at AS user_created_at,
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.is_service_account AS user_is_service_account,
+    impersonators.username AS impersonator_username,
+    impersonators.avatar_url AS impersonator_avatar_url,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
     LEFT JOIN users ON audit_logs.user_id = users.id
+    LEFT JOIN users AS impersonators ON audit_logs.impersonator_id = impersonators.id
     LEFT JOIN
         -- First join on workspaces to get the initial workspace create
         -- to workspace build 1 id. This is because the first create is
@@ -539,6 +721,12 @@ WHERE
             workspace_builds.reason::text = $12
         ELSE true
     END
+	-- Filter by organization_id
+	AND CASE
+		WHEN $13 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			audit_logs.organization_id = $13
+		ELSE true
+	END
 ORDER BY
     "time" DESC
 LIMIT
@@ -560,31 +748,36 @@ type GetAuditLogsOffsetParams struct {
 	DateFrom       time.Time `db:"date_from" json:"date_from"`
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
 type GetAuditLogsOffsetRow struct {
-	ID               uuid.UUID       `db:"id" json:"id"`
-	Time             time.Time       `db:"time" json:"time"`
-	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
-	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Ip               pqtype.Inet     `db:"ip" json:"ip"`
-	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
-	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
-	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
-	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
-	Action           AuditAction     `db:"action" json:"action"`
-	Diff             json.RawMessage `db:"diff" json:"diff"`
-	StatusCode       int32           `db:"status_code" json:"status_code"`
-	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
-	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
-	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
-	UserUsername     sql.NullString  `db:"user_username" json:"user_username"`
-	UserEmail        sql.NullString  `db:"user_email" json:"user_email"`
-	UserCreatedAt    sql.NullTime    `db:"user_created_at" json:"user_created_at"`
-	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
-	UserRoles        pq.StringArray  `db:"user_roles" json:"user_roles"`
-	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
-	Count            int64           `db:"count" json:"count"`
+	ID                    uuid.UUID       `db:"id" json:"id"`
+	Time                  time.Time       `db:"time" json:"time"`
+	UserID                uuid.UUID       `db:"user_id" json:"user_id"`
+	OrganizationID        uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Ip                    pqtype.Inet     `db:"ip" json:"ip"`
+	UserAgent             sql.NullString  `db:"user_agent" json:"user_agent"`
+	ResourceType          ResourceType    `db:"resource_type" json:"resource_type"`
+	ResourceID            uuid.UUID       `db:"resource_id" json:"resource_id"`
+	ResourceTarget        string          `db:"resource_target" json:"resource_target"`
+	Action                AuditAction     `db:"action" json:"action"`
+	Diff                  json.RawMessage `db:"diff" json:"diff"`
+	StatusCode            int32           `db:"status_code" json:"status_code"`
+	AdditionalFields      json.RawMessage `db:"additional_fields" json:"additional_fields"`
+	RequestID             uuid.UUID       `db:"request_id" json:"request_id"`
+	ResourceIcon          string          `db:"resource_icon" json:"resource_icon"`
+	ImpersonatorID        uuid.NullUUID   `db:"impersonator_id" json:"impersonator_id"`
+	UserUsername          sql.NullString  `db:"user_username" json:"user_username"`
+	UserEmail             sql.NullString  `db:"user_email" json:"user_email"`
+	UserCreatedAt         sql.NullTime    `db:"user_created_at" json:"user_created_at"`
+	UserStatus            NullUserStatus  `db:"user_status" json:"user_status"`
+	UserRoles             pq.StringArray  `db:"user_roles" json:"user_roles"`
+	UserAvatarUrl         sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
+	UserIsServiceAccount  sql.NullBool    `db:"user_is_service_account" json:"user_is_service_account"`
+	ImpersonatorUsername  sql.NullString  `db:"impersonator_username" json:"impersonator_username"`
+	ImpersonatorAvatarUrl sql.NullString  `db:"impersonator_avatar_url" json:"impersonator_avatar_url"`
+	Count                 int64           `db:"count" json:"count"`
 }
 
 // GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
@@ -603,6 +796,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.DateFrom,
 		arg.DateTo,
 		arg.BuildReason,
+		arg.OrganizationID,
 	)
 	if err != nil {
 		return nil, err
@@ -627,12 +821,16 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 			&i.AdditionalFields,
 			&i.RequestID,
 			&i.ResourceIcon,
+			&i.ImpersonatorID,
 			&i.UserUsername,
 			&i.UserEmail,
 			&i.UserCreatedAt,
 			&i.UserStatus,
 			&i.UserRoles,
 			&i.UserAvatarUrl,
+			&i.UserIsServiceAccount,
+			&i.ImpersonatorUsername,
+			&i.ImpersonatorAvatarUrl,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -665,10 +863,11 @@ INSERT INTO
         status_code,
         additional_fields,
         request_id,
-        resource_icon
+        resource_icon,
+        impersonator_id
     )
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon, impersonator_id
 `
 
 type InsertAuditLogParams struct {
@@ -687,6 +886,7 @@ type InsertAuditLogParams struct {
 	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
 	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	ImpersonatorID   uuid.NullUUID   `db:"impersonator_id" json:"impersonator_id"`
 }
 
 func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error) {
@@ -706,6 +906,7 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 		arg.AdditionalFields,
 		arg.RequestID,
 		arg.ResourceIcon,
+		arg.ImpersonatorID,
 	)
 	var i AuditLog
 	err := row.Scan(
@@ -724,30 +925,108 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 		&i.AdditionalFields,
 		&i.RequestID,
 		&i.ResourceIcon,
+		&i.ImpersonatorID,
 	)
 	return i, err
 }
 
-const getDBCryptKeys = `-- name: GetDBCryptKeys :many
-SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+const deleteCustomRole = `-- name: DeleteCustomRole :exec
+WITH deleted AS (
+	DELETE FROM
+		custom_roles
+	WHERE
+		id = $1
+	RETURNING
+		name, organization_id
+), unassigned_users AS (
+	UPDATE
+		users
+	SET
+		rbac_roles = array_remove(users.rbac_roles, deleted.name)
+	FROM
+		deleted
+	WHERE
+		deleted.organization_id IS NULL
+		AND deleted.name = ANY(users.rbac_roles)
+)
+UPDATE
+	organization_members
+SET
+	roles = array_remove(organization_members.roles, deleted.name || ':' || deleted.organization_id :: text)
+FROM
+	deleted
+WHERE
+	organization_members.organization_id = deleted.organization_id
+	AND (deleted.name || ':' || deleted.organization_id :: text) = ANY(organization_members.roles)
 `
 
-func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
-	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
+// Deleting a role also unassigns it, so users are never left with roles that
+// cannot be expanded.
+func (q *sqlQuerier) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCustomRole, id)
+	return err
+}
+
+const getCustomRoleByName = `-- name: GetCustomRoleByName :one
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	name = $1
+	AND organization_id IS NOT DISTINCT FROM $2 :: uuid
+`
+
+type GetCustomRoleByNameParams struct {
+	Name           string        `db:"name" json:"name"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetCustomRoleByName(ctx context.Context, arg GetCustomRoleByNameParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, getCustomRoleByName, arg.Name, arg.OrganizationID)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCustomRoles = `-- name: GetCustomRoles :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+ORDER BY
+	organization_id NULLS FIRST, name
+`
+
+func (q *sqlQuerier) GetCustomRoles(ctx context.Context) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRoles)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []DBCryptKey
+	var items []CustomRole
 	for rows.Next() {
-		var i DBCryptKey
+		var i CustomRole
 		if err := rows.Scan(
-			&i.Number,
-			&i.ActiveKeyDigest,
-			&i.RevokedKeyDigest,
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
 			&i.CreatedAt,
-			&i.RevokedAt,
-			&i.Test,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -762,32 +1041,167 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 	return items, nil
 }
 
-const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
-INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+const insertCustomRole = `-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $8) RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
 `
 
-type InsertDBCryptKeyParams struct {
-	Number          int32  `db:"number" json:"number"`
-	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
-	Test            string `db:"test" json:"test"`
+type InsertCustomRoleParams struct {
+	ID              uuid.UUID             `db:"id" json:"id"`
+	Name            string                `db:"name" json:"name"`
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
-	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
-	return err
+func (q *sqlQuerier) InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, insertCustomRole,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.CreatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
-UPDATE dbcrypt_keys
+const updateCustomRole = `-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
 SET
-	revoked_key_digest = active_key_digest,
-	active_key_digest = revoked_key_digest,
-	revoked_at = CURRENT_TIMESTAMP
+	display_name = $1,
+	site_permissions = $2,
+	org_permissions = $3,
+	user_permissions = $4,
+	updated_at = $5
 WHERE
-	active_key_digest = $1::text
-AND
+	id = $6
+RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type UpdateCustomRoleParams struct {
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+	ID              uuid.UUID             `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, updateCustomRole,
+		arg.DisplayName,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getDBCryptKeys = `-- name: GetDBCryptKeys :many
+SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+`
+
+func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
+	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DBCryptKey
+	for rows.Next() {
+		var i DBCryptKey
+		if err := rows.Scan(
+			&i.Number,
+			&i.ActiveKeyDigest,
+			&i.RevokedKeyDigest,
+			&i.CreatedAt,
+			&i.RevokedAt,
+			&i.Test,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
+INSERT INTO dbcrypt_keys
+	(number, active_key_digest, created_at, test)
+VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+`
+
+type InsertDBCryptKeyParams struct {
+	Number          int32  `db:"number" json:"number"`
+	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
+	Test            string `db:"test" json:"test"`
+}
+
+func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
+	return err
+}
+
+const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
+UPDATE dbcrypt_keys
+SET
+	revoked_key_digest = active_key_digest,
+	active_key_digest = revoked_key_digest,
+	revoked_at = CURRENT_TIMESTAMP
+WHERE
+	active_key_digest = $1::text
+AND
 	revoked_key_digest IS NULL
 `
 
@@ -1290,7 +1704,7 @@ func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteG
 
 const getGroupMembers = `-- name: GetGroupMembers :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.theme_preference, users.name
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.theme_preference, users.name, users.is_service_account
 FROM
 	users
 LEFT JOIN
@@ -1339,6 +1753,7 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 			&i.QuietHoursSchedule,
 			&i.ThemePreference,
 			&i.Name,
+			&i.IsServiceAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -1378,7 +1793,8 @@ WITH groups AS (
         groups
     WHERE
         groups.organization_id = $2 AND
-        groups.name = ANY($3 :: text [])
+        groups.name = ANY($3 :: text []) AND
+        groups.source != 'scim'
 )
 INSERT INTO
     group_members (user_id, group_id)
@@ -1396,6 +1812,8 @@ type InsertUserGroupsByNameParams struct {
 }
 
 // InsertUserGroupsByName adds a user to all provided groups, if they exist.
+// Groups provisioned over SCIM are managed by the identity provider and are
+// skipped.
 func (q *sqlQuerier) InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error {
 	_, err := q.db.ExecContext(ctx, insertUserGroupsByName, arg.UserID, arg.OrganizationID, pq.Array(arg.GroupNames))
 	return err
@@ -1405,9 +1823,14 @@ const removeUserFromAllGroups = `-- name: RemoveUserFromAllGroups :exec
 DELETE FROM
 	group_members
 WHERE
-	user_id = $1
+	user_id = $1 AND
+	group_id NOT IN (
+		SELECT id FROM groups WHERE source = 'scim'
+	)
 `
 
+// RemoveUserFromAllGroups removes a user from every group except the ones
+// provisioned over SCIM, which are managed by the identity provider.
 func (q *sqlQuerier) RemoveUserFromAllGroups(ctx context.Context, userID uuid.UUID) error {
 	_, err := q.db.ExecContext(ctx, removeUserFromAllGroups, userID)
 	return err
@@ -1427,7 +1850,7 @@ func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 
 const getGroupByID = `-- name: GetGroupByID :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 FROM
 	groups
 WHERE
@@ -1447,13 +1870,14 @@ func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, err
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MaxWorkspacesPerUser,
 	)
 	return i, err
 }
 
 const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 FROM
 	groups
 WHERE
@@ -1480,13 +1904,14 @@ func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrg
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MaxWorkspacesPerUser,
 	)
 	return i, err
 }
 
 const getGroupsByOrganizationAndUserID = `-- name: GetGroupsByOrganizationAndUserID :many
 SELECT
-    groups.id, groups.name, groups.organization_id, groups.avatar_url, groups.quota_allowance, groups.display_name, groups.source
+    groups.id, groups.name, groups.organization_id, groups.avatar_url, groups.quota_allowance, groups.display_name, groups.source, groups.max_workspaces_per_user
 FROM
     groups
 	-- If the group is a user made group, then we need to check the group_members table.
@@ -1531,6 +1956,7 @@ func (q *sqlQuerier) GetGroupsByOrganizationAndUserID(ctx context.Context, arg G
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.MaxWorkspacesPerUser,
 		); err != nil {
 			return nil, err
 		}
@@ -1547,7 +1973,7 @@ func (q *sqlQuerier) GetGroupsByOrganizationAndUserID(ctx context.Context, arg G
 
 const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 FROM
 	groups
 WHERE
@@ -1571,6 +1997,7 @@ func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organization
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.MaxWorkspacesPerUser,
 		); err != nil {
 			return nil, err
 		}
@@ -1592,7 +2019,7 @@ INSERT INTO groups (
 	organization_id
 )
 VALUES
-	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 `
 
 // We use the organization_id as the id
@@ -1609,6 +2036,7 @@ func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uui
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MaxWorkspacesPerUser,
 	)
 	return i, err
 }
@@ -1620,19 +2048,21 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	max_workspaces_per_user
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 `
 
 type InsertGroupParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	ID                   uuid.UUID `db:"id" json:"id"`
+	Name                 string    `db:"name" json:"name"`
+	DisplayName          string    `db:"display_name" json:"display_name"`
+	OrganizationID       uuid.UUID `db:"organization_id" json:"organization_id"`
+	AvatarURL            string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance       int32     `db:"quota_allowance" json:"quota_allowance"`
+	MaxWorkspacesPerUser int32     `db:"max_workspaces_per_user" json:"max_workspaces_per_user"`
 }
 
 func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
@@ -1643,6 +2073,7 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		arg.OrganizationID,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.MaxWorkspacesPerUser,
 	)
 	var i Group
 	err := row.Scan(
@@ -1653,6 +2084,7 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MaxWorkspacesPerUser,
 	)
 	return i, err
 }
@@ -1672,7 +2104,7 @@ SELECT
 FROM
     UNNEST($3 :: text[]) AS group_name
 ON CONFLICT DO NOTHING
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 `
 
 type InsertMissingGroupsParams struct {
@@ -1702,6 +2134,7 @@ func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingG
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.MaxWorkspacesPerUser,
 		); err != nil {
 			return nil, err
 		}
@@ -1723,18 +2156,20 @@ SET
 	name = $1,
 	display_name = $2,
 	avatar_url = $3,
-	quota_allowance = $4
+	quota_allowance = $4,
+	max_workspaces_per_user = $5
 WHERE
-	id = $5
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id = $6
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, max_workspaces_per_user
 `
 
 type UpdateGroupByIDParams struct {
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-	ID             uuid.UUID `db:"id" json:"id"`
+	Name                 string    `db:"name" json:"name"`
+	DisplayName          string    `db:"display_name" json:"display_name"`
+	AvatarURL            string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance       int32     `db:"quota_allowance" json:"quota_allowance"`
+	MaxWorkspacesPerUser int32     `db:"max_workspaces_per_user" json:"max_workspaces_per_user"`
+	ID                   uuid.UUID `db:"id" json:"id"`
 }
 
 func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
@@ -1743,6 +2178,7 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		arg.DisplayName,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.MaxWorkspacesPerUser,
 		arg.ID,
 	)
 	var i Group
@@ -1754,6 +2190,7 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.MaxWorkspacesPerUser,
 	)
 	return i, err
 }
@@ -3158,135 +3595,490 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
-const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
-DELETE FROM oauth2_provider_apps WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
-	return err
-}
-
-const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :exec
-DELETE FROM oauth2_provider_app_codes WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
-	return err
-}
-
-const deleteOAuth2ProviderAppCodesByAppAndUserID = `-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
-DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2
+const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	status = 'leased' :: notification_message_status,
+	updated_at = $1,
+	leased_until = $2
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.method = $3
+			AND nested.attempt_count < $4 :: int
+			AND (
+				nested.status = 'pending' :: notification_message_status
+				OR (nested.status = 'temporary_failure' :: notification_message_status AND nested.next_retry_after <= $1)
+				OR (nested.status = 'leased' :: notification_message_status AND nested.leased_until <= $1)
+			)
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			$5 :: int
+	) RETURNING id, user_id, template, method, status, status_reason, payload, attempt_count, created_by, created_at, updated_at, leased_until, next_retry_after, sent_at
 `
 
-type DeleteOAuth2ProviderAppCodesByAppAndUserIDParams struct {
-	AppID  uuid.UUID `db:"app_id" json:"app_id"`
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-}
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodesByAppAndUserID, arg.AppID, arg.UserID)
-	return err
+type AcquireNotificationMessagesParams struct {
+	Now             time.Time          `db:"now" json:"now"`
+	LeasedUntil     sql.NullTime       `db:"leased_until" json:"leased_until"`
+	Method          NotificationMethod `db:"method" json:"method"`
+	MaxAttemptCount int32              `db:"max_attempt_count" json:"max_attempt_count"`
+	Count           int32              `db:"count" json:"count"`
 }
 
-const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
-DELETE FROM oauth2_provider_app_secrets WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
-	return err
+// Acquires a batch of messages that are waiting to be delivered with the
+// given method: new messages, failed messages whose retry is due, and
+// leased messages whose lease expired without an outcome being recorded.
+//
+// SKIP LOCKED is used to jump over locked rows. This prevents
+// multiple coderd replicas from acquiring the same messages.
+func (q *sqlQuerier) AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, acquireNotificationMessages,
+		arg.Now,
+		arg.LeasedUntil,
+		arg.Method,
+		arg.MaxAttemptCount,
+		arg.Count,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Method,
+			&i.Status,
+			&i.StatusReason,
+			&i.Payload,
+			&i.AttemptCount,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextRetryAfter,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
 DELETE FROM
-  oauth2_provider_app_tokens
-USING
-  oauth2_provider_app_secrets, api_keys
+	notification_messages
 WHERE
-  oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
-  AND api_keys.id = oauth2_provider_app_tokens.api_key_id
-  AND oauth2_provider_app_secrets.app_id = $1
-	AND api_keys.user_id = $2
+	status IN ('sent' :: notification_message_status, 'permanent_failure' :: notification_message_status)
+	AND updated_at < (NOW() - INTERVAL '7 days')
 `
 
-type DeleteOAuth2ProviderAppTokensByAppAndUserIDParams struct {
-	AppID  uuid.UUID `db:"app_id" json:"app_id"`
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
-}
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppTokensByAppAndUserID, arg.AppID, arg.UserID)
+// Delete messages that reached a final state more than a week ago.
+func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages)
 	return err
 }
 
-const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+const enqueueNotificationMessage = `-- name: EnqueueNotificationMessage :one
+INSERT INTO
+	notification_messages (
+		id,
+		user_id,
+		template,
+		method,
+		payload,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, user_id, template, method, status, status_reason, payload, attempt_count, created_by, created_at, updated_at, leased_until, next_retry_after, sent_at
 `
 
-func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
-	var i OAuth2ProviderApp
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Icon,
-		&i.CallbackURL,
-	)
-	return i, err
+type EnqueueNotificationMessageParams struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	Template  string             `db:"template" json:"template"`
+	Method    NotificationMethod `db:"method" json:"method"`
+	Payload   json.RawMessage    `db:"payload" json:"payload"`
+	CreatedBy string             `db:"created_by" json:"created_by"`
+	CreatedAt time.Time          `db:"created_at" json:"created_at"`
 }
 
-const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE id = $1
-`
-
-func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
-	var i OAuth2ProviderAppCode
+func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, enqueueNotificationMessage,
+		arg.ID,
+		arg.UserID,
+		arg.Template,
+		arg.Method,
+		arg.Payload,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i NotificationMessage
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedAt,
-		&i.ExpiresAt,
-		&i.SecretPrefix,
-		&i.HashedSecret,
 		&i.UserID,
-		&i.AppID,
+		&i.Template,
+		&i.Method,
+		&i.Status,
+		&i.StatusReason,
+		&i.Payload,
+		&i.AttemptCount,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LeasedUntil,
+		&i.NextRetryAfter,
+		&i.SentAt,
 	)
 	return i, err
 }
 
-const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
-SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+const getNotificationMessagesByStatus = `-- name: GetNotificationMessagesByStatus :many
+SELECT
+	id, user_id, template, method, status, status_reason, payload, attempt_count, created_by, created_at, updated_at, leased_until, next_retry_after, sent_at
+FROM
+	notification_messages
+WHERE
+	status = $1
+ORDER BY
+	created_at
+LIMIT
+	$2 :: int
 `
 
-func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByPrefix, secretPrefix)
-	var i OAuth2ProviderAppCode
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.ExpiresAt,
-		&i.SecretPrefix,
-		&i.HashedSecret,
-		&i.UserID,
-		&i.AppID,
-	)
-	return i, err
+type GetNotificationMessagesByStatusParams struct {
+	Status   NotificationMessageStatus `db:"status" json:"status"`
+	LimitOpt int32                     `db:"limit_opt" json:"limit_opt"`
 }
 
-const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
-SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id, secret_prefix FROM oauth2_provider_app_secrets WHERE id = $1
-`
-
-func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
-	var i OAuth2ProviderAppSecret
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
+func (q *sqlQuerier) GetNotificationMessagesByStatus(ctx context.Context, arg GetNotificationMessagesByStatusParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationMessagesByStatus, arg.Status, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Method,
+			&i.Status,
+			&i.StatusReason,
+			&i.Payload,
+			&i.AttemptCount,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextRetryAfter,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserNotificationPreferences = `-- name: GetUserNotificationPreferences :many
+SELECT
+	user_id, template, disabled, created_at, updated_at
+FROM
+	notification_preferences
+WHERE
+	user_id = $1
+ORDER BY
+	template
+`
+
+func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getUserNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Template,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationMessageFailed = `-- name: MarkNotificationMessageFailed :exec
+UPDATE
+	notification_messages
+SET
+	status = $1,
+	status_reason = $2,
+	attempt_count = attempt_count + 1,
+	updated_at = $3,
+	leased_until = NULL,
+	next_retry_after = $4
+WHERE
+	id = $5
+`
+
+type MarkNotificationMessageFailedParams struct {
+	Status         NotificationMessageStatus `db:"status" json:"status"`
+	StatusReason   sql.NullString            `db:"status_reason" json:"status_reason"`
+	UpdatedAt      time.Time                 `db:"updated_at" json:"updated_at"`
+	NextRetryAfter sql.NullTime              `db:"next_retry_after" json:"next_retry_after"`
+	ID             uuid.UUID                 `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkNotificationMessageFailed(ctx context.Context, arg MarkNotificationMessageFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageFailed,
+		arg.Status,
+		arg.StatusReason,
+		arg.UpdatedAt,
+		arg.NextRetryAfter,
+		arg.ID,
+	)
+	return err
+}
+
+const markNotificationMessageSent = `-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent' :: notification_message_status,
+	status_reason = NULL,
+	attempt_count = attempt_count + 1,
+	updated_at = $1,
+	sent_at = $1,
+	leased_until = NULL,
+	next_retry_after = NULL
+WHERE
+	id = $2
+`
+
+type MarkNotificationMessageSentParams struct {
+	SentAt time.Time `db:"sent_at" json:"sent_at"`
+	ID     uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkNotificationMessageSent(ctx context.Context, arg MarkNotificationMessageSentParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageSent, arg.SentAt, arg.ID)
+	return err
+}
+
+const upsertUserNotificationPreference = `-- name: UpsertUserNotificationPreference :one
+INSERT INTO
+	notification_preferences (
+		user_id,
+		template,
+		disabled,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $4)
+ON CONFLICT (user_id, template) DO UPDATE
+SET
+	disabled = $3,
+	updated_at = $4
+RETURNING user_id, template, disabled, created_at, updated_at
+`
+
+type UpsertUserNotificationPreferenceParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertUserNotificationPreference(ctx context.Context, arg UpsertUserNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserNotificationPreference,
+		arg.UserID,
+		arg.Template,
+		arg.Disabled,
+		arg.UpdatedAt,
+	)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Template,
+		&i.Disabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+DELETE FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :exec
+DELETE FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodesByAppAndUserID = `-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2
+`
+
+type DeleteOAuth2ProviderAppCodesByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppCodesByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+DELETE FROM
+  oauth2_provider_app_tokens
+USING
+  oauth2_provider_app_secrets, api_keys
+WHERE
+  oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+  AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+  AND oauth2_provider_app_secrets.app_id = $1
+	AND api_keys.user_id = $2
+`
+
+type DeleteOAuth2ProviderAppTokensByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppTokensByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id, secret_prefix FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
 		&i.LastUsedAt,
 		&i.HashedSecret,
 		&i.DisplaySecret,
@@ -3722,28 +4514,232 @@ func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg
 	return i, err
 }
 
-const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
-SELECT
-    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
-FROM
-    organization_members
+const deleteOIDCSyncRule = `-- name: DeleteOIDCSyncRule :exec
+DELETE FROM
+	oidc_sync_rules
 WHERE
-    user_id = ANY($1 :: uuid [ ])
-GROUP BY
-    user_id
+	id = $1
 `
 
-type GetOrganizationIDsByMemberIDsRow struct {
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+func (q *sqlQuerier) DeleteOIDCSyncRule(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOIDCSyncRule, id)
+	return err
 }
 
-func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+const getOIDCSyncRuleByID = `-- name: GetOIDCSyncRuleByID :one
+SELECT
+	id, claim, pattern, site_roles, organization_id, organization_roles, created_at, updated_at
+FROM
+	oidc_sync_rules
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetOIDCSyncRuleByID(ctx context.Context, id uuid.UUID) (OIDCSyncRule, error) {
+	row := q.db.QueryRowContext(ctx, getOIDCSyncRuleByID, id)
+	var i OIDCSyncRule
+	err := row.Scan(
+		&i.ID,
+		&i.Claim,
+		&i.Pattern,
+		pq.Array(&i.SiteRoles),
+		&i.OrganizationID,
+		pq.Array(&i.OrganizationRoles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOIDCSyncRules = `-- name: GetOIDCSyncRules :many
+SELECT
+	id, claim, pattern, site_roles, organization_id, organization_roles, created_at, updated_at
+FROM
+	oidc_sync_rules
+ORDER BY
+	created_at, id
+`
+
+func (q *sqlQuerier) GetOIDCSyncRules(ctx context.Context) ([]OIDCSyncRule, error) {
+	rows, err := q.db.QueryContext(ctx, getOIDCSyncRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OIDCSyncRule
+	for rows.Next() {
+		var i OIDCSyncRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Claim,
+			&i.Pattern,
+			pq.Array(&i.SiteRoles),
+			&i.OrganizationID,
+			pq.Array(&i.OrganizationRoles),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOIDCSyncRule = `-- name: InsertOIDCSyncRule :one
+INSERT INTO
+	oidc_sync_rules (
+		id,
+		claim,
+		pattern,
+		site_roles,
+		organization_id,
+		organization_roles,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, claim, pattern, site_roles, organization_id, organization_roles, created_at, updated_at
+`
+
+type InsertOIDCSyncRuleParams struct {
+	ID                uuid.UUID     `db:"id" json:"id"`
+	Claim             string        `db:"claim" json:"claim"`
+	Pattern           string        `db:"pattern" json:"pattern"`
+	SiteRoles         []string      `db:"site_roles" json:"site_roles"`
+	OrganizationID    uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	OrganizationRoles []string      `db:"organization_roles" json:"organization_roles"`
+	CreatedAt         time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertOIDCSyncRule(ctx context.Context, arg InsertOIDCSyncRuleParams) (OIDCSyncRule, error) {
+	row := q.db.QueryRowContext(ctx, insertOIDCSyncRule,
+		arg.ID,
+		arg.Claim,
+		arg.Pattern,
+		pq.Array(arg.SiteRoles),
+		arg.OrganizationID,
+		pq.Array(arg.OrganizationRoles),
+		arg.CreatedAt,
+	)
+	var i OIDCSyncRule
+	err := row.Scan(
+		&i.ID,
+		&i.Claim,
+		&i.Pattern,
+		pq.Array(&i.SiteRoles),
+		&i.OrganizationID,
+		pq.Array(&i.OrganizationRoles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOIDCSyncRule = `-- name: UpdateOIDCSyncRule :one
+UPDATE
+	oidc_sync_rules
+SET
+	claim = $1,
+	pattern = $2,
+	site_roles = $3,
+	organization_id = $4,
+	organization_roles = $5,
+	updated_at = $6
+WHERE
+	id = $7
+RETURNING id, claim, pattern, site_roles, organization_id, organization_roles, created_at, updated_at
+`
+
+type UpdateOIDCSyncRuleParams struct {
+	Claim             string        `db:"claim" json:"claim"`
+	Pattern           string        `db:"pattern" json:"pattern"`
+	SiteRoles         []string      `db:"site_roles" json:"site_roles"`
+	OrganizationID    uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	OrganizationRoles []string      `db:"organization_roles" json:"organization_roles"`
+	UpdatedAt         time.Time     `db:"updated_at" json:"updated_at"`
+	ID                uuid.UUID     `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateOIDCSyncRule(ctx context.Context, arg UpdateOIDCSyncRuleParams) (OIDCSyncRule, error) {
+	row := q.db.QueryRowContext(ctx, updateOIDCSyncRule,
+		arg.Claim,
+		arg.Pattern,
+		pq.Array(arg.SiteRoles),
+		arg.OrganizationID,
+		pq.Array(arg.OrganizationRoles),
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i OIDCSyncRule
+	err := row.Scan(
+		&i.ID,
+		&i.Claim,
+		&i.Pattern,
+		pq.Array(&i.SiteRoles),
+		&i.OrganizationID,
+		pq.Array(&i.OrganizationRoles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
+WITH deleted_group_members AS (
+	DELETE FROM
+		group_members
+	WHERE
+		group_members.user_id = $1
+		AND group_members.group_id IN (SELECT id FROM groups WHERE organization_id = $2)
+)
+DELETE FROM
+	organization_members
+WHERE
+	organization_members.organization_id = $2
+	AND organization_members.user_id = $1
+`
+
+type DeleteOrganizationMemberParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Removes the user from the organization and from the groups of the
+// organization.
+func (q *sqlQuerier) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationMember, arg.UserID, arg.OrganizationID)
+	return err
+}
+
+const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+SELECT
+    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+FROM
+    organization_members
+WHERE
+    user_id = ANY($1 :: uuid [ ])
+GROUP BY
+    user_id
+`
+
+type GetOrganizationIDsByMemberIDsRow struct {
+	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
+	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+}
+
+func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 	var items []GetOrganizationIDsByMemberIDsRow
 	for rows.Next() {
 		var i GetOrganizationIDsByMemberIDsRow
@@ -4144,48 +5140,171 @@ func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.
 	return items, nil
 }
 
-const deleteOldProvisionerDaemons = `-- name: DeleteOldProvisionerDaemons :exec
-DELETE FROM provisioner_daemons WHERE (
-	(created_at < (NOW() - INTERVAL '7 days') AND last_seen_at IS NULL) OR
-	(last_seen_at IS NOT NULL AND last_seen_at < (NOW() - INTERVAL '7 days'))
+const claimWorkspacePrebuild = `-- name: ClaimWorkspacePrebuild :one
+WITH claimed AS (
+	DELETE FROM
+		workspace_prebuilds
+	WHERE
+		workspace_id = (
+			SELECT
+				workspace_prebuilds.workspace_id
+			FROM
+				workspace_prebuilds
+				INNER JOIN workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+				INNER JOIN workspace_builds ON workspace_builds.workspace_id = workspaces.id
+				INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+			WHERE
+				workspace_prebuilds.template_version_preset_id = $1
+				AND workspaces.organization_id = $2
+				AND workspaces.deleted = false
+				AND workspace_builds.build_number = (
+					SELECT
+						MAX(latest.build_number)
+					FROM
+						workspace_builds AS latest
+					WHERE
+						latest.workspace_id = workspaces.id
+				)
+				AND workspace_builds.transition = 'start'
+				AND provisioner_jobs.job_status = 'succeeded'
+			ORDER BY
+				workspace_prebuilds.created_at
+			LIMIT
+				1
+			-- Concurrent claims skip the prebuild instead of waiting for it.
+			FOR UPDATE OF workspace_prebuilds SKIP LOCKED
+		)
+	RETURNING
+		workspace_id
 )
+UPDATE
+	workspaces
+SET
+	owner_id = $3,
+	name = $4,
+	autostart_schedule = $5,
+	ttl = $6,
+	automatic_updates = $7,
+	last_used_at = $8,
+	updated_at = $8
+FROM
+	claimed
+WHERE
+	workspaces.id = claimed.workspace_id
+RETURNING
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl
 `
 
-// Delete provisioner daemons that have been created at least a week ago
-// and have not connected to coderd since a week.
-// A provisioner daemon with "zeroed" last_seen_at column indicates possible
-// connectivity issues (no provisioner daemon activity since registration).
-func (q *sqlQuerier) DeleteOldProvisionerDaemons(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldProvisionerDaemons)
+type ClaimWorkspacePrebuildParams struct {
+	TemplateVersionPresetID uuid.UUID        `db:"template_version_preset_id" json:"template_version_preset_id"`
+	OrganizationID          uuid.UUID        `db:"organization_id" json:"organization_id"`
+	OwnerID                 uuid.UUID        `db:"owner_id" json:"owner_id"`
+	Name                    string           `db:"name" json:"name"`
+	AutostartSchedule       sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl                     sql.NullInt64    `db:"ttl" json:"ttl"`
+	AutomaticUpdates        AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	Now                     time.Time        `db:"now" json:"now"`
+}
+
+// Transfers the oldest ready prebuild of a preset to a new owner. A prebuild
+// is ready once its latest build started it successfully.
+func (q *sqlQuerier) ClaimWorkspacePrebuild(ctx context.Context, arg ClaimWorkspacePrebuildParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, claimWorkspacePrebuild,
+		arg.TemplateVersionPresetID,
+		arg.OrganizationID,
+		arg.OwnerID,
+		arg.Name,
+		arg.AutostartSchedule,
+		arg.Ttl,
+		arg.AutomaticUpdates,
+		arg.Now,
+	)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
+	)
+	return i, err
+}
+
+const deleteWorkspacePrebuildsOfDeletedWorkspaces = `-- name: DeleteWorkspacePrebuildsOfDeletedWorkspaces :exec
+DELETE FROM
+	workspace_prebuilds
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			deleted = true
+	)
+`
+
+func (q *sqlQuerier) DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePrebuildsOfDeletedWorkspaces)
 	return err
 }
 
-const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
+const getPrebuildPresets = `-- name: GetPrebuildPresets :many
 SELECT
-	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+	template_version_presets.id, template_version_presets.template_version_id, template_version_presets.name, template_version_presets.created_at, template_version_presets.desired_prebuild_instances,
+	templates.id AS template_id,
+	templates.organization_id
 FROM
-	provisioner_daemons
+	template_version_presets
+	INNER JOIN templates ON templates.active_version_id = template_version_presets.template_version_id
+WHERE
+	templates.deleted = false
+	AND templates.deprecated = ''
+	AND template_version_presets.desired_prebuild_instances > 0
+ORDER BY
+	templates.id, template_version_presets.name
 `
 
-func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
+type GetPrebuildPresetsRow struct {
+	ID                       uuid.UUID `db:"id" json:"id"`
+	TemplateVersionID        uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name                     string    `db:"name" json:"name"`
+	CreatedAt                time.Time `db:"created_at" json:"created_at"`
+	DesiredPrebuildInstances int32     `db:"desired_prebuild_instances" json:"desired_prebuild_instances"`
+	TemplateID               uuid.UUID `db:"template_id" json:"template_id"`
+	OrganizationID           uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Returns the presets of the active version of every template that want
+// workspaces built ahead of time.
+func (q *sqlQuerier) GetPrebuildPresets(ctx context.Context) ([]GetPrebuildPresetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrebuildPresets)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerDaemon
+	var items []GetPrebuildPresetsRow
 	for rows.Next() {
-		var i ProvisionerDaemon
+		var i GetPrebuildPresetsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
+			&i.TemplateVersionID,
 			&i.Name,
-			pq.Array(&i.Provisioners),
-			&i.ReplicaID,
-			&i.Tags,
-			&i.LastSeenAt,
-			&i.Version,
-			&i.APIVersion,
+			&i.CreatedAt,
+			&i.DesiredPrebuildInstances,
+			&i.TemplateID,
 			&i.OrganizationID,
 		); err != nil {
 			return nil, err
@@ -4201,48 +5320,240 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 	return items, nil
 }
 
-const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
-UPDATE provisioner_daemons
-SET
-	last_seen_at = $1
+const getWorkspacePrebuilds = `-- name: GetWorkspacePrebuilds :many
+SELECT
+	workspace_prebuilds.workspace_id,
+	workspace_prebuilds.template_version_preset_id,
+	workspace_prebuilds.created_at,
+	workspaces.organization_id,
+	workspace_builds.template_version_id,
+	workspace_builds.transition,
+	provisioner_jobs.job_status,
+	provisioner_jobs.completed_at
+FROM
+	workspace_prebuilds
+	INNER JOIN workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+	INNER JOIN workspace_builds ON workspace_builds.workspace_id = workspaces.id
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
 WHERE
-	id = $2
-AND
-	last_seen_at <= $1
+	workspaces.deleted = false
+	AND workspace_builds.build_number = (
+		SELECT
+			MAX(latest.build_number)
+		FROM
+			workspace_builds AS latest
+		WHERE
+			latest.workspace_id = workspaces.id
+	)
+ORDER BY
+	workspace_prebuilds.created_at
 `
 
-type UpdateProvisionerDaemonLastSeenAtParams struct {
-	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
-	ID         uuid.UUID    `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
-	return err
+type GetWorkspacePrebuildsRow struct {
+	WorkspaceID             uuid.UUID            `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionPresetID uuid.UUID            `db:"template_version_preset_id" json:"template_version_preset_id"`
+	CreatedAt               time.Time            `db:"created_at" json:"created_at"`
+	OrganizationID          uuid.UUID            `db:"organization_id" json:"organization_id"`
+	TemplateVersionID       uuid.UUID            `db:"template_version_id" json:"template_version_id"`
+	Transition              WorkspaceTransition  `db:"transition" json:"transition"`
+	JobStatus               ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	CompletedAt             sql.NullTime         `db:"completed_at" json:"completed_at"`
 }
 
-const upsertProvisionerDaemon = `-- name: UpsertProvisionerDaemon :one
-INSERT INTO
-	provisioner_daemons (
-		id,
-		created_at,
-		"name",
-		provisioners,
-		tags,
-		last_seen_at,
-		"version",
-		organization_id,
-		api_version
-	)
-VALUES (
-	gen_random_uuid(),
-	$1,
-	$2,
-	$3,
-	$4,
-	$5,
-	$6,
-	$7,
+// Returns every prebuild that has not been claimed yet along with the
+// status of its latest build.
+func (q *sqlQuerier) GetWorkspacePrebuilds(ctx context.Context) ([]GetWorkspacePrebuildsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePrebuilds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacePrebuildsRow
+	for rows.Next() {
+		var i GetWorkspacePrebuildsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.TemplateVersionPresetID,
+			&i.CreatedAt,
+			&i.OrganizationID,
+			&i.TemplateVersionID,
+			&i.Transition,
+			&i.JobStatus,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspacePrebuild = `-- name: InsertWorkspacePrebuild :exec
+INSERT INTO
+	workspace_prebuilds (workspace_id, template_version_preset_id, created_at)
+VALUES
+	($1, $2, $3)
+`
+
+type InsertWorkspacePrebuildParams struct {
+	WorkspaceID             uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionPresetID uuid.UUID `db:"template_version_preset_id" json:"template_version_preset_id"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspacePrebuild(ctx context.Context, arg InsertWorkspacePrebuildParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspacePrebuild, arg.WorkspaceID, arg.TemplateVersionPresetID, arg.CreatedAt)
+	return err
+}
+
+const deleteOldProvisionerDaemons = `-- name: DeleteOldProvisionerDaemons :exec
+DELETE FROM provisioner_daemons WHERE (
+	(created_at < (NOW() - INTERVAL '7 days') AND last_seen_at IS NULL) OR
+	(last_seen_at IS NOT NULL AND last_seen_at < (NOW() - INTERVAL '7 days'))
+)
+`
+
+// Delete provisioner daemons that have been created at least a week ago
+// and have not connected to coderd since a week.
+// A provisioner daemon with "zeroed" last_seen_at column indicates possible
+// connectivity issues (no provisioner daemon activity since registration).
+func (q *sqlQuerier) DeleteOldProvisionerDaemons(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldProvisionerDaemons)
+	return err
+}
+
+const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
+SELECT
+	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+FROM
+	provisioner_daemons
+`
+
+func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerDaemon
+	for rows.Next() {
+		var i ProvisionerDaemon
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Name,
+			pq.Array(&i.Provisioners),
+			&i.ReplicaID,
+			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.APIVersion,
+			&i.OrganizationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerDaemonsByOrganization = `-- name: GetProvisionerDaemonsByOrganization :many
+SELECT
+	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+FROM
+	provisioner_daemons
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerDaemonsByOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerDaemon
+	for rows.Next() {
+		var i ProvisionerDaemon
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Name,
+			pq.Array(&i.Provisioners),
+			&i.ReplicaID,
+			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.APIVersion,
+			&i.OrganizationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE provisioner_daemons
+SET
+	last_seen_at = $1
+WHERE
+	id = $2
+AND
+	last_seen_at <= $1
+`
+
+type UpdateProvisionerDaemonLastSeenAtParams struct {
+	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
+	return err
+}
+
+const upsertProvisionerDaemon = `-- name: UpsertProvisionerDaemon :one
+INSERT INTO
+	provisioner_daemons (
+		id,
+		created_at,
+		"name",
+		provisioners,
+		tags,
+		last_seen_at,
+		"version",
+		organization_id,
+		api_version
+	)
+VALUES (
+	gen_random_uuid(),
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
 	$8
 ) ON CONFLICT("name", LOWER(COALESCE(tags ->> 'owner'::text, ''::text))) DO UPDATE SET
 	provisioners = $3,
@@ -4427,21 +5738,28 @@ WHERE
 				-- Ensure the caller satisfies all job tags.
 				ELSE nested.tags :: jsonb <@ $5 :: jsonb
 			END
+			-- Ensure the caller has an engine version satisfying the
+			-- template's required_version, given as "<provisioner>:<constraint>".
+			AND (
+				nested.engine_version_constraint = ''
+				OR nested.provisioner :: text || ':' || nested.engine_version_constraint = ANY($6 :: text [ ])
+			)
 		ORDER BY
 			nested.created_at
 		FOR UPDATE
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt      sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID       uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	OrganizationID uuid.UUID         `db:"organization_id" json:"organization_id"`
-	Types          []ProvisionerType `db:"types" json:"types"`
-	Tags           json.RawMessage   `db:"tags" json:"tags"`
+	StartedAt                sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID                 uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	OrganizationID           uuid.UUID         `db:"organization_id" json:"organization_id"`
+	Types                    []ProvisionerType `db:"types" json:"types"`
+	Tags                     json.RawMessage   `db:"tags" json:"tags"`
+	EngineVersionConstraints []string          `db:"engine_version_constraints" json:"engine_version_constraints"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
@@ -4457,6 +5775,7 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		arg.OrganizationID,
 		pq.Array(arg.Types),
 		arg.Tags,
+		pq.Array(arg.EngineVersionConstraints),
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -4479,13 +5798,14 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.EngineVersionConstraint,
 	)
 	return i, err
 }
 
 const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint
 FROM
 	provisioner_jobs
 WHERE
@@ -4523,6 +5843,7 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.EngineVersionConstraint,
 		); err != nil {
 			return nil, err
 		}
@@ -4537,9 +5858,52 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 	return items, nil
 }
 
+const getPendingProvisionerJobEngineVersionConstraints = `-- name: GetPendingProvisionerJobEngineVersionConstraints :many
+SELECT DISTINCT
+	provisioner,
+	engine_version_constraint
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND organization_id = $1
+	AND engine_version_constraint != ''
+`
+
+type GetPendingProvisionerJobEngineVersionConstraintsRow struct {
+	Provisioner             ProvisionerType `db:"provisioner" json:"provisioner"`
+	EngineVersionConstraint string          `db:"engine_version_constraint" json:"engine_version_constraint"`
+}
+
+// Returns the distinct engine version constraints of the jobs waiting for a
+// provisioner daemon, so daemons can check which of them they satisfy before
+// acquiring a job.
+func (q *sqlQuerier) GetPendingProvisionerJobEngineVersionConstraints(ctx context.Context, organizationID uuid.UUID) ([]GetPendingProvisionerJobEngineVersionConstraintsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobEngineVersionConstraints, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingProvisionerJobEngineVersionConstraintsRow
+	for rows.Next() {
+		var i GetPendingProvisionerJobEngineVersionConstraintsRow
+		if err := rows.Scan(&i.Provisioner, &i.EngineVersionConstraint); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint
 FROM
 	provisioner_jobs
 WHERE
@@ -4569,13 +5933,14 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.EngineVersionConstraint,
 	)
 	return i, err
 }
 
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint
 FROM
 	provisioner_jobs
 WHERE
@@ -4611,6 +5976,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.EngineVersionConstraint,
 		); err != nil {
 			return nil, err
 		}
@@ -4645,7 +6011,7 @@ queue_size AS (
 	SELECT COUNT(*) as count FROM unstarted_jobs
 )
 SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.engine_version_constraint,
     COALESCE(qp.queue_position, 0) AS queue_position,
     COALESCE(qs.count, 0) AS queue_size
 FROM
@@ -4693,6 +6059,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.ErrorCode,
 			&i.ProvisionerJob.TraceMetadata,
 			&i.ProvisionerJob.JobStatus,
+			&i.ProvisionerJob.EngineVersionConstraint,
 			&i.QueuePosition,
 			&i.QueueSize,
 		); err != nil {
@@ -4710,7 +6077,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -4742,6 +6109,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.EngineVersionConstraint,
 		); err != nil {
 			return nil, err
 		}
@@ -4770,25 +6138,27 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		engine_version_constraint
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, engine_version_constraint
 `
 
 type InsertProvisionerJobParams struct {
-	ID             uuid.UUID                `db:"id" json:"id"`
-	CreatedAt      time.Time                `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                `db:"updated_at" json:"updated_at"`
-	OrganizationID uuid.UUID                `db:"organization_id" json:"organization_id"`
-	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
-	Provisioner    ProvisionerType          `db:"provisioner" json:"provisioner"`
-	StorageMethod  ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
-	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
-	Type           ProvisionerJobType       `db:"type" json:"type"`
-	Input          json.RawMessage          `db:"input" json:"input"`
-	Tags           StringMap                `db:"tags" json:"tags"`
-	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+	ID                      uuid.UUID                `db:"id" json:"id"`
+	CreatedAt               time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time                `db:"updated_at" json:"updated_at"`
+	OrganizationID          uuid.UUID                `db:"organization_id" json:"organization_id"`
+	InitiatorID             uuid.UUID                `db:"initiator_id" json:"initiator_id"`
+	Provisioner             ProvisionerType          `db:"provisioner" json:"provisioner"`
+	StorageMethod           ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
+	FileID                  uuid.UUID                `db:"file_id" json:"file_id"`
+	Type                    ProvisionerJobType       `db:"type" json:"type"`
+	Input                   json.RawMessage          `db:"input" json:"input"`
+	Tags                    StringMap                `db:"tags" json:"tags"`
+	TraceMetadata           pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+	EngineVersionConstraint string                   `db:"engine_version_constraint" json:"engine_version_constraint"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
@@ -4805,6 +6175,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		arg.Input,
 		arg.Tags,
 		arg.TraceMetadata,
+		arg.EngineVersionConstraint,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -4827,6 +6198,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.EngineVersionConstraint,
 	)
 	return i, err
 }
@@ -4850,6 +6222,29 @@ func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdatePro
 	return err
 }
 
+const updateProvisionerJobEngineVersionConstraintByID = `-- name: UpdateProvisionerJobEngineVersionConstraintByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	engine_version_constraint = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobEngineVersionConstraintByIDParams struct {
+	ID                      uuid.UUID `db:"id" json:"id"`
+	EngineVersionConstraint string    `db:"engine_version_constraint" json:"engine_version_constraint"`
+	UpdatedAt               time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Records the required_version constraint a template import job found in the
+// template, which workspace builds of the template version inherit.
+func (q *sqlQuerier) UpdateProvisionerJobEngineVersionConstraintByID(ctx context.Context, arg UpdateProvisionerJobEngineVersionConstraintByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobEngineVersionConstraintByID, arg.ID, arg.EngineVersionConstraint, arg.UpdatedAt)
+	return err
+}
+
 const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -5283,15 +6678,28 @@ SELECT
 FROM
 	groups g
 LEFT JOIN group_members gm ON
-	g.id = gm.group_id
+	g.id = gm.group_id AND gm.user_id = $1
 WHERE
-	user_id = $1
-OR
-    g.id = g.organization_id
+	g.organization_id = $2
+AND
+	(gm.user_id IS NOT NULL OR g.id = g.organization_id)
+AND
+	EXISTS (
+		SELECT 1 FROM organization_members om
+		WHERE om.organization_id = $2 AND om.user_id = $1
+	)
 `
 
-func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
+type GetQuotaAllowanceForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Returns the sum of the allowances of the groups of the user in the
+// organization, including the Everyone group. Users that aren't members of the
+// organization have no allowance.
+func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, arg GetQuotaAllowanceForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, arg.UserID, arg.OrganizationID)
 	var column_1 int64
 	err := row.Scan(&column_1)
 	return column_1, err
@@ -5316,37 +6724,119 @@ FROM
 	workspaces
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
-WHERE NOT deleted AND workspaces.owner_id = $1
+WHERE NOT deleted AND workspaces.owner_id = $1 AND workspaces.organization_id = $2
 `
 
-func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
+type GetQuotaConsumedForUserParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, arg GetQuotaConsumedForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, arg.OwnerID, arg.OrganizationID)
 	var column_1 int64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
-const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
-DELETE FROM replicas WHERE updated_at < $1
+const getWorkspaceCountForTemplate = `-- name: GetWorkspaceCountForTemplate :one
+SELECT
+	COUNT(*)
+FROM
+	workspaces
+WHERE
+	NOT deleted
+	AND template_id = $1
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_prebuilds
+		WHERE workspace_prebuilds.workspace_id = workspaces.id
+	)
 `
 
-func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
-	return err
+// Prebuilt workspaces that no user has claimed yet don't count towards the
+// limit of the template.
+func (q *sqlQuerier) GetWorkspaceCountForTemplate(ctx context.Context, templateID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceCountForTemplate, templateID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getReplicaByID = `-- name: GetReplicaByID :one
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
+const getWorkspaceCountForUser = `-- name: GetWorkspaceCountForUser :one
+SELECT
+	COUNT(*)
+FROM
+	workspaces
+WHERE NOT deleted AND owner_id = $1 AND organization_id = $2
 `
 
-func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
-	var i Replica
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
+type GetWorkspaceCountForUserParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetWorkspaceCountForUser(ctx context.Context, arg GetWorkspaceCountForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceCountForUser, arg.OwnerID, arg.OrganizationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getWorkspaceLimitForUser = `-- name: GetWorkspaceLimitForUser :one
+SELECT
+	coalesce(MAX(max_workspaces_per_user), 0)::INTEGER
+FROM
+	groups g
+LEFT JOIN group_members gm ON
+	g.id = gm.group_id AND gm.user_id = $1
+WHERE
+	g.organization_id = $2
+AND
+	(gm.user_id IS NOT NULL OR g.id = g.organization_id)
+AND
+	max_workspaces_per_user > 0
+AND
+	EXISTS (
+		SELECT 1 FROM organization_members om
+		WHERE om.organization_id = $2 AND om.user_id = $1
+	)
+`
+
+type GetWorkspaceLimitForUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Returns the highest workspace limit of the groups of the user in the
+// organization. 0 means none of the groups sets a limit.
+func (q *sqlQuerier) GetWorkspaceLimitForUser(ctx context.Context, arg GetWorkspaceLimitForUserParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceLimitForUser, arg.UserID, arg.OrganizationID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
+DELETE FROM replicas WHERE updated_at < $1
+`
+
+func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
+	return err
+}
+
+const getReplicaByID = `-- name: GetReplicaByID :one
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
+`
+
+func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
 		&i.UpdatedAt,
 		&i.Hostname,
 		&i.RegionID,
@@ -5519,6 +7009,153 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
+const deleteSCIMToken = `-- name: DeleteSCIMToken :exec
+DELETE FROM scim_tokens WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteSCIMToken(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSCIMToken, id)
+	return err
+}
+
+const getSCIMTokenByID = `-- name: GetSCIMTokenByID :one
+SELECT id, name, created_at, last_used_at, hashed_secret, display_secret, secret_prefix FROM scim_tokens WHERE id = $1
+`
+
+func (q *sqlQuerier) GetSCIMTokenByID(ctx context.Context, id uuid.UUID) (SCIMToken, error) {
+	row := q.db.QueryRowContext(ctx, getSCIMTokenByID, id)
+	var i SCIMToken
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.SecretPrefix,
+	)
+	return i, err
+}
+
+const getSCIMTokenByPrefix = `-- name: GetSCIMTokenByPrefix :one
+SELECT id, name, created_at, last_used_at, hashed_secret, display_secret, secret_prefix FROM scim_tokens WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetSCIMTokenByPrefix(ctx context.Context, secretPrefix []byte) (SCIMToken, error) {
+	row := q.db.QueryRowContext(ctx, getSCIMTokenByPrefix, secretPrefix)
+	var i SCIMToken
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.SecretPrefix,
+	)
+	return i, err
+}
+
+const getSCIMTokens = `-- name: GetSCIMTokens :many
+SELECT id, name, created_at, last_used_at, hashed_secret, display_secret, secret_prefix FROM scim_tokens ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetSCIMTokens(ctx context.Context) ([]SCIMToken, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SCIMToken
+	for rows.Next() {
+		var i SCIMToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.HashedSecret,
+			&i.DisplaySecret,
+			&i.SecretPrefix,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSCIMToken = `-- name: InsertSCIMToken :one
+INSERT INTO scim_tokens (
+    id,
+    name,
+    created_at,
+    secret_prefix,
+    hashed_secret,
+    display_secret
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING id, name, created_at, last_used_at, hashed_secret, display_secret, secret_prefix
+`
+
+type InsertSCIMTokenParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	Name          string    `db:"name" json:"name"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	SecretPrefix  []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+}
+
+func (q *sqlQuerier) InsertSCIMToken(ctx context.Context, arg InsertSCIMTokenParams) (SCIMToken, error) {
+	row := q.db.QueryRowContext(ctx, insertSCIMToken,
+		arg.ID,
+		arg.Name,
+		arg.CreatedAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+	)
+	var i SCIMToken
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.SecretPrefix,
+	)
+	return i, err
+}
+
+const updateSCIMTokenLastUsedAt = `-- name: UpdateSCIMTokenLastUsedAt :exec
+UPDATE scim_tokens SET
+    last_used_at = $2
+WHERE id = $1
+`
+
+type UpdateSCIMTokenLastUsedAtParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateSCIMTokenLastUsedAt(ctx context.Context, arg UpdateSCIMTokenLastUsedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateSCIMTokenLastUsedAt, arg.ID, arg.LastUsedAt)
+	return err
+}
+
 const getAppSecurityKey = `-- name: GetAppSecurityKey :one
 SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
@@ -6470,90 +8107,412 @@ func (q *sqlQuerier) UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetT
 	return i, err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
+const deleteTemplateReleaseChannelByID = `-- name: DeleteTemplateReleaseChannelByID :exec
+DELETE FROM
+	template_release_channels
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateReleaseChannelByID, id)
+	return err
+}
+
+const getTemplateActiveVersionForUser = `-- name: GetTemplateActiveVersionForUser :one
 SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
+	COALESCE(channel.template_version_id, templates.active_version_id) :: uuid AS active_version_id,
+	COALESCE(channel.name, '') :: text AS release_channel
 FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+	templates
+LEFT JOIN LATERAL (
+	SELECT
+		template_release_channels.name,
+		template_release_channels.template_version_id
+	FROM
+		template_release_channels
+	WHERE
+		template_release_channels.template_id = templates.id
+		AND (
+			$1 :: uuid = ANY(template_release_channels.user_ids)
+			OR template_release_channels.group_ids && ARRAY(
+				SELECT group_id FROM group_members WHERE group_members.user_id = $1
+				UNION
+				-- The "Everyone" group of an organization has the organization's ID.
+				SELECT organization_id FROM organization_members WHERE organization_members.user_id = $1
+			)
+		)
+	ORDER BY
+		$1 = ANY(template_release_channels.user_ids) DESC,
+		template_release_channels.name ASC
+	LIMIT 1
+) channel ON true
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
-ORDER BY
-	workspace_builds.created_at DESC
-)
-SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	templates.id = $2
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+type GetTemplateActiveVersionForUserParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
 }
 
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
-	Delete95 float64 `db:"delete_95" json:"delete_95"`
+type GetTemplateActiveVersionForUserRow struct {
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	ReleaseChannel  string    `db:"release_channel" json:"release_channel"`
 }
 
-func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
-	var i GetTemplateAverageBuildTimeRow
+// Returns the version of the template that the workspaces of the user use.
+// This is the version of the release channel the user is assigned to, or the
+// active version of the template if the user isn't assigned to any channel. A
+// channel the user is assigned to directly takes precedence over channels
+// assigned to one of their groups, and ties are broken by channel name.
+func (q *sqlQuerier) GetTemplateActiveVersionForUser(ctx context.Context, arg GetTemplateActiveVersionForUserParams) (GetTemplateActiveVersionForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateActiveVersionForUser, arg.UserID, arg.TemplateID)
+	var i GetTemplateActiveVersionForUserRow
 	err := row.Scan(
-		&i.Start50,
-		&i.Stop50,
-		&i.Delete50,
-		&i.Start95,
-		&i.Stop95,
-		&i.Delete95,
+		&i.ActiveVersionID,
+		&i.ReleaseChannel,
 	)
 	return i, err
 }
 
-const getTemplateByID = `-- name: GetTemplateByID :one
+const getTemplateActiveVersionsByWorkspaceIDs = `-- name: GetTemplateActiveVersionsByWorkspaceIDs :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username
+	workspaces.id AS workspace_id,
+	COALESCE(channel.template_version_id, templates.active_version_id) :: uuid AS active_version_id,
+	COALESCE(channel.name, '') :: text AS release_channel
 FROM
-	template_with_users
+	workspaces
+JOIN
+	templates ON templates.id = workspaces.template_id
+LEFT JOIN LATERAL (
+	SELECT
+		template_release_channels.name,
+		template_release_channels.template_version_id
+	FROM
+		template_release_channels
+	WHERE
+		template_release_channels.template_id = workspaces.template_id
+		AND (
+			workspaces.owner_id = ANY(template_release_channels.user_ids)
+			OR template_release_channels.group_ids && ARRAY(
+				SELECT group_id FROM group_members WHERE group_members.user_id = workspaces.owner_id
+				UNION
+				SELECT organization_id FROM organization_members WHERE organization_members.user_id = workspaces.owner_id
+			)
+		)
+	ORDER BY
+		workspaces.owner_id = ANY(template_release_channels.user_ids) DESC,
+		template_release_channels.name ASC
+	LIMIT 1
+) channel ON true
 WHERE
-	id = $1
-LIMIT
-	1
+	workspaces.id = ANY($1 :: uuid[])
 `
 
-func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
-	var i Template
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
+type GetTemplateActiveVersionsByWorkspaceIDsRow struct {
+	WorkspaceID     uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	ReleaseChannel  string    `db:"release_channel" json:"release_channel"`
+}
+
+// Returns the version of its template that each workspace uses, resolved for
+// the workspace owner like GetTemplateActiveVersionForUser.
+func (q *sqlQuerier) GetTemplateActiveVersionsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetTemplateActiveVersionsByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateActiveVersionsByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateActiveVersionsByWorkspaceIDsRow
+	for rows.Next() {
+		var i GetTemplateActiveVersionsByWorkspaceIDsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.ActiveVersionID,
+			&i.ReleaseChannel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateReleaseChannelByID = `-- name: GetTemplateReleaseChannelByID :one
+SELECT
+	id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at
+FROM
+	template_release_channels
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateReleaseChannelByID(ctx context.Context, id uuid.UUID) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateReleaseChannelByID, id)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.TemplateVersionID,
+		pq.Array(&i.UserIDs),
+		pq.Array(&i.GroupIDs),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateReleaseChannelByTemplateIDAndName = `-- name: GetTemplateReleaseChannelByTemplateIDAndName :one
+SELECT
+	id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at
+FROM
+	template_release_channels
+WHERE
+	template_id = $1
+	AND name = $2
+`
+
+type GetTemplateReleaseChannelByTemplateIDAndNameParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetTemplateReleaseChannelByTemplateIDAndName(ctx context.Context, arg GetTemplateReleaseChannelByTemplateIDAndNameParams) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateReleaseChannelByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.TemplateVersionID,
+		pq.Array(&i.UserIDs),
+		pq.Array(&i.GroupIDs),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateReleaseChannelsByTemplateID = `-- name: GetTemplateReleaseChannelsByTemplateID :many
+SELECT
+	id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at
+FROM
+	template_release_channels
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetTemplateReleaseChannelsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateReleaseChannel, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateReleaseChannelsByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateReleaseChannel
+	for rows.Next() {
+		var i TemplateReleaseChannel
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.Name,
+			&i.TemplateVersionID,
+			pq.Array(&i.UserIDs),
+			pq.Array(&i.GroupIDs),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateReleaseChannel = `-- name: InsertTemplateReleaseChannel :one
+INSERT INTO
+	template_release_channels (id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5 :: uuid[], $6 :: uuid[], $7, $7)
+RETURNING id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at
+`
+
+type InsertTemplateReleaseChannelParams struct {
+	ID                uuid.UUID   `db:"id" json:"id"`
+	TemplateID        uuid.UUID   `db:"template_id" json:"template_id"`
+	Name              string      `db:"name" json:"name"`
+	TemplateVersionID uuid.UUID   `db:"template_version_id" json:"template_version_id"`
+	UserIDs           []uuid.UUID `db:"user_ids" json:"user_ids"`
+	GroupIDs          []uuid.UUID `db:"group_ids" json:"group_ids"`
+	CreatedAt         time.Time   `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertTemplateReleaseChannel(ctx context.Context, arg InsertTemplateReleaseChannelParams) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateReleaseChannel,
+		arg.ID,
+		arg.TemplateID,
+		arg.Name,
+		arg.TemplateVersionID,
+		pq.Array(arg.UserIDs),
+		pq.Array(arg.GroupIDs),
+		arg.CreatedAt,
+	)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.TemplateVersionID,
+		pq.Array(&i.UserIDs),
+		pq.Array(&i.GroupIDs),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTemplateReleaseChannelByID = `-- name: UpdateTemplateReleaseChannelByID :one
+UPDATE
+	template_release_channels
+SET
+	template_version_id = $1,
+	user_ids = $2 :: uuid[],
+	group_ids = $3 :: uuid[],
+	updated_at = $4
+WHERE
+	id = $5
+RETURNING id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at
+`
+
+type UpdateTemplateReleaseChannelByIDParams struct {
+	TemplateVersionID uuid.UUID   `db:"template_version_id" json:"template_version_id"`
+	UserIDs           []uuid.UUID `db:"user_ids" json:"user_ids"`
+	GroupIDs          []uuid.UUID `db:"group_ids" json:"group_ids"`
+	UpdatedAt         time.Time   `db:"updated_at" json:"updated_at"`
+	ID                uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateReleaseChannelByID(ctx context.Context, arg UpdateTemplateReleaseChannelByIDParams) (TemplateReleaseChannel, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateReleaseChannelByID,
+		arg.TemplateVersionID,
+		pq.Array(arg.UserIDs),
+		pq.Array(arg.GroupIDs),
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i TemplateReleaseChannel
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.Name,
+		&i.TemplateVersionID,
+		pq.Array(&i.UserIDs),
+		pq.Array(&i.GroupIDs),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
+SELECT
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
+FROM
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
+WHERE
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
+`
+
+type GetTemplateAverageBuildTimeParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
+}
+
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
+	Delete95 float64 `db:"delete_95" json:"delete_95"`
+}
+
+func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
+	var i GetTemplateAverageBuildTimeRow
+	err := row.Scan(
+		&i.Start50,
+		&i.Stop50,
+		&i.Delete50,
+		&i.Start95,
+		&i.Stop95,
+		&i.Delete95,
+	)
+	return i, err
+}
+
+const getTemplateByID = `-- name: GetTemplateByID :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, max_workspaces, created_by_avatar_url, created_by_username
+FROM
+	template_with_users
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
 		&i.DefaultTTL,
 		&i.CreatedBy,
 		&i.Icon,
@@ -6573,6 +8532,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.Deprecated,
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
+		&i.MaxWorkspaces,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6581,7 +8541,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, max_workspaces, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -6630,6 +8590,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.Deprecated,
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
+		&i.MaxWorkspaces,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6637,7 +8598,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, max_workspaces, created_by_avatar_url, created_by_username FROM template_with_users AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -6679,6 +8640,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.Deprecated,
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
+			&i.MaxWorkspaces,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -6697,7 +8659,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, max_workspaces, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -6789,6 +8751,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.Deprecated,
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
+			&i.MaxWorkspaces,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -6962,7 +8925,8 @@ SET
 	display_name = $6,
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
-	max_port_sharing_level = $9
+	max_port_sharing_level = $9,
+	max_workspaces = $10
 WHERE
 	id = $1
 `
@@ -6977,6 +8941,7 @@ type UpdateTemplateMetaByIDParams struct {
 	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
 	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
 	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	MaxWorkspaces                int32           `db:"max_workspaces" json:"max_workspaces"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -6990,6 +8955,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.AllowUserCancelWorkspaceJobs,
 		arg.GroupACL,
 		arg.MaxPortSharingLevel,
+		arg.MaxWorkspaces,
 	)
 	return err
 }
@@ -7046,37 +9012,35 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 	return err
 }
 
-const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+const getTemplateVersionDiagnostics = `-- name: GetTemplateVersionDiagnostics :many
+SELECT
+	id, template_version_id, severity, code, summary, detail, filename, line
+FROM
+	template_version_diagnostics
+WHERE
+	template_version_id = $1
+ORDER BY
+	filename, line, code
 `
 
-func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+func (q *sqlQuerier) GetTemplateVersionDiagnostics(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionDiagnostic, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionDiagnostics, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionParameter
+	var items []TemplateVersionDiagnostic
 	for rows.Next() {
-		var i TemplateVersionParameter
+		var i TemplateVersionDiagnostic
 		if err := rows.Scan(
+			&i.ID,
 			&i.TemplateVersionID,
-			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Mutable,
-			&i.DefaultValue,
-			&i.Icon,
-			&i.Options,
-			&i.ValidationRegex,
-			&i.ValidationMin,
-			&i.ValidationMax,
-			&i.ValidationError,
-			&i.ValidationMonotonic,
-			&i.Required,
-			&i.DisplayName,
-			&i.DisplayOrder,
-			&i.Ephemeral,
+			&i.Severity,
+			&i.Code,
+			&i.Summary,
+			&i.Detail,
+			&i.Filename,
+			&i.Line,
 		); err != nil {
 			return nil, err
 		}
@@ -7091,12 +9055,109 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 	return items, nil
 }
 
-const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+const insertTemplateVersionDiagnostic = `-- name: InsertTemplateVersionDiagnostic :one
 INSERT INTO
-    template_version_parameters (
-        template_version_id,
-        name,
-        description,
+	template_version_diagnostics (
+		id,
+		template_version_id,
+		severity,
+		code,
+		summary,
+		detail,
+		filename,
+		line
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, template_version_id, severity, code, summary, detail, filename, line
+`
+
+type InsertTemplateVersionDiagnosticParams struct {
+	ID                uuid.UUID                         `db:"id" json:"id"`
+	TemplateVersionID uuid.UUID                         `db:"template_version_id" json:"template_version_id"`
+	Severity          TemplateVersionDiagnosticSeverity `db:"severity" json:"severity"`
+	Code              string                            `db:"code" json:"code"`
+	Summary           string                            `db:"summary" json:"summary"`
+	Detail            string                            `db:"detail" json:"detail"`
+	Filename          string                            `db:"filename" json:"filename"`
+	Line              int32                             `db:"line" json:"line"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionDiagnostic(ctx context.Context, arg InsertTemplateVersionDiagnosticParams) (TemplateVersionDiagnostic, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionDiagnostic,
+		arg.ID,
+		arg.TemplateVersionID,
+		arg.Severity,
+		arg.Code,
+		arg.Summary,
+		arg.Detail,
+		arg.Filename,
+		arg.Line,
+	)
+	var i TemplateVersionDiagnostic
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateVersionID,
+		&i.Severity,
+		&i.Code,
+		&i.Summary,
+		&i.Detail,
+		&i.Filename,
+		&i.Line,
+	)
+	return i, err
+}
+
+const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+`
+
+func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionParameter
+	for rows.Next() {
+		var i TemplateVersionParameter
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Mutable,
+			&i.DefaultValue,
+			&i.Icon,
+			&i.Options,
+			&i.ValidationRegex,
+			&i.ValidationMin,
+			&i.ValidationMax,
+			&i.ValidationError,
+			&i.ValidationMonotonic,
+			&i.Required,
+			&i.DisplayName,
+			&i.DisplayOrder,
+			&i.Ephemeral,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+INSERT INTO
+    template_version_parameters (
+        template_version_id,
+        name,
+        description,
         type,
         mutable,
         default_value,
@@ -7197,6 +9258,223 @@ func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg Ins
 	return i, err
 }
 
+const getTemplateVersionPresetByID = `-- name: GetTemplateVersionPresetByID :one
+SELECT
+	id, template_version_id, name, created_at, desired_prebuild_instances
+FROM
+	template_version_presets
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionPresetByID(ctx context.Context, id uuid.UUID) (TemplateVersionPreset, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionPresetByID, id)
+	var i TemplateVersionPreset
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.DesiredPrebuildInstances,
+	)
+	return i, err
+}
+
+const getTemplateVersionPresetParameters = `-- name: GetTemplateVersionPresetParameters :many
+SELECT
+	template_version_preset_parameters.template_version_preset_id, template_version_preset_parameters.name, template_version_preset_parameters.value
+FROM
+	template_version_preset_parameters
+	INNER JOIN template_version_presets ON template_version_presets.id = template_version_preset_parameters.template_version_preset_id
+WHERE
+	template_version_presets.template_version_id = $1
+ORDER BY
+	template_version_preset_parameters.name
+`
+
+// Returns the parameters of every preset of a template version.
+func (q *sqlQuerier) GetTemplateVersionPresetParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPresetParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPresetParameters, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPresetParameter
+	for rows.Next() {
+		var i TemplateVersionPresetParameter
+		if err := rows.Scan(
+			&i.TemplateVersionPresetID,
+			&i.Name,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionPresetParametersByPresetID = `-- name: GetTemplateVersionPresetParametersByPresetID :many
+SELECT
+	template_version_preset_id, name, value
+FROM
+	template_version_preset_parameters
+WHERE
+	template_version_preset_id = $1
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetTemplateVersionPresetParametersByPresetID(ctx context.Context, templateVersionPresetID uuid.UUID) ([]TemplateVersionPresetParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPresetParametersByPresetID, templateVersionPresetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPresetParameter
+	for rows.Next() {
+		var i TemplateVersionPresetParameter
+		if err := rows.Scan(
+			&i.TemplateVersionPresetID,
+			&i.Name,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionPresets = `-- name: GetTemplateVersionPresets :many
+SELECT
+	id, template_version_id, name, created_at, desired_prebuild_instances
+FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPresets, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPreset
+	for rows.Next() {
+		var i TemplateVersionPreset
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.DesiredPrebuildInstances,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionPreset = `-- name: InsertTemplateVersionPreset :one
+INSERT INTO
+	template_version_presets (id, template_version_id, name, created_at, desired_prebuild_instances)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, template_version_id, name, created_at, desired_prebuild_instances
+`
+
+type InsertTemplateVersionPresetParams struct {
+	ID                       uuid.UUID `db:"id" json:"id"`
+	TemplateVersionID        uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name                     string    `db:"name" json:"name"`
+	CreatedAt                time.Time `db:"created_at" json:"created_at"`
+	DesiredPrebuildInstances int32     `db:"desired_prebuild_instances" json:"desired_prebuild_instances"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionPreset(ctx context.Context, arg InsertTemplateVersionPresetParams) (TemplateVersionPreset, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionPreset,
+		arg.ID,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.CreatedAt,
+		arg.DesiredPrebuildInstances,
+	)
+	var i TemplateVersionPreset
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.DesiredPrebuildInstances,
+	)
+	return i, err
+}
+
+const insertTemplateVersionPresetParameters = `-- name: InsertTemplateVersionPresetParameters :many
+INSERT INTO
+	template_version_preset_parameters (template_version_preset_id, name, value)
+SELECT
+	$1 :: uuid AS template_version_preset_id,
+	unnest($2 :: text [ ]) AS name,
+	unnest($3 :: text [ ]) AS value
+RETURNING template_version_preset_parameters.template_version_preset_id, template_version_preset_parameters.name, template_version_preset_parameters.value
+`
+
+type InsertTemplateVersionPresetParametersParams struct {
+	TemplateVersionPresetID uuid.UUID `db:"template_version_preset_id" json:"template_version_preset_id"`
+	Names                   []string  `db:"names" json:"names"`
+	Values                  []string  `db:"values" json:"values"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionPresetParameters(ctx context.Context, arg InsertTemplateVersionPresetParametersParams) ([]TemplateVersionPresetParameter, error) {
+	rows, err := q.db.QueryContext(ctx, insertTemplateVersionPresetParameters, arg.TemplateVersionPresetID, pq.Array(arg.Names), pq.Array(arg.Values))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPresetParameter
+	for rows.Next() {
+		var i TemplateVersionPresetParameter
+		if err := rows.Scan(
+			&i.TemplateVersionPresetID,
+			&i.Name,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
 UPDATE
 	template_versions
@@ -7254,6 +9532,11 @@ FROM
 		)
 		-- Also never archive the active template version
 		AND active_version_id != scoped_template_versions.id
+		-- Nor the versions of release channels
+		AND NOT EXISTS (
+			SELECT 1 FROM template_release_channels
+			WHERE template_release_channels.template_version_id = scoped_template_versions.id
+		)
 		AND CASE
 			-- Optionally, only archive versions that match a given
 			-- job status like 'failed'.
@@ -8113,48 +10396,255 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 	return i, err
 }
 
-const allUserIDs = `-- name: AllUserIDs :many
-SELECT DISTINCT id FROM USERS
+const deleteOldUserLoginAttempts = `-- name: DeleteOldUserLoginAttempts :exec
+DELETE FROM user_login_attempts WHERE created_at < NOW() - INTERVAL '90 days'
 `
 
-// AllUserIDs returns all UserIDs regardless of user status or deletion.
-func (q *sqlQuerier) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, allUserIDs)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []uuid.UUID
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// Login history is kept for 90 days to detect logins from new IP addresses or
+// user agents.
+func (q *sqlQuerier) DeleteOldUserLoginAttempts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldUserLoginAttempts)
+	return err
 }
 
-const getActiveUserCount = `-- name: GetActiveUserCount :one
-SELECT
-	COUNT(*)
-FROM
-	users
-WHERE
-	status = 'active'::user_status AND deleted = false
+const deleteUserLoginFailures = `-- name: DeleteUserLoginFailures :exec
+DELETE FROM user_login_attempts WHERE user_id = $1 :: uuid AND NOT success
 `
 
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *sqlQuerier) DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserLoginFailures, userID)
+	return err
+}
+
+const deleteUserLoginLockoutByUserID = `-- name: DeleteUserLoginLockoutByUserID :exec
+DELETE FROM user_login_lockouts WHERE user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserLoginLockoutByUserID, userID)
+	return err
+}
+
+const getLoginFailureStats = `-- name: GetLoginFailureStats :one
+SELECT
+	COUNT(*) FILTER (WHERE user_id = $1 :: uuid) AS user_failures,
+	COALESCE(MAX(created_at) FILTER (WHERE user_id = $1 :: uuid), $2 :: timestamptz) :: timestamptz AS last_user_failure,
+	COUNT(*) FILTER (WHERE ip = $3) AS ip_failures,
+	COALESCE(MAX(created_at) FILTER (WHERE ip = $3), $2) :: timestamptz AS last_ip_failure
+FROM
+	user_login_attempts
+WHERE
+	NOT success
+	AND created_at > $2
+	AND (user_id = $1 :: uuid OR ip = $3)
+`
+
+type GetLoginFailureStatsParams struct {
+	UserID uuid.UUID   `db:"user_id" json:"user_id"`
+	Since  time.Time   `db:"since" json:"since"`
+	Ip     pqtype.Inet `db:"ip" json:"ip"`
+}
+
+type GetLoginFailureStatsRow struct {
+	UserFailures    int64     `db:"user_failures" json:"user_failures"`
+	LastUserFailure time.Time `db:"last_user_failure" json:"last_user_failure"`
+	IpFailures      int64     `db:"ip_failures" json:"ip_failures"`
+	LastIpFailure   time.Time `db:"last_ip_failure" json:"last_ip_failure"`
+}
+
+// Counts the failed password logins since @since for the user and for the IP
+// address, along with the time of the latest failure of each. The times fall
+// back to @since when there are no failures.
+func (q *sqlQuerier) GetLoginFailureStats(ctx context.Context, arg GetLoginFailureStatsParams) (GetLoginFailureStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getLoginFailureStats, arg.UserID, arg.Since, arg.Ip)
+	var i GetLoginFailureStatsRow
+	err := row.Scan(
+		&i.UserFailures,
+		&i.LastUserFailure,
+		&i.IpFailures,
+		&i.LastIpFailure,
+	)
+	return i, err
+}
+
+const getUserLoginHistorySummary = `-- name: GetUserLoginHistorySummary :one
+SELECT
+	COUNT(*) > 0 AS has_logins,
+	COUNT(*) FILTER (WHERE ip = $1) > 0 AS seen_ip,
+	COUNT(*) FILTER (WHERE user_agent = $2) > 0 AS seen_user_agent
+FROM
+	user_login_attempts
+WHERE
+	user_id = $3 :: uuid
+	AND success
+`
+
+type GetUserLoginHistorySummaryParams struct {
+	Ip        pqtype.Inet `db:"ip" json:"ip"`
+	UserAgent string      `db:"user_agent" json:"user_agent"`
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+}
+
+type GetUserLoginHistorySummaryRow struct {
+	HasLogins     bool `db:"has_logins" json:"has_logins"`
+	SeenIp        bool `db:"seen_ip" json:"seen_ip"`
+	SeenUserAgent bool `db:"seen_user_agent" json:"seen_user_agent"`
+}
+
+// Reports whether the user has logged in with a password before, and whether
+// any of those logins came from the IP address or user agent.
+func (q *sqlQuerier) GetUserLoginHistorySummary(ctx context.Context, arg GetUserLoginHistorySummaryParams) (GetUserLoginHistorySummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserLoginHistorySummary, arg.Ip, arg.UserAgent, arg.UserID)
+	var i GetUserLoginHistorySummaryRow
+	err := row.Scan(
+		&i.HasLogins,
+		&i.SeenIp,
+		&i.SeenUserAgent,
+	)
+	return i, err
+}
+
+const getUserLoginLockoutByUserID = `-- name: GetUserLoginLockoutByUserID :one
+SELECT * FROM user_login_lockouts WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLoginLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLoginLockout, error) {
+	row := q.db.QueryRowContext(ctx, getUserLoginLockoutByUserID, userID)
+	var i UserLoginLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.CreatedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const insertUserLoginAttempt = `-- name: InsertUserLoginAttempt :exec
+INSERT INTO user_login_attempts (
+    id,
+    user_id,
+    ip,
+    user_agent,
+    success,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+`
+
+type InsertUserLoginAttemptParams struct {
+	ID        uuid.UUID     `db:"id" json:"id"`
+	UserID    uuid.NullUUID `db:"user_id" json:"user_id"`
+	Ip        pqtype.Inet   `db:"ip" json:"ip"`
+	UserAgent string        `db:"user_agent" json:"user_agent"`
+	Success   bool          `db:"success" json:"success"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertUserLoginAttempt(ctx context.Context, arg InsertUserLoginAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserLoginAttempt,
+		arg.ID,
+		arg.UserID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Success,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const upsertUserLoginLockout = `-- name: UpsertUserLoginLockout :one
+INSERT INTO user_login_lockouts (
+    user_id,
+    failed_attempts,
+    created_at,
+    locked_until
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    failed_attempts = $2,
+    created_at = $3,
+    locked_until = $4
+RETURNING user_id, failed_attempts, created_at, locked_until
+`
+
+type UpsertUserLoginLockoutParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+}
+
+func (q *sqlQuerier) UpsertUserLoginLockout(ctx context.Context, arg UpsertUserLoginLockoutParams) (UserLoginLockout, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserLoginLockout,
+		arg.UserID,
+		arg.FailedAttempts,
+		arg.CreatedAt,
+		arg.LockedUntil,
+	)
+	var i UserLoginLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.CreatedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const allUserIDs = `-- name: AllUserIDs :many
+SELECT DISTINCT id FROM USERS
+`
+
+// AllUserIDs returns all UserIDs regardless of user status or deletion.
+func (q *sqlQuerier) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, allUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false AND is_service_account = false
+`
+
+// Service accounts are not counted, as they do not use a licensed seat.
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
 const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
@@ -8222,7 +10712,7 @@ func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.
 
 const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 FROM
 	users
 WHERE
@@ -8256,13 +10746,14 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 FROM
 	users
 WHERE
@@ -8290,6 +10781,7 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8312,7 +10804,7 @@ func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
 
 const getUsers = `-- name: GetUsers :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, COUNT(*) OVER() AS count
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account, COUNT(*) OVER() AS count
 FROM
 	users
 WHERE
@@ -8411,6 +10903,7 @@ type GetUsersRow struct {
 	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
 	ThemePreference    string         `db:"theme_preference" json:"theme_preference"`
 	Name               string         `db:"name" json:"name"`
+	IsServiceAccount   bool           `db:"is_service_account" json:"is_service_account"`
 	Count              int64          `db:"count" json:"count"`
 }
 
@@ -8449,6 +10942,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 			&i.QuietHoursSchedule,
 			&i.ThemePreference,
 			&i.Name,
+			&i.IsServiceAccount,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -8465,7 +10959,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 }
 
 const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name FROM users WHERE id = ANY($1 :: uuid [ ])
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account FROM users WHERE id = ANY($1 :: uuid [ ])
 `
 
 // This shouldn't check for deleted, because it's frequently used
@@ -8496,6 +10990,7 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 			&i.QuietHoursSchedule,
 			&i.ThemePreference,
 			&i.Name,
+			&i.IsServiceAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -8520,21 +11015,23 @@ INSERT INTO
 		created_at,
 		updated_at,
 		rbac_roles,
-		login_type
+		login_type,
+		is_service_account
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type InsertUserParams struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
+	ID               uuid.UUID      `db:"id" json:"id"`
+	Email            string         `db:"email" json:"email"`
+	Username         string         `db:"username" json:"username"`
+	HashedPassword   []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt        time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles        pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType        LoginType      `db:"login_type" json:"login_type"`
+	IsServiceAccount bool           `db:"is_service_account" json:"is_service_account"`
 }
 
 func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
@@ -8547,6 +11044,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		arg.UpdatedAt,
 		arg.RBACRoles,
 		arg.LoginType,
+		arg.IsServiceAccount,
 	)
 	var i User
 	err := row.Scan(
@@ -8565,6 +11063,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8578,6 +11077,8 @@ SET
 WHERE
     last_seen_at < $2 :: timestamp
     AND status = 'active'::user_status
+    -- Service accounts only use tokens, so they are never dormant.
+    AND is_service_account = false
 RETURNING id, email, last_seen_at
 `
 
@@ -8623,7 +11124,7 @@ SET
 	updated_at = $3
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserAppearanceSettingsParams struct {
@@ -8651,6 +11152,7 @@ func (q *sqlQuerier) UpdateUserAppearanceSettings(ctx context.Context, arg Updat
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8695,7 +11197,7 @@ SET
 	last_seen_at = $2,
 	updated_at = $3
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserLastSeenAtParams struct {
@@ -8723,6 +11225,7 @@ func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLas
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8740,7 +11243,7 @@ SET
 		'':: bytea
 	END
 WHERE
-	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserLoginTypeParams struct {
@@ -8767,6 +11270,7 @@ func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLogi
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8782,7 +11286,7 @@ SET
 	name = $6
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserProfileParams struct {
@@ -8820,6 +11324,7 @@ func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfil
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8831,7 +11336,7 @@ SET
 	quiet_hours_schedule = $2
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserQuietHoursScheduleParams struct {
@@ -8858,6 +11363,7 @@ func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg Updat
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
 	)
 	return i, err
 }
@@ -8870,7 +11376,7 @@ SET
 	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
 	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
 `
 
 type UpdateUserRolesParams struct {
@@ -8897,45 +11403,715 @@ func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesPar
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		&i.IsServiceAccount,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
+	users
+SET
+	status = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, is_service_account
+`
+
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		&i.IsServiceAccount,
+	)
+	return i, err
+}
+
+const deleteUserTOTPByUserID = `-- name: DeleteUserTOTPByUserID :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTPByUserID, userID)
+	return err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :one
+UPDATE
+	user_totp
+SET
+	enabled_at = $1,
+	last_used_step = $2,
+	hashed_recovery_codes = $3 :: text[]
+WHERE
+	user_id = $4
+	AND enabled_at IS NULL
+RETURNING user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at
+`
+
+type EnableUserTOTPParams struct {
+	EnabledAt           sql.NullTime `db:"enabled_at" json:"enabled_at"`
+	LastUsedStep        int64        `db:"last_used_step" json:"last_used_step"`
+	HashedRecoveryCodes []string     `db:"hashed_recovery_codes" json:"hashed_recovery_codes"`
+	UserID              uuid.UUID    `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, enableUserTOTP,
+		arg.EnabledAt,
+		arg.LastUsedStep,
+		pq.Array(arg.HashedRecoveryCodes),
+		arg.UserID,
+	)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTPByUserID = `-- name: GetUserTOTPByUserID :one
+SELECT
+	user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at
+FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTPByUserID, userID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateUserTOTPLastUsedStep = `-- name: UpdateUserTOTPLastUsedStep :one
+UPDATE
+	user_totp
+SET
+	last_used_step = $1
+WHERE
+	user_id = $2
+	AND last_used_step < $1
+RETURNING user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at
+`
+
+type UpdateUserTOTPLastUsedStepParams struct {
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Only moves forward, so a code is accepted at most once. Returns no rows when
+// the step was already used.
+func (q *sqlQuerier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTOTPLastUsedStep, arg.LastUsedStep, arg.UserID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO user_totp (
+	user_id,
+	secret,
+	created_at
+) VALUES (
+	$1,
+	$2,
+	$3
+)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	enabled_at = NULL,
+	last_used_step = 0,
+	hashed_recovery_codes = '{}'::text[],
+	created_at = $3
+WHERE
+	user_totp.enabled_at IS NULL
+RETURNING user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at
+`
+
+type UpsertUserTOTPParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Secret    string    `db:"secret" json:"secret"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Starts a new enrollment, replacing any earlier enrollment that was not
+// confirmed. Returns no rows when the user already has TOTP enabled.
+func (q *sqlQuerier) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserTOTP, arg.UserID, arg.Secret, arg.CreatedAt)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useUserTOTPRecoveryCode = `-- name: UseUserTOTPRecoveryCode :one
+UPDATE
+	user_totp
+SET
+	hashed_recovery_codes = array_remove(hashed_recovery_codes, $1 :: text)
+WHERE
+	user_id = $2
+	AND enabled_at IS NOT NULL
+	AND $1 :: text = ANY(hashed_recovery_codes)
+RETURNING user_id, secret, enabled_at, last_used_step, hashed_recovery_codes, created_at
+`
+
+type UseUserTOTPRecoveryCodeParams struct {
+	HashedCode string    `db:"hashed_code" json:"hashed_code"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Removes a recovery code so it cannot be used again. Returns no rows when the
+// code does not match.
+func (q *sqlQuerier) UseUserTOTPRecoveryCode(ctx context.Context, arg UseUserTOTPRecoveryCodeParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, useUserTOTPRecoveryCode, arg.HashedCode, arg.UserID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const acquireWebhookDeliveries = `-- name: AcquireWebhookDeliveries :many
+UPDATE
+	webhook_deliveries
+SET
+	status = 'leased' :: webhook_delivery_status,
+	updated_at = $1,
+	leased_until = $2
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.attempt_count < $3 :: int
+			AND (
+				nested.status = 'pending' :: webhook_delivery_status
+				OR (nested.status = 'temporary_failure' :: webhook_delivery_status AND nested.next_attempt_after <= $1)
+				OR (nested.status = 'leased' :: webhook_delivery_status AND nested.leased_until <= $1)
+			)
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			$4 :: int
+	) RETURNING id, webhook_id, event, payload, status, attempt_count, response_status, response_body, error, created_at, updated_at, leased_until, next_attempt_after, delivered_at
+`
+
+type AcquireWebhookDeliveriesParams struct {
+	Now             time.Time    `db:"now" json:"now"`
+	LeasedUntil     sql.NullTime `db:"leased_until" json:"leased_until"`
+	MaxAttemptCount int32        `db:"max_attempt_count" json:"max_attempt_count"`
+	Count           int32        `db:"count" json:"count"`
+}
+
+// Acquires a batch of deliveries that are waiting to be sent: new
+// deliveries, failed deliveries whose retry is due, and leased deliveries
+// whose lease expired without an outcome being recorded.
+//
+// SKIP LOCKED is used to jump over locked rows. This prevents
+// multiple coderd replicas from acquiring the same deliveries.
+func (q *sqlQuerier) AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, acquireWebhookDeliveries,
+		arg.Now,
+		arg.LeasedUntil,
+		arg.MaxAttemptCount,
+		arg.Count,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.AttemptCount,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextAttemptAfter,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteOldWebhookDeliveries = `-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM
+	webhook_deliveries
+WHERE
+	status IN ('succeeded' :: webhook_delivery_status, 'permanent_failure' :: webhook_delivery_status)
+	AND updated_at < (NOW() - INTERVAL '7 days')
+`
+
+// Delete deliveries that reached a final state more than a week ago.
+func (q *sqlQuerier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWebhookDeliveries)
+	return err
+}
+
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT
+	id, name, url, events, secret, enabled, created_at, updated_at
+FROM
+	webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		pq.Array(&i.Events),
+		&i.Secret,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, event, payload, status, attempt_count, response_status, response_body, error, created_at, updated_at, leased_until, next_attempt_after, delivered_at
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	$2 :: int
+`
+
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.AttemptCount,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextAttemptAfter,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhookDeliveryByID = `-- name: GetWebhookDeliveryByID :one
+SELECT
+	id, webhook_id, event, payload, status, attempt_count, response_status, response_body, error, created_at, updated_at, leased_until, next_attempt_after, delivered_at
+FROM
+	webhook_deliveries
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDeliveryByID, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Status,
+		&i.AttemptCount,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LeasedUntil,
+		&i.NextAttemptAfter,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT
+	id, name, url, events, secret, enabled, created_at, updated_at
+FROM
+	webhooks
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			pq.Array(&i.Events),
+			&i.Secret,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWebhook = `-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (
+		id,
+		name,
+		url,
+		events,
+		secret,
+		enabled,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id, name, url, events, secret, enabled, created_at, updated_at
+`
+
+type InsertWebhookParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Url       string    `db:"url" json:"url"`
+	Events    []string  `db:"events" json:"events"`
+	Secret    string    `db:"secret" json:"secret"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		pq.Array(arg.Events),
+		arg.Secret,
+		arg.Enabled,
+		arg.CreatedAt,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		pq.Array(&i.Events),
+		&i.Secret,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $5) RETURNING id, webhook_id, event, payload, status, attempt_count, response_status, response_body, error, created_at, updated_at, leased_until, next_attempt_after, delivered_at
+`
+
+type InsertWebhookDeliveryParams struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	WebhookID uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event     string          `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhookDelivery,
+		arg.ID,
+		arg.WebhookID,
+		arg.Event,
+		arg.Payload,
+		arg.CreatedAt,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Status,
+		&i.AttemptCount,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LeasedUntil,
+		&i.NextAttemptAfter,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const markWebhookDeliveryFailed = `-- name: MarkWebhookDeliveryFailed :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = $1,
+	attempt_count = attempt_count + 1,
+	response_status = $2,
+	response_body = $3,
+	error = $4,
+	updated_at = $5,
+	leased_until = NULL,
+	next_attempt_after = $6
+WHERE
+	id = $7
+`
+
+type MarkWebhookDeliveryFailedParams struct {
+	Status           WebhookDeliveryStatus `db:"status" json:"status"`
+	ResponseStatus   sql.NullInt32         `db:"response_status" json:"response_status"`
+	ResponseBody     sql.NullString        `db:"response_body" json:"response_body"`
+	Error            sql.NullString        `db:"error" json:"error"`
+	UpdatedAt        time.Time             `db:"updated_at" json:"updated_at"`
+	NextAttemptAfter sql.NullTime          `db:"next_attempt_after" json:"next_attempt_after"`
+	ID               uuid.UUID             `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliveryFailed,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.NextAttemptAfter,
+		arg.ID,
+	)
+	return err
+}
+
+const markWebhookDeliverySucceeded = `-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = 'succeeded' :: webhook_delivery_status,
+	attempt_count = attempt_count + 1,
+	response_status = $1,
+	response_body = $2,
+	error = NULL,
+	updated_at = $3,
+	delivered_at = $3,
+	leased_until = NULL,
+	next_attempt_after = NULL
+WHERE
+	id = $4
+`
+
+type MarkWebhookDeliverySucceededParams struct {
+	ResponseStatus sql.NullInt32  `db:"response_status" json:"response_status"`
+	ResponseBody   sql.NullString `db:"response_body" json:"response_body"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at" json:"delivered_at"`
+	ID             uuid.UUID      `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDeliverySucceeded,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.DeliveredAt,
+		arg.ID,
 	)
-	return i, err
+	return err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
+const updateWebhookByID = `-- name: UpdateWebhookByID :one
 UPDATE
-	users
+	webhooks
 SET
-	status = $2,
-	updated_at = $3
+	name = $1,
+	url = $2,
+	events = $3,
+	secret = $4,
+	enabled = $5,
+	updated_at = $6
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	id = $7
+RETURNING id, name, url, events, secret, enabled, created_at, updated_at
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpdateWebhookByIDParams struct {
+	Name      string    `db:"name" json:"name"`
+	Url       string    `db:"url" json:"url"`
+	Events    []string  `db:"events" json:"events"`
+	Secret    string    `db:"secret" json:"secret"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) UpdateWebhookByID(ctx context.Context, arg UpdateWebhookByIDParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhookByID,
+		arg.Name,
+		arg.Url,
+		pq.Array(arg.Events),
+		arg.Secret,
+		arg.Enabled,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Webhook
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.Name,
+		&i.Url,
+		pq.Array(&i.Events),
+		&i.Secret,
+		&i.Enabled,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
 	)
 	return i, err
 }
@@ -9138,7 +12314,7 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 
 const getWorkspaceAgentAndLatestBuildByAuthToken = `-- name: GetWorkspaceAgentAndLatestBuildByAuthToken :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl,
 	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order,
 	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username
 FROM
@@ -9197,6 +12373,8 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.Workspace.DeletingAt,
 		&i.Workspace.AutomaticUpdates,
 		&i.Workspace.Favorite,
+		&i.Workspace.UserACL,
+		&i.Workspace.GroupACL,
 		&i.WorkspaceAgent.ID,
 		&i.WorkspaceAgent.CreatedAt,
 		&i.WorkspaceAgent.UpdatedAt,
@@ -11067,6 +14245,35 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 	return err
 }
 
+const deleteOldWorkspaceBuildProvisionerStates = `-- name: DeleteOldWorkspaceBuildProvisionerStates :exec
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = NULL
+WHERE
+	provisioner_state IS NOT NULL
+	AND id IN (
+		SELECT
+			id
+		FROM (
+			SELECT
+				id,
+				row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS rn
+			FROM
+				workspace_builds
+		) AS ranked
+		WHERE
+			ranked.rn > $1::integer
+	)
+`
+
+// Clears the provisioner state of all but the most recent @keep builds of
+// every workspace. The build rows themselves are retained.
+func (q *sqlQuerier) DeleteOldWorkspaceBuildProvisionerStates(ctx context.Context, keep int32) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceBuildProvisionerStates, keep)
+	return err
+}
+
 const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
 SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
@@ -11396,6 +14603,72 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 	return i, err
 }
 
+const getWorkspaceBuildStatesByWorkspaceID = `-- name: GetWorkspaceBuildStatesByWorkspaceID :many
+SELECT
+	wb.id,
+	wb.build_number,
+	wb.transition,
+	wb.initiator_id,
+	wb.created_at,
+	pj.job_status,
+	COALESCE(octet_length(wb.provisioner_state), 0)::integer AS state_size,
+	COALESCE(encode(sha256(wb.provisioner_state), 'hex'), '')::text AS state_hash
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+	ON wb.job_id = pj.id
+WHERE
+	wb.workspace_id = $1
+ORDER BY
+	wb.build_number DESC
+`
+
+type GetWorkspaceBuildStatesByWorkspaceIDRow struct {
+	ID          uuid.UUID            `db:"id" json:"id"`
+	BuildNumber int32                `db:"build_number" json:"build_number"`
+	Transition  WorkspaceTransition  `db:"transition" json:"transition"`
+	InitiatorID uuid.UUID            `db:"initiator_id" json:"initiator_id"`
+	CreatedAt   time.Time            `db:"created_at" json:"created_at"`
+	JobStatus   ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	StateSize   int32                `db:"state_size" json:"state_size"`
+	StateHash   string               `db:"state_hash" json:"state_hash"`
+}
+
+// Returns a summary of the provisioner state stored for every build of a
+// workspace. The state itself is not returned, as it can be large.
+func (q *sqlQuerier) GetWorkspaceBuildStatesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]GetWorkspaceBuildStatesByWorkspaceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildStatesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceBuildStatesByWorkspaceIDRow
+	for rows.Next() {
+		var i GetWorkspaceBuildStatesByWorkspaceIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.CreatedAt,
+			&i.JobStatus,
+			&i.StateSize,
+			&i.StateHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
 	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
@@ -11593,60 +14866,321 @@ WHERE
 	id = $1
 `
 
-type UpdateWorkspaceBuildCostByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+type UpdateWorkspaceBuildCostByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
+}
+
+const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
+UPDATE
+	workspace_builds
+SET
+	deadline = $1::timestamptz,
+	max_deadline = $2::timestamptz,
+	updated_at = $3::timestamptz
+WHERE id = $4::uuid
+`
+
+type UpdateWorkspaceBuildDeadlineByIDParams struct {
+	Deadline    time.Time `db:"deadline" json:"deadline"`
+	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
+		arg.Deadline,
+		arg.MaxDeadline,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = $1::bytea,
+	updated_at = $2::timestamptz
+WHERE id = $3::uuid
+`
+
+type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const getRunningWorkspaceBulkOperations = `-- name: GetRunningWorkspaceBulkOperations :many
+SELECT
+	id, initiator_id, action, query, concurrency, status, created_at, updated_at, completed_at
+FROM
+	workspace_bulk_operations
+WHERE
+	status = 'running'::workspace_bulk_operation_status
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetRunningWorkspaceBulkOperations(ctx context.Context) ([]WorkspaceBulkOperation, error) {
+	rows, err := q.db.QueryContext(ctx, getRunningWorkspaceBulkOperations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBulkOperation
+	for rows.Next() {
+		var i WorkspaceBulkOperation
+		if err := rows.Scan(
+			&i.ID,
+			&i.InitiatorID,
+			&i.Action,
+			&i.Query,
+			&i.Concurrency,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBulkOperationByID = `-- name: GetWorkspaceBulkOperationByID :one
+SELECT
+	id, initiator_id, action, query, concurrency, status, created_at, updated_at, completed_at
+FROM
+	workspace_bulk_operations
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceBulkOperationByID(ctx context.Context, id uuid.UUID) (WorkspaceBulkOperation, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBulkOperationByID, id)
+	var i WorkspaceBulkOperation
+	err := row.Scan(
+		&i.ID,
+		&i.InitiatorID,
+		&i.Action,
+		&i.Query,
+		&i.Concurrency,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceBulkOperationWorkspaces = `-- name: GetWorkspaceBulkOperationWorkspaces :many
+SELECT
+	workspace_bulk_operation_workspaces.operation_id, workspace_bulk_operation_workspaces.workspace_id, workspace_bulk_operation_workspaces.status, workspace_bulk_operation_workspaces.build_id, workspace_bulk_operation_workspaces.error, workspace_bulk_operation_workspaces.updated_at,
+	workspaces.name AS workspace_name,
+	users.username AS owner_name,
+	provisioner_jobs.job_status AS job_status,
+	provisioner_jobs.error AS job_error
+FROM
+	workspace_bulk_operation_workspaces
+	JOIN workspaces ON workspaces.id = workspace_bulk_operation_workspaces.workspace_id
+	JOIN users ON users.id = workspaces.owner_id
+	LEFT JOIN workspace_builds ON workspace_builds.id = workspace_bulk_operation_workspaces.build_id
+	LEFT JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_bulk_operation_workspaces.operation_id = $1
+ORDER BY
+	users.username ASC, workspaces.name ASC
+`
+
+type GetWorkspaceBulkOperationWorkspacesRow struct {
+	OperationID   uuid.UUID                 `db:"operation_id" json:"operation_id"`
+	WorkspaceID   uuid.UUID                 `db:"workspace_id" json:"workspace_id"`
+	Status        WorkspaceBulkResultStatus `db:"status" json:"status"`
+	BuildID       uuid.NullUUID             `db:"build_id" json:"build_id"`
+	Error         string                    `db:"error" json:"error"`
+	UpdatedAt     time.Time                 `db:"updated_at" json:"updated_at"`
+	WorkspaceName string                    `db:"workspace_name" json:"workspace_name"`
+	OwnerName     string                    `db:"owner_name" json:"owner_name"`
+	JobStatus     NullProvisionerJobStatus  `db:"job_status" json:"job_status"`
+	JobError      sql.NullString            `db:"job_error" json:"job_error"`
+}
+
+// The status of the job of the build started for a workspace tells the
+// runner when the build has finished.
+func (q *sqlQuerier) GetWorkspaceBulkOperationWorkspaces(ctx context.Context, operationID uuid.UUID) ([]GetWorkspaceBulkOperationWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBulkOperationWorkspaces, operationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceBulkOperationWorkspacesRow
+	for rows.Next() {
+		var i GetWorkspaceBulkOperationWorkspacesRow
+		if err := rows.Scan(
+			&i.OperationID,
+			&i.WorkspaceID,
+			&i.Status,
+			&i.BuildID,
+			&i.Error,
+			&i.UpdatedAt,
+			&i.WorkspaceName,
+			&i.OwnerName,
+			&i.JobStatus,
+			&i.JobError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceBulkOperation = `-- name: InsertWorkspaceBulkOperation :one
+INSERT INTO
+	workspace_bulk_operations (id, initiator_id, action, query, concurrency, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $6)
+RETURNING id, initiator_id, action, query, concurrency, status, created_at, updated_at, completed_at
+`
+
+type InsertWorkspaceBulkOperationParams struct {
+	ID          uuid.UUID           `db:"id" json:"id"`
+	InitiatorID uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	Action      WorkspaceBulkAction `db:"action" json:"action"`
+	Query       string              `db:"query" json:"query"`
+	Concurrency int32               `db:"concurrency" json:"concurrency"`
+	CreatedAt   time.Time           `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBulkOperation(ctx context.Context, arg InsertWorkspaceBulkOperationParams) (WorkspaceBulkOperation, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBulkOperation,
+		arg.ID,
+		arg.InitiatorID,
+		arg.Action,
+		arg.Query,
+		arg.Concurrency,
+		arg.CreatedAt,
+	)
+	var i WorkspaceBulkOperation
+	err := row.Scan(
+		&i.ID,
+		&i.InitiatorID,
+		&i.Action,
+		&i.Query,
+		&i.Concurrency,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceBulkOperationWorkspaces = `-- name: InsertWorkspaceBulkOperationWorkspaces :exec
+INSERT INTO
+	workspace_bulk_operation_workspaces (operation_id, workspace_id, updated_at)
+SELECT
+	$1 :: uuid,
+	unnest($2 :: uuid[]),
+	$3 :: timestamptz
+`
+
+type InsertWorkspaceBulkOperationWorkspacesParams struct {
+	OperationID  uuid.UUID   `db:"operation_id" json:"operation_id"`
+	WorkspaceIds []uuid.UUID `db:"workspace_ids" json:"workspace_ids"`
+	UpdatedAt    time.Time   `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+func (q *sqlQuerier) InsertWorkspaceBulkOperationWorkspaces(ctx context.Context, arg InsertWorkspaceBulkOperationWorkspacesParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBulkOperationWorkspaces, arg.OperationID, pq.Array(arg.WorkspaceIds), arg.UpdatedAt)
 	return err
 }
 
-const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
+const updateWorkspaceBulkOperationStatus = `-- name: UpdateWorkspaceBulkOperationStatus :exec
 UPDATE
-	workspace_builds
+	workspace_bulk_operations
 SET
-	deadline = $1::timestamptz,
-	max_deadline = $2::timestamptz,
-	updated_at = $3::timestamptz
-WHERE id = $4::uuid
+	status = $1,
+	updated_at = $2,
+	completed_at = $3
+WHERE
+	id = $4
 `
 
-type UpdateWorkspaceBuildDeadlineByIDParams struct {
-	Deadline    time.Time `db:"deadline" json:"deadline"`
-	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	ID          uuid.UUID `db:"id" json:"id"`
+type UpdateWorkspaceBulkOperationStatusParams struct {
+	Status      WorkspaceBulkOperationStatus `db:"status" json:"status"`
+	UpdatedAt   time.Time                    `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime                 `db:"completed_at" json:"completed_at"`
+	ID          uuid.UUID                    `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
-		arg.Deadline,
-		arg.MaxDeadline,
+func (q *sqlQuerier) UpdateWorkspaceBulkOperationStatus(ctx context.Context, arg UpdateWorkspaceBulkOperationStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBulkOperationStatus,
+		arg.Status,
 		arg.UpdatedAt,
+		arg.CompletedAt,
 		arg.ID,
 	)
 	return err
 }
 
-const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
+const updateWorkspaceBulkOperationWorkspace = `-- name: UpdateWorkspaceBulkOperationWorkspace :exec
 UPDATE
-	workspace_builds
+	workspace_bulk_operation_workspaces
 SET
-	provisioner_state = $1::bytea,
-	updated_at = $2::timestamptz
-WHERE id = $3::uuid
+	status = $1,
+	build_id = $2,
+	error = $3,
+	updated_at = $4
+WHERE
+	operation_id = $5
+	AND workspace_id = $6
 `
 
-type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type UpdateWorkspaceBulkOperationWorkspaceParams struct {
+	Status      WorkspaceBulkResultStatus `db:"status" json:"status"`
+	BuildID     uuid.NullUUID             `db:"build_id" json:"build_id"`
+	Error       string                    `db:"error" json:"error"`
+	UpdatedAt   time.Time                 `db:"updated_at" json:"updated_at"`
+	OperationID uuid.UUID                 `db:"operation_id" json:"operation_id"`
+	WorkspaceID uuid.UUID                 `db:"workspace_id" json:"workspace_id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+func (q *sqlQuerier) UpdateWorkspaceBulkOperationWorkspace(ctx context.Context, arg UpdateWorkspaceBulkOperationWorkspaceParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBulkOperationWorkspace,
+		arg.Status,
+		arg.BuildID,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.OperationID,
+		arg.WorkspaceID,
+	)
 	return err
 }
 
@@ -12091,7 +15625,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl,
 	templates.name as template_name
 FROM
 	workspaces
@@ -12146,6 +15680,8 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.Workspace.DeletingAt,
 		&i.Workspace.AutomaticUpdates,
 		&i.Workspace.Favorite,
+		&i.Workspace.UserACL,
+		&i.Workspace.GroupACL,
 		&i.TemplateName,
 	)
 	return i, err
@@ -12153,7 +15689,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -12181,13 +15717,15 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -12222,13 +15760,15 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -12282,6 +15822,8 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -12333,7 +15875,7 @@ SELECT
 ),
 filtered_workspaces AS (
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl,
 	COALESCE(template.name, 'unknown') as template_name,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
@@ -12381,7 +15923,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, max_workspaces
 	FROM
 		templates
 	WHERE
@@ -12440,9 +15982,15 @@ WHERE
 			workspaces.owner_id = $5
 		ELSE true
 	END
+	-- Filter by organization_id
+	AND CASE
+		WHEN $6 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces.organization_id = $6
+		ELSE true
+	END
 	-- Filter by build parameter
    	-- @has_param will match any build that includes the parameter.
-	AND CASE WHEN array_length($6 :: text[], 1) > 0  THEN
+	AND CASE WHEN array_length($7 :: text[], 1) > 0  THEN
 		EXISTS (
 			SELECT
 				1
@@ -12451,7 +15999,7 @@ WHERE
 			WHERE
 				workspace_build_parameters.workspace_build_id = latest_build.id AND
 				-- ILIKE is case insensitive
-				workspace_build_parameters.name ILIKE ANY($6)
+				workspace_build_parameters.name ILIKE ANY($7)
 		)
 		ELSE true
 	END
@@ -12476,40 +16024,40 @@ WHERE
 
 	-- Filter by owner_name
 	AND CASE
-		WHEN $7 :: text != '' THEN
-			workspaces.owner_id = (SELECT id FROM users WHERE lower(username) = lower($7) AND deleted = false)
+		WHEN $8 :: text != '' THEN
+			workspaces.owner_id = (SELECT id FROM users WHERE lower(username) = lower($8) AND deleted = false)
 		ELSE true
 	END
 	-- Filter by template_name
 	-- There can be more than 1 template with the same name across organizations.
 	-- Use the organization filter to restrict to 1 org if needed.
 	AND CASE
-		WHEN $8 :: text != '' THEN
-			workspaces.template_id = ANY(SELECT id FROM templates WHERE lower(name) = lower($8) AND deleted = false)
+		WHEN $9 :: text != '' THEN
+			workspaces.template_id = ANY(SELECT id FROM templates WHERE lower(name) = lower($9) AND deleted = false)
 		ELSE true
 	END
 	-- Filter by template_ids
 	AND CASE
-		WHEN array_length($9 :: uuid[], 1) > 0 THEN
-			workspaces.template_id = ANY($9)
+		WHEN array_length($10 :: uuid[], 1) > 0 THEN
+			workspaces.template_id = ANY($10)
 		ELSE true
 	END
   	-- Filter by workspace_ids
   	AND CASE
-		  WHEN array_length($10 :: uuid[], 1) > 0 THEN
-			  workspaces.id = ANY($10)
+		  WHEN array_length($11 :: uuid[], 1) > 0 THEN
+			  workspaces.id = ANY($11)
 		  ELSE true
 	END
 	-- Filter by name, matching on substring
 	AND CASE
-		WHEN $11 :: text != '' THEN
-			workspaces.name ILIKE '%' || $11 || '%'
+		WHEN $12 :: text != '' THEN
+			workspaces.name ILIKE '%' || $12 || '%'
 		ELSE true
 	END
 	-- Filter by agent status
 	-- has-agent: is only applicable for workspaces in "start" transition. Stopped and deleted workspaces don't have agents.
 	AND CASE
-		WHEN $12 :: text != '' THEN
+		WHEN $13 :: text != '' THEN
 			(
 				SELECT COUNT(*)
 				FROM
@@ -12521,7 +16069,7 @@ WHERE
 				WHERE
 					workspace_resources.job_id = latest_build.provisioner_job_id AND
 					latest_build.transition = 'start'::workspace_transition AND
-					$12 = (
+					$13 = (
 						CASE
 							WHEN workspace_agents.first_connected_at IS NULL THEN
 								CASE
@@ -12532,7 +16080,7 @@ WHERE
 								END
 							WHEN workspace_agents.disconnected_at > workspace_agents.last_connected_at THEN
 								'disconnected'
-							WHEN NOW() - workspace_agents.last_connected_at > INTERVAL '1 second' * $13 :: bigint THEN
+							WHEN NOW() - workspace_agents.last_connected_at > INTERVAL '1 second' * $14 :: bigint THEN
 								'disconnected'
 							WHEN workspace_agents.last_connected_at IS NOT NULL THEN
 								'connected'
@@ -12545,36 +16093,75 @@ WHERE
 	END
 	-- Filter by dormant workspaces.
 	AND CASE
-		WHEN $14 :: boolean != 'false' THEN
+		WHEN $15 :: boolean != 'false' THEN
 			dormant_at IS NOT NULL
 		ELSE true
 	END
 	-- Filter by last_used
 	AND CASE
-		  WHEN $15 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
-				  workspaces.last_used_at <= $15
+		  WHEN $16 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+				  workspaces.last_used_at <= $16
 		  ELSE true
 	END
 	AND CASE
-		  WHEN $16 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
-				  workspaces.last_used_at >= $16
+		  WHEN $17 :: timestamp with time zone > '0001-01-01 00:00:00Z' THEN
+				  workspaces.last_used_at >= $17
 		  ELSE true
 	END
   	AND CASE
-		  WHEN $17 :: boolean IS NOT NULL THEN
-			  (latest_build.template_version_id = template.active_version_id) = $17 :: boolean
+		  WHEN $18 :: boolean IS NOT NULL THEN
+			  -- Workspaces use the version of the release channel of their
+			  -- owner, see GetTemplateActiveVersionIDForUser.
+			  (latest_build.template_version_id = COALESCE(
+				  (
+					  SELECT
+						  template_release_channels.template_version_id
+					  FROM
+						  template_release_channels
+					  WHERE
+						  template_release_channels.template_id = workspaces.template_id
+						  AND (
+							  workspaces.owner_id = ANY(template_release_channels.user_ids)
+							  OR template_release_channels.group_ids && ARRAY(
+								  SELECT group_id FROM group_members WHERE group_members.user_id = workspaces.owner_id
+								  UNION
+								  SELECT organization_id FROM organization_members WHERE organization_members.user_id = workspaces.owner_id
+							  )
+						  )
+					  ORDER BY
+						  workspaces.owner_id = ANY(template_release_channels.user_ids) DESC,
+						  template_release_channels.name ASC
+					  LIMIT 1
+				  ),
+				  template.active_version_id
+			  )) = $18 :: boolean
 		  ELSE true
 	END
+	-- Filter by workspaces shared with the requester, directly or through a
+	-- group.
+	AND CASE
+		WHEN $19 :: boolean THEN
+			workspaces.owner_id != $20 AND (
+				workspaces.user_acl ? ($20 :: text)
+				OR workspaces.group_acl ?| ARRAY(
+					SELECT group_id :: text FROM group_members WHERE user_id = $20
+					UNION
+					-- The "Everyone" group of an organization has the organization's ID.
+					SELECT organization_id :: text FROM organization_members WHERE user_id = $20
+				)
+			)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
 	SELECT
-		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.template_name, fw.template_version_id, fw.template_version_name, fw.username, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status
+		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.user_acl, fw.group_acl, fw.template_name, fw.template_version_id, fw.template_version_name, fw.username, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status
 	FROM
 		filtered_workspaces fw
 	ORDER BY
 		-- To ensure that 'favorite' workspaces show up first in the list only for their owner.
-		CASE WHEN owner_id = $18 AND favorite THEN 0 ELSE 1 END ASC,
+		CASE WHEN owner_id = $20 AND favorite THEN 0 ELSE 1 END ASC,
 		(latest_build_completed_at IS NOT NULL AND
 			latest_build_canceled_at IS NULL AND
 			latest_build_error IS NULL AND
@@ -12583,14 +16170,14 @@ WHERE
 		LOWER(name) ASC
 	LIMIT
 		CASE
-			WHEN $20 :: integer > 0 THEN
-				$20
+			WHEN $22 :: integer > 0 THEN
+				$22
 		END
 	OFFSET
-		$19
+		$21
 ), filtered_workspaces_order_with_summary AS (
 	SELECT
-		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.template_name, fwo.template_version_id, fwo.template_version_name, fwo.username, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status
+		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.user_acl, fwo.group_acl, fwo.template_name, fwo.template_version_id, fwo.template_version_name, fwo.username, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status
 	FROM
 		filtered_workspaces_order fwo
 	-- Return a technical summary row with total count of workspaces.
@@ -12612,6 +16199,8 @@ WHERE
 		'0001-01-01 00:00:00+00'::timestamptz, -- deleting_at
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
+		'{}'::jsonb, -- user_acl
+		'{}'::jsonb, -- group_acl
 		-- Extra columns added to ` + "`" + `filtered_workspaces` + "`" + `
 		'', -- template_name
 		'00000000-0000-0000-0000-000000000000'::uuid, -- template_version_id
@@ -12623,7 +16212,7 @@ WHERE
 		'start'::workspace_transition, -- latest_build_transition
 		'unknown'::provisioner_job_status -- latest_build_status
 	WHERE
-		$21 :: boolean = true
+		$23 :: boolean = true
 ), total_count AS (
 	SELECT
 		count(*) AS count
@@ -12631,7 +16220,7 @@ WHERE
 		filtered_workspaces
 )
 SELECT
-	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.template_name, fwos.template_version_id, fwos.template_version_name, fwos.username, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status,
+	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.user_acl, fwos.group_acl, fwos.template_name, fwos.template_version_id, fwos.template_version_name, fwos.username, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status,
 	tc.count
 FROM
 	filtered_workspaces_order_with_summary fwos
@@ -12645,6 +16234,7 @@ type GetWorkspacesParams struct {
 	Deleted                               bool         `db:"deleted" json:"deleted"`
 	Status                                string       `db:"status" json:"status"`
 	OwnerID                               uuid.UUID    `db:"owner_id" json:"owner_id"`
+	OrganizationID                        uuid.UUID    `db:"organization_id" json:"organization_id"`
 	HasParam                              []string     `db:"has_param" json:"has_param"`
 	OwnerUsername                         string       `db:"owner_username" json:"owner_username"`
 	TemplateName                          string       `db:"template_name" json:"template_name"`
@@ -12657,6 +16247,7 @@ type GetWorkspacesParams struct {
 	LastUsedBefore                        time.Time    `db:"last_used_before" json:"last_used_before"`
 	LastUsedAfter                         time.Time    `db:"last_used_after" json:"last_used_after"`
 	UsingActive                           sql.NullBool `db:"using_active" json:"using_active"`
+	Shared                                bool         `db:"shared" json:"shared"`
 	RequesterID                           uuid.UUID    `db:"requester_id" json:"requester_id"`
 	Offset                                int32        `db:"offset_" json:"offset_"`
 	Limit                                 int32        `db:"limit_" json:"limit_"`
@@ -12679,6 +16270,8 @@ type GetWorkspacesRow struct {
 	DeletingAt             sql.NullTime         `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates       AutomaticUpdates     `db:"automatic_updates" json:"automatic_updates"`
 	Favorite               bool                 `db:"favorite" json:"favorite"`
+	UserACL                WorkspaceACL         `db:"user_acl" json:"user_acl"`
+	GroupACL               WorkspaceACL         `db:"group_acl" json:"group_acl"`
 	TemplateName           string               `db:"template_name" json:"template_name"`
 	TemplateVersionID      uuid.UUID            `db:"template_version_id" json:"template_version_id"`
 	TemplateVersionName    sql.NullString       `db:"template_version_name" json:"template_version_name"`
@@ -12701,6 +16294,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.Deleted,
 		arg.Status,
 		arg.OwnerID,
+		arg.OrganizationID,
 		pq.Array(arg.HasParam),
 		arg.OwnerUsername,
 		arg.TemplateName,
@@ -12713,6 +16307,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
 		arg.UsingActive,
+		arg.Shared,
 		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
@@ -12741,6 +16336,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
 			&i.Favorite,
+			&i.UserACL,
+			&i.GroupACL,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -12767,7 +16364,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 
 const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl
 FROM
 	workspaces
 LEFT JOIN
@@ -12856,6 +16453,8 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
 			&i.Favorite,
+			&i.UserACL,
+			&i.GroupACL,
 		); err != nil {
 			return nil, err
 		}
@@ -12886,7 +16485,7 @@ INSERT INTO
 		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, user_acl, group_acl
 `
 
 type InsertWorkspaceParams struct {
@@ -12934,6 +16533,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -12973,7 +16574,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, user_acl, group_acl
 `
 
 type UpdateWorkspaceParams struct {
@@ -13000,10 +16601,33 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
+const updateWorkspaceACLByID = `-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = $1,
+	group_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceACLByIDParams struct {
+	UserACL  WorkspaceACL `db:"user_acl" json:"user_acl"`
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
+	ID       uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceACLByID, arg.UserACL, arg.GroupACL, arg.ID)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -13086,7 +16710,7 @@ WHERE
     workspaces.id = $1
     AND templates.id = workspaces.template_id
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.user_acl, workspaces.group_acl
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -13113,6 +16737,8 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
 		&i.Favorite,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -13136,6 +16762,29 @@ func (q *sqlQuerier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWo
 	return err
 }
 
+const updateWorkspaceOwnerByID = `-- name: UpdateWorkspaceOwnerByID :exec
+UPDATE
+	workspaces
+SET
+	owner_id = $1,
+	favorite = false,
+	updated_at = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceOwnerByIDParams struct {
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+// Favorites belong to the previous owner, so they are reset.
+func (q *sqlQuerier) UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceOwnerByID, arg.OwnerID, arg.UpdatedAt, arg.ID)
+	return err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -13293,3 +16942,239 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const deleteWorkspaceSnapshotByID = `-- name: DeleteWorkspaceSnapshotByID :exec
+DELETE FROM
+	workspace_snapshots
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceSnapshotByID, id)
+	return err
+}
+
+const getWorkspaceSnapshotByBuildID = `-- name: GetWorkspaceSnapshotByBuildID :one
+SELECT
+	id, workspace_id, name, build_id, artifacts, created_at
+FROM
+	workspace_snapshots
+WHERE
+	build_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByBuildID(ctx context.Context, buildID uuid.UUID) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByBuildID, buildID)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.BuildID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotByID = `-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	id, workspace_id, name, build_id, artifacts, created_at
+FROM
+	workspace_snapshots
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByID, id)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.BuildID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotByWorkspaceIDAndName = `-- name: GetWorkspaceSnapshotByWorkspaceIDAndName :one
+SELECT
+	id, workspace_id, name, build_id, artifacts, created_at
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+	AND name = $2
+`
+
+type GetWorkspaceSnapshotByWorkspaceIDAndNameParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByWorkspaceIDAndName, arg.WorkspaceID, arg.Name)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.BuildID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotRestoreByWorkspaceID = `-- name: GetWorkspaceSnapshotRestoreByWorkspaceID :one
+SELECT
+	workspace_id, snapshot_id, artifacts, created_at
+FROM
+	workspace_snapshot_restores
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotRestoreByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceSnapshotRestore, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotRestoreByWorkspaceID, workspaceID)
+	var i WorkspaceSnapshotRestore
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.SnapshotID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotsByWorkspaceID = `-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	id, workspace_id, name, build_id, artifacts, created_at
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSnapshotsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSnapshot
+	for rows.Next() {
+		var i WorkspaceSnapshot
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.Name,
+			&i.BuildID,
+			&i.Artifacts,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSnapshot = `-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (id, workspace_id, name, build_id, created_at)
+VALUES
+	($1, $2, $3, $4, $5)
+RETURNING id, workspace_id, name, build_id, artifacts, created_at
+`
+
+type InsertWorkspaceSnapshotParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	BuildID     uuid.UUID `db:"build_id" json:"build_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSnapshot,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.BuildID,
+		arg.CreatedAt,
+	)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.BuildID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceSnapshotRestore = `-- name: InsertWorkspaceSnapshotRestore :one
+INSERT INTO
+	workspace_snapshot_restores (workspace_id, snapshot_id, artifacts, created_at)
+VALUES
+	($1, $2, $3 :: jsonb, $4)
+RETURNING workspace_id, snapshot_id, artifacts, created_at
+`
+
+type InsertWorkspaceSnapshotRestoreParams struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	SnapshotID  uuid.NullUUID   `db:"snapshot_id" json:"snapshot_id"`
+	Artifacts   json.RawMessage `db:"artifacts" json:"artifacts"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSnapshotRestore(ctx context.Context, arg InsertWorkspaceSnapshotRestoreParams) (WorkspaceSnapshotRestore, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSnapshotRestore,
+		arg.WorkspaceID,
+		arg.SnapshotID,
+		arg.Artifacts,
+		arg.CreatedAt,
+	)
+	var i WorkspaceSnapshotRestore
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.SnapshotID,
+		&i.Artifacts,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceSnapshotArtifactsByID = `-- name: UpdateWorkspaceSnapshotArtifactsByID :exec
+UPDATE
+	workspace_snapshots
+SET
+	artifacts = $1 :: jsonb
+WHERE
+	id = $2
+`
+
+type UpdateWorkspaceSnapshotArtifactsByIDParams struct {
+	Artifacts json.RawMessage `db:"artifacts" json:"artifacts"`
+	ID        uuid.UUID       `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSnapshotArtifactsByID(ctx context.Context, arg UpdateWorkspaceSnapshotArtifactsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSnapshotArtifactsByID, arg.Artifacts, arg.ID)
+	return err
+}
@@ -30,6 +30,32 @@ SELECT * FROM api_keys WHERE login_type = $1;
 -- name: GetAPIKeysByUserID :many
 SELECT * FROM api_keys WHERE login_type = $1 AND user_id = $2;
 
+-- name: GetImpersonationAPIKeysByUserID :many
+-- Returns the unexpired keys other users have minted to impersonate the user.
+SELECT
+	*
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	impersonator_id IS NOT NULL AND
+	expires_at > NOW()
+ORDER BY
+	created_at DESC;
+
+-- name: GetActiveAPIKeysByUserID :many
+-- Returns the user's unexpired keys, such as browser sessions, CLI logins and
+-- tokens, most recently used first.
+SELECT
+	*
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	expires_at > NOW()
+ORDER BY
+	last_used DESC;
+
 -- name: InsertAPIKey :one
 INSERT INTO
 	api_keys (
@@ -44,7 +70,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		impersonator_id,
+		user_agent
 	)
 VALUES
 	(@id,
@@ -53,7 +81,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name, @impersonator_id, @user_agent) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -83,3 +111,14 @@ DELETE FROM
 	api_keys
 WHERE
 	user_id = $1;
+
+-- name: DeleteOtherAPIKeysByUserID :many
+-- Deletes all keys of the user except except_id, returning the deleted keys.
+-- Tokens are only deleted if include_tokens is set.
+DELETE FROM
+	api_keys
+WHERE
+	user_id = @user_id AND
+	id != @except_id AND
+	(@include_tokens :: boolean OR login_type != 'token'::login_type)
+RETURNING *;
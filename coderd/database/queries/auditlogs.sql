@@ -9,10 +9,14 @@ SELECT
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.is_service_account AS user_is_service_account,
+    impersonators.username AS impersonator_username,
+    impersonators.avatar_url AS impersonator_avatar_url,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
     LEFT JOIN users ON audit_logs.user_id = users.id
+    LEFT JOIN users AS impersonators ON audit_logs.impersonator_id = impersonators.id
     LEFT JOIN
         -- First join on workspaces to get the initial workspace create
         -- to workspace build 1 id. This is because the first create is
@@ -98,6 +102,12 @@ WHERE
             workspace_builds.reason::text = @build_reason
         ELSE true
     END
+	-- Filter by organization_id
+	AND CASE
+		WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			audit_logs.organization_id = @organization_id
+		ELSE true
+	END
 ORDER BY
     "time" DESC
 LIMIT
@@ -122,7 +132,8 @@ INSERT INTO
         status_code,
         additional_fields,
         request_id,
-        resource_icon
+        resource_icon,
+        impersonator_id
     )
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING *;
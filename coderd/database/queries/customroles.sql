@@ -0,0 +1,76 @@
+-- name: GetCustomRoles :many
+SELECT
+	*
+FROM
+	custom_roles
+ORDER BY
+	organization_id NULLS FIRST, name;
+
+-- name: GetCustomRoleByName :one
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	name = @name
+	AND organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id') :: uuid;
+
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $8) RETURNING *;
+
+-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	display_name = @display_name,
+	site_permissions = @site_permissions,
+	org_permissions = @org_permissions,
+	user_permissions = @user_permissions,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteCustomRole :exec
+-- Deleting a role also unassigns it, so users are never left with roles that
+-- cannot be expanded.
+WITH deleted AS (
+	DELETE FROM
+		custom_roles
+	WHERE
+		id = @id
+	RETURNING
+		name, organization_id
+), unassigned_users AS (
+	UPDATE
+		users
+	SET
+		rbac_roles = array_remove(users.rbac_roles, deleted.name)
+	FROM
+		deleted
+	WHERE
+		deleted.organization_id IS NULL
+		AND deleted.name = ANY(users.rbac_roles)
+)
+UPDATE
+	organization_members
+SET
+	roles = array_remove(organization_members.roles, deleted.name || ':' || deleted.organization_id :: text)
+FROM
+	deleted
+WHERE
+	organization_members.organization_id = deleted.organization_id
+	AND (deleted.name || ':' || deleted.organization_id :: text) = ANY(organization_members.roles);
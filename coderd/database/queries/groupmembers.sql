@@ -24,6 +24,8 @@ AND
 	users.deleted = 'false';
 
 -- InsertUserGroupsByName adds a user to all provided groups, if they exist.
+-- Groups provisioned over SCIM are managed by the identity provider and are
+-- skipped.
 -- name: InsertUserGroupsByName :exec
 WITH groups AS (
     SELECT
@@ -32,7 +34,8 @@ WITH groups AS (
         groups
     WHERE
         groups.organization_id = @organization_id AND
-        groups.name = ANY(@group_names :: text [])
+        groups.name = ANY(@group_names :: text []) AND
+        groups.source != 'scim'
 )
 INSERT INTO
     group_members (user_id, group_id)
@@ -42,11 +45,16 @@ SELECT
 FROM
     groups;
 
+-- RemoveUserFromAllGroups removes a user from every group except the ones
+-- provisioned over SCIM, which are managed by the identity provider.
 -- name: RemoveUserFromAllGroups :exec
 DELETE FROM
 	group_members
 WHERE
-	user_id = @user_id;
+	user_id = @user_id AND
+	group_id NOT IN (
+		SELECT id FROM groups WHERE source = 'scim'
+	);
 
 -- name: InsertGroupMember :exec
 INSERT INTO
@@ -60,10 +60,11 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	max_workspaces_per_user
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
 
 -- name: InsertMissingGroups :many
 -- Inserts any group by name that does not exist. All new groups are given
@@ -106,7 +107,8 @@ SET
 	name = @name,
 	display_name = @display_name,
 	avatar_url = @avatar_url,
-	quota_allowance = @quota_allowance
+	quota_allowance = @quota_allowance,
+	max_workspaces_per_user = @max_workspaces_per_user
 WHERE
 	id = @id
 RETURNING *;
@@ -0,0 +1,123 @@
+-- name: EnqueueNotificationMessage :one
+INSERT INTO
+	notification_messages (
+		id,
+		user_id,
+		template,
+		method,
+		payload,
+		created_by,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING *;
+
+-- Acquires a batch of messages that are waiting to be delivered with the
+-- given method: new messages, failed messages whose retry is due, and
+-- leased messages whose lease expired without an outcome being recorded.
+--
+-- SKIP LOCKED is used to jump over locked rows. This prevents
+-- multiple coderd replicas from acquiring the same messages.
+-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	status = 'leased' :: notification_message_status,
+	updated_at = @now,
+	leased_until = @leased_until
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.method = @method
+			AND nested.attempt_count < @max_attempt_count :: int
+			AND (
+				nested.status = 'pending' :: notification_message_status
+				OR (nested.status = 'temporary_failure' :: notification_message_status AND nested.next_retry_after <= @now)
+				OR (nested.status = 'leased' :: notification_message_status AND nested.leased_until <= @now)
+			)
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			@count :: int
+	) RETURNING *;
+
+-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent' :: notification_message_status,
+	status_reason = NULL,
+	attempt_count = attempt_count + 1,
+	updated_at = @sent_at,
+	sent_at = @sent_at,
+	leased_until = NULL,
+	next_retry_after = NULL
+WHERE
+	id = @id;
+
+-- name: MarkNotificationMessageFailed :exec
+UPDATE
+	notification_messages
+SET
+	status = @status,
+	status_reason = @status_reason,
+	attempt_count = attempt_count + 1,
+	updated_at = @updated_at,
+	leased_until = NULL,
+	next_retry_after = @next_retry_after
+WHERE
+	id = @id;
+
+-- name: GetNotificationMessagesByStatus :many
+SELECT
+	*
+FROM
+	notification_messages
+WHERE
+	status = @status
+ORDER BY
+	created_at
+LIMIT
+	@limit_opt :: int;
+
+-- name: DeleteOldNotificationMessages :exec
+-- Delete messages that reached a final state more than a week ago.
+DELETE FROM
+	notification_messages
+WHERE
+	status IN ('sent' :: notification_message_status, 'permanent_failure' :: notification_message_status)
+	AND updated_at < (NOW() - INTERVAL '7 days');
+
+-- name: GetUserNotificationPreferences :many
+SELECT
+	*
+FROM
+	notification_preferences
+WHERE
+	user_id = @user_id
+ORDER BY
+	template;
+
+-- name: UpsertUserNotificationPreference :one
+INSERT INTO
+	notification_preferences (
+		user_id,
+		template,
+		disabled,
+		created_at,
+		updated_at
+	)
+VALUES
+	(@user_id, @template, @disabled, @updated_at, @updated_at)
+ON CONFLICT (user_id, template) DO UPDATE
+SET
+	disabled = @disabled,
+	updated_at = @updated_at
+RETURNING *;
@@ -0,0 +1,50 @@
+-- name: GetOIDCSyncRules :many
+SELECT
+	*
+FROM
+	oidc_sync_rules
+ORDER BY
+	created_at, id;
+
+-- name: GetOIDCSyncRuleByID :one
+SELECT
+	*
+FROM
+	oidc_sync_rules
+WHERE
+	id = $1;
+
+-- name: InsertOIDCSyncRule :one
+INSERT INTO
+	oidc_sync_rules (
+		id,
+		claim,
+		pattern,
+		site_roles,
+		organization_id,
+		organization_roles,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING *;
+
+-- name: UpdateOIDCSyncRule :one
+UPDATE
+	oidc_sync_rules
+SET
+	claim = @claim,
+	pattern = @pattern,
+	site_roles = @site_roles,
+	organization_id = @organization_id,
+	organization_roles = @organization_roles,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteOIDCSyncRule :exec
+DELETE FROM
+	oidc_sync_rules
+WHERE
+	id = $1;
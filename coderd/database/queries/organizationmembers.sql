@@ -21,6 +21,21 @@ INSERT INTO
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
 
+-- name: DeleteOrganizationMember :exec
+-- Removes the user from the organization and from the groups of the
+-- organization.
+WITH deleted_group_members AS (
+	DELETE FROM
+		group_members
+	WHERE
+		group_members.user_id = @user_id
+		AND group_members.group_id IN (SELECT id FROM groups WHERE organization_id = @organization_id)
+)
+DELETE FROM
+	organization_members
+WHERE
+	organization_members.organization_id = @organization_id
+	AND organization_members.user_id = @user_id;
 
 -- name: GetOrganizationMembershipsByUserID :many
 SELECT
@@ -0,0 +1,121 @@
+-- name: InsertWorkspacePrebuild :exec
+INSERT INTO
+	workspace_prebuilds (workspace_id, template_version_preset_id, created_at)
+VALUES
+	($1, $2, $3);
+
+-- name: GetPrebuildPresets :many
+-- Returns the presets of the active version of every template that want
+-- workspaces built ahead of time.
+SELECT
+	template_version_presets.*,
+	templates.id AS template_id,
+	templates.organization_id
+FROM
+	template_version_presets
+	INNER JOIN templates ON templates.active_version_id = template_version_presets.template_version_id
+WHERE
+	templates.deleted = false
+	AND templates.deprecated = ''
+	AND template_version_presets.desired_prebuild_instances > 0
+ORDER BY
+	templates.id, template_version_presets.name;
+
+-- name: GetWorkspacePrebuilds :many
+-- Returns every prebuild that has not been claimed yet along with the
+-- status of its latest build.
+SELECT
+	workspace_prebuilds.workspace_id,
+	workspace_prebuilds.template_version_preset_id,
+	workspace_prebuilds.created_at,
+	workspaces.organization_id,
+	workspace_builds.template_version_id,
+	workspace_builds.transition,
+	provisioner_jobs.job_status,
+	provisioner_jobs.completed_at
+FROM
+	workspace_prebuilds
+	INNER JOIN workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+	INNER JOIN workspace_builds ON workspace_builds.workspace_id = workspaces.id
+	INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspaces.deleted = false
+	AND workspace_builds.build_number = (
+		SELECT
+			MAX(latest.build_number)
+		FROM
+			workspace_builds AS latest
+		WHERE
+			latest.workspace_id = workspaces.id
+	)
+ORDER BY
+	workspace_prebuilds.created_at;
+
+-- name: ClaimWorkspacePrebuild :one
+-- Transfers the oldest ready prebuild of a preset to a new owner. A prebuild
+-- is ready once its latest build started it successfully.
+WITH claimed AS (
+	DELETE FROM
+		workspace_prebuilds
+	WHERE
+		workspace_id = (
+			SELECT
+				workspace_prebuilds.workspace_id
+			FROM
+				workspace_prebuilds
+				INNER JOIN workspaces ON workspaces.id = workspace_prebuilds.workspace_id
+				INNER JOIN workspace_builds ON workspace_builds.workspace_id = workspaces.id
+				INNER JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+			WHERE
+				workspace_prebuilds.template_version_preset_id = @template_version_preset_id
+				AND workspaces.organization_id = @organization_id
+				AND workspaces.deleted = false
+				AND workspace_builds.build_number = (
+					SELECT
+						MAX(latest.build_number)
+					FROM
+						workspace_builds AS latest
+					WHERE
+						latest.workspace_id = workspaces.id
+				)
+				AND workspace_builds.transition = 'start'
+				AND provisioner_jobs.job_status = 'succeeded'
+			ORDER BY
+				workspace_prebuilds.created_at
+			LIMIT
+				1
+			-- Concurrent claims skip the prebuild instead of waiting for it.
+			FOR UPDATE OF workspace_prebuilds SKIP LOCKED
+		)
+	RETURNING
+		workspace_id
+)
+UPDATE
+	workspaces
+SET
+	owner_id = @owner_id,
+	name = @name,
+	autostart_schedule = @autostart_schedule,
+	ttl = @ttl,
+	automatic_updates = @automatic_updates,
+	last_used_at = @now,
+	updated_at = @now
+FROM
+	claimed
+WHERE
+	workspaces.id = claimed.workspace_id
+RETURNING
+	workspaces.*;
+
+-- name: DeleteWorkspacePrebuildsOfDeletedWorkspaces :exec
+DELETE FROM
+	workspace_prebuilds
+WHERE
+	workspace_id IN (
+		SELECT
+			id
+		FROM
+			workspaces
+		WHERE
+			deleted = true
+	);
@@ -29,6 +29,12 @@ WHERE
 				-- Ensure the caller satisfies all job tags.
 				ELSE nested.tags :: jsonb <@ @tags :: jsonb
 			END
+			-- Ensure the caller has an engine version satisfying the
+			-- template's required_version, given as "<provisioner>:<constraint>".
+			AND (
+				nested.engine_version_constraint = ''
+				OR nested.provisioner :: text || ':' || nested.engine_version_constraint = ANY(@engine_version_constraints :: text [ ])
+			)
 		ORDER BY
 			nested.created_at
 		FOR UPDATE
@@ -37,6 +43,20 @@ WHERE
 			1
 	) RETURNING *;
 
+-- name: GetPendingProvisionerJobEngineVersionConstraints :many
+-- Returns the distinct engine version constraints of the jobs waiting for a
+-- provisioner daemon, so daemons can check which of them they satisfy before
+-- acquiring a job.
+SELECT DISTINCT
+	provisioner,
+	engine_version_constraint
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND organization_id = @organization_id
+	AND engine_version_constraint != '';
+
 -- name: GetProvisionerJobByID :one
 SELECT
 	*
@@ -102,10 +122,11 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		engine_version_constraint
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
@@ -115,6 +136,17 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateProvisionerJobEngineVersionConstraintByID :exec
+-- Records the required_version constraint a template import job found in the
+-- template, which workspace builds of the template version inherit.
+UPDATE
+	provisioner_jobs
+SET
+	engine_version_constraint = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -1,14 +1,22 @@
 -- name: GetQuotaAllowanceForUser :one
+-- Returns the sum of the allowances of the groups of the user in the
+-- organization, including the Everyone group. Users that aren't members of the
+-- organization have no allowance.
 SELECT
 	coalesce(SUM(quota_allowance), 0)::BIGINT
 FROM
 	groups g
 LEFT JOIN group_members gm ON
-	g.id = gm.group_id
+	g.id = gm.group_id AND gm.user_id = @user_id
 WHERE
-	user_id = $1
-OR
-    g.id = g.organization_id;
+	g.organization_id = @organization_id
+AND
+	(gm.user_id IS NOT NULL OR g.id = g.organization_id)
+AND
+	EXISTS (
+		SELECT 1 FROM organization_members om
+		WHERE om.organization_id = @organization_id AND om.user_id = @user_id
+	);
 
 -- name: GetQuotaConsumedForUser :one
 WITH latest_builds AS (
@@ -29,4 +37,47 @@ FROM
 	workspaces
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
-WHERE NOT deleted AND workspaces.owner_id = $1;
+WHERE NOT deleted AND workspaces.owner_id = @owner_id AND workspaces.organization_id = @organization_id;
+
+-- name: GetWorkspaceLimitForUser :one
+-- Returns the highest workspace limit of the groups of the user in the
+-- organization. 0 means none of the groups sets a limit.
+SELECT
+	coalesce(MAX(max_workspaces_per_user), 0)::INTEGER
+FROM
+	groups g
+LEFT JOIN group_members gm ON
+	g.id = gm.group_id AND gm.user_id = @user_id
+WHERE
+	g.organization_id = @organization_id
+AND
+	(gm.user_id IS NOT NULL OR g.id = g.organization_id)
+AND
+	max_workspaces_per_user > 0
+AND
+	EXISTS (
+		SELECT 1 FROM organization_members om
+		WHERE om.organization_id = @organization_id AND om.user_id = @user_id
+	);
+
+-- name: GetWorkspaceCountForUser :one
+SELECT
+	COUNT(*)
+FROM
+	workspaces
+WHERE NOT deleted AND owner_id = @owner_id AND organization_id = @organization_id;
+
+-- name: GetWorkspaceCountForTemplate :one
+-- Prebuilt workspaces that no user has claimed yet don't count towards the
+-- limit of the template.
+SELECT
+	COUNT(*)
+FROM
+	workspaces
+WHERE
+	NOT deleted
+	AND template_id = $1
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_prebuilds
+		WHERE workspace_prebuilds.workspace_id = workspaces.id
+	);
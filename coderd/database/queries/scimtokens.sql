@@ -0,0 +1,33 @@
+-- name: GetSCIMTokens :many
+SELECT * FROM scim_tokens ORDER BY created_at ASC;
+
+-- name: GetSCIMTokenByID :one
+SELECT * FROM scim_tokens WHERE id = $1;
+
+-- name: GetSCIMTokenByPrefix :one
+SELECT * FROM scim_tokens WHERE secret_prefix = $1;
+
+-- name: InsertSCIMToken :one
+INSERT INTO scim_tokens (
+    id,
+    name,
+    created_at,
+    secret_prefix,
+    hashed_secret,
+    display_secret
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING *;
+
+-- name: UpdateSCIMTokenLastUsedAt :exec
+UPDATE scim_tokens SET
+    last_used_at = $2
+WHERE id = $1;
+
+-- name: DeleteSCIMToken :exec
+DELETE FROM scim_tokens WHERE id = $1;
@@ -0,0 +1,122 @@
+-- name: InsertTemplateReleaseChannel :one
+INSERT INTO
+	template_release_channels (id, template_id, name, template_version_id, user_ids, group_ids, created_at, updated_at)
+VALUES
+	(@id, @template_id, @name, @template_version_id, @user_ids :: uuid[], @group_ids :: uuid[], @created_at, @created_at)
+RETURNING *;
+
+-- name: GetTemplateReleaseChannelsByTemplateID :many
+SELECT
+	*
+FROM
+	template_release_channels
+WHERE
+	template_id = @template_id
+ORDER BY
+	name ASC;
+
+-- name: GetTemplateReleaseChannelByID :one
+SELECT
+	*
+FROM
+	template_release_channels
+WHERE
+	id = @id;
+
+-- name: GetTemplateReleaseChannelByTemplateIDAndName :one
+SELECT
+	*
+FROM
+	template_release_channels
+WHERE
+	template_id = @template_id
+	AND name = @name;
+
+-- name: UpdateTemplateReleaseChannelByID :one
+UPDATE
+	template_release_channels
+SET
+	template_version_id = @template_version_id,
+	user_ids = @user_ids :: uuid[],
+	group_ids = @group_ids :: uuid[],
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteTemplateReleaseChannelByID :exec
+DELETE FROM
+	template_release_channels
+WHERE
+	id = @id;
+
+-- name: GetTemplateActiveVersionForUser :one
+-- Returns the version of the template that the workspaces of the user use.
+-- This is the version of the release channel the user is assigned to, or the
+-- active version of the template if the user isn't assigned to any channel. A
+-- channel the user is assigned to directly takes precedence over channels
+-- assigned to one of their groups, and ties are broken by channel name.
+SELECT
+	COALESCE(channel.template_version_id, templates.active_version_id) :: uuid AS active_version_id,
+	COALESCE(channel.name, '') :: text AS release_channel
+FROM
+	templates
+LEFT JOIN LATERAL (
+	SELECT
+		template_release_channels.name,
+		template_release_channels.template_version_id
+	FROM
+		template_release_channels
+	WHERE
+		template_release_channels.template_id = templates.id
+		AND (
+			@user_id :: uuid = ANY(template_release_channels.user_ids)
+			OR template_release_channels.group_ids && ARRAY(
+				SELECT group_id FROM group_members WHERE group_members.user_id = @user_id
+				UNION
+				-- The "Everyone" group of an organization has the organization's ID.
+				SELECT organization_id FROM organization_members WHERE organization_members.user_id = @user_id
+			)
+		)
+	ORDER BY
+		@user_id = ANY(template_release_channels.user_ids) DESC,
+		template_release_channels.name ASC
+	LIMIT 1
+) channel ON true
+WHERE
+	templates.id = @template_id;
+
+-- name: GetTemplateActiveVersionsByWorkspaceIDs :many
+-- Returns the version of its template that each workspace uses, resolved for
+-- the workspace owner like GetTemplateActiveVersionForUser.
+SELECT
+	workspaces.id AS workspace_id,
+	COALESCE(channel.template_version_id, templates.active_version_id) :: uuid AS active_version_id,
+	COALESCE(channel.name, '') :: text AS release_channel
+FROM
+	workspaces
+JOIN
+	templates ON templates.id = workspaces.template_id
+LEFT JOIN LATERAL (
+	SELECT
+		template_release_channels.name,
+		template_release_channels.template_version_id
+	FROM
+		template_release_channels
+	WHERE
+		template_release_channels.template_id = workspaces.template_id
+		AND (
+			workspaces.owner_id = ANY(template_release_channels.user_ids)
+			OR template_release_channels.group_ids && ARRAY(
+				SELECT group_id FROM group_members WHERE group_members.user_id = workspaces.owner_id
+				UNION
+				SELECT organization_id FROM organization_members WHERE organization_members.user_id = workspaces.owner_id
+			)
+		)
+	ORDER BY
+		workspaces.owner_id = ANY(template_release_channels.user_ids) DESC,
+		template_release_channels.name ASC
+	LIMIT 1
+) channel ON true
+WHERE
+	workspaces.id = ANY(@ids :: uuid[]);
@@ -118,7 +118,8 @@ SET
 	display_name = $6,
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
-	max_port_sharing_level = $9
+	max_port_sharing_level = $9,
+	max_workspaces = $10
 WHERE
 	id = $1
 ;
@@ -0,0 +1,24 @@
+-- name: InsertTemplateVersionDiagnostic :one
+INSERT INTO
+	template_version_diagnostics (
+		id,
+		template_version_id,
+		severity,
+		code,
+		summary,
+		detail,
+		filename,
+		line
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: GetTemplateVersionDiagnostics :many
+SELECT
+	*
+FROM
+	template_version_diagnostics
+WHERE
+	template_version_id = $1
+ORDER BY
+	filename, line, code;
@@ -0,0 +1,54 @@
+-- name: InsertTemplateVersionPreset :one
+INSERT INTO
+	template_version_presets (id, template_version_id, name, created_at, desired_prebuild_instances)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: InsertTemplateVersionPresetParameters :many
+INSERT INTO
+	template_version_preset_parameters (template_version_preset_id, name, value)
+SELECT
+	@template_version_preset_id :: uuid AS template_version_preset_id,
+	unnest(@names :: text [ ]) AS name,
+	unnest(@values :: text [ ]) AS value
+RETURNING template_version_preset_parameters.*;
+
+-- name: GetTemplateVersionPresets :many
+SELECT
+	*
+FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+ORDER BY
+	name;
+
+-- name: GetTemplateVersionPresetByID :one
+SELECT
+	*
+FROM
+	template_version_presets
+WHERE
+	id = $1;
+
+-- name: GetTemplateVersionPresetParameters :many
+-- Returns the parameters of every preset of a template version.
+SELECT
+	template_version_preset_parameters.*
+FROM
+	template_version_preset_parameters
+	INNER JOIN template_version_presets ON template_version_presets.id = template_version_preset_parameters.template_version_preset_id
+WHERE
+	template_version_presets.template_version_id = $1
+ORDER BY
+	template_version_preset_parameters.name;
+
+-- name: GetTemplateVersionPresetParametersByPresetID :many
+SELECT
+	*
+FROM
+	template_version_preset_parameters
+WHERE
+	template_version_preset_id = $1
+ORDER BY
+	name;
@@ -209,6 +209,11 @@ FROM
 		)
 		-- Also never archive the active template version
 		AND active_version_id != scoped_template_versions.id
+		-- Nor the versions of release channels
+		AND NOT EXISTS (
+			SELECT 1 FROM template_release_channels
+			WHERE template_release_channels.template_version_id = scoped_template_versions.id
+		)
 		AND CASE
 			-- Optionally, only archive versions that match a given
 			-- job status like 'failed'.
@@ -0,0 +1,77 @@
+-- name: InsertUserLoginAttempt :exec
+INSERT INTO user_login_attempts (
+    id,
+    user_id,
+    ip,
+    user_agent,
+    success,
+    created_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+);
+
+-- name: GetLoginFailureStats :one
+-- Counts the failed password logins since @since for the user and for the IP
+-- address, along with the time of the latest failure of each. The times fall
+-- back to @since when there are no failures.
+SELECT
+	COUNT(*) FILTER (WHERE user_id = @user_id :: uuid) AS user_failures,
+	COALESCE(MAX(created_at) FILTER (WHERE user_id = @user_id :: uuid), @since :: timestamptz) :: timestamptz AS last_user_failure,
+	COUNT(*) FILTER (WHERE ip = @ip) AS ip_failures,
+	COALESCE(MAX(created_at) FILTER (WHERE ip = @ip), @since) :: timestamptz AS last_ip_failure
+FROM
+	user_login_attempts
+WHERE
+	NOT success
+	AND created_at > @since
+	AND (user_id = @user_id :: uuid OR ip = @ip);
+
+-- name: GetUserLoginHistorySummary :one
+-- Reports whether the user has logged in with a password before, and whether
+-- any of those logins came from the IP address or user agent.
+SELECT
+	COUNT(*) > 0 AS has_logins,
+	COUNT(*) FILTER (WHERE ip = @ip) > 0 AS seen_ip,
+	COUNT(*) FILTER (WHERE user_agent = @user_agent) > 0 AS seen_user_agent
+FROM
+	user_login_attempts
+WHERE
+	user_id = @user_id :: uuid
+	AND success;
+
+-- name: DeleteUserLoginFailures :exec
+DELETE FROM user_login_attempts WHERE user_id = @user_id :: uuid AND NOT success;
+
+-- name: DeleteOldUserLoginAttempts :exec
+-- Login history is kept for 90 days to detect logins from new IP addresses or
+-- user agents.
+DELETE FROM user_login_attempts WHERE created_at < NOW() - INTERVAL '90 days';
+
+-- name: GetUserLoginLockoutByUserID :one
+SELECT * FROM user_login_lockouts WHERE user_id = $1;
+
+-- name: UpsertUserLoginLockout :one
+INSERT INTO user_login_lockouts (
+    user_id,
+    failed_attempts,
+    created_at,
+    locked_until
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    failed_attempts = $2,
+    created_at = $3,
+    locked_until = $4
+RETURNING *;
+
+-- name: DeleteUserLoginLockoutByUserID :exec
+DELETE FROM user_login_lockouts WHERE user_id = $1;
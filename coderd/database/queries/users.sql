@@ -49,12 +49,13 @@ WHERE
 	deleted = false;
 
 -- name: GetActiveUserCount :one
+-- Service accounts are not counted, as they do not use a licensed seat.
 SELECT
 	COUNT(*)
 FROM
 	users
 WHERE
-	status = 'active'::user_status AND deleted = false;
+	status = 'active'::user_status AND deleted = false AND is_service_account = false;
 
 -- name: InsertUser :one
 INSERT INTO
@@ -66,10 +67,11 @@ INSERT INTO
 		created_at,
 		updated_at,
 		rbac_roles,
-		login_type
+		login_type,
+		is_service_account
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
 
 -- name: UpdateUserProfile :one
 UPDATE
@@ -273,6 +275,8 @@ SET
 WHERE
     last_seen_at < @last_seen_after :: timestamp
     AND status = 'active'::user_status
+    -- Service accounts only use tokens, so they are never dormant.
+    AND is_service_account = false
 RETURNING id, email, last_seen_at;
 
 -- AllUserIDs returns all UserIDs regardless of user status or deletion.
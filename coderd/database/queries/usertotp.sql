@@ -0,0 +1,72 @@
+-- name: GetUserTOTPByUserID :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserTOTP :one
+-- Starts a new enrollment, replacing any earlier enrollment that was not
+-- confirmed. Returns no rows when the user already has TOTP enabled.
+INSERT INTO user_totp (
+	user_id,
+	secret,
+	created_at
+) VALUES (
+	$1,
+	$2,
+	$3
+)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	enabled_at = NULL,
+	last_used_step = 0,
+	hashed_recovery_codes = '{}'::text[],
+	created_at = $3
+WHERE
+	user_totp.enabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUserTOTP :one
+UPDATE
+	user_totp
+SET
+	enabled_at = @enabled_at,
+	last_used_step = @last_used_step,
+	hashed_recovery_codes = @hashed_recovery_codes :: text[]
+WHERE
+	user_id = @user_id
+	AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UpdateUserTOTPLastUsedStep :one
+-- Only moves forward, so a code is accepted at most once. Returns no rows when
+-- the step was already used.
+UPDATE
+	user_totp
+SET
+	last_used_step = @last_used_step
+WHERE
+	user_id = @user_id
+	AND last_used_step < @last_used_step
+RETURNING *;
+
+-- name: UseUserTOTPRecoveryCode :one
+-- Removes a recovery code so it cannot be used again. Returns no rows when the
+-- code does not match.
+UPDATE
+	user_totp
+SET
+	hashed_recovery_codes = array_remove(hashed_recovery_codes, @hashed_code :: text)
+WHERE
+	user_id = @user_id
+	AND enabled_at IS NOT NULL
+	AND @hashed_code :: text = ANY(hashed_recovery_codes)
+RETURNING *;
+
+-- name: DeleteUserTOTPByUserID :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1;
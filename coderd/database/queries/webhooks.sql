@@ -0,0 +1,156 @@
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (
+		id,
+		name,
+		url,
+		events,
+		secret,
+		enabled,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $7) RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT
+	*
+FROM
+	webhooks
+ORDER BY
+	name;
+
+-- name: GetWebhookByID :one
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	name = @name,
+	url = @url,
+	events = @events,
+	secret = @secret,
+	enabled = @enabled,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		event,
+		payload,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $5) RETURNING *;
+
+-- Acquires a batch of deliveries that are waiting to be sent: new
+-- deliveries, failed deliveries whose retry is due, and leased deliveries
+-- whose lease expired without an outcome being recorded.
+--
+-- SKIP LOCKED is used to jump over locked rows. This prevents
+-- multiple coderd replicas from acquiring the same deliveries.
+-- name: AcquireWebhookDeliveries :many
+UPDATE
+	webhook_deliveries
+SET
+	status = 'leased' :: webhook_delivery_status,
+	updated_at = @now,
+	leased_until = @leased_until
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.attempt_count < @max_attempt_count :: int
+			AND (
+				nested.status = 'pending' :: webhook_delivery_status
+				OR (nested.status = 'temporary_failure' :: webhook_delivery_status AND nested.next_attempt_after <= @now)
+				OR (nested.status = 'leased' :: webhook_delivery_status AND nested.leased_until <= @now)
+			)
+		ORDER BY
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			@count :: int
+	) RETURNING *;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = 'succeeded' :: webhook_delivery_status,
+	attempt_count = attempt_count + 1,
+	response_status = @response_status,
+	response_body = @response_body,
+	error = NULL,
+	updated_at = @delivered_at,
+	delivered_at = @delivered_at,
+	leased_until = NULL,
+	next_attempt_after = NULL
+WHERE
+	id = @id;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = @status,
+	attempt_count = attempt_count + 1,
+	response_status = @response_status,
+	response_body = @response_body,
+	error = @error,
+	updated_at = @updated_at,
+	leased_until = NULL,
+	next_attempt_after = @next_attempt_after
+WHERE
+	id = @id;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	id = $1;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+LIMIT
+	@limit_opt :: int;
+
+-- name: DeleteOldWebhookDeliveries :exec
+-- Delete deliveries that reached a final state more than a week ago.
+DELETE FROM
+	webhook_deliveries
+WHERE
+	status IN ('succeeded' :: webhook_delivery_status, 'permanent_failure' :: webhook_delivery_status)
+	AND updated_at < (NOW() - INTERVAL '7 days');
@@ -179,3 +179,48 @@ WHERE
 	wb.transition = 'start'::workspace_transition
 AND
 	pj.completed_at IS NOT NULL;
+
+-- name: GetWorkspaceBuildStatesByWorkspaceID :many
+-- Returns a summary of the provisioner state stored for every build of a
+-- workspace. The state itself is not returned, as it can be large.
+SELECT
+	wb.id,
+	wb.build_number,
+	wb.transition,
+	wb.initiator_id,
+	wb.created_at,
+	pj.job_status,
+	COALESCE(octet_length(wb.provisioner_state), 0)::integer AS state_size,
+	COALESCE(encode(sha256(wb.provisioner_state), 'hex'), '')::text AS state_hash
+FROM
+	workspace_builds AS wb
+JOIN
+	provisioner_jobs AS pj
+	ON wb.job_id = pj.id
+WHERE
+	wb.workspace_id = $1
+ORDER BY
+	wb.build_number DESC;
+
+-- name: DeleteOldWorkspaceBuildProvisionerStates :exec
+-- Clears the provisioner state of all but the most recent @keep builds of
+-- every workspace. The build rows themselves are retained.
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = NULL
+WHERE
+	provisioner_state IS NOT NULL
+	AND id IN (
+		SELECT
+			id
+		FROM (
+			SELECT
+				id,
+				row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS rn
+			FROM
+				workspace_builds
+		) AS ranked
+		WHERE
+			ranked.rn > @keep::integer
+	);
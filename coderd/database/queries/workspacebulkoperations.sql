@@ -0,0 +1,74 @@
+-- name: InsertWorkspaceBulkOperation :one
+INSERT INTO
+	workspace_bulk_operations (id, initiator_id, action, query, concurrency, created_at, updated_at)
+VALUES
+	(@id, @initiator_id, @action, @query, @concurrency, @created_at, @created_at)
+RETURNING *;
+
+-- name: InsertWorkspaceBulkOperationWorkspaces :exec
+INSERT INTO
+	workspace_bulk_operation_workspaces (operation_id, workspace_id, updated_at)
+SELECT
+	@operation_id :: uuid,
+	unnest(@workspace_ids :: uuid[]),
+	@updated_at :: timestamptz;
+
+-- name: GetWorkspaceBulkOperationByID :one
+SELECT
+	*
+FROM
+	workspace_bulk_operations
+WHERE
+	id = @id;
+
+-- name: GetRunningWorkspaceBulkOperations :many
+SELECT
+	*
+FROM
+	workspace_bulk_operations
+WHERE
+	status = 'running'::workspace_bulk_operation_status
+ORDER BY
+	created_at ASC;
+
+-- name: GetWorkspaceBulkOperationWorkspaces :many
+-- The status of the job of the build started for a workspace tells the
+-- runner when the build has finished.
+SELECT
+	workspace_bulk_operation_workspaces.*,
+	workspaces.name AS workspace_name,
+	users.username AS owner_name,
+	provisioner_jobs.job_status AS job_status,
+	provisioner_jobs.error AS job_error
+FROM
+	workspace_bulk_operation_workspaces
+	JOIN workspaces ON workspaces.id = workspace_bulk_operation_workspaces.workspace_id
+	JOIN users ON users.id = workspaces.owner_id
+	LEFT JOIN workspace_builds ON workspace_builds.id = workspace_bulk_operation_workspaces.build_id
+	LEFT JOIN provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_bulk_operation_workspaces.operation_id = @operation_id
+ORDER BY
+	users.username ASC, workspaces.name ASC;
+
+-- name: UpdateWorkspaceBulkOperationWorkspace :exec
+UPDATE
+	workspace_bulk_operation_workspaces
+SET
+	status = @status,
+	build_id = @build_id,
+	error = @error,
+	updated_at = @updated_at
+WHERE
+	operation_id = @operation_id
+	AND workspace_id = @workspace_id;
+
+-- name: UpdateWorkspaceBulkOperationStatus :exec
+UPDATE
+	workspace_bulk_operations
+SET
+	status = @status,
+	updated_at = @updated_at,
+	completed_at = @completed_at
+WHERE
+	id = @id;
@@ -195,6 +195,12 @@ WHERE
 			workspaces.owner_id = @owner_id
 		ELSE true
 	END
+	-- Filter by organization_id
+	AND CASE
+		WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspaces.organization_id = @organization_id
+		ELSE true
+	END
 	-- Filter by build parameter
    	-- @has_param will match any build that includes the parameter.
 	AND CASE WHEN array_length(@has_param :: text[], 1) > 0  THEN
@@ -317,9 +323,48 @@ WHERE
 	END
   	AND CASE
 		  WHEN sqlc.narg('using_active') :: boolean IS NOT NULL THEN
-			  (latest_build.template_version_id = template.active_version_id) = sqlc.narg('using_active') :: boolean
+			  -- Workspaces use the version of the release channel of their
+			  -- owner, see GetTemplateActiveVersionIDForUser.
+			  (latest_build.template_version_id = COALESCE(
+				  (
+					  SELECT
+						  template_release_channels.template_version_id
+					  FROM
+						  template_release_channels
+					  WHERE
+						  template_release_channels.template_id = workspaces.template_id
+						  AND (
+							  workspaces.owner_id = ANY(template_release_channels.user_ids)
+							  OR template_release_channels.group_ids && ARRAY(
+								  SELECT group_id FROM group_members WHERE group_members.user_id = workspaces.owner_id
+								  UNION
+								  SELECT organization_id FROM organization_members WHERE organization_members.user_id = workspaces.owner_id
+							  )
+						  )
+					  ORDER BY
+						  workspaces.owner_id = ANY(template_release_channels.user_ids) DESC,
+						  template_release_channels.name ASC
+					  LIMIT 1
+				  ),
+				  template.active_version_id
+			  )) = sqlc.narg('using_active') :: boolean
 		  ELSE true
 	END
+	-- Filter by workspaces shared with the requester, directly or through a
+	-- group.
+	AND CASE
+		WHEN @shared :: boolean THEN
+			workspaces.owner_id != @requester_id AND (
+				workspaces.user_acl ? (@requester_id :: text)
+				OR workspaces.group_acl ?| ARRAY(
+					SELECT group_id :: text FROM group_members WHERE user_id = @requester_id
+					UNION
+					-- The "Everyone" group of an organization has the organization's ID.
+					SELECT organization_id :: text FROM organization_members WHERE user_id = @requester_id
+				)
+			)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
@@ -367,6 +412,8 @@ WHERE
 		'0001-01-01 00:00:00+00'::timestamptz, -- deleting_at
 		'never'::automatic_updates, -- automatic_updates
 		false, -- favorite
+		'{}'::jsonb, -- user_acl
+		'{}'::jsonb, -- group_acl
 		-- Extra columns added to `filtered_workspaces`
 		'', -- template_name
 		'00000000-0000-0000-0000-000000000000'::uuid, -- template_version_id
@@ -672,3 +719,23 @@ UPDATE workspaces SET favorite = true WHERE id = @id;
 
 -- name: UnfavoriteWorkspace :exec
 UPDATE workspaces SET favorite = false WHERE id = @id;
+
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = @user_acl,
+	group_acl = @group_acl
+WHERE
+	id = @id;
+
+-- name: UpdateWorkspaceOwnerByID :exec
+-- Favorites belong to the previous owner, so they are reset.
+UPDATE
+	workspaces
+SET
+	owner_id = @owner_id,
+	favorite = false,
+	updated_at = @updated_at
+WHERE
+	id = @id;
@@ -0,0 +1,70 @@
+-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (id, workspace_id, name, build_id, created_at)
+VALUES
+	(@id, @workspace_id, @name, @build_id, @created_at)
+RETURNING *;
+
+-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	id = @id;
+
+-- name: GetWorkspaceSnapshotByBuildID :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	build_id = @build_id;
+
+-- name: GetWorkspaceSnapshotByWorkspaceIDAndName :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = @workspace_id
+	AND name = @name;
+
+-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = @workspace_id
+ORDER BY
+	created_at DESC;
+
+-- name: UpdateWorkspaceSnapshotArtifactsByID :exec
+UPDATE
+	workspace_snapshots
+SET
+	artifacts = @artifacts :: jsonb
+WHERE
+	id = @id;
+
+-- name: DeleteWorkspaceSnapshotByID :exec
+DELETE FROM
+	workspace_snapshots
+WHERE
+	id = @id;
+
+-- name: InsertWorkspaceSnapshotRestore :one
+INSERT INTO
+	workspace_snapshot_restores (workspace_id, snapshot_id, artifacts, created_at)
+VALUES
+	(@workspace_id, @snapshot_id, @artifacts :: jsonb, @created_at)
+RETURNING *;
+
+-- name: GetWorkspaceSnapshotRestoreByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_snapshot_restores
+WHERE
+	workspace_id = @workspace_id;
@@ -64,6 +64,50 @@ func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// WorkspaceACL is a map of user or group ids to the actions they are allowed
+// to perform on a workspace they don't own.
+type WorkspaceACL map[string][]rbac.Action
+
+func (w *WorkspaceACL) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &w)
+	case []byte, json.RawMessage:
+		//nolint
+		return json.Unmarshal(v.([]byte), &w)
+	}
+
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (w WorkspaceACL) Value() (driver.Value, error) {
+	return json.Marshal(w)
+}
+
+// CustomRolePermissions are the permissions a custom role grants at one level
+// of the role, e.g. site wide.
+type CustomRolePermissions []rbac.Permission
+
+func (p *CustomRolePermissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &p)
+	case []byte, json.RawMessage:
+		//nolint
+		return json.Unmarshal(v.([]byte), &p)
+	}
+
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (p CustomRolePermissions) Value() (driver.Value, error) {
+	if p == nil {
+		// The columns are not nullable.
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
 type ExternalAuthProvider struct {
 	ID       string `json:"id"`
 	Optional bool   `json:"optional,omitempty"`
@@ -46,7 +46,7 @@ func init() {
 		valid := NameValid(str)
 		return valid == nil
 	}
-	for _, tag := range []string{"username", "template_name", "workspace_name", "oauth2_app_name"} {
+	for _, tag := range []string{"username", "template_name", "workspace_name", "oauth2_app_name", "release_channel_name", "snapshot_name", "role_name"} {
 		err := Validate.RegisterValidation(tag, nameValidator)
 		if err != nil {
 			panic(err)
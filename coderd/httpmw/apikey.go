@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -108,6 +109,15 @@ type ExtractAPIKeyConfig struct {
 	// This is originally implemented to send entitlement warning headers after
 	// a user is authenticated to prevent additional CLI invocations.
 	PostAuthAdditionalHeadersFunc func(a rbac.Subject, header http.Header)
+
+	// Sessions, if set, cancels the request context when the API key is
+	// revoked, so long-lived requests such as websockets are closed.
+	Sessions SessionTracker
+}
+
+// SessionTracker tracks the requests made with each API key.
+type SessionTracker interface {
+	Track(ctx context.Context, keyID string) (context.Context, func())
 }
 
 // ExtractAPIKeyMW calls ExtractAPIKey with the given config on each request,
@@ -133,6 +143,12 @@ func ExtractAPIKeyMW(cfg ExtractAPIKeyConfig) func(http.Handler) http.Handler {
 			// Set the auth context for the user.
 			ctx = dbauthz.As(ctx, authz)
 
+			if cfg.Sessions != nil {
+				var done func()
+				ctx, done = cfg.Sessions.Track(ctx, key.ID)
+				defer done()
+			}
+
 			next.ServeHTTP(rw, r.WithContext(ctx))
 		})
 	}
@@ -339,8 +355,9 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		changed = true
 	}
 	// Only update the ExpiresAt once an hour to prevent database spam.
-	// We extend the ExpiresAt to reduce re-authentication.
-	if !cfg.DisableSessionExpiryRefresh {
+	// We extend the ExpiresAt to reduce re-authentication. Impersonation
+	// keys are never extended so the session ends when it was meant to.
+	if !cfg.DisableSessionExpiryRefresh && !key.ImpersonatorID.Valid {
 		apiKeyLifetime := time.Duration(key.LifetimeSeconds) * time.Second
 		if key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
 			key.ExpiresAt = now.Add(apiKeyLifetime)
@@ -387,17 +404,39 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 
 		// We only want to update this occasionally to reduce DB write
 		// load. We update alongside the UserLink and APIKey since it's
-		// easier on the DB to colocate writes.
-		//nolint:gocritic // system needs to update user last seen at
-		_, err = cfg.DB.UpdateUserLastSeenAt(dbauthz.AsSystemRestricted(ctx), database.UpdateUserLastSeenAtParams{
-			ID:         key.UserID,
-			LastSeenAt: dbtime.Now(),
-			UpdatedAt:  dbtime.Now(),
-		})
-		if err != nil {
+		// easier on the DB to colocate writes. An impersonator acting as
+		// the user does not count as the user being seen.
+		if !key.ImpersonatorID.Valid {
+			//nolint:gocritic // system needs to update user last seen at
+			_, err = cfg.DB.UpdateUserLastSeenAt(dbauthz.AsSystemRestricted(ctx), database.UpdateUserLastSeenAtParams{
+				ID:         key.UserID,
+				LastSeenAt: dbtime.Now(),
+				UpdatedAt:  dbtime.Now(),
+			})
+			if err != nil {
+				return write(http.StatusInternalServerError, codersdk.Response{
+					Message: internalErrorMessage,
+					Detail:  fmt.Sprintf("update user last_seen_at: %s", err.Error()),
+				})
+			}
+		}
+	}
+
+	// An impersonation session is only valid for as long as the user who
+	// started it is an active owner.
+	if key.ImpersonatorID.Valid {
+		//nolint:gocritic // system needs to check the impersonator's roles
+		impersonator, err := cfg.DB.GetUserByID(dbauthz.AsSystemRestricted(ctx), key.ImpersonatorID.UUID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return write(http.StatusInternalServerError, codersdk.Response{
 				Message: internalErrorMessage,
-				Detail:  fmt.Sprintf("update user last_seen_at: %s", err.Error()),
+				Detail:  fmt.Sprintf("Internal error fetching impersonator. %s", err.Error()),
+			})
+		}
+		if err != nil || impersonator.Deleted || impersonator.Status != database.UserStatusActive || !slices.Contains(impersonator.RBACRoles, rbac.RoleOwner()) {
+			return optionalWrite(http.StatusUnauthorized, codersdk.Response{
+				Message: SignedOutErrorMessage,
+				Detail:  "The user who started this impersonation session is no longer allowed to impersonate.",
 			})
 		}
 	}
@@ -414,7 +453,8 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
-	if roles.Status == database.UserStatusDormant {
+	// Impersonating a dormant user must not activate them.
+	if roles.Status == database.UserStatusDormant && !key.ImpersonatorID.Valid {
 		// If coder confirms that the dormant user is valid, it can switch their account to active.
 		// nolint:gocritic
 		u, err := cfg.DB.UpdateUserStatus(dbauthz.AsSystemRestricted(ctx), database.UpdateUserStatusParams{
@@ -431,7 +471,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		roles.Status = u.Status
 	}
 
-	if roles.Status != database.UserStatusActive {
+	if roles.Status != database.UserStatusActive && !(key.ImpersonatorID.Valid && roles.Status == database.UserStatusDormant) {
 		return write(http.StatusUnauthorized, codersdk.Response{
 			Message: fmt.Sprintf("User is not active (status = %q). Contact an admin to reactivate your account.", roles.Status),
 		})
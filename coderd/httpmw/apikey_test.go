@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
@@ -24,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/testutil"
@@ -433,6 +435,72 @@ func TestAPIKey(t *testing.T) {
 		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
 	})
 
+	t.Run("Impersonation", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db                = dbmem.New()
+			owner             = dbgen.User(t, db, database.User{RBACRoles: []string{rbac.RoleOwner()}})
+			user              = dbgen.User(t, db, database.User{Status: database.UserStatusDormant})
+			sentAPIKey, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:         user.ID,
+				LastUsed:       dbtime.Now().AddDate(0, 0, -1),
+				ExpiresAt:      dbtime.Now().Add(time.Hour),
+				ImpersonatorID: uuid.NullUUID{UUID: owner.ID, Valid: true},
+			})
+
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+
+		httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:              db,
+			RedirectToLogin: false,
+		})(httpmw.BlockImpersonation(successHandler)).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		gotAPIKey, err := db.GetAPIKeyByID(r.Context(), sentAPIKey.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, sentAPIKey.LastUsed, gotAPIKey.LastUsed)
+		// Impersonation keys are never extended.
+		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
+
+		// Impersonating a dormant user neither activates them nor counts
+		// as them being seen.
+		gotUser, err := db.GetUserByID(r.Context(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.UserStatusDormant, gotUser.Status)
+		require.Equal(t, user.LastSeenAt, gotUser.LastSeenAt)
+	})
+
+	t.Run("ImpersonatorNotOwner", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db       = dbmem.New()
+			demoted  = dbgen.User(t, db, database.User{})
+			user     = dbgen.User(t, db, database.User{})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:         user.ID,
+				ExpiresAt:      dbtime.Now().AddDate(0, 0, 1),
+				ImpersonatorID: uuid.NullUUID{UUID: demoted.ID, Valid: true},
+			})
+
+			r  = httptest.NewRequest("GET", "/", nil)
+			rw = httptest.NewRecorder()
+		)
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+
+		httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:              db,
+			RedirectToLogin: false,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
 	t.Run("OAuthNotExpired", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -0,0 +1,88 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+func TestCustomRoleParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func(db database.Store, param string) (*http.Request, *chi.Mux) {
+		r := httptest.NewRequest("GET", "/", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("role", param)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router := chi.NewRouter()
+		router.Use(httpmw.ExtractCustomRoleParam(db))
+		return r, router
+	}
+
+	t.Run("Site", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		role := dbgen.CustomRole(t, db, database.CustomRole{})
+		r, router := setup(db, role.Name)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, role, httpmw.CustomRoleParam(r))
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		orgID := uuid.New()
+		role := dbgen.CustomRole(t, db, database.CustomRole{
+			OrganizationID: uuid.NullUUID{UUID: orgID, Valid: true},
+		})
+		// A site role with the same name must not be returned.
+		_ = dbgen.CustomRole(t, db, database.CustomRole{Name: role.Name})
+		r, router := setup(db, rbac.RoleName(role.Name, orgID.String()))
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, role, httpmw.CustomRoleParam(r))
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		role := dbgen.CustomRole(t, db, database.CustomRole{})
+		r, router := setup(db, rbac.RoleName(role.Name, uuid.NewString()))
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,31 @@
+package httpmw
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Impersonating returns true if the request was authenticated with an API key
+// that another user minted to act as the key's owner. Depends on the
+// ExtractAPIKey handler.
+func Impersonating(r *http.Request) bool {
+	key, ok := APIKeyOptional(r)
+	return ok && key.ImpersonatorID.Valid
+}
+
+// BlockImpersonation rejects requests authenticated with an impersonation API
+// key. It guards sensitive actions like changing credentials or minting new
+// keys, which an impersonator must never perform on the user's behalf.
+func BlockImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if Impersonating(r) {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: "This action is not allowed while impersonating another user.",
+			})
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,52 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type oidcSyncRuleParamContextKey struct{}
+
+// OIDCSyncRuleParam returns the sync rule extracted via the
+// ExtractOIDCSyncRuleParam middleware.
+func OIDCSyncRuleParam(r *http.Request) database.OIDCSyncRule {
+	rule, ok := r.Context().Value(oidcSyncRuleParamContextKey{}).(database.OIDCSyncRule)
+	if !ok {
+		panic("developer error: oidc sync rule param middleware not provided")
+	}
+	return rule
+}
+
+// ExtractOIDCSyncRuleParam grabs an OIDC sync rule from the "rule" URL parameter.
+func ExtractOIDCSyncRuleParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			ruleID, parsed := ParseUUIDParam(rw, r, "rule")
+			if !parsed {
+				return
+			}
+
+			rule, err := db.GetOIDCSyncRuleByID(ctx, ruleID)
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching OIDC sync rule.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, oidcSyncRuleParamContextKey{}, rule)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/httpmw"
+)
+
+func TestOIDCSyncRuleParam(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db   = dbmem.New()
+			rule = dbgen.OIDCSyncRule(t, db, database.OIDCSyncRule{})
+			r    = httptest.NewRequest("GET", "/", nil)
+			w    = httptest.NewRecorder()
+		)
+
+		router := chi.NewRouter()
+		router.Use(httpmw.ExtractOIDCSyncRuleParam(db))
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			found := httpmw.OIDCSyncRuleParam(r)
+			require.Equal(t, rule, found)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("rule", rule.ID.String())
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router.ServeHTTP(w, r)
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db   = dbmem.New()
+			rule = dbgen.OIDCSyncRule(t, db, database.OIDCSyncRule{})
+			r    = httptest.NewRequest("GET", "/", nil)
+			w    = httptest.NewRecorder()
+		)
+
+		router := chi.NewRouter()
+		router.Use(httpmw.ExtractOIDCSyncRuleParam(db))
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			found := httpmw.OIDCSyncRuleParam(r)
+			require.Equal(t, rule, found)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("rule", uuid.NewString())
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router.ServeHTTP(w, r)
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,63 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type templateReleaseChannelParamContextKey struct{}
+
+// TemplateReleaseChannelParam returns the release channel extracted via the
+// ExtractTemplateReleaseChannelParam middleware.
+func TemplateReleaseChannelParam(r *http.Request) database.TemplateReleaseChannel {
+	channel, ok := r.Context().Value(templateReleaseChannelParamContextKey{}).(database.TemplateReleaseChannel)
+	if !ok {
+		panic("developer error: template release channel param middleware not provided")
+	}
+	return channel
+}
+
+// ExtractTemplateReleaseChannelParam grabs a release channel of the template
+// from the "channel" URL parameter, which is the name of the channel.
+// ExtractTemplateParam must be used before it.
+func ExtractTemplateReleaseChannelParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			template := TemplateParam(r)
+
+			name := chi.URLParam(r, "channel")
+			if name == "" {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "\"channel\" must be provided.",
+				})
+				return
+			}
+
+			channel, err := db.GetTemplateReleaseChannelByTemplateIDAndName(ctx, database.GetTemplateReleaseChannelByTemplateIDAndNameParams{
+				TemplateID: template.ID,
+				Name:       name,
+			})
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching template release channel.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, templateReleaseChannelParamContextKey{}, channel)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
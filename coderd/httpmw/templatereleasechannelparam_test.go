@@ -0,0 +1,86 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/httpmw"
+)
+
+func TestTemplateReleaseChannelParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func(db database.Store, channelName string) *http.Request {
+		var (
+			org      = dbgen.Organization(t, db, database.Organization{})
+			user     = dbgen.User(t, db, database.User{})
+			template = dbgen.Template(t, db, database.Template{
+				OrganizationID: org.ID,
+				CreatedBy:      user.ID,
+			})
+			_ = dbgen.TemplateReleaseChannel(t, db, database.TemplateReleaseChannel{
+				TemplateID: template.ID,
+				Name:       "canary",
+			})
+		)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("template", template.ID.String())
+		rctx.URLParams.Add("channel", channelName)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractTemplateParam(db),
+			httpmw.ExtractTemplateReleaseChannelParam(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			channel := httpmw.TemplateReleaseChannelParam(r)
+			require.Equal(t, "canary", channel.Name)
+			require.Equal(t, httpmw.TemplateParam(r).ID, channel.TemplateID)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, setup(db, "canary"))
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractTemplateParam(db),
+			httpmw.ExtractTemplateReleaseChannelParam(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, setup(db, "beta"))
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
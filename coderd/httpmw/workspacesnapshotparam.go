@@ -0,0 +1,63 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type workspaceSnapshotParamContextKey struct{}
+
+// WorkspaceSnapshotParam returns the snapshot extracted via the
+// ExtractWorkspaceSnapshotParam middleware.
+func WorkspaceSnapshotParam(r *http.Request) database.WorkspaceSnapshot {
+	snapshot, ok := r.Context().Value(workspaceSnapshotParamContextKey{}).(database.WorkspaceSnapshot)
+	if !ok {
+		panic("developer error: workspace snapshot param middleware not provided")
+	}
+	return snapshot
+}
+
+// ExtractWorkspaceSnapshotParam grabs a snapshot of the workspace from the
+// "snapshot" URL parameter, which is the name of the snapshot.
+// ExtractWorkspaceParam must be used before it.
+func ExtractWorkspaceSnapshotParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			workspace := WorkspaceParam(r)
+
+			name := chi.URLParam(r, "snapshot")
+			if name == "" {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "\"snapshot\" must be provided.",
+				})
+				return
+			}
+
+			snapshot, err := db.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams{
+				WorkspaceID: workspace.ID,
+				Name:        name,
+			})
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching workspace snapshot.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
+			ctx = context.WithValue(ctx, workspaceSnapshotParamContextKey{}, snapshot)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/httpmw"
+)
+
+func TestWorkspaceSnapshotParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func(db database.Store, snapshotName string) *http.Request {
+		var (
+			user      = dbgen.User(t, db, database.User{})
+			workspace = dbgen.Workspace(t, db, database.Workspace{
+				OwnerID: user.ID,
+			})
+			_ = dbgen.WorkspaceSnapshot(t, db, database.WorkspaceSnapshot{
+				WorkspaceID: workspace.ID,
+				Name:        "datasets",
+			})
+		)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("workspace", workspace.ID.String())
+		rctx.URLParams.Add("snapshot", snapshotName)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractWorkspaceParam(db),
+			httpmw.ExtractWorkspaceSnapshotParam(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			snapshot := httpmw.WorkspaceSnapshotParam(r)
+			require.Equal(t, "datasets", snapshot.Name)
+			require.Equal(t, httpmw.WorkspaceParam(r).ID, snapshot.WorkspaceID)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, setup(db, "datasets"))
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractWorkspaceParam(db),
+			httpmw.ExtractWorkspaceSnapshotParam(db),
+		)
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, setup(db, "models"))
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,213 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Creates a short-lived API key that lets an owner act as another user.
+//
+// @Summary Impersonate user
+// @ID impersonate-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ImpersonateUserRequest true "Impersonate user request"
+// @Success 201 {object} codersdk.ImpersonateUserResponse
+// @Router /users/{user}/impersonations [post]
+func (api *API) postUserImpersonation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if !slices.Contains(httpmw.UserAuthorization(r).Roles.Names(), rbac.RoleOwner()) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only owners can impersonate other users.",
+		})
+		return
+	}
+	if user.ID == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot impersonate yourself.",
+		})
+		return
+	}
+	if user.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Suspended users cannot be impersonated.",
+		})
+		return
+	}
+
+	var req codersdk.ImpersonateUserRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	lifetime := codersdk.DefaultImpersonationLifetime
+	if req.Lifetime != 0 {
+		lifetime = req.Lifetime
+	}
+	if lifetime < 0 || lifetime > codersdk.MaxImpersonationLifetime {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid impersonation lifetime.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "lifetime",
+				Detail: fmt.Sprintf("Must be positive and no longer than %s.", codersdk.MaxImpersonationLifetime),
+			}},
+		})
+		return
+	}
+
+	cookie, key, err := api.createAPIKey(ctx, apikey.CreateParams{
+		UserID:          user.ID,
+		LoginType:       database.LoginTypePassword,
+		DefaultLifetime: lifetime,
+		ExpiresAt:       dbtime.Now().Add(lifetime),
+		LifetimeSeconds: int64(lifetime.Seconds()),
+		RemoteAddr:      r.RemoteAddr,
+		UserAgent:       r.UserAgent(),
+		ImpersonatorID:  uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create impersonation session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = *key
+
+	// As with session keys created for the CLI, the cookie is intentionally
+	// not set so the owner's own browser session is left untouched.
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.ImpersonateUserResponse{
+		ImpersonationSession: convertImpersonationSession(*key),
+		Key:                  cookie.Value,
+	})
+}
+
+// @Summary Get user impersonation sessions
+// @ID get-user-impersonation-sessions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.ImpersonationSession
+// @Router /users/{user}/impersonations [get]
+func (api *API) userImpersonations(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	keys, err := api.Database.GetImpersonationAPIKeysByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching impersonation sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	sessions := make([]codersdk.ImpersonationSession, 0, len(keys))
+	for _, key := range keys {
+		sessions = append(sessions, convertImpersonationSession(key))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
+// @Summary Revoke user impersonation session
+// @ID revoke-user-impersonation-session
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyid path string true "Impersonation session ID"
+// @Success 204
+// @Router /users/{user}/impersonations/{keyid} [delete]
+func (api *API) deleteUserImpersonation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		keyID             = chi.URLParam(r, "keyid")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching impersonation session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Only impersonation keys can be revoked here, and only through the
+	// user they impersonate.
+	if key.UserID != user.ID || !key.ImpersonatorID.Valid {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = key
+
+	err = api.Database.DeleteAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking impersonation session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.SessionTracker.Revoked(ctx, api.Pubsub, keyID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func convertImpersonationSession(k database.APIKey) codersdk.ImpersonationSession {
+	return codersdk.ImpersonationSession{
+		ID:             k.ID,
+		UserID:         k.UserID,
+		ImpersonatorID: k.ImpersonatorID.UUID,
+		CreatedAt:      k.CreatedAt,
+		ExpiresAt:      k.ExpiresAt,
+	}
+}
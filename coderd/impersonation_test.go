@@ -0,0 +1,209 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestImpersonation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		auditor.ResetLogs()
+		session, err := client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.NoError(t, err)
+		require.Equal(t, member.ID, session.UserID)
+		require.Equal(t, owner.UserID, session.ImpersonatorID)
+		require.WithinDuration(t, time.Now().Add(codersdk.DefaultImpersonationLifetime), session.ExpiresAt, time.Minute)
+		require.Len(t, auditor.AuditLogs(), 1)
+		require.Equal(t, database.AuditActionCreate, auditor.AuditLogs()[0].Action)
+		require.Equal(t, owner.UserID, auditor.AuditLogs()[0].UserID)
+
+		impersonated := codersdk.New(client.URL)
+		impersonated.SetSessionToken(session.Key)
+
+		me, err := impersonated.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, member.ID, me.ID)
+
+		// Audited actions record both identities.
+		auditor.ResetLogs()
+		_, err = impersonated.UpdateUserProfile(ctx, codersdk.Me, codersdk.UpdateUserProfileRequest{
+			Username: member.Username,
+			Name:     "Impersonated",
+		})
+		require.NoError(t, err)
+		require.Len(t, auditor.AuditLogs(), 1)
+		alog := auditor.AuditLogs()[0]
+		require.Equal(t, member.ID, alog.UserID)
+		require.True(t, alog.ImpersonatorID.Valid)
+		require.Equal(t, owner.UserID, alog.ImpersonatorID.UUID)
+
+		sessions, err := client.UserImpersonations(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		require.Equal(t, session.ID, sessions[0].ID)
+
+		// Impersonation sessions cannot be managed while impersonating.
+		_, err = impersonated.UserImpersonations(ctx, codersdk.Me)
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		session, err := client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.NoError(t, err)
+		impersonated := codersdk.New(client.URL)
+		impersonated.SetSessionToken(session.Key)
+
+		err = impersonated.UpdateUserPassword(ctx, codersdk.Me, codersdk.UpdateUserPasswordRequest{
+			OldPassword: "SomeSecurePassword!",
+			Password:    "SomeNewSecurePassword!",
+		})
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = impersonated.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = impersonated.CreateAPIKey(ctx, codersdk.Me)
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = impersonated.ImpersonateUser(ctx, codersdk.Me, codersdk.ImpersonateUserRequest{})
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = impersonated.UpdateUserRoles(ctx, codersdk.Me, codersdk.UpdateRoles{Roles: []string{}})
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		err = impersonated.DeleteAPIKey(ctx, codersdk.Me, session.ID)
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = impersonated.RegenerateGitSSHKey(ctx, codersdk.Me)
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		session, err := client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.NoError(t, err)
+		impersonated := codersdk.New(client.URL)
+		impersonated.SetSessionToken(session.Key)
+
+		// The impersonated user can see and revoke the session.
+		sessions, err := memberClient.UserImpersonations(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+
+		// Only impersonation keys can be revoked through this endpoint.
+		err = client.RevokeImpersonation(ctx, codersdk.Me, session.ID)
+		require.Equal(t, http.StatusNotFound, coderdtest.SDKError(t, err).StatusCode())
+
+		auditor.ResetLogs()
+		err = memberClient.RevokeImpersonation(ctx, codersdk.Me, session.ID)
+		require.NoError(t, err)
+		require.Len(t, auditor.AuditLogs(), 1)
+		require.Equal(t, database.AuditActionDelete, auditor.AuditLogs()[0].Action)
+
+		_, err = impersonated.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+
+		sessions, err = client.UserImpersonations(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+	})
+
+	t.Run("ImpersonatorDemoted", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		secondOwnerClient, secondOwner := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleOwner())
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		session, err := secondOwnerClient.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.NoError(t, err)
+		impersonated := codersdk.New(client.URL)
+		impersonated.SetSessionToken(session.Key)
+
+		_, err = impersonated.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = client.UpdateUserRoles(ctx, secondOwner.ID.String(), codersdk.UpdateRoles{Roles: []string{}})
+		require.NoError(t, err)
+
+		_, err = impersonated.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		userAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := userAdminClient.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.Equal(t, http.StatusForbidden, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.ImpersonateUser(ctx, codersdk.Me, codersdk.ImpersonateUserRequest{})
+		require.Equal(t, http.StatusBadRequest, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{
+			Lifetime: codersdk.MaxImpersonationLifetime + time.Minute,
+		})
+		require.Equal(t, http.StatusBadRequest, coderdtest.SDKError(t, err).StatusCode())
+
+		_, err = client.UpdateUserStatus(ctx, member.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+		_, err = client.ImpersonateUser(ctx, member.ID.String(), codersdk.ImpersonateUserRequest{})
+		require.Equal(t, http.StatusBadRequest, coderdtest.SDKError(t, err).StatusCode())
+	})
+}
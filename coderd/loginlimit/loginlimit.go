@@ -0,0 +1,62 @@
+// Package loginlimit decides when password logins are slowed down or locked
+// out after repeated failures.
+package loginlimit
+
+import "time"
+
+// Policy configures brute-force protection for password logins. Failures are
+// counted per user and per IP address within Window.
+type Policy struct {
+	// Window is how far back failed attempts are counted.
+	Window time.Duration
+	// FreeAttempts is the number of failures allowed before delays apply.
+	FreeAttempts int64
+	// BaseDelay is the wait after the first failure past FreeAttempts. It
+	// doubles with every further failure, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxFailures is the number of failures for a single user within Window
+	// that locks the account for LockoutDuration. Zero disables lockouts.
+	MaxFailures     int64
+	LockoutDuration time.Duration
+}
+
+// DefaultPolicy is used when a deployment does not configure its own policy.
+var DefaultPolicy = Policy{
+	Window:          15 * time.Minute,
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        30 * time.Second,
+	MaxFailures:     10,
+	LockoutDuration: 15 * time.Minute,
+}
+
+// Delay returns how long to wait after the latest of the given number of
+// failures before another attempt is allowed.
+func (p Policy) Delay(failures int64) time.Duration {
+	if failures <= p.FreeAttempts || p.BaseDelay <= 0 {
+		return 0
+	}
+	// Avoid overflowing the shift, the delay is capped long before this.
+	shift := failures - p.FreeAttempts - 1
+	if shift > 30 {
+		return p.MaxDelay
+	}
+	delay := p.BaseDelay << shift
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// RetryAt returns the earliest time another attempt is allowed, given the
+// number of failures and the time of the latest one.
+func (p Policy) RetryAt(failures int64, lastFailure time.Time) time.Time {
+	return lastFailure.Add(p.Delay(failures))
+}
+
+// Locks reports whether the number of failures for a single user locks the
+// account.
+func (p Policy) Locks(failures int64) bool {
+	return p.MaxFailures > 0 && failures >= p.MaxFailures
+}
@@ -0,0 +1,52 @@
+package loginlimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/loginlimit"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delay", func(t *testing.T) {
+		t.Parallel()
+
+		p := loginlimit.Policy{
+			FreeAttempts: 2,
+			BaseDelay:    time.Second,
+			MaxDelay:     5 * time.Second,
+		}
+		require.Zero(t, p.Delay(0))
+		require.Zero(t, p.Delay(2))
+		require.Equal(t, time.Second, p.Delay(3))
+		require.Equal(t, 2*time.Second, p.Delay(4))
+		require.Equal(t, 4*time.Second, p.Delay(5))
+		require.Equal(t, 5*time.Second, p.Delay(6))
+		require.Equal(t, 5*time.Second, p.Delay(1000))
+
+		now := time.Now()
+		require.Equal(t, now.Add(2*time.Second), p.RetryAt(4, now))
+	})
+
+	t.Run("NoDelay", func(t *testing.T) {
+		t.Parallel()
+
+		p := loginlimit.Policy{MaxDelay: time.Minute}
+		require.Zero(t, p.Delay(100))
+	})
+
+	t.Run("Locks", func(t *testing.T) {
+		t.Parallel()
+
+		p := loginlimit.Policy{MaxFailures: 3}
+		require.False(t, p.Locks(2))
+		require.True(t, p.Locks(3))
+		require.True(t, p.Locks(4))
+
+		require.False(t, loginlimit.Policy{}.Locks(100))
+	})
+}
@@ -0,0 +1,355 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user login lockout
+// @ID get-user-login-lockout
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserLoginLockout
+// @Router /users/{user}/login-lockout [get]
+func (api *API) userLoginLockout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	lockout, err := api.Database.GetUserLoginLockoutByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching login lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Expired lockouts are only kept until the next successful login.
+	if !lockout.LockedUntil.After(dbtime.Now()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserLoginLockout(lockout))
+}
+
+// Lifts the user's login lockout and clears their failed login attempts.
+//
+// @Summary Unlock user login
+// @ID unlock-user-login
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/login-lockout [delete]
+func (api *API) deleteUserLoginLockout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableUserLoginLockout](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	lockout, err := api.Database.GetUserLoginLockoutByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching login lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = lockout.Auditable(user.Username)
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserLoginLockoutByUserID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		return tx.DeleteUserLoginFailures(ctx, user.ID)
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unlocking user login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// checkLoginAttempt rejects a password login while the client IP address has
+// to wait after recent failures, and while the user is locked out or has to
+// wait. Rejected attempts are not counted as failures.
+//
+// The delay of the IP address applies to every account alike, so it is
+// reported with a Retry-After header. Rejections because of the user's own
+// failures are answered with rejectMessage, the message the caller returns
+// for wrong credentials, so they don't reveal whether the account exists.
+func (api *API) checkLoginAttempt(ctx context.Context, rw http.ResponseWriter, r *http.Request, user database.User, rejectMessage string) bool {
+	logger := api.Logger.Named(userAuthLoggerName)
+	now := dbtime.Now()
+
+	//nolint:gocritic // The user is not authenticated yet.
+	stats, err := api.Database.GetLoginFailureStats(dbauthz.AsSystemRestricted(ctx), database.GetLoginFailureStatsParams{
+		UserID: user.ID,
+		Since:  now.Add(-api.LoginLimit.Window),
+		Ip:     loginAttemptIP(r.RemoteAddr),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to fetch login failure stats", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return false
+	}
+
+	if retryAt := api.LoginLimit.RetryAt(stats.IpFailures, stats.LastIpFailure); retryAt.After(now) {
+		wait := retryAt.Sub(now).Round(time.Second)
+		if wait < time.Second {
+			wait = time.Second
+		}
+		rw.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())))
+		httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+			Message: "Too many failed login attempts.",
+			Detail:  fmt.Sprintf("Try again in %s.", wait),
+		})
+		return false
+	}
+
+	if user.ID == uuid.Nil {
+		return true
+	}
+
+	//nolint:gocritic // The user is not authenticated yet.
+	lockout, err := api.Database.GetUserLoginLockoutByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		logger.Error(ctx, "unable to fetch login lockout", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return false
+	}
+	locked := err == nil && lockout.LockedUntil.After(now)
+	if locked || api.LoginLimit.RetryAt(stats.UserFailures, stats.LastUserFailure).After(now) {
+		logger.Debug(ctx, "rejected login after too many failed attempts",
+			slog.F("user_id", user.ID),
+			slog.F("locked", locked),
+		)
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: rejectMessage,
+		})
+		return false
+	}
+	return true
+}
+
+// recordLoginFailure stores a failed password login, and locks the user out
+// once they reach the policy's maximum number of failures.
+func (api *API) recordLoginFailure(ctx context.Context, r *http.Request, user database.User) {
+	var (
+		logger = api.Logger.Named(userAuthLoggerName)
+		now    = dbtime.Now()
+		ip     = loginAttemptIP(r.RemoteAddr)
+		userID = uuid.NullUUID{UUID: user.ID, Valid: user.ID != uuid.Nil}
+	)
+
+	//nolint:gocritic // The user is not authenticated yet.
+	err := api.Database.InsertUserLoginAttempt(dbauthz.AsSystemRestricted(ctx), database.InsertUserLoginAttemptParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Ip:        ip,
+		UserAgent: r.UserAgent(),
+		Success:   false,
+		CreatedAt: now,
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to record failed login attempt", slog.Error(err))
+		return
+	}
+	if !userID.Valid {
+		return
+	}
+
+	//nolint:gocritic // The user is not authenticated yet.
+	stats, err := api.Database.GetLoginFailureStats(dbauthz.AsSystemRestricted(ctx), database.GetLoginFailureStatsParams{
+		UserID: user.ID,
+		Since:  now.Add(-api.LoginLimit.Window),
+		Ip:     ip,
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to fetch login failure stats", slog.Error(err))
+		return
+	}
+	if !api.LoginLimit.Locks(stats.UserFailures) {
+		return
+	}
+
+	//nolint:gocritic // The user is not authenticated yet.
+	lockout, err := api.Database.UpsertUserLoginLockout(dbauthz.AsSystemRestricted(ctx), database.UpsertUserLoginLockoutParams{
+		UserID:         user.ID,
+		FailedAttempts: int32(stats.UserFailures),
+		CreatedAt:      now,
+		LockedUntil:    now.Add(api.LoginLimit.LockoutDuration),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to lock out user", slog.Error(err))
+		return
+	}
+	logger.Warn(ctx, "locked out user after too many failed login attempts",
+		slog.F("user_id", user.ID),
+		slog.F("username", user.Username),
+		slog.F("failed_attempts", lockout.FailedAttempts),
+		slog.F("locked_until", lockout.LockedUntil),
+	)
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.AuditableUserLoginLockout]{
+		Audit:     *api.Auditor.Load(),
+		Log:       api.Logger,
+		UserID:    user.ID,
+		RequestID: httpmw.RequestID(r),
+		IP:        r.RemoteAddr,
+		Action:    database.AuditActionCreate,
+		New:       lockout.Auditable(user.Username),
+		Status:    http.StatusOK,
+	})
+}
+
+// resetLoginFailures clears the user's failed logins and any expired lockout
+// after a successful login.
+func (api *API) resetLoginFailures(ctx context.Context, user database.User) {
+	logger := api.Logger.Named(userAuthLoggerName)
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	err := api.Database.DeleteUserLoginFailures(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to reset failed login attempts", slog.Error(err))
+	}
+	//nolint:gocritic // The user is not fully logged in yet.
+	err = api.Database.DeleteUserLoginLockoutByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to delete expired login lockout", slog.Error(err))
+	}
+}
+
+// recordLoginSuccess stores a successful password login. It returns audit log
+// fields flagging an IP address or user agent the user has not logged in from
+// before, or nil if neither is new or this is their first recorded login.
+func (api *API) recordLoginSuccess(ctx context.Context, r *http.Request, user database.User) json.RawMessage {
+	var (
+		logger = api.Logger.Named(userAuthLoggerName)
+		ip     = loginAttemptIP(r.RemoteAddr)
+	)
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	history, err := api.Database.GetUserLoginHistorySummary(dbauthz.AsSystemRestricted(ctx), database.GetUserLoginHistorySummaryParams{
+		Ip:        ip,
+		UserAgent: r.UserAgent(),
+		UserID:    user.ID,
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to fetch login history", slog.Error(err))
+	}
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	err = api.Database.InsertUserLoginAttempt(dbauthz.AsSystemRestricted(ctx), database.InsertUserLoginAttemptParams{
+		ID:        uuid.New(),
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		Ip:        ip,
+		UserAgent: r.UserAgent(),
+		Success:   true,
+		CreatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to record login attempt", slog.Error(err))
+	}
+
+	if !history.HasLogins || (history.SeenIp && history.SeenUserAgent) {
+		return nil
+	}
+	logger.Warn(ctx, "user logged in from a new device",
+		slog.F("user_id", user.ID),
+		slog.F("username", user.Username),
+		slog.F("ip", r.RemoteAddr),
+		slog.F("user_agent", r.UserAgent()),
+		slog.F("new_ip", !history.SeenIp),
+		slog.F("new_user_agent", !history.SeenUserAgent),
+	)
+	fields, err := json.Marshal(audit.LoginFields{
+		NewIP:        !history.SeenIp,
+		NewUserAgent: !history.SeenUserAgent,
+	})
+	if err != nil {
+		logger.Error(ctx, "unable to marshal login audit fields", slog.Error(err))
+		return nil
+	}
+	return fields
+}
+
+// loginAttemptIP converts the request's remote address to an inet. Unknown
+// addresses are stored as the unspecified address so they are still throttled
+// together.
+func loginAttemptIP(remoteAddr string) pqtype.Inet {
+	ip := net.ParseIP(remoteAddr)
+	if ip == nil {
+		ip = net.IPv4zero
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+		},
+		Valid: true,
+	}
+}
+
+func convertUserLoginLockout(lockout database.UserLoginLockout) codersdk.UserLoginLockout {
+	return codersdk.UserLoginLockout{
+		UserID:         lockout.UserID,
+		FailedAttempts: lockout.FailedAttempts,
+		CreatedAt:      lockout.CreatedAt,
+		LockedUntil:    lockout.LockedUntil,
+	}
+}
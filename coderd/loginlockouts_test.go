@@ -0,0 +1,233 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/loginlimit"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLoginLockout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("LockAndUnlock", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor: auditor,
+			LoginLimit: loginlimit.Policy{
+				Window:          time.Hour,
+				MaxFailures:     2,
+				LockoutDuration: time.Hour,
+			},
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anonClient := codersdk.New(client.URL)
+
+		// No lockout before any failures.
+		_, err := userAdmin.UserLoginLockout(ctx, user.Username)
+		requireStatus(t, err, http.StatusNotFound)
+
+		for i := 0; i < 2; i++ {
+			_, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    user.Email,
+				Password: "wrong-password",
+			})
+			requireStatus(t, err, http.StatusUnauthorized)
+		}
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType:   database.ResourceTypeUserLoginLockout,
+			ResourceID:     user.ID,
+			ResourceTarget: user.Username,
+			Action:         database.AuditActionCreate,
+		}))
+
+		lockout, err := userAdmin.UserLoginLockout(ctx, user.Username)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, lockout.UserID)
+		require.EqualValues(t, 2, lockout.FailedAttempts)
+		require.True(t, lockout.LockedUntil.After(time.Now()))
+
+		// The correct password is rejected while locked out, exactly like a
+		// login to an account that doesn't exist.
+		_, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		var lockedErr *codersdk.Error
+		require.ErrorAs(t, err, &lockedErr)
+		_, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "nobody@coder.com",
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		var unknownErr *codersdk.Error
+		require.ErrorAs(t, err, &unknownErr)
+		require.Equal(t, http.StatusUnauthorized, lockedErr.StatusCode())
+		require.Equal(t, unknownErr.StatusCode(), lockedErr.StatusCode())
+		require.Equal(t, unknownErr.Response, lockedErr.Response)
+
+		// A locked out user cannot unlock themselves from another session.
+		err = member.UnlockUserLogin(ctx, codersdk.Me)
+		requireStatus(t, err, http.StatusForbidden)
+
+		err = userAdmin.UnlockUserLogin(ctx, user.Username)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeUserLoginLockout,
+			ResourceID:   user.ID,
+			Action:       database.AuditActionDelete,
+		}))
+
+		_, err = userAdmin.UserLoginLockout(ctx, user.Username)
+		requireStatus(t, err, http.StatusNotFound)
+		_, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("LockTOTP", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LoginLimit: loginlimit.Policy{
+				Window:          time.Hour,
+				MaxFailures:     2,
+				LockoutDuration: time.Hour,
+			},
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anonClient := codersdk.New(client.URL)
+
+		enrollment, err := member.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, time.Now()),
+		})
+		require.NoError(t, err)
+
+		login, err := anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, login.TOTPTicket)
+
+		var wrongErr *codersdk.Error
+		for i := 0; i < 2; i++ {
+			_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+				Ticket: login.TOTPTicket,
+				Code:   "abcdef",
+			})
+			require.ErrorAs(t, err, &wrongErr)
+		}
+
+		// The correct code is rejected while locked out, exactly like a
+		// wrong code.
+		_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   totpCode(t, enrollment.Secret, time.Now().Add(userpassword.TOTPPeriod)),
+		})
+		var lockedErr *codersdk.Error
+		require.ErrorAs(t, err, &lockedErr)
+		require.Equal(t, http.StatusUnauthorized, lockedErr.StatusCode())
+		require.Equal(t, wrongErr.Response, lockedErr.Response)
+	})
+
+	t.Run("Throttle", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LoginLimit: loginlimit.Policy{
+				Window:       time.Hour,
+				FreeAttempts: 1,
+				BaseDelay:    time.Hour,
+				MaxDelay:     time.Hour,
+			},
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anonClient := codersdk.New(client.URL)
+		for i := 0; i < 2; i++ {
+			_, err := anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    user.Email,
+				Password: "wrong-password",
+			})
+			requireStatus(t, err, http.StatusUnauthorized)
+		}
+
+		// Even the correct password has to wait.
+		_, err := anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		requireStatus(t, err, http.StatusTooManyRequests)
+
+		// Failures for unknown users are throttled by IP address.
+		_, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "nobody@coder.com",
+			Password: "wrong-password",
+		})
+		requireStatus(t, err, http.StatusTooManyRequests)
+	})
+
+	t.Run("NewDevice", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		login := func(userAgent string) audit.LoginFields {
+			t.Helper()
+			auditor.ResetLogs()
+			res, err := client.Request(ctx, http.MethodPost, "/api/v2/users/login", codersdk.LoginWithPasswordRequest{
+				Email:    user.Email,
+				Password: coderdtest.FirstUserParams.Password,
+			}, func(r *http.Request) {
+				r.Header.Set("User-Agent", userAgent)
+			})
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusCreated, res.StatusCode)
+
+			logs := auditor.AuditLogs()
+			require.Len(t, logs, 1)
+			require.Equal(t, database.AuditActionLogin, logs[0].Action)
+			var fields audit.LoginFields
+			require.NoError(t, json.Unmarshal(logs[0].AdditionalFields, &fields))
+			return fields
+		}
+
+		// CreateAnotherUser has already logged in with the default user agent.
+		require.Equal(t, audit.LoginFields{NewUserAgent: true}, login("other-agent"))
+		require.Equal(t, audit.LoginFields{}, login("other-agent"))
+	})
+}
+
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, status, apiErr.StatusCode())
+}
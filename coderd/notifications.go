@@ -0,0 +1,151 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user notification preferences
+// @ID get-user-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [get]
+func (api *API) userNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	prefs, err := userNotificationPreferences(ctx, api.Database, user)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, prefs)
+}
+
+// @Summary Update user notification preferences
+// @ID update-user-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserNotificationPreferences true "Preferences to update"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [put]
+func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.UpdateUserNotificationPreferences
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validationErrs []codersdk.ValidationError
+	for name := range req.TemplateDisabledMap {
+		if _, ok := notifications.LookupTemplate(name); !ok {
+			validationErrs = append(validationErrs, codersdk.ValidationError{
+				Field:  "template_disabled_map",
+				Detail: fmt.Sprintf("Unknown notification template %q.", name),
+			})
+		}
+	}
+	if len(validationErrs) > 0 {
+		sort.Slice(validationErrs, func(i, j int) bool {
+			return validationErrs[i].Detail < validationErrs[j].Detail
+		})
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid notification preferences.",
+			Validations: validationErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		for name, disabled := range req.TemplateDisabledMap {
+			_, err := tx.UpsertUserNotificationPreference(ctx, database.UpsertUserNotificationPreferenceParams{
+				UserID:    user.ID,
+				Template:  name,
+				Disabled:  disabled,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return xerrors.Errorf("upsert preference %q: %w", name, err)
+			}
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	prefs, err := userNotificationPreferences(ctx, api.Database, user)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, prefs)
+}
+
+// userNotificationPreferences lists every notification template along with
+// whether the user has disabled it.
+func userNotificationPreferences(ctx context.Context, db database.Store, user database.User) ([]codersdk.NotificationPreference, error) {
+	stored, err := db.GetUserNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get user notification preferences: %w", err)
+	}
+	disabled := make(map[string]bool, len(stored))
+	for _, pref := range stored {
+		disabled[pref.Template] = pref.Disabled
+	}
+
+	templates := notifications.Templates()
+	prefs := make([]codersdk.NotificationPreference, 0, len(templates))
+	for _, tmpl := range templates {
+		prefs = append(prefs, codersdk.NotificationPreference{
+			Template:    tmpl.Name,
+			Description: tmpl.Description,
+			Disabled:    disabled[tmpl.Name],
+		})
+	}
+	return prefs, nil
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// StoreEnqueuer renders notifications and stores them in the database for
+// a Manager to deliver.
+type StoreEnqueuer struct {
+	store     database.Store
+	method    database.NotificationMethod
+	accessURL *url.URL
+	log       slog.Logger
+}
+
+// NewStoreEnqueuer creates an Enqueuer that queues messages for delivery
+// with the given method.
+func NewStoreEnqueuer(method database.NotificationMethod, store database.Store, accessURL *url.URL, log slog.Logger) (*StoreEnqueuer, error) {
+	if !method.Valid() {
+		return nil, xerrors.Errorf("invalid notification method %q", method)
+	}
+	return &StoreEnqueuer{
+		store:     store,
+		method:    method,
+		accessURL: accessURL,
+		log:       log.Named("notifications_enqueuer"),
+	}, nil
+}
+
+func (s *StoreEnqueuer) Enqueue(ctx context.Context, userID uuid.UUID, templateName string, labels map[string]string, createdBy string) (*uuid.UUID, error) {
+	tmpl, ok := LookupTemplate(templateName)
+	if !ok {
+		return nil, xerrors.Errorf("%w: %q", ErrUnknownTemplate, templateName)
+	}
+
+	// Notifications are raised by background jobs and API handlers alike,
+	// on behalf of users that are not necessarily the caller.
+	//nolint:gocritic // The notification system reads user data as the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	preferences, err := s.store.GetUserNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf("get notification preferences: %w", err)
+	}
+	for _, preference := range preferences {
+		if preference.Template == templateName && preference.Disabled {
+			s.log.Debug(ctx, "user opted out of notification",
+				slog.F("user_id", userID), slog.F("template", templateName))
+			return nil, nil
+		}
+	}
+
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf("get user: %w", err)
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	title, body, err := tmpl.render(templateData{
+		UserName:  user.Username,
+		AccessURL: strings.TrimSuffix(s.accessURL.String(), "/"),
+		Labels:    labels,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("render template %q: %w", templateName, err)
+	}
+
+	payload, err := json.Marshal(MessagePayload{
+		Version:   MessagePayloadVersion,
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		UserName:  user.Username,
+		Template:  templateName,
+		Title:     title,
+		Body:      body,
+		Labels:    labels,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	msg, err := s.store.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Template:  templateName,
+		Method:    s.method,
+		Payload:   payload,
+		CreatedBy: createdBy,
+		CreatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("enqueue notification: %w", err)
+	}
+
+	s.log.Debug(ctx, "enqueued notification",
+		slog.F("msg_id", msg.ID), slog.F("user_id", userID), slog.F("template", templateName))
+	return &msg.ID, nil
+}
@@ -0,0 +1,198 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// batchSize is the number of messages leased and delivered concurrently
+// on every fetch.
+const batchSize = 10
+
+// Handler delivers a single message with one delivery method.
+type Handler interface {
+	// Dispatch delivers the message. If it fails, retryable reports whether
+	// a later attempt might succeed.
+	Dispatch(ctx context.Context, msgID uuid.UUID, payload MessagePayload) (retryable bool, err error)
+}
+
+// Manager delivers queued notifications with the configured method.
+type Manager struct {
+	cfg     codersdk.NotificationsConfig
+	store   database.Store
+	method  database.NotificationMethod
+	handler Handler
+	log     slog.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewManager creates a Manager for the configured delivery method. It
+// returns an error if the method is not configured.
+func NewManager(cfg codersdk.NotificationsConfig, store database.Store, log slog.Logger) (*Manager, error) {
+	method, err := ParseMethod(cfg.Method.String())
+	if err != nil {
+		return nil, err
+	}
+	log = log.Named("notifications")
+
+	var handler Handler
+	switch method {
+	case database.NotificationMethodSmtp:
+		handler, err = NewSMTPHandler(cfg.SMTP, log)
+	case database.NotificationMethodWebhook:
+		handler, err = NewWebhookHandler(cfg.Webhook, log)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("configure %s delivery: %w", method, err)
+	}
+
+	return &Manager{
+		cfg:     cfg,
+		store:   store,
+		method:  method,
+		handler: handler,
+		log:     log,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Method returns the delivery method messages are dispatched with.
+func (m *Manager) Method() database.NotificationMethod {
+	return m.method
+}
+
+// Run starts delivering messages in the background until Close is called.
+func (m *Manager) Run(ctx context.Context) {
+	//nolint:gocritic // The notification system operates on every user's messages.
+	ctx, m.cancel = context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.cfg.FetchInterval.Value())
+		defer ticker.Stop()
+		for {
+			err := m.processBatch(ctx)
+			if err != nil && !xerrors.Is(err, context.Canceled) {
+				m.log.Error(ctx, "process notification batch", slog.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops delivering messages and waits for in-flight deliveries to
+// finish. Messages leased but not delivered are picked up again once their
+// lease expires.
+func (m *Manager) Close() error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	<-m.done
+	return nil
+}
+
+func (m *Manager) processBatch(ctx context.Context) error {
+	now := dbtime.Now()
+	msgs, err := m.store.AcquireNotificationMessages(ctx, database.AcquireNotificationMessagesParams{
+		Now: now,
+		// A lease outlives the longest possible delivery, so no other
+		// replica picks a message up while it is still being sent.
+		LeasedUntil:     sql.NullTime{Time: now.Add(2 * m.cfg.DispatchTimeout.Value()), Valid: true},
+		Method:          m.method,
+		MaxAttemptCount: int32(m.cfg.MaxSendAttempts.Value()),
+		Count:           batchSize,
+	})
+	if err != nil {
+		return xerrors.Errorf("acquire messages: %w", err)
+	}
+
+	var eg errgroup.Group
+	for _, msg := range msgs {
+		msg := msg
+		eg.Go(func() error {
+			m.deliver(ctx, msg)
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+func (m *Manager) deliver(ctx context.Context, msg database.NotificationMessage) {
+	log := m.log.With(slog.F("msg_id", msg.ID), slog.F("template", msg.Template), slog.F("attempt", msg.AttemptCount+1))
+
+	var (
+		retryable bool
+		err       error
+	)
+	payload, err := decodePayload(msg)
+	if err == nil {
+		dispatchCtx, cancel := context.WithTimeout(ctx, m.cfg.DispatchTimeout.Value())
+		retryable, err = m.handler.Dispatch(dispatchCtx, msg.ID, payload)
+		cancel()
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the message is retried when its lease expires.
+		return
+	}
+
+	now := dbtime.Now()
+	if err == nil {
+		log.Debug(ctx, "notification delivered")
+		err = m.store.MarkNotificationMessageSent(ctx, database.MarkNotificationMessageSentParams{
+			ID:     msg.ID,
+			SentAt: now,
+		})
+		if err != nil {
+			log.Error(ctx, "mark notification sent", slog.Error(err))
+		}
+		return
+	}
+
+	attempts := msg.AttemptCount + 1
+	params := database.MarkNotificationMessageFailedParams{
+		ID:           msg.ID,
+		Status:       database.NotificationMessageStatusPermanentFailure,
+		StatusReason: sql.NullString{String: err.Error(), Valid: true},
+		UpdatedAt:    now,
+	}
+	if retryable && int64(attempts) < m.cfg.MaxSendAttempts.Value() {
+		params.Status = database.NotificationMessageStatusTemporaryFailure
+		params.NextRetryAfter = sql.NullTime{Time: now.Add(m.retryBackoff(attempts)), Valid: true}
+	}
+	log.Warn(ctx, "notification delivery failed",
+		slog.F("status", params.Status), slog.F("next_retry_after", params.NextRetryAfter.Time), slog.Error(err))
+
+	err = m.store.MarkNotificationMessageFailed(ctx, params)
+	if err != nil {
+		log.Error(ctx, "mark notification failed", slog.Error(err))
+	}
+}
+
+// retryBackoff returns the delay before the next attempt after the given
+// number of failed attempts.
+func (m *Manager) retryBackoff(attempts int32) time.Duration {
+	// Cap the exponent so a large max attempt count can't overflow.
+	exponent := min(attempts-1, 10)
+	return m.cfg.RetryInterval.Value() * time.Duration(1<<exponent)
+}
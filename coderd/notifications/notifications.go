@@ -0,0 +1,84 @@
+// Package notifications queues messages for users about events that happen
+// while they are away, such as their workspace being marked dormant, and
+// delivers them by email or webhook.
+//
+// Messages are rendered from a fixed set of templates when they are
+// enqueued and stored in the database. A Manager running in coderd leases
+// batches of queued messages, hands each one to the configured delivery
+// method and records the outcome, retrying transient failures with an
+// exponential backoff.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// ErrUnknownTemplate is returned when enqueueing a message for a template
+// that does not exist.
+var ErrUnknownTemplate = xerrors.New("unknown notification template")
+
+// Enqueuer queues notifications for delivery.
+type Enqueuer interface {
+	// Enqueue renders the named template with the given labels and queues
+	// the result for delivery to the user. createdBy identifies the
+	// component that raised the notification. The returned ID is nil if
+	// the user has opted out of the template.
+	Enqueue(ctx context.Context, userID uuid.UUID, templateName string, labels map[string]string, createdBy string) (*uuid.UUID, error)
+}
+
+// NoopEnqueuer discards all notifications. It is used when no delivery
+// method is configured.
+type NoopEnqueuer struct{}
+
+// NewNoopEnqueuer returns an Enqueuer that discards all notifications.
+func NewNoopEnqueuer() *NoopEnqueuer {
+	return &NoopEnqueuer{}
+}
+
+func (*NoopEnqueuer) Enqueue(context.Context, uuid.UUID, string, map[string]string, string) (*uuid.UUID, error) {
+	return nil, nil
+}
+
+// MessagePayloadVersion is bumped whenever the shape of MessagePayload
+// changes in a way that delivery methods need to be aware of.
+const MessagePayloadVersion = "1.0"
+
+// MessagePayload is the rendered message stored in the queue. It holds
+// everything needed to deliver the message, so delivery does not depend on
+// the state of the user or workspace at the time it is sent.
+type MessagePayload struct {
+	Version string `json:"_version"`
+
+	UserID    uuid.UUID `json:"user_id"`
+	UserEmail string    `json:"user_email"`
+	UserName  string    `json:"user_name"`
+
+	Template string            `json:"template"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// ParseMethod validates a delivery method name from the deployment config.
+func ParseMethod(method string) (database.NotificationMethod, error) {
+	m := database.NotificationMethod(method)
+	if !m.Valid() {
+		return "", xerrors.Errorf("invalid notification method %q, must be one of %v", method, database.AllNotificationMethodValues())
+	}
+	return m, nil
+}
+
+func decodePayload(msg database.NotificationMessage) (MessagePayload, error) {
+	var payload MessagePayload
+	err := json.Unmarshal(msg.Payload, &payload)
+	if err != nil {
+		return MessagePayload{}, xerrors.Errorf("decode payload: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,296 @@
+package notifications_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestStoreEnqueuer(t *testing.T) {
+	t.Parallel()
+
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+
+	t.Run("Enqueue", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		user := dbgen.User(t, db, database.User{Username: "alice", Email: "alice@example.com"})
+		enq, err := notifications.NewStoreEnqueuer(database.NotificationMethodSmtp, db, accessURL, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		id, err := enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceDormant, map[string]string{
+			"workspace": "dev",
+			"reason":    "it was not used for 7 days",
+		}, "test")
+		require.NoError(t, err)
+		require.NotNil(t, id)
+
+		msgs := pendingMessages(t, db)
+		require.Len(t, msgs, 1)
+		require.Equal(t, *id, msgs[0].ID)
+		require.Equal(t, database.NotificationMethodSmtp, msgs[0].Method)
+		require.Equal(t, "test", msgs[0].CreatedBy)
+
+		var payload notifications.MessagePayload
+		require.NoError(t, json.Unmarshal(msgs[0].Payload, &payload))
+		require.Equal(t, "alice@example.com", payload.UserEmail)
+		require.Equal(t, `Workspace "dev" marked as dormant`, payload.Title)
+		require.Contains(t, payload.Body, "because it was not used for 7 days")
+		require.Contains(t, payload.Body, "https://coder.example.com/@alice/dev")
+	})
+
+	t.Run("OptedOut", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		user := dbgen.User(t, db, database.User{})
+		_, err := db.UpsertUserNotificationPreference(ctx, database.UpsertUserNotificationPreferenceParams{
+			UserID:    user.ID,
+			Template:  notifications.TemplateUserAccountSuspended,
+			Disabled:  true,
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		enq, err := notifications.NewStoreEnqueuer(database.NotificationMethodSmtp, db, accessURL, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		id, err := enq.Enqueue(ctx, user.ID, notifications.TemplateUserAccountSuspended, map[string]string{
+			"initiator": "admin",
+		}, "test")
+		require.NoError(t, err)
+		require.Nil(t, id)
+		require.Empty(t, pendingMessages(t, db))
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		user := dbgen.User(t, db, database.User{})
+		enq, err := notifications.NewStoreEnqueuer(database.NotificationMethodSmtp, db, accessURL, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		_, err = enq.Enqueue(ctx, user.ID, "does-not-exist", nil, "test")
+		require.ErrorIs(t, err, notifications.ErrUnknownTemplate)
+	})
+
+	t.Run("MissingLabel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		user := dbgen.User(t, db, database.User{})
+		enq, err := notifications.NewStoreEnqueuer(database.NotificationMethodSmtp, db, accessURL, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		_, err = enq.Enqueue(ctx, user.ID, notifications.TemplateWorkspaceDeleted, map[string]string{
+			"workspace": "dev",
+		}, "test")
+		require.ErrorContains(t, err, "reason")
+	})
+}
+
+func TestManager_Webhook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RetriesUntilDelivered", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls    atomic.Int32
+			received = make(chan notifications.WebhookPayload, 1)
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Fail the first two attempts.
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var payload notifications.WebhookPayload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			received <- payload
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, user := setupManager(t, webhookConfig(t, srv.URL))
+		msgID := enqueue(t, db, user.ID, database.NotificationMethodWebhook)
+
+		var payload notifications.WebhookPayload
+		select {
+		case payload = <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for webhook")
+		}
+		require.Equal(t, msgID, payload.MsgID)
+		require.Equal(t, user.Email, payload.Payload.UserEmail)
+		require.Equal(t, notifications.TemplateUserAccountSuspended, payload.Payload.Template)
+
+		msg := waitForStatus(t, db, database.NotificationMessageStatusSent)
+		require.EqualValues(t, 3, msg.AttemptCount)
+		require.True(t, msg.SentAt.Valid)
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			http.Error(w, "no such channel", http.StatusNotFound)
+		}))
+		t.Cleanup(srv.Close)
+
+		db, user := setupManager(t, webhookConfig(t, srv.URL))
+		enqueue(t, db, user.ID, database.NotificationMethodWebhook)
+
+		msg := waitForStatus(t, db, database.NotificationMessageStatusPermanentFailure)
+		require.EqualValues(t, 1, msg.AttemptCount)
+		require.Contains(t, msg.StatusReason.String, "no such channel")
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(srv.Close)
+
+		cfg := webhookConfig(t, srv.URL)
+		cfg.MaxSendAttempts = 3
+		db, user := setupManager(t, cfg)
+		enqueue(t, db, user.ID, database.NotificationMethodWebhook)
+
+		msg := waitForStatus(t, db, database.NotificationMessageStatusPermanentFailure)
+		require.EqualValues(t, 3, msg.AttemptCount)
+		require.EqualValues(t, 3, calls.Load())
+	})
+}
+
+func TestNewManager(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, nil)
+
+	cfg := codersdk.NotificationsConfig{Method: "pigeon"}
+	_, err := notifications.NewManager(cfg, db, logger)
+	require.ErrorContains(t, err, "invalid notification method")
+
+	cfg = codersdk.NotificationsConfig{Method: "webhook"}
+	_, err = notifications.NewManager(cfg, db, logger)
+	require.ErrorContains(t, err, "endpoint must be set")
+
+	cfg = codersdk.NotificationsConfig{Method: "smtp"}
+	_, err = notifications.NewManager(cfg, db, logger)
+	require.ErrorContains(t, err, "smarthost")
+}
+
+func webhookConfig(t *testing.T, endpoint string) codersdk.NotificationsConfig {
+	t.Helper()
+
+	u, err := url.Parse(endpoint)
+	require.NoError(t, err)
+	return codersdk.NotificationsConfig{
+		Method:          "webhook",
+		DispatchTimeout: serpent.Duration(testutil.WaitShort),
+		FetchInterval:   serpent.Duration(10 * time.Millisecond),
+		MaxSendAttempts: 5,
+		RetryInterval:   serpent.Duration(time.Millisecond),
+		Webhook: codersdk.NotificationsWebhookConfig{
+			Endpoint: serpent.URL(*u),
+		},
+	}
+}
+
+// setupManager starts a Manager with the given configuration and returns
+// its database along with a user to send notifications to.
+func setupManager(t *testing.T, cfg codersdk.NotificationsConfig) (database.Store, database.User) {
+	t.Helper()
+
+	db := dbmem.New()
+	user := dbgen.User(t, db, database.User{})
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	mgr, err := notifications.NewManager(cfg, db, logger)
+	require.NoError(t, err)
+	mgr.Run(testutil.Context(t, testutil.WaitLong))
+	t.Cleanup(func() {
+		require.NoError(t, mgr.Close())
+	})
+	return db, user
+}
+
+func enqueue(t *testing.T, db database.Store, userID uuid.UUID, method database.NotificationMethod) uuid.UUID {
+	t.Helper()
+
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+	enq, err := notifications.NewStoreEnqueuer(method, db, accessURL, slogtest.Make(t, nil))
+	require.NoError(t, err)
+	id, err := enq.Enqueue(testutil.Context(t, testutil.WaitShort), userID, notifications.TemplateUserAccountSuspended, map[string]string{
+		"initiator": "admin",
+	}, "test")
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	return *id
+}
+
+func pendingMessages(t *testing.T, db database.Store) []database.NotificationMessage {
+	t.Helper()
+
+	msgs, err := db.GetNotificationMessagesByStatus(testutil.Context(t, testutil.WaitShort), database.GetNotificationMessagesByStatusParams{
+		Status:   database.NotificationMessageStatusPending,
+		LimitOpt: 10,
+	})
+	require.NoError(t, err)
+	return msgs
+}
+
+func waitForStatus(t *testing.T, db database.Store, status database.NotificationMessageStatus) database.NotificationMessage {
+	t.Helper()
+
+	var msg database.NotificationMessage
+	require.Eventually(t, func() bool {
+		msgs, err := db.GetNotificationMessagesByStatus(testutil.Context(t, testutil.WaitShort), database.GetNotificationMessagesByStatusParams{
+			Status:   status,
+			LimitOpt: 1,
+		})
+		if err != nil || len(msgs) == 0 {
+			return false
+		}
+		msg = msgs[0]
+		return true
+	}, testutil.WaitLong, testutil.IntervalFast)
+	return msg
+}
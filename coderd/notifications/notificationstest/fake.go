@@ -0,0 +1,53 @@
+package notificationstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Notification is a notification recorded by FakeEnqueuer.
+type Notification struct {
+	UserID       uuid.UUID
+	TemplateName string
+	Labels       map[string]string
+	CreatedBy    string
+}
+
+// FakeEnqueuer records notifications instead of queuing them.
+type FakeEnqueuer struct {
+	mu   sync.Mutex
+	sent []Notification
+}
+
+func (f *FakeEnqueuer) Enqueue(_ context.Context, userID uuid.UUID, templateName string, labels map[string]string, createdBy string) (*uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, Notification{
+		UserID:       userID,
+		TemplateName: templateName,
+		Labels:       labels,
+		CreatedBy:    createdBy,
+	})
+	id := uuid.New()
+	return &id, nil
+}
+
+// Sent returns the notifications recorded so far, optionally filtered to
+// those using one of the given templates.
+func (f *FakeEnqueuer) Sent(templateNames ...string) []Notification {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []Notification
+	for _, n := range f.sent {
+		if len(templateNames) > 0 && !slices.Contains(templateNames, n.TemplateName) {
+			continue
+		}
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// SMTPHandler delivers notifications by email.
+type SMTPHandler struct {
+	cfg  codersdk.NotificationsEmailConfig
+	from *mail.Address
+	log  slog.Logger
+}
+
+// NewSMTPHandler validates the email configuration and returns a Handler
+// that sends mail through the configured smarthost.
+func NewSMTPHandler(cfg codersdk.NotificationsEmailConfig, log slog.Logger) (*SMTPHandler, error) {
+	if cfg.Smarthost.Host == "" || cfg.Smarthost.Port == "" {
+		return nil, xerrors.New("smarthost must be set as host:port")
+	}
+	from, err := mail.ParseAddress(cfg.From.String())
+	if err != nil {
+		return nil, xerrors.Errorf("parse from address %q: %w", cfg.From.String(), err)
+	}
+	return &SMTPHandler{
+		cfg:  cfg,
+		from: from,
+		log:  log.Named("smtp"),
+	}, nil
+}
+
+func (s *SMTPHandler) Dispatch(ctx context.Context, msgID uuid.UUID, payload MessagePayload) (bool, error) {
+	if payload.UserEmail == "" {
+		return false, xerrors.New("user has no email address")
+	}
+
+	host := s.cfg.Smarthost.Host
+	tlsConfig := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, s.cfg.Smarthost.Port))
+	if err != nil {
+		return true, xerrors.Errorf("dial smarthost: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if s.cfg.ForceTLS.Value() {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return smtpRetryable(err), xerrors.Errorf("connect to smarthost: %w", err)
+	}
+	defer c.Close()
+
+	err = c.Hello(s.cfg.Hello.String())
+	if err != nil {
+		return smtpRetryable(err), xerrors.Errorf("hello: %w", err)
+	}
+	if !s.cfg.ForceTLS.Value() {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			err = c.StartTLS(tlsConfig)
+			if err != nil {
+				return smtpRetryable(err), xerrors.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	if s.cfg.Username.String() != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return false, xerrors.New("smarthost does not support authentication")
+		}
+		err = c.Auth(smtp.PlainAuth("", s.cfg.Username.String(), s.cfg.Password.String(), host))
+		if err != nil {
+			return smtpRetryable(err), xerrors.Errorf("authenticate: %w", err)
+		}
+	}
+
+	err = c.Mail(s.from.Address)
+	if err != nil {
+		return smtpRetryable(err), xerrors.Errorf("mail from: %w", err)
+	}
+	err = c.Rcpt(payload.UserEmail)
+	if err != nil {
+		return smtpRetryable(err), xerrors.Errorf("rcpt to: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpRetryable(err), xerrors.Errorf("data: %w", err)
+	}
+	_, err = w.Write(s.message(msgID, payload))
+	if err != nil {
+		return true, xerrors.Errorf("write message: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return smtpRetryable(err), xerrors.Errorf("send message: %w", err)
+	}
+
+	// The message was accepted, so a failure to quit cleanly doesn't matter.
+	_ = c.Quit()
+	return false, nil
+}
+
+func (s *SMTPHandler) message(msgID uuid.UUID, payload MessagePayload) []byte {
+	to := mail.Address{Name: payload.UserName, Address: payload.UserEmail}
+
+	var sb strings.Builder
+	header := func(key, value string) {
+		_, _ = fmt.Fprintf(&sb, "%s: %s\r\n", key, value)
+	}
+	header("From", s.from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", payload.Title))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-Id", fmt.Sprintf("<%s@%s>", msgID, s.cfg.Hello.String()))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	sb.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&sb)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(payload.Body, "\n", "\r\n")))
+	_ = qp.Close()
+	return []byte(sb.String())
+}
+
+// smtpRetryable reports whether a failed SMTP exchange is worth retrying.
+// Servers reply with 4xx codes for transient failures and 5xx codes for
+// permanent ones. Errors without a reply code come from the connection.
+func smtpRetryable(err error) bool {
+	var protoErr *textproto.Error
+	if xerrors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+	return true
+}
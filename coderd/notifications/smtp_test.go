@@ -0,0 +1,253 @@
+package notifications_test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestSMTPHandler(t *testing.T) {
+	t.Parallel()
+
+	payload := notifications.MessagePayload{
+		UserEmail: "bob@example.com",
+		UserName:  "bob",
+		Title:     `Workspace "dev" deleted`,
+		Body:      "Hi bob,\n\nYour workspace \"dev\" was deleted.",
+	}
+
+	t.Run("Send", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newSMTPStub(t, "")
+		handler, err := notifications.NewSMTPHandler(srv.config("coder@example.com"), slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		msgID := uuid.New()
+		retryable, err := handler.Dispatch(testutil.Context(t, testutil.WaitShort), msgID, payload)
+		require.NoError(t, err)
+		require.False(t, retryable)
+
+		mails := srv.received()
+		require.Len(t, mails, 1)
+		require.Equal(t, "coder@example.com", mails[0].from)
+		require.Equal(t, []string{"bob@example.com"}, mails[0].to)
+
+		msg, err := mail.ReadMessage(strings.NewReader(mails[0].data))
+		require.NoError(t, err)
+		require.Equal(t, `"bob" <bob@example.com>`, msg.Header.Get("To"))
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		require.Equal(t, payload.Title, subject)
+		require.Contains(t, msg.Header.Get("Message-Id"), msgID.String())
+		require.Contains(t, mails[0].data, "was deleted.")
+	})
+
+	t.Run("Auth", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newSMTPStub(t, "")
+		cfg := srv.config("coder@example.com")
+		cfg.Username = "coder"
+		cfg.Password = "hunter2"
+		handler, err := notifications.NewSMTPHandler(cfg, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		_, err = handler.Dispatch(testutil.Context(t, testutil.WaitShort), uuid.New(), payload)
+		require.NoError(t, err)
+		require.Equal(t, "\x00coder\x00hunter2", srv.auth())
+	})
+
+	t.Run("TemporaryRejection", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newSMTPStub(t, "450 mailbox busy")
+		handler, err := notifications.NewSMTPHandler(srv.config("coder@example.com"), slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		retryable, err := handler.Dispatch(testutil.Context(t, testutil.WaitShort), uuid.New(), payload)
+		require.ErrorContains(t, err, "mailbox busy")
+		require.True(t, retryable)
+	})
+
+	t.Run("PermanentRejection", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newSMTPStub(t, "550 no such user")
+		handler, err := notifications.NewSMTPHandler(srv.config("coder@example.com"), slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		retryable, err := handler.Dispatch(testutil.Context(t, testutil.WaitShort), uuid.New(), payload)
+		require.ErrorContains(t, err, "no such user")
+		require.False(t, retryable)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		// Grab a free port and close it again so nothing is listening.
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		var hp serpent.HostPort
+		require.NoError(t, hp.Set(addr))
+		handler, err := notifications.NewSMTPHandler(codersdk.NotificationsEmailConfig{
+			From:      "coder@example.com",
+			Smarthost: hp,
+			Hello:     "localhost",
+		}, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		retryable, err := handler.Dispatch(testutil.Context(t, testutil.WaitShort), uuid.New(), payload)
+		require.Error(t, err)
+		require.True(t, retryable)
+	})
+}
+
+type stubMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStub is a minimal SMTP server that records the mail it accepts.
+type smtpStub struct {
+	listener net.Listener
+	// rcptReply replaces the reply to RCPT TO when set.
+	rcptReply string
+
+	mu       sync.Mutex
+	mails    []stubMail
+	authData string
+}
+
+func newSMTPStub(t *testing.T, rcptReply string) *smtpStub {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	s := &smtpStub{listener: l, rcptReply: rcptReply}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStub) config(from string) codersdk.NotificationsEmailConfig {
+	var hp serpent.HostPort
+	_ = hp.Set(s.listener.Addr().String())
+	return codersdk.NotificationsEmailConfig{
+		From:      serpent.String(from),
+		Smarthost: hp,
+		Hello:     "localhost",
+	}
+}
+
+func (s *smtpStub) received() []stubMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stubMail(nil), s.mails...)
+}
+
+func (s *smtpStub) auth() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authData
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+	reply("220 localhost ESMTP stub")
+
+	var current stubMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			s.mu.Lock()
+			s.authData = string(decoded)
+			s.mu.Unlock()
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			current = stubMail{from: trimAddress(arg)}
+			reply("250 OK")
+		case "RCPT":
+			if s.rcptReply != "" {
+				reply(s.rcptReply)
+				continue
+			}
+			current.to = append(current.to, trimAddress(arg))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var sb strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				sb.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			current.data = sb.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, current)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// trimAddress extracts the address from "FROM:<addr>" or "TO:<addr>".
+func trimAddress(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(addr, " ")
+	return strings.Trim(addr, "<>")
+}
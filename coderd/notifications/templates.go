@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+)
+
+// Names of the built-in notification templates. They are stored with each
+// message and in user preferences, so they must not change.
+const (
+	TemplateWorkspaceDormant         = "workspace-dormant"
+	TemplateWorkspaceDeleted         = "workspace-deleted"
+	TemplateWorkspaceAutobuildFailed = "workspace-autobuild-failed"
+	TemplateTemplateDeprecated       = "template-deprecated"
+	TemplateUserAccountSuspended     = "user-account-suspended"
+)
+
+// Template describes a kind of notification. Title and Body are Go text
+// templates rendered with the labels passed to Enqueue, available as
+// .Labels, along with .UserName and .AccessURL.
+type Template struct {
+	Name        string
+	Description string
+	Title       string
+	Body        string
+}
+
+var templates = []Template{
+	{
+		Name:        TemplateWorkspaceDormant,
+		Description: "A workspace you own was marked as dormant.",
+		Title:       `Workspace "{{.Labels.workspace}}" marked as dormant`,
+		Body: `Hi {{.UserName}},
+
+Your workspace "{{.Labels.workspace}}" was marked as dormant because {{.Labels.reason}}.
+Dormant workspaces are stopped and may be deleted automatically after a while.
+To keep the workspace, start it again: {{.AccessURL}}/@{{.UserName}}/{{.Labels.workspace}}`,
+	},
+	{
+		Name:        TemplateWorkspaceDeleted,
+		Description: "A workspace you own was deleted automatically.",
+		Title:       `Workspace "{{.Labels.workspace}}" deleted`,
+		Body: `Hi {{.UserName}},
+
+Your workspace "{{.Labels.workspace}}" was deleted because {{.Labels.reason}}.`,
+	},
+	{
+		Name:        TemplateWorkspaceAutobuildFailed,
+		Description: "Automatically starting or stopping a workspace you own failed.",
+		Title:       `Workspace "{{.Labels.workspace}}" failed to {{.Labels.transition}} automatically`,
+		Body: `Hi {{.UserName}},
+
+The scheduled {{.Labels.transition}} of your workspace "{{.Labels.workspace}}" failed:
+
+{{.Labels.reason}}
+
+The build logs are available at {{.AccessURL}}/@{{.UserName}}/{{.Labels.workspace}}`,
+	},
+	{
+		Name:        TemplateTemplateDeprecated,
+		Description: "A template one of your workspaces uses was deprecated.",
+		Title:       `Template "{{.Labels.template}}" deprecated`,
+		Body: `Hi {{.UserName}},
+
+The template "{{.Labels.template}}" used by your workspaces was deprecated:
+
+{{.Labels.message}}
+
+Consider moving your work to a workspace built from another template.`,
+	},
+	{
+		Name:        TemplateUserAccountSuspended,
+		Description: "Your account was suspended.",
+		Title:       `Your account "{{.UserName}}" was suspended`,
+		Body: `Hi {{.UserName}},
+
+Your account was suspended by {{.Labels.initiator}}. Contact an administrator if you believe this is a mistake.`,
+	},
+}
+
+// Templates returns the built-in notification templates.
+func Templates() []Template {
+	return append([]Template(nil), templates...)
+}
+
+// LookupTemplate returns the template with the given name.
+func LookupTemplate(name string) (Template, bool) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+type templateData struct {
+	UserName  string
+	AccessURL string
+	Labels    map[string]string
+}
+
+// render executes the title and body of the template. A label referenced
+// by the template but missing from data is an error.
+func (t Template) render(data templateData) (title string, body string, err error) {
+	title, err = renderText(t.Name+".title", t.Title, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = renderText(t.Name+".body", t.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+func renderText(name, text string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", xerrors.Errorf("parse %s: %w", name, err)
+	}
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", xerrors.Errorf("render %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
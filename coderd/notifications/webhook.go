@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// WebhookHandler delivers notifications by POSTing them as JSON to an
+// HTTP endpoint.
+type WebhookHandler struct {
+	cfg    codersdk.NotificationsWebhookConfig
+	client *http.Client
+	log    slog.Logger
+}
+
+// WebhookPayload is the body of the request sent for every notification.
+type WebhookPayload struct {
+	Version string         `json:"_version"`
+	MsgID   uuid.UUID      `json:"msg_id"`
+	Payload MessagePayload `json:"payload"`
+}
+
+// NewWebhookHandler validates the webhook configuration and returns a
+// Handler that sends notifications to the configured endpoint.
+func NewWebhookHandler(cfg codersdk.NotificationsWebhookConfig, log slog.Logger) (*WebhookHandler, error) {
+	if cfg.Endpoint.String() == "" {
+		return nil, xerrors.New("endpoint must be set")
+	}
+	return &WebhookHandler{
+		cfg:    cfg,
+		client: &http.Client{},
+		log:    log.Named("webhook"),
+	}, nil
+}
+
+func (w *WebhookHandler) Dispatch(ctx context.Context, msgID uuid.UUID, payload MessagePayload) (bool, error) {
+	body, err := json.Marshal(WebhookPayload{
+		Version: "1.0",
+		MsgID:   msgID,
+		Payload: payload,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.Endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return false, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	// Include the start of the response in the error, the endpoint may
+	// explain what went wrong.
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = xerrors.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	// Server errors and rate limits are usually temporary, any other
+	// response means the endpoint will never accept the message.
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, err
+}
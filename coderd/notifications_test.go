@@ -0,0 +1,97 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		prefs, err := member.UserNotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, prefs, len(notifications.Templates()))
+		for _, pref := range prefs {
+			require.False(t, pref.Disabled, pref.Template)
+			require.NotEmpty(t, pref.Description)
+		}
+
+		prefs, err = member.UpdateUserNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				notifications.TemplateWorkspaceDormant: true,
+			},
+		})
+		require.NoError(t, err)
+		for _, pref := range prefs {
+			require.Equal(t, pref.Template == notifications.TemplateWorkspaceDormant, pref.Disabled, pref.Template)
+		}
+
+		// Re-enabling the template should be reflected on the next read.
+		_, err = member.UpdateUserNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				notifications.TemplateWorkspaceDormant: false,
+			},
+		})
+		require.NoError(t, err)
+		prefs, err = member.UserNotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		for _, pref := range prefs {
+			require.False(t, pref.Disabled, pref.Template)
+		}
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := client.UpdateUserNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				"does-not-exist": true,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		// Members can't change another user's preferences.
+		_, err := member.UpdateUserNotificationPreferences(ctx, first.UserID.String(), codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				notifications.TemplateWorkspaceDormant: true,
+			},
+		})
+		require.Error(t, err)
+
+		prefs, err := owner.UserNotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		for _, pref := range prefs {
+			require.False(t, pref.Disabled, pref.Template)
+		}
+	})
+}
@@ -0,0 +1,580 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get OIDC sync rules
+// @ID get-oidc-sync-rules
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Success 200 {array} codersdk.OIDCSyncRule
+// @Router /users/oidc/sync-rules [get]
+func (api *API) oidcSyncRules(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rules, err := api.Database.GetOIDCSyncRules(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.OIDCSyncRules(rules))
+}
+
+// @Summary Get OIDC sync rule by ID
+// @ID get-oidc-sync-rule-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param rule path string true "Rule ID" format(uuid)
+// @Success 200 {object} codersdk.OIDCSyncRule
+// @Router /users/oidc/sync-rules/{rule} [get]
+func (api *API) oidcSyncRule(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rule := httpmw.OIDCSyncRuleParam(r)
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.OIDCSyncRule(rule))
+}
+
+// @Summary Create OIDC sync rule
+// @Description Rules apply from the next OIDC login of each user.
+// @ID create-oidc-sync-rule
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param request body codersdk.CreateOIDCSyncRuleRequest true "Create OIDC sync rule request"
+// @Success 201 {object} codersdk.OIDCSyncRule
+// @Router /users/oidc/sync-rules [post]
+func (api *API) postOIDCSyncRule(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OIDCSyncRule](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateOIDCSyncRuleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	rule, ok := api.oidcSyncRuleFromRequest(ctx, rw, req)
+	if !ok {
+		return
+	}
+
+	inserted, err := api.Database.InsertOIDCSyncRule(ctx, database.InsertOIDCSyncRuleParams{
+		ID:                uuid.New(),
+		Claim:             rule.Claim,
+		Pattern:           rule.Pattern,
+		SiteRoles:         rule.SiteRoles,
+		OrganizationID:    rule.OrganizationID,
+		OrganizationRoles: rule.OrganizationRoles,
+		CreatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OIDC sync rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = inserted
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.OIDCSyncRule(inserted))
+}
+
+// @Summary Update OIDC sync rule
+// @Description Replaces all fields of a sync rule. The change applies from the
+// @Description next OIDC login of each user.
+// @ID update-oidc-sync-rule
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param rule path string true "Rule ID" format(uuid)
+// @Param request body codersdk.UpdateOIDCSyncRuleRequest true "Update OIDC sync rule request"
+// @Success 200 {object} codersdk.OIDCSyncRule
+// @Router /users/oidc/sync-rules/{rule} [put]
+func (api *API) putOIDCSyncRule(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		old               = httpmw.OIDCSyncRuleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OIDCSyncRule](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = old
+	defer commitAudit()
+
+	var req codersdk.UpdateOIDCSyncRuleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	rule, ok := api.oidcSyncRuleFromRequest(ctx, rw, codersdk.CreateOIDCSyncRuleRequest(req))
+	if !ok {
+		return
+	}
+
+	updated, err := api.Database.UpdateOIDCSyncRule(ctx, database.UpdateOIDCSyncRuleParams{
+		ID:                old.ID,
+		Claim:             rule.Claim,
+		Pattern:           rule.Pattern,
+		SiteRoles:         rule.SiteRoles,
+		OrganizationID:    rule.OrganizationID,
+		OrganizationRoles: rule.OrganizationRoles,
+		UpdatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating OIDC sync rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.OIDCSyncRule(updated))
+}
+
+// @Summary Delete OIDC sync rule
+// @Description Roles and memberships granted by the rule are removed on the
+// @Description next OIDC login of each user.
+// @ID delete-oidc-sync-rule
+// @Security CoderSessionToken
+// @Tags Users
+// @Param rule path string true "Rule ID" format(uuid)
+// @Success 204
+// @Router /users/oidc/sync-rules/{rule} [delete]
+func (api *API) deleteOIDCSyncRule(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		rule              = httpmw.OIDCSyncRuleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OIDCSyncRule](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = rule
+	defer commitAudit()
+
+	err := api.Database.DeleteOIDCSyncRule(ctx, rule.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OIDC sync rule.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Preview OIDC sync
+// @Description Returns the roles, groups and organization memberships an OIDC
+// @Description login with the given claims would assign. Nothing is changed.
+// @ID preview-oidc-sync
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param request body codersdk.OIDCSyncDryRunRequest true "Dry run request"
+// @Success 200 {object} codersdk.OIDCSyncDryRunResponse
+// @Router /users/oidc/sync-rules/dry-run [post]
+func (api *API) postOIDCSyncDryRun(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceOIDCSyncRule) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if api.OIDCConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "OIDC is not configured.",
+		})
+		return
+	}
+
+	var req codersdk.OIDCSyncDryRunRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var rules []database.OIDCSyncRule
+	if len(req.Rules) > 0 {
+		for _, ruleReq := range req.Rules {
+			rule, ok := api.oidcSyncRuleFromRequest(ctx, rw, ruleReq)
+			if !ok {
+				return
+			}
+			rules = append(rules, rule)
+		}
+	} else {
+		var err error
+		rules, err = api.Database.GetOIDCSyncRules(ctx)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching OIDC sync rules.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	resp := codersdk.OIDCSyncDryRunResponse{
+		MatchedRules:  []int{},
+		SiteRoles:     []string{},
+		Groups:        []string{},
+		Organizations: []codersdk.OIDCSyncDryRunOrganization{},
+	}
+	claims := req.Claims
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+	sync, syncErr := api.oidcSync(ctx, claims, rules)
+	if syncErr != nil {
+		resp.Error = syncErr.msg
+		if syncErr.detail != "" {
+			resp.Error += ": " + syncErr.detail
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+	resp.MatchedRules = append(resp.MatchedRules, sync.MatchedRules...)
+
+	resp.RoleSyncEnabled = sync.UsingRoles
+	roles, _ := filterSiteRoles(sync.Roles)
+	resp.SiteRoles = uniqueSorted(roles)
+
+	if sync.UsingGroups {
+		resp.GroupSyncEnabled = true
+		groups := sync.Groups
+		if api.OIDCConfig.GroupFilter != nil {
+			groups = slices.DeleteFunc(slices.Clone(groups), func(group string) bool {
+				return !api.OIDCConfig.GroupFilter.MatchString(group)
+			})
+		}
+		resp.Groups = uniqueSorted(groups)
+	}
+
+	resp.OrganizationSyncEnabled = sync.UsingOrganizations
+	organizationIDs := slices.Clone(sync.OrganizationIDs)
+	if sync.UsingOrganizations && api.OIDCConfig.OrganizationAssignDefault {
+		defaultOrganization, err := api.Database.GetDefaultOrganization(ctx)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching default organization.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		organizationIDs = append(organizationIDs, defaultOrganization.ID)
+	}
+	seen := make(map[uuid.UUID]struct{}, len(organizationIDs))
+	for _, id := range organizationIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		// Like the login, skip organizations that do not exist.
+		_, err := api.Database.GetOrganizationByID(ctx, id)
+		if httpapi.Is404Error(err) {
+			continue
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		organization := codersdk.OIDCSyncDryRunOrganization{
+			OrganizationID: id,
+			Roles:          []string{},
+		}
+		if roles, ok := sync.OrganizationRoles[id]; ok {
+			organization.RolesSynced = true
+			organization.Roles = uniqueSorted(roles)
+		}
+		resp.Organizations = append(resp.Organizations, organization)
+	}
+	sort.Slice(resp.Organizations, func(i, j int) bool {
+		return resp.Organizations[i].OrganizationID.String() < resp.Organizations[j].OrganizationID.String()
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// oidcSyncRuleFromRequest validates a sync rule from a request. If the rule is
+// invalid, the response is written and false is returned.
+func (api *API) oidcSyncRuleFromRequest(ctx context.Context, rw http.ResponseWriter, req codersdk.CreateOIDCSyncRuleRequest) (database.OIDCSyncRule, bool) {
+	rule := database.OIDCSyncRule{
+		Claim:             req.Claim,
+		Pattern:           req.Pattern,
+		SiteRoles:         req.SiteRoles,
+		OrganizationRoles: req.OrganizationRoles,
+	}
+	if rule.SiteRoles == nil {
+		rule.SiteRoles = []string{}
+	}
+	if rule.OrganizationRoles == nil {
+		rule.OrganizationRoles = []string{}
+	}
+
+	var validations []codersdk.ValidationError
+	if _, err := regexp.Compile(req.Pattern); err != nil {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "pattern",
+			Detail: fmt.Sprintf("Invalid regular expression: %s", err.Error()),
+		})
+	}
+	for _, role := range rule.SiteRoles {
+		if _, isOrgRole := rbac.IsOrgRole(role); isOrgRole {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "site_roles",
+				Detail: fmt.Sprintf("%q is an organization role.", role),
+			})
+			continue
+		}
+		if _, err := rbac.RoleByName(role); err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "site_roles",
+				Detail: fmt.Sprintf("Unknown site role %q.", role),
+			})
+		}
+	}
+
+	if req.OrganizationID == nil {
+		if len(rule.OrganizationRoles) > 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "organization_roles",
+				Detail: "Organization roles require an organization.",
+			})
+		}
+		if len(rule.SiteRoles) == 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "site_roles",
+				Detail: "The rule must grant site roles or an organization membership.",
+			})
+		}
+	} else {
+		_, err := api.Database.GetOrganizationByID(ctx, *req.OrganizationID)
+		if httpapi.Is404Error(err) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "organization_id",
+				Detail: "Must be the ID of an existing organization.",
+			})
+		} else if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization.",
+				Detail:  err.Error(),
+			})
+			return database.OIDCSyncRule{}, false
+		}
+		rule.OrganizationID = uuid.NullUUID{UUID: *req.OrganizationID, Valid: true}
+
+		for _, role := range rule.OrganizationRoles {
+			orgID, isOrgRole := rbac.IsOrgRole(role)
+			if !isOrgRole || orgID != req.OrganizationID.String() {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  "organization_roles",
+					Detail: fmt.Sprintf("%q is not a role of the organization of the rule.", role),
+				})
+				continue
+			}
+			if _, err := rbac.RoleByName(role); err != nil {
+				validations = append(validations, codersdk.ValidationError{
+					Field:  "organization_roles",
+					Detail: fmt.Sprintf("Unknown organization role %q.", role),
+				})
+			}
+		}
+	}
+
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid OIDC sync rule.",
+			Validations: validations,
+		})
+		return database.OIDCSyncRule{}, false
+	}
+	return rule, true
+}
+
+// oidcSyncRulesResult is what the sync rules matching the claims of a user
+// grant. Rules only add to what the user has, so rules that don't match
+// change nothing.
+type oidcSyncRulesResult struct {
+	// Matched are the indexes of the rules that matched.
+	Matched         []int
+	Roles           []string
+	OrganizationIDs []uuid.UUID
+	// OrganizationRoles are the roles the matching rules grant in each
+	// organization.
+	OrganizationRoles map[uuid.UUID][]string
+}
+
+// applyOIDCSyncRules matches the claims of a user against the sync rules.
+// Rules with an invalid pattern or a claim that is not a string or an array
+// of strings never match.
+func applyOIDCSyncRules(ctx context.Context, logger slog.Logger, rules []database.OIDCSyncRule, claims map[string]interface{}) oidcSyncRulesResult {
+	result := oidcSyncRulesResult{
+		Matched:           []int{},
+		OrganizationRoles: map[uuid.UUID][]string{},
+	}
+	for i, rule := range rules {
+		if !oidcSyncRuleMatches(ctx, logger, rule, claims) {
+			continue
+		}
+		result.Matched = append(result.Matched, i)
+		result.Roles = append(result.Roles, rule.SiteRoles...)
+		if rule.OrganizationID.Valid {
+			orgID := rule.OrganizationID.UUID
+			result.OrganizationIDs = append(result.OrganizationIDs, orgID)
+			if len(rule.OrganizationRoles) > 0 {
+				result.OrganizationRoles[orgID] = append(result.OrganizationRoles[orgID], rule.OrganizationRoles...)
+			}
+		}
+	}
+	return result
+}
+
+func oidcSyncRuleMatches(ctx context.Context, logger slog.Logger, rule database.OIDCSyncRule, claims map[string]interface{}) bool {
+	raw, ok := claims[rule.Claim]
+	if !ok {
+		return false
+	}
+	values, err := parseStringSliceClaim(raw)
+	if err != nil {
+		logger.Debug(ctx, "oidc sync rule claim is not a string or an array of strings",
+			slog.F("rule_id", rule.ID),
+			slog.F("claim", rule.Claim),
+			slog.F("type", fmt.Sprintf("%T", raw)),
+		)
+		return false
+	}
+	pattern, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		logger.Warn(ctx, "oidc sync rule has an invalid pattern",
+			slog.F("rule_id", rule.ID),
+			slog.Error(err),
+		)
+		return false
+	}
+	for _, value := range values {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// addUserOrganizationRoles adds the roles of organizationRoles to the roles
+// of the user in each organization. Organizations the user is not a member of
+// are skipped.
+func addUserOrganizationRoles(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, organizationRoles map[uuid.UUID][]string) error {
+	//nolint:gocritic // No user present in the context.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	for orgID, roles := range organizationRoles {
+		member, err := tx.GetOrganizationMemberByUserID(sysCtx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: orgID,
+			UserID:         userID,
+		})
+		if httpapi.Is404Error(err) {
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization member %s: %w", orgID, err)
+		}
+
+		granted := make([]string, 0, len(roles))
+		ignored := make([]string, 0)
+		for _, role := range roles {
+			if _, err := rbac.RoleByName(role); err == nil {
+				granted = append(granted, role)
+			} else {
+				ignored = append(ignored, role)
+			}
+		}
+		if len(ignored) > 0 {
+			logger.Debug(ctx, "oidc organization roles ignored in assignment",
+				slog.F("organization_id", orgID),
+				slog.F("ignored", ignored),
+				slog.F("user_id", userID),
+			)
+		}
+
+		// The member role is implied, and only stored for some members.
+		current := slices.DeleteFunc(slices.Clone(member.Roles), func(role string) bool {
+			return role == rbac.RoleOrgMember(orgID)
+		})
+		want := uniqueSorted(append(slices.Clone(current), granted...))
+		added, _ := rbac.ChangeRoleSet(current, want)
+		if len(added) == 0 {
+			continue
+		}
+		_, err = tx.UpdateMemberRoles(sysCtx, database.UpdateMemberRolesParams{
+			GrantedRoles: want,
+			UserID:       userID,
+			OrgID:        orgID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update roles in organization %s: %w", orgID, err)
+		}
+	}
+	return nil
+}
+
+// uniqueSorted returns the sorted values without duplicates.
+func uniqueSorted(values []string) []string {
+	values = append([]string{}, values...)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
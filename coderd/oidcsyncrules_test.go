@@ -0,0 +1,353 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOIDCSyncRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		rules, err := client.OIDCSyncRules(ctx)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		rule, err := client.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:     "groups",
+			Pattern:   "^admins$",
+			SiteRoles: []string{rbac.RoleTemplateAdmin()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "groups", rule.Claim)
+		require.Equal(t, []string{rbac.RoleTemplateAdmin()}, rule.SiteRoles)
+		require.Nil(t, rule.OrganizationID)
+		require.Empty(t, rule.OrganizationRoles)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:     database.AuditActionCreate,
+			ResourceID: rule.ID,
+		}))
+
+		got, err := client.OIDCSyncRule(ctx, rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, got)
+
+		updated, err := client.UpdateOIDCSyncRule(ctx, rule.ID, codersdk.UpdateOIDCSyncRuleRequest{
+			Claim:             "department",
+			Pattern:           "^eng",
+			OrganizationID:    &first.OrganizationID,
+			OrganizationRoles: []string{rbac.RoleOrgAdmin(first.OrganizationID)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, rule.ID, updated.ID)
+		require.Equal(t, "department", updated.Claim)
+		require.Empty(t, updated.SiteRoles)
+		require.Equal(t, &first.OrganizationID, updated.OrganizationID)
+		require.Equal(t, []string{rbac.RoleOrgAdmin(first.OrganizationID)}, updated.OrganizationRoles)
+
+		rules, err = client.OIDCSyncRules(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.OIDCSyncRule{updated}, rules)
+
+		err = client.DeleteOIDCSyncRule(ctx, rule.ID)
+		require.NoError(t, err)
+		_, err = client.OIDCSyncRule(ctx, rule.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		missing := uuid.New()
+
+		for _, tc := range []struct {
+			Name  string
+			Req   codersdk.CreateOIDCSyncRuleRequest
+			Field string
+		}{
+			{
+				Name:  "Pattern",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: "(", SiteRoles: []string{rbac.RoleTemplateAdmin()}},
+				Field: "pattern",
+			},
+			{
+				Name:  "NothingGranted",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*"},
+				Field: "site_roles",
+			},
+			{
+				Name:  "UnknownSiteRole",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*", SiteRoles: []string{"not-a-role"}},
+				Field: "site_roles",
+			},
+			{
+				Name:  "OrganizationRoleAsSiteRole",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*", SiteRoles: []string{rbac.RoleOrgAdmin(first.OrganizationID)}},
+				Field: "site_roles",
+			},
+			{
+				Name:  "OrganizationRolesWithoutOrganization",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*", SiteRoles: []string{rbac.RoleTemplateAdmin()}, OrganizationRoles: []string{rbac.RoleOrgAdmin(first.OrganizationID)}},
+				Field: "organization_roles",
+			},
+			{
+				Name:  "RoleOfOtherOrganization",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*", OrganizationID: &first.OrganizationID, OrganizationRoles: []string{rbac.RoleOrgAdmin(missing)}},
+				Field: "organization_roles",
+			},
+			{
+				Name:  "MissingOrganization",
+				Req:   codersdk.CreateOIDCSyncRuleRequest{Claim: "groups", Pattern: ".*", OrganizationID: &missing},
+				Field: "organization_id",
+			},
+		} {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitLong)
+				_, err := client.CreateOIDCSyncRule(ctx, tc.Req)
+				var apiErr *codersdk.Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+				require.Len(t, apiErr.Validations, 1)
+				require.Equal(t, tc.Field, apiErr.Validations[0].Field)
+			})
+		}
+	})
+
+	t.Run("NotOwner", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:     "groups",
+			Pattern:   ".*",
+			SiteRoles: []string{rbac.RoleOwner()},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestOIDCSyncDryRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		t.Parallel()
+		fake := oidctest.NewFakeIDP(t, oidctest.WithServing())
+		cfg := fake.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+			cfg.UserRoleField = "roles"
+		})
+		// Previewing claims the login rejects logs an error.
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		client := coderdtest.New(t, &coderdtest.Options{OIDCConfig: cfg, Logger: &logger})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "eng"})
+		require.NoError(t, err)
+		_, err = client.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:     "groups",
+			Pattern:   "^user-admins$",
+			SiteRoles: []string{rbac.RoleUserAdmin()},
+		})
+		require.NoError(t, err)
+		_, err = client.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:             "department",
+			Pattern:           "^engineering$",
+			OrganizationID:    &org.ID,
+			OrganizationRoles: []string{rbac.RoleOrgAdmin(org.ID)},
+		})
+		require.NoError(t, err)
+
+		claims := map[string]interface{}{
+			"roles":      []string{rbac.RoleTemplateAdmin()},
+			"groups":     []string{"user-admins", "everyone"},
+			"department": "engineering",
+		}
+
+		// The stored rules are used by default.
+		resp, err := client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{Claims: claims})
+		require.NoError(t, err)
+		require.Empty(t, resp.Error)
+		require.Equal(t, []int{0, 1}, resp.MatchedRules)
+		require.True(t, resp.RoleSyncEnabled)
+		require.ElementsMatch(t, []string{rbac.RoleUserAdmin(), rbac.RoleTemplateAdmin()}, resp.SiteRoles)
+		// Without an organization claim, the rules only add memberships.
+		require.False(t, resp.OrganizationSyncEnabled)
+		require.Equal(t, []codersdk.OIDCSyncDryRunOrganization{{
+			OrganizationID: org.ID,
+			RolesSynced:    true,
+			Roles:          []string{rbac.RoleOrgAdmin(org.ID)},
+		}}, resp.Organizations)
+
+		// Rules in the request replace the stored rules.
+		resp, err = client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{
+			Claims: claims,
+			Rules: []codersdk.CreateOIDCSyncRuleRequest{{
+				Claim:          "groups",
+				Pattern:        "every",
+				OrganizationID: &first.OrganizationID,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{0}, resp.MatchedRules)
+		require.Equal(t, []string{rbac.RoleTemplateAdmin()}, resp.SiteRoles)
+		require.Equal(t, []codersdk.OIDCSyncDryRunOrganization{{
+			OrganizationID: first.OrganizationID,
+			Roles:          []string{},
+		}}, resp.Organizations)
+
+		// Invalid rules in the request are rejected.
+		_, err = client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{
+			Claims: claims,
+			Rules:  []codersdk.CreateOIDCSyncRuleRequest{{Claim: "groups", Pattern: "("}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Claims the login would reject are reported.
+		resp, err = client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{
+			Claims: map[string]interface{}{"roles": 1},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Error)
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		t.Parallel()
+		fake := oidctest.NewFakeIDP(t, oidctest.WithServing())
+		cfg := fake.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+		})
+		client := coderdtest.New(t, &coderdtest.Options{OIDCConfig: cfg})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "eng"})
+		require.NoError(t, err)
+		orgRule, err := client.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:             "department",
+			Pattern:           "^engineering$",
+			OrganizationID:    &org.ID,
+			OrganizationRoles: []string{rbac.RoleOrgAdmin(org.ID)},
+		})
+		require.NoError(t, err)
+
+		userClient, _ := fake.Login(t, client, jwt.MapClaims{
+			"email":      "alice@coder.com",
+			"department": "engineering",
+		})
+		roles, err := userClient.UserRoles(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, roles.OrganizationRoles[org.ID], rbac.RoleOrgAdmin(org.ID))
+		// Rules only add memberships, so the user stays in the default
+		// organization.
+		require.Contains(t, roles.OrganizationRoles, first.OrganizationID)
+
+		// Changes to the rules apply on the next login without a restart,
+		// and don't take back what the rule granted before.
+		_, err = client.UpdateOIDCSyncRule(ctx, orgRule.ID, codersdk.UpdateOIDCSyncRuleRequest{
+			Claim:             "department",
+			Pattern:           "^engineering$",
+			OrganizationID:    &org.ID,
+			OrganizationRoles: []string{rbac.RoleOrgMember(org.ID)},
+		})
+		require.NoError(t, err)
+		userClient, _ = fake.Login(t, client, jwt.MapClaims{
+			"email":      "alice@coder.com",
+			"department": "engineering",
+		})
+		roles, err = userClient.UserRoles(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, roles.OrganizationRoles[org.ID], rbac.RoleOrgAdmin(org.ID))
+	})
+
+	// A user no rule matches keeps the memberships they were given
+	// manually.
+	t.Run("LoginUnmatched", func(t *testing.T) {
+		t.Parallel()
+		fake := oidctest.NewFakeIDP(t, oidctest.WithServing())
+		cfg := fake.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+		})
+		client := coderdtest.New(t, &coderdtest.Options{OIDCConfig: cfg})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		eng, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "eng"})
+		require.NoError(t, err)
+		ops, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "ops"})
+		require.NoError(t, err)
+		_, err = client.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+			Claim:             "department",
+			Pattern:           "^engineering$",
+			OrganizationID:    &eng.ID,
+			OrganizationRoles: []string{rbac.RoleOrgAdmin(eng.ID)},
+		})
+		require.NoError(t, err)
+
+		claims := jwt.MapClaims{
+			"email":      "bob@coder.com",
+			"department": "sales",
+		}
+		userClient, _ := fake.Login(t, client, claims)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.PostOrganizationMember(ctx, ops.ID, user.ID.String())
+		require.NoError(t, err)
+
+		userClient, _ = fake.Login(t, client, claims)
+		roles, err := userClient.UserRoles(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, roles.OrganizationRoles, first.OrganizationID)
+		require.Contains(t, roles.OrganizationRoles, ops.ID)
+		require.NotContains(t, roles.OrganizationRoles, eng.ID)
+	})
+}
@@ -140,3 +140,43 @@ func TestPostOrganizationsByUser(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestOrganizationMembers(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	first := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+		Name: "other",
+	})
+	require.NoError(t, err)
+
+	added, err := client.PostOrganizationMember(ctx, org.ID, member.Username)
+	require.NoError(t, err)
+	require.Equal(t, member.ID, added.UserID)
+	require.Empty(t, added.Roles)
+
+	_, err = client.PostOrganizationMember(ctx, org.ID, member.Username)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	orgs, err := client.OrganizationsByUser(ctx, member.ID.String())
+	require.NoError(t, err)
+	require.Len(t, orgs, 2)
+
+	err = client.DeleteOrganizationMember(ctx, org.ID, member.Username)
+	require.NoError(t, err)
+
+	orgs, err = client.OrganizationsByUser(ctx, member.ID.String())
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+
+	err = client.DeleteOrganizationMember(ctx, org.ID, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
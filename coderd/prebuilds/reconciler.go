@@ -0,0 +1,402 @@
+package prebuilds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// SystemUserID is the ID of the user that owns prebuilt workspaces until
+// they are claimed. The user is created by the reconciler the first time a
+// template asks for prebuilds.
+var SystemUserID = uuid.MustParse("c42fdf75-3097-471c-8c33-fb52454d81c0")
+
+const (
+	systemUsername = "prebuilds"
+	systemEmail    = "prebuilds@coder.invalid"
+	// namePrefix is prepended to the random names of prebuilt workspaces.
+	// Claimed workspaces are renamed to the name their new owner asked for.
+	namePrefix = "prebuild-"
+)
+
+// ReconcilerOptions configures a Reconciler. Zero values use the defaults.
+type ReconcilerOptions struct {
+	// Interval is how often prebuilds are reconciled. Defaults to 15
+	// seconds.
+	Interval time.Duration
+	// FailureBackoff is how long a failed prebuild is kept before it is
+	// deleted. No new prebuilds are created for a preset while it has one,
+	// so a broken template doesn't keep the provisioners busy. Defaults to
+	// 5 minutes.
+	FailureBackoff time.Duration
+	// DaemonStaleInterval is how long since a provisioner daemon was last
+	// seen before it no longer counts towards the provisioner capacity.
+	// Defaults to 3 heartbeats.
+	DaemonStaleInterval time.Duration
+}
+
+// Reconciler keeps the number of prebuilt workspaces of every preset at the
+// number its template version asks for. Only presets of the active version
+// of a template are prebuilt; prebuilds of older versions are deleted.
+//
+// The number of prebuild jobs pending or running in an organization is
+// limited to the number of provisioner daemons connected to it, so
+// prebuilds never delay the builds of users for long.
+type Reconciler struct {
+	opts   ReconcilerOptions
+	store  database.Store
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReconciler creates a Reconciler. Call Run to start reconciling.
+func NewReconciler(store database.Store, ps pubsub.Pubsub, log slog.Logger, opts ReconcilerOptions) *Reconciler {
+	if opts.Interval == 0 {
+		opts.Interval = 15 * time.Second
+	}
+	if opts.FailureBackoff == 0 {
+		opts.FailureBackoff = 5 * time.Minute
+	}
+	if opts.DaemonStaleInterval == 0 {
+		opts.DaemonStaleInterval = provisionerdserver.DefaultHeartbeatInterval * 3
+	}
+	return &Reconciler{
+		opts:   opts,
+		store:  store,
+		pubsub: ps,
+		log:    log.Named("prebuilds_reconciler"),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run starts reconciling prebuilds in the background until Close is called.
+func (r *Reconciler) Run(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.opts.Interval)
+		defer ticker.Stop()
+		for {
+			err := r.ReconcileAll(ctx)
+			if err != nil && !xerrors.Is(err, context.Canceled) {
+				r.log.Error(ctx, "reconcile prebuilds", slog.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops reconciling and waits for the current run to finish.
+func (r *Reconciler) Close() error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+// ReconcileAll creates and deletes prebuilds until every preset has the
+// number it asks for, within the provisioner capacity. It is called on
+// every tick by Run.
+func (r *Reconciler) ReconcileAll(ctx context.Context) error {
+	//nolint:gocritic // The reconciler manages workspaces of every template.
+	ctx = dbauthz.AsPrebuildsReconciler(ctx)
+
+	presets, err := r.store.GetPrebuildPresets(ctx)
+	if err != nil {
+		return xerrors.Errorf("get prebuild presets: %w", err)
+	}
+	prebuilds, err := r.store.GetWorkspacePrebuilds(ctx)
+	if err != nil {
+		return xerrors.Errorf("get prebuilds: %w", err)
+	}
+	if len(presets) == 0 && len(prebuilds) == 0 {
+		return nil
+	}
+
+	if len(presets) > 0 {
+		err = r.ensureSystemUser(ctx)
+		if err != nil {
+			return xerrors.Errorf("ensure system user: %w", err)
+		}
+	}
+	capacity, err := r.capacity(ctx, prebuilds)
+	if err != nil {
+		return xerrors.Errorf("get provisioner capacity: %w", err)
+	}
+
+	byPreset := make(map[uuid.UUID][]database.GetWorkspacePrebuildsRow)
+	for _, prebuild := range prebuilds {
+		byPreset[prebuild.TemplateVersionPresetID] = append(byPreset[prebuild.TemplateVersionPresetID], prebuild)
+	}
+
+	for _, preset := range presets {
+		preset := preset
+		existing := byPreset[preset.ID]
+		delete(byPreset, preset.ID)
+		err = r.reconcilePreset(ctx, preset.ID, &preset, existing, capacity)
+		if err != nil {
+			r.log.Error(ctx, "reconcile prebuilds of preset", slog.F("preset_id", preset.ID),
+				slog.F("preset_name", preset.Name), slog.F("template_id", preset.TemplateID), slog.Error(err))
+		}
+	}
+	// Whatever is left belongs to presets that no longer want prebuilds,
+	// usually because a new template version was promoted.
+	for presetID, existing := range byPreset {
+		err = r.reconcilePreset(ctx, presetID, nil, existing, capacity)
+		if err != nil {
+			r.log.Error(ctx, "delete outdated prebuilds", slog.F("preset_id", presetID), slog.Error(err))
+		}
+	}
+
+	err = r.store.DeleteWorkspacePrebuildsOfDeletedWorkspaces(ctx)
+	if err != nil {
+		return xerrors.Errorf("delete prebuilds of deleted workspaces: %w", err)
+	}
+	return nil
+}
+
+// ensureSystemUser creates the user that owns prebuilds if it doesn't exist
+// yet. The user is dormant and can't log in, so it doesn't take a seat.
+func (r *Reconciler) ensureSystemUser(ctx context.Context) error {
+	_, err := r.store.GetUserByID(ctx, SystemUserID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get user: %w", err)
+	}
+
+	now := dbtime.Now()
+	//nolint:gocritic // Only the system can create users without a request.
+	_, err = r.store.InsertUser(dbauthz.AsSystemRestricted(ctx), database.InsertUserParams{
+		ID:             SystemUserID,
+		Email:          systemEmail,
+		Username:       systemUsername,
+		HashedPassword: []byte{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		RBACRoles:      []string{},
+		LoginType:      database.LoginTypeNone,
+	})
+	if err != nil && !database.IsUniqueViolation(err) {
+		return xerrors.Errorf("insert user: %w", err)
+	}
+	r.log.Info(ctx, "created prebuilds system user", slog.F("user_id", SystemUserID))
+	return nil
+}
+
+// capacity returns the number of prebuild jobs that may be started in every
+// organization: the number of connected provisioner daemons less the number
+// of prebuild jobs that are already pending or running.
+func (r *Reconciler) capacity(ctx context.Context, prebuilds []database.GetWorkspacePrebuildsRow) (map[uuid.UUID]int, error) {
+	daemons, err := r.store.GetProvisionerDaemons(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get provisioner daemons: %w", err)
+	}
+	now := dbtime.Now()
+	capacity := make(map[uuid.UUID]int)
+	for _, daemon := range daemons {
+		if !daemon.LastSeenAt.Valid || now.Sub(daemon.LastSeenAt.Time) > r.opts.DaemonStaleInterval {
+			continue
+		}
+		capacity[daemon.OrganizationID]++
+	}
+	for _, prebuild := range prebuilds {
+		if inProgress(prebuild) {
+			capacity[prebuild.OrganizationID]--
+		}
+	}
+	return capacity, nil
+}
+
+// reconcilePreset creates or deletes prebuilds of a preset. A nil preset
+// means the preset doesn't want any prebuilds anymore. Prebuilds don't count
+// towards the workspace limit of the template until they are claimed, so
+// they are built even if the template has reached its limit.
+func (r *Reconciler) reconcilePreset(ctx context.Context, presetID uuid.UUID, preset *database.GetPrebuildPresetsRow, existing []database.GetWorkspacePrebuildsRow, capacity map[uuid.UUID]int) error {
+	var desired int
+	if preset != nil {
+		desired = int(preset.DesiredPrebuildInstances)
+	}
+
+	var (
+		now      = dbtime.Now()
+		valid    int
+		backoff  bool
+		toDelete []database.GetWorkspacePrebuildsRow
+	)
+	for _, prebuild := range existing {
+		switch {
+		case inProgress(prebuild):
+			if prebuild.Transition == database.WorkspaceTransitionStart {
+				valid++
+			}
+		case prebuild.JobStatus == database.ProvisionerJobStatusFailed || prebuild.JobStatus == database.ProvisionerJobStatusCanceled:
+			// Keep failed prebuilds around for a while, so their logs can be
+			// inspected and the template isn't built over and over.
+			if prebuild.CompletedAt.Valid && now.Sub(prebuild.CompletedAt.Time) < r.opts.FailureBackoff {
+				backoff = true
+				continue
+			}
+			toDelete = append(toDelete, prebuild)
+		case prebuild.Transition == database.WorkspaceTransitionStart:
+			valid++
+			if valid > desired {
+				toDelete = append(toDelete, prebuild)
+			}
+		default:
+			// Stopped prebuilds can't be claimed.
+			toDelete = append(toDelete, prebuild)
+		}
+	}
+
+	create := 0
+	if preset != nil && !backoff && valid < desired {
+		create = desired - valid
+	}
+	if create == 0 && len(toDelete) == 0 {
+		return nil
+	}
+
+	var jobs []database.ProvisionerJob
+	err := r.store.InTx(func(tx database.Store) error {
+		// Replicas reconcile the same presets, the lock makes sure only one
+		// of them acts on a preset at a time.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID("prebuilds:"+presetID.String()))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		for _, prebuild := range toDelete {
+			if capacity[prebuild.OrganizationID] <= 0 {
+				break
+			}
+			job, err := r.deletePrebuild(ctx, tx, prebuild)
+			if err != nil {
+				return xerrors.Errorf("delete prebuild %s: %w", prebuild.WorkspaceID, err)
+			}
+			capacity[prebuild.OrganizationID]--
+			jobs = append(jobs, *job)
+		}
+		for i := 0; i < create && capacity[preset.OrganizationID] > 0; i++ {
+			job, err := r.createPrebuild(ctx, tx, *preset)
+			if err != nil {
+				return xerrors.Errorf("create prebuild: %w", err)
+			}
+			capacity[preset.OrganizationID]--
+			jobs = append(jobs, *job)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	// Jobs are only posted once the transaction has committed, otherwise a
+	// provisioner could try to acquire a job it can't see yet.
+	for _, job := range jobs {
+		err = provisionerjobs.PostJob(r.pubsub, job)
+		if err != nil {
+			r.log.Warn(ctx, "post provisioner job to pubsub", slog.F("job_id", job.ID), slog.Error(err))
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) createPrebuild(ctx context.Context, tx database.Store, preset database.GetPrebuildPresetsRow) (*database.ProvisionerJob, error) {
+	suffix, err := cryptorand.StringCharset(cryptorand.Lower+cryptorand.Numeric, 8)
+	if err != nil {
+		return nil, xerrors.Errorf("generate name: %w", err)
+	}
+	now := dbtime.Now()
+	workspace, err := tx.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+		ID:               uuid.New(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		OwnerID:          SystemUserID,
+		OrganizationID:   preset.OrganizationID,
+		TemplateID:       preset.TemplateID,
+		Name:             namePrefix + suffix,
+		LastUsedAt:       now,
+		AutomaticUpdates: database.AutomaticUpdatesNever,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("insert workspace: %w", err)
+	}
+	err = tx.InsertWorkspacePrebuild(ctx, database.InsertWorkspacePrebuildParams{
+		WorkspaceID:             workspace.ID,
+		TemplateVersionPresetID: preset.ID,
+		CreatedAt:               now,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("insert prebuild: %w", err)
+	}
+
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+		Initiator(SystemUserID).
+		VersionID(preset.TemplateVersionID).
+		TemplateVersionPresetID(preset.ID)
+	_, job, err := builder.Build(ctx, tx, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+	if err != nil {
+		return nil, xerrors.Errorf("build workspace: %w", err)
+	}
+	r.log.Info(ctx, "creating prebuild", slog.F("workspace_id", workspace.ID),
+		slog.F("preset_id", preset.ID), slog.F("template_id", preset.TemplateID))
+	return job, nil
+}
+
+func (r *Reconciler) deletePrebuild(ctx context.Context, tx database.Store, prebuild database.GetWorkspacePrebuildsRow) (*database.ProvisionerJob, error) {
+	workspace, err := tx.GetWorkspaceByID(ctx, prebuild.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionDelete).
+		Initiator(SystemUserID)
+	_, job, err := builder.Build(ctx, tx, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+	if err != nil {
+		return nil, xerrors.Errorf("build workspace: %w", err)
+	}
+	r.log.Info(ctx, "deleting prebuild", slog.F("workspace_id", workspace.ID),
+		slog.F("preset_id", prebuild.TemplateVersionPresetID), slog.F("job_status", prebuild.JobStatus))
+	return job, nil
+}
+
+func inProgress(prebuild database.GetWorkspacePrebuildsRow) bool {
+	switch prebuild.JobStatus {
+	case database.ProvisionerJobStatusPending, database.ProvisionerJobStatusRunning, database.ProvisionerJobStatusCanceling:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+package prebuilds_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/prebuilds"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReconciler(t *testing.T) {
+	t.Parallel()
+
+	db, ps := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database:                 db,
+		Pubsub:                   ps,
+		IncludeProvisionerDaemon: true,
+		// The test reconciles by itself.
+		PrebuildsReconcileInterval: time.Hour,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, presetResponses("Large", 2))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	require.EqualValues(t, 2, presets[0].Prebuilds)
+
+	reconciler := prebuilds.NewReconciler(db, ps, slogtest.Make(t, nil), prebuilds.ReconcilerOptions{})
+
+	// There is a single provisioner, so only one prebuild is built at a time.
+	require.NoError(t, reconciler.ReconcileAll(ctx))
+	require.Len(t, prebuildsOf(ctx, t, db, presets[0].ID), 1)
+	require.NoError(t, reconciler.ReconcileAll(ctx))
+	require.Len(t, prebuildsOf(ctx, t, db, presets[0].ID), 1)
+
+	reconcileUntil(ctx, t, reconciler, func() bool {
+		return countRunning(prebuildsOf(ctx, t, db, presets[0].ID)) == 2
+	})
+
+	// Prebuilds are owned by a system user that doesn't take a seat.
+	systemUser, err := db.GetUserByID(ctx, prebuilds.SystemUserID)
+	require.NoError(t, err)
+	require.Equal(t, database.UserStatusDormant, systemUser.Status)
+
+	// Promoting a new version replaces the prebuilds of the old one.
+	newVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, presetResponses("Small", 1), func(ctvr *codersdk.CreateTemplateVersionRequest) {
+		ctvr.TemplateID = template.ID
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+	coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, newVersion.ID)
+	newPresets, err := client.TemplateVersionPresets(ctx, newVersion.ID)
+	require.NoError(t, err)
+	require.Len(t, newPresets, 1)
+
+	reconcileUntil(ctx, t, reconciler, func() bool {
+		return len(prebuildsOf(ctx, t, db, presets[0].ID)) == 0 &&
+			countRunning(prebuildsOf(ctx, t, db, newPresets[0].ID)) == 1
+	})
+}
+
+func presetResponses(name string, prebuilds int32) *echo.Responses {
+	return &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{{Name: "cpu", Type: "number", DefaultValue: "1"}},
+					Presets: []*proto.Preset{{
+						Name:       name,
+						Parameters: []*proto.PresetParameter{{Name: "cpu", Value: "8"}},
+						Prebuilds:  prebuilds,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	}
+}
+
+func reconcileUntil(ctx context.Context, t *testing.T, reconciler *prebuilds.Reconciler, condition func() bool) {
+	t.Helper()
+	require.True(t, testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+		require.NoError(t, reconciler.ReconcileAll(ctx))
+		return condition()
+	}, testutil.IntervalFast))
+}
+
+func prebuildsOf(ctx context.Context, t *testing.T, db database.Store, presetID uuid.UUID) []database.GetWorkspacePrebuildsRow {
+	t.Helper()
+	rows, err := db.GetWorkspacePrebuilds(ctx)
+	require.NoError(t, err)
+	var prebuilds []database.GetWorkspacePrebuildsRow
+	for _, row := range rows {
+		if row.TemplateVersionPresetID == presetID {
+			prebuilds = append(prebuilds, row)
+		}
+	}
+	return prebuilds
+}
+
+func countRunning(prebuilds []database.GetWorkspacePrebuildsRow) int {
+	running := 0
+	for _, prebuild := range prebuilds {
+		if prebuild.Transition == database.WorkspaceTransitionStart && prebuild.JobStatus == database.ProvisionerJobStatusSucceeded {
+			running++
+		}
+	}
+	return running
+}
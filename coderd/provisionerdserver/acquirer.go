@@ -64,6 +64,7 @@ func TestingBackupPollDuration(dur time.Duration) AcquirerOption {
 // AcquirerStore is the subset of database.Store that the Acquirer needs
 type AcquirerStore interface {
 	AcquireProvisionerJob(context.Context, database.AcquireProvisionerJobParams) (database.ProvisionerJob, error)
+	GetPendingProvisionerJobEngineVersionConstraints(context.Context, uuid.UUID) ([]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error)
 }
 
 func NewAcquirer(ctx context.Context, logger slog.Logger, store AcquirerStore, ps pubsub.Pubsub,
@@ -85,11 +86,14 @@ func NewAcquirer(ctx context.Context, logger slog.Logger, store AcquirerStore, p
 }
 
 // AcquireJob acquires a job with one of the given provisioner types and compatible
-// tags from the database.  The call blocks until a job is acquired, the context is
-// done, or the database returns an error _other_ than that no jobs are available.
-// If no jobs are available, this method handles retrying as appropriate.
+// tags from the database.  Jobs of templates that constrain the engine version are
+// only acquired if the daemon downloads engine versions for the provisioner type,
+// or if one of the given engine versions satisfies the constraint.  The
+// call blocks until a job is acquired, the context is done, or the database returns
+// an error _other_ than that no jobs are available.  If no jobs are available, this
+// method handles retrying as appropriate.
 func (a *Acquirer) AcquireJob(
-	ctx context.Context, organization uuid.UUID, worker uuid.UUID, pt []database.ProvisionerType, tags Tags,
+	ctx context.Context, organization uuid.UUID, worker uuid.UUID, pt []database.ProvisionerType, tags Tags, versions EngineVersions,
 ) (
 	retJob database.ProvisionerJob, retErr error,
 ) {
@@ -97,9 +101,10 @@ func (a *Acquirer) AcquireJob(
 		slog.F("organization_id", organization),
 		slog.F("worker_id", worker),
 		slog.F("provisioner_types", pt),
-		slog.F("tags", tags))
+		slog.F("tags", tags),
+		slog.F("engine_versions", versions))
 	logger.Debug(ctx, "acquiring job")
-	dk := domainKey(organization, pt, tags)
+	dk := domainKey(organization, pt, tags, versions)
 	dbTags, err := tags.ToJSON()
 	if err != nil {
 		return database.ProvisionerJob{}, err
@@ -107,7 +112,7 @@ func (a *Acquirer) AcquireJob(
 	// buffer of 1 so that cancel doesn't deadlock while writing to the channel
 	clearance := make(chan struct{}, 1)
 	for {
-		a.want(organization, pt, tags, clearance)
+		a.want(dk, pt, tags, clearance)
 		select {
 		case <-ctx.Done():
 			err := ctx.Err()
@@ -120,6 +125,15 @@ func (a *Acquirer) AcquireJob(
 			return database.ProvisionerJob{}, err
 		case <-clearance:
 			logger.Debug(ctx, "got clearance to call database")
+			constraints, err := a.satisfiedConstraints(ctx, organization, versions)
+			if err != nil {
+				internalError := a.done(dk, clearance)
+				if internalError != nil {
+					return database.ProvisionerJob{}, internalError
+				}
+				logger.Warn(ctx, "error attempting to get engine version constraints", slog.Error(err))
+				return database.ProvisionerJob{}, xerrors.Errorf("failed to get engine version constraints: %w", err)
+			}
 			job, err := a.store.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
 				OrganizationID: organization,
 				StartedAt: sql.NullTime{
@@ -130,8 +144,9 @@ func (a *Acquirer) AcquireJob(
 					UUID:  worker,
 					Valid: true,
 				},
-				Types: pt,
-				Tags:  dbTags,
+				Types:                    pt,
+				Tags:                     dbTags,
+				EngineVersionConstraints: constraints,
 			})
 			if xerrors.Is(err, sql.ErrNoRows) {
 				logger.Debug(ctx, "no job available")
@@ -153,9 +168,24 @@ func (a *Acquirer) AcquireJob(
 	}
 }
 
+// satisfiedConstraints returns the engine version constraints of pending jobs
+// that the daemon can satisfy, as "<provisioner>:<constraint>".
+func (a *Acquirer) satisfiedConstraints(ctx context.Context, organization uuid.UUID, versions EngineVersions) ([]string, error) {
+	rows, err := a.store.GetPendingProvisionerJobEngineVersionConstraints(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+	var constraints []string
+	for _, row := range rows {
+		if versions.satisfies(row.Provisioner, row.EngineVersionConstraint) {
+			constraints = append(constraints, string(row.Provisioner)+":"+row.EngineVersionConstraint)
+		}
+	}
+	return constraints, nil
+}
+
 // want signals that an acquiree wants clearance to query for a job with the given dKey.
-func (a *Acquirer) want(organization uuid.UUID, pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
-	dk := domainKey(organization, pt, tags)
+func (a *Acquirer) want(dk dKey, pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	cleared := false
@@ -401,12 +431,12 @@ func (*Acquirer) clearOrPendLocked(d domain) {
 
 type dKey string
 
-// domainKey generates a canonical map key for the given provisioner types and
-// tags.  It uses the null byte (0x00) as a delimiter because it is an
-// unprintable control character and won't show up in any "reasonable" set of
-// string tags, even in non-Latin scripts.  It is important that Tags are
+// domainKey generates a canonical map key for the given provisioner types,
+// tags and engine versions.  It uses the null byte (0x00) as a delimiter because
+// it is an unprintable control character and won't show up in any "reasonable"
+// set of string tags, even in non-Latin scripts.  It is important that Tags are
 // validated not to contain this control character prior to use.
-func domainKey(orgID uuid.UUID, pt []database.ProvisionerType, tags Tags) dKey {
+func domainKey(orgID uuid.UUID, pt []database.ProvisionerType, tags Tags, versions EngineVersions) dKey {
 	sb := strings.Builder{}
 	_, _ = sb.WriteString(orgID.String())
 	_ = sb.WriteByte(0x00)
@@ -432,6 +462,24 @@ func domainKey(orgID uuid.UUID, pt []database.ProvisionerType, tags Tags) dKey {
 		_, _ = sb.WriteString(tags[k])
 		_ = sb.WriteByte(0x00)
 	}
+	// Daemons with different engine versions may be able to acquire
+	// different jobs, so they must not share a domain.
+	_ = sb.WriteByte(0x00)
+	vpts := make([]database.ProvisionerType, 0, len(versions))
+	for t := range versions {
+		vpts = append(vpts, t)
+	}
+	slices.Sort(vpts)
+	for _, t := range vpts {
+		vs := slices.Clone(versions[t])
+		slices.Sort(vs)
+		_, _ = sb.WriteString(string(t))
+		_ = sb.WriteByte(0x00)
+		for _, v := range vs {
+			_, _ = sb.WriteString(v)
+			_ = sb.WriteByte(0x00)
+		}
+	}
 	return dKey(sb.String())
 }
 
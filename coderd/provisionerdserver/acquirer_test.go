@@ -507,7 +507,7 @@ func TestAcquirer_MatchTags(t *testing.T) {
 			if tt.unmatchedOrg {
 				acquireOrgID = uuid.New()
 			}
-			aj, err := acq.AcquireJob(ctx, acquireOrgID, uuid.New(), ptypes, tt.acquireJobTags)
+			aj, err := acq.AcquireJob(ctx, acquireOrgID, uuid.New(), ptypes, tt.acquireJobTags, nil)
 			if tt.expectAcquire {
 				assert.NoError(t, err)
 				assert.Equal(t, pj.ID, aj.ID)
@@ -550,6 +550,97 @@ func TestAcquirer_MatchTags(t *testing.T) {
 	})
 }
 
+func TestAcquirer_MatchEngineVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		constraint    string
+		versions      provisionerdserver.EngineVersions
+		expectAcquire bool
+	}{
+		{
+			name:          "NoConstraint",
+			versions:      provisionerdserver.EngineVersions{database.ProvisionerTypeTerraform: {"1.5.7"}},
+			expectAcquire: true,
+		},
+		{
+			name:          "NoConstraintNoVersions",
+			expectAcquire: true,
+		},
+		{
+			name:          "Satisfied",
+			constraint:    "~> 1.5.0",
+			versions:      provisionerdserver.EngineVersions{database.ProvisionerTypeTerraform: {"1.6.6", "1.5.7"}},
+			expectAcquire: true,
+		},
+		{
+			name:          "NotSatisfied",
+			constraint:    "~> 1.5.0",
+			versions:      provisionerdserver.EngineVersions{database.ProvisionerTypeTerraform: {"1.6.6"}},
+			expectAcquire: false,
+		},
+		{
+			// Only the OpenTofu versions are restricted, so the daemon
+			// downloads a satisfying Terraform version.
+			name:          "NotInstalledOtherEngineRestricted",
+			constraint:    "~> 1.5.0",
+			versions:      provisionerdserver.EngineVersions{database.ProvisionerTypeOpentofu: {"1.5.7"}},
+			expectAcquire: true,
+		},
+		{
+			// Daemons that download engine versions do not advertise any.
+			name:          "NotInstalledDownloads",
+			constraint:    "~> 1.5.0",
+			expectAcquire: true,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.Context(t, testutil.WaitShort)
+			db, ps := dbtestutil.NewDB(t)
+			log := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+			org, err := db.InsertOrganization(ctx, database.InsertOrganizationParams{
+				ID:          uuid.New(),
+				Name:        "test org",
+				Description: "the organization of testing",
+				CreatedAt:   dbtime.Now(),
+				UpdatedAt:   dbtime.Now(),
+			})
+			require.NoError(t, err)
+			tags := provisionerdserver.Tags{"scope": "organization", "owner": ""}
+			pj, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+				ID:                      uuid.New(),
+				CreatedAt:               dbtime.Now(),
+				UpdatedAt:               dbtime.Now(),
+				OrganizationID:          org.ID,
+				InitiatorID:             uuid.New(),
+				Provisioner:             database.ProvisionerTypeTerraform,
+				StorageMethod:           database.ProvisionerStorageMethodFile,
+				FileID:                  uuid.New(),
+				Type:                    database.ProvisionerJobTypeWorkspaceBuild,
+				Input:                   []byte("{}"),
+				Tags:                    database.StringMap(tags),
+				EngineVersionConstraint: tt.constraint,
+			})
+			require.NoError(t, err)
+			ptypes := []database.ProvisionerType{database.ProvisionerTypeTerraform, database.ProvisionerTypeOpentofu}
+			acq := provisionerdserver.NewAcquirer(ctx, log, db, ps)
+
+			aj, err := acq.AcquireJob(ctx, org.ID, uuid.New(), ptypes, tags, tt.versions)
+			if tt.expectAcquire {
+				assert.NoError(t, err)
+				assert.Equal(t, pj.ID, aj.ID)
+			} else {
+				assert.Empty(t, aj, "should not have acquired job")
+				assert.ErrorIs(t, err, context.DeadlineExceeded, "should have timed out")
+			}
+		})
+	}
+}
+
 func postJob(t *testing.T, ps pubsub.Pubsub, pt database.ProvisionerType, tags provisionerdserver.Tags) {
 	t.Helper()
 	msg, err := json.Marshal(provisionerjobs.JobPosting{
@@ -609,6 +700,14 @@ func (s *fakeOrderedStore) AcquireProvisionerJob(
 	return job, err
 }
 
+func (*fakeOrderedStore) GetPendingProvisionerJobEngineVersionConstraints(
+	context.Context, uuid.UUID,
+) (
+	[]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error,
+) {
+	return nil, nil
+}
+
 func (s *fakeOrderedStore) sendCtx(ctx context.Context, job database.ProvisionerJob, err error) error {
 	select {
 	case <-ctx.Done():
@@ -676,6 +775,14 @@ jobLoop:
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (*fakeTaggedStore) GetPendingProvisionerJobEngineVersionConstraints(
+	context.Context, uuid.UUID,
+) (
+	[]database.GetPendingProvisionerJobEngineVersionConstraintsRow, error,
+) {
+	return nil, nil
+}
+
 // testAcquiree is a helper type that handles asynchronously calling AcquireJob
 // and asserting whether or not it returns, blocks, or is canceled.
 type testAcquiree struct {
@@ -702,7 +809,7 @@ func newTestAcquiree(t *testing.T, orgID uuid.UUID, workerID uuid.UUID, pt []dat
 
 func (a *testAcquiree) startAcquire(ctx context.Context, uut *provisionerdserver.Acquirer) {
 	go func() {
-		j, e := uut.AcquireJob(ctx, a.orgID, a.workerID, a.pt, a.tags)
+		j, e := uut.AcquireJob(ctx, a.orgID, a.workerID, a.pt, a.tags, nil)
 		a.ec <- e
 		a.jc <- j
 	}()
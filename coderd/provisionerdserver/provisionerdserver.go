@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-version"
 	"github.com/sqlc-dev/pqtype"
 	semconv "go.opentelemetry.io/otel/semconv/v1.14.0"
 	"go.opentelemetry.io/otel/trace"
@@ -32,10 +33,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
 	"github.com/coder/coder/v2/provisioner"
@@ -72,6 +75,12 @@ type Options struct {
 	// The default function just calls UpdateProvisionerDaemonLastSeenAt.
 	// This is mainly used for testing.
 	HeartbeatFn func(context.Context) error
+
+	// EngineVersions are the engine versions the provisioner daemon has
+	// installed, for the provisioner types it does not download versions of.
+	// Jobs of templates that constrain the engine version are only acquired
+	// if one of them satisfies the constraint.
+	EngineVersions EngineVersions
 }
 
 type server struct {
@@ -86,6 +95,7 @@ type server struct {
 	Provisioners                []database.ProvisionerType
 	ExternalAuthConfigs         []*externalauth.Config
 	Tags                        Tags
+	EngineVersions              EngineVersions
 	Database                    database.Store
 	Pubsub                      pubsub.Pubsub
 	Acquirer                    *Acquirer
@@ -96,6 +106,8 @@ type server struct {
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	DeploymentValues            *codersdk.DeploymentValues
+	NotificationsEnqueuer       notifications.Enqueuer
+	WebhookPublisher            webhooks.Publisher
 
 	OIDCConfig promoauth.OAuth2Config
 
@@ -131,6 +143,46 @@ func (t Tags) Valid() error {
 	return nil
 }
 
+// EngineVersions maps provisioner types to the versions of their engine that a
+// provisioner daemon has installed. Provisioner types without an entry are
+// unrestricted, since the daemon downloads the versions templates require.
+type EngineVersions map[database.ProvisionerType][]string
+
+func (v EngineVersions) Valid() error {
+	for pt, versions := range v {
+		for _, raw := range versions {
+			_, err := version.NewVersion(raw)
+			if err != nil {
+				return xerrors.Errorf("%s version %q: %w", pt, raw, err)
+			}
+		}
+	}
+	return nil
+}
+
+// satisfies returns whether the daemon can run a version of the provisioner's
+// engine that satisfies the constraint.
+func (v EngineVersions) satisfies(pt database.ProvisionerType, constraint string) bool {
+	installed, ok := v[pt]
+	if !ok {
+		return true
+	}
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	for _, raw := range installed {
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+		if constraints.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewServer(
 	lifecycleCtx context.Context,
 	accessURL *url.URL,
@@ -149,6 +201,8 @@ func NewServer(
 	templateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore],
 	userQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore],
 	deploymentValues *codersdk.DeploymentValues,
+	enqueuer notifications.Enqueuer,
+	webhookPublisher webhooks.Publisher,
 	options Options,
 ) (proto.DRPCProvisionerDaemonServer, error) {
 	// Fail-fast if pointers are nil
@@ -173,12 +227,21 @@ func NewServer(
 	if acquirer == nil {
 		return nil, xerrors.New("acquirer is nil")
 	}
+	if enqueuer == nil {
+		return nil, xerrors.New("enqueuer is nil")
+	}
+	if webhookPublisher == nil {
+		return nil, xerrors.New("webhookPublisher is nil")
+	}
 	if tags == nil {
 		return nil, xerrors.Errorf("tags is nil")
 	}
 	if err := tags.Valid(); err != nil {
 		return nil, xerrors.Errorf("invalid tags: %w", err)
 	}
+	if err := options.EngineVersions.Valid(); err != nil {
+		return nil, xerrors.Errorf("invalid engine versions: %w", err)
+	}
 	if options.AcquireJobLongPollDur == 0 {
 		options.AcquireJobLongPollDur = DefaultAcquireJobLongPollDur
 	}
@@ -195,6 +258,7 @@ func NewServer(
 		Provisioners:                provisioners,
 		ExternalAuthConfigs:         options.ExternalAuthConfigs,
 		Tags:                        tags,
+		EngineVersions:              options.EngineVersions,
 		Database:                    db,
 		Pubsub:                      ps,
 		Acquirer:                    acquirer,
@@ -205,6 +269,8 @@ func NewServer(
 		TemplateScheduleStore:       templateScheduleStore,
 		UserQuietHoursScheduleStore: userQuietHoursScheduleStore,
 		DeploymentValues:            deploymentValues,
+		NotificationsEnqueuer:       enqueuer,
+		WebhookPublisher:            webhookPublisher,
 		OIDCConfig:                  options.OIDCConfig,
 		TimeNowFn:                   options.TimeNowFn,
 		acquireJobLongPollDur:       options.AcquireJobLongPollDur,
@@ -290,7 +356,7 @@ func (s *server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Acquire
 	// database.
 	acqCtx, acqCancel := context.WithTimeout(ctx, s.acquireJobLongPollDur)
 	defer acqCancel()
-	job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags)
+	job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags, s.EngineVersions)
 	if xerrors.Is(err, context.DeadlineExceeded) {
 		s.Logger.Debug(ctx, "successful cancel")
 		return &proto.AcquiredJob{}, nil
@@ -327,7 +393,7 @@ func (s *server) AcquireJobWithCancel(stream proto.DRPCProvisionerDaemon_Acquire
 	}()
 	jec := make(chan jobAndErr, 1)
 	go func() {
-		job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags)
+		job, err := s.Acquirer.AcquireJob(acqCtx, s.OrganizationID, s.ID, s.Provisioners, s.Tags, s.EngineVersions)
 		jec <- jobAndErr{job: job, err: err}
 	}()
 	var recvErr error
@@ -563,6 +629,27 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			})
 		}
 
+		// Snapshot builds tell the template to snapshot the workspace, and
+		// every build of a workspace created from a snapshot restores it.
+		workspaceSnapshot := true
+		_, err = s.Database.GetWorkspaceSnapshotByBuildID(ctx, workspaceBuild.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			workspaceSnapshot = false
+		} else if err != nil {
+			return nil, failJob(fmt.Sprintf("get workspace snapshot: %s", err))
+		}
+		var workspaceSnapshotArtifacts map[string]string
+		restore, err := s.Database.GetWorkspaceSnapshotRestoreByWorkspaceID(ctx, workspace.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, failJob(fmt.Sprintf("get workspace snapshot restore: %s", err))
+		}
+		if err == nil {
+			err = json.Unmarshal(restore.Artifacts, &workspaceSnapshotArtifacts)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("unmarshal workspace snapshot artifacts: %s", err))
+			}
+		}
+
 		protoJob.Type = &proto.AcquiredJob_WorkspaceBuild_{
 			WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
 				WorkspaceBuildId:      workspaceBuild.ID.String(),
@@ -586,10 +673,13 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					TemplateName:                  template.Name,
 					TemplateVersion:               templateVersion.Name,
 					WorkspaceOwnerSessionToken:    sessionToken,
+					WorkspaceSnapshot:             workspaceSnapshot,
+					WorkspaceSnapshotArtifacts:    workspaceSnapshotArtifacts,
 				},
 				LogLevel: input.LogLevel,
 			},
 		}
+		s.publishWorkspaceBuildEvent(ctx, codersdk.WebhookEventWorkspaceBuildStarted, workspace, workspaceBuild, "")
 	case database.ProvisionerJobTypeTemplateVersionDryRun:
 		var input TemplateVersionDryRunJob
 		err = json.Unmarshal(job.Input, &input)
@@ -828,6 +918,32 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 		}
 	}
 
+	if len(request.TemplateDiagnostics) > 0 {
+		templateVersion, err := s.Database.GetTemplateVersionByJobID(ctx, job.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template version by job id: %w", err)
+		}
+		for _, diagnostic := range request.TemplateDiagnostics {
+			severity := database.TemplateVersionDiagnosticSeverityWarning
+			if diagnostic.Severity == sdkproto.Diagnostic_ERROR {
+				severity = database.TemplateVersionDiagnosticSeverityError
+			}
+			_, err = s.Database.InsertTemplateVersionDiagnostic(ctx, database.InsertTemplateVersionDiagnosticParams{
+				ID:                uuid.New(),
+				TemplateVersionID: templateVersion.ID,
+				Severity:          severity,
+				Code:              diagnostic.Code,
+				Summary:           diagnostic.Summary,
+				Detail:            diagnostic.Detail,
+				Filename:          diagnostic.Filename,
+				Line:              diagnostic.Line,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("insert template version diagnostic: %w", err)
+			}
+		}
+	}
+
 	if len(request.TemplateVariables) > 0 {
 		templateVersion, err := s.Database.GetTemplateVersionByJobID(ctx, job.ID)
 		if err != nil {
@@ -1034,6 +1150,9 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 					Status:           http.StatusInternalServerError,
 					AdditionalFields: wriBytes,
 				})
+
+				s.notifyWorkspaceBuildFailed(ctx, workspace, build, failJob.Error)
+				s.publishWorkspaceBuildEvent(ctx, codersdk.WebhookEventWorkspaceBuildFailed, workspace, build, failJob.Error)
 			}
 		}
 	}
@@ -1050,6 +1169,63 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 	return &proto.Empty{}, nil
 }
 
+// notifyWorkspaceBuildFailed tells the owner of a workspace that a build
+// started on their behalf by the autobuild executor failed. Failures of
+// builds the user started themselves are already visible to them.
+func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, reason string) {
+	if build.Reason != database.BuildReasonAutostart && build.Reason != database.BuildReasonAutostop {
+		return
+	}
+
+	if _, err := s.NotificationsEnqueuer.Enqueue(ctx, workspace.OwnerID, notifications.TemplateWorkspaceAutobuildFailed, map[string]string{
+		"workspace":  workspace.Name,
+		"transition": string(build.Transition),
+		"reason":     reason,
+	}, "provisionerdserver"); err != nil {
+		s.Logger.Warn(ctx, "enqueue autobuild failure notification", slog.F("workspace_id", workspace.ID), slog.Error(err))
+	}
+}
+
+// publishWorkspaceBuildEvent sends a workspace build event to the webhooks
+// subscribed to it. buildErr is the reason a failed build failed.
+func (s *server) publishWorkspaceBuildEvent(ctx context.Context, event codersdk.WebhookEvent, workspace database.Workspace, build database.WorkspaceBuild, buildErr string) {
+	owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		s.Logger.Warn(ctx, "get workspace owner for webhook event", slog.F("workspace_id", workspace.ID), slog.Error(err))
+		return
+	}
+	s.WebhookPublisher.Publish(ctx, event, codersdk.WebhookWorkspaceBuildData{
+		BuildID:           build.ID,
+		BuildNumber:       build.BuildNumber,
+		Transition:        codersdk.WorkspaceTransition(build.Transition),
+		Reason:            codersdk.BuildReason(build.Reason),
+		WorkspaceID:       workspace.ID,
+		WorkspaceName:     workspace.Name,
+		OwnerID:           owner.ID,
+		OwnerName:         owner.Username,
+		TemplateID:        workspace.TemplateID,
+		TemplateVersionID: build.TemplateVersionID,
+		Error:             buildErr,
+	})
+}
+
+// publishWorkspaceEvent sends a workspace event to the webhooks subscribed
+// to it.
+func (s *server) publishWorkspaceEvent(ctx context.Context, event codersdk.WebhookEvent, workspace database.Workspace) {
+	owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		s.Logger.Warn(ctx, "get workspace owner for webhook event", slog.F("workspace_id", workspace.ID), slog.Error(err))
+		return
+	}
+	s.WebhookPublisher.Publish(ctx, event, codersdk.WebhookWorkspaceData{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		OwnerID:       owner.ID,
+		OwnerName:     owner.Username,
+		TemplateID:    workspace.TemplateID,
+	})
+}
+
 // CompleteJob is triggered by a provision daemon to mark a provisioner job as completed.
 func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob) (*proto.Empty, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
@@ -1150,6 +1326,51 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			}
 		}
 
+		for _, protoPreset := range jobType.TemplateImport.Presets {
+			s.Logger.Info(ctx, "inserting template import job preset",
+				slog.F("job_id", job.ID.String()),
+				slog.F("preset_name", protoPreset.Name),
+			)
+			preset, err := s.Database.InsertTemplateVersionPreset(ctx, database.InsertTemplateVersionPresetParams{
+				ID:                       uuid.New(),
+				TemplateVersionID:        input.TemplateVersionID,
+				Name:                     protoPreset.Name,
+				CreatedAt:                s.timeNow(),
+				DesiredPrebuildInstances: protoPreset.Prebuilds,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("insert preset: %w", err)
+			}
+			names := make([]string, 0, len(protoPreset.Parameters))
+			values := make([]string, 0, len(protoPreset.Parameters))
+			for _, parameter := range protoPreset.Parameters {
+				names = append(names, parameter.Name)
+				values = append(values, parameter.Value)
+			}
+			_, err = s.Database.InsertTemplateVersionPresetParameters(ctx, database.InsertTemplateVersionPresetParametersParams{
+				TemplateVersionPresetID: preset.ID,
+				Names:                   names,
+				Values:                  values,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("insert preset parameters: %w", err)
+			}
+		}
+
+		// Workspace builds and dry runs of the template version inherit the
+		// constraint from the import job, so they are only assigned to
+		// provisioner daemons able to run a satisfying engine version.
+		if jobType.TemplateImport.EngineVersionConstraint != "" {
+			err = s.Database.UpdateProvisionerJobEngineVersionConstraintByID(ctx, database.UpdateProvisionerJobEngineVersionConstraintByIDParams{
+				ID:                      jobID,
+				EngineVersionConstraint: jobType.TemplateImport.EngineVersionConstraint,
+				UpdatedAt:               dbtime.Now(),
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("update provisioner job engine version constraint: %w", err)
+			}
+		}
+
 		var completedError sql.NullString
 
 		for _, externalAuthProvider := range jobType.TemplateImport.ExternalAuthProviders {
@@ -1306,6 +1527,24 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				}
 			}
 
+			workspaceSnapshot, err := db.GetWorkspaceSnapshotByBuildID(ctx, workspaceBuild.ID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get workspace snapshot: %w", err)
+			}
+			if err == nil {
+				artifacts, err := json.Marshal(snapshotArtifacts(jobType.WorkspaceBuild.Resources))
+				if err != nil {
+					return xerrors.Errorf("marshal workspace snapshot artifacts: %w", err)
+				}
+				err = db.UpdateWorkspaceSnapshotArtifactsByID(ctx, database.UpdateWorkspaceSnapshotArtifactsByIDParams{
+					ID:        workspaceSnapshot.ID,
+					Artifacts: artifacts,
+				})
+				if err != nil {
+					return xerrors.Errorf("update workspace snapshot artifacts: %w", err)
+				}
+			}
+
 			// On start, we want to ensure that workspace agents timeout statuses
 			// are propagated. This method is simple and does not protect against
 			// notifying in edge cases like when a workspace is stopped soon
@@ -1418,6 +1657,11 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				Status:           http.StatusOK,
 				AdditionalFields: wriBytes,
 			})
+
+			s.publishWorkspaceBuildEvent(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, workspace, workspaceBuild, "")
+			if workspaceBuild.Transition == database.WorkspaceTransitionDelete {
+				s.publishWorkspaceEvent(ctx, codersdk.WebhookEventWorkspaceDeleted, workspace)
+			}
 		}
 
 		err = s.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(workspaceBuild.WorkspaceID), []byte{})
@@ -1483,6 +1727,26 @@ func (s *server) startTrace(ctx context.Context, name string, opts ...trace.Span
 	))...)
 }
 
+// SnapshotArtifactPrefix prefixes the keys of the resource metadata that
+// templates return as the artifacts of a workspace snapshot.
+const SnapshotArtifactPrefix = "snapshot."
+
+// snapshotArtifacts collects the snapshot artifacts in the metadata of the
+// given resources, keyed by name without the prefix.
+func snapshotArtifacts(resources []*sdkproto.Resource) map[string]string {
+	artifacts := map[string]string{}
+	for _, resource := range resources {
+		for _, metadatum := range resource.Metadata {
+			name, ok := strings.CutPrefix(metadatum.Key, SnapshotArtifactPrefix)
+			if !ok || name == "" || metadatum.IsNull {
+				continue
+			}
+			artifacts[name] = metadatum.Value
+		}
+	}
+	return artifacts
+}
+
 func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.UUID, transition database.WorkspaceTransition, protoResource *sdkproto.Resource, snapshot *telemetry.Snapshot) error {
 	resource, err := db.InsertWorkspaceResource(ctx, database.InsertWorkspaceResourceParams{
 		ID:         uuid.New(),
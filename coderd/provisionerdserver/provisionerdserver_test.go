@@ -32,10 +32,14 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/coderd/webhooks/webhookstest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
 	"github.com/coder/coder/v2/provisionersdk"
@@ -384,6 +388,18 @@ func TestAcquireJob(t *testing.T) {
 					WorkspaceBuildID: stopbuild.ID,
 				})),
 			})
+			// The stop build takes a snapshot, and the workspace restores
+			// another one.
+			_ = dbgen.WorkspaceSnapshot(t, db, database.WorkspaceSnapshot{
+				WorkspaceID: workspace.ID,
+				BuildID:     stopbuild.ID,
+			})
+			_, err = db.InsertWorkspaceSnapshotRestore(ctx, database.InsertWorkspaceSnapshotRestoreParams{
+				WorkspaceID: workspace.ID,
+				Artifacts:   []byte(`{"home":"vol-123"}`),
+				CreatedAt:   dbtime.Now(),
+			})
+			require.NoError(t, err)
 
 			stopPublished := make(chan struct{})
 			closeStopSubscribe, err := ps.Subscribe(codersdk.WorkspaceNotifyChannel(workspace.ID), func(_ context.Context, _ []byte) {
@@ -404,6 +420,9 @@ func TestAcquireJob(t *testing.T) {
 			// Validate that a session token is deleted during a stop job.
 			sessionToken = job.Type.(*proto.AcquiredJob_WorkspaceBuild_).WorkspaceBuild.Metadata.WorkspaceOwnerSessionToken
 			require.Empty(t, sessionToken)
+			metadata := job.Type.(*proto.AcquiredJob_WorkspaceBuild_).WorkspaceBuild.Metadata
+			require.True(t, metadata.WorkspaceSnapshot)
+			require.Equal(t, map[string]string{"home": "vol-123"}, metadata.WorkspaceSnapshotArtifacts)
 			_, err = db.GetAPIKeyByID(ctx, key.ID)
 			require.ErrorIs(t, err, sql.ErrNoRows)
 		})
@@ -652,6 +671,42 @@ func TestUpdateJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "# hello world", version.Readme)
 	})
+	t.Run("TemplateDiagnostics", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		job := setupJob(t, db, pd.ID)
+		versionID := uuid.New()
+		err := db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:    versionID,
+			JobID: job,
+		})
+		require.NoError(t, err)
+		_, err = srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.String(),
+			TemplateDiagnostics: []*sdkproto.Diagnostic{{
+				Severity: sdkproto.Diagnostic_ERROR,
+				Code:     "duplicate-app-slug",
+				Summary:  "coder_app.b has the same slug as coder_app.a",
+				Filename: "main.tf",
+				Line:     12,
+			}, {
+				Severity: sdkproto.Diagnostic_WARNING,
+				Code:     "agent-not-attached",
+				Summary:  "coder_agent.dev is not used by any resource",
+				Filename: "main.tf",
+				Line:     1,
+			}},
+		})
+		require.NoError(t, err)
+
+		diagnostics, err := db.GetTemplateVersionDiagnostics(ctx, versionID)
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 2)
+		require.Equal(t, database.TemplateVersionDiagnosticSeverityWarning, diagnostics[0].Severity)
+		require.Equal(t, "agent-not-attached", diagnostics[0].Code)
+		require.Equal(t, database.TemplateVersionDiagnosticSeverityError, diagnostics[1].Severity)
+		require.EqualValues(t, 12, diagnostics[1].Line)
+	})
 
 	t.Run("TemplateVariables", func(t *testing.T) {
 		t.Parallel()
@@ -904,6 +959,145 @@ func TestFailJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, workspace.ID, additionalFields.WorkspaceID)
 	})
+	t.Run("AutobuildNotification", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			reason database.BuildReason
+			notify bool
+		}{
+			{reason: database.BuildReasonAutostart, notify: true},
+			{reason: database.BuildReasonAutostop, notify: true},
+			{reason: database.BuildReasonInitiator, notify: false},
+		} {
+			tc := tc
+			t.Run(string(tc.reason), func(t *testing.T) {
+				t.Parallel()
+
+				enqueuer := &notificationstest.FakeEnqueuer{}
+				srv, db, _, pd := setup(t, true, &overrides{
+					notificationsEnqueuer: enqueuer,
+				})
+				user := dbgen.User(t, db, database.User{})
+				org := dbgen.Organization(t, db, database.Organization{})
+				workspace := dbgen.Workspace(t, db, database.Workspace{
+					OwnerID:        user.ID,
+					OrganizationID: org.ID,
+				})
+				buildID := uuid.New()
+				input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+					WorkspaceBuildID: buildID,
+				})
+				require.NoError(t, err)
+				job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+					ID:            uuid.New(),
+					Input:         input,
+					Provisioner:   database.ProvisionerTypeEcho,
+					Type:          database.ProvisionerJobTypeWorkspaceBuild,
+					StorageMethod: database.ProvisionerStorageMethodFile,
+				})
+				require.NoError(t, err)
+				err = db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+					ID:          buildID,
+					WorkspaceID: workspace.ID,
+					Transition:  database.WorkspaceTransitionStart,
+					Reason:      tc.reason,
+					JobID:       job.ID,
+				})
+				require.NoError(t, err)
+				_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+					WorkerID: uuid.NullUUID{
+						UUID:  pd.ID,
+						Valid: true,
+					},
+					Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+				})
+				require.NoError(t, err)
+
+				_, err = srv.FailJob(ctx, &proto.FailedJob{
+					JobId: job.ID.String(),
+					Error: "docker daemon unreachable",
+					Type: &proto.FailedJob_WorkspaceBuild_{
+						WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{},
+					},
+				})
+				require.NoError(t, err)
+
+				sent := enqueuer.Sent(notifications.TemplateWorkspaceAutobuildFailed)
+				if !tc.notify {
+					require.Empty(t, sent)
+					return
+				}
+				require.Len(t, sent, 1)
+				require.Equal(t, user.ID, sent[0].UserID)
+				require.Equal(t, workspace.Name, sent[0].Labels["workspace"])
+				require.Equal(t, "start", sent[0].Labels["transition"])
+				require.Equal(t, "docker daemon unreachable", sent[0].Labels["reason"])
+			})
+		}
+	})
+	t.Run("WebhookEvent", func(t *testing.T) {
+		t.Parallel()
+
+		publisher := &webhookstest.FakePublisher{}
+		srv, db, _, pd := setup(t, true, &overrides{
+			webhookPublisher: publisher,
+		})
+		user := dbgen.User(t, db, database.User{})
+		org := dbgen.Organization(t, db, database.Organization{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+		})
+		buildID := uuid.New()
+		input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+			WorkspaceBuildID: buildID,
+		})
+		require.NoError(t, err)
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            uuid.New(),
+			Input:         input,
+			Provisioner:   database.ProvisionerTypeEcho,
+			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+		})
+		require.NoError(t, err)
+		err = db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+			ID:          buildID,
+			WorkspaceID: workspace.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Reason:      database.BuildReasonInitiator,
+			JobID:       job.ID,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.FailJob(ctx, &proto.FailedJob{
+			JobId: job.ID.String(),
+			Error: "docker daemon unreachable",
+			Type: &proto.FailedJob_WorkspaceBuild_{
+				WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{},
+			},
+		})
+		require.NoError(t, err)
+
+		published := publisher.Published(codersdk.WebhookEventWorkspaceBuildFailed)
+		require.Len(t, published, 1)
+		data, ok := published[0].Data.(codersdk.WebhookWorkspaceBuildData)
+		require.True(t, ok)
+		require.Equal(t, buildID, data.BuildID)
+		require.Equal(t, workspace.ID, data.WorkspaceID)
+		require.Equal(t, user.Username, data.OwnerName)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, data.Transition)
+		require.Equal(t, "docker daemon unreachable", data.Error)
+	})
 }
 
 func TestCompleteJob(t *testing.T) {
@@ -1057,6 +1251,126 @@ func TestCompleteJob(t *testing.T) {
 		require.False(t, job.Error.Valid)
 	})
 
+	t.Run("TemplateImport_EngineVersionConstraint", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		jobID := uuid.New()
+		versionID := uuid.New()
+		err := db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:             versionID,
+			JobID:          jobID,
+			OrganizationID: pd.OrganizationID,
+		})
+		require.NoError(t, err)
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			ID:             jobID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			Input:          []byte(`{"template_version_id": "` + versionID.String() + `"}`),
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{
+					StartResources:          []*sdkproto.Resource{},
+					StopResources:           []*sdkproto.Resource{},
+					EngineVersionConstraint: "~> 1.5.0",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		// Builds of the template version inherit the constraint from the
+		// import job.
+		job, err = db.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, "~> 1.5.0", job.EngineVersionConstraint)
+	})
+
+	t.Run("TemplateImport_WithPresets", func(t *testing.T) {
+		t.Parallel()
+		srv, db, _, pd := setup(t, false, &overrides{})
+		jobID := uuid.New()
+		versionID := uuid.New()
+		err := db.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:             versionID,
+			JobID:          jobID,
+			OrganizationID: pd.OrganizationID,
+		})
+		require.NoError(t, err)
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			ID:             jobID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			Input:          []byte(`{"template_version_id": "` + versionID.String() + `"}`),
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			OrganizationID: pd.OrganizationID,
+			WorkerID: uuid.NullUUID{
+				UUID:  pd.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{
+					StartResources: []*sdkproto.Resource{},
+					StopResources:  []*sdkproto.Resource{},
+					RichParameters: []*sdkproto.RichParameter{{
+						Name: "cpu",
+						Type: "number",
+					}, {
+						Name: "region",
+						Type: "string",
+					}},
+					Presets: []*sdkproto.Preset{{
+						Name: "Large",
+						Parameters: []*sdkproto.PresetParameter{{
+							Name:  "cpu",
+							Value: "8",
+						}, {
+							Name:  "region",
+							Value: "eu",
+						}},
+						Prebuilds: 2,
+					}},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		presets, err := db.GetTemplateVersionPresets(ctx, versionID)
+		require.NoError(t, err)
+		require.Len(t, presets, 1)
+		require.Equal(t, "Large", presets[0].Name)
+		require.EqualValues(t, 2, presets[0].DesiredPrebuildInstances)
+		parameters, err := db.GetTemplateVersionPresetParametersByPresetID(ctx, presets[0].ID)
+		require.NoError(t, err)
+		require.Len(t, parameters, 2)
+		require.Equal(t, "cpu", parameters[0].Name)
+		require.Equal(t, "8", parameters[0].Value)
+		require.Equal(t, "region", parameters[1].Name)
+		require.Equal(t, "eu", parameters[1].Value)
+	})
+
 	t.Run("WorkspaceBuild", func(t *testing.T) {
 		t.Parallel()
 
@@ -1533,6 +1847,8 @@ type overrides struct {
 	heartbeatFn                 func(ctx context.Context) error
 	heartbeatInterval           time.Duration
 	auditor                     audit.Auditor
+	notificationsEnqueuer       notifications.Enqueuer
+	webhookPublisher            webhooks.Publisher
 }
 
 func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisionerDaemonServer, database.Store, pubsub.Pubsub, database.ProvisionerDaemon) {
@@ -1594,6 +1910,14 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 	}
 	auditPtr.Store(&auditor)
 	pollDur = ov.acquireJobLongPollDuration
+	var enqueuer notifications.Enqueuer = notifications.NewNoopEnqueuer()
+	if ov.notificationsEnqueuer != nil {
+		enqueuer = ov.notificationsEnqueuer
+	}
+	var webhookPublisher webhooks.Publisher = webhooks.NewNoopPublisher()
+	if ov.webhookPublisher != nil {
+		webhookPublisher = ov.webhookPublisher
+	}
 
 	daemon, err := db.UpsertProvisionerDaemon(ov.ctx, database.UpsertProvisionerDaemonParams{
 		Name:           "test",
@@ -1625,6 +1949,8 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 		tss,
 		uqhss,
 		deploymentValues,
+		enqueuer,
+		webhookPublisher,
 		provisionerdserver.Options{
 			ExternalAuthConfigs:   externalAuthConfigs,
 			TimeNowFn:             timeNowFn,
@@ -0,0 +1,88 @@
+package rbac
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+)
+
+// CustomRoleStore looks up the roles that are defined at runtime, as opposed
+// to the built in roles in this package.
+type CustomRoleStore interface {
+	// CustomRoles returns the custom roles with the given names. An error is
+	// returned if any of the names is not a custom role.
+	CustomRoles(ctx context.Context, names []string) ([]Role, error)
+}
+
+// WithCustomRoles returns an Authorizer that expands the custom roles of
+// subjects with the store before calling the wrapped Authorizer. Subjects
+// with only built in roles are passed through as is.
+//
+// The expanded subject contains the permissions of its roles, so the Cacher
+// never returns results for outdated custom roles.
+func WithCustomRoles(authz Authorizer, store CustomRoleStore) Authorizer {
+	return &customRoleAuthorizer{
+		authz: authz,
+		store: store,
+	}
+}
+
+type customRoleAuthorizer struct {
+	authz Authorizer
+	store CustomRoleStore
+}
+
+var _ Authorizer = (*customRoleAuthorizer)(nil)
+
+func (c *customRoleAuthorizer) Authorize(ctx context.Context, subject Subject, action Action, object Object) error {
+	subject, err := c.expand(ctx, subject)
+	if err != nil {
+		return err
+	}
+	return c.authz.Authorize(ctx, subject, action, object)
+}
+
+func (c *customRoleAuthorizer) Prepare(ctx context.Context, subject Subject, action Action, objectType string) (PreparedAuthorized, error) {
+	subject, err := c.expand(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	return c.authz.Prepare(ctx, subject, action, objectType)
+}
+
+// Unwrap returns the wrapped Authorizer.
+func (c *customRoleAuthorizer) Unwrap() Authorizer {
+	return c.authz
+}
+
+func (c *customRoleAuthorizer) expand(ctx context.Context, subject Subject) (Subject, error) {
+	// A cached value means all roles were expanded without the store.
+	if subject.cachedASTValue != nil {
+		return subject, nil
+	}
+	names, ok := subject.Roles.(RoleNames)
+	if !ok {
+		return subject, nil
+	}
+
+	roles := make([]Role, 0, len(names))
+	var custom []string
+	for _, name := range names {
+		role, err := RoleByName(name)
+		if err != nil {
+			custom = append(custom, name)
+			continue
+		}
+		roles = append(roles, role)
+	}
+	if len(custom) == 0 {
+		return subject, nil
+	}
+
+	customRoles, err := c.store.CustomRoles(ctx, custom)
+	if err != nil {
+		return Subject{}, xerrors.Errorf("expand custom roles: %w", err)
+	}
+	subject.Roles = Roles(append(roles, customRoles...))
+	return subject, nil
+}
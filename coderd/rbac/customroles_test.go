@@ -0,0 +1,100 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+type fakeCustomRoleStore map[string]rbac.Role
+
+func (s fakeCustomRoleStore) CustomRoles(_ context.Context, names []string) ([]rbac.Role, error) {
+	roles := make([]rbac.Role, 0, len(names))
+	for _, name := range names {
+		role, ok := s[name]
+		if !ok {
+			return nil, xerrors.Errorf("role %q not found", name)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func TestWithCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	templateOperator := rbac.Role{
+		Name:        "template-operator",
+		DisplayName: "Template Operator",
+		Site: []rbac.Permission{
+			{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionRead},
+			{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionUpdate},
+		},
+		Org:  map[string][]rbac.Permission{},
+		User: []rbac.Permission{},
+	}
+	auth := rbac.WithCustomRoles(rbac.NewCachingAuthorizer(prometheus.NewRegistry()), fakeCustomRoleStore{
+		templateOperator.Name: templateOperator,
+	})
+
+	t.Run("Expanded", func(t *testing.T) {
+		t.Parallel()
+		subject := rbac.Subject{
+			ID:    uuid.NewString(),
+			Roles: rbac.RoleNames{rbac.RoleMember(), templateOperator.Name},
+			Scope: rbac.ScopeAll,
+		}
+		template := rbac.ResourceTemplate.WithID(uuid.New()).InOrg(orgID)
+
+		err := auth.Authorize(context.Background(), subject, rbac.ActionUpdate, template)
+		require.NoError(t, err)
+		err = auth.Authorize(context.Background(), subject, rbac.ActionDelete, template)
+		require.Error(t, err)
+		require.True(t, rbac.IsUnauthorizedError(err))
+
+		// Built in roles of the subject still apply.
+		err = auth.Authorize(context.Background(), subject, rbac.ActionRead, rbac.ResourceUserObject(uuid.MustParse(subject.ID)))
+		require.NoError(t, err)
+	})
+
+	t.Run("Prepared", func(t *testing.T) {
+		t.Parallel()
+		subject := rbac.Subject{
+			ID:    uuid.NewString(),
+			Roles: rbac.RoleNames{rbac.RoleMember(), templateOperator.Name},
+			Scope: rbac.ScopeAll,
+		}
+		prepared, err := auth.Prepare(context.Background(), subject, rbac.ActionUpdate, rbac.ResourceTemplate.Type)
+		require.NoError(t, err)
+		err = prepared.Authorize(context.Background(), rbac.ResourceTemplate.WithID(uuid.New()).InOrg(orgID))
+		require.NoError(t, err)
+	})
+
+	t.Run("UnknownRole", func(t *testing.T) {
+		t.Parallel()
+		subject := rbac.Subject{
+			ID:    uuid.NewString(),
+			Roles: rbac.RoleNames{rbac.RoleMember(), "unknown"},
+			Scope: rbac.ScopeAll,
+		}
+		err := auth.Authorize(context.Background(), subject, rbac.ActionRead, rbac.ResourceTemplate.InOrg(orgID))
+		require.ErrorContains(t, err, `role "unknown" not found`)
+	})
+}
+
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, rbac.CanAssignRole(rbac.RoleNames{rbac.RoleOwner()}, "template-operator"))
+	require.True(t, rbac.CanAssignRole(rbac.RoleNames{rbac.RoleOwner()}, rbac.RoleName("template-operator", uuid.NewString())))
+	require.False(t, rbac.CanAssignRole(rbac.RoleNames{rbac.RoleUserAdmin()}, "template-operator"))
+	orgID := uuid.New()
+	require.False(t, rbac.CanAssignRole(rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, rbac.RoleName("template-operator", orgID.String())))
+}
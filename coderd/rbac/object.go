@@ -37,6 +37,15 @@ var (
 		Type: "workspace_build",
 	}
 
+	// ResourceWorkspaceBulkOperation is a build of many workspaces requested
+	// at once. It is owned by the user who started it.
+	//	create = Start a bulk operation.
+	//	read = View the progress of a bulk operation.
+	//	update = Record the outcome of its builds.
+	ResourceWorkspaceBulkOperation = Object{
+		Type: "workspace_bulk_operation",
+	}
+
 	// ResourceWorkspaceDormant is returned if a workspace is dormant.
 	// It grants restricted permissions on workspace builds.
 	ResourceWorkspaceDormant = Object{
@@ -124,6 +133,15 @@ var (
 		Type: "assign_org_role",
 	}
 
+	// ResourceCustomRole is a role defined at runtime, for the site or an
+	// organization. Custom roles are always managed at the site level.
+	//	create/delete = Define or remove a custom role
+	//	read = View custom roles and their permissions
+	//	update = Change the permissions of a custom role
+	ResourceCustomRole = Object{
+		Type: "custom_role",
+	}
+
 	// ResourceAPIKey is owned by a user.
 	//	create  = Create a new api key for user
 	//	update  = ??
@@ -228,6 +246,30 @@ var (
 	ResourceOAuth2ProviderAppCodeToken = Object{
 		Type: "oauth2_app_code_token",
 	}
+
+	// ResourceWebhook CRUD. Webhooks are site wide.
+	//	create/delete = Add or remove a webhook endpoint.
+	//	update = Change the URL, events or secret of a webhook.
+	//	read = Read webhooks and their delivery log.
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
+
+	// ResourceOIDCSyncRule CRUD. Sync rules are site wide.
+	//	create/delete = Add or remove a sync rule.
+	//	update = Change the claim, pattern or granted roles of a sync rule.
+	//	read = Read sync rules.
+	ResourceOIDCSyncRule = Object{
+		Type: "oidc_sync_rule",
+	}
+
+	// ResourceSCIMToken CRUD. SCIM tokens are site wide.
+	//	create/delete = Issue or revoke a SCIM bearer token.
+	//	update = Record when a token was last used.
+	//	read = List SCIM tokens, or look one up to authenticate a request.
+	ResourceSCIMToken = Object{
+		Type: "scim_token",
+	}
 )
 
 // ResourceUserObject is a helper function to create a user object for authz checks.
@@ -5,6 +5,7 @@ func AllResources() []Object {
 	return []Object{
 		ResourceAPIKey,
 		ResourceAuditLog,
+		ResourceCustomRole,
 		ResourceDebugInfo,
 		ResourceDeploymentStats,
 		ResourceDeploymentValues,
@@ -14,12 +15,14 @@ func AllResources() []Object {
 		ResourceOAuth2ProviderApp,
 		ResourceOAuth2ProviderAppCodeToken,
 		ResourceOAuth2ProviderAppSecret,
+		ResourceOIDCSyncRule,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
 		ResourceProvisionerDaemon,
 		ResourceReplicas,
 		ResourceRoleAssignment,
+		ResourceSCIMToken,
 		ResourceSystem,
 		ResourceTailnetCoordinator,
 		ResourceTemplate,
@@ -27,10 +30,12 @@ func AllResources() []Object {
 		ResourceUser,
 		ResourceUserData,
 		ResourceUserWorkspaceBuildParameters,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
 		ResourceWorkspaceBuild,
+		ResourceWorkspaceBulkOperation,
 		ResourceWorkspaceDormant,
 		ResourceWorkspaceExecution,
 		ResourceWorkspaceProxy,
@@ -0,0 +1,174 @@
+// Package rolestore loads the custom roles stored in the database for the
+// authorizer.
+package rolestore
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// EventCustomRolesChanged is published after a custom role is created,
+// updated or deleted, so every replica reloads its custom roles.
+const EventCustomRolesChanged = "custom_roles_changed"
+
+// Store caches the custom roles of the deployment. The cache is loaded on
+// first use and dropped whenever any replica changes a custom role.
+type Store struct {
+	db     database.Store
+	logger slog.Logger
+
+	mu sync.Mutex
+	// roles is keyed by the assignable role name, nil until loaded.
+	roles map[string]rbac.Role
+	// generation is increased on every invalidation, so a load that started
+	// before an invalidation doesn't store outdated roles.
+	generation uint64
+}
+
+var _ rbac.CustomRoleStore = (*Store)(nil)
+
+// New returns a Store that loads the custom roles from db. The database must
+// not be wrapped with dbauthz, as the roles are loaded to authorize.
+func New(db database.Store, logger slog.Logger) *Store {
+	return &Store{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Subscribe invalidates the cache whenever a custom role changes on any
+// replica.
+func (s *Store) Subscribe(ps pubsub.Pubsub) (cancel func(), err error) {
+	return ps.SubscribeWithErr(EventCustomRolesChanged, func(_ context.Context, _ []byte, err error) {
+		if err != nil {
+			// Messages may have been dropped, so the cache may be outdated.
+			s.logger.Warn(context.Background(), "custom roles subscription error", slog.Error(err))
+		}
+		s.Invalidate()
+	})
+}
+
+// Changed invalidates the cache of this replica and notifies the others. It
+// must be called after a custom role is created, updated or deleted.
+func (s *Store) Changed(ctx context.Context, ps pubsub.Pubsub) {
+	s.Invalidate()
+	err := ps.Publish(EventCustomRolesChanged, nil)
+	if err != nil {
+		s.logger.Error(ctx, "publish custom roles changed", slog.Error(err))
+	}
+}
+
+// Invalidate drops the cached custom roles.
+func (s *Store) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles = nil
+	s.generation++
+}
+
+// CustomRoles returns the custom roles with the given assignable names. The
+// roles are reloaded once if a name is missing, in case another replica
+// created the role before this replica was notified.
+func (s *Store) CustomRoles(ctx context.Context, names []string) ([]rbac.Role, error) {
+	roles, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reloaded := false
+	found := make([]rbac.Role, 0, len(names))
+	for _, name := range names {
+		role, ok := roles[name]
+		if !ok && !reloaded {
+			s.Invalidate()
+			roles, err = s.load(ctx)
+			if err != nil {
+				return nil, err
+			}
+			reloaded = true
+			role, ok = roles[name]
+		}
+		if !ok {
+			return nil, xerrors.Errorf("role %q not found", name)
+		}
+		found = append(found, role)
+	}
+	return found, nil
+}
+
+// Roles returns all custom roles, sorted with site roles first.
+func (s *Store) Roles(ctx context.Context) ([]rbac.Role, error) {
+	dbRoles, err := s.db.GetCustomRoles(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom roles: %w", err)
+	}
+	roles := make([]rbac.Role, 0, len(dbRoles))
+	for _, dbRole := range dbRoles {
+		roles = append(roles, ConvertDBRole(dbRole))
+	}
+	return roles, nil
+}
+
+func (s *Store) load(ctx context.Context) (map[string]rbac.Role, error) {
+	s.mu.Lock()
+	if s.roles != nil {
+		roles := s.roles
+		s.mu.Unlock()
+		return roles, nil
+	}
+	generation := s.generation
+	s.mu.Unlock()
+
+	list, err := s.Roles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]rbac.Role, len(list))
+	for _, role := range list {
+		roles[role.Name] = role
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.roles = roles
+	}
+	s.mu.Unlock()
+	return roles, nil
+}
+
+// RoleName returns the assignable name of a custom role. Organization roles
+// are suffixed with their organization ID, like the built in ones.
+func RoleName(role database.CustomRole) string {
+	if !role.OrganizationID.Valid {
+		return role.Name
+	}
+	return rbac.RoleName(role.Name, role.OrganizationID.UUID.String())
+}
+
+// ConvertDBRole converts a custom role to the rbac role it expands to.
+func ConvertDBRole(role database.CustomRole) rbac.Role {
+	converted := rbac.Role{
+		Name:        RoleName(role),
+		DisplayName: role.DisplayName,
+		Site:        []rbac.Permission(role.SitePermissions),
+		Org:         map[string][]rbac.Permission{},
+		User:        []rbac.Permission(role.UserPermissions),
+	}
+	if converted.Site == nil {
+		converted.Site = []rbac.Permission{}
+	}
+	if converted.User == nil {
+		converted.User = []rbac.Permission{}
+	}
+	if role.OrganizationID.Valid && len(role.OrgPermissions) > 0 {
+		converted.Org[role.OrganizationID.UUID.String()] = []rbac.Permission(role.OrgPermissions)
+	}
+	return converted
+}
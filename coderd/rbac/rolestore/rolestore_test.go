@@ -0,0 +1,106 @@
+package rolestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CustomRoles", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := dbmem.New()
+		orgID := uuid.New()
+		site := dbgen.CustomRole(t, db, database.CustomRole{
+			SitePermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionUpdate},
+			},
+		})
+		org := dbgen.CustomRole(t, db, database.CustomRole{
+			Name:           site.Name,
+			OrganizationID: uuid.NullUUID{UUID: orgID, Valid: true},
+			OrgPermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionRead},
+			},
+		})
+		store := rolestore.New(db, slogtest.Make(t, nil))
+
+		roles, err := store.CustomRoles(ctx, []string{site.Name, rolestore.RoleName(org)})
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+		require.Equal(t, site.Name, roles[0].Name)
+		require.Equal(t, []rbac.Permission(site.SitePermissions), roles[0].Site)
+		require.Equal(t, rbac.RoleName(org.Name, orgID.String()), roles[1].Name)
+		require.Equal(t, []rbac.Permission(org.OrgPermissions), roles[1].Org[orgID.String()])
+
+		_, err = store.CustomRoles(ctx, []string{"unknown"})
+		require.ErrorContains(t, err, `role "unknown" not found`)
+	})
+
+	t.Run("ReloadMissing", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := dbmem.New()
+		store := rolestore.New(db, slogtest.Make(t, nil))
+
+		first := dbgen.CustomRole(t, db, database.CustomRole{})
+		_, err := store.CustomRoles(ctx, []string{first.Name})
+		require.NoError(t, err)
+
+		// A role created by another replica is found without a notification.
+		second := dbgen.CustomRole(t, db, database.CustomRole{})
+		roles, err := store.CustomRoles(ctx, []string{second.Name})
+		require.NoError(t, err)
+		require.Equal(t, second.Name, roles[0].Name)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db := dbmem.New()
+		ps := pubsub.NewInMemory()
+		store := rolestore.New(db, slogtest.Make(t, nil))
+		other := rolestore.New(db, slogtest.Make(t, nil))
+		cancel, err := other.Subscribe(ps)
+		require.NoError(t, err)
+		defer cancel()
+
+		role := dbgen.CustomRole(t, db, database.CustomRole{})
+		roles, err := other.CustomRoles(ctx, []string{role.Name})
+		require.NoError(t, err)
+		require.Empty(t, roles[0].Site)
+
+		_, err = db.UpdateCustomRole(ctx, database.UpdateCustomRoleParams{
+			ID:          role.ID,
+			DisplayName: role.DisplayName,
+			SitePermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionUpdate},
+			},
+			OrgPermissions:  role.OrgPermissions,
+			UserPermissions: role.UserPermissions,
+			UpdatedAt:       dbtime.Now(),
+		})
+		require.NoError(t, err)
+		store.Changed(ctx, ps)
+
+		require.Eventually(t, func() bool {
+			roles, err := other.CustomRoles(ctx, []string{role.Name})
+			return err == nil && len(roles[0].Site) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
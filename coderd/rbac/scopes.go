@@ -48,6 +48,15 @@ func WorkspaceAgentScope(params WorkspaceAgentScopeParams) Scope {
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
+
+	// The scopes below are meant for API tokens, e.g. in CI, that need only
+	// a small part of the user's permissions. A scope never grants more
+	// than the roles of the user allow.
+	ScopeWorkspaceRead      ScopeName = "workspace:read"
+	ScopeWorkspaceStartStop ScopeName = "workspace:start-stop"
+	ScopeTemplateRead       ScopeName = "template:read"
+	ScopeTemplatePush       ScopeName = "template:push"
+	ScopeUserRead           ScopeName = "user:read"
 )
 
 // TODO: Support passing in scopeID list for allowlisting resources.
@@ -79,6 +88,60 @@ var builtinScopes = map[ScopeName]Scope{
 		},
 		AllowIDList: []string{WildcardSymbol},
 	},
+
+	ScopeWorkspaceRead: tokenScope(ScopeWorkspaceRead, "Read workspaces", map[string][]Action{
+		ResourceWorkspace.Type: {ActionRead},
+		ResourceTemplate.Type:  {ActionRead},
+	}),
+
+	// Starting and stopping a workspace is authorized as an update of the
+	// workspace, so the scope also allows changing the workspace settings.
+	// Creating and deleting workspaces is not allowed, as both change how
+	// much of the quota the user spends.
+	ScopeWorkspaceStartStop: tokenScope(ScopeWorkspaceStartStop, "Start and stop workspaces", map[string][]Action{
+		ResourceWorkspace.Type:                    {ActionRead, ActionUpdate},
+		ResourceWorkspaceBuild.Type:               {ActionRead, ActionUpdate},
+		ResourceTemplate.Type:                     {ActionRead},
+		ResourceUserWorkspaceBuildParameters.Type: {ActionRead},
+	}),
+
+	ScopeTemplateRead: tokenScope(ScopeTemplateRead, "Read templates", map[string][]Action{
+		ResourceTemplate.Type: {ActionRead},
+	}),
+
+	// Pushing a template uploads its files, creates a template version and
+	// promotes it. Deleting a template is not allowed.
+	ScopeTemplatePush: tokenScope(ScopeTemplatePush, "Push template versions", map[string][]Action{
+		ResourceTemplate.Type: {ActionCreate, ActionRead, ActionUpdate},
+		ResourceFile.Type:     {ActionCreate, ActionRead},
+	}),
+
+	ScopeUserRead: tokenScope(ScopeUserRead, "Read users", map[string][]Action{}),
+}
+
+// tokenScope returns a scope for API tokens that allows the given
+// permissions. Every token scope can read users and organizations, as
+// clients need them to resolve names like "me".
+func tokenScope(name ScopeName, displayName string, perms map[string][]Action) Scope {
+	site := map[string][]Action{
+		ResourceUser.Type:               {ActionRead},
+		ResourceUserData.Type:           {ActionRead},
+		ResourceOrganization.Type:       {ActionRead},
+		ResourceOrganizationMember.Type: {ActionRead},
+	}
+	for resource, actions := range perms {
+		site[resource] = append(site[resource], actions...)
+	}
+	return Scope{
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", name),
+			DisplayName: displayName,
+			Site:        Permissions(site),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	}
 }
 
 type ExpandableScope interface {
@@ -10,7 +10,8 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 )
 
-// assignableSiteRoles returns all site wide roles that can be assigned.
+// assignableSiteRoles returns all site wide roles that can be assigned,
+// including custom roles.
 //
 // @Summary Get site member roles
 // @ID get-site-member-roles
@@ -27,11 +28,26 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoles, err := api.customRoleStore.Roles(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	roles := rbac.SiteRoles()
+	for _, role := range customRoles {
+		if _, ok := rbac.IsOrgRole(role.Name); !ok {
+			roles = append(roles, role)
+		}
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Roles, roles))
 }
 
-// assignableSiteRoles returns all org wide roles that can be assigned.
+// assignableOrgRoles returns all org wide roles that can be assigned,
+// including the custom roles of the organization.
 //
 // @Summary Get member roles by organization
 // @ID get-member-roles-by-organization
@@ -51,7 +67,21 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoles, err := api.customRoleStore.Roles(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	roles := rbac.OrganizationRoles(organization.ID)
+	for _, role := range customRoles {
+		if orgID, ok := rbac.IsOrgRole(role.Name); ok && orgID == organization.ID.String() {
+			roles = append(roles, role)
+		}
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Roles, roles))
 }
 
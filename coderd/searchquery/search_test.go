@@ -1,6 +1,7 @@
 package searchquery_test
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/codersdk"
@@ -127,6 +130,13 @@ func TestSearchWorkspace(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:  "Shared",
+			Query: `shared:true`,
+			Expected: database.GetWorkspacesParams{
+				Shared: true,
+			},
+		},
 		{
 			Name:  "Updated",
 			Query: `outdated:false`,
@@ -241,7 +251,7 @@ func TestSearchWorkspace(t *testing.T) {
 		c := c
 		t.Run(c.Name, func(t *testing.T) {
 			t.Parallel()
-			values, errs := searchquery.Workspaces(c.Query, codersdk.Pagination{}, 0)
+			values, errs := searchquery.Workspaces(context.Background(), dbmem.New(), c.Query, codersdk.Pagination{}, 0)
 			if c.ExpectedErrorContains != "" {
 				assert.True(t, len(errs) > 0, "expect some errors")
 				var s strings.Builder
@@ -268,10 +278,28 @@ func TestSearchWorkspace(t *testing.T) {
 
 		query := ``
 		timeout := 1337 * time.Second
-		values, errs := searchquery.Workspaces(query, codersdk.Pagination{}, timeout)
+		values, errs := searchquery.Workspaces(context.Background(), dbmem.New(), query, codersdk.Pagination{}, timeout)
 		require.Empty(t, errs)
 		require.Equal(t, int64(timeout.Seconds()), values.AgentInactiveDisconnectTimeoutSeconds)
 	})
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		org := dbgen.Organization(t, db, database.Organization{Name: "acme"})
+
+		values, errs := searchquery.Workspaces(context.Background(), db, "organization:acme", codersdk.Pagination{}, 0)
+		require.Empty(t, errs)
+		require.Equal(t, org.ID, values.OrganizationID)
+
+		values, errs = searchquery.Workspaces(context.Background(), db, "organization:"+org.ID.String(), codersdk.Pagination{}, 0)
+		require.Empty(t, errs)
+		require.Equal(t, org.ID, values.OrganizationID)
+
+		_, errs = searchquery.Workspaces(context.Background(), db, "organization:unknown", codersdk.Pagination{}, 0)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0].Detail, `organization "unknown" either does not exist`)
+	})
 }
 
 func TestSearchAudit(t *testing.T) {
@@ -316,7 +344,7 @@ func TestSearchAudit(t *testing.T) {
 		c := c
 		t.Run(c.Name, func(t *testing.T) {
 			t.Parallel()
-			values, errs := searchquery.AuditLogs(c.Query)
+			values, errs := searchquery.AuditLogs(context.Background(), dbmem.New(), c.Query)
 			if c.ExpectedErrorContains != "" {
 				require.True(t, len(errs) > 0, "expect some errors")
 				var s strings.Builder
@@ -330,6 +358,20 @@ func TestSearchAudit(t *testing.T) {
 			}
 		})
 	}
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		org := dbgen.Organization(t, db, database.Organization{Name: "acme"})
+
+		values, errs := searchquery.AuditLogs(context.Background(), db, "organization:acme")
+		require.Empty(t, errs)
+		require.Equal(t, org.ID, values.OrganizationID)
+
+		_, errs = searchquery.AuditLogs(context.Background(), db, "organization:unknown")
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0].Detail, `organization "unknown" either does not exist`)
+	})
 }
 
 func TestSearchUsers(t *testing.T) {
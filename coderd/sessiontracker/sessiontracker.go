@@ -0,0 +1,128 @@
+// Package sessiontracker ends the requests of revoked API keys on every
+// replica. Deleting a key stops new requests from authenticating, but
+// long-lived requests such as websockets authenticated before the key was
+// deleted and would otherwise stay open.
+package sessiontracker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// EventKeysRevoked is published with the IDs of revoked API keys, so every
+// replica cancels the requests that authenticated with them.
+const EventKeysRevoked = "api_keys_revoked"
+
+// maxKeysPerMessage keeps messages well below the Postgres NOTIFY payload
+// limit of 8000 bytes. API key IDs are 10 characters long.
+const maxKeysPerMessage = 500
+
+// ErrRevoked is the cause of request contexts cancelled because their API
+// key was revoked.
+var ErrRevoked = xerrors.New("API key revoked")
+
+// Tracker tracks the requests in flight per API key.
+type Tracker struct {
+	logger slog.Logger
+
+	mu sync.Mutex
+	// requests is keyed by API key ID.
+	requests map[string]map[*request]struct{}
+}
+
+type request struct {
+	cancel context.CancelCauseFunc
+}
+
+// New returns a Tracker that tracks no requests.
+func New(logger slog.Logger) *Tracker {
+	return &Tracker{
+		logger:   logger,
+		requests: make(map[string]map[*request]struct{}),
+	}
+}
+
+// Track returns a context that is cancelled with ErrRevoked if the API key
+// is revoked. The returned function must be called once the request is done.
+func (t *Tracker) Track(ctx context.Context, keyID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	req := &request{cancel: cancel}
+
+	t.mu.Lock()
+	reqs, ok := t.requests[keyID]
+	if !ok {
+		reqs = make(map[*request]struct{})
+		t.requests[keyID] = reqs
+	}
+	reqs[req] = struct{}{}
+	t.mu.Unlock()
+
+	return ctx, func() {
+		t.mu.Lock()
+		// The key's requests are dropped once it is revoked.
+		if tracked, ok := t.requests[keyID]; ok {
+			delete(tracked, req)
+			if len(tracked) == 0 {
+				delete(t.requests, keyID)
+			}
+		}
+		t.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// Subscribe cancels the requests of keys revoked on any replica.
+func (t *Tracker) Subscribe(ps pubsub.Pubsub) (cancel func(), err error) {
+	return ps.SubscribeWithErr(EventKeysRevoked, func(ctx context.Context, message []byte, err error) {
+		if err != nil {
+			// Revoked keys are deleted from the database, so dropped messages
+			// only leave requests open that were already in flight.
+			t.logger.Warn(ctx, "revoked API keys subscription error", slog.Error(err))
+			return
+		}
+		var keyIDs []string
+		err = json.Unmarshal(message, &keyIDs)
+		if err != nil {
+			t.logger.Warn(ctx, "invalid revoked API keys message", slog.Error(err))
+			return
+		}
+		t.cancel(keyIDs)
+	})
+}
+
+// Revoked cancels the requests of the keys on this replica and notifies the
+// others. It must be called after the keys are deleted.
+func (t *Tracker) Revoked(ctx context.Context, ps pubsub.Pubsub, keyIDs ...string) {
+	t.cancel(keyIDs)
+	for len(keyIDs) > 0 {
+		batch := keyIDs[:min(len(keyIDs), maxKeysPerMessage)]
+		keyIDs = keyIDs[len(batch):]
+
+		message, err := json.Marshal(batch)
+		if err != nil {
+			t.logger.Error(ctx, "marshal revoked API keys", slog.Error(err))
+			return
+		}
+		err = ps.Publish(EventKeysRevoked, message)
+		if err != nil {
+			t.logger.Error(ctx, "publish revoked API keys", slog.Error(err))
+		}
+	}
+}
+
+func (t *Tracker) cancel(keyIDs []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, keyID := range keyIDs {
+		for req := range t.requests[keyID] {
+			req.cancel(ErrRevoked)
+		}
+		delete(t.requests, keyID)
+	}
+}
@@ -0,0 +1,59 @@
+package sessiontracker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/sessiontracker"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Revoked", func(t *testing.T) {
+		t.Parallel()
+		tracker := sessiontracker.New(slogtest.Make(t, nil))
+		ps := pubsub.NewInMemory()
+
+		revokedCtx, doneRevoked := tracker.Track(context.Background(), "revoked")
+		defer doneRevoked()
+		otherCtx, doneOther := tracker.Track(context.Background(), "other")
+		defer doneOther()
+
+		tracker.Revoked(context.Background(), ps, "revoked")
+		require.ErrorIs(t, context.Cause(revokedCtx), sessiontracker.ErrRevoked)
+		require.NoError(t, otherCtx.Err())
+
+		// Requests that are done are no longer tracked.
+		doneOther()
+		require.ErrorIs(t, otherCtx.Err(), context.Canceled)
+		require.NotErrorIs(t, context.Cause(otherCtx), sessiontracker.ErrRevoked)
+	})
+
+	t.Run("Replicas", func(t *testing.T) {
+		t.Parallel()
+		ps := pubsub.NewInMemory()
+		first := sessiontracker.New(slogtest.Make(t, nil))
+		second := sessiontracker.New(slogtest.Make(t, nil))
+		cancel, err := second.Subscribe(ps)
+		require.NoError(t, err)
+		defer cancel()
+
+		ctx, done := second.Track(context.Background(), "key")
+		defer done()
+
+		// Keys revoked on one replica end the requests on all of them.
+		first.Revoked(context.Background(), ps, "key")
+		select {
+		case <-ctx.Done():
+		case <-testutil.Context(t, testutil.WaitShort).Done():
+			t.Fatal("request was not cancelled")
+		}
+		require.ErrorIs(t, context.Cause(ctx), sessiontracker.ErrRevoked)
+	})
+}
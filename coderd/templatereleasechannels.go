@@ -0,0 +1,394 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template release channels
+// @ID get-template-release-channels
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateReleaseChannel
+// @Router /templates/{template}/channels [get]
+func (api *API) templateReleaseChannels(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	channels, err := api.Database.GetTemplateReleaseChannelsByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template release channels.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiChannels := make([]codersdk.TemplateReleaseChannel, 0, len(channels))
+	if len(channels) == 0 {
+		httpapi.Write(ctx, rw, http.StatusOK, apiChannels)
+		return
+	}
+
+	versionIDs := make([]uuid.UUID, 0, len(channels))
+	for _, channel := range channels {
+		versionIDs = append(versionIDs, channel.TemplateVersionID)
+	}
+	versions, err := api.Database.GetTemplateVersionsByIDs(ctx, versionIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template versions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	versionNames := make(map[uuid.UUID]string, len(versions))
+	for _, version := range versions {
+		versionNames[version.ID] = version.Name
+	}
+
+	for _, channel := range channels {
+		apiChannels = append(apiChannels, convertTemplateReleaseChannel(channel, versionNames[channel.TemplateVersionID]))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiChannels)
+}
+
+// @Summary Create template release channel
+// @ID create-template-release-channel
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateReleaseChannelRequest true "Create template release channel request"
+// @Success 201 {object} codersdk.TemplateReleaseChannel
+// @Router /templates/{template}/channels [post]
+func (api *API) postTemplateReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	var req codersdk.CreateTemplateReleaseChannelRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// The columns are not nullable.
+	if req.UserIDs == nil {
+		req.UserIDs = []uuid.UUID{}
+	}
+	if req.GroupIDs == nil {
+		req.GroupIDs = []uuid.UUID{}
+	}
+
+	version, ok := api.releaseChannelVersion(ctx, rw, template, req.TemplateVersionID)
+	if !ok {
+		return
+	}
+	if !api.validateReleaseChannelMembers(ctx, rw, template, req.UserIDs, req.GroupIDs) {
+		return
+	}
+
+	channel, err := api.Database.InsertTemplateReleaseChannel(ctx, database.InsertTemplateReleaseChannelParams{
+		ID:                uuid.New(),
+		TemplateID:        template.ID,
+		Name:              req.Name,
+		TemplateVersionID: version.ID,
+		UserIDs:           req.UserIDs,
+		GroupIDs:          req.GroupIDs,
+		CreatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A release channel named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishTemplateUpdate(ctx, template.ID)
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateReleaseChannel(channel, version.Name))
+}
+
+// @Summary Update template release channel
+// @ID update-template-release-channel
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param channel path string true "Release channel name"
+// @Param request body codersdk.UpdateTemplateReleaseChannelRequest true "Update template release channel request"
+// @Success 200 {object} codersdk.TemplateReleaseChannel
+// @Router /templates/{template}/channels/{channel} [patch]
+func (api *API) patchTemplateReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		channel  = httpmw.TemplateReleaseChannelParam(r)
+	)
+
+	var req codersdk.UpdateTemplateReleaseChannelRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	versionID := channel.TemplateVersionID
+	if req.TemplateVersionID != nil {
+		versionID = *req.TemplateVersionID
+	}
+	userIDs := channel.UserIDs
+	if req.UserIDs != nil {
+		userIDs = *req.UserIDs
+	}
+	groupIDs := channel.GroupIDs
+	if req.GroupIDs != nil {
+		groupIDs = *req.GroupIDs
+	}
+	// The columns are not nullable.
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+	if groupIDs == nil {
+		groupIDs = []uuid.UUID{}
+	}
+
+	version, ok := api.releaseChannelVersion(ctx, rw, template, versionID)
+	if !ok {
+		return
+	}
+	if !api.validateReleaseChannelMembers(ctx, rw, template, userIDs, groupIDs) {
+		return
+	}
+
+	channel, err := api.Database.UpdateTemplateReleaseChannelByID(ctx, database.UpdateTemplateReleaseChannelByIDParams{
+		ID:                channel.ID,
+		TemplateVersionID: version.ID,
+		UserIDs:           userIDs,
+		GroupIDs:          groupIDs,
+		UpdatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishTemplateUpdate(ctx, template.ID)
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateReleaseChannel(channel, version.Name))
+}
+
+// @Summary Delete template release channel
+// @ID delete-template-release-channel
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param channel path string true "Release channel name"
+// @Success 204
+// @Router /templates/{template}/channels/{channel} [delete]
+func (api *API) deleteTemplateReleaseChannel(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		channel  = httpmw.TemplateReleaseChannelParam(r)
+	)
+
+	err := api.Database.DeleteTemplateReleaseChannelByID(ctx, channel.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template release channel.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishTemplateUpdate(ctx, template.ID)
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get template active version for user
+// @ID get-template-active-version-for-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.TemplateUserActiveVersion
+// @Router /templates/{template}/users/{user}/active-version [get]
+func (api *API) templateActiveVersionForUser(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		user     = httpmw.UserParam(r)
+	)
+
+	activeVersion, err := api.Database.GetTemplateActiveVersionForUser(ctx, database.GetTemplateActiveVersionForUserParams{
+		UserID:     user.ID,
+		TemplateID: template.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateUserActiveVersion{
+		TemplateVersionID: activeVersion.ActiveVersionID,
+		ReleaseChannel:    activeVersion.ReleaseChannel,
+	})
+}
+
+// releaseChannelVersion fetches the version a release channel of the template
+// should point at, and writes an error response if it can't be used.
+func (api *API) releaseChannelVersion(ctx context.Context, rw http.ResponseWriter, template database.Template, versionID uuid.UUID) (database.TemplateVersion, bool) {
+	version, err := api.Database.GetTemplateVersionByID(ctx, versionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version not found.",
+		})
+		return database.TemplateVersion{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return database.TemplateVersion{}, false
+	}
+	if version.TemplateID.UUID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return database.TemplateVersion{}, false
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version job status.",
+			Detail:  err.Error(),
+		})
+		return database.TemplateVersion{}, false
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only versions that have been built successfully can be promoted.",
+			Detail:  fmt.Sprintf("Attempted to promote a version with a %s build", job.JobStatus),
+		})
+		return database.TemplateVersion{}, false
+	}
+	if version.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version is archived.",
+		})
+		return database.TemplateVersion{}, false
+	}
+	return version, true
+}
+
+// validateReleaseChannelMembers checks that the users exist and the groups
+// belong to the organization of the template, and writes an error response
+// otherwise.
+func (api *API) validateReleaseChannelMembers(ctx context.Context, rw http.ResponseWriter, template database.Template, userIDs, groupIDs []uuid.UUID) bool {
+	if len(userIDs) > 0 {
+		users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+		if err != nil && !dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching users.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		found := make(map[uuid.UUID]bool, len(users))
+		for _, user := range users {
+			found[user.ID] = true
+		}
+		for _, userID := range userIDs {
+			if !found[userID] {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: fmt.Sprintf("User %q not found.", userID),
+					Validations: []codersdk.ValidationError{{
+						Field:  "user_ids",
+						Detail: "All users must exist.",
+					}},
+				})
+				return false
+			}
+		}
+	}
+
+	for _, groupID := range groupIDs {
+		group, err := api.Database.GetGroupByID(ctx, groupID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		if err != nil || group.OrganizationID != template.OrganizationID {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Group %q not found in the organization of the template.", groupID),
+				Validations: []codersdk.ValidationError{{
+					Field:  "group_ids",
+					Detail: "All groups must belong to the organization of the template.",
+				}},
+			})
+			return false
+		}
+	}
+	return true
+}
+
+func convertTemplateReleaseChannel(channel database.TemplateReleaseChannel, versionName string) codersdk.TemplateReleaseChannel {
+	return codersdk.TemplateReleaseChannel{
+		ID:                  channel.ID,
+		TemplateID:          channel.TemplateID,
+		Name:                channel.Name,
+		TemplateVersionID:   channel.TemplateVersionID,
+		TemplateVersionName: versionName,
+		UserIDs:             channel.UserIDs,
+		GroupIDs:            channel.GroupIDs,
+		CreatedAt:           channel.CreatedAt,
+		UpdatedAt:           channel.UpdatedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateReleaseChannels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		canary := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		channel, err := client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canary.ID,
+			UserIDs:           []uuid.UUID{member.ID},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "canary", channel.Name)
+		require.Equal(t, canary.ID, channel.TemplateVersionID)
+		require.Equal(t, canary.Name, channel.TemplateVersionName)
+		require.Equal(t, []uuid.UUID{member.ID}, channel.UserIDs)
+		require.Empty(t, channel.GroupIDs)
+
+		_, err = client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canary.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Only the fields that are set change.
+		groups := []uuid.UUID{owner.OrganizationID}
+		updated, err := client.UpdateTemplateReleaseChannel(ctx, template.ID, "canary", codersdk.UpdateTemplateReleaseChannelRequest{
+			GroupIDs: &groups,
+		})
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, updated.TemplateVersionID)
+		require.Equal(t, []uuid.UUID{member.ID}, updated.UserIDs)
+		require.Equal(t, groups, updated.GroupIDs)
+
+		channels, err := client.TemplateReleaseChannels(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+		require.Equal(t, updated, channels[0])
+
+		err = client.DeleteTemplateReleaseChannel(ctx, template.ID, "canary")
+		require.NoError(t, err)
+		channels, err = client.TemplateReleaseChannels(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, channels)
+
+		err = client.DeleteTemplateReleaseChannel(ctx, template.ID, "canary")
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		_ = coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, tc := range []struct {
+			name   string
+			req    codersdk.CreateTemplateReleaseChannelRequest
+			status int
+		}{
+			{
+				name:   "InvalidName",
+				req:    codersdk.CreateTemplateReleaseChannelRequest{Name: "not a name", TemplateVersionID: version.ID},
+				status: http.StatusBadRequest,
+			},
+			{
+				name:   "OtherTemplateVersion",
+				req:    codersdk.CreateTemplateReleaseChannelRequest{Name: "canary", TemplateVersionID: otherVersion.ID},
+				status: http.StatusBadRequest,
+			},
+			{
+				name:   "UnknownUser",
+				req:    codersdk.CreateTemplateReleaseChannelRequest{Name: "canary", TemplateVersionID: version.ID, UserIDs: []uuid.UUID{uuid.New()}},
+				status: http.StatusBadRequest,
+			},
+			{
+				name:   "UnknownGroup",
+				req:    codersdk.CreateTemplateReleaseChannelRequest{Name: "canary", TemplateVersionID: version.ID, GroupIDs: []uuid.UUID{uuid.New()}},
+				status: http.StatusBadRequest,
+			},
+		} {
+			_, err := client.CreateTemplateReleaseChannel(ctx, template.ID, tc.req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, tc.name)
+			require.Equal(t, tc.status, apiErr.StatusCode(), tc.name)
+		}
+	})
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		canary := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canary.ID,
+			UserIDs:           []uuid.UUID{member.ID},
+		})
+		require.NoError(t, err)
+
+		activeVersion, err := memberClient.TemplateActiveVersionForUser(ctx, template.ID, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, activeVersion.TemplateVersionID)
+		require.Equal(t, "canary", activeVersion.ReleaseChannel)
+
+		// Workspaces created without a version use the version of the
+		// owner's channel.
+		workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+		workspace, err = memberClient.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, canary.ID, workspace.TemplateActiveVersionID)
+		require.Equal(t, "canary", workspace.TemplateReleaseChannel)
+		require.False(t, workspace.Outdated)
+
+		// Users without a channel keep using the active version.
+		otherWorkspace := coderdtest.CreateWorkspace(t, otherClient, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, otherClient, otherWorkspace.LatestBuild.ID)
+		otherWorkspace, err = otherClient.Workspace(ctx, otherWorkspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, otherWorkspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, version.ID, otherWorkspace.TemplateActiveVersionID)
+		require.Empty(t, otherWorkspace.TemplateReleaseChannel)
+		require.False(t, otherWorkspace.Outdated)
+
+		// Deleting the channel moves its users back to the active version.
+		err = client.DeleteTemplateReleaseChannel(ctx, template.ID, "canary")
+		require.NoError(t, err)
+		workspace, err = memberClient.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, workspace.TemplateActiveVersionID)
+		require.Empty(t, workspace.TemplateReleaseChannel)
+		require.True(t, workspace.Outdated)
+	})
+
+	t.Run("GroupAssignment", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		beta := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, beta.ID)
+		canary := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The "Everyone" group shares the ID of the organization.
+		_, err := client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "beta",
+			TemplateVersionID: beta.ID,
+			GroupIDs:          []uuid.UUID{owner.OrganizationID},
+		})
+		require.NoError(t, err)
+		activeVersion, err := client.TemplateActiveVersionForUser(ctx, template.ID, member.Username)
+		require.NoError(t, err)
+		require.Equal(t, beta.ID, activeVersion.TemplateVersionID)
+		require.Equal(t, "beta", activeVersion.ReleaseChannel)
+
+		// A direct assignment takes precedence over a group.
+		_, err = client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canary.ID,
+			UserIDs:           []uuid.UUID{member.ID},
+		})
+		require.NoError(t, err)
+		activeVersion, err = client.TemplateActiveVersionForUser(ctx, template.ID, member.Username)
+		require.NoError(t, err)
+		require.Equal(t, canary.ID, activeVersion.TemplateVersionID)
+		require.Equal(t, "canary", activeVersion.ReleaseChannel)
+
+		activeVersion, err = client.TemplateActiveVersionForUser(ctx, template.ID, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, beta.ID, activeVersion.TemplateVersionID)
+	})
+
+	t.Run("ArchiveChannelVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		canary := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, canary.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canary.ID,
+		})
+		require.NoError(t, err)
+
+		err = client.SetArchiveTemplateVersion(ctx, canary.ID, true)
+		require.Error(t, err)
+
+		res, err := client.ArchiveTemplateVersions(ctx, template.ID, false)
+		require.NoError(t, err)
+		require.NotContains(t, res.ArchivedIDs, canary.ID)
+	})
+}
@@ -1,6 +1,7 @@
 package coderd
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -12,12 +13,15 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
+
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -631,6 +635,14 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			maxPortShareLevel = database.AppSharingLevel(*req.MaxPortShareLevel)
 		}
 	}
+	maxWorkspaces := template.MaxWorkspaces
+	if req.MaxWorkspaces != nil {
+		if *req.MaxWorkspaces < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_workspaces", Detail: "Value must not be negative."})
+		} else {
+			maxWorkspaces = *req.MaxWorkspaces
+		}
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -659,7 +671,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() &&
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
-			maxPortShareLevel == template.MaxPortSharingLevel {
+			maxPortShareLevel == template.MaxPortSharingLevel &&
+			maxWorkspaces == template.MaxWorkspaces {
 			return nil
 		}
 
@@ -700,6 +713,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
 			GroupACL:                     groupACL,
 			MaxPortSharingLevel:          maxPortShareLevel,
+			MaxWorkspaces:                maxWorkspaces,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -776,9 +790,50 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	}
 	aReq.New = updated
 
+	if template.Deprecated == "" && updated.Deprecated != "" {
+		api.notifyTemplateDeprecated(ctx, updated)
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(updated))
 }
 
+// notifyTemplateDeprecated tells the owner of every workspace using the
+// template that it has been deprecated. Failures are logged and don't fail
+// the request since the template has already been updated.
+func (api *API) notifyTemplateDeprecated(ctx context.Context, template database.Template) {
+	// The caller may not be able to see every workspace built from the
+	// template, but all of their owners need to hear about it.
+	//nolint:gocritic // Need to list workspaces of all users.
+	workspaces, err := api.Database.GetWorkspaces(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacesParams{
+		TemplateIDs: []uuid.UUID{template.ID},
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "list workspaces for template deprecation notification",
+			slog.F("template_id", template.ID), slog.Error(err))
+		return
+	}
+
+	templateName := template.DisplayName
+	if templateName == "" {
+		templateName = template.Name
+	}
+	notified := make(map[uuid.UUID]struct{})
+	for _, workspace := range workspaces {
+		if _, ok := notified[workspace.OwnerID]; ok {
+			continue
+		}
+		notified[workspace.OwnerID] = struct{}{}
+		_, err = api.NotificationsEnqueuer.Enqueue(ctx, workspace.OwnerID, notifications.TemplateTemplateDeprecated, map[string]string{
+			"template": templateName,
+			"message":  template.Deprecated,
+		}, "api_templates")
+		if err != nil {
+			api.Logger.Warn(ctx, "enqueue template deprecation notification",
+				slog.F("template_id", template.ID), slog.F("user_id", workspace.OwnerID), slog.Error(err))
+		}
+	}
+}
+
 // @Summary Get template DAUs by ID
 // @ID get-template-daus-by-id
 // @Security CoderSessionToken
@@ -892,5 +947,6 @@ func (api *API) convertTemplate(
 		Deprecated:           templateAccessControl.IsDeprecated(),
 		DeprecationMessage:   templateAccessControl.Deprecated,
 		MaxPortShareLevel:    codersdk.WorkspaceAgentPortShareLevel(template.MaxPortSharingLevel),
+		MaxWorkspaces:        template.MaxWorkspaces,
 	}
 }
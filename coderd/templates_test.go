@@ -623,6 +623,41 @@ func TestPatchTemplateMeta(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("MaxWorkspaces", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxWorkspaces)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:          template.Name,
+			MaxWorkspaces: ptr.Ref[int32](50),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 50, updated.MaxWorkspaces)
+
+		// Omitting the limit keeps it.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:        template.Name,
+			Description: "GPU workspaces",
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 50, updated.MaxWorkspaces)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxWorkspaces: ptr.Ref[int32](-1),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "max_workspaces", apiErr.Validations[0].Field)
+	})
+
 	t.Run("NoDefaultTTL", func(t *testing.T) {
 		t.Parallel()
 
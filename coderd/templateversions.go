@@ -411,6 +411,95 @@ func (api *API) templateVersionVariables(rw http.ResponseWriter, r *http.Request
 	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionVariables(dbTemplateVersionVariables))
 }
 
+// @Summary Get template version diagnostics
+// @ID get-template-version-diagnostics
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVersionDiagnostic
+// @Router /templateversions/{templateversion}/diagnostics [get]
+func (api *API) templateVersionDiagnostics(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	// Diagnostics are stored while the import job runs, and are most useful
+	// when it failed, so the job is not required to have completed.
+	dbDiagnostics, err := api.Database.GetTemplateVersionDiagnostics(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version diagnostics.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	diagnostics := make([]codersdk.TemplateVersionDiagnostic, 0, len(dbDiagnostics))
+	for _, diagnostic := range dbDiagnostics {
+		diagnostics = append(diagnostics, codersdk.TemplateVersionDiagnostic{
+			Severity: codersdk.TemplateVersionDiagnosticSeverity(diagnostic.Severity),
+			Code:     diagnostic.Code,
+			Summary:  diagnostic.Summary,
+			Detail:   diagnostic.Detail,
+			Filename: diagnostic.Filename,
+			Line:     int(diagnostic.Line),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, diagnostics)
+}
+
+// @Summary Get template version presets
+// @ID get-template-version-presets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {array} codersdk.Preset
+// @Router /templateversions/{templateversion}/presets [get]
+func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	dbPresets, err := api.Database.GetTemplateVersionPresets(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	dbParameters, err := api.Database.GetTemplateVersionPresetParameters(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version preset parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	parametersByPreset := make(map[uuid.UUID][]codersdk.PresetParameter)
+	for _, parameter := range dbParameters {
+		parametersByPreset[parameter.TemplateVersionPresetID] = append(parametersByPreset[parameter.TemplateVersionPresetID], codersdk.PresetParameter{
+			Name:  parameter.Name,
+			Value: parameter.Value,
+		})
+	}
+	presets := make([]codersdk.Preset, 0, len(dbPresets))
+	for _, preset := range dbPresets {
+		parameters := parametersByPreset[preset.ID]
+		if parameters == nil {
+			parameters = []codersdk.PresetParameter{}
+		}
+		presets = append(presets, codersdk.Preset{
+			ID:         preset.ID,
+			Name:       preset.Name,
+			Parameters: parameters,
+			Prebuilds:  preset.DesiredPrebuildInstances,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, presets)
+}
+
 // @Summary Create template version dry-run
 // @ID create-template-version-dry-run
 // @Security CoderSessionToken
@@ -429,9 +518,11 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 	)
 
 	// We use the workspace RBAC check since we don't want to allow dry runs if
-	// the user can't create workspaces.
-	if !api.Authorize(r, rbac.ActionCreate,
-		rbac.ResourceWorkspace.InOrg(templateVersion.OrganizationID).WithOwner(apiKey.UserID.String())) {
+	// the user can't create workspaces. The CLI also runs one before it
+	// starts or updates a workspace, so being able to update workspaces is
+	// enough. A dry run does not create a workspace.
+	workspaceObj := rbac.ResourceWorkspace.InOrg(templateVersion.OrganizationID).WithOwner(apiKey.UserID.String())
+	if !api.Authorize(r, rbac.ActionCreate, workspaceObj) && !api.Authorize(r, rbac.ActionUpdate, workspaceObj) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -508,6 +599,7 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 			Valid:      true,
 			RawMessage: metadataRaw,
 		},
+		EngineVersionConstraint: job.EngineVersionConstraint,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -1165,7 +1257,7 @@ func (api *API) setArchiveTemplateVersion(archive bool) func(rw http.ResponseWri
 				err = archiveError
 			} else {
 				if len(archived) == 0 {
-					err = xerrors.New("Unable to archive specified version, the version is likely in use by a workspace or currently set to the active version")
+					err = xerrors.New("Unable to archive specified version, the version is likely in use by a workspace, a release channel or currently set to the active version")
 				}
 			}
 		} else {
@@ -1288,6 +1380,7 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	aReq.New = newTemplate
 
 	api.publishTemplateUpdate(ctx, template.ID)
+	api.publishTemplateVersionEvent(ctx, codersdk.WebhookEventTemplateVersionPromoted, version, httpmw.APIKey(r).UserID)
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
@@ -1522,6 +1615,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		return
 	}
 	aReq.New = templateVersion
+	api.publishTemplateVersionEvent(ctx, codersdk.WebhookEventTemplateVersionPushed, templateVersion, apiKey.UserID)
 	err = provisionerjobs.PostJob(api.Pubsub, provisionerJob)
 	if err != nil {
 		// Client probably doesn't care about this error, so just log it.
@@ -1718,3 +1812,22 @@ func (api *API) publishTemplateUpdate(ctx context.Context, templateID uuid.UUID)
 			slog.F("template_id", templateID), slog.Error(err))
 	}
 }
+
+// publishTemplateVersionEvent sends a template version event to the webhooks
+// subscribed to it.
+func (api *API) publishTemplateVersionEvent(ctx context.Context, event codersdk.WebhookEvent, version database.TemplateVersion, initiatorID uuid.UUID) {
+	initiator, err := api.Database.GetUserByID(ctx, initiatorID)
+	if err != nil {
+		api.Logger.Warn(ctx, "get initiator for webhook event",
+			slog.F("template_version_id", version.ID), slog.Error(err))
+		return
+	}
+	api.WebhookPublisher.Publish(ctx, event, codersdk.WebhookTemplateVersionData{
+		TemplateVersionID:   version.ID,
+		TemplateVersionName: version.Name,
+		TemplateID:          version.TemplateID.UUID,
+		OrganizationID:      version.OrganizationID,
+		InitiatorID:         initiator.ID,
+		InitiatorName:       initiator.Username,
+	})
+}
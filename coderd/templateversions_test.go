@@ -1276,6 +1276,122 @@ func TestTemplateVersionVariables(t *testing.T) {
 	})
 }
 
+func TestTemplateVersionDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	createEchoResponses := func(diagnostics ...*proto.Diagnostic) *echo.Responses {
+		return &echo.Responses{
+			Parse: []*proto.Response{{
+				Type: &proto.Response_Parse{
+					Parse: &proto.ParseComplete{
+						Diagnostics: diagnostics,
+					},
+				},
+			}},
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ApplyComplete,
+		}
+	}
+
+	t.Run("Warning", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, createEchoResponses(&proto.Diagnostic{
+			Severity: proto.Diagnostic_WARNING,
+			Code:     "agent-not-attached",
+			Summary:  "coder_agent.dev is not used by any resource",
+			Filename: "main.tf",
+			Line:     3,
+		}))
+		templateVersion := coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		require.Empty(t, templateVersion.Job.Error)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		diagnostics, err := client.TemplateVersionDiagnostics(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.TemplateVersionDiagnostic{{
+			Severity: codersdk.TemplateVersionDiagnosticSeverityWarning,
+			Code:     "agent-not-attached",
+			Summary:  "coder_agent.dev is not used by any resource",
+			Filename: "main.tf",
+			Line:     3,
+		}}, diagnostics)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, createEchoResponses(&proto.Diagnostic{
+			Severity: proto.Diagnostic_ERROR,
+			Code:     "duplicate-app-slug",
+			Summary:  "coder_app.b has the same slug as coder_app.a",
+			Filename: "apps.tf",
+			Line:     8,
+		}))
+		templateVersion := coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, templateVersion.Job.Status)
+		require.Contains(t, templateVersion.Job.Error, "apps.tf:8: coder_app.b has the same slug as coder_app.a")
+
+		// Diagnostics of failed imports are kept to explain the failure.
+		ctx := testutil.Context(t, testutil.WaitShort)
+		diagnostics, err := client.TemplateVersionDiagnostics(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		require.Equal(t, codersdk.TemplateVersionDiagnosticSeverityError, diagnostics[0].Severity)
+	})
+}
+
+func TestTemplateVersionPresets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "cpu", Type: "number"},
+						{Name: "image", Type: "string"},
+					},
+					Presets: []*proto.Preset{{
+						Name: "Small Go dev",
+						Parameters: []*proto.PresetParameter{
+							{Name: "cpu", Value: "2"},
+							{Name: "image", Value: "golang"},
+						},
+					}, {
+						Name: "GPU ML",
+						Parameters: []*proto.PresetParameter{
+							{Name: "cpu", Value: "16"},
+						},
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 2)
+	// Presets are sorted by name.
+	require.Equal(t, "GPU ML", presets[0].Name)
+	require.Equal(t, []codersdk.PresetParameter{{Name: "cpu", Value: "16"}}, presets[0].Parameters)
+	require.Equal(t, "Small Go dev", presets[1].Name)
+	require.Equal(t, []codersdk.PresetParameter{
+		{Name: "cpu", Value: "2"},
+		{Name: "image", Value: "golang"},
+	}, presets[1].Parameters)
+}
+
 func TestTemplateVersionPatch(t *testing.T) {
 	t.Parallel()
 	t.Run("Update the name", func(t *testing.T) {
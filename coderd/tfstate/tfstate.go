@@ -0,0 +1,113 @@
+// Package tfstate compares the Terraform state stored for workspace builds.
+package tfstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Diff lists the resource instances that differ between two states. Each
+// entry is a Terraform resource address, e.g. "docker_container.workspace[0]".
+type Diff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+type state struct {
+	Resources []resource `json:"resources"`
+}
+
+type resource struct {
+	Module    string     `json:"module"`
+	Mode      string     `json:"mode"`
+	Type      string     `json:"type"`
+	Name      string     `json:"name"`
+	Instances []instance `json:"instances"`
+}
+
+type instance struct {
+	IndexKey   any `json:"index_key"`
+	Attributes any `json:"attributes"`
+}
+
+// Compare returns the resource instances that were added, removed or had
+// their attributes changed going from one state to another. An empty state
+// is treated as a state without any resources.
+func Compare(from, to []byte) (Diff, error) {
+	fromInstances, err := instances(from)
+	if err != nil {
+		return Diff{}, xerrors.Errorf("parse from state: %w", err)
+	}
+	toInstances, err := instances(to)
+	if err != nil {
+		return Diff{}, xerrors.Errorf("parse to state: %w", err)
+	}
+
+	diff := Diff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+	}
+	for address, attributes := range toInstances {
+		previous, ok := fromInstances[address]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, address)
+		case !reflect.DeepEqual(previous, attributes):
+			diff.Changed = append(diff.Changed, address)
+		}
+	}
+	for address := range fromInstances {
+		if _, ok := toInstances[address]; !ok {
+			diff.Removed = append(diff.Removed, address)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff, nil
+}
+
+// instances maps the address of every resource instance in the state to its
+// attributes.
+func instances(raw []byte) (map[string]any, error) {
+	found := map[string]any{}
+	if len(raw) == 0 {
+		return found, nil
+	}
+	var s state
+	err := json.Unmarshal(raw, &s)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range s.Resources {
+		for _, i := range r.Instances {
+			found[address(r, i)] = i.Attributes
+		}
+	}
+	return found, nil
+}
+
+func address(r resource, i instance) string {
+	var b strings.Builder
+	if r.Module != "" {
+		_, _ = b.WriteString(r.Module + ".")
+	}
+	if r.Mode == "data" {
+		_, _ = b.WriteString("data.")
+	}
+	_, _ = b.WriteString(r.Type + "." + r.Name)
+	switch key := i.IndexKey.(type) {
+	case float64:
+		_, _ = fmt.Fprintf(&b, "[%d]", int64(key))
+	case string:
+		_, _ = fmt.Fprintf(&b, "[%q]", key)
+	}
+	return b.String()
+}
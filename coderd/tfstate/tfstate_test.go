@@ -0,0 +1,93 @@
+package tfstate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/tfstate"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	const from = `{
+		"version": 4,
+		"resources": [
+			{
+				"mode": "managed",
+				"type": "docker_container",
+				"name": "workspace",
+				"instances": [{"index_key": 0, "attributes": {"image": "ubuntu:22.04"}}]
+			},
+			{
+				"mode": "managed",
+				"type": "docker_volume",
+				"name": "home",
+				"instances": [{"attributes": {"name": "home"}}]
+			},
+			{
+				"module": "module.code_server",
+				"mode": "data",
+				"type": "coder_parameter",
+				"name": "extensions",
+				"instances": [{"index_key": "go", "attributes": {"value": "golang.go"}}]
+			}
+		]
+	}`
+	const to = `{
+		"version": 4,
+		"resources": [
+			{
+				"mode": "managed",
+				"type": "docker_container",
+				"name": "workspace",
+				"instances": [{"index_key": 0, "attributes": {"image": "ubuntu:24.04"}}]
+			},
+			{
+				"mode": "managed",
+				"type": "docker_volume",
+				"name": "home",
+				"instances": [{"attributes": {"name": "home"}}]
+			},
+			{
+				"mode": "managed",
+				"type": "coder_agent",
+				"name": "main",
+				"instances": [{"attributes": {"os": "linux"}}]
+			}
+		]
+	}`
+
+	t.Run("Changes", func(t *testing.T) {
+		t.Parallel()
+		diff, err := tfstate.Compare([]byte(from), []byte(to))
+		require.NoError(t, err)
+		require.Equal(t, []string{"coder_agent.main"}, diff.Added)
+		require.Equal(t, []string{`module.code_server.data.coder_parameter.extensions["go"]`}, diff.Removed)
+		require.Equal(t, []string{"docker_container.workspace[0]"}, diff.Changed)
+	})
+
+	t.Run("Identical", func(t *testing.T) {
+		t.Parallel()
+		diff, err := tfstate.Compare([]byte(from), []byte(from))
+		require.NoError(t, err)
+		require.Empty(t, diff.Added)
+		require.Empty(t, diff.Removed)
+		require.Empty(t, diff.Changed)
+	})
+
+	t.Run("EmptyState", func(t *testing.T) {
+		t.Parallel()
+		diff, err := tfstate.Compare(nil, []byte(to))
+		require.NoError(t, err)
+		require.Len(t, diff.Added, 3)
+		require.Empty(t, diff.Removed)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := tfstate.Compare([]byte("not json"), []byte(to))
+		require.Error(t, err)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/mail"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,7 +21,6 @@ import (
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
-	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
@@ -106,7 +106,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// This handles the email/pass checking.
-	user, _, ok := api.loginRequest(ctx, rw, codersdk.LoginWithPasswordRequest{
+	user, _, ok := api.loginRequest(rw, r, codersdk.LoginWithPasswordRequest{
 		Email:    user.Email,
 		Password: req.Password,
 	})
@@ -210,18 +210,19 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 // @Tags Authorization
 // @Param request body codersdk.LoginWithPasswordRequest true "Login request"
 // @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Success 202 {object} codersdk.LoginWithPasswordResponse
 // @Router /users/login [post]
 func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx               = r.Context()
-		auditor           = api.Auditor.Load()
-		logger            = api.Logger.Named(userAuthLoggerName)
-		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+		ctx         = r.Context()
+		auditor     = api.Auditor.Load()
+		auditParams = &audit.RequestParams{
 			Audit:   *auditor,
 			Log:     api.Logger,
 			Request: r,
 			Action:  database.AuditActionLogin,
-		})
+		}
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, auditParams)
 	)
 	aReq.Old = database.APIKey{}
 	defer commitAudit()
@@ -231,7 +232,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, roles, ok := api.loginRequest(ctx, rw, loginWithPassword)
+	user, roles, ok := api.loginRequest(rw, r, loginWithPassword)
 	// 'user.ID' will be empty, or will be an actual value. Either is correct
 	// here.
 	aReq.UserID = user.ID
@@ -240,6 +241,40 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Users who need a second factor finish logging in with a TOTP code.
+	ticket, enroll, ok := api.totpLoginTicket(ctx, rw, user)
+	if !ok {
+		return
+	}
+	if ticket != "" {
+		httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.LoginWithPasswordResponse{
+			TOTPTicket:             ticket,
+			TOTPEnrollmentRequired: enroll,
+		})
+		return
+	}
+
+	auditParams.AdditionalFields = api.recordLoginSuccess(ctx, r, user)
+	api.resetLoginFailures(ctx, user)
+
+	cookie, key, ok := api.createPasswordLoginKey(ctx, rw, r, user, roles)
+	if !ok {
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// createPasswordLoginKey creates the session for a user who logged in with
+// their password. If 'false' is returned, the error has been written to the
+// ResponseWriter.
+func (api *API) createPasswordLoginKey(ctx context.Context, rw http.ResponseWriter, r *http.Request, user database.User, roles database.GetAuthorizationUserRolesRow) (*http.Cookie, *database.APIKey, bool) {
+	logger := api.Logger.Named(userAuthLoggerName)
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
 		Roles:  rbac.RoleNames(roles.Roles),
@@ -252,6 +287,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		UserID:          user.ID,
 		LoginType:       database.LoginTypePassword,
 		RemoteAddr:      r.RemoteAddr,
+		UserAgent:       r.UserAgent(),
 		DefaultLifetime: api.DeploymentValues.SessionDuration.Value(),
 	})
 	if err != nil {
@@ -260,16 +296,9 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 			Message: "Failed to create API key.",
 			Detail:  err.Error(),
 		})
-		return
+		return nil, nil, false
 	}
-
-	aReq.New = *key
-
-	http.SetCookie(rw, cookie)
-
-	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
-	})
+	return cookie, key, true
 }
 
 // loginRequest will process a LoginWithPasswordRequest and return the user if
@@ -278,7 +307,8 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 //
 // The user struct is always returned, even if authentication failed. This is
 // to support knowing what user attempted to login.
-func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
+func (api *API) loginRequest(rw http.ResponseWriter, r *http.Request, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
+	ctx := r.Context()
 	logger := api.Logger.Named(userAuthLoggerName)
 
 	//nolint:gocritic // In order to login, we need to get the user first!
@@ -293,6 +323,10 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 		return user, database.GetAuthorizationUserRolesRow{}, false
 	}
 
+	if !api.checkLoginAttempt(ctx, rw, r, user, "Incorrect email or password.") {
+		return user, database.GetAuthorizationUserRolesRow{}, false
+	}
+
 	// If the user doesn't exist, it will be a default struct.
 	equal, err := userpassword.Compare(string(user.HashedPassword), req.Password)
 	if err != nil {
@@ -304,6 +338,7 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 	}
 
 	if !equal {
+		api.recordLoginFailure(ctx, r, user)
 		// This message is the same as above to remove ease in detecting whether
 		// users are registered or not. Attackers still could with a timing attack.
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
@@ -427,6 +462,8 @@ func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = database.APIKey{}
+	// This also ends the logout request itself, so it must come last.
+	api.SessionTracker.Revoked(ctx, api.Pubsub, apiKey.ID)
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Logged out!",
@@ -738,6 +775,17 @@ type OIDCConfig struct {
 	// UserRolesDefault is the default set of roles to assign to a user if role sync
 	// is enabled.
 	UserRolesDefault []string
+	// OrganizationField selects the claim field to be used as the user's
+	// organizations. If the field is the empty string, then no organization
+	// membership updates will ever come from the OIDC provider.
+	OrganizationField string
+	// OrganizationMapping controls how organizations returned by the OIDC
+	// provider get mapped to organizations within Coder.
+	// map[oidcOrganizationName][]coderOrganizationID
+	OrganizationMapping map[string][]uuid.UUID
+	// OrganizationAssignDefault keeps users in the default organization when
+	// organization sync is enabled.
+	OrganizationAssignDefault bool
 	// SignInText is the text to display on the OIDC login button
 	SignInText string
 	// IconURL points to the URL of an icon to display on the OIDC login button
@@ -750,6 +798,10 @@ func (cfg OIDCConfig) RoleSyncEnabled() bool {
 	return cfg.UserRoleField != ""
 }
 
+func (cfg OIDCConfig) OrganizationSyncEnabled() bool {
+	return cfg.OrganizationField != ""
+}
+
 // @Summary OpenID Connect Callback
 // @ID openid-connect-callback
 // @Security CoderSessionToken
@@ -958,15 +1010,20 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username))
-	usingGroups, groups, groupErr := api.oidcGroups(ctx, mergedClaims)
-	if groupErr != nil {
-		groupErr.Write(rw, r)
+	//nolint:gocritic // No user present in the context.
+	syncRules, err := api.Database.GetOIDCSyncRules(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		logger.Error(ctx, "oauth2: unable to fetch oidc sync rules", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch OIDC sync rules.",
+			Detail:  err.Error(),
+		})
 		return
 	}
 
-	roles, roleErr := api.oidcRoles(ctx, mergedClaims)
-	if roleErr != nil {
-		roleErr.Write(rw, r)
+	sync, syncErr := api.oidcSync(ctx, mergedClaims, syncRules)
+	if syncErr != nil {
+		syncErr.Write(rw, r)
 		return
 	}
 
@@ -987,21 +1044,25 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	params := (&oauthLoginParams{
-		User:                user,
-		Link:                link,
-		State:               state,
-		LinkedID:            oidcLinkedID(idToken),
-		LoginType:           database.LoginTypeOIDC,
-		AllowSignups:        api.OIDCConfig.AllowSignups,
-		Email:               email,
-		Username:            username,
-		AvatarURL:           picture,
-		UsingRoles:          api.OIDCConfig.RoleSyncEnabled(),
-		Roles:               roles,
-		UsingGroups:         usingGroups,
-		Groups:              groups,
-		CreateMissingGroups: api.OIDCConfig.CreateMissingGroups,
-		GroupFilter:         api.OIDCConfig.GroupFilter,
+		User:                      user,
+		Link:                      link,
+		State:                     state,
+		LinkedID:                  oidcLinkedID(idToken),
+		LoginType:                 database.LoginTypeOIDC,
+		AllowSignups:              api.OIDCConfig.AllowSignups,
+		Email:                     email,
+		Username:                  username,
+		AvatarURL:                 picture,
+		UsingRoles:                sync.UsingRoles,
+		Roles:                     sync.Roles,
+		UsingGroups:               sync.UsingGroups,
+		Groups:                    sync.Groups,
+		CreateMissingGroups:       api.OIDCConfig.CreateMissingGroups,
+		GroupFilter:               api.OIDCConfig.GroupFilter,
+		UsingOrganizations:        sync.UsingOrganizations,
+		OrganizationIDs:           sync.OrganizationIDs,
+		OrganizationAssignDefault: api.OIDCConfig.OrganizationAssignDefault,
+		OrganizationRoles:         sync.OrganizationRoles,
 		DebugContext: OauthDebugContext{
 			IDTokenClaims:  idtokenClaims,
 			UserInfoClaims: userInfoClaims,
@@ -1038,6 +1099,66 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
+// oidcSyncResult is what an OIDC login assigns to the user, from the claims
+// of the user and the sync configuration.
+type oidcSyncResult struct {
+	UsingGroups        bool
+	Groups             []string
+	UsingRoles         bool
+	Roles              []string
+	UsingOrganizations bool
+	OrganizationIDs    []uuid.UUID
+	OrganizationRoles  map[uuid.UUID][]string
+	// MatchedRules are the indexes of the sync rules that matched.
+	MatchedRules []int
+}
+
+// oidcSync combines the group, role and organization mappings of the
+// deployment config with the sync rules. If role or organization sync is
+// enabled, the matching rules add to what the mappings replace. Otherwise,
+// Roles and OrganizationIDs only hold what the matching rules add to the
+// user.
+func (api *API) oidcSync(ctx context.Context, mergedClaims map[string]interface{}, rules []database.OIDCSyncRule) (oidcSyncResult, *httpError) {
+	usingGroups, groups, groupErr := api.oidcGroups(ctx, mergedClaims)
+	if groupErr != nil {
+		return oidcSyncResult{}, groupErr
+	}
+
+	roles, roleErr := api.oidcRoles(ctx, mergedClaims)
+	if roleErr != nil {
+		return oidcSyncResult{}, roleErr
+	}
+
+	organizations, orgErr := api.oidcOrganizations(ctx, mergedClaims)
+	if orgErr != nil {
+		return oidcSyncResult{}, orgErr
+	}
+
+	ruleResult := applyOIDCSyncRules(ctx, api.Logger.Named(userAuthLoggerName), rules, mergedClaims)
+	if len(ruleResult.Matched) > 0 {
+		api.Logger.Debug(ctx, "oidc sync rules matched",
+			slog.F("matched", ruleResult.Matched),
+		)
+	}
+	result := oidcSyncResult{
+		UsingGroups:        usingGroups,
+		Groups:             groups,
+		UsingRoles:         api.OIDCConfig.RoleSyncEnabled(),
+		Roles:              ruleResult.Roles,
+		UsingOrganizations: api.OIDCConfig.OrganizationSyncEnabled(),
+		OrganizationIDs:    ruleResult.OrganizationIDs,
+		OrganizationRoles:  ruleResult.OrganizationRoles,
+		MatchedRules:       ruleResult.Matched,
+	}
+	if result.UsingRoles {
+		result.Roles = append(roles, ruleResult.Roles...)
+	}
+	if result.UsingOrganizations {
+		result.OrganizationIDs = append(organizations, ruleResult.OrganizationIDs...)
+	}
+	return result, nil
+}
+
 // oidcGroups returns the groups for the user from the OIDC claims.
 func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interface{}) (bool, []string, *httpError) {
 	logger := api.Logger.Named(userAuthLoggerName)
@@ -1166,6 +1287,58 @@ func (api *API) oidcRoles(ctx context.Context, mergedClaims map[string]interface
 	return roles, nil
 }
 
+// oidcOrganizations returns the IDs of the organizations the user should be a
+// member of according to the OIDC claims. Claim values that are not present
+// in the organization mapping are ignored.
+func (api *API) oidcOrganizations(ctx context.Context, mergedClaims map[string]interface{}) ([]uuid.UUID, *httpError) {
+	if !api.OIDCConfig.OrganizationSyncEnabled() {
+		return nil, nil
+	}
+
+	orgsRaw, ok := mergedClaims[api.OIDCConfig.OrganizationField]
+	if !ok {
+		// IDPs omit claims if they are empty, so a missing claim means the
+		// user belongs to no organizations.
+		orgsRaw = []interface{}{}
+	}
+
+	parsedOrgs, err := parseStringSliceClaim(orgsRaw)
+	if err != nil {
+		api.Logger.Error(ctx, "oidc claims organization field was an unknown type",
+			slog.F("type", fmt.Sprintf("%T", orgsRaw)),
+			slog.Error(err),
+		)
+		return nil, &httpError{
+			code:             http.StatusInternalServerError,
+			msg:              "Login disabled until OIDC config is fixed",
+			detail:           fmt.Sprintf("Organization claim must be an array of strings, type found: %T. Disabling organization sync will allow login to proceed.", orgsRaw),
+			renderStaticPage: false,
+		}
+	}
+
+	api.Logger.Debug(ctx, "organizations returned in oidc claims",
+		slog.F("len", len(parsedOrgs)),
+		slog.F("organizations", parsedOrgs),
+	)
+
+	organizationIDs := make([]uuid.UUID, 0)
+	ignored := make([]string, 0)
+	for _, org := range parsedOrgs {
+		mapped, ok := api.OIDCConfig.OrganizationMapping[org]
+		if !ok {
+			ignored = append(ignored, org)
+			continue
+		}
+		organizationIDs = append(organizationIDs, mapped...)
+	}
+	if len(ignored) > 0 {
+		api.Logger.Debug(ctx, "oidc organizations without a mapping ignored",
+			slog.F("ignored", ignored),
+		)
+	}
+	return organizationIDs, nil
+}
+
 // claimFields returns the sorted list of fields in the claims map.
 func claimFields(claims map[string]interface{}) []string {
 	fields := []string{}
@@ -1231,9 +1404,19 @@ type oauthLoginParams struct {
 	Groups      []string
 	GroupFilter *regexp.Regexp
 	// Is UsingRoles is true, then the user will be assigned
-	// the roles provided.
+	// the roles provided. Otherwise, the roles are added to the roles the
+	// user has.
 	UsingRoles bool
 	Roles      []string
+	// If UsingOrganizations is true, then the user's organization
+	// memberships will be set to the OrganizationIDs provided. Otherwise,
+	// the user is added to the OrganizationIDs.
+	UsingOrganizations        bool
+	OrganizationIDs           []uuid.UUID
+	OrganizationAssignDefault bool
+	// OrganizationRoles are the roles to add in each organization the user
+	// is a member of. Roles in other organizations are left unchanged.
+	OrganizationRoles map[uuid.UUID][]string
 
 	DebugContext OauthDebugContext
 
@@ -1306,7 +1489,10 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		logger  = api.Logger.Named(userAuthLoggerName)
 	)
 
-	var isConvertLoginType bool
+	var (
+		isConvertLoginType bool
+		isNewUser          bool
+	)
 	err := api.Database.InTx(func(tx database.Store) error {
 		var (
 			link database.UserLink
@@ -1405,6 +1591,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			isNewUser = true
 		}
 
 		// Activate dormant user on sigin
@@ -1461,10 +1648,31 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			}
 		}
 
+		// Ensure organization memberships are correct. This must happen
+		// before group sync, as groups can only be assigned in organizations
+		// the user is a member of.
+		if params.UsingOrganizations {
+			err := syncUserOrganizations(ctx, logger, tx, user.ID, params.OrganizationIDs, params.OrganizationAssignDefault)
+			if err != nil {
+				return xerrors.Errorf("sync organizations: %w", err)
+			}
+		} else if len(params.OrganizationIDs) > 0 {
+			err := addUserOrganizations(ctx, logger, tx, user.ID, params.OrganizationIDs)
+			if err != nil {
+				return xerrors.Errorf("add organizations: %w", err)
+			}
+		}
+		if len(params.OrganizationRoles) > 0 {
+			err := addUserOrganizationRoles(ctx, logger, tx, user.ID, params.OrganizationRoles)
+			if err != nil {
+				return xerrors.Errorf("add organization roles: %w", err)
+			}
+		}
+
 		// Ensure groups are correct.
-		// This places all groups into the default organization.
-		// To go multi-org, we need to add a mapping feature here to know which
-		// groups go to which orgs.
+		// The same group names are assigned in every organization the user is
+		// a member of. Groups that do not exist in an organization are
+		// ignored, unless they are created by CreateMissingGroups.
 		if params.UsingGroups {
 			filtered := params.Groups
 			if params.GroupFilter != nil {
@@ -1476,47 +1684,31 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 				}
 			}
 
-			//nolint:gocritic // No user present in the context.
-			defaultOrganization, err := tx.GetDefaultOrganization(dbauthz.AsSystemRestricted(ctx))
-			if err != nil {
-				// If there is no default org, then we can't assign groups.
-				// By default, we assume all groups belong to the default org.
-				return xerrors.Errorf("get default organization: %w", err)
-			}
-
 			//nolint:gocritic // No user present in the context.
 			memberships, err := tx.GetOrganizationMembershipsByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
 			if err != nil {
 				return xerrors.Errorf("get organization memberships: %w", err)
 			}
 
-			// If the user is not in the default organization, then we can't assign groups.
-			// A user cannot be in groups to an org they are not a member of.
-			if !slices.ContainsFunc(memberships, func(member database.OrganizationMember) bool {
-				return member.OrganizationID == defaultOrganization.ID
-			}) {
-				return xerrors.Errorf("user %s is not a member of the default organization, cannot assign to groups in the org", user.ID)
+			orgGroups := make(map[uuid.UUID][]string, len(memberships))
+			for _, member := range memberships {
+				orgGroups[member.OrganizationID] = filtered
 			}
 
 			//nolint:gocritic
-			err = api.Options.SetUserGroups(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, map[uuid.UUID][]string{
-				defaultOrganization.ID: filtered,
-			}, params.CreateMissingGroups)
+			err = api.Options.SetUserGroups(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, orgGroups, params.CreateMissingGroups)
 			if err != nil {
 				return xerrors.Errorf("set user groups: %w", err)
 			}
 		}
 
 		// Ensure roles are correct.
-		if params.UsingRoles {
-			ignored := make([]string, 0)
-			filtered := make([]string, 0, len(params.Roles))
-			for _, role := range params.Roles {
-				if _, err := rbac.RoleByName(role); err == nil {
-					filtered = append(filtered, role)
-				} else {
-					ignored = append(ignored, role)
-				}
+		if params.UsingRoles || len(params.Roles) > 0 {
+			filtered, ignored := filterSiteRoles(params.Roles)
+			if !params.UsingRoles {
+				// Only add the roles, and keep the ones that were
+				// assigned manually.
+				filtered = uniqueSorted(append(slices.Clone(user.RBACRoles), filtered...))
 			}
 
 			//nolint:gocritic
@@ -1581,6 +1773,10 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if isNewUser {
+		//nolint:gocritic // Users created on login are created by the system.
+		api.PublishUserCreated(dbauthz.AsSystemRestricted(ctx), user)
+	}
 
 	var key database.APIKey
 	oldKey, _, ok := httpmw.APIKeyFromRequest(ctx, api.Database, nil, r)
@@ -1614,6 +1810,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			LoginType:       params.LoginType,
 			DefaultLifetime: api.DeploymentValues.SessionDuration.Value(),
 			RemoteAddr:      r.RemoteAddr,
+			UserAgent:       r.UserAgent(),
 		})
 		if err != nil {
 			return nil, database.APIKey{}, xerrors.Errorf("create API key: %w", err)
@@ -1625,6 +1822,109 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 	return cookies, key, nil
 }
 
+// filterSiteRoles splits roles into the ones that exist and the ones that are
+// ignored.
+func filterSiteRoles(roles []string) (filtered []string, ignored []string) {
+	ignored = make([]string, 0)
+	filtered = make([]string, 0, len(roles))
+	for _, role := range roles {
+		if _, err := rbac.RoleByName(role); err == nil {
+			filtered = append(filtered, role)
+		} else {
+			ignored = append(ignored, role)
+		}
+	}
+	return filtered, ignored
+}
+
+// syncUserOrganizations sets the user's organization memberships to the
+// organizations provided. Organizations that do not exist are skipped, and
+// removed memberships also remove the user from the organization's groups.
+func syncUserOrganizations(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, organizationIDs []uuid.UUID, assignDefault bool) error {
+	//nolint:gocritic // No user present in the context.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	want := make(map[uuid.UUID]struct{}, len(organizationIDs)+1)
+	for _, id := range organizationIDs {
+		want[id] = struct{}{}
+	}
+	if assignDefault {
+		defaultOrganization, err := tx.GetDefaultOrganization(sysCtx)
+		if err != nil {
+			return xerrors.Errorf("get default organization: %w", err)
+		}
+		want[defaultOrganization.ID] = struct{}{}
+	}
+
+	memberships, err := tx.GetOrganizationMembershipsByUserID(sysCtx, userID)
+	if err != nil {
+		return xerrors.Errorf("get organization memberships: %w", err)
+	}
+	for _, member := range memberships {
+		if _, ok := want[member.OrganizationID]; ok {
+			continue
+		}
+		err := tx.DeleteOrganizationMember(sysCtx, database.DeleteOrganizationMemberParams{
+			OrganizationID: member.OrganizationID,
+			UserID:         userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("remove user from organization %s: %w", member.OrganizationID, err)
+		}
+	}
+
+	wantIDs := make([]uuid.UUID, 0, len(want))
+	for id := range want {
+		wantIDs = append(wantIDs, id)
+	}
+	return addUserOrganizations(ctx, logger, tx, userID, wantIDs)
+}
+
+// addUserOrganizations adds the user to the organizations provided they are
+// not a member of yet. Organizations that do not exist are skipped.
+func addUserOrganizations(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, organizationIDs []uuid.UUID) error {
+	//nolint:gocritic // No user present in the context.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	memberships, err := tx.GetOrganizationMembershipsByUserID(sysCtx, userID)
+	if err != nil {
+		return xerrors.Errorf("get organization memberships: %w", err)
+	}
+	have := make(map[uuid.UUID]struct{}, len(memberships))
+	for _, member := range memberships {
+		have[member.OrganizationID] = struct{}{}
+	}
+
+	for _, id := range organizationIDs {
+		if _, ok := have[id]; ok {
+			continue
+		}
+		have[id] = struct{}{}
+		_, err := tx.GetOrganizationByID(sysCtx, id)
+		if httpapi.Is404Error(err) {
+			logger.Warn(ctx, "oidc organization mapping references a missing organization",
+				slog.F("organization_id", id),
+				slog.F("user_id", userID),
+			)
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization %s: %w", id, err)
+		}
+		_, err = tx.InsertOrganizationMember(sysCtx, database.InsertOrganizationMemberParams{
+			OrganizationID: id,
+			UserID:         userID,
+			CreatedAt:      dbtime.Now(),
+			UpdatedAt:      dbtime.Now(),
+			Roles:          []string{},
+		})
+		if err != nil {
+			return xerrors.Errorf("add user to organization %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // convertUserToOauth will convert a user from password base loginType to
 // an oauth login type. If it fails, it will return a httpError
 func (api *API) convertUserToOauth(ctx context.Context, r *http.Request, db database.Store, params *oauthLoginParams) (database.User, error) {
@@ -1129,6 +1129,59 @@ func TestUserOIDC(t *testing.T) {
 		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
 	})
 
+	t.Run("OrganizationSync", func(t *testing.T) {
+		t.Parallel()
+		fake := oidctest.NewFakeIDP(t,
+			oidctest.WithRefresh(func(_ string) error {
+				return xerrors.New("refreshing token should never occur")
+			}),
+			oidctest.WithServing(),
+		)
+		cfg := fake.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+			cfg.OrganizationField = "organizations"
+			cfg.OrganizationAssignDefault = true
+		})
+
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: cfg,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		one, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "one"})
+		require.NoError(t, err)
+		two, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "two"})
+		require.NoError(t, err)
+		cfg.OrganizationMapping = map[string][]uuid.UUID{
+			"bu-one": {one.ID},
+			"bu-two": {two.ID},
+		}
+
+		orgIDs := func(userClient *codersdk.Client) []uuid.UUID {
+			orgs, err := userClient.OrganizationsByUser(ctx, codersdk.Me)
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, 0, len(orgs))
+			for _, org := range orgs {
+				ids = append(ids, org.ID)
+			}
+			return ids
+		}
+
+		userClient, _ := fake.Login(t, client, jwt.MapClaims{
+			"email":         "alice@coder.com",
+			"organizations": []string{"bu-one", "unmapped"},
+		})
+		require.ElementsMatch(t, []uuid.UUID{first.OrganizationID, one.ID}, orgIDs(userClient))
+
+		// Moving business units removes the old membership.
+		userClient, _ = fake.Login(t, client, jwt.MapClaims{
+			"email":         "alice@coder.com",
+			"organizations": []string{"bu-two"},
+		})
+		require.ElementsMatch(t, []uuid.UUID{first.OrganizationID, two.ID}, orgIDs(userClient))
+	})
+
 	t.Run("Disabled", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -0,0 +1,91 @@
+package userpassword_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/userpassword"
+)
+
+func TestTOTP(t *testing.T) {
+	t.Parallel()
+
+	// The SHA-1 test secret from RFC 6238 appendix B, "12345678901234567890".
+	const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	t.Run("RFC6238", func(t *testing.T) {
+		t.Parallel()
+		// The RFC lists 8 digit codes, these are their last 6 digits.
+		for unix, want := range map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		} {
+			code, err := userpassword.TOTPCode(rfcSecret, time.Unix(unix, 0))
+			require.NoError(t, err)
+			require.Equal(t, want, code, "time %d", unix)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+		secret, err := userpassword.GenerateTOTPSecret()
+		require.NoError(t, err)
+
+		now := time.Now()
+		code, err := userpassword.TOTPCode(secret, now)
+		require.NoError(t, err)
+
+		step, ok, err := userpassword.ValidateTOTP(secret, code, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, userpassword.TOTPStep(now), step)
+
+		// Codes from the previous period are still accepted for clock drift.
+		step, ok, err = userpassword.ValidateTOTP(secret, code, now.Add(userpassword.TOTPPeriod))
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, userpassword.TOTPStep(now), step)
+
+		_, ok, err = userpassword.ValidateTOTP(secret, code, now.Add(5*userpassword.TOTPPeriod))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, ok, err = userpassword.ValidateTOTP(secret, "12345", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, _, err = userpassword.ValidateTOTP("not base32!", code, now)
+		require.Error(t, err)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		t.Parallel()
+		u, err := url.Parse(userpassword.TOTPURL("JBSWY3DPEHPK3PXP", "Coder", "alice"))
+		require.NoError(t, err)
+		require.Equal(t, "otpauth", u.Scheme)
+		require.Equal(t, "totp", u.Host)
+		require.Equal(t, "/Coder:alice", u.Path)
+		require.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+		require.Equal(t, "Coder", u.Query().Get("issuer"))
+	})
+
+	t.Run("RecoveryCodes", func(t *testing.T) {
+		t.Parallel()
+		codes, err := userpassword.GenerateRecoveryCodes()
+		require.NoError(t, err)
+		require.Len(t, codes, userpassword.RecoveryCodeCount)
+		require.Regexp(t, `^[a-z0-9]{5}-[a-z0-9]{5}$`, codes[0])
+		require.NotEqual(t, codes[0], codes[1])
+
+		hashed := userpassword.HashRecoveryCode(codes[0])
+		require.Equal(t, hashed, userpassword.HashRecoveryCode(" "+codes[0][:5]+codes[0][6:]+" "))
+		require.NotEqual(t, hashed, userpassword.HashRecoveryCode(codes[1]))
+	})
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
+
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
@@ -20,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/telemetry"
@@ -199,6 +202,9 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	//nolint:gocritic // The first user is created by the system.
+	api.PublishUserCreated(dbauthz.AsSystemRestricted(ctx), user)
+
 	if api.RefreshEntitlements != nil {
 		err = api.RefreshEntitlements(ctx)
 		if err != nil {
@@ -360,6 +366,16 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		// Handle the deprecated field
 		req.UserLoginType = codersdk.LoginTypeNone
 	}
+	if req.ServiceAccount {
+		if req.UserLoginType != "" && req.UserLoginType != codersdk.LoginTypeNone {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Service accounts cannot use %q authentication.", req.UserLoginType),
+			})
+			return
+		}
+		// Service accounts only authenticate with API tokens.
+		req.UserLoginType = codersdk.LoginTypeNone
+	}
 	if req.UserLoginType == "" {
 		// Default to password auth
 		req.UserLoginType = codersdk.LoginTypePassword
@@ -487,6 +503,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = user
+	api.PublishUserCreated(ctx, user)
 
 	// Report when users are added!
 	api.Telemetry.Report(&telemetry.Snapshot{
@@ -827,6 +844,16 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 		}
 		aReq.New = suspendedUser
 
+		if status == database.UserStatusSuspended && user.Status != database.UserStatusSuspended {
+			api.notifyUserSuspended(ctx, suspendedUser, apiKey.UserID)
+			api.WebhookPublisher.Publish(ctx, codersdk.WebhookEventUserSuspended, codersdk.WebhookUserData{
+				UserID:      suspendedUser.ID,
+				Username:    suspendedUser.Username,
+				Email:       suspendedUser.Email,
+				InitiatorID: apiKey.UserID,
+			})
+		}
+
 		organizations, err := userOrganizationIDs(ctx, api, user)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -840,6 +867,22 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 	}
 }
 
+// notifyUserSuspended lets the user know their account was suspended and
+// by whom. Failures are logged since the suspension has already happened.
+func (api *API) notifyUserSuspended(ctx context.Context, user database.User, initiatorID uuid.UUID) {
+	initiator, err := api.Database.GetUserByID(ctx, initiatorID)
+	if err != nil {
+		api.Logger.Warn(ctx, "get initiator for suspension notification", slog.F("user_id", user.ID), slog.Error(err))
+		return
+	}
+	_, err = api.NotificationsEnqueuer.Enqueue(ctx, user.ID, notifications.TemplateUserAccountSuspended, map[string]string{
+		"initiator": initiator.Username,
+	}, "api_users")
+	if err != nil {
+		api.Logger.Warn(ctx, "enqueue suspension notification", slog.F("user_id", user.ID), slog.Error(err))
+	}
+}
+
 // @Summary Update user appearance settings
 // @ID update-user-appearance-settings
 // @Security CoderSessionToken
@@ -1130,10 +1173,6 @@ func UpdateSiteUserRoles(ctx context.Context, db database.Store, args database.U
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
 	}
 
 	updatedUser, err := db.UpdateUserRoles(ctx, args)
@@ -1244,8 +1283,9 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			UpdatedAt:      dbtime.Now(),
 			HashedPassword: []byte{},
 			// All new users are defaulted to members of the site.
-			RBACRoles: []string{},
-			LoginType: req.LoginType,
+			RBACRoles:        []string{},
+			LoginType:        req.LoginType,
+			IsServiceAccount: req.ServiceAccount,
 		}
 		// If a user signs up with OAuth, they can have no password!
 		if req.Password != "" {
@@ -1261,6 +1301,18 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		if err != nil {
 			return xerrors.Errorf("create user: %w", err)
 		}
+		if user.IsServiceAccount {
+			// Users are dormant until they first log in, which service
+			// accounts never do.
+			user, err = tx.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+				ID:        user.ID,
+				Status:    database.UserStatusActive,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("activate service account: %w", err)
+			}
+		}
 
 		privateKey, publicKey, err := gitsshkey.Generate(api.SSHKeygenAlgorithm)
 		if err != nil {
@@ -1291,6 +1343,23 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 	}, nil)
 }
 
+// PublishUserCreated raises the user created webhook event. Callers must only
+// call it once the transaction that created the user has been committed.
+func (api *API) PublishUserCreated(ctx context.Context, user database.User) {
+	// Users created on login are created by the system, which has the nil
+	// UUID as its ID.
+	var initiatorID uuid.UUID
+	if actor, ok := dbauthz.ActorFromContext(ctx); ok {
+		initiatorID, _ = uuid.Parse(actor.ID)
+	}
+	api.WebhookPublisher.Publish(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		InitiatorID: initiatorID,
+	})
+}
+
 func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID) []codersdk.User {
 	converted := make([]codersdk.User, 0, len(users))
 	for _, u := range users {
@@ -26,6 +26,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
@@ -646,6 +648,45 @@ func TestPostUsers(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, found.LoginType, codersdk.LoginTypeOIDC)
 	})
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci@coder.com",
+			Username:       "ci",
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+		require.True(t, user.IsServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+		// Service accounts never log in, so they start out active.
+		require.Equal(t, codersdk.UserStatusActive, user.Status)
+
+		_, err = client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci2@coder.com",
+			Username:       "ci2",
+			Password:       "SomeSecurePassword!",
+			ServiceAccount: true,
+		})
+		requireStatus(t, err, http.StatusBadRequest)
+
+		_, err = client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: first.OrganizationID,
+			Email:          "ci2@coder.com",
+			Username:       "ci2",
+			UserLoginType:  codersdk.LoginTypeOIDC,
+			ServiceAccount: true,
+		})
+		requireStatus(t, err, http.StatusBadRequest)
+	})
 }
 
 func TestUpdateUserProfile(t *testing.T) {
@@ -1115,7 +1156,8 @@ func TestPutUserSuspend(t *testing.T) {
 	t.Run("SuspendAnotherUser", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
-		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		enqueuer := &notificationstest.FakeEnqueuer{}
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor, NotificationsEnqueuer: enqueuer})
 		numLogs := len(auditor.AuditLogs())
 
 		me := coderdtest.CreateFirstUser(t, client)
@@ -1123,6 +1165,7 @@ func TestPutUserSuspend(t *testing.T) {
 		numLogs++ // add an audit log for login
 
 		_, user := coderdtest.CreateAnotherUser(t, client, me.OrganizationID)
+		userID := user.ID
 		numLogs++ // add an audit log for user create
 
 		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
@@ -1135,6 +1178,11 @@ func TestPutUserSuspend(t *testing.T) {
 
 		require.Len(t, auditor.AuditLogs(), numLogs)
 		require.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[numLogs-1].Action)
+
+		sent := enqueuer.Sent(notifications.TemplateUserAccountSuspended)
+		require.Len(t, sent, 1)
+		require.Equal(t, userID, sent[0].UserID)
+		require.Equal(t, coderdtest.FirstUserParams.Username, sent[0].Labels["initiator"])
 	})
 
 	t.Run("SuspendItSelf", func(t *testing.T) {
@@ -0,0 +1,197 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user sessions
+// @ID get-user-sessions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.UserSession
+// @Router /users/{user}/sessions [get]
+func (api *API) userSessions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	keys, err := api.Database.GetActiveAPIKeysByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	sessions := make([]codersdk.UserSession, 0, len(keys))
+	for _, key := range keys {
+		sessions = append(sessions, convertUserSession(key, apiKey.ID))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
+// Revokes all sessions of the user except the one making the request. Owners
+// revoking the sessions of another user log them out everywhere.
+//
+// @Summary Revoke other user sessions
+// @ID revoke-other-user-sessions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param include_tokens query bool false "Also revoke tokens"
+// @Success 200 {object} codersdk.RevokeUserSessionsResponse
+// @Router /users/{user}/sessions [delete]
+func (api *API) deleteUserSessions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	queryParams := httpapi.NewQueryParamParser()
+	includeTokens := queryParams.Boolean(r.URL.Query(), false, "include_tokens")
+	if len(queryParams.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: queryParams.Errors,
+		})
+		return
+	}
+
+	// The caller's own session is kept. When revoking the sessions of another
+	// user, none of them are.
+	var exceptID string
+	if apiKey.UserID == user.ID {
+		exceptID = apiKey.ID
+	}
+	keys, err := api.Database.DeleteOtherAPIKeysByUserID(ctx, database.DeleteOtherAPIKeysByUserIDParams{
+		UserID:        user.ID,
+		ExceptID:      exceptID,
+		IncludeTokens: includeTokens,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	keyIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyIDs = append(keyIDs, key.ID)
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.APIKey]{
+			Audit:     *api.Auditor.Load(),
+			Log:       api.Logger,
+			UserID:    apiKey.UserID,
+			RequestID: httpmw.RequestID(r),
+			IP:        r.RemoteAddr,
+			Action:    database.AuditActionDelete,
+			Old:       key,
+			Status:    http.StatusOK,
+		})
+	}
+	api.SessionTracker.Revoked(ctx, api.Pubsub, keyIDs...)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RevokeUserSessionsResponse{
+		Revoked: len(keys),
+	})
+}
+
+// @Summary Revoke user session
+// @ID revoke-user-session
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param keyid path string true "Session ID"
+// @Success 204
+// @Router /users/{user}/sessions/{keyid} [delete]
+func (api *API) deleteUserSession(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		keyID             = chi.URLParam(r, "keyid")
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if key.UserID != user.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = key
+
+	err = api.Database.DeleteAPIKeyByID(ctx, keyID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking session.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.SessionTracker.Revoked(ctx, api.Pubsub, keyID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func convertUserSession(k database.APIKey, currentID string) codersdk.UserSession {
+	sessionType := codersdk.UserSessionTypeSession
+	switch {
+	case k.Scope == database.APIKeyScopeApplicationConnect:
+		sessionType = codersdk.UserSessionTypeApp
+	case k.LoginType == database.LoginTypeToken:
+		sessionType = codersdk.UserSessionTypeToken
+	}
+	var ipAddress string
+	if k.IPAddress.Valid {
+		ipAddress = k.IPAddress.IPNet.IP.String()
+	}
+	return codersdk.UserSession{
+		APIKey:       convertAPIKey(k),
+		Type:         sessionType,
+		IPAddress:    ipAddress,
+		UserAgent:    k.UserAgent,
+		Impersonated: k.ImpersonatorID.Valid,
+		Current:      k.ID == currentID,
+	}
+}
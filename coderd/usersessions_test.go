@@ -0,0 +1,188 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{TokenName: "ci"})
+		require.NoError(t, err)
+
+		sessions, err := memberClient.UserSessions(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		var current, token codersdk.UserSession
+		for _, session := range sessions {
+			require.Equal(t, member.ID, session.UserID)
+			require.NotEmpty(t, session.UserAgent)
+			if session.Current {
+				current = session
+			}
+			if session.Type == codersdk.UserSessionTypeToken {
+				token = session
+			}
+		}
+		require.Equal(t, codersdk.UserSessionTypeSession, current.Type)
+		require.NotEmpty(t, current.IPAddress)
+		require.Equal(t, "ci", token.TokenName)
+
+		// Owners can list the sessions of other users, none of which are
+		// theirs.
+		sessions, err = client.UserSessions(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		for _, session := range sessions {
+			require.False(t, session.Current)
+		}
+	})
+
+	t.Run("RevokeOthers", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		otherKey, err := memberClient.CreateAPIKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		other := codersdk.New(client.URL)
+		other.SetSessionToken(otherKey.Key)
+		token, err := memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SetSessionToken(token.Key)
+
+		auditor.ResetLogs()
+		res, err := memberClient.RevokeOtherUserSessions(ctx, codersdk.Me, false)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Revoked)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionDelete,
+			ResourceType: database.ResourceTypeApiKey,
+		}))
+
+		_, err = other.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = tokenClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		res, err = memberClient.RevokeOtherUserSessions(ctx, codersdk.Me, true)
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Revoked)
+		_, err = tokenClient.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+	})
+
+	t.Run("ForceLogout", func(t *testing.T) {
+		t.Parallel()
+
+		client, closer := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		defer closer.Close()
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		watcher, err := memberClient.WatchWorkspace(ctx, workspace.ID)
+		require.NoError(t, err)
+
+		res, err := client.RevokeOtherUserSessions(ctx, member.ID.String(), true)
+		require.NoError(t, err)
+		require.Positive(t, res.Revoked)
+		sessions, err := client.UserSessions(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+		// Requests that were already open are ended too.
+		for {
+			select {
+			case _, ok := <-watcher:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				t.Fatal("watch was not ended")
+			}
+		}
+	})
+
+	t.Run("ForceLogoutNotOwner", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.RevokeOtherUserSessions(ctx, owner.UserID.String(), true)
+		require.Error(t, err)
+		_, err = client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+
+	t.Run("RevokeOne", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		key, err := memberClient.CreateAPIKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		other := codersdk.New(client.URL)
+		other.SetSessionToken(key.Key)
+		keyID := strings.Split(key.Key, "-")[0]
+
+		// Sessions can only be revoked through the user they belong to.
+		err = client.RevokeUserSession(ctx, codersdk.Me, keyID)
+		require.Equal(t, http.StatusNotFound, coderdtest.SDKError(t, err).StatusCode())
+
+		auditor.ResetLogs()
+		err = memberClient.RevokeUserSession(ctx, codersdk.Me, keyID)
+		require.NoError(t, err)
+		require.Len(t, auditor.AuditLogs(), 1)
+		require.Equal(t, database.AuditActionDelete, auditor.AuditLogs()[0].Action)
+
+		_, err = other.User(ctx, codersdk.Me)
+		require.Equal(t, http.StatusUnauthorized, coderdtest.SDKError(t, err).StatusCode())
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,600 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// totpLoginTicketSubject tells TOTP login tickets apart from other JWTs
+	// signed with the same key.
+	totpLoginTicketSubject = "totp-login"
+	totpLoginTicketExpiry  = 5 * time.Minute
+	totpIssuer             = "Coder"
+)
+
+// TOTPLoginClaims are the claims of the ticket returned by a password login
+// that still needs a second factor. The ticket proves the password was
+// correct, so the second step does not need it again.
+type TOTPLoginClaims struct {
+	jwt.RegisteredClaims
+
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// totpLoginTicket returns a signed ticket if the user has to finish logging in
+// with a TOTP code, and whether they must enroll first. An empty ticket means
+// no second factor is needed. If 'false' is returned, the error has been
+// written to the ResponseWriter.
+func (api *API) totpLoginTicket(ctx context.Context, rw http.ResponseWriter, user database.User) (string, bool, bool) {
+	logger := api.Logger.Named(userAuthLoggerName)
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	totp, err := api.Database.GetUserTOTPByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		logger.Error(ctx, "unable to fetch user totp", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return "", false, false
+	}
+	enabled := err == nil && totp.EnabledAt.Valid
+	if !enabled && !api.DeploymentValues.RequireTOTP.Value() {
+		return "", false, true
+	}
+
+	now := time.Now()
+	claims := &TOTPLoginClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    api.DeploymentID,
+			Subject:   totpLoginTicketSubject,
+			Audience:  []string{user.ID.String()},
+			ExpiresAt: jwt.NewNumericDate(now.Add(totpLoginTicketExpiry)),
+			NotBefore: jwt.NewNumericDate(now.Add(time.Second * -1)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+		UserID: user.ID,
+	}
+	// Key must be a byte slice, not an array. So make sure to include the [:]
+	ticket, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString(api.OAuthSigningKey[:])
+	if err != nil {
+		logger.Error(ctx, "unable to sign totp login ticket", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error signing login ticket.",
+			Detail:  err.Error(),
+		})
+		return "", false, false
+	}
+	return ticket, !enabled, true
+}
+
+// parseTOTPLoginTicket returns the user a ticket was issued to. If 'false' is
+// returned, the error has been written to the ResponseWriter.
+func (api *API) parseTOTPLoginTicket(ctx context.Context, rw http.ResponseWriter, ticket string) (database.User, bool) {
+	var claims TOTPLoginClaims
+	token, err := jwt.ParseWithClaims(ticket, &claims, func(token *jwt.Token) (interface{}, error) {
+		return api.OAuthSigningKey[:], nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}))
+	if err != nil || !token.Valid ||
+		claims.Subject != totpLoginTicketSubject ||
+		claims.Issuer != api.DeploymentID ||
+		!claims.VerifyAudience(claims.UserID.String(), true) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid or expired login ticket. Log in with your password again.",
+		})
+		return database.User{}, false
+	}
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), claims.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid or expired login ticket. Log in with your password again.",
+		})
+		return database.User{}, false
+	}
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "TOTP is only supported for password logins.",
+		})
+		return database.User{}, false
+	}
+	return user, true
+}
+
+// Finishes a password login that needs a second factor. If the deployment
+// requires TOTP and the user has just enrolled, this also confirms the
+// enrollment and returns the recovery codes.
+//
+// @Summary Log in user with TOTP
+// @ID log-in-user-with-totp
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithTOTPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithTOTPResponse
+// @Router /users/login/totp [post]
+func (api *API) postLoginTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx         = r.Context()
+		auditor     = api.Auditor.Load()
+		logger      = api.Logger.Named(userAuthLoggerName)
+		auditParams = &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		}
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, auditParams)
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.LoginWithTOTPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	user, ok := api.parseTOTPLoginTicket(ctx, rw, req.Ticket)
+	aReq.UserID = user.ID
+	if !ok {
+		return
+	}
+	if !api.checkLoginAttempt(ctx, rw, r, user, "Incorrect authentication code.") {
+		return
+	}
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	totp, err := api.Database.GetUserTOTPByUserID(sysCtx, user.ID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You must enroll in TOTP before you can log in.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "unable to fetch user totp", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
+	var recoveryCodes []string
+	if totp.EnabledAt.Valid {
+		ok, err = api.checkTOTPCode(sysCtx, totp, req.Code)
+	} else {
+		recoveryCodes, ok, err = api.confirmTOTPEnrollment(sysCtx, totp, req.Code)
+	}
+	if err != nil {
+		logger.Error(ctx, "unable to check totp code", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	if !ok {
+		api.recordLoginFailure(ctx, r, user)
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect authentication code.",
+		})
+		return
+	}
+
+	roles, err := api.Database.GetAuthorizationUserRoles(sysCtx, user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	// The user may have been suspended since the password step.
+	if roles.Status != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is suspended. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+
+	auditParams.AdditionalFields = api.recordLoginSuccess(ctx, r, user)
+	api.resetLoginFailures(ctx, user)
+
+	cookie, key, ok := api.createPasswordLoginKey(ctx, rw, r, user, roles)
+	if !ok {
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithTOTPResponse{
+		SessionToken:  cookie.Value,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// Starts the TOTP enrollment that the deployment requires before the user can
+// finish logging in.
+//
+// @Summary Enroll in TOTP during login
+// @ID enroll-in-totp-during-login
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginTOTPEnrollRequest true "Enroll request"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/login/totp/enroll [post]
+func (api *API) postLoginTOTPEnroll(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req codersdk.LoginTOTPEnrollRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	user, ok := api.parseTOTPLoginTicket(ctx, rw, req.Ticket)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // The user is not fully logged in yet.
+	enrollment, ok := api.startTOTPEnrollment(dbauthz.AsSystemRestricted(ctx), rw, user)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// @Summary Get user TOTP status
+// @ID get-user-totp-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserTOTP
+// @Router /users/{user}/totp [get]
+func (api *API) userTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP status.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.UserTOTP{
+		Required: api.DeploymentValues.RequireTOTP.Value(),
+	}
+	if err == nil && totp.EnabledAt.Valid {
+		resp.Enabled = true
+		resp.RecoveryCodesRemaining = len(totp.HashedRecoveryCodes)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// Starts enrolling the authenticated user in TOTP. The enrollment is only
+// used for logins once it is confirmed with a valid code.
+//
+// @Summary Enroll user in TOTP
+// @ID enroll-user-in-totp
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/{user}/totp [post]
+func (api *API) postUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only enroll yourself in TOTP.",
+		})
+		return
+	}
+
+	enrollment, ok := api.startTOTPEnrollment(ctx, rw, user)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// Confirms the authenticated user's TOTP enrollment with a code from their
+// authenticator app.
+//
+// @Summary Verify user TOTP enrollment
+// @ID verify-user-totp-enrollment
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.VerifyTOTPRequest true "Verify request"
+// @Success 200 {object} codersdk.TOTPRecoveryCodes
+// @Router /users/{user}/totp/verify [post]
+func (api *API) postUserTOTPVerify(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	var req codersdk.VerifyTOTPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only enroll yourself in TOTP.",
+		})
+		return
+	}
+
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Start enrolling in TOTP before verifying a code.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP enrollment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if totp.EnabledAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already enabled.",
+		})
+		return
+	}
+
+	recoveryCodes, ok, err := api.confirmTOTPEnrollment(ctx, totp, req.Code)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error enabling TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Incorrect authentication code.",
+		})
+		return
+	}
+
+	api.Logger.Info(ctx, "user enabled totp", slog.F("user_id", user.ID), slog.F("username", user.Username))
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// Removes the user's TOTP second factor. Users removing their own enabled
+// second factor must provide a TOTP or recovery code, so a stolen session
+// can't turn it off. User admins can reset another user who lost their
+// authenticator app and recovery codes without one. If the deployment
+// requires TOTP, the user enrolls again on their next login.
+//
+// @Summary Reset user TOTP
+// @ID reset-user-totp
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ResetTOTPRequest false "Reset request"
+// @Success 204
+// @Router /users/{user}/totp [delete]
+func (api *API) deleteUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	totp, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP enrollment.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if apiKey.UserID == user.ID && totp.EnabledAt.Valid {
+		var req codersdk.ResetTOTPRequest
+		if !httpapi.Read(ctx, rw, r, &req) {
+			return
+		}
+		ok, err := api.checkTOTPCode(ctx, totp, req.Code)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error checking authentication code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !ok {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Incorrect authentication code.",
+			})
+			return
+		}
+	}
+
+	err = api.Database.DeleteUserTOTPByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resetting TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.Logger.Info(ctx, "reset user totp",
+		slog.F("user_id", user.ID),
+		slog.F("username", user.Username),
+		slog.F("reset_by", apiKey.UserID),
+	)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// startTOTPEnrollment stores a new TOTP secret for the user, replacing any
+// enrollment that was not confirmed. If 'false' is returned, the error has
+// been written to the ResponseWriter.
+func (api *API) startTOTPEnrollment(ctx context.Context, rw http.ResponseWriter, user database.User) (codersdk.TOTPEnrollment, bool) {
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "TOTP is only supported for password logins.",
+		})
+		return codersdk.TOTPEnrollment{}, false
+	}
+
+	secret, err := userpassword.GenerateTOTPSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating TOTP secret.",
+			Detail:  err.Error(),
+		})
+		return codersdk.TOTPEnrollment{}, false
+	}
+	_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: dbtime.Now(),
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The upsert leaves an enabled second factor alone, so a concurrent
+		// confirmation can't be replaced.
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already enabled. Reset it before enrolling again.",
+		})
+		return codersdk.TOTPEnrollment{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error storing TOTP secret.",
+			Detail:  err.Error(),
+		})
+		return codersdk.TOTPEnrollment{}, false
+	}
+
+	return codersdk.TOTPEnrollment{
+		Secret: secret,
+		URL:    userpassword.TOTPURL(secret, totpIssuer, user.Username+"@"+api.AccessURL.Hostname()),
+	}, true
+}
+
+// confirmTOTPEnrollment enables a pending enrollment if the code is valid, and
+// returns the new recovery codes.
+func (api *API) confirmTOTPEnrollment(ctx context.Context, totp database.UserTOTP, code string) ([]string, bool, error) {
+	step, ok, err := userpassword.ValidateTOTP(totp.Secret, code, dbtime.Now())
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	recoveryCodes, err := userpassword.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, false, err
+	}
+	hashed := make([]string, 0, len(recoveryCodes))
+	for _, recoveryCode := range recoveryCodes {
+		hashed = append(hashed, userpassword.HashRecoveryCode(recoveryCode))
+	}
+
+	_, err = api.Database.EnableUserTOTP(ctx, database.EnableUserTOTPParams{
+		EnabledAt:           sql.NullTime{Time: dbtime.Now(), Valid: true},
+		LastUsedStep:        step,
+		HashedRecoveryCodes: hashed,
+		UserID:              totp.UserID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// Another request confirmed the enrollment first.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, xerrors.Errorf("enable user totp: %w", err)
+	}
+	return recoveryCodes, true, nil
+}
+
+// checkTOTPCode checks a TOTP or recovery code for an enabled second factor.
+// Each TOTP code and recovery code is only accepted once.
+func (api *API) checkTOTPCode(ctx context.Context, totp database.UserTOTP, code string) (bool, error) {
+	step, ok, err := userpassword.ValidateTOTP(totp.Secret, code, dbtime.Now())
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		_, err = api.Database.UpdateUserTOTPLastUsedStep(ctx, database.UpdateUserTOTPLastUsedStepParams{
+			LastUsedStep: step,
+			UserID:       totp.UserID,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			// The code was already used.
+			return false, nil
+		}
+		if err != nil {
+			return false, xerrors.Errorf("update totp last used step: %w", err)
+		}
+		return true, nil
+	}
+
+	_, err = api.Database.UseUserTOTPRecoveryCode(ctx, database.UseUserTOTPRecoveryCodeParams{
+		HashedCode: userpassword.HashRecoveryCode(code),
+		UserID:     totp.UserID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("use totp recovery code: %w", err)
+	}
+	return true, nil
+}
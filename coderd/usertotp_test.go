@@ -0,0 +1,271 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserTOTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EnrollAndLogin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anonClient := codersdk.New(client.URL)
+
+		status, err := member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+		require.False(t, status.Required)
+
+		// Users can only enroll themselves.
+		_, err = client.EnrollTOTP(ctx, user.Username)
+		requireStatus(t, err, http.StatusForbidden)
+
+		enrollment, err := member.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Contains(t, enrollment.URL, enrollment.Secret)
+
+		// An unconfirmed enrollment does not change how the user logs in.
+		login, err := anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, login.SessionToken)
+		require.Empty(t, login.TOTPTicket)
+
+		_, err = member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: "abcdef"})
+		requireStatus(t, err, http.StatusBadRequest)
+
+		recovery, err := member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, time.Now()),
+		})
+		require.NoError(t, err)
+		require.Len(t, recovery.RecoveryCodes, userpassword.RecoveryCodeCount)
+
+		_, err = member.EnrollTOTP(ctx, codersdk.Me)
+		requireStatus(t, err, http.StatusConflict)
+
+		status, err = member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.Equal(t, userpassword.RecoveryCodeCount, status.RecoveryCodesRemaining)
+
+		// The password alone no longer returns a session token.
+		login, err = anonClient.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		require.Empty(t, login.SessionToken)
+		require.NotEmpty(t, login.TOTPTicket)
+		require.False(t, login.TOTPEnrollmentRequired)
+
+		_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   "abcdef",
+		})
+		requireStatus(t, err, http.StatusUnauthorized)
+
+		_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket + "x",
+			Code:   "abcdef",
+		})
+		requireStatus(t, err, http.StatusUnauthorized)
+
+		// The code used to verify the enrollment has been used, so use the
+		// next one.
+		code := totpCode(t, enrollment.Secret, time.Now().Add(userpassword.TOTPPeriod))
+		resp, err := anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   code,
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.RecoveryCodes)
+		requireSessionUser(ctx, t, client, resp.SessionToken, user.ID.String())
+
+		// Codes cannot be replayed.
+		_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   code,
+		})
+		requireStatus(t, err, http.StatusUnauthorized)
+
+		// Recovery codes work once.
+		resp, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   recovery.RecoveryCodes[0],
+		})
+		require.NoError(t, err)
+		requireSessionUser(ctx, t, client, resp.SessionToken, user.ID.String())
+
+		_, err = anonClient.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   recovery.RecoveryCodes[0],
+		})
+		requireStatus(t, err, http.StatusUnauthorized)
+
+		status, err = member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, userpassword.RecoveryCodeCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireTOTP = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateFirstUser(ctx, coderdtest.FirstUserParams)
+		require.NoError(t, err)
+
+		login, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+		require.Empty(t, login.SessionToken)
+		require.True(t, login.TOTPEnrollmentRequired)
+
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   "123456",
+		})
+		requireStatus(t, err, http.StatusBadRequest)
+
+		enrollment, err := client.LoginTOTPEnroll(ctx, codersdk.LoginTOTPEnrollRequest{
+			Ticket: login.TOTPTicket,
+		})
+		require.NoError(t, err)
+
+		resp, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			Ticket: login.TOTPTicket,
+			Code:   totpCode(t, enrollment.Secret, time.Now()),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.RecoveryCodes, userpassword.RecoveryCodeCount)
+
+		client.SetSessionToken(resp.SessionToken)
+		status, err := client.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.True(t, status.Required)
+
+		// Enrolling again needs a reset first.
+		_, err = client.LoginTOTPEnroll(ctx, codersdk.LoginTOTPEnrollRequest{
+			Ticket: login.TOTPTicket,
+		})
+		requireStatus(t, err, http.StatusConflict)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Nothing to reset yet.
+		err := userAdmin.ResetTOTP(ctx, user.Username, codersdk.ResetTOTPRequest{})
+		requireStatus(t, err, http.StatusNotFound)
+
+		enrollment, err := member.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, time.Now()),
+		})
+		require.NoError(t, err)
+
+		// Reading the user is not enough to see or reset their TOTP.
+		_, err = templateAdmin.UserTOTP(ctx, user.Username)
+		requireStatus(t, err, http.StatusNotFound)
+		err = templateAdmin.ResetTOTP(ctx, user.Username, codersdk.ResetTOTPRequest{})
+		requireStatus(t, err, http.StatusNotFound)
+
+		err = userAdmin.ResetTOTP(ctx, user.Username, codersdk.ResetTOTPRequest{})
+		require.NoError(t, err)
+
+		status, err := member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+
+		login, err := codersdk.New(client.URL).LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    user.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, login.SessionToken)
+	})
+
+	t.Run("ResetSelf", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		enrollment, err := member.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		recovery, err := member.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, time.Now()),
+		})
+		require.NoError(t, err)
+
+		// A session alone is not enough to remove the second factor.
+		err = member.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{})
+		requireStatus(t, err, http.StatusBadRequest)
+		err = member.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{Code: "abcdef"})
+		requireStatus(t, err, http.StatusBadRequest)
+
+		status, err := member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+
+		err = member.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{
+			Code: recovery.RecoveryCodes[0],
+		})
+		require.NoError(t, err)
+
+		status, err = member.UserTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+}
+
+func totpCode(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	code, err := userpassword.TOTPCode(secret, now)
+	require.NoError(t, err)
+	return code
+}
+
+func requireSessionUser(ctx context.Context, t *testing.T, client *codersdk.Client, token, userID string) {
+	t.Helper()
+	sessionClient := codersdk.New(client.URL)
+	sessionClient.SetSessionToken(token)
+	me, err := sessionClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, userID, me.ID.String())
+}
@@ -0,0 +1,373 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// defaultWebhookDeliveriesLimit is the number of deliveries returned when no
+// limit is requested.
+const defaultWebhookDeliveriesLimit = 25
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	dbWebhooks, err := api.Database.GetWebhooks(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Webhooks(dbWebhooks))
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Webhook(webhook))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.Webhook
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	events, ok := webhookEvents(ctx, rw, req.Events)
+	if !ok {
+		return
+	}
+
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Url:       req.URL,
+		Events:    events,
+		Secret:    req.Secret,
+		Enabled:   true,
+		CreatedAt: dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A webhook named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = webhook
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.Webhook(webhook))
+}
+
+// @Summary Update webhook
+// @ID update-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param request body codersdk.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [patch]
+func (api *API) patchWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = webhook
+	defer commitAudit()
+
+	var req codersdk.UpdateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	params := database.UpdateWebhookByIDParams{
+		ID:        webhook.ID,
+		Name:      webhook.Name,
+		Url:       webhook.Url,
+		Events:    webhook.Events,
+		Secret:    webhook.Secret,
+		Enabled:   webhook.Enabled,
+		UpdatedAt: dbtime.Now(),
+	}
+	if req.Name != nil {
+		params.Name = *req.Name
+	}
+	if req.URL != nil {
+		params.Url = *req.URL
+	}
+	if req.Events != nil {
+		events, ok := webhookEvents(ctx, rw, *req.Events)
+		if !ok {
+			return
+		}
+		params.Events = events
+	}
+	if req.Secret != nil {
+		params.Secret = *req.Secret
+	}
+	if req.Enabled != nil {
+		params.Enabled = *req.Enabled
+	}
+
+	updated, err := api.Database.UpdateWebhookByID(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A webhook named %q already exists.", params.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Webhook(updated))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 204
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	aReq.Old = webhook
+	defer commitAudit()
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Maximum number of deliveries to return, newest first"
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	limit := p.PositiveInt32(vals, defaultWebhookDeliveriesLimit, "limit")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if limit == 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		LimitOpt:  limit,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.WebhookDeliveries(deliveries))
+}
+
+// @Summary Redeliver webhook delivery
+// @Description Queues a new delivery with the payload of an earlier one. The
+// @Description payload keeps the ID of the original event.
+// @ID redeliver-webhook-delivery
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param delivery path string true "Delivery ID" format(uuid)
+// @Success 201 {object} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries/{delivery}/redeliver [post]
+func (api *API) postWebhookRedelivery(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook := httpmw.WebhookParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	deliveryID, ok := httpmw.ParseUUIDParam(rw, r, "delivery")
+	if !ok {
+		return
+	}
+	delivery, err := api.Database.GetWebhookDeliveryByID(ctx, deliveryID)
+	if httpapi.Is404Error(err) || (err == nil && delivery.WebhookID != webhook.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook delivery.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	//nolint:gocritic // Deliveries are stored by the system on behalf of
+	// users allowed to update the webhook, checked above.
+	redelivery, err := api.Database.InsertWebhookDelivery(dbauthz.AsSystemRestricted(ctx), database.InsertWebhookDeliveryParams{
+		ID:        uuid.New(),
+		WebhookID: webhook.ID,
+		Event:     delivery.Event,
+		Payload:   delivery.Payload,
+		CreatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error queueing webhook redelivery.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = webhooks.WakeDispatchers(api.Pubsub)
+	if err != nil {
+		// The redelivery is picked up on the next fetch anyway.
+		api.Logger.Warn(ctx, "wake webhook dispatchers", slog.Error(err))
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.WebhookDelivery(redelivery))
+}
+
+// webhookEvents validates the events a webhook subscribes to and converts
+// them for the database. It writes a response and returns false if any
+// event is unknown.
+func webhookEvents(ctx context.Context, rw http.ResponseWriter, events []codersdk.WebhookEvent) ([]string, bool) {
+	converted := make([]string, 0, len(events))
+	for _, event := range events {
+		if !event.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown webhook event %q.", event),
+				Validations: []codersdk.ValidationError{{
+					Field:  "events",
+					Detail: fmt.Sprintf("Must be one of %v.", codersdk.WebhookEvents),
+				}},
+			})
+			return nil, false
+		}
+		converted = append(converted, string(event))
+	}
+	return converted, true
+}
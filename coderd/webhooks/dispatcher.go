@@ -0,0 +1,276 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// batchSize is the number of deliveries leased and sent concurrently
+	// on every fetch.
+	batchSize = 10
+	// maxResponseBodySize is the number of bytes of each response kept in
+	// the delivery log.
+	maxResponseBodySize = 4096
+)
+
+// DispatcherOptions configures a Dispatcher. Zero values use the defaults.
+type DispatcherOptions struct {
+	// FetchInterval is how often pending deliveries are fetched when no
+	// dispatcher is woken over pubsub. Defaults to 15 seconds.
+	FetchInterval time.Duration
+	// RetryInterval is the delay before the first retry of a failed
+	// delivery. It doubles with every attempt. Defaults to one minute.
+	RetryInterval time.Duration
+	// MaxAttempts is the number of times a delivery is attempted before it
+	// fails permanently. Defaults to 5.
+	MaxAttempts int32
+	// Timeout bounds every request to a webhook. Defaults to 10 seconds.
+	Timeout time.Duration
+	// HTTPClient sends the requests. Defaults to a new client.
+	HTTPClient *http.Client
+}
+
+// Dispatcher sends stored deliveries to their webhooks.
+type Dispatcher struct {
+	opts   DispatcherOptions
+	store  database.Store
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a Dispatcher. Call Run to start sending
+// deliveries.
+func NewDispatcher(store database.Store, ps pubsub.Pubsub, log slog.Logger, opts DispatcherOptions) *Dispatcher {
+	if opts.FetchInterval == 0 {
+		opts.FetchInterval = 15 * time.Second
+	}
+	if opts.RetryInterval == 0 {
+		opts.RetryInterval = time.Minute
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{}
+	}
+	return &Dispatcher{
+		opts:   opts,
+		store:  store,
+		pubsub: ps,
+		log:    log.Named("webhooks_dispatcher"),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// Run starts sending deliveries in the background until Close is called.
+func (d *Dispatcher) Run(ctx context.Context) {
+	//nolint:gocritic // The dispatcher sends the deliveries of every webhook.
+	ctx, d.cancel = context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+
+	cancelSub, err := d.pubsub.Subscribe(deliveriesChannel, func(context.Context, []byte) {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		// Deliveries are still sent on every tick, just with more delay.
+		d.log.Warn(ctx, "subscribe to webhook deliveries", slog.Error(err))
+		cancelSub = func() {}
+	}
+
+	go func() {
+		defer close(d.done)
+		defer cancelSub()
+
+		ticker := time.NewTicker(d.opts.FetchInterval)
+		defer ticker.Stop()
+		for {
+			err := d.processBatch(ctx)
+			if err != nil && !xerrors.Is(err, context.Canceled) {
+				d.log.Error(ctx, "process webhook deliveries", slog.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-d.wake:
+			}
+		}
+	}()
+}
+
+// Close stops sending deliveries and waits for in-flight requests to
+// finish. Deliveries leased but not sent are picked up again once their
+// lease expires.
+func (d *Dispatcher) Close() error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	<-d.done
+	return nil
+}
+
+func (d *Dispatcher) processBatch(ctx context.Context) error {
+	now := dbtime.Now()
+	deliveries, err := d.store.AcquireWebhookDeliveries(ctx, database.AcquireWebhookDeliveriesParams{
+		Now: now,
+		// A lease outlives the longest possible request, so no other
+		// replica picks a delivery up while it is still being sent.
+		LeasedUntil:     sql.NullTime{Time: now.Add(2 * d.opts.Timeout), Valid: true},
+		MaxAttemptCount: d.opts.MaxAttempts,
+		Count:           batchSize,
+	})
+	if err != nil {
+		return xerrors.Errorf("acquire deliveries: %w", err)
+	}
+
+	var eg errgroup.Group
+	for _, delivery := range deliveries {
+		delivery := delivery
+		eg.Go(func() error {
+			d.deliver(ctx, delivery)
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// result is the outcome of sending a delivery.
+type result struct {
+	statusCode int
+	body       string
+	retryable  bool
+	err        error
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery database.WebhookDelivery) {
+	log := d.log.With(slog.F("delivery_id", delivery.ID), slog.F("webhook_id", delivery.WebhookID),
+		slog.F("event", delivery.Event), slog.F("attempt", delivery.AttemptCount+1))
+
+	var res result
+	webhook, err := d.store.GetWebhookByID(ctx, delivery.WebhookID)
+	switch {
+	case err != nil:
+		res = result{retryable: true, err: xerrors.Errorf("get webhook: %w", err)}
+	case !webhook.Enabled:
+		res = result{err: xerrors.New("webhook is disabled")}
+	default:
+		sendCtx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+		res = d.send(sendCtx, webhook, delivery)
+		cancel()
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the delivery is retried when its lease expires.
+		return
+	}
+
+	now := dbtime.Now()
+	responseStatus := sql.NullInt32{Int32: int32(res.statusCode), Valid: res.statusCode != 0}
+	responseBody := sql.NullString{String: res.body, Valid: res.statusCode != 0}
+	if res.err == nil {
+		log.Debug(ctx, "webhook delivered", slog.F("status", res.statusCode))
+		err = d.store.MarkWebhookDeliverySucceeded(ctx, database.MarkWebhookDeliverySucceededParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+			ResponseBody:   responseBody,
+			DeliveredAt:    sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			log.Error(ctx, "mark webhook delivery succeeded", slog.Error(err))
+		}
+		return
+	}
+
+	attempts := delivery.AttemptCount + 1
+	params := database.MarkWebhookDeliveryFailedParams{
+		ID:             delivery.ID,
+		Status:         database.WebhookDeliveryStatusPermanentFailure,
+		ResponseStatus: responseStatus,
+		ResponseBody:   responseBody,
+		Error:          sql.NullString{String: res.err.Error(), Valid: true},
+		UpdatedAt:      now,
+	}
+	if res.retryable && attempts < d.opts.MaxAttempts {
+		params.Status = database.WebhookDeliveryStatusTemporaryFailure
+		params.NextAttemptAfter = sql.NullTime{Time: now.Add(d.retryBackoff(attempts)), Valid: true}
+	}
+	log.Warn(ctx, "webhook delivery failed",
+		slog.F("status", params.Status), slog.F("next_attempt_after", params.NextAttemptAfter.Time), slog.Error(res.err))
+
+	err = d.store.MarkWebhookDeliveryFailed(ctx, params)
+	if err != nil {
+		log.Error(ctx, "mark webhook delivery failed", slog.Error(err))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return result{err: xerrors.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Coder/"+buildinfo.Version())
+	req.Header.Set(codersdk.WebhookEventHeader, delivery.Event)
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	if webhook.Secret != "" {
+		req.Header.Set(codersdk.WebhookSignatureHeader, Sign(webhook.Secret, delivery.Payload))
+	}
+
+	resp, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return result{retryable: true, err: xerrors.Errorf("send request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	res := result{
+		statusCode: resp.StatusCode,
+		// Postgres rejects text that isn't valid UTF-8 or contains NUL.
+		body: strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", ""),
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return res
+	}
+	res.err = xerrors.Errorf("unexpected status %d", resp.StatusCode)
+	// Server errors and rate limits are usually temporary, any other
+	// response means the endpoint will never accept the delivery.
+	res.retryable = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return res
+}
+
+// retryBackoff returns the delay before the next attempt after the given
+// number of failed attempts.
+func (d *Dispatcher) retryBackoff(attempts int32) time.Duration {
+	// Cap the exponent so a large max attempt count can't overflow.
+	exponent := min(attempts-1, 10)
+	return d.opts.RetryInterval * time.Duration(1<<exponent)
+}
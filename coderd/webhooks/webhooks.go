@@ -0,0 +1,154 @@
+// Package webhooks sends events that happen in the deployment, such as
+// workspace builds finishing or users being created, to endpoints
+// configured by administrators.
+//
+// A Publisher stores a delivery for every enabled webhook subscribed to an
+// event and wakes the Dispatcher over pubsub. The Dispatcher runs in every
+// coderd replica, leases batches of pending deliveries, POSTs them to their
+// webhook and records the response, retrying transient failures with an
+// exponential backoff. Deliveries are kept as a log that administrators can
+// inspect and redeliver from.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// deliveriesChannel is the pubsub channel used to wake dispatchers when
+// new deliveries are stored.
+const deliveriesChannel = "webhook_deliveries"
+
+// Publisher sends events to the webhooks subscribed to them.
+type Publisher interface {
+	// Publish queues the event for delivery. data is marshaled into the
+	// payload and should be the codersdk.Webhook*Data type of the event.
+	// Failures are logged rather than returned, so publishing never fails
+	// the operation that raised the event.
+	Publish(ctx context.Context, event codersdk.WebhookEvent, data any)
+}
+
+// NoopPublisher discards all events.
+type NoopPublisher struct{}
+
+// NewNoopPublisher returns a Publisher that discards all events.
+func NewNoopPublisher() *NoopPublisher {
+	return &NoopPublisher{}
+}
+
+func (*NoopPublisher) Publish(context.Context, codersdk.WebhookEvent, any) {}
+
+// StorePublisher stores deliveries in the database for a Dispatcher to
+// send.
+type StorePublisher struct {
+	store  database.Store
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+}
+
+// NewStorePublisher creates a Publisher that stores deliveries and wakes
+// dispatchers through ps.
+func NewStorePublisher(store database.Store, ps pubsub.Pubsub, log slog.Logger) *StorePublisher {
+	return &StorePublisher{
+		store:  store,
+		pubsub: ps,
+		log:    log.Named("webhooks_publisher"),
+	}
+}
+
+func (p *StorePublisher) Publish(ctx context.Context, event codersdk.WebhookEvent, data any) {
+	log := p.log.With(slog.F("event", event))
+	err := p.publish(ctx, event, data)
+	if err != nil {
+		log.Error(ctx, "publish webhook event", slog.Error(err))
+	}
+}
+
+func (p *StorePublisher) publish(ctx context.Context, event codersdk.WebhookEvent, data any) error {
+	// Events are raised on behalf of users who can't read webhooks.
+	//nolint:gocritic // The webhook system reads webhooks as the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	webhooks, err := p.store.GetWebhooks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	webhooks = slices.DeleteFunc(webhooks, func(webhook database.Webhook) bool {
+		return !Subscribed(webhook, event)
+	})
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return xerrors.Errorf("marshal data: %w", err)
+	}
+	now := dbtime.Now()
+	payload, err := json.Marshal(codersdk.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		Timestamp: now,
+		Data:      rawData,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		_, err := p.store.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: webhook.ID,
+			Event:     string(event),
+			Payload:   payload,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert delivery for webhook %q: %w", webhook.Name, err)
+		}
+	}
+
+	err = WakeDispatchers(p.pubsub)
+	if err != nil {
+		// The deliveries are picked up on the next fetch anyway.
+		p.log.Warn(ctx, "wake webhook dispatchers", slog.Error(err))
+	}
+	return nil
+}
+
+// WakeDispatchers tells dispatchers in every replica to fetch pending
+// deliveries now rather than on their next tick.
+func WakeDispatchers(ps pubsub.Pubsub) error {
+	return ps.Publish(deliveriesChannel, nil)
+}
+
+// Subscribed reports whether the webhook should receive the event.
+func Subscribed(webhook database.Webhook, event codersdk.WebhookEvent) bool {
+	if !webhook.Enabled {
+		return false
+	}
+	return len(webhook.Events) == 0 || slices.Contains(webhook.Events, string(event))
+}
+
+// Sign returns the value of the signature header for a request body sent
+// to a webhook with the given secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,268 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestStorePublisher(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmem.New()
+	ps := pubsub.NewInMemory()
+	all := dbgen.Webhook(t, db, database.Webhook{Name: "all"})
+	builds := dbgen.Webhook(t, db, database.Webhook{
+		Name:   "builds",
+		Events: []string{string(codersdk.WebhookEventWorkspaceBuildFailed)},
+	})
+	users := dbgen.Webhook(t, db, database.Webhook{
+		Name:   "users",
+		Events: []string{string(codersdk.WebhookEventUserCreated)},
+	})
+	disabled := dbgen.Webhook(t, db, database.Webhook{Name: "disabled"})
+	_, err := db.UpdateWebhookByID(ctx, database.UpdateWebhookByIDParams{
+		ID:        disabled.ID,
+		Name:      disabled.Name,
+		Url:       disabled.Url,
+		Events:    disabled.Events,
+		Enabled:   false,
+		UpdatedAt: dbtime.Now(),
+	})
+	require.NoError(t, err)
+
+	woken := make(chan struct{}, 1)
+	cancel, err := ps.Subscribe("webhook_deliveries", func(context.Context, []byte) {
+		woken <- struct{}{}
+	})
+	require.NoError(t, err)
+	t.Cleanup(cancel)
+
+	buildID := uuid.New()
+	webhooks.NewStorePublisher(db, ps, slogtest.Make(t, nil)).Publish(ctx, codersdk.WebhookEventWorkspaceBuildFailed, codersdk.WebhookWorkspaceBuildData{
+		BuildID: buildID,
+		Error:   "boom",
+	})
+
+	select {
+	case <-woken:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for dispatchers to be woken")
+	}
+
+	var eventID uuid.UUID
+	for _, webhook := range []database.Webhook{all, builds} {
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+			LimitOpt:  10,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1, webhook.Name)
+		require.Equal(t, database.WebhookDeliveryStatusPending, deliveries[0].Status)
+		require.Equal(t, string(codersdk.WebhookEventWorkspaceBuildFailed), deliveries[0].Event)
+
+		var payload codersdk.WebhookPayload
+		require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+		require.Equal(t, codersdk.WebhookEventWorkspaceBuildFailed, payload.Event)
+		// Every webhook is sent the same event.
+		if eventID == uuid.Nil {
+			eventID = payload.ID
+		}
+		require.Equal(t, eventID, payload.ID)
+
+		var data codersdk.WebhookWorkspaceBuildData
+		require.NoError(t, json.Unmarshal(payload.Data, &data))
+		require.Equal(t, buildID, data.BuildID)
+		require.Equal(t, "boom", data.Error)
+	}
+	for _, webhook := range []database.Webhook{users, disabled} {
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+			LimitOpt:  10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries, webhook.Name)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SignsRequests", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan *http.Request, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, webhooks.Sign("hunter2", body), r.Header.Get(codersdk.WebhookSignatureHeader))
+			received <- r
+			_, _ = w.Write([]byte("thanks"))
+		}))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := setupDispatcher(t, webhooks.DispatcherOptions{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{Url: srv.URL, Secret: "hunter2"})
+		delivery := dbgen.WebhookDelivery(t, db, database.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     string(codersdk.WebhookEventUserCreated),
+		})
+
+		var req *http.Request
+		select {
+		case req = <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for webhook")
+		}
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), req.Header.Get(codersdk.WebhookEventHeader))
+		require.Equal(t, delivery.ID.String(), req.Header.Get(codersdk.WebhookDeliveryHeader))
+
+		delivery = waitForStatus(t, db, delivery.ID, database.WebhookDeliveryStatusSucceeded)
+		require.EqualValues(t, 1, delivery.AttemptCount)
+		require.EqualValues(t, http.StatusOK, delivery.ResponseStatus.Int32)
+		require.Equal(t, "thanks", delivery.ResponseBody.String)
+		require.True(t, delivery.DeliveredAt.Valid)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan http.Header, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- r.Header
+		}))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := setupDispatcher(t, webhooks.DispatcherOptions{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{Url: srv.URL})
+		dbgen.WebhookDelivery(t, db, database.WebhookDelivery{WebhookID: webhook.ID})
+
+		select {
+		case header := <-received:
+			require.Empty(t, header.Get(codersdk.WebhookSignatureHeader))
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for webhook")
+		}
+	})
+
+	t.Run("RetriesUntilDelivered", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Fail the first two attempts.
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		db := setupDispatcher(t, webhooks.DispatcherOptions{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{Url: srv.URL})
+		delivery := dbgen.WebhookDelivery(t, db, database.WebhookDelivery{WebhookID: webhook.ID})
+
+		delivery = waitForStatus(t, db, delivery.ID, database.WebhookDeliveryStatusSucceeded)
+		require.EqualValues(t, 3, delivery.AttemptCount)
+		require.EqualValues(t, http.StatusNoContent, delivery.ResponseStatus.Int32)
+		require.False(t, delivery.Error.Valid)
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			http.Error(w, "unknown event", http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+
+		db := setupDispatcher(t, webhooks.DispatcherOptions{})
+		webhook := dbgen.Webhook(t, db, database.Webhook{Url: srv.URL})
+		delivery := dbgen.WebhookDelivery(t, db, database.WebhookDelivery{WebhookID: webhook.ID})
+
+		delivery = waitForStatus(t, db, delivery.ID, database.WebhookDeliveryStatusPermanentFailure)
+		require.EqualValues(t, 1, delivery.AttemptCount)
+		require.EqualValues(t, http.StatusBadRequest, delivery.ResponseStatus.Int32)
+		require.Contains(t, delivery.ResponseBody.String, "unknown event")
+		require.Contains(t, delivery.Error.String, "unexpected status 400")
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(srv.Close)
+
+		db := setupDispatcher(t, webhooks.DispatcherOptions{MaxAttempts: 3})
+		webhook := dbgen.Webhook(t, db, database.Webhook{Url: srv.URL})
+		delivery := dbgen.WebhookDelivery(t, db, database.WebhookDelivery{WebhookID: webhook.ID})
+
+		delivery = waitForStatus(t, db, delivery.ID, database.WebhookDeliveryStatusPermanentFailure)
+		require.EqualValues(t, 3, delivery.AttemptCount)
+		require.EqualValues(t, 3, calls.Load())
+	})
+}
+
+// setupDispatcher starts a Dispatcher that retries quickly and returns its
+// database.
+func setupDispatcher(t *testing.T, opts webhooks.DispatcherOptions) database.Store {
+	t.Helper()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+	opts.FetchInterval = 10 * time.Millisecond
+	opts.RetryInterval = time.Millisecond
+	opts.Timeout = testutil.WaitShort
+
+	dispatcher := webhooks.NewDispatcher(db, pubsub.NewInMemory(), logger, opts)
+	dispatcher.Run(testutil.Context(t, testutil.WaitLong))
+	t.Cleanup(func() {
+		require.NoError(t, dispatcher.Close())
+	})
+	return db
+}
+
+func waitForStatus(t *testing.T, db database.Store, id uuid.UUID, status database.WebhookDeliveryStatus) database.WebhookDelivery {
+	t.Helper()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	var delivery database.WebhookDelivery
+	require.Eventually(t, func() bool {
+		var err error
+		delivery, err = db.GetWebhookDeliveryByID(ctx, id)
+		return err == nil && delivery.Status == status
+	}, testutil.WaitLong, testutil.IntervalFast)
+	return delivery
+}
@@ -0,0 +1,44 @@
+package webhookstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Event is an event recorded by FakePublisher.
+type Event struct {
+	Event codersdk.WebhookEvent
+	Data  any
+}
+
+// FakePublisher records events instead of delivering them.
+type FakePublisher struct {
+	mu        sync.Mutex
+	published []Event
+}
+
+func (f *FakePublisher) Publish(_ context.Context, event codersdk.WebhookEvent, data any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.published = append(f.published, Event{Event: event, Data: data})
+}
+
+// Published returns the events recorded so far, optionally filtered to the
+// given kinds of event.
+func (f *FakePublisher) Published(events ...codersdk.WebhookEvent) []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []Event
+	for _, e := range f.published {
+		if len(events) > 0 && !slices.Contains(events, e.Event) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "ci",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+			Secret: "hunter2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ci", webhook.Name)
+		require.True(t, webhook.Enabled)
+		require.True(t, webhook.HasSecret)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed}, webhook.Events)
+
+		got, err := client.Webhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, webhook, got)
+
+		// Only the fields that are set change.
+		disabled := false
+		noSecret := ""
+		updated, err := client.UpdateWebhook(ctx, webhook.ID, codersdk.UpdateWebhookRequest{
+			Enabled: &disabled,
+			Secret:  &noSecret,
+		})
+		require.NoError(t, err)
+		require.False(t, updated.Enabled)
+		require.False(t, updated.HasSecret)
+		require.Equal(t, webhook.Name, updated.Name)
+		require.Equal(t, webhook.URL, updated.URL)
+		require.Equal(t, webhook.Events, updated.Events)
+
+		all, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, updated, all[0])
+
+		err = client.DeleteWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, webhook.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		req := codersdk.CreateWebhookRequest{Name: "ci", URL: "https://example.com/hook"}
+		_, err := client.CreateWebhook(ctx, req)
+		require.NoError(t, err)
+		_, err = client.CreateWebhook(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "ci",
+			URL:    "https://example.com/hook",
+			Events: []codersdk.WebhookEvent{"workspace.exploded"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+
+		owner := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, owner)
+		member, _ := coderdtest.CreateAnotherUser(t, owner, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		webhook, err := owner.CreateWebhook(ctx, codersdk.CreateWebhookRequest{Name: "ci", URL: "https://example.com/hook"})
+		require.NoError(t, err)
+
+		var apiErr *codersdk.Error
+		_, err = member.Webhooks(ctx)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{Name: "mine", URL: "https://example.com/hook"})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.Webhook(ctx, webhook.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("DeliverAndRedeliver", func(t *testing.T) {
+		t.Parallel()
+
+		type request struct {
+			header  http.Header
+			payload codersdk.WebhookPayload
+		}
+		received := make(chan request, 2)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, webhooks.Sign("hunter2", body), r.Header.Get(codersdk.WebhookSignatureHeader))
+			var payload codersdk.WebhookPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			received <- request{header: r.Header, payload: payload}
+		}))
+		t.Cleanup(srv.Close)
+
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "users",
+			URL:    srv.URL,
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+			Secret: "hunter2",
+		})
+		require.NoError(t, err)
+
+		_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		var req request
+		select {
+		case req = <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for webhook")
+		}
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), req.header.Get(codersdk.WebhookEventHeader))
+		require.Equal(t, codersdk.WebhookEventUserCreated, req.payload.Event)
+		var data codersdk.WebhookUserData
+		require.NoError(t, json.Unmarshal(req.payload.Data, &data))
+		require.Equal(t, user.ID, data.UserID)
+		require.Equal(t, user.Username, data.Username)
+		require.Equal(t, first.UserID, data.InitiatorID)
+
+		var deliveries []codersdk.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, err = client.WebhookDeliveries(ctx, webhook.ID, 0)
+			return err == nil && len(deliveries) == 1 && deliveries[0].Status == codersdk.WebhookDeliveryStatusSucceeded
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, req.header.Get(codersdk.WebhookDeliveryHeader), deliveries[0].ID.String())
+		require.EqualValues(t, http.StatusOK, deliveries[0].ResponseStatus)
+
+		eventID := req.payload.ID
+		redelivery, err := client.RedeliverWebhookDelivery(ctx, webhook.ID, deliveries[0].ID)
+		require.NoError(t, err)
+		require.NotEqual(t, deliveries[0].ID, redelivery.ID)
+		select {
+		case req = <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for redelivery")
+		}
+		require.Equal(t, redelivery.ID.String(), req.header.Get(codersdk.WebhookDeliveryHeader))
+		// The payload, including the event ID, is unchanged.
+		require.Equal(t, eventID, req.payload.ID)
+		require.Equal(t, codersdk.WebhookEventUserCreated, req.payload.Event)
+	})
+}
@@ -0,0 +1,294 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace ACLs
+// @ID get-workspace-acls
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	userIDs := make([]uuid.UUID, 0, len(workspace.UserACL))
+	for id := range workspace.UserACL {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	// The caller might not be able to read the users and groups the
+	// workspace is shared with, but anyone who can read the workspace
+	// may know who else can use it.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	users := make([]codersdk.WorkspaceUser, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		dbUsers, err := api.Database.GetUsersByIDs(sysCtx, userIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		for _, user := range dbUsers {
+			users = append(users, codersdk.WorkspaceUser{
+				ReducedUser: db2sdk.ReducedUser(user),
+				Role:        convertToWorkspaceRole(workspace.UserACL[user.ID.String()]),
+			})
+		}
+	}
+
+	groups := make([]codersdk.WorkspaceGroup, 0, len(workspace.GroupACL))
+	for id, actions := range workspace.GroupACL {
+		groupID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		group, err := api.Database.GetGroupByID(sysCtx, groupID)
+		if httpapi.Is404Error(err) {
+			// The group was deleted after the workspace was shared with it.
+			continue
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		members, err := api.Database.GetGroupMembers(sysCtx, group.ID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		groups = append(groups, codersdk.WorkspaceGroup{
+			Group: db2sdk.Group(group, members),
+			Role:  convertToWorkspaceRole(actions),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceACL{
+		Users:  users,
+		Groups: groups,
+	})
+}
+
+// @Summary Update workspace ACL
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 200 {object} codersdk.Response
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	// Only the owner and admins can share a workspace. Having a workspace
+	// shared with you never allows sharing it further.
+	if !api.Authorize(r, rbac.ActionCreate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	userPerms, validErrs := resolveWorkspaceACLPerms(ctx, api.Database, workspace, req.UserPerms, "user_perms", true)
+	groupPerms, groupErrs := resolveWorkspaceACLPerms(ctx, api.Database, workspace, req.GroupPerms, "group_perms", false)
+	validErrs = append(validErrs, groupErrs...)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		if workspace.UserACL == nil {
+			workspace.UserACL = database.WorkspaceACL{}
+		}
+		if workspace.GroupACL == nil {
+			workspace.GroupACL = database.WorkspaceACL{}
+		}
+
+		for id, role := range userPerms {
+			// An empty role stops sharing the workspace.
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.UserACL, id)
+				continue
+			}
+			workspace.UserACL[id] = convertSDKWorkspaceRole(role)
+		}
+		for id, role := range groupPerms {
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.GroupACL, id)
+				continue
+			}
+			workspace.GroupACL[id] = convertSDKWorkspaceRole(role)
+		}
+
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:       workspace.ID,
+			UserACL:  workspace.UserACL,
+			GroupACL: workspace.GroupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = workspace
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully updated workspace ACL list.",
+	})
+}
+
+// resolveWorkspaceACLPerms ensures every user or group exists and belongs to
+// the organization of the workspace. Users and groups may be referenced by
+// name, as members can't look up other users. The returned perms are keyed by
+// ID.
+func resolveWorkspaceACLPerms(ctx context.Context, db database.Store, workspace database.Workspace, perms map[string]codersdk.WorkspaceRole, field string, isUser bool) (map[string]codersdk.WorkspaceRole, []codersdk.ValidationError) {
+	// Validate requires full read access to users and groups
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var (
+		resolved  = make(map[string]codersdk.WorkspaceRole, len(perms))
+		validErrs []codersdk.ValidationError
+	)
+	for k, v := range perms {
+		if convertSDKWorkspaceRole(v) == nil && v != codersdk.WorkspaceRoleDeleted {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Role %q is not a valid workspace role.", v)})
+			continue
+		}
+
+		id, err := uuid.Parse(k)
+		if err == nil && v == codersdk.WorkspaceRoleDeleted {
+			// Removing an entry is always allowed, even if the user or group
+			// no longer exists.
+			resolved[id.String()] = v
+			continue
+		}
+
+		if isUser {
+			var user database.User
+			if err == nil {
+				user, err = db.GetUserByID(ctx, id)
+			} else {
+				user, err = db.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{Username: k})
+			}
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find user %q: %v", k, err.Error())})
+				continue
+			}
+			if user.ID == workspace.OwnerID {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: "The workspace owner cannot be added to its ACL."})
+				continue
+			}
+			_, err = db.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+				OrganizationID: workspace.OrganizationID,
+				UserID:         user.ID,
+			})
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("User %q is not a member of the organization of the workspace.", user.Username)})
+				continue
+			}
+			resolved[user.ID.String()] = v
+		} else {
+			var group database.Group
+			if err == nil {
+				group, err = db.GetGroupByID(ctx, id)
+			} else {
+				group, err = db.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+					OrganizationID: workspace.OrganizationID,
+					Name:           k,
+				})
+			}
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find group %q: %v", k, err.Error())})
+				continue
+			}
+			if group.OrganizationID != workspace.OrganizationID {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Group %q does not belong to the organization of the workspace.", group.Name)})
+				continue
+			}
+			resolved[group.ID.String()] = v
+		}
+	}
+
+	return resolved, validErrs
+}
+
+func convertToWorkspaceRole(actions []rbac.Action) codersdk.WorkspaceRole {
+	switch {
+	case len(actions) == 1 && actions[0] == rbac.ActionRead:
+		return codersdk.WorkspaceRoleUse
+	case len(actions) == 2 && actions[0] == rbac.ActionRead && actions[1] == rbac.ActionUpdate:
+		return codersdk.WorkspaceRoleAdmin
+	}
+
+	return ""
+}
+
+// convertSDKWorkspaceRole maps a role to the actions stored in the ACL. Both
+// roles may connect to the workspace, see database.Workspace.ExecutionRBAC.
+// The update action of admins only applies to builds, see
+// database.Workspace.WorkspaceBuildRBAC.
+func convertSDKWorkspaceRole(role codersdk.WorkspaceRole) []rbac.Action {
+	switch role {
+	case codersdk.WorkspaceRoleAdmin:
+		return []rbac.Action{rbac.ActionRead, rbac.ActionUpdate}
+	case codersdk.WorkspaceRoleUse:
+		return []rbac.Action{rbac.ActionRead}
+	}
+
+	return nil
+}
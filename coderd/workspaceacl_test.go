@@ -0,0 +1,189 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	sharedClient, shared := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	otherClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	canConnect := func(client *codersdk.Client) bool {
+		t.Helper()
+		res, err := client.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"connect": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspaceExecution,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: codersdk.ActionCreate,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return res["connect"]
+	}
+
+	// Before sharing, the workspace is invisible to other members.
+	_, err := sharedClient.Workspace(ctx, workspace.ID)
+	require.Error(t, err)
+	require.False(t, canConnect(sharedClient))
+
+	err = memberClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserPerms: map[string]codersdk.WorkspaceRole{
+			shared.ID.String(): codersdk.WorkspaceRoleUse,
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:         database.AuditActionWrite,
+		ResourceType:   database.ResourceTypeWorkspace,
+		ResourceTarget: workspace.Name,
+		UserID:         member.ID,
+	}))
+
+	acl, err := memberClient.WorkspaceACL(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, acl.Users, 1)
+	require.Equal(t, shared.ID, acl.Users[0].ID)
+	require.Equal(t, codersdk.WorkspaceRoleUse, acl.Users[0].Role)
+
+	// The shared user can use the workspace, and finds it among the
+	// workspaces shared with them.
+	_, err = sharedClient.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.True(t, canConnect(sharedClient))
+	res, err := sharedClient.Workspaces(ctx, codersdk.WorkspaceFilter{Shared: true})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, workspace.ID, res.Workspaces[0].ID)
+
+	// The owner doesn't see their own workspace as shared.
+	res, err = memberClient.Workspaces(ctx, codersdk.WorkspaceFilter{Shared: true})
+	require.NoError(t, err)
+	require.Empty(t, res.Workspaces)
+
+	// Other members still can't see the workspace.
+	_, err = otherClient.Workspace(ctx, workspace.ID)
+	require.Error(t, err)
+	require.False(t, canConnect(otherClient))
+
+	// The use role doesn't allow stopping the workspace...
+	_, err = sharedClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.Error(t, err)
+
+	// ...nor sharing it further.
+	err = sharedClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserPerms: map[string]codersdk.WorkspaceRole{
+			shared.ID.String(): codersdk.WorkspaceRoleAdmin,
+		},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// The admin role allows stopping and starting the workspace, but not
+	// deleting it.
+	err = memberClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserPerms: map[string]codersdk.WorkspaceRole{
+			shared.ID.String(): codersdk.WorkspaceRoleAdmin,
+		},
+	})
+	require.NoError(t, err)
+	build, err := sharedClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, build.ID)
+	build, err = sharedClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, build.ID)
+	_, err = sharedClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	require.Error(t, err)
+
+	// The admin role only grants builds, not changes to the workspace
+	// settings.
+	err = sharedClient.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+		Name: "renamed",
+	})
+	require.Error(t, err)
+	err = sharedClient.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{})
+	require.Error(t, err)
+	err = sharedClient.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{})
+	require.Error(t, err)
+	err = sharedClient.UpdateWorkspaceDormancy(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancy{
+		Dormant: true,
+	})
+	require.Error(t, err)
+
+	// Sharing with the Everyone group makes the workspace usable by all
+	// members of the organization.
+	err = memberClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserPerms: map[string]codersdk.WorkspaceRole{
+			shared.ID.String(): codersdk.WorkspaceRoleDeleted,
+		},
+		GroupPerms: map[string]codersdk.WorkspaceRole{
+			owner.OrganizationID.String(): codersdk.WorkspaceRoleUse,
+		},
+	})
+	require.NoError(t, err)
+	acl, err = memberClient.WorkspaceACL(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, acl.Users)
+	require.Len(t, acl.Groups, 1)
+	require.Equal(t, owner.OrganizationID, acl.Groups[0].ID)
+	require.True(t, canConnect(otherClient))
+	res, err = otherClient.Workspaces(ctx, codersdk.WorkspaceFilter{Shared: true})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := memberClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserPerms: map[string]codersdk.WorkspaceRole{
+				shared.ID.String(): "superuser",
+				member.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+}
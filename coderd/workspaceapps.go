@@ -99,7 +99,8 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 	}
 
 	// Create the application_connect-scoped API key with the same lifetime as
-	// the current session.
+	// the current session. Keys derived from an impersonation session stay
+	// flagged so they are audited and restricted the same way.
 	exp := apiKey.ExpiresAt
 	lifetimeSeconds := apiKey.LifetimeSeconds
 	if exp.IsZero() || time.Until(exp) > api.DeploymentValues.SessionDuration.Value() {
@@ -113,6 +114,9 @@ func (api *API) workspaceApplicationAuth(rw http.ResponseWriter, r *http.Request
 		ExpiresAt:       exp,
 		LifetimeSeconds: lifetimeSeconds,
 		Scope:           database.APIKeyScopeApplicationConnect,
+		ImpersonatorID:  apiKey.ImpersonatorID,
+		RemoteAddr:      r.RemoteAddr,
+		UserAgent:       r.UserAgent(),
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
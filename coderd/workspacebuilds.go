@@ -28,6 +28,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/tfstate"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -345,6 +346,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	builder := wsbuilder.New(workspace, database.WorkspaceTransition(createBuild.Transition)).
 		Initiator(apiKey.UserID).
 		RichParameterValues(createBuild.RichParameterValues).
+		TemplateVersionPresetID(createBuild.TemplateVersionPresetID).
 		LogLevel(string(createBuild.LogLevel)).
 		DeploymentValues(api.Options.DeploymentValues)
 
@@ -409,7 +411,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
 	}
 
-	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
+	// The owner of a shared workspace, or a user who built it, might not be
+	// readable by the caller, but their names are part of the build.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{
 		workspace.OwnerID,
 		workspaceBuild.InitiatorID,
 	})
@@ -625,25 +630,139 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if !api.authorizeProvisionerState(rw, r, workspace) {
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(workspaceBuild.ProvisionerState)
+}
+
+// @Summary Get provisioner state history for workspace
+// @ID get-provisioner-state-history-for-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceBuildState
+// @Router /workspaces/{workspace}/builds/states [get]
+func (api *API) workspaceBuildStates(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.authorizeProvisionerState(rw, r, workspace) {
+		return
+	}
+
+	states, err := api.Database.GetWorkspaceBuildStatesByWorkspaceID(ctx, workspace.ID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get template",
+			Message: "Internal error fetching workspace build states.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	// You must have update permissions on the template to get the state.
-	// This matches a push!
-	if !api.Authorize(r, rbac.ActionUpdate, template.RBACObject()) {
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(states, func(state database.GetWorkspaceBuildStatesByWorkspaceIDRow) codersdk.WorkspaceBuildState {
+		return codersdk.WorkspaceBuildState{
+			BuildID:     state.ID,
+			BuildNumber: state.BuildNumber,
+			Transition:  codersdk.WorkspaceTransition(state.Transition),
+			Status:      codersdk.ProvisionerJobStatus(state.JobStatus),
+			InitiatorID: state.InitiatorID,
+			CreatedAt:   state.CreatedAt,
+			Size:        state.StateSize,
+			Hash:        state.StateHash,
+		}
+	}))
+}
+
+// @Summary Compare provisioner state of workspace builds
+// @ID compare-provisioner-state-of-workspace-builds
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID"
+// @Param from query string true "Workspace build ID to compare against" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildStateDiff
+// @Router /workspacebuilds/{workspacebuild}/state/diff [get]
+func (api *API) workspaceBuildStateDiff(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+	workspace := httpmw.WorkspaceParam(r)
+
+	p := httpapi.NewQueryParamParser().RequiredNotEmpty("from")
+	vals := r.URL.Query()
+	fromBuildID := p.UUID(vals, uuid.Nil, "from")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	if !api.authorizeProvisionerState(rw, r, workspace) {
+		return
+	}
+
+	fromBuild, err := api.Database.GetWorkspaceBuildByID(ctx, fromBuildID)
+	if httpapi.Is404Error(err) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if fromBuild.WorkspaceID != workspaceBuild.WorkspaceID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Both builds must belong to the same workspace.",
+		})
+		return
+	}
 
-	rw.Header().Set("Content-Type", "application/json")
-	rw.WriteHeader(http.StatusOK)
-	_, _ = rw.Write(workspaceBuild.ProvisionerState)
+	diff, err := tfstate.Compare(fromBuild.ProvisionerState, workspaceBuild.ProvisionerState)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Provisioner state is not a valid Terraform state.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceBuildStateDiff{
+		FromBuildID: fromBuild.ID,
+		ToBuildID:   workspaceBuild.ID,
+		Added:       diff.Added,
+		Removed:     diff.Removed,
+		Changed:     diff.Changed,
+	})
+}
+
+// authorizeProvisionerState writes a not found response and returns false if
+// the user is not allowed to read the provisioner state of the workspace.
+// You must have update permissions on the template to get the state.
+// This matches a push!
+func (api *API) authorizeProvisionerState(rw http.ResponseWriter, r *http.Request, workspace database.Workspace) bool {
+	ctx := r.Context()
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get template",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, template.RBACObject()) {
+		httpapi.ResourceNotFound(rw)
+		return false
+	}
+	return true
 }
 
 type workspaceBuildsData struct {
@@ -663,7 +782,10 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 	for _, workspace := range workspaces {
 		userIDs = append(userIDs, workspace.OwnerID)
 	}
-	users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+	// Workspaces can be shared with the caller, who might not be able to
+	// read their owners. Only their names and avatars are returned.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), userIDs)
 	if err != nil {
 		return workspaceBuildsData{}, xerrors.Errorf("get users: %w", err)
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
@@ -644,6 +645,71 @@ func TestWorkspaceBuildState(t *testing.T) {
 	require.Equal(t, wantState, gotState)
 }
 
+func TestWorkspaceBuildStates(t *testing.T) {
+	t.Parallel()
+	client, store := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	first := dbfake.WorkspaceBuild(t, store, database.Workspace{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).
+		Seed(database.WorkspaceBuild{
+			BuildNumber:      1,
+			ProvisionerState: []byte(`{"resources":[{"mode":"managed","type":"docker_volume","name":"home","instances":[{"attributes":{}}]}]}`),
+		}).
+		Do()
+	second := dbfake.WorkspaceBuild(t, store, first.Workspace).
+		Seed(database.WorkspaceBuild{
+			BuildNumber:      2,
+			ProvisionerState: []byte(`{"resources":[{"mode":"managed","type":"coder_agent","name":"main","instances":[{"attributes":{}}]}]}`),
+		}).
+		Do()
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		states, err := client.WorkspaceBuildStates(ctx, first.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, states, 2)
+		require.Equal(t, second.Build.ID, states[0].BuildID)
+		require.Equal(t, first.Build.ID, states[1].BuildID)
+		require.NotEqual(t, states[0].Hash, states[1].Hash)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		diff, err := client.WorkspaceBuildStateDiff(ctx, second.Build.ID, first.Build.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"coder_agent.main"}, diff.Added)
+		require.Equal(t, []string{"docker_volume.home"}, diff.Removed)
+		require.Empty(t, diff.Changed)
+	})
+
+	t.Run("DiffOtherWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		other := dbfake.WorkspaceBuild(t, store, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        memberUser.ID,
+		}).Do()
+		_, err := client.WorkspaceBuildStateDiff(ctx, second.Build.ID, other.Build.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NoTemplateUpdatePermission", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := member.WorkspaceBuildStates(ctx, first.Workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceBuildStatus(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,86 @@
+package workspacebulk
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// Reasons for skipping a workspace that don't depend on the action.
+const (
+	SkipDeleted       = "The workspace was deleted."
+	SkipNotAuthorized = "You are not allowed to perform this action on the workspace."
+)
+
+// WorkspaceState is what an operation needs to know about a workspace to
+// decide whether to build it.
+type WorkspaceState struct {
+	Deleted           bool
+	Transition        database.WorkspaceTransition
+	JobStatus         database.ProvisionerJobStatus
+	TemplateVersionID uuid.UUID
+	ActiveVersionID   uuid.UUID
+}
+
+// InProgress reports whether the latest build of the workspace hasn't
+// finished. A new build can only be started once it has.
+func (s WorkspaceState) InProgress() bool {
+	switch s.JobStatus {
+	case database.ProvisionerJobStatusPending,
+		database.ProvisionerJobStatusRunning,
+		database.ProvisionerJobStatusCanceling:
+		return true
+	default:
+		return false
+	}
+}
+
+// SkipReason returns why the action has nothing to do for a workspace, or an
+// empty string if the workspace must be built.
+func SkipReason(action database.WorkspaceBulkAction, s WorkspaceState) string {
+	if s.Deleted {
+		return SkipDeleted
+	}
+	succeeded := s.JobStatus == database.ProvisionerJobStatusSucceeded
+	switch action {
+	case database.WorkspaceBulkActionStart:
+		if succeeded && s.Transition == database.WorkspaceTransitionStart {
+			return "The workspace is already started."
+		}
+	case database.WorkspaceBulkActionStop:
+		if succeeded && s.Transition == database.WorkspaceTransitionStop {
+			return "The workspace is already stopped."
+		}
+	case database.WorkspaceBulkActionUpdate:
+		if s.TemplateVersionID == s.ActiveVersionID {
+			return "The workspace already uses the active template version."
+		}
+	}
+	return ""
+}
+
+// Transition returns the transition of the build started by the action. An
+// update keeps the workspace in its current state, so stopped workspaces are
+// updated without being started.
+func Transition(action database.WorkspaceBulkAction, latest database.WorkspaceTransition) database.WorkspaceTransition {
+	switch action {
+	case database.WorkspaceBulkActionStart:
+		return database.WorkspaceTransitionStart
+	case database.WorkspaceBulkActionStop:
+		return database.WorkspaceTransitionStop
+	case database.WorkspaceBulkActionDelete:
+		return database.WorkspaceTransitionDelete
+	default:
+		return latest
+	}
+}
+
+// Action returns the permission required on a workspace to perform the
+// action.
+func Action(action database.WorkspaceBulkAction) rbac.Action {
+	if action == database.WorkspaceBulkActionDelete {
+		return rbac.ActionDelete
+	}
+	return rbac.ActionUpdate
+}
@@ -0,0 +1,378 @@
+// Package workspacebulk executes builds of many workspaces requested at once.
+package workspacebulk
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// DefaultConcurrency is the number of builds of an operation in progress at
+// once when the request doesn't ask for another number.
+const DefaultConcurrency = 10
+
+// MaxConcurrency is the largest number of builds of a single operation that
+// may be in progress at once.
+const MaxConcurrency = 100
+
+// RunnerOptions configures a Runner. Zero values use the defaults.
+type RunnerOptions struct {
+	// Interval is how often running operations check on their builds and
+	// start new ones. Defaults to 5 seconds.
+	Interval time.Duration
+}
+
+// Runner executes bulk operations in the background. Every operation has at
+// most its concurrency of builds in progress; the next workspaces are built
+// once earlier builds have finished.
+//
+// Builds are started on behalf of the user who started the operation, with
+// their permissions at the time of the build.
+type Runner struct {
+	opts       RunnerOptions
+	store      database.Store
+	pubsub     pubsub.Pubsub
+	authorizer rbac.Authorizer
+	log        slog.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRunner creates a Runner. Call Run to start executing operations.
+func NewRunner(store database.Store, ps pubsub.Pubsub, authorizer rbac.Authorizer, log slog.Logger, opts RunnerOptions) *Runner {
+	if opts.Interval == 0 {
+		opts.Interval = 5 * time.Second
+	}
+	return &Runner{
+		opts:       opts,
+		store:      store,
+		pubsub:     ps,
+		authorizer: authorizer,
+		log:        log.Named("workspace_bulk_runner"),
+		done:       make(chan struct{}),
+	}
+}
+
+// Run starts executing operations in the background until Close is called.
+func (r *Runner) Run(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.opts.Interval)
+		defer ticker.Stop()
+		for {
+			err := r.RunAll(ctx)
+			if err != nil && !xerrors.Is(err, context.Canceled) {
+				r.log.Error(ctx, "run workspace bulk operations", slog.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops executing operations and waits for the current run to finish.
+func (r *Runner) Close() error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+// RunAll records the outcome of finished builds and starts new builds for
+// every running operation. It is called on every tick by Run.
+func (r *Runner) RunAll(ctx context.Context) error {
+	//nolint:gocritic // The runner executes the operations of every user.
+	operations, err := r.store.GetRunningWorkspaceBulkOperations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return xerrors.Errorf("get running operations: %w", err)
+	}
+	for _, operation := range operations {
+		err = r.runOperation(ctx, operation)
+		if err != nil {
+			r.log.Error(ctx, "run workspace bulk operation", slog.F("operation_id", operation.ID), slog.Error(err))
+		}
+	}
+	return nil
+}
+
+func (r *Runner) runOperation(ctx context.Context, operation database.WorkspaceBulkOperation) error {
+	//nolint:gocritic // The roles of the initiator are needed to act on their behalf.
+	roles, err := r.store.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), operation.InitiatorID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get initiator roles: %w", err)
+	}
+	if err != nil || roles.Status != database.UserStatusActive {
+		return r.cancelOperation(ctx, operation, "The user who started the operation is no longer active.")
+	}
+	subject := rbac.Subject{
+		ID:     operation.InitiatorID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()
+	ctx = dbauthz.As(ctx, subject)
+	authFunc := func(action rbac.Action, object rbac.Objecter) bool {
+		return r.authorizer.Authorize(ctx, subject, action, object.RBACObject()) == nil
+	}
+
+	var jobs []database.ProvisionerJob
+	err = r.store.InTx(func(tx database.Store) error {
+		// Replicas run the same operations, the lock makes sure only one of
+		// them acts on an operation at a time.
+		ok, err := tx.TryAcquireLock(ctx, database.GenLockID("workspace_bulk_operation:"+operation.ID.String()))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		results, err := tx.GetWorkspaceBulkOperationWorkspaces(ctx, operation.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspaces: %w", err)
+		}
+
+		var (
+			now     = dbtime.Now()
+			running int
+			pending []database.GetWorkspaceBulkOperationWorkspacesRow
+		)
+		for _, result := range results {
+			switch result.Status {
+			case database.WorkspaceBulkResultStatusPending:
+				pending = append(pending, result)
+				continue
+			case database.WorkspaceBulkResultStatusRunning:
+			default:
+				continue
+			}
+
+			status, message := database.WorkspaceBulkResultStatusRunning, ""
+			switch {
+			case !result.JobStatus.Valid:
+				status, message = database.WorkspaceBulkResultStatusFailed, "The build no longer exists."
+			case result.JobStatus.ProvisionerJobStatus == database.ProvisionerJobStatusSucceeded:
+				status = database.WorkspaceBulkResultStatusSucceeded
+			case result.JobStatus.ProvisionerJobStatus == database.ProvisionerJobStatusFailed,
+				result.JobStatus.ProvisionerJobStatus == database.ProvisionerJobStatusCanceled:
+				status, message = database.WorkspaceBulkResultStatusFailed, result.JobError.String
+				if message == "" {
+					message = "The build was canceled."
+				}
+			default:
+				running++
+				continue
+			}
+			err = tx.UpdateWorkspaceBulkOperationWorkspace(ctx, database.UpdateWorkspaceBulkOperationWorkspaceParams{
+				OperationID: operation.ID,
+				WorkspaceID: result.WorkspaceID,
+				Status:      status,
+				BuildID:     result.BuildID,
+				Error:       message,
+				UpdatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace %s: %w", result.WorkspaceID, err)
+			}
+		}
+
+		waiting := 0
+		for _, result := range pending {
+			if running >= int(operation.Concurrency) {
+				waiting++
+				continue
+			}
+			build, job, skip, err := r.build(ctx, tx, operation, result.WorkspaceID, authFunc)
+			if err != nil {
+				return xerrors.Errorf("build workspace %s: %w", result.WorkspaceID, err)
+			}
+			if build == nil && skip == "" {
+				// The workspace has a build in progress, try again later.
+				waiting++
+				continue
+			}
+
+			update := database.UpdateWorkspaceBulkOperationWorkspaceParams{
+				OperationID: operation.ID,
+				WorkspaceID: result.WorkspaceID,
+				Status:      database.WorkspaceBulkResultStatusSkipped,
+				Error:       skip,
+				UpdatedAt:   now,
+			}
+			if build != nil {
+				update.Status = database.WorkspaceBulkResultStatusRunning
+				update.BuildID = uuid.NullUUID{UUID: build.ID, Valid: true}
+				running++
+				jobs = append(jobs, *job)
+			}
+			err = tx.UpdateWorkspaceBulkOperationWorkspace(ctx, update)
+			if err != nil {
+				return xerrors.Errorf("update workspace %s: %w", result.WorkspaceID, err)
+			}
+		}
+
+		if running > 0 || waiting > 0 {
+			return nil
+		}
+		err = tx.UpdateWorkspaceBulkOperationStatus(ctx, database.UpdateWorkspaceBulkOperationStatusParams{
+			ID:          operation.ID,
+			Status:      database.WorkspaceBulkOperationStatusCompleted,
+			UpdatedAt:   now,
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("complete operation: %w", err)
+		}
+		r.log.Info(ctx, "completed workspace bulk operation", slog.F("operation_id", operation.ID),
+			slog.F("action", operation.Action), slog.F("workspaces", len(results)))
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	// Jobs are only posted once the transaction has committed, otherwise a
+	// provisioner could try to acquire a job it can't see yet.
+	for _, job := range jobs {
+		err = provisionerjobs.PostJob(r.pubsub, job)
+		if err != nil {
+			r.log.Warn(ctx, "post provisioner job to pubsub", slog.F("job_id", job.ID), slog.Error(err))
+		}
+	}
+	return nil
+}
+
+// build starts the build of a workspace for an operation. If the workspace is
+// skipped, the reason is returned instead. If neither a build nor a reason is
+// returned, the workspace has a build in progress and must be tried again.
+func (r *Runner) build(ctx context.Context, tx database.Store, operation database.WorkspaceBulkOperation, workspaceID uuid.UUID, authFunc func(rbac.Action, rbac.Objecter) bool) (*database.WorkspaceBuild, *database.ProvisionerJob, string, error) {
+	workspace, err := tx.GetWorkspaceByID(ctx, workspaceID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		return nil, nil, SkipNotAuthorized, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, SkipDeleted, nil
+	}
+	if err != nil {
+		return nil, nil, "", xerrors.Errorf("get workspace: %w", err)
+	}
+	latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		return nil, nil, "", xerrors.Errorf("get latest build: %w", err)
+	}
+	latestJob, err := tx.GetProvisionerJobByID(ctx, latestBuild.JobID)
+	if err != nil {
+		return nil, nil, "", xerrors.Errorf("get latest job: %w", err)
+	}
+	activeVersion, err := tx.GetTemplateActiveVersionForUser(ctx, database.GetTemplateActiveVersionForUserParams{
+		UserID:     workspace.OwnerID,
+		TemplateID: workspace.TemplateID,
+	})
+	if err != nil {
+		return nil, nil, "", xerrors.Errorf("get active template version: %w", err)
+	}
+
+	state := WorkspaceState{
+		Deleted:           workspace.Deleted,
+		Transition:        latestBuild.Transition,
+		JobStatus:         latestJob.JobStatus,
+		TemplateVersionID: latestBuild.TemplateVersionID,
+		ActiveVersionID:   activeVersion.ActiveVersionID,
+	}
+	if skip := SkipReason(operation.Action, state); skip != "" {
+		return nil, nil, skip, nil
+	}
+	if state.InProgress() {
+		return nil, nil, "", nil
+	}
+	if !authFunc(Action(operation.Action), workspace) {
+		return nil, nil, SkipNotAuthorized, nil
+	}
+
+	builder := wsbuilder.New(workspace, Transition(operation.Action, latestBuild.Transition)).
+		Initiator(operation.InitiatorID).
+		SetLastWorkspaceBuildInTx(&latestBuild).
+		SetLastWorkspaceBuildJobInTx(&latestJob)
+	if operation.Action == database.WorkspaceBulkActionUpdate {
+		builder = builder.ActiveVersion()
+	}
+	build, job, err := builder.Build(ctx, tx, authFunc, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) && buildErr.Status < http.StatusInternalServerError {
+		// The workspace can't be built as requested, for example because
+		// the parameters of a new template version need values.
+		return nil, nil, buildErr.Message, nil
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+	r.log.Debug(ctx, "building workspace", slog.F("operation_id", operation.ID),
+		slog.F("workspace_id", workspace.ID), slog.F("build_id", build.ID))
+	return build, job, "", nil
+}
+
+// cancelOperation skips the workspaces that weren't built yet and stops the
+// operation.
+func (r *Runner) cancelOperation(ctx context.Context, operation database.WorkspaceBulkOperation, reason string) error {
+	//nolint:gocritic // The initiator can no longer act on the operation.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	return r.store.InTx(func(tx database.Store) error {
+		results, err := tx.GetWorkspaceBulkOperationWorkspaces(ctx, operation.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspaces: %w", err)
+		}
+		now := dbtime.Now()
+		for _, result := range results {
+			if result.Status != database.WorkspaceBulkResultStatusPending {
+				continue
+			}
+			err = tx.UpdateWorkspaceBulkOperationWorkspace(ctx, database.UpdateWorkspaceBulkOperationWorkspaceParams{
+				OperationID: operation.ID,
+				WorkspaceID: result.WorkspaceID,
+				Status:      database.WorkspaceBulkResultStatusSkipped,
+				Error:       reason,
+				UpdatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace %s: %w", result.WorkspaceID, err)
+			}
+		}
+		err = tx.UpdateWorkspaceBulkOperationStatus(ctx, database.UpdateWorkspaceBulkOperationStatusParams{
+			ID:          operation.ID,
+			Status:      database.WorkspaceBulkOperationStatusCanceled,
+			UpdatedAt:   now,
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("cancel operation: %w", err)
+		}
+		r.log.Info(ctx, "canceled workspace bulk operation", slog.F("operation_id", operation.ID), slog.F("reason", reason))
+		return nil
+	}, nil)
+}
@@ -0,0 +1,148 @@
+package workspacebulk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspacebulk"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		// The test runs the operations by itself.
+		WorkspaceBulkOperationInterval: time.Hour,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, other := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, suspended := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	_, err := client.UpdateUserStatus(testutil.Context(t, testutil.WaitShort), suspended.ID.String(), codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+	// The workspace is running, nothing builds it in this test.
+	workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OwnerID:        member.ID,
+		OrganizationID: owner.OrganizationID,
+	}).Do().Workspace
+
+	runner := workspacebulk.NewRunner(db, nil, rbac.NewAuthorizer(prometheus.NewRegistry()), slogtest.Make(t, nil), workspacebulk.RunnerOptions{})
+
+	run := func(t *testing.T, initiatorID uuid.UUID, action database.WorkspaceBulkAction) (database.WorkspaceBulkOperation, database.GetWorkspaceBulkOperationWorkspacesRow) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:gocritic // The test inspects the operation directly.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		operation := dbgen.WorkspaceBulkOperation(t, db, database.WorkspaceBulkOperation{
+			InitiatorID: initiatorID,
+			Action:      action,
+		})
+		err := db.InsertWorkspaceBulkOperationWorkspaces(sysCtx, database.InsertWorkspaceBulkOperationWorkspacesParams{
+			OperationID:  operation.ID,
+			WorkspaceIds: []uuid.UUID{workspace.ID},
+			UpdatedAt:    dbtime.Now(),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, runner.RunAll(ctx))
+
+		operation, err = db.GetWorkspaceBulkOperationByID(sysCtx, operation.ID)
+		require.NoError(t, err)
+		results, err := db.GetWorkspaceBulkOperationWorkspaces(sysCtx, operation.ID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return operation, results[0]
+	}
+
+	t.Run("NothingToDo", func(t *testing.T) {
+		t.Parallel()
+
+		operation, result := run(t, member.ID, database.WorkspaceBulkActionStart)
+		require.Equal(t, database.WorkspaceBulkOperationStatusCompleted, operation.Status)
+		require.True(t, operation.CompletedAt.Valid)
+		require.Equal(t, database.WorkspaceBulkResultStatusSkipped, result.Status)
+		require.Equal(t, "The workspace is already started.", result.Error)
+	})
+
+	t.Run("NotAuthorized", func(t *testing.T) {
+		t.Parallel()
+
+		operation, result := run(t, other.ID, database.WorkspaceBulkActionStop)
+		require.Equal(t, database.WorkspaceBulkOperationStatusCompleted, operation.Status)
+		require.Equal(t, database.WorkspaceBulkResultStatusSkipped, result.Status)
+		require.Equal(t, workspacebulk.SkipNotAuthorized, result.Error)
+	})
+
+	t.Run("InitiatorSuspended", func(t *testing.T) {
+		t.Parallel()
+
+		operation, result := run(t, suspended.ID, database.WorkspaceBulkActionStop)
+		require.Equal(t, database.WorkspaceBulkOperationStatusCanceled, operation.Status)
+		require.True(t, operation.CompletedAt.Valid)
+		require.Equal(t, database.WorkspaceBulkResultStatusSkipped, result.Status)
+		require.False(t, result.BuildID.Valid)
+	})
+}
+
+func TestSkipReason(t *testing.T) {
+	t.Parallel()
+
+	active := uuid.New()
+	started := workspacebulk.WorkspaceState{
+		Transition:        database.WorkspaceTransitionStart,
+		JobStatus:         database.ProvisionerJobStatusSucceeded,
+		TemplateVersionID: active,
+		ActiveVersionID:   active,
+	}
+	stopped := started
+	stopped.Transition = database.WorkspaceTransitionStop
+	failed := started
+	failed.JobStatus = database.ProvisionerJobStatusFailed
+	outdated := stopped
+	outdated.TemplateVersionID = uuid.New()
+	deleted := started
+	deleted.Deleted = true
+
+	for _, tc := range []struct {
+		name   string
+		action database.WorkspaceBulkAction
+		state  workspacebulk.WorkspaceState
+		skip   bool
+	}{
+		{"StartStarted", database.WorkspaceBulkActionStart, started, true},
+		{"StartStopped", database.WorkspaceBulkActionStart, stopped, false},
+		// A failed start is retried.
+		{"StartFailed", database.WorkspaceBulkActionStart, failed, false},
+		{"StopStarted", database.WorkspaceBulkActionStop, started, false},
+		{"StopStopped", database.WorkspaceBulkActionStop, stopped, true},
+		{"UpdateCurrent", database.WorkspaceBulkActionUpdate, started, true},
+		{"UpdateOutdated", database.WorkspaceBulkActionUpdate, outdated, false},
+		{"DeleteStarted", database.WorkspaceBulkActionDelete, started, false},
+		{"DeleteDeleted", database.WorkspaceBulkActionDelete, deleted, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.skip, workspacebulk.SkipReason(tc.action, tc.state) != "")
+		})
+	}
+
+	// Updating keeps stopped workspaces stopped.
+	require.Equal(t, database.WorkspaceTransitionStop, workspacebulk.Transition(database.WorkspaceBulkActionUpdate, database.WorkspaceTransitionStop))
+	require.Equal(t, rbac.ActionDelete, workspacebulk.Action(database.WorkspaceBulkActionDelete))
+}
@@ -0,0 +1,252 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/searchquery"
+	"github.com/coder/coder/v2/coderd/workspacebulk"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Create workspace bulk operation
+// @Description Builds every workspace matching the query. At most the
+// @Description concurrency of builds are in progress at once. A dry run
+// @Description returns the workspaces that would be built without building them.
+// @ID create-workspace-bulk-operation
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param request body codersdk.CreateWorkspaceBulkOperationRequest true "Create workspace bulk operation request"
+// @Success 201 {object} codersdk.WorkspaceBulkOperation
+// @Router /workspaces/bulk [post]
+func (api *API) postWorkspaceBulkOperation(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	var req codersdk.CreateWorkspaceBulkOperationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Concurrency == 0 {
+		req.Concurrency = workspacebulk.DefaultConcurrency
+	}
+	action := database.WorkspaceBulkAction(req.Action)
+
+	filter, errs := searchquery.Workspaces(ctx, api.Database, req.Query, codersdk.Pagination{}, api.AgentInactiveDisconnectTimeout)
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace search query.",
+			Validations: errs,
+		})
+		return
+	}
+	if filter.OwnerUsername == "me" {
+		filter.OwnerID = apiKey.UserID
+		filter.OwnerUsername = ""
+	}
+
+	prepared, err := api.HTTPAuth.AuthorizeSQLFilter(r, rbac.ActionRead, rbac.ResourceWorkspace.Type)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error preparing sql filter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rows, err := api.Database.GetAuthorizedWorkspaces(ctx, filter, prepared)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(rows) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No workspaces match the query.",
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	if req.DryRun {
+		workspaceIDs := make([]uuid.UUID, 0, len(rows))
+		for _, row := range rows {
+			workspaceIDs = append(workspaceIDs, row.ID)
+		}
+		// The active version of a workspace depends on the release channel
+		// of its owner. The workspaces are already authorized.
+		// nolint:gocritic
+		activeVersionRows, err := api.Database.GetTemplateActiveVersionsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, xerrors.Errorf("get active template versions: %w", err))
+			return
+		}
+		activeVersions := make(map[uuid.UUID]uuid.UUID, len(activeVersionRows))
+		for _, row := range activeVersionRows {
+			activeVersions[row.WorkspaceID] = row.ActiveVersionID
+		}
+
+		workspaces := make([]codersdk.WorkspaceBulkOperationWorkspace, 0, len(rows))
+		for i, workspace := range database.ConvertWorkspaceRows(rows) {
+			row := rows[i]
+
+			result := codersdk.WorkspaceBulkOperationWorkspace{
+				WorkspaceID:   workspace.ID,
+				WorkspaceName: workspace.Name,
+				OwnerName:     row.Username,
+				Status:        codersdk.WorkspaceBulkResultStatusPending,
+				UpdatedAt:     now,
+			}
+			result.Error = workspacebulk.SkipReason(action, workspacebulk.WorkspaceState{
+				Deleted:           workspace.Deleted,
+				Transition:        row.LatestBuildTransition,
+				JobStatus:         row.LatestBuildStatus,
+				TemplateVersionID: row.TemplateVersionID,
+				ActiveVersionID:   activeVersions[workspace.ID],
+			})
+			if result.Error == "" && !api.Authorize(r, workspacebulk.Action(action), workspace) {
+				result.Error = workspacebulk.SkipNotAuthorized
+			}
+			if result.Error != "" {
+				result.Status = codersdk.WorkspaceBulkResultStatusSkipped
+			}
+			workspaces = append(workspaces, result)
+		}
+
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceBulkOperation{
+			InitiatorID: apiKey.UserID,
+			Action:      req.Action,
+			Query:       req.Query,
+			Concurrency: req.Concurrency,
+			Status:      codersdk.WorkspaceBulkOperationStatusDryRun,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Workspaces:  workspaces,
+		})
+		return
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		workspaceIDs = append(workspaceIDs, row.ID)
+	}
+	var (
+		operation database.WorkspaceBulkOperation
+		results   []database.GetWorkspaceBulkOperationWorkspacesRow
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		operation, err = tx.InsertWorkspaceBulkOperation(ctx, database.InsertWorkspaceBulkOperationParams{
+			ID:          uuid.New(),
+			InitiatorID: apiKey.UserID,
+			Action:      action,
+			Query:       req.Query,
+			Concurrency: req.Concurrency,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert operation: %w", err)
+		}
+		err = tx.InsertWorkspaceBulkOperationWorkspaces(ctx, database.InsertWorkspaceBulkOperationWorkspacesParams{
+			OperationID:  operation.ID,
+			WorkspaceIds: workspaceIDs,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert operation workspaces: %w", err)
+		}
+		results, err = tx.GetWorkspaceBulkOperationWorkspaces(ctx, operation.ID)
+		if err != nil {
+			return xerrors.Errorf("get operation workspaces: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	api.Logger.Info(ctx, "created workspace bulk operation", slog.F("operation_id", operation.ID),
+		slog.F("action", operation.Action), slog.F("workspaces", len(workspaceIDs)))
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceBulkOperation(operation, results))
+}
+
+// @Summary Get workspace bulk operation
+// @ID get-workspace-bulk-operation
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param bulkoperation path string true "Bulk operation ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBulkOperation
+// @Router /workspaces/bulk/{bulkoperation} [get]
+func (api *API) workspaceBulkOperation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := httpmw.ParseUUIDParam(rw, r, "bulkoperation")
+	if !ok {
+		return
+	}
+
+	operation, err := api.Database.GetWorkspaceBulkOperationByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	results, err := api.Database.GetWorkspaceBulkOperationWorkspaces(ctx, operation.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceBulkOperation(operation, results))
+}
+
+func convertWorkspaceBulkOperation(operation database.WorkspaceBulkOperation, results []database.GetWorkspaceBulkOperationWorkspacesRow) codersdk.WorkspaceBulkOperation {
+	sdk := codersdk.WorkspaceBulkOperation{
+		ID:          operation.ID,
+		InitiatorID: operation.InitiatorID,
+		Action:      codersdk.WorkspaceBulkAction(operation.Action),
+		Query:       operation.Query,
+		Concurrency: operation.Concurrency,
+		Status:      codersdk.WorkspaceBulkOperationStatus(operation.Status),
+		CreatedAt:   operation.CreatedAt,
+		UpdatedAt:   operation.UpdatedAt,
+		Workspaces:  make([]codersdk.WorkspaceBulkOperationWorkspace, 0, len(results)),
+	}
+	if operation.CompletedAt.Valid {
+		sdk.CompletedAt = &operation.CompletedAt.Time
+	}
+	for _, result := range results {
+		ws := codersdk.WorkspaceBulkOperationWorkspace{
+			WorkspaceID:   result.WorkspaceID,
+			WorkspaceName: result.WorkspaceName,
+			OwnerName:     result.OwnerName,
+			Status:        codersdk.WorkspaceBulkResultStatus(result.Status),
+			Error:         result.Error,
+			UpdatedAt:     result.UpdatedAt,
+		}
+		if result.BuildID.Valid {
+			buildID := result.BuildID.UUID
+			ws.BuildID = &buildID
+		}
+		sdk.Workspaces = append(sdk.Workspaces, ws)
+	}
+	return sdk
+}
@@ -0,0 +1,149 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceBulkOperation(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon:       true,
+		WorkspaceBulkOperationInterval: testutil.IntervalFast,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	workspaces := make([]codersdk.Workspace, 0, 3)
+	for i := 0; i < 3; i++ {
+		workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+		workspaces = append(workspaces, workspace)
+	}
+	// Stopped workspaces are skipped by a stop.
+	build := coderdtest.CreateWorkspaceBuild(t, memberClient, workspaces[2], database.WorkspaceTransitionStop)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, build.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	query := "template:" + template.Name
+
+	// A dry run doesn't build anything.
+	preview, err := client.CreateWorkspaceBulkOperation(ctx, codersdk.CreateWorkspaceBulkOperationRequest{
+		Action: codersdk.WorkspaceBulkActionStop,
+		Query:  query,
+		DryRun: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceBulkOperationStatusDryRun, preview.Status)
+	require.Len(t, preview.Workspaces, 3)
+	counts := preview.Counts()
+	require.Equal(t, 2, counts[codersdk.WorkspaceBulkResultStatusPending])
+	require.Equal(t, 1, counts[codersdk.WorkspaceBulkResultStatusSkipped])
+	for _, workspace := range workspaces[:2] {
+		workspace, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	}
+
+	operation, err := client.CreateWorkspaceBulkOperation(ctx, codersdk.CreateWorkspaceBulkOperationRequest{
+		Action:      codersdk.WorkspaceBulkActionStop,
+		Query:       query,
+		Concurrency: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceBulkOperationStatusRunning, operation.Status)
+	require.Len(t, operation.Workspaces, 3)
+
+	require.Eventually(t, func() bool {
+		operation, err = client.WorkspaceBulkOperation(ctx, operation.ID)
+		if err != nil {
+			return false
+		}
+		// Never more builds than the concurrency are in progress.
+		require.LessOrEqual(t, operation.Counts()[codersdk.WorkspaceBulkResultStatusRunning], 1)
+		return operation.Status.Done()
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, codersdk.WorkspaceBulkOperationStatusCompleted, operation.Status)
+	require.NotNil(t, operation.CompletedAt)
+	counts = operation.Counts()
+	require.Equal(t, 2, counts[codersdk.WorkspaceBulkResultStatusSucceeded])
+	require.Equal(t, 1, counts[codersdk.WorkspaceBulkResultStatusSkipped])
+	for _, result := range operation.Workspaces {
+		workspace, err := client.Workspace(ctx, result.WorkspaceID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+		if result.Status == codersdk.WorkspaceBulkResultStatusSucceeded {
+			require.NotNil(t, result.BuildID)
+			require.Equal(t, *result.BuildID, workspace.LatestBuild.ID)
+		}
+	}
+
+	// Updating moves the workspaces to the active version and leaves them
+	// stopped.
+	version2 := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+	err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: version2.ID})
+	require.NoError(t, err)
+	operation, err = client.CreateWorkspaceBulkOperation(ctx, codersdk.CreateWorkspaceBulkOperationRequest{
+		Action: codersdk.WorkspaceBulkActionUpdate,
+		Query:  query,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		operation, err = client.WorkspaceBulkOperation(ctx, operation.ID)
+		return err == nil && operation.Status.Done()
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, 3, operation.Counts()[codersdk.WorkspaceBulkResultStatusSucceeded])
+	for _, result := range operation.Workspaces {
+		workspace, err := client.Workspace(ctx, result.WorkspaceID)
+		require.NoError(t, err)
+		require.Equal(t, version2.ID, workspace.LatestBuild.TemplateVersionID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+	}
+
+	t.Run("OtherUsersOperation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.WorkspaceBulkOperation(ctx, operation.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("NoMatches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.CreateWorkspaceBulkOperation(ctx, codersdk.CreateWorkspaceBulkOperationRequest{
+			Action: codersdk.WorkspaceBulkActionStart,
+			Query:  "name:does-not-exist",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidAction", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.CreateWorkspaceBulkOperation(ctx, codersdk.CreateWorkspaceBulkOperationRequest{
+			Action: "restart",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
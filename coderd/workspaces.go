@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -108,6 +109,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersion(workspace, data.templates[0]),
 		owner.Username,
 		owner.AvatarURL,
 		api.Options.AllowWorkspaceRenames,
@@ -145,7 +147,7 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	queryStr := r.URL.Query().Get("q")
-	filter, errs := searchquery.Workspaces(queryStr, page, api.AgentInactiveDisconnectTimeout)
+	filter, errs := searchquery.Workspaces(ctx, api.Database, queryStr, page, api.AgentInactiveDisconnectTimeout)
 	if len(errs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid workspace search query.",
@@ -159,7 +161,7 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		filter.OwnerUsername = ""
 	}
 
-	// Workspaces do not have ACL columns.
+	// Workspaces shared with the user are authorized through their ACL columns.
 	prepared, err := api.HTTPAuth.AuthorizeSQLFilter(r, rbac.ActionRead, rbac.ResourceWorkspace.Type)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -315,6 +317,7 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersion(workspace, data.templates[0]),
 		owner.Username,
 		owner.AvatarURL,
 		api.Options.AllowWorkspaceRenames,
@@ -466,6 +469,10 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !api.checkWorkspaceLimits(ctx, rw, template, member.UserID) {
+		return
+	}
+
 	dbAutostartSchedule, err := validWorkspaceSchedule(createWorkspace.AutostartSchedule)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -532,37 +539,105 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	var snapshot database.WorkspaceSnapshot
+	if createWorkspace.SnapshotID != uuid.Nil {
+		var ok bool
+		snapshot, ok = api.restorableWorkspaceSnapshot(ctx, rw, createWorkspace.SnapshotID, template.ID)
+		if !ok {
+			return
+		}
+	}
+
+	// The workspace is built from the version of the release channel its
+	// owner is assigned to, if any.
+	activeVersion, err := api.Database.GetTemplateActiveVersionForUser(ctx, database.GetTemplateActiveVersionForUserParams{
+		UserID:     member.UserID,
+		TemplateID: template.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	claimPrebuild, err := canClaimPrebuild(ctx, api.Database, activeVersion.ActiveVersionID, createWorkspace)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	var (
 		provisionerJob *database.ProvisionerJob
 		workspaceBuild *database.WorkspaceBuild
 	)
 	err = api.Database.InTx(func(db database.Store) error {
 		now := dbtime.Now()
-		// Workspaces are created without any versions.
-		workspace, err = db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
-			ID:                uuid.New(),
-			CreatedAt:         now,
-			UpdatedAt:         now,
-			OwnerID:           member.UserID,
-			OrganizationID:    template.OrganizationID,
-			TemplateID:        template.ID,
-			Name:              createWorkspace.Name,
-			AutostartSchedule: dbAutostartSchedule,
-			Ttl:               dbTTL,
-			// The workspaces page will sort by last used at, and it's useful to
-			// have the newly created workspace at the top of the list!
-			LastUsedAt:       dbtime.Now(),
-			AutomaticUpdates: dbAU,
-		})
-		if err != nil {
-			return xerrors.Errorf("insert workspace: %w", err)
+		if claimPrebuild {
+			// A prebuilt workspace is already running, take it over. The
+			// build below applies the identity of the new owner to it.
+			workspace, err = db.ClaimWorkspacePrebuild(ctx, database.ClaimWorkspacePrebuildParams{
+				TemplateVersionPresetID: createWorkspace.TemplateVersionPresetID,
+				OrganizationID:          template.OrganizationID,
+				OwnerID:                 member.UserID,
+				Name:                    createWorkspace.Name,
+				AutostartSchedule:       dbAutostartSchedule,
+				Ttl:                     dbTTL,
+				AutomaticUpdates:        dbAU,
+				Now:                     now,
+			})
+			if err == nil {
+				api.Logger.Info(ctx, "claimed prebuilt workspace", slog.F("workspace_id", workspace.ID),
+					slog.F("preset_id", createWorkspace.TemplateVersionPresetID), slog.F("owner_id", member.UserID))
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("claim prebuild: %w", err)
+			}
+		}
+		if !claimPrebuild || errors.Is(err, sql.ErrNoRows) {
+			// Workspaces are created without any versions.
+			workspace, err = db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+				ID:                uuid.New(),
+				CreatedAt:         now,
+				UpdatedAt:         now,
+				OwnerID:           member.UserID,
+				OrganizationID:    template.OrganizationID,
+				TemplateID:        template.ID,
+				Name:              createWorkspace.Name,
+				AutostartSchedule: dbAutostartSchedule,
+				Ttl:               dbTTL,
+				// The workspaces page will sort by last used at, and it's useful to
+				// have the newly created workspace at the top of the list!
+				LastUsedAt:       dbtime.Now(),
+				AutomaticUpdates: dbAU,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace: %w", err)
+			}
+		}
+		if createWorkspace.SnapshotID != uuid.Nil {
+			// The artifacts are copied so the workspace keeps restoring them
+			// when the snapshot is deleted.
+			_, err = db.InsertWorkspaceSnapshotRestore(ctx, database.InsertWorkspaceSnapshotRestoreParams{
+				WorkspaceID: workspace.ID,
+				SnapshotID:  uuid.NullUUID{UUID: snapshot.ID, Valid: true},
+				Artifacts:   snapshot.Artifacts,
+				CreatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace snapshot restore: %w", err)
+			}
 		}
 
 		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
 			Reason(database.BuildReasonInitiator).
 			Initiator(apiKey.UserID).
 			ActiveVersion().
-			RichParameterValues(createWorkspace.RichParameterValues)
+			RichParameterValues(createWorkspace.RichParameterValues).
+			TemplateVersionPresetID(createWorkspace.TemplateVersionPresetID)
 		if createWorkspace.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(createWorkspace.TemplateVersionID)
 		}
@@ -599,6 +674,14 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	}
 	aReq.New = workspace
 
+	api.WebhookPublisher.Publish(ctx, codersdk.WebhookEventWorkspaceCreated, codersdk.WebhookWorkspaceData{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		OwnerID:       workspace.OwnerID,
+		OwnerName:     member.Username,
+		TemplateID:    workspace.TemplateID,
+	})
+
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Workspaces:      []telemetry.Workspace{telemetry.ConvertWorkspace(workspace)},
 		WorkspaceBuilds: []telemetry.WorkspaceBuild{telemetry.ConvertWorkspaceBuild(*workspaceBuild)},
@@ -634,6 +717,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		workspace,
 		apiBuild,
 		template,
+		activeVersion,
 		member.Username,
 		member.AvatarURL,
 		api.Options.AllowWorkspaceRenames,
@@ -648,6 +732,137 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusCreated, w)
 }
 
+// checkWorkspaceLimits writes an error and returns false if the owner can't
+// create another workspace from the template, because the template or the
+// owner has reached their workspace limit. Like quotas, the limits of owners
+// are given by their groups in the organization of the template and only
+// enforced when a quota committer is registered.
+func (api *API) checkWorkspaceLimits(ctx context.Context, rw http.ResponseWriter, template database.Template, ownerID uuid.UUID) bool {
+	if template.MaxWorkspaces > 0 {
+		count, err := api.Database.GetWorkspaceCountForTemplate(ctx, template.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template workspace count.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		if count >= int64(template.MaxWorkspaces) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Template %q has reached its limit of %d workspaces.", template.Name, template.MaxWorkspaces),
+				Detail:  "Delete workspaces of the template, or ask a template admin to raise the limit.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "template_id",
+					Detail: "The template has reached its workspace limit.",
+				}},
+			})
+			return false
+		}
+	}
+	return api.checkUserWorkspaceLimit(ctx, rw, template.OrganizationID, ownerID)
+}
+
+// checkUserWorkspaceLimit writes an error and returns false if the owner
+// can't own another workspace in the organization.
+func (api *API) checkUserWorkspaceLimit(ctx context.Context, rw http.ResponseWriter, organizationID uuid.UUID, ownerID uuid.UUID) bool {
+	if api.QuotaCommitter.Load() == nil {
+		return true
+	}
+	limit, err := api.Database.GetWorkspaceLimitForUser(ctx, database.GetWorkspaceLimitForUserParams{
+		UserID:         ownerID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace limit.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if limit <= 0 {
+		return true
+	}
+	count, err := api.Database.GetWorkspaceCountForUser(ctx, database.GetWorkspaceCountForUserParams{
+		OwnerID:        ownerID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace count.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if count >= int64(limit) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The owner of the workspace has reached their limit of %d workspaces.", limit),
+			Detail:  "Delete unused workspaces, or ask an administrator to raise the limit of your groups.",
+		})
+		return false
+	}
+	return true
+}
+
+// canClaimPrebuild reports whether a workspace created by the request may
+// take over a prebuilt workspace of the requested preset. Prebuilds are only
+// built from the active template version, which must be the active version
+// for the owner of the workspace, and the parameters given in the request must
+// not change any immutable parameter the prebuild was built with. Workspaces
+// restored from a snapshot are always built from scratch.
+func canClaimPrebuild(ctx context.Context, db database.Store, activeVersionID uuid.UUID, req codersdk.CreateWorkspaceRequest) (bool, error) {
+	if req.TemplateVersionPresetID == uuid.Nil || req.SnapshotID != uuid.Nil {
+		return false, nil
+	}
+	if req.TemplateVersionID != uuid.Nil && req.TemplateVersionID != activeVersionID {
+		return false, nil
+	}
+	preset, err := db.GetTemplateVersionPresetByID(ctx, req.TemplateVersionPresetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The build fails with a helpful message.
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get preset: %w", err)
+	}
+	if preset.DesiredPrebuildInstances <= 0 || preset.TemplateVersionID != activeVersionID {
+		return false, nil
+	}
+	if len(req.RichParameterValues) == 0 {
+		return true, nil
+	}
+
+	templateVersionParameters, err := db.GetTemplateVersionParameters(ctx, preset.TemplateVersionID)
+	if err != nil {
+		return false, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	presetParameters, err := db.GetTemplateVersionPresetParametersByPresetID(ctx, preset.ID)
+	if err != nil {
+		return false, xerrors.Errorf("get preset parameters: %w", err)
+	}
+	for _, value := range req.RichParameterValues {
+		i := slices.IndexFunc(templateVersionParameters, func(p database.TemplateVersionParameter) bool {
+			return p.Name == value.Name
+		})
+		if i < 0 {
+			return false, nil
+		}
+		parameter := templateVersionParameters[i]
+		if parameter.Mutable {
+			continue
+		}
+		built := parameter.DefaultValue
+		for _, presetParameter := range presetParameters {
+			if presetParameter.Name == value.Name {
+				built = presetParameter.Value
+			}
+		}
+		if built != value.Value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // @Summary Update workspace metadata by ID
 // @ID update-workspace-metadata-by-id
 // @Security CoderSessionToken
@@ -983,6 +1198,7 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		workspace,
 		data.builds[0],
 		data.templates[0],
+		data.activeVersion(workspace, data.templates[0]),
 		owner.Username,
 		owner.AvatarURL,
 		api.Options.AllowWorkspaceRenames,
@@ -1261,6 +1477,309 @@ func (api *API) putWorkspaceAutoupdates(rw http.ResponseWriter, r *http.Request)
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Transfer workspace ownership
+// @ID transfer-workspace-ownership
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.TransferWorkspaceOwnershipRequest true "Transfer workspace ownership request"
+// @Success 200 {object} codersdk.Workspace
+// @Router /workspaces/{workspace}/owner [put]
+func (api *API) putWorkspaceOwner(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	// Transferring a workspace deletes it for its owner and creates it for
+	// another user, which only administrators of the organization may do.
+	if !api.Authorize(r, rbac.ActionDelete, workspace) ||
+		!api.Authorize(r, rbac.ActionCreate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.TransferWorkspaceOwnershipRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if workspace.Deleted {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Deleted workspaces cannot be transferred.",
+		})
+		return
+	}
+
+	var (
+		newOwner database.User
+		err      error
+	)
+	if id, parseErr := uuid.Parse(req.Owner); parseErr == nil {
+		newOwner, err = api.Database.GetUserByID(ctx, id)
+	} else {
+		newOwner, err = api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: req.Owner,
+		})
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     fmt.Sprintf("User %q does not exist.", req.Owner),
+			Validations: []codersdk.ValidationError{{Field: "owner", Detail: "must be an existing user ID or username"}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if newOwner.ID == workspace.OwnerID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q already owns the workspace.", newOwner.Username),
+		})
+		return
+	}
+	if newOwner.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is suspended.", newOwner.Username),
+		})
+		return
+	}
+	_, err = api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: workspace.OrganizationID,
+		UserID:         newOwner.ID,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is not a member of the organization of the workspace.", newOwner.Username),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The new owner must be able to use the workspace's template, as
+	// anyone creating the workspace themselves would.
+	// nolint:gocritic // System needs to be able to get the new owner's roles.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), newOwner.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	newOwnerSubject := rbac.Subject{
+		ID:     newOwner.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err := api.Authorizer.Authorize(ctx, newOwnerSubject, rbac.ActionRead, template.RBACObject()); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q cannot use the template %q.", newOwner.Username, template.Name),
+		})
+		return
+	}
+
+	latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Quotas are only enforced when a committer is registered. The cost of
+	// the workspace moves to the new owner, who must be able to afford it.
+	if api.QuotaCommitter.Load() != nil && latestBuild.DailyCost > 0 {
+		consumed, err := api.Database.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+			OwnerID:        newOwner.ID,
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching quota.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		allowance, err := api.Database.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+			UserID:         newOwner.ID,
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching quota.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if consumed+int64(latestBuild.DailyCost) > allowance {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("User %q does not have enough quota for the workspace.", newOwner.Username),
+				Detail:  fmt.Sprintf("The workspace costs %d, and the user has consumed %d of %d.", latestBuild.DailyCost, consumed, allowance),
+			})
+			return
+		}
+	}
+	// The workspace counts towards the limit of the new owner even while it
+	// is stopped. The template limit is not affected by a transfer.
+	if !api.checkUserWorkspaceLimit(ctx, rw, workspace.OrganizationID, newOwner.ID) {
+		return
+	}
+
+	var (
+		workspaceBuild *database.WorkspaceBuild
+		provisionerJob *database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.UpdateWorkspaceOwnerByID(ctx, database.UpdateWorkspaceOwnerByIDParams{
+			ID:        workspace.ID,
+			OwnerID:   newOwner.ID,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace owner: %w", err)
+		}
+		// The new owner no longer needs the workspace shared with them.
+		if _, ok := workspace.UserACL[newOwner.ID.String()]; ok {
+			// Copy the ACL so the audit log keeps the old one.
+			userACL := make(database.WorkspaceACL, len(workspace.UserACL))
+			for id, actions := range workspace.UserACL {
+				if id != newOwner.ID.String() {
+					userACL[id] = actions
+				}
+			}
+			err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+				ID:       workspace.ID,
+				UserACL:  userACL,
+				GroupACL: workspace.GroupACL,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace ACL: %w", err)
+			}
+		}
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+
+		// Rebuild the workspace in its current state, so the resources
+		// pick up the identity of the new owner.
+		builder := wsbuilder.New(workspace, latestBuild.Transition).
+			Initiator(apiKey.UserID).
+			DeploymentValues(api.Options.DeploymentValues)
+		workspaceBuild, provisionerJob, err = builder.Build(
+			ctx,
+			tx,
+			func(action rbac.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		return err
+	}, nil)
+	// Check if the new owner already has a workspace with the same name.
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("User %q already has a workspace named %q.", newOwner.Username, workspace.Name),
+			Detail:  "Rename the workspace before transferring it.",
+		})
+		return
+	}
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error transferring workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = workspace
+
+	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(data.builds) == 0 || len(data.templates) == 0 {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  fmt.Sprintf("build %s or template not found", workspaceBuild.ID),
+		})
+		return
+	}
+	apiWorkspace, err := convertWorkspace(
+		apiKey.UserID,
+		workspace,
+		data.builds[0],
+		data.templates[0],
+		data.activeVersion(workspace, data.templates[0]),
+		newOwner.Username,
+		newOwner.AvatarURL,
+		api.Options.AllowWorkspaceRenames,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiWorkspace)
+}
+
 // @Summary Resolve workspace autostart by id.
 // @ID resolve-workspace-autostart-by-id
 // @Security CoderSessionToken
@@ -1297,12 +1816,24 @@ func (api *API) resolveAutostart(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if build.TemplateVersionID == template.ActiveVersionID {
+	activeVersion, err := api.Database.GetTemplateActiveVersionForUser(ctx, database.GetTemplateActiveVersionForUserParams{
+		UserID:     workspace.OwnerID,
+		TemplateID: template.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching active template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if build.TemplateVersionID == activeVersion.ActiveVersionID {
 		httpapi.Write(ctx, rw, http.StatusOK, codersdk.ResolveAutostartResponse{})
 		return
 	}
 
-	version, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	version, err := api.Database.GetTemplateVersionByID(ctx, activeVersion.ActiveVersionID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching template version.",
@@ -1432,6 +1963,7 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 			workspace,
 			data.builds[0],
 			data.templates[0],
+			data.activeVersion(workspace, data.templates[0]),
 			owner.Username,
 			owner.AvatarURL,
 			api.Options.AllowWorkspaceRenames,
@@ -1498,10 +2030,20 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 }
 
 type workspaceData struct {
-	templates    []database.Template
-	builds       []codersdk.WorkspaceBuild
-	users        []database.User
-	allowRenames bool
+	templates      []database.Template
+	builds         []codersdk.WorkspaceBuild
+	users          []database.User
+	activeVersions map[uuid.UUID]database.GetTemplateActiveVersionForUserRow
+	allowRenames   bool
+}
+
+// activeVersion returns the version of its template the workspace should use,
+// which depends on the release channel of its owner.
+func (d workspaceData) activeVersion(workspace database.Workspace, template database.Template) database.GetTemplateActiveVersionForUserRow {
+	if active, ok := d.activeVersions[workspace.ID]; ok {
+		return active
+	}
+	return database.GetTemplateActiveVersionForUserRow{ActiveVersionID: template.ActiveVersionID}
 }
 
 // workspacesData only returns the data the caller can access. If the caller
@@ -1535,6 +2077,20 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		return workspaceData{}, xerrors.Errorf("get workspace builds data: %w", err)
 	}
 
+	// This query must be run as system restricted to be efficient.
+	// nolint:gocritic
+	activeVersionRows, err := api.Database.GetTemplateActiveVersionsByWorkspaceIDs(dbauthz.AsSystemRestricted(ctx), workspaceIDs)
+	if err != nil {
+		return workspaceData{}, xerrors.Errorf("get active template versions: %w", err)
+	}
+	activeVersions := make(map[uuid.UUID]database.GetTemplateActiveVersionForUserRow, len(activeVersionRows))
+	for _, row := range activeVersionRows {
+		activeVersions[row.WorkspaceID] = database.GetTemplateActiveVersionForUserRow{
+			ActiveVersionID: row.ActiveVersionID,
+			ReleaseChannel:  row.ReleaseChannel,
+		}
+	}
+
 	apiBuilds, err := api.convertWorkspaceBuilds(
 		builds,
 		workspaces,
@@ -1553,10 +2109,11 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 	}
 
 	return workspaceData{
-		templates:    templates,
-		builds:       apiBuilds,
-		users:        data.users,
-		allowRenames: api.Options.AllowWorkspaceRenames,
+		templates:      templates,
+		builds:         apiBuilds,
+		users:          data.users,
+		activeVersions: activeVersions,
+		allowRenames:   api.Options.AllowWorkspaceRenames,
 	}, nil
 }
 
@@ -1599,6 +2156,7 @@ func convertWorkspaces(requesterID uuid.UUID, workspaces []database.Workspace, d
 			workspace,
 			build,
 			template,
+			data.activeVersion(workspace, template),
 			owner.Username,
 			owner.AvatarURL,
 			data.allowRenames,
@@ -1617,6 +2175,7 @@ func convertWorkspace(
 	workspace database.Workspace,
 	workspaceBuild codersdk.WorkspaceBuild,
 	template database.Template,
+	activeVersion database.GetTemplateActiveVersionForUserRow,
 	username string,
 	avatarURL string,
 	allowRenames bool,
@@ -1667,9 +2226,10 @@ func convertWorkspace(
 		TemplateIcon:                         template.Icon,
 		TemplateDisplayName:                  template.DisplayName,
 		TemplateAllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
-		TemplateActiveVersionID:              template.ActiveVersionID,
+		TemplateActiveVersionID:              activeVersion.ActiveVersionID,
 		TemplateRequireActiveVersion:         template.RequireActiveVersion,
-		Outdated:                             workspaceBuild.TemplateVersionID.String() != template.ActiveVersionID.String(),
+		TemplateReleaseChannel:               activeVersion.ReleaseChannel,
+		Outdated:                             workspaceBuild.TemplateVersionID.String() != activeVersion.ActiveVersionID.String(),
 		Name:                                 workspace.Name,
 		AutostartSchedule:                    autostartSchedule,
 		TTLMillis:                            ttlMillis,
@@ -630,6 +630,49 @@ func TestPostWorkspacesByOrganization(t *testing.T) {
 		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
 	})
 
+	t.Run("TemplateWorkspaceLimit", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxWorkspaces: ptr.Ref[int32](1),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, template.MaxWorkspaces)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// The limit applies to all users of the template.
+		_, err = member.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "workspace",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "has reached its limit of 1 workspaces")
+
+		// Deleted workspaces don't count.
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		_, err = member.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "workspace",
+		})
+		require.NoError(t, err)
+	})
+
 	t.Run("CreateWithAuditLogs", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
@@ -2977,6 +3020,231 @@ func TestWorkspaceWithRichParameters(t *testing.T) {
 	require.ElementsMatch(t, expectedBuildParameters, workspaceBuildParameters)
 }
 
+func TestWorkspaceWithPreset(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "cpu", Type: "number", DefaultValue: "1"},
+						{Name: "image", Type: "string", DefaultValue: "ubuntu"},
+					},
+					Presets: []*proto.Preset{{
+						Name: "Large",
+						Parameters: []*proto.PresetParameter{
+							{Name: "cpu", Value: "8"},
+							{Name: "image", Value: "golang"},
+						},
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+
+	// Values given explicitly take precedence over the preset.
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.TemplateVersionPresetID = presets[0].ID
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "image", Value: "rust"}}
+	})
+	workspaceBuild := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+	workspaceBuildParameters, err := client.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+		{Name: "cpu", Value: "8"},
+		{Name: "image", Value: "rust"},
+	}, workspaceBuildParameters)
+
+	// Presets of other template versions are rejected.
+	_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID:              template.ID,
+		Name:                    "other",
+		TemplateVersionPresetID: uuid.New(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
+func TestWorkspaceClaimPrebuild(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon:   true,
+		PrebuildsReconcileInterval: testutil.IntervalFast,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "cpu", Type: "number", DefaultValue: "1", Mutable: true},
+						{Name: "region", Type: "string", DefaultValue: "eu"},
+					},
+					Presets: []*proto.Preset{{
+						Name:       "Large",
+						Parameters: []*proto.PresetParameter{{Name: "cpu", Value: "8"}},
+						Prebuilds:  1,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+
+	awaitPrebuild := func() codersdk.Workspace {
+		var prebuild codersdk.Workspace
+		require.True(t, testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+			res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: "prebuilds"})
+			require.NoError(t, err)
+			for _, workspace := range res.Workspaces {
+				if workspace.LatestBuild.Status == codersdk.WorkspaceStatusRunning {
+					prebuild = workspace
+					return true
+				}
+			}
+			return false
+		}, testutil.IntervalFast))
+		return prebuild
+	}
+	prebuild := awaitPrebuild()
+
+	// Changing an immutable parameter the prebuild was built with can't be
+	// applied to it, so a new workspace is built.
+	workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = "other-region"
+		cwr.TemplateVersionPresetID = presets[0].ID
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "us"}}
+	})
+	require.NotEqual(t, prebuild.ID, workspace.ID)
+	require.EqualValues(t, 1, workspace.LatestBuild.BuildNumber)
+
+	// Otherwise the prebuild is handed over and rebuilt for its new owner.
+	workspace = coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = "claimed"
+		cwr.TemplateVersionPresetID = presets[0].ID
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "cpu", Value: "4"}}
+	})
+	require.Equal(t, prebuild.ID, workspace.ID)
+	require.Equal(t, "claimed", workspace.Name)
+	require.Equal(t, member.ID, workspace.OwnerID)
+	require.Equal(t, member.ID, workspace.LatestBuild.InitiatorID)
+	require.EqualValues(t, 2, workspace.LatestBuild.BuildNumber)
+	workspaceBuild := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+	workspaceBuildParameters, err := memberClient.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+		{Name: "cpu", Value: "4"},
+		{Name: "region", Value: "eu"},
+	}, workspaceBuildParameters)
+
+	// The pool is refilled.
+	require.NotEqual(t, prebuild.ID, awaitPrebuild().ID)
+}
+
+func TestWorkspaceClaimPrebuildTemplateLimit(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon:   true,
+		PrebuildsReconcileInterval: testutil.IntervalFast,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Presets: []*proto.Preset{{
+						Name:      "Default",
+						Prebuilds: 1,
+					}},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		MaxWorkspaces: ptr.Ref[int32](1),
+	})
+	require.NoError(t, err)
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+
+	awaitPrebuild := func() codersdk.Workspace {
+		var prebuild codersdk.Workspace
+		require.True(t, testutil.Eventually(ctx, t, func(ctx context.Context) bool {
+			res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: "prebuilds"})
+			require.NoError(t, err)
+			for _, workspace := range res.Workspaces {
+				if workspace.LatestBuild.Status == codersdk.WorkspaceStatusRunning {
+					prebuild = workspace
+					return true
+				}
+			}
+			return false
+		}, testutil.IntervalFast))
+		return prebuild
+	}
+	prebuild := awaitPrebuild()
+
+	// Unclaimed prebuilds don't count towards the limit, so the prebuild can
+	// be claimed.
+	workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = "claimed"
+		cwr.TemplateVersionPresetID = presets[0].ID
+	})
+	require.Equal(t, prebuild.ID, workspace.ID)
+	require.Equal(t, member.ID, workspace.OwnerID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// The pool is refilled even though the template has reached its limit.
+	require.NotEqual(t, prebuild.ID, awaitPrebuild().ID)
+
+	// The claimed workspace counts, so the next claim is rejected.
+	_, err = memberClient.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID:              template.ID,
+		TemplateVersionPresetID: presets[0].ID,
+		Name:                    "over-limit",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Contains(t, apiErr.Message, "has reached its limit of 1 workspaces")
+}
+
 func TestWorkspaceWithOptionalRichParameters(t *testing.T) {
 	t.Parallel()
 
@@ -3370,3 +3638,96 @@ func TestWorkspaceFavoriteUnfavorite(t *testing.T) {
 	require.ErrorAs(t, err, &sdkErr)
 	require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
 }
+
+func TestWorkspaceOwnerTransfer(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	newOwnerClient, newOwner := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	workspace := coderdtest.CreateWorkspace(t, memberClient, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Members can't give their workspaces away.
+	_, err := memberClient.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: newOwner.Username,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// The new owner must exist.
+	_, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: "doesnotexist",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// The new owner already having a workspace with the same name conflicts.
+	conflict := coderdtest.CreateWorkspace(t, newOwnerClient, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = workspace.Name
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, newOwnerClient, conflict.LatestBuild.ID)
+	_, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: newOwner.Username,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	build := coderdtest.CreateWorkspaceBuild(t, newOwnerClient, conflict, database.WorkspaceTransitionDelete)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, newOwnerClient, build.ID)
+
+	auditor.ResetLogs()
+	transferred, err := client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: newOwner.ID.String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, newOwner.ID, transferred.OwnerID)
+	require.Equal(t, newOwner.Username, transferred.OwnerName)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		Action:         database.AuditActionWrite,
+		ResourceType:   database.ResourceTypeWorkspace,
+		ResourceTarget: workspace.Name,
+	}))
+
+	// The workspace is rebuilt with the identity of the new owner, keeping
+	// its state.
+	require.NotEqual(t, workspace.LatestBuild.ID, transferred.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, transferred.LatestBuild.Transition)
+	require.Equal(t, newOwner.ID, transferred.LatestBuild.WorkspaceOwnerID)
+	require.Equal(t, owner.UserID, transferred.LatestBuild.InitiatorID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, transferred.LatestBuild.ID)
+
+	// The workspace now belongs to the new owner only.
+	_, err = newOwnerClient.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	_, err = memberClient.Workspace(ctx, workspace.ID)
+	require.Error(t, err)
+
+	// Transferring to the current owner does nothing.
+	_, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: newOwner.Username,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Suspended users can't receive workspaces.
+	_, err = client.UpdateUserStatus(ctx, member.Username, codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+	_, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+		Owner: member.Username,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
@@ -0,0 +1,316 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace snapshots
+// @ID get-workspace-snapshots
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [get]
+func (api *API) workspaceSnapshots(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	snapshots, err := api.Database.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiSnapshots := make([]codersdk.WorkspaceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		apiSnapshot, err := api.convertWorkspaceSnapshot(ctx, snapshot)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting workspace snapshot.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		apiSnapshots = append(apiSnapshots, apiSnapshot)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiSnapshots)
+}
+
+// @Summary Get workspace snapshot
+// @ID get-workspace-snapshot
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot name"
+// @Success 200 {object} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots/{snapshot} [get]
+func (api *API) workspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		snapshot = httpmw.WorkspaceSnapshotParam(r)
+	)
+
+	apiSnapshot, err := api.convertWorkspaceSnapshot(ctx, snapshot)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiSnapshot)
+}
+
+// @Summary Create workspace snapshot
+// @Description Stops the workspace and tells its template to take a snapshot.
+// @Description The template returns the artifacts of the snapshot as resource
+// @Description metadata prefixed with "snapshot.".
+// @ID create-workspace-snapshot
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceSnapshotRequest true "Create workspace snapshot request"
+// @Success 201 {object} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [post]
+func (api *API) postWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	var req codersdk.CreateWorkspaceSnapshotRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStop).
+		Initiator(apiKey.UserID).
+		Snapshot(req.Name).
+		DeploymentValues(api.Options.DeploymentValues)
+	workspaceBuild, provisionerJob, err := builder.Build(
+		ctx,
+		api.Database,
+		func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+		audit.WorkspaceBuildBaggageFromRequest(r),
+	)
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusForbidden
+		}
+
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace snapshot build error", slog.Error(buildErr.Wrapped))
+		}
+
+		resp := codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		}
+		if buildErr.Status == http.StatusConflict && database.IsUniqueViolation(err) {
+			resp.Validations = []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}}
+		}
+		httpapi.Write(ctx, rw, buildErr.Status, resp)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	snapshot, err := api.Database.GetWorkspaceSnapshotByBuildID(ctx, workspaceBuild.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	apiSnapshot, err := api.convertWorkspaceSnapshot(ctx, snapshot)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, apiSnapshot)
+}
+
+// @Summary Delete workspace snapshot
+// @Description Deletes the record of the snapshot. The artifacts are not
+// @Description deleted, and workspaces restored from the snapshot keep them.
+// @ID delete-workspace-snapshot
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot name"
+// @Success 204
+// @Router /workspaces/{workspace}/snapshots/{snapshot} [delete]
+func (api *API) deleteWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		snapshot = httpmw.WorkspaceSnapshotParam(r)
+	)
+
+	err := api.Database.DeleteWorkspaceSnapshotByID(ctx, snapshot.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// restorableWorkspaceSnapshot returns the snapshot a new workspace of the
+// template is restored from. It writes the error response and returns false if
+// the snapshot can't be restored.
+func (api *API) restorableWorkspaceSnapshot(ctx context.Context, rw http.ResponseWriter, snapshotID uuid.UUID, templateID uuid.UUID) (database.WorkspaceSnapshot, bool) {
+	snapshot, err := api.Database.GetWorkspaceSnapshotByID(ctx, snapshotID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q doesn't exist.", snapshotID.String()),
+			Validations: []codersdk.ValidationError{{
+				Field:  "snapshot_id",
+				Detail: "snapshot not found",
+			}},
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, snapshot.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace of snapshot.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	if workspace.TemplateID != templateID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q was taken from a workspace of another template.", snapshot.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "snapshot_id",
+				Detail: "snapshot of another template",
+			}},
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+
+	apiSnapshot, err := api.convertWorkspaceSnapshot(ctx, snapshot)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	if apiSnapshot.Status != codersdk.ProvisionerJobSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q is not ready to be restored, its build is %s.", snapshot.Name, apiSnapshot.Status),
+			Validations: []codersdk.ValidationError{{
+				Field:  "snapshot_id",
+				Detail: "snapshot not ready",
+			}},
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	if len(apiSnapshot.Artifacts) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q has no artifacts to restore.", snapshot.Name),
+			Detail:  fmt.Sprintf("The template must return them as resource metadata with keys prefixed with %q.", provisionerdserver.SnapshotArtifactPrefix),
+			Validations: []codersdk.ValidationError{{
+				Field:  "snapshot_id",
+				Detail: "snapshot has no artifacts",
+			}},
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+func (api *API) convertWorkspaceSnapshot(ctx context.Context, snapshot database.WorkspaceSnapshot) (codersdk.WorkspaceSnapshot, error) {
+	build, err := api.Database.GetWorkspaceBuildByID(ctx, snapshot.BuildID)
+	if err != nil {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("get workspace build: %w", err)
+	}
+	job, err := api.Database.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("get provisioner job: %w", err)
+	}
+	artifacts := map[string]string{}
+	if len(snapshot.Artifacts) > 0 {
+		err = json.Unmarshal(snapshot.Artifacts, &artifacts)
+		if err != nil {
+			return codersdk.WorkspaceSnapshot{}, xerrors.Errorf("unmarshal artifacts: %w", err)
+		}
+	}
+
+	return codersdk.WorkspaceSnapshot{
+		ID:                snapshot.ID,
+		WorkspaceID:       snapshot.WorkspaceID,
+		Name:              snapshot.Name,
+		WorkspaceBuildID:  build.ID,
+		TemplateVersionID: build.TemplateVersionID,
+		Status:            codersdk.ProvisionerJobStatus(job.JobStatus),
+		Artifacts:         artifacts,
+		CreatedAt:         snapshot.CreatedAt,
+	}, nil
+}
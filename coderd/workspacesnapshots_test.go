@@ -0,0 +1,211 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	// snapshotResponses returns the responses of a template that returns
+	// the artifacts of a snapshot on every build. Real templates only
+	// return them when they are told to take a snapshot.
+	snapshotResponses := func(artifacts ...*proto.Resource_Metadata) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name:     "home",
+							Type:     "example_volume",
+							Metadata: artifacts,
+						}},
+					},
+				},
+			}},
+		}
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses(
+			&proto.Resource_Metadata{Key: "snapshot.home", Value: "snap-123"},
+			&proto.Resource_Metadata{Key: "snapshot.empty", IsNull: true},
+			&proto.Resource_Metadata{Key: "size", Value: "10GB"},
+		))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "datasets", snapshot.Name)
+		require.Equal(t, workspace.ID, snapshot.WorkspaceID)
+		require.Equal(t, version.ID, snapshot.TemplateVersionID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.WorkspaceBuildID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+
+		snapshot, err = client.WorkspaceSnapshot(ctx, workspace.ID, "datasets")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, snapshot.Status)
+		require.Equal(t, map[string]string{"home": "snap-123"}, snapshot.Artifacts)
+
+		snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, snapshot.ID, snapshots[0].ID)
+
+		err = client.DeleteWorkspaceSnapshot(ctx, workspace.ID, "datasets")
+		require.NoError(t, err)
+
+		_, err = client.WorkspaceSnapshot(ctx, workspace.ID, "datasets")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.WorkspaceBuildID)
+
+		_, err = client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses(
+			&proto.Resource_Metadata{Key: "snapshot.home", Value: "snap-123"},
+		))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.WorkspaceBuildID)
+
+		clone := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.SnapshotID = snapshot.ID
+		})
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, clone.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// The clone keeps the artifacts when the snapshot is deleted.
+		err = client.DeleteWorkspaceSnapshot(ctx, workspace.ID, "datasets")
+		require.NoError(t, err)
+		build, err = client.CreateWorkspaceBuild(ctx, clone.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	})
+
+	t.Run("RestoreInvalid", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The template doesn't return any artifacts.
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.WorkspaceBuildID)
+
+		for _, tc := range []struct {
+			name       string
+			templateID uuid.UUID
+			snapshotID uuid.UUID
+		}{
+			{name: "NotFound", templateID: template.ID, snapshotID: uuid.New()},
+			{name: "OtherTemplate", templateID: otherTemplate.ID, snapshotID: snapshot.ID},
+			{name: "NoArtifacts", templateID: template.ID, snapshotID: snapshot.ID},
+		} {
+			_, err = client.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+				TemplateID: tc.templateID,
+				Name:       coderdtest.RandomUsername(t),
+				SnapshotID: tc.snapshotID,
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, tc.name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), tc.name)
+			require.Equal(t, "snapshot_id", apiErr.Validations[0].Field, tc.name)
+		}
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Members can't see the workspaces of others.
+		_, err := member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "datasets",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/audit"
@@ -45,9 +46,11 @@ type Builder struct {
 	logLevel         string
 	deploymentValues *codersdk.DeploymentValues
 
-	richParameterValues []codersdk.WorkspaceBuildParameter
-	initiator           uuid.UUID
-	reason              database.BuildReason
+	richParameterValues     []codersdk.WorkspaceBuildParameter
+	templateVersionPresetID uuid.UUID
+	initiator               uuid.UUID
+	reason                  database.BuildReason
+	snapshotName            string
 
 	// used during build, makes function arguments less verbose
 	ctx   context.Context
@@ -55,9 +58,11 @@ type Builder struct {
 
 	// cache of objects, so we only fetch once
 	template                  *database.Template
+	activeVersionID           *uuid.UUID
 	templateVersion           *database.TemplateVersion
 	templateVersionJob        *database.ProvisionerJob
 	templateVersionParameters *[]database.TemplateVersionParameter
+	presetParameters          *[]database.TemplateVersionPresetParameter
 	lastBuild                 *database.WorkspaceBuild
 	lastBuildErr              *error
 	lastBuildParameters       *[]database.WorkspaceBuildParameter
@@ -73,7 +78,8 @@ type Option func(Builder) Builder
 // The zero value of this struct means to use the version from the last build.  If there is no last build,
 // the build will fail.
 //
-// setting active: true means to use the active version from the template.
+// setting active: true means to use the active version from the template, or the version of the release channel
+// the workspace owner is assigned to.
 //
 // setting specific to a non-nil value means to use the provided template version ID.
 //
@@ -158,6 +164,24 @@ func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Build
 	return b
 }
 
+// TemplateVersionPresetID sets the preset whose parameter values are used for
+// parameters that are not given by RichParameterValues. The preset must belong
+// to the template version of the build.
+func (b Builder) TemplateVersionPresetID(id uuid.UUID) Builder {
+	// nolint: revive
+	b.templateVersionPresetID = id
+	return b
+}
+
+// Snapshot makes the build take a snapshot of the workspace with the given
+// name. The template is told to snapshot its data and the artifacts it returns
+// are recorded when the build completes. Only stop builds take snapshots.
+func (b Builder) Snapshot(name string) Builder {
+	// nolint: revive
+	b.snapshotName = name
+	return b
+}
+
 // SetLastWorkspaceBuildInTx prepopulates the Builder's cache with the last workspace build.  This allows us
 // to avoid a repeated database query when the Builder's caller also needs the workspace build, e.g. auto-start &
 // auto-stop.
@@ -246,6 +270,10 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			return nil, nil, err
 		}
 	}
+	if b.snapshotName != "" && b.trans != database.WorkspaceTransitionStop {
+		msg := "Snapshots can only be taken by stop builds."
+		return nil, nil, BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
 	err := b.checkTemplateVersionMatchesTemplate()
 	if err != nil {
 		return nil, nil, err
@@ -299,13 +327,15 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	tags := provisionersdk.MutateTags(b.workspace.OwnerID, templateVersionJob.Tags)
 
 	now := dbtime.Now()
+	// Builds run with the provisioner the template version was imported with,
+	// so a template can move to a different engine one version at a time.
 	provisionerJob, err := b.store.InsertProvisionerJob(b.ctx, database.InsertProvisionerJobParams{
 		ID:             uuid.New(),
 		CreatedAt:      now,
 		UpdatedAt:      now,
 		InitiatorID:    b.initiator,
 		OrganizationID: template.OrganizationID,
-		Provisioner:    template.Provisioner,
+		Provisioner:    templateVersionJob.Provisioner,
 		Type:           database.ProvisionerJobTypeWorkspaceBuild,
 		StorageMethod:  templateVersionJob.StorageMethod,
 		FileID:         templateVersionJob.FileID,
@@ -315,6 +345,7 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			Valid:      true,
 			RawMessage: traceMetadataRaw,
 		},
+		EngineVersionConstraint: templateVersionJob.EngineVersionConstraint,
 	})
 	if err != nil {
 		return nil, nil, BuildError{http.StatusInternalServerError, "insert provisioner job", err}
@@ -372,6 +403,23 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			return BuildError{http.StatusInternalServerError, "insert workspace build parameters: %w", err}
 		}
 
+		if b.snapshotName != "" {
+			_, err = store.InsertWorkspaceSnapshot(b.ctx, database.InsertWorkspaceSnapshotParams{
+				ID:          uuid.New(),
+				WorkspaceID: b.workspace.ID,
+				Name:        b.snapshotName,
+				BuildID:     workspaceBuildID,
+				CreatedAt:   now,
+			})
+			if database.IsUniqueViolation(err) {
+				msg := fmt.Sprintf("A snapshot named %q already exists for this workspace.", b.snapshotName)
+				return BuildError{http.StatusConflict, msg, err}
+			}
+			if err != nil {
+				return BuildError{http.StatusInternalServerError, "insert workspace snapshot", err}
+			}
+		}
+
 		workspaceBuild, err = store.GetWorkspaceBuildByID(b.ctx, workspaceBuildID)
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "get workspace build", err}
@@ -435,11 +483,18 @@ func (b *Builder) getTemplateVersionID() (uuid.UUID, error) {
 		return *b.version.specific, nil
 	}
 	if b.version.active {
-		t, err := b.getTemplate()
+		if b.activeVersionID != nil {
+			return *b.activeVersionID, nil
+		}
+		active, err := b.store.GetTemplateActiveVersionForUser(b.ctx, database.GetTemplateActiveVersionForUserParams{
+			UserID:     b.workspace.OwnerID,
+			TemplateID: b.workspace.TemplateID,
+		})
 		if err != nil {
-			return uuid.Nil, xerrors.Errorf("get template so we can get active version: %w", err)
+			return uuid.Nil, xerrors.Errorf("get active version of workspace owner: %w", err)
 		}
-		return t.ActiveVersionID, nil
+		b.activeVersionID = &active.ActiveVersionID
+		return *b.activeVersionID, nil
 	}
 	// default is prior version
 	bld, err := b.getLastBuild()
@@ -513,6 +568,18 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 	if err != nil {
 		return nil, nil, BuildError{http.StatusBadRequest, "Unable to build workspace with unsupported parameters", err}
 	}
+	presetParameters, err := b.getPresetParameters()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, presetParameter := range presetParameters {
+		if !slices.ContainsFunc(templateVersionParameters, func(tvp database.TemplateVersionParameter) bool {
+			return tvp.Name == presetParameter.Name
+		}) {
+			msg := fmt.Sprintf("Preset sets parameter %q, which is not a parameter of the template version", presetParameter.Name)
+			return nil, nil, BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+	}
 	resolver := codersdk.ParameterResolver{
 		Rich: db2sdk.WorkspaceBuildParameters(lastBuildParameters),
 	}
@@ -537,15 +604,54 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 	return names, values, nil
 }
 
+// findNewBuildParameterValue returns the value given for the parameter in the
+// request, falling back to the value set by the preset.
 func (b *Builder) findNewBuildParameterValue(name string) *codersdk.WorkspaceBuildParameter {
 	for _, v := range b.richParameterValues {
 		if v.Name == name {
 			return &v
 		}
 	}
+	if b.presetParameters != nil {
+		for _, p := range *b.presetParameters {
+			if p.Name == name {
+				return &codersdk.WorkspaceBuildParameter{Name: p.Name, Value: p.Value}
+			}
+		}
+	}
 	return nil
 }
 
+// getPresetParameters returns the parameter values of the preset selected for
+// the build, or none if no preset was selected.
+func (b *Builder) getPresetParameters() ([]database.TemplateVersionPresetParameter, error) {
+	if b.presetParameters != nil {
+		return *b.presetParameters, nil
+	}
+	if b.templateVersionPresetID == uuid.Nil {
+		b.presetParameters = &[]database.TemplateVersionPresetParameter{}
+		return *b.presetParameters, nil
+	}
+	tvID, err := b.getTemplateVersionID()
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "failed to compute template version ID", err}
+	}
+	preset, err := b.store.GetTemplateVersionPresetByID(b.ctx, b.templateVersionPresetID)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && preset.TemplateVersionID != tvID) {
+		msg := fmt.Sprintf("Preset %s does not exist for template version %s", b.templateVersionPresetID, tvID)
+		return nil, BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "failed to fetch preset", err}
+	}
+	parameters, err := b.store.GetTemplateVersionPresetParametersByPresetID(b.ctx, preset.ID)
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "failed to fetch preset parameters", err}
+	}
+	b.presetParameters = &parameters
+	return parameters, nil
+}
+
 func (b *Builder) getLastBuildParameters() ([]database.WorkspaceBuildParameter, error) {
 	if b.lastBuildParameters != nil {
 		return *b.lastBuildParameters, nil
@@ -646,7 +752,7 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 		msg := fmt.Sprintf("Transition %q not supported.", b.trans)
 		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
 	}
-	if !authFunc(action, b.workspace) {
+	if !authFunc(action, b.workspace.WorkspaceBuildRBAC(b.trans)) {
 		// We use the same wording as the httpapi to avoid leaking the existence of the workspace
 		return BuildError{http.StatusNotFound, httpapi.ResourceNotFoundResponse.Message, xerrors.New(httpapi.ResourceNotFoundResponse.Message)}
 	}
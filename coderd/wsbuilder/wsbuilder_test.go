@@ -65,6 +65,7 @@ func TestBuilder_NoOptions(t *testing.T) {
 		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
 			asrt.Equal(userID, job.InitiatorID)
 			asrt.Equal(inactiveFileID, job.FileID)
+			asrt.Equal(database.ProvisionerTypeOpentofu, job.Provisioner)
 			input := provisionerdserver.WorkspaceProvisionJob{}
 			err := json.Unmarshal(job.Input, &input)
 			req.NoError(err)
@@ -218,6 +219,7 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	mDB := expectDB(t,
 		// Inputs
 		withTemplate,
+		withOwnerActiveVersion(activeVersionID),
 		withActiveVersion(nil),
 		withLastBuildNotFound,
 		withParameterSchemas(activeJobID, nil),
@@ -246,6 +248,100 @@ func TestBuilder_ActiveVersion(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_ActiveVersionReleaseChannel(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		// The owner is on a release channel of the inactive version.
+		withOwnerActiveVersion(inactiveVersionID),
+		withInactiveVersion(nil),
+		withLastBuildNotFound,
+		withParameterSchemas(inactiveJobID, nil),
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+			asrt.Equal(inactiveFileID, job.FileID)
+		}),
+
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(inactiveVersionID, bld.TemplateVersionID)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).ActiveVersion()
+	_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
+func TestBuilder_Snapshot(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buildID uuid.UUID
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersion(nil),
+		withLastBuildFound,
+		withRichParameters(nil),
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {
+		}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(database.WorkspaceTransitionStop, bld.Transition)
+			buildID = bld.ID
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		expectSnapshot(func(snapshot database.InsertWorkspaceSnapshotParams) {
+			asrt.Equal(workspaceID, snapshot.WorkspaceID)
+			asrt.Equal("datasets", snapshot.Name)
+			asrt.Equal(buildID, snapshot.BuildID)
+		}),
+		withBuild,
+	)
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStop).Snapshot("datasets")
+	_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
+func TestBuilder_SnapshotRequiresStop(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t)
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Snapshot("datasets")
+	_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+	var buildErr wsbuilder.BuildError
+	req.ErrorAs(err, &buildErr)
+	req.Equal(http.StatusBadRequest, buildErr.Status)
+}
+
 func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 	t.Parallel()
 
@@ -625,6 +721,144 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 	})
 }
 
+func TestWorkspaceBuildWithPreset(t *testing.T) {
+	t.Parallel()
+
+	const (
+		cpuParameterName   = "cpu"
+		imageParameterName = "image"
+	)
+	presetID := uuid.MustParse("12341234-0000-0000-000e-000000000000")
+
+	richParameters := []database.TemplateVersionParameter{
+		{Name: cpuParameterName, Mutable: true, Options: json.RawMessage("[]")},
+		{Name: imageParameterName, Mutable: true, Options: json.RawMessage("[]")},
+	}
+
+	t.Run("PresetValues", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+		asrt := assert.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Values given in the request take precedence over the preset.
+		nextBuildParameters := []codersdk.WorkspaceBuildParameter{
+			{Name: imageParameterName, Value: "ubuntu"},
+		}
+		expectedParams := map[string]string{
+			cpuParameterName:   "8",
+			imageParameterName: "ubuntu",
+		}
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withOwnerActiveVersion(activeVersionID),
+			withActiveVersion(richParameters),
+			withLastBuildNotFound,
+			withParameterSchemas(activeJobID, nil),
+			withPreset(presetID, activeVersionID, []database.TemplateVersionPresetParameter{
+				{TemplateVersionPresetID: presetID, Name: cpuParameterName, Value: "8"},
+				{TemplateVersionPresetID: presetID, Name: imageParameterName, Value: "golang"},
+			}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+			expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+				asrt.Len(params.Name, len(expectedParams))
+				for i := range params.Name {
+					value, ok := expectedParams[params.Name[i]]
+					asrt.True(ok, "unexpected name %s", params.Name[i])
+					asrt.Equal(value, params.Value[i])
+				}
+			}),
+			withBuild,
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			ActiveVersion().
+			RichParameterValues(nextBuildParameters).
+			TemplateVersionPresetID(presetID)
+		_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		req.NoError(err)
+	})
+
+	t.Run("PresetOfOtherVersion", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withOwnerActiveVersion(activeVersionID),
+			withActiveVersion(richParameters),
+			withLastBuildNotFound,
+			withParameterSchemas(activeJobID, nil),
+			withPreset(presetID, inactiveVersionID, nil),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			ActiveVersion().
+			TemplateVersionPresetID(presetID)
+		_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		bldErr := wsbuilder.BuildError{}
+		req.ErrorAs(err, &bldErr)
+		req.Equal(http.StatusBadRequest, bldErr.Status)
+	})
+
+	t.Run("UnknownParameter", func(t *testing.T) {
+		t.Parallel()
+
+		req := require.New(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mDB := expectDB(t,
+			// Inputs
+			withTemplate,
+			withOwnerActiveVersion(activeVersionID),
+			withActiveVersion(richParameters),
+			withLastBuildNotFound,
+			withParameterSchemas(activeJobID, nil),
+			withPreset(presetID, activeVersionID, []database.TemplateVersionPresetParameter{
+				{TemplateVersionPresetID: presetID, Name: "gpu", Value: "1"},
+			}),
+
+			// Outputs
+			expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+			withInTx,
+			expectBuild(func(bld database.InsertWorkspaceBuildParams) {}),
+		)
+
+		ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+		uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).
+			ActiveVersion().
+			TemplateVersionPresetID(presetID)
+		_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+		bldErr := wsbuilder.BuildError{}
+		req.ErrorAs(err, &bldErr)
+		req.Equal(http.StatusBadRequest, bldErr.Status)
+		req.Contains(bldErr.Message, `"gpu"`)
+	})
+}
+
 type txExpect func(mTx *dbmock.MockStore)
 
 func expectDB(t *testing.T, opts ...txExpect) *dbmock.MockStore {
@@ -661,6 +895,19 @@ func withTemplate(mTx *dbmock.MockStore) {
 		}, nil)
 }
 
+// withOwnerActiveVersion resolves the active version for the workspace owner,
+// which is the version of their release channel if they are assigned to one.
+func withOwnerActiveVersion(versionID uuid.UUID) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetTemplateActiveVersionForUser(gomock.Any(), database.GetTemplateActiveVersionForUserParams{
+			UserID:     userID,
+			TemplateID: templateID,
+		}).
+			Times(1).
+			Return(database.GetTemplateActiveVersionForUserRow{ActiveVersionID: versionID}, nil)
+	}
+}
+
 // withInTx runs the given functions on the same db mock.
 func withInTx(mTx *dbmock.MockStore) {
 	mTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
@@ -727,7 +974,7 @@ func withInactiveVersion(params []database.TemplateVersionParameter) func(mTx *d
 			ID:             inactiveJobID,
 			OrganizationID: orgID,
 			InitiatorID:    userID,
-			Provisioner:    database.ProvisionerTypeTerraform,
+			Provisioner:    database.ProvisionerTypeOpentofu,
 			StorageMethod:  database.ProvisionerStorageMethodFile,
 			Type:           database.ProvisionerJobTypeTemplateVersionImport,
 			Input:          nil,
@@ -813,6 +1060,24 @@ func withRichParameters(params []database.WorkspaceBuildParameter) func(mTx *dbm
 	}
 }
 
+func withPreset(id, versionID uuid.UUID, params []database.TemplateVersionPresetParameter) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().GetTemplateVersionPresetByID(gomock.Any(), id).
+			Times(1).
+			Return(database.TemplateVersionPreset{
+				ID:                id,
+				TemplateVersionID: versionID,
+				Name:              "preset",
+			}, nil)
+		if versionID != activeVersionID {
+			return
+		}
+		mTx.EXPECT().GetTemplateVersionPresetParametersByPresetID(gomock.Any(), id).
+			Times(1).
+			Return(params, nil)
+	}
+}
+
 // Since there is expected to be only one each of job, build, and build-parameters inserted, instead
 // of building matchers, we match any call and then assert its parameters.  This will feel
 // more familiar to the way we write other tests.
@@ -836,6 +1101,23 @@ func expectProvisionerJob(
 	}
 }
 
+// expectSnapshot captures a call to InsertWorkspaceSnapshot and runs the
+// provided assertions against it.
+func expectSnapshot(
+	assertions func(database.InsertWorkspaceSnapshotParams),
+) func(mTx *dbmock.MockStore) {
+	return func(mTx *dbmock.MockStore) {
+		mTx.EXPECT().InsertWorkspaceSnapshot(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(
+				func(ctx context.Context, params database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+					assertions(params)
+					return database.WorkspaceSnapshot{}, nil
+				},
+			)
+	}
+}
+
 func withBuild(mTx *dbmock.MockStore) {
 	mTx.EXPECT().GetWorkspaceBuildByID(gomock.Any(), gomock.Any()).Times(1).
 		DoAndReturn(func(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
@@ -19,7 +19,7 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,workspace:read,workspace:start-stop,template:read,template:push,user:read"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
 }
@@ -48,11 +48,38 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeWorkspaceRead is a scope that allows the user to read
+	// workspaces and their templates.
+	APIKeyScopeWorkspaceRead APIKeyScope = "workspace:read"
+	// APIKeyScopeWorkspaceStartStop is a scope that allows the user to
+	// start, stop and update workspaces, but not to create or delete them.
+	APIKeyScopeWorkspaceStartStop APIKeyScope = "workspace:start-stop"
+	// APIKeyScopeTemplateRead is a scope that allows the user to read
+	// templates.
+	APIKeyScopeTemplateRead APIKeyScope = "template:read"
+	// APIKeyScopeTemplatePush is a scope that allows the user to create
+	// templates and push new versions, but not to delete them.
+	APIKeyScopeTemplatePush APIKeyScope = "template:push"
+	// APIKeyScopeUserRead is a scope that allows the user to read users
+	// and organizations.
+	APIKeyScopeUserRead APIKeyScope = "user:read"
 )
 
+// APIKeyScopes are all scopes an API key can have. Scopes only restrict the
+// permissions of the user, they never grant more.
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeAll,
+	APIKeyScopeApplicationConnect,
+	APIKeyScopeWorkspaceRead,
+	APIKeyScopeWorkspaceStartStop,
+	APIKeyScopeTemplateRead,
+	APIKeyScopeTemplatePush,
+	APIKeyScopeUserRead,
+}
+
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,workspace:read,workspace:start-stop,template:read,template:push,user:read"`
 	TokenName string        `json:"token_name"`
 }
 
@@ -30,6 +30,11 @@ const (
 	ResourceTypeOAuth2ProviderApp ResourceType = "oauth2_provider_app"
 	// nolint:gosec // This is not a secret.
 	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeWebhook                 ResourceType = "webhook"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeOIDCSyncRule            ResourceType = "oidc_sync_rule"
+	ResourceTypeSCIMToken               ResourceType = "scim_token"
+	ResourceTypeUserLoginLockout        ResourceType = "user_login_lockout"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -66,6 +71,16 @@ func (r ResourceType) FriendlyString() string {
 		return "oauth2 app"
 	case ResourceTypeOAuth2ProviderAppSecret:
 		return "oauth2 app secret"
+	case ResourceTypeWebhook:
+		return "webhook"
+	case ResourceTypeCustomRole:
+		return "custom role"
+	case ResourceTypeOIDCSyncRule:
+		return "oidc sync rule"
+	case ResourceTypeSCIMToken:
+		return "scim token"
+	case ResourceTypeUserLoginLockout:
+		return "login lockout"
 	default:
 		return "unknown"
 	}
@@ -136,6 +151,9 @@ type AuditLog struct {
 	IsDeleted        bool            `json:"is_deleted"`
 
 	User *User `json:"user"`
+	// Impersonator is the user who performed the action while impersonating
+	// User. It is only set for actions taken during an impersonation session.
+	Impersonator *MinimalUser `json:"impersonator,omitempty"`
 }
 
 type AuditLogsRequest struct {
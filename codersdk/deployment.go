@@ -16,6 +16,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/agentmetrics"
@@ -183,6 +184,7 @@ type DeploymentValues struct {
 	Experiments                     serpent.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
 	UpdateCheck                     serpent.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
 	MaxTokenLifetime                serpent.Duration                     `json:"max_token_lifetime,omitempty" typescript:",notnull"`
+	MaxServiceAccountTokenLifetime  serpent.Duration                     `json:"max_service_account_token_lifetime,omitempty" typescript:",notnull"`
 	Swagger                         SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
 	Logging                         LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
 	Dangerous                       DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
@@ -190,6 +192,8 @@ type DeploymentValues struct {
 	SessionDuration                 serpent.Duration                     `json:"max_session_expiry,omitempty" typescript:",notnull"`
 	DisableSessionExpiryRefresh     serpent.Bool                         `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
 	DisablePasswordAuth             serpent.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	LoginLimit                      LoginLimitConfig                     `json:"login_limit,omitempty" typescript:",notnull"`
+	RequireTOTP                     serpent.Bool                         `json:"require_totp,omitempty" typescript:",notnull"`
 	Support                         SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
 	ExternalAuthConfigs             serpent.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
 	SSHConfig                       SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
@@ -202,6 +206,7 @@ type DeploymentValues struct {
 	AllowWorkspaceRenames           serpent.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
 	Healthcheck                     HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
 	CLIUpgradeMessage               serpent.String                       `json:"cli_upgrade_message,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
 
 	Config      serpent.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig serpent.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -297,27 +302,30 @@ type OIDCConfig struct {
 	ClientID     serpent.String `json:"client_id" typescript:",notnull"`
 	ClientSecret serpent.String `json:"client_secret" typescript:",notnull"`
 	// ClientKeyFile & ClientCertFile are used in place of ClientSecret for PKI auth.
-	ClientKeyFile       serpent.String                      `json:"client_key_file" typescript:",notnull"`
-	ClientCertFile      serpent.String                      `json:"client_cert_file" typescript:",notnull"`
-	EmailDomain         serpent.StringArray                 `json:"email_domain" typescript:",notnull"`
-	IssuerURL           serpent.String                      `json:"issuer_url" typescript:",notnull"`
-	Scopes              serpent.StringArray                 `json:"scopes" typescript:",notnull"`
-	IgnoreEmailVerified serpent.Bool                        `json:"ignore_email_verified" typescript:",notnull"`
-	UsernameField       serpent.String                      `json:"username_field" typescript:",notnull"`
-	EmailField          serpent.String                      `json:"email_field" typescript:",notnull"`
-	AuthURLParams       serpent.Struct[map[string]string]   `json:"auth_url_params" typescript:",notnull"`
-	IgnoreUserInfo      serpent.Bool                        `json:"ignore_user_info" typescript:",notnull"`
-	GroupAutoCreate     serpent.Bool                        `json:"group_auto_create" typescript:",notnull"`
-	GroupRegexFilter    serpent.Regexp                      `json:"group_regex_filter" typescript:",notnull"`
-	GroupAllowList      serpent.StringArray                 `json:"group_allow_list" typescript:",notnull"`
-	GroupField          serpent.String                      `json:"groups_field" typescript:",notnull"`
-	GroupMapping        serpent.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
-	UserRoleField       serpent.String                      `json:"user_role_field" typescript:",notnull"`
-	UserRoleMapping     serpent.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
-	UserRolesDefault    serpent.StringArray                 `json:"user_roles_default" typescript:",notnull"`
-	SignInText          serpent.String                      `json:"sign_in_text" typescript:",notnull"`
-	IconURL             serpent.URL                         `json:"icon_url" typescript:",notnull"`
-	SignupsDisabledText serpent.String                      `json:"signups_disabled_text" typescript:",notnull"`
+	ClientKeyFile             serpent.String                         `json:"client_key_file" typescript:",notnull"`
+	ClientCertFile            serpent.String                         `json:"client_cert_file" typescript:",notnull"`
+	EmailDomain               serpent.StringArray                    `json:"email_domain" typescript:",notnull"`
+	IssuerURL                 serpent.String                         `json:"issuer_url" typescript:",notnull"`
+	Scopes                    serpent.StringArray                    `json:"scopes" typescript:",notnull"`
+	IgnoreEmailVerified       serpent.Bool                           `json:"ignore_email_verified" typescript:",notnull"`
+	UsernameField             serpent.String                         `json:"username_field" typescript:",notnull"`
+	EmailField                serpent.String                         `json:"email_field" typescript:",notnull"`
+	AuthURLParams             serpent.Struct[map[string]string]      `json:"auth_url_params" typescript:",notnull"`
+	IgnoreUserInfo            serpent.Bool                           `json:"ignore_user_info" typescript:",notnull"`
+	GroupAutoCreate           serpent.Bool                           `json:"group_auto_create" typescript:",notnull"`
+	GroupRegexFilter          serpent.Regexp                         `json:"group_regex_filter" typescript:",notnull"`
+	GroupAllowList            serpent.StringArray                    `json:"group_allow_list" typescript:",notnull"`
+	GroupField                serpent.String                         `json:"groups_field" typescript:",notnull"`
+	GroupMapping              serpent.Struct[map[string]string]      `json:"group_mapping" typescript:",notnull"`
+	UserRoleField             serpent.String                         `json:"user_role_field" typescript:",notnull"`
+	UserRoleMapping           serpent.Struct[map[string][]string]    `json:"user_role_mapping" typescript:",notnull"`
+	UserRolesDefault          serpent.StringArray                    `json:"user_roles_default" typescript:",notnull"`
+	OrganizationField         serpent.String                         `json:"organization_field" typescript:",notnull"`
+	OrganizationMapping       serpent.Struct[map[string][]uuid.UUID] `json:"organization_mapping" typescript:",notnull"`
+	OrganizationAssignDefault serpent.Bool                           `json:"organization_assign_default" typescript:",notnull"`
+	SignInText                serpent.String                         `json:"sign_in_text" typescript:",notnull"`
+	IconURL                   serpent.URL                            `json:"icon_url" typescript:",notnull"`
+	SignupsDisabledText       serpent.String                         `json:"signups_disabled_text" typescript:",notnull"`
 }
 
 type TelemetryConfig struct {
@@ -386,6 +394,8 @@ type ProvisionerConfig struct {
 	DaemonPollJitter    serpent.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
 	ForceCancelInterval serpent.Duration `json:"force_cancel_interval" typescript:",notnull"`
 	DaemonPSK           serpent.String   `json:"daemon_psk" typescript:",notnull"`
+	ModuleCacheSize     serpent.Int64    `json:"module_cache_size" typescript:",notnull"`
+	StateRetention      serpent.Int64    `json:"state_retention" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
@@ -393,6 +403,17 @@ type RateLimitConfig struct {
 	API        serpent.Int64 `json:"api" typescript:",notnull"`
 }
 
+// LoginLimitConfig configures how password logins are slowed down and locked
+// out after repeated failures.
+type LoginLimitConfig struct {
+	FailureWindow    serpent.Duration `json:"failure_window" typescript:",notnull"`
+	FreeAttempts     serpent.Int64    `json:"free_attempts" typescript:",notnull"`
+	BaseDelay        serpent.Duration `json:"base_delay" typescript:",notnull"`
+	MaxDelay         serpent.Duration `json:"max_delay" typescript:",notnull"`
+	LockoutThreshold serpent.Int64    `json:"lockout_threshold" typescript:",notnull"`
+	LockoutDuration  serpent.Duration `json:"lockout_duration" typescript:",notnull"`
+}
+
 type SwaggerConfig struct {
 	Enable serpent.Bool `json:"enable" typescript:",notnull"`
 }
@@ -424,6 +445,38 @@ type HealthcheckConfig struct {
 	ThresholdDatabase serpent.Duration `json:"threshold_database" typescript:",notnull"`
 }
 
+// NotificationsConfig configures how notifications are delivered to users.
+type NotificationsConfig struct {
+	// Method is the delivery method used for new messages: smtp or webhook.
+	Method serpent.String `json:"method" typescript:",notnull"`
+	// DispatchTimeout bounds how long a single delivery attempt may take.
+	DispatchTimeout serpent.Duration `json:"dispatch_timeout" typescript:",notnull"`
+	// FetchInterval is how often the queue is checked for messages to send.
+	FetchInterval serpent.Duration `json:"fetch_interval" typescript:",notnull"`
+	// MaxSendAttempts is the number of attempts made to deliver a message
+	// before it is marked as permanently failed.
+	MaxSendAttempts serpent.Int64 `json:"max_send_attempts" typescript:",notnull"`
+	// RetryInterval is the delay before the first retry of a failed
+	// delivery. It doubles with every subsequent attempt.
+	RetryInterval serpent.Duration `json:"retry_interval" typescript:",notnull"`
+
+	SMTP    NotificationsEmailConfig   `json:"email" typescript:",notnull"`
+	Webhook NotificationsWebhookConfig `json:"webhook" typescript:",notnull"`
+}
+
+type NotificationsEmailConfig struct {
+	From      serpent.String   `json:"from" typescript:",notnull"`
+	Smarthost serpent.HostPort `json:"smarthost" typescript:",notnull"`
+	Hello     serpent.String   `json:"hello" typescript:",notnull"`
+	Username  serpent.String   `json:"username" typescript:",notnull"`
+	Password  serpent.String   `json:"password" typescript:",notnull"`
+	ForceTLS  serpent.Bool     `json:"force_tls" typescript:",notnull"`
+}
+
+type NotificationsWebhookConfig struct {
+	Endpoint serpent.URL `json:"endpoint" typescript:",notnull"`
+}
+
 const (
 	annotationFormatDuration = "format_duration"
 	annotationEnterpriseKey  = "enterprise"
@@ -565,6 +618,23 @@ when required by your organization's security policy.`,
 				"Clients include the coder cli, vs code extension, and the web UI.",
 			YAML: "client",
 		}
+		deploymentGroupNotifications = serpent.Group{
+			Name:        "Notifications",
+			Description: "Configure how notifications are queued and delivered to users.",
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = serpent.Group{
+			Parent:      &deploymentGroupNotifications,
+			Name:        "Email",
+			Description: "Deliver notifications by email through an SMTP server.",
+			YAML:        "email",
+		}
+		deploymentGroupNotificationsWebhook = serpent.Group{
+			Parent:      &deploymentGroupNotifications,
+			Name:        "Webhook",
+			Description: "Deliver notifications as JSON payloads to an HTTP endpoint.",
+			YAML:        "webhook",
+		}
 		deploymentGroupConfig = serpent.Group{
 			Name:        "Config",
 			Description: `Use a YAML configuration file when your server launch become unwieldy.`,
@@ -1279,6 +1349,38 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "userRoleDefault",
 		},
+		{
+			Name:        "OIDC Organization Field",
+			Description: "This field must be set if using the organization sync feature. Set this to the name of the claim used to store the user's organizations. The organizations should be sent as an array of strings.",
+			Flag:        "oidc-organization-field",
+			Env:         "CODER_OIDC_ORGANIZATION_FIELD",
+			// This value is intentionally blank. If this is empty, then OIDC
+			// organization sync behavior is disabled.
+			Default: "",
+			Value:   &c.OIDC.OrganizationField,
+			Group:   &deploymentGroupOIDC,
+			YAML:    "organizationField",
+		},
+		{
+			Name:        "OIDC Organization Mapping",
+			Description: "A map of the OIDC passed in organization claim values and the IDs of the organizations in Coder they should map to. Claim values without a mapping are ignored.",
+			Flag:        "oidc-organization-mapping",
+			Env:         "CODER_OIDC_ORGANIZATION_MAPPING",
+			Default:     "{}",
+			Value:       &c.OIDC.OrganizationMapping,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "organizationMapping",
+		},
+		{
+			Name:        "OIDC Organization Assign Default",
+			Description: "If organization sync is enabled, users are always kept in the default organization in addition to the organizations from their claims.",
+			Flag:        "oidc-organization-assign-default",
+			Env:         "CODER_OIDC_ORGANIZATION_ASSIGN_DEFAULT",
+			Default:     "true",
+			Value:       &c.OIDC.OrganizationAssignDefault,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "organizationAssignDefault",
+		},
 		{
 			Name:        "OpenID Connect sign in text",
 			Description: "The text to show on the OpenID Connect sign in button.",
@@ -1439,6 +1541,26 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
 		},
+		{
+			Name:        "Module Cache Size",
+			Description: "Maximum size in megabytes of the cache of Terraform modules shared by the built-in provisioner daemons. The least recently used modules are evicted once it is exceeded. Set to 0 to disable the cache.",
+			Flag:        "provisioner-module-cache-size",
+			Env:         "CODER_PROVISIONER_MODULE_CACHE_SIZE",
+			Default:     "1024",
+			Value:       &c.Provisioner.ModuleCacheSize,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "moduleCacheSize",
+		},
+		{
+			Name:        "State Retention",
+			Description: "Number of most recent builds per workspace that keep their Terraform state, so they can be restored with `coder state rollback`. The state of older builds is purged. Set to 0 to keep the state of all builds.",
+			Flag:        "provisioner-state-retention",
+			Env:         "CODER_PROVISIONER_STATE_RETENTION",
+			Default:     "25",
+			Value:       &c.Provisioner.StateRetention,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "stateRetention",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -1584,6 +1706,17 @@ when required by your organization's security policy.`,
 			YAML:        "maxTokenLifetime",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name:        "Max Service Account Token Lifetime",
+			Description: "The maximum lifetime duration of API tokens owned by service accounts. Automation relies on these tokens, so this can be longer than the lifetime allowed for other users.",
+			Flag:        "max-service-account-token-lifetime",
+			Env:         "CODER_MAX_SERVICE_ACCOUNT_TOKEN_LIFETIME",
+			Default:     ((100 * 365 * time.Hour * 24) + (25 * time.Hour * 24)).String(),
+			Value:       &c.MaxServiceAccountTokenLifetime,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "maxServiceAccountTokenLifetime",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Enable swagger endpoint",
 			Description: "Expose the swagger endpoint via /swagger.",
@@ -1733,7 +1866,7 @@ when required by your organization's security policy.`,
 		},
 		{
 			Name:        "SCIM API Key",
-			Description: "Enables SCIM and sets the authentication header for the built-in SCIM server. New users are automatically created with OIDC authentication.",
+			Description: "A static bearer token for the built-in SCIM server. More tokens can be issued and revoked with the SCIM tokens API. New users are automatically created with OIDC authentication.",
 			Flag:        "scim-auth-header",
 			Env:         "CODER_SCIM_AUTH_HEADER",
 			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
@@ -1798,6 +1931,80 @@ when required by your organization's security policy.`,
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disablePasswordAuth",
 		},
+		{
+			Name:        "Login Failure Window",
+			Description: "How far back failed password logins are counted, both for each user and for each IP address.",
+			Flag:        "login-failure-window",
+			Env:         "CODER_LOGIN_FAILURE_WINDOW",
+			Default:     (15 * time.Minute).String(),
+			Value:       &c.LoginLimit.FailureWindow,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginFailureWindow",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Login Free Attempts",
+			Description: "The number of failed password logins allowed within the failure window before further attempts are delayed.",
+			Flag:        "login-free-attempts",
+			Env:         "CODER_LOGIN_FREE_ATTEMPTS",
+			Default:     "3",
+			Value:       &c.LoginLimit.FreeAttempts,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginFreeAttempts",
+		},
+		{
+			Name:        "Login Base Delay",
+			Description: "The delay after the first failed password login past the free attempts. It doubles with every further failure, up to --login-max-delay.",
+			Flag:        "login-base-delay",
+			Env:         "CODER_LOGIN_BASE_DELAY",
+			Default:     time.Second.String(),
+			Value:       &c.LoginLimit.BaseDelay,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginBaseDelay",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Login Max Delay",
+			Description: "The longest delay between failed password logins.",
+			Flag:        "login-max-delay",
+			Env:         "CODER_LOGIN_MAX_DELAY",
+			Default:     (30 * time.Second).String(),
+			Value:       &c.LoginLimit.MaxDelay,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginMaxDelay",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Login Lockout Threshold",
+			Description: "The number of failed password logins of a single user within the failure window that locks their account. Set to 0 to disable lockouts.",
+			Flag:        "login-lockout-threshold",
+			Env:         "CODER_LOGIN_LOCKOUT_THRESHOLD",
+			Default:     "10",
+			Value:       &c.LoginLimit.LockoutThreshold,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginLockoutThreshold",
+		},
+		{
+			Name:        "Login Lockout Duration",
+			Description: "How long an account stays locked after too many failed password logins. Admins can unlock it earlier.",
+			Flag:        "login-lockout-duration",
+			Env:         "CODER_LOGIN_LOCKOUT_DURATION",
+			Default:     (15 * time.Minute).String(),
+			Value:       &c.LoginLimit.LockoutDuration,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginLockoutDuration",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Require TOTP",
+			Description: "Require a TOTP second factor for password logins. Users who have not set one up yet are asked to enroll the next time they log in with their password.",
+			Flag:        "require-totp",
+			Env:         "CODER_REQUIRE_TOTP",
+
+			Value: &c.RequireTOTP,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "requireTOTP",
+		},
 		{
 			Name:          "Config Path",
 			Description:   `Specify a YAML file to load configuration from.`,
@@ -1952,6 +2159,127 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "thresholdDatabase",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		// Notifications Options
+		{
+			Name:        "Notifications: Method",
+			Description: "Which delivery method to use for notifications. Valid values are 'smtp' and 'webhook'. Notifications are not sent if the chosen method is not configured.",
+			Flag:        "notifications-method",
+			Env:         "CODER_NOTIFICATIONS_METHOD",
+			Default:     "smtp",
+			Value:       &c.Notifications.Method,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "method",
+		},
+		{
+			Name:        "Notifications: Dispatch Timeout",
+			Description: "How long a single attempt to deliver a notification may take before it is abandoned and retried.",
+			Flag:        "notifications-dispatch-timeout",
+			Env:         "CODER_NOTIFICATIONS_DISPATCH_TIMEOUT",
+			Default:     time.Minute.String(),
+			Value:       &c.Notifications.DispatchTimeout,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "dispatchTimeout",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Fetch Interval",
+			Description: "How often to check the queue for notifications to deliver.",
+			Flag:        "notifications-fetch-interval",
+			Env:         "CODER_NOTIFICATIONS_FETCH_INTERVAL",
+			Default:     (15 * time.Second).String(),
+			Value:       &c.Notifications.FetchInterval,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "fetchInterval",
+			Hidden:      true,
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Max Send Attempts",
+			Description: "The number of times delivery of a notification is attempted before it is marked as failed.",
+			Flag:        "notifications-max-send-attempts",
+			Env:         "CODER_NOTIFICATIONS_MAX_SEND_ATTEMPTS",
+			Default:     "5",
+			Value:       &c.Notifications.MaxSendAttempts,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "maxSendAttempts",
+		},
+		{
+			Name:        "Notifications: Retry Interval",
+			Description: "The delay before retrying a failed notification. The delay doubles with every subsequent attempt.",
+			Flag:        "notifications-retry-interval",
+			Env:         "CODER_NOTIFICATIONS_RETRY_INTERVAL",
+			Default:     (5 * time.Minute).String(),
+			Value:       &c.Notifications.RetryInterval,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "retryInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Email: From Address",
+			Description: "The sender's address to use.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.SMTP.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications: Email: Smarthost",
+			Description: "The intermediary SMTP host through which emails are sent, as host:port.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Default:     "localhost:587",
+			Value:       &c.Notifications.SMTP.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications: Email: Hello",
+			Description: "The hostname to identify as when greeting the SMTP server.",
+			Flag:        "notifications-email-hello",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_HELLO",
+			Default:     "localhost",
+			Value:       &c.Notifications.SMTP.Hello,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "hello",
+		},
+		{
+			Name:        "Notifications: Email: Username",
+			Description: "Username to authenticate with the SMTP server using PLAIN authentication.",
+			Flag:        "notifications-email-auth-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_USERNAME",
+			Value:       &c.Notifications.SMTP.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "username",
+		},
+		{
+			Name:        "Notifications: Email: Password",
+			Description: "Password to authenticate with the SMTP server using PLAIN authentication.",
+			Flag:        "notifications-email-auth-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_PASSWORD",
+			Value:       &c.Notifications.SMTP.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Notifications: Email: Force TLS",
+			Description: "Connect to the SMTP server over TLS from the start, instead of upgrading the connection with STARTTLS.",
+			Flag:        "notifications-email-force-tls",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FORCE_TLS",
+			Default:     "false",
+			Value:       &c.Notifications.SMTP.ForceTLS,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "forceTLS",
+		},
+		{
+			Name:        "Notifications: Webhook: Endpoint",
+			Description: "The URL notifications are POSTed to as JSON.",
+			Flag:        "notifications-webhook-endpoint",
+			Env:         "CODER_NOTIFICATIONS_WEBHOOK_ENDPOINT",
+			Value:       &c.Notifications.Webhook.Endpoint,
+			Group:       &deploymentGroupNotificationsWebhook,
+			YAML:        "endpoint",
+		},
 	}
 
 	return opts
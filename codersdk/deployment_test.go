@@ -65,6 +65,9 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"External Token Encryption Keys": {
 			yaml: true,
 		},
+		"Notifications: Email: Password": {
+			yaml: true,
+		},
 		"External Auth Providers": {
 			// Technically External Auth Providers can be provided through the env,
 			// but bypassing serpent. See cli.ReadExternalAuthProvidersFromEnv.
@@ -15,13 +15,15 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOIDC GroupSource = "oidc"
+	GroupSourceSCIM GroupSource = "scim"
 )
 
 type CreateGroupRequest struct {
-	Name           string `json:"name"`
-	DisplayName    string `json:"display_name"`
-	AvatarURL      string `json:"avatar_url"`
-	QuotaAllowance int    `json:"quota_allowance"`
+	Name                 string `json:"name"`
+	DisplayName          string `json:"display_name"`
+	AvatarURL            string `json:"avatar_url"`
+	QuotaAllowance       int    `json:"quota_allowance"`
+	MaxWorkspacesPerUser int    `json:"max_workspaces_per_user"`
 }
 
 type Group struct {
@@ -33,6 +35,10 @@ type Group struct {
 	AvatarURL      string        `json:"avatar_url"`
 	QuotaAllowance int           `json:"quota_allowance"`
 	Source         GroupSource   `json:"source"`
+	// MaxWorkspacesPerUser is the maximum number of workspaces each member
+	// of the group can own. Members of several groups get the highest limit.
+	// 0 means the group sets no limit.
+	MaxWorkspacesPerUser int `json:"max_workspaces_per_user"`
 }
 
 func (g Group) IsEveryone() bool {
@@ -109,12 +115,13 @@ func (c *Client) Group(ctx context.Context, group uuid.UUID) (Group, error) {
 }
 
 type PatchGroupRequest struct {
-	AddUsers       []string `json:"add_users"`
-	RemoveUsers    []string `json:"remove_users"`
-	Name           string   `json:"name"`
-	DisplayName    *string  `json:"display_name"`
-	AvatarURL      *string  `json:"avatar_url"`
-	QuotaAllowance *int     `json:"quota_allowance"`
+	AddUsers             []string `json:"add_users"`
+	RemoveUsers          []string `json:"remove_users"`
+	Name                 string   `json:"name"`
+	DisplayName          *string  `json:"display_name"`
+	AvatarURL            *string  `json:"avatar_url"`
+	QuotaAllowance       *int     `json:"quota_allowance"`
+	MaxWorkspacesPerUser *int     `json:"max_workspaces_per_user"`
 }
 
 func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest) (Group, error) {
@@ -0,0 +1,83 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultImpersonationLifetime is used when an impersonation session is
+	// started without a lifetime.
+	DefaultImpersonationLifetime = 30 * time.Minute
+	// MaxImpersonationLifetime is the longest an impersonation session can
+	// last. Impersonation sessions are never extended.
+	MaxImpersonationLifetime = 2 * time.Hour
+)
+
+type ImpersonateUserRequest struct {
+	// Lifetime defaults to 30 minutes and cannot exceed 2 hours.
+	Lifetime time.Duration `json:"lifetime"`
+}
+
+// ImpersonationSession is an API key an owner minted to act as another user.
+type ImpersonationSession struct {
+	ID             string    `json:"id" validate:"required"`
+	UserID         uuid.UUID `json:"user_id" validate:"required" format:"uuid"`
+	ImpersonatorID uuid.UUID `json:"impersonator_id" validate:"required" format:"uuid"`
+	CreatedAt      time.Time `json:"created_at" validate:"required" format:"date-time"`
+	ExpiresAt      time.Time `json:"expires_at" validate:"required" format:"date-time"`
+}
+
+// ImpersonateUserResponse contains the session token for an impersonation
+// session. The token is only returned once.
+type ImpersonateUserResponse struct {
+	ImpersonationSession
+	Key string `json:"key"`
+}
+
+// ImpersonateUser starts an impersonation session for the user. Only owners
+// can impersonate other users.
+func (c *Client) ImpersonateUser(ctx context.Context, user string, req ImpersonateUserRequest) (ImpersonateUserResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/impersonations", user), req)
+	if err != nil {
+		return ImpersonateUserResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ImpersonateUserResponse{}, ReadBodyAsError(res)
+	}
+	var resp ImpersonateUserResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UserImpersonations returns the active impersonation sessions for the user.
+func (c *Client) UserImpersonations(ctx context.Context, user string) ([]ImpersonationSession, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/impersonations", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sessions []ImpersonationSession
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// RevokeImpersonation ends an impersonation session for the user.
+func (c *Client) RevokeImpersonation(ctx context.Context, user string, id string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/impersonations/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserLoginLockout blocks password logins for a user after too many failed
+// attempts. Lockouts expire on their own, or can be lifted by an admin.
+type UserLoginLockout struct {
+	UserID         uuid.UUID `json:"user_id" format:"uuid"`
+	FailedAttempts int32     `json:"failed_attempts"`
+	CreatedAt      time.Time `json:"created_at" format:"date-time"`
+	LockedUntil    time.Time `json:"locked_until" format:"date-time"`
+}
+
+// UserLoginLockout returns the active login lockout for the user.
+func (c *Client) UserLoginLockout(ctx context.Context, user string) (UserLoginLockout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/login-lockout", user), nil)
+	if err != nil {
+		return UserLoginLockout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserLoginLockout{}, ReadBodyAsError(res)
+	}
+	var lockout UserLoginLockout
+	return lockout, json.NewDecoder(res.Body).Decode(&lockout)
+}
+
+// UnlockUserLogin lifts the login lockout for the user and resets their
+// failed login attempts.
+func (c *Client) UnlockUserLogin(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/login-lockout", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NotificationPreference is a user's choice of whether to receive a kind of
+// notification.
+type NotificationPreference struct {
+	Template    string `json:"template"`
+	Description string `json:"description"`
+	Disabled    bool   `json:"disabled"`
+}
+
+// UpdateUserNotificationPreferences enables or disables notification
+// templates by name. Templates that aren't listed are left unchanged.
+type UpdateUserNotificationPreferences struct {
+	TemplateDisabledMap map[string]bool `json:"template_disabled_map" validate:"required"`
+}
+
+// UserNotificationPreferences returns the notification preferences of a user.
+func (c *Client) UserNotificationPreferences(ctx context.Context, user string) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var prefs []NotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
+// UpdateUserNotificationPreferences updates the notification preferences of
+// a user and returns the result.
+func (c *Client) UpdateUserNotificationPreferences(ctx context.Context, user string, req UpdateUserNotificationPreferences) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var prefs []NotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
@@ -0,0 +1,183 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCSyncRule assigns roles and an organization membership to the users
+// whose OIDC claims match a pattern. Rules are applied on every OIDC login,
+// in addition to the role, group and organization mappings of the deployment
+// config.
+type OIDCSyncRule struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// Claim is the name of the claim to match. The claim can be a string or
+	// an array of strings.
+	Claim string `json:"claim"`
+	// Pattern is a regular expression. The rule matches if any value of the
+	// claim matches it. Use ^ and $ to match whole values.
+	Pattern string `json:"pattern"`
+	// SiteRoles are granted to the users matching the rule.
+	SiteRoles []string `json:"site_roles"`
+	// OrganizationID is the organization users matching the rule are made a
+	// member of.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	// OrganizationRoles are granted in the organization of the rule.
+	OrganizationRoles []string  `json:"organization_roles"`
+	CreatedAt         time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt         time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateOIDCSyncRuleRequest struct {
+	Claim             string     `json:"claim" validate:"required"`
+	Pattern           string     `json:"pattern" validate:"required"`
+	SiteRoles         []string   `json:"site_roles"`
+	OrganizationID    *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	OrganizationRoles []string   `json:"organization_roles"`
+}
+
+// UpdateOIDCSyncRuleRequest replaces all fields of a sync rule.
+type UpdateOIDCSyncRuleRequest struct {
+	Claim             string     `json:"claim" validate:"required"`
+	Pattern           string     `json:"pattern" validate:"required"`
+	SiteRoles         []string   `json:"site_roles"`
+	OrganizationID    *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	OrganizationRoles []string   `json:"organization_roles"`
+}
+
+// OIDCSyncDryRunRequest contains sample claims to preview the result of an
+// OIDC login with.
+type OIDCSyncDryRunRequest struct {
+	// Claims are the merged claims of the ID token and the user info
+	// endpoint, as returned by the identity provider.
+	Claims map[string]interface{} `json:"claims"`
+	// Rules replace the stored sync rules for the preview, so rules can be
+	// tested before they are saved. The stored rules are used if empty.
+	Rules []CreateOIDCSyncRuleRequest `json:"rules,omitempty"`
+}
+
+// OIDCSyncDryRunResponse is what an OIDC login with the sample claims would
+// assign to the user. Kinds of sync that are disabled leave the user
+// unchanged.
+type OIDCSyncDryRunResponse struct {
+	// Error is set if the login would be rejected.
+	Error string `json:"error,omitempty"`
+	// MatchedRules are the indexes of the sync rules that matched the
+	// claims, in the rules of the request or, if none were given, in the
+	// stored rules ordered oldest first.
+	MatchedRules []int `json:"matched_rules"`
+	// RoleSyncEnabled is true if the login would replace the site roles of
+	// the user with SiteRoles. Otherwise, SiteRoles are added to the roles of
+	// the user. Site roles are only synced with the user role management
+	// feature.
+	RoleSyncEnabled bool     `json:"role_sync_enabled"`
+	SiteRoles       []string `json:"site_roles"`
+	// GroupSyncEnabled is true if the login would replace the groups of the
+	// user with Groups, in every organization the user is a member of.
+	GroupSyncEnabled bool     `json:"group_sync_enabled"`
+	Groups           []string `json:"groups"`
+	// OrganizationSyncEnabled is true if the login would replace the
+	// organization memberships of the user with Organizations. Otherwise, the
+	// user is added to Organizations.
+	OrganizationSyncEnabled bool                         `json:"organization_sync_enabled"`
+	Organizations           []OIDCSyncDryRunOrganization `json:"organizations"`
+}
+
+type OIDCSyncDryRunOrganization struct {
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// RolesSynced is true if sync rules grant roles in the organization.
+	// The login adds Roles to the roles of the user in the organization.
+	RolesSynced bool     `json:"roles_synced"`
+	Roles       []string `json:"roles"`
+}
+
+// OIDCSyncRules lists the OIDC sync rules, oldest first.
+func (c *Client) OIDCSyncRules(ctx context.Context) ([]OIDCSyncRule, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/oidc/sync-rules", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var rules []OIDCSyncRule
+	return rules, json.NewDecoder(res.Body).Decode(&rules)
+}
+
+// OIDCSyncRule returns an OIDC sync rule by ID.
+func (c *Client) OIDCSyncRule(ctx context.Context, id uuid.UUID) (OIDCSyncRule, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/oidc/sync-rules/%s", id), nil)
+	if err != nil {
+		return OIDCSyncRule{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncRule{}, ReadBodyAsError(res)
+	}
+	var rule OIDCSyncRule
+	return rule, json.NewDecoder(res.Body).Decode(&rule)
+}
+
+// CreateOIDCSyncRule adds an OIDC sync rule. It applies from the next login
+// of each user.
+func (c *Client) CreateOIDCSyncRule(ctx context.Context, req CreateOIDCSyncRuleRequest) (OIDCSyncRule, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/oidc/sync-rules", req)
+	if err != nil {
+		return OIDCSyncRule{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OIDCSyncRule{}, ReadBodyAsError(res)
+	}
+	var rule OIDCSyncRule
+	return rule, json.NewDecoder(res.Body).Decode(&rule)
+}
+
+// UpdateOIDCSyncRule replaces an OIDC sync rule.
+func (c *Client) UpdateOIDCSyncRule(ctx context.Context, id uuid.UUID, req UpdateOIDCSyncRuleRequest) (OIDCSyncRule, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/oidc/sync-rules/%s", id), req)
+	if err != nil {
+		return OIDCSyncRule{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncRule{}, ReadBodyAsError(res)
+	}
+	var rule OIDCSyncRule
+	return rule, json.NewDecoder(res.Body).Decode(&rule)
+}
+
+// DeleteOIDCSyncRule deletes an OIDC sync rule. Roles and memberships granted
+// by the rule are removed on the next login of each user.
+func (c *Client) DeleteOIDCSyncRule(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/oidc/sync-rules/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OIDCSyncDryRun previews the roles, groups and organization memberships an
+// OIDC login with the given claims would assign, without changing anything.
+func (c *Client) OIDCSyncDryRun(ctx context.Context, req OIDCSyncDryRunRequest) (OIDCSyncDryRunResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/oidc/sync-rules/dry-run", req)
+	if err != nil {
+		return OIDCSyncDryRunResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncDryRunResponse{}, ReadBodyAsError(res)
+	}
+	var resp OIDCSyncDryRunResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
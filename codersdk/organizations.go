@@ -23,8 +23,10 @@ const (
 type ProvisionerType string
 
 const (
-	ProvisionerTypeEcho      ProvisionerType = "echo"
-	ProvisionerTypeTerraform ProvisionerType = "terraform"
+	ProvisionerTypeEcho       ProvisionerType = "echo"
+	ProvisionerTypeTerraform  ProvisionerType = "terraform"
+	ProvisionerTypeOpenTofu   ProvisionerType = "opentofu"
+	ProvisionerTypeKubernetes ProvisionerType = "kubernetes"
 )
 
 // Organization is the JSON representation of a Coder organization.
@@ -53,7 +55,7 @@ type CreateTemplateVersionRequest struct {
 	StorageMethod   ProvisionerStorageMethod `json:"storage_method" validate:"oneof=file,required" enums:"file"`
 	FileID          uuid.UUID                `json:"file_id,omitempty" validate:"required_without=ExampleID" format:"uuid"`
 	ExampleID       string                   `json:"example_id,omitempty" validate:"required_without=FileID"`
-	Provisioner     ProvisionerType          `json:"provisioner" validate:"oneof=terraform echo,required"`
+	Provisioner     ProvisionerType          `json:"provisioner" validate:"oneof=terraform opentofu kubernetes echo,required"`
 	ProvisionerTags map[string]string        `json:"tags"`
 
 	UserVariableValues []VariableValue `json:"user_variable_values,omitempty"`
@@ -153,6 +155,13 @@ type CreateWorkspaceRequest struct {
 	// during the initial provision.
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
 	AutomaticUpdates    AutomaticUpdates          `json:"automatic_updates,omitempty"`
+	// TemplateVersionPresetID selects a preset of the template version whose
+	// parameter values are used unless overridden by RichParameterValues.
+	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id,omitempty" format:"uuid"`
+	// SnapshotID restores the workspace from a snapshot of another workspace
+	// of the same template. The artifacts of the snapshot are passed to every
+	// build of the new workspace.
+	SnapshotID uuid.UUID `json:"snapshot_id,omitempty" format:"uuid"`
 }
 
 func (c *Client) OrganizationByName(ctx context.Context, name string) (Organization, error) {
@@ -176,11 +185,21 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return c.OrganizationByName(ctx, id.String())
 }
 
-// ProvisionerDaemons returns provisioner daemons available.
+// ProvisionerDaemons returns the provisioner daemons of the default
+// organization.
 func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
+	return c.provisionerDaemons(ctx, DefaultOrganization)
+}
+
+// OrganizationProvisionerDaemons returns the provisioner daemons of the
+// organization.
+func (c *Client) OrganizationProvisionerDaemons(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error) {
+	return c.provisionerDaemons(ctx, organizationID.String())
+}
+
+func (c *Client) provisionerDaemons(ctx context.Context, organization string) ([]ProvisionerDaemon, error) {
 	res, err := c.Request(ctx, http.MethodGet,
-		// TODO: the organization path parameter is currently ignored.
-		"/api/v2/organizations/default/provisionerdaemons",
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons", organization),
 		nil,
 	)
 	if err != nil {
@@ -186,6 +186,11 @@ type ServeProvisionerDaemonRequest struct {
 	Provisioners []ProvisionerType `json:"provisioners"`
 	// Tags is a map of key-value pairs that tag the jobs this provisioner daemon can handle
 	Tags map[string]string `json:"tags"`
+	// EngineVersions are the installed versions of the engine of each
+	// provisioner type the daemon does not download versions of. Jobs of
+	// templates that constrain the engine version with required_version are
+	// then only assigned to the daemon if one of them satisfies it.
+	EngineVersions map[ProvisionerType][]string `json:"engine_versions"`
 	// PreSharedKey is an authentication key to use on the API instead of the normal session token from the client.
 	PreSharedKey string `json:"pre_shared_key"`
 }
@@ -215,6 +220,11 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 	for key, value := range req.Tags {
 		query.Add("tag", fmt.Sprintf("%s=%s", key, value))
 	}
+	for provisioner, versions := range req.EngineVersions {
+		for _, version := range versions {
+			query.Add("engine_version", fmt.Sprintf("%s=%s", provisioner, version))
+		}
+	}
 	serverURL.RawQuery = query.Encode()
 	httpClient := &http.Client{
 		Transport: c.HTTPClient.Transport,
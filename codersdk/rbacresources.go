@@ -27,6 +27,7 @@ const (
 	ResourceDebugInfo                    RBACResource = "debug_info"
 	ResourceSystem                       RBACResource = "system"
 	ResourceTemplateInsights             RBACResource = "template_insights"
+	ResourceCustomRole                   RBACResource = "custom_role"
 )
 
 const (
@@ -62,6 +63,7 @@ var (
 		ResourceDebugInfo,
 		ResourceSystem,
 		ResourceTemplateInsights,
+		ResourceCustomRole,
 	}
 
 	AllRBACActions = []string{
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -46,3 +47,121 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// Permission allows an action on a resource type, or denies it if Negate is
+// set. The resource type and action can be "*" to match any.
+type Permission struct {
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action" enums:"create,read,update,delete,*"`
+}
+
+// CustomRole is a role defined at runtime by an administrator, in addition
+// to the built in roles.
+type CustomRole struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// Name is the name the role is assigned with. Organization roles are
+	// suffixed with ":<organization_id>", like the built in ones.
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	// OrganizationID is only set for organization roles.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	// SitePermissions apply to all resources. Only site roles have them.
+	SitePermissions []Permission `json:"site_permissions"`
+	// OrganizationPermissions apply to the resources of the organization of
+	// the role. Only organization roles have them.
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	// UserPermissions apply to the resources owned by the users with the role.
+	UserPermissions []Permission `json:"user_permissions"`
+	CreatedAt       time.Time    `json:"created_at" format:"date-time"`
+	UpdatedAt       time.Time    `json:"updated_at" format:"date-time"`
+}
+
+type CreateCustomRoleRequest struct {
+	Name        string `json:"name" validate:"required,role_name"`
+	DisplayName string `json:"display_name"`
+	// OrganizationID creates an organization role instead of a site role.
+	OrganizationID          *uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
+// UpdateCustomRoleRequest replaces the display name and permissions of a
+// custom role.
+type UpdateCustomRoleRequest struct {
+	DisplayName             string       `json:"display_name"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	UserPermissions         []Permission `json:"user_permissions"`
+}
+
+// CustomRoles lists the custom site and organization roles.
+func (c *Client) CustomRoles(ctx context.Context) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/roles", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// CustomRole returns a custom role by the name it is assigned with.
+func (c *Client) CustomRole(ctx context.Context, name string) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/roles/%s", name), nil)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// CreateCustomRole creates a custom site or organization role.
+func (c *Client) CreateCustomRole(ctx context.Context, req CreateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/roles", req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// UpdateCustomRole replaces the display name and permissions of a custom
+// role. The change applies to all users with the role.
+func (c *Client) UpdateCustomRole(ctx context.Context, name string, req UpdateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/roles/%s", name), req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// DeleteCustomRole deletes a custom role and unassigns it from all users.
+func (c *Client) DeleteCustomRole(ctx context.Context, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/roles/%s", name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
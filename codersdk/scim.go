@@ -0,0 +1,77 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SCIMToken is a bearer token an identity provider uses to authenticate
+// against the SCIM API.
+type SCIMToken struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+	LastUsedAt NullTime  `json:"last_used_at"`
+	// TokenTruncated is the end of the token, so tokens can be told apart.
+	TokenTruncated string `json:"token_truncated"`
+}
+
+// SCIMTokenFull contains the token itself. It is only returned when the
+// token is created.
+type SCIMTokenFull struct {
+	ID    uuid.UUID `json:"id" format:"uuid"`
+	Name  string    `json:"name"`
+	Token string    `json:"token"`
+}
+
+type CreateSCIMTokenRequest struct {
+	// Name tells tokens apart, for example the name of the identity provider
+	// the token is for.
+	Name string `json:"name" validate:"required"`
+}
+
+// SCIMTokens lists the SCIM tokens without their secrets.
+func (c *Client) SCIMTokens(ctx context.Context) ([]SCIMToken, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/scim/tokens", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var tokens []SCIMToken
+	return tokens, json.NewDecoder(res.Body).Decode(&tokens)
+}
+
+// CreateSCIMToken creates a SCIM token. The token cannot be retrieved later.
+func (c *Client) CreateSCIMToken(ctx context.Context, req CreateSCIMTokenRequest) (SCIMTokenFull, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/scim/tokens", req)
+	if err != nil {
+		return SCIMTokenFull{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return SCIMTokenFull{}, ReadBodyAsError(res)
+	}
+	var token SCIMTokenFull
+	return token, json.NewDecoder(res.Body).Decode(&token)
+}
+
+// DeleteSCIMToken revokes a SCIM token.
+func (c *Client) DeleteSCIMToken(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/scim/tokens/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
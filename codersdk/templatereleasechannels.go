@@ -0,0 +1,126 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateReleaseChannel is a named version of a template used by the
+// workspaces of the assigned users and groups instead of the active version.
+// A user assigned directly takes precedence over an assignment through a
+// group, and if several channels match the groups of a user the first by name
+// is used.
+type TemplateReleaseChannel struct {
+	ID                  uuid.UUID   `json:"id" format:"uuid"`
+	TemplateID          uuid.UUID   `json:"template_id" format:"uuid"`
+	Name                string      `json:"name"`
+	TemplateVersionID   uuid.UUID   `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string      `json:"template_version_name"`
+	UserIDs             []uuid.UUID `json:"user_ids" format:"uuid"`
+	GroupIDs            []uuid.UUID `json:"group_ids" format:"uuid"`
+	CreatedAt           time.Time   `json:"created_at" format:"date-time"`
+	UpdatedAt           time.Time   `json:"updated_at" format:"date-time"`
+}
+
+type CreateTemplateReleaseChannelRequest struct {
+	Name              string      `json:"name" validate:"required,release_channel_name"`
+	TemplateVersionID uuid.UUID   `json:"template_version_id" validate:"required" format:"uuid"`
+	UserIDs           []uuid.UUID `json:"user_ids,omitempty" format:"uuid"`
+	GroupIDs          []uuid.UUID `json:"group_ids,omitempty" format:"uuid"`
+}
+
+// UpdateTemplateReleaseChannelRequest updates the fields that are set. The
+// user and group lists replace the existing assignments.
+type UpdateTemplateReleaseChannelRequest struct {
+	TemplateVersionID *uuid.UUID   `json:"template_version_id,omitempty" format:"uuid"`
+	UserIDs           *[]uuid.UUID `json:"user_ids,omitempty" format:"uuid"`
+	GroupIDs          *[]uuid.UUID `json:"group_ids,omitempty" format:"uuid"`
+}
+
+// TemplateUserActiveVersion is the version of a template new builds of a
+// user's workspaces are expected to use.
+type TemplateUserActiveVersion struct {
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	// ReleaseChannel is the name of the channel the version comes from. It is
+	// empty if the user is not assigned to a channel and the version is the
+	// active version of the template.
+	ReleaseChannel string `json:"release_channel,omitempty"`
+}
+
+// TemplateReleaseChannels lists the release channels of a template.
+func (c *Client) TemplateReleaseChannels(ctx context.Context, template uuid.UUID) ([]TemplateReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/channels", template), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var channels []TemplateReleaseChannel
+	return channels, json.NewDecoder(res.Body).Decode(&channels)
+}
+
+// CreateTemplateReleaseChannel creates a release channel for a template.
+func (c *Client) CreateTemplateReleaseChannel(ctx context.Context, template uuid.UUID, req CreateTemplateReleaseChannelRequest) (TemplateReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/channels", template), req)
+	if err != nil {
+		return TemplateReleaseChannel{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateReleaseChannel{}, ReadBodyAsError(res)
+	}
+	var channel TemplateReleaseChannel
+	return channel, json.NewDecoder(res.Body).Decode(&channel)
+}
+
+// UpdateTemplateReleaseChannel updates the release channel of a template with
+// the given name.
+func (c *Client) UpdateTemplateReleaseChannel(ctx context.Context, template uuid.UUID, name string, req UpdateTemplateReleaseChannelRequest) (TemplateReleaseChannel, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/channels/%s", template, name), req)
+	if err != nil {
+		return TemplateReleaseChannel{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateReleaseChannel{}, ReadBodyAsError(res)
+	}
+	var channel TemplateReleaseChannel
+	return channel, json.NewDecoder(res.Body).Decode(&channel)
+}
+
+// DeleteTemplateReleaseChannel deletes the release channel of a template with
+// the given name. Its users go back to the active version of the template.
+func (c *Client) DeleteTemplateReleaseChannel(ctx context.Context, template uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/channels/%s", template, name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TemplateActiveVersionForUser returns the version of a template new builds
+// of the user's workspaces are expected to use, taking the release channels
+// of the template into account. The user can be a username, a UUID or "me".
+func (c *Client) TemplateActiveVersionForUser(ctx context.Context, template uuid.UUID, user string) (TemplateUserActiveVersion, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/users/%s/active-version", template, user), nil)
+	if err != nil {
+		return TemplateUserActiveVersion{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateUserActiveVersion{}, ReadBodyAsError(res)
+	}
+	var version TemplateUserActiveVersion
+	return version, json.NewDecoder(res.Body).Decode(&version)
+}
@@ -58,6 +58,9 @@ type Template struct {
 	// template version.
 	RequireActiveVersion bool                         `json:"require_active_version"`
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
+	// MaxWorkspaces is the maximum number of workspaces that can be created
+	// from the template. 0 means no limit.
+	MaxWorkspaces int32 `json:"max_workspaces"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -245,6 +248,9 @@ type UpdateTemplateMeta struct {
 	// of the template.
 	DisableEveryoneGroupAccess bool                          `json:"disable_everyone_group_access"`
 	MaxPortShareLevel          *WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
+	// MaxWorkspaces limits the number of workspaces that can be created from
+	// the template. 0 removes the limit, and nil keeps the current limit.
+	MaxWorkspaces *int32 `json:"max_workspaces,omitempty"`
 }
 
 type TemplateExample struct {
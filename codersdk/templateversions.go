@@ -90,6 +90,44 @@ type TemplateVersionVariable struct {
 	Sensitive    bool   `json:"sensitive"`
 }
 
+type TemplateVersionDiagnosticSeverity string
+
+const (
+	TemplateVersionDiagnosticSeverityWarning TemplateVersionDiagnosticSeverity = "warning"
+	TemplateVersionDiagnosticSeverityError   TemplateVersionDiagnosticSeverity = "error"
+)
+
+// TemplateVersionDiagnostic is a problem found by statically checking the
+// source of a template version.
+type TemplateVersionDiagnostic struct {
+	Severity TemplateVersionDiagnosticSeverity `json:"severity" enums:"warning,error"`
+	// Code identifies the check that found the problem.
+	Code    string `json:"code"`
+	Summary string `json:"summary"`
+	Detail  string `json:"detail"`
+	// Filename is relative to the root of the template.
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+}
+
+// Preset is a named set of parameter values declared by a template version.
+// Users can pick a preset when creating a workspace instead of entering each
+// value.
+type Preset struct {
+	ID         uuid.UUID         `json:"id" format:"uuid"`
+	Name       string            `json:"name"`
+	Parameters []PresetParameter `json:"parameters"`
+	// Prebuilds is the number of workspaces kept built with the preset while
+	// its template version is active. Creating a workspace with the preset
+	// claims one of them if it is ready.
+	Prebuilds int32 `json:"prebuilds"`
+}
+
+type PresetParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 type PatchTemplateVersionRequest struct {
 	Name    string  `json:"name" validate:"omitempty,template_version_name"`
 	Message *string `json:"message,omitempty" validate:"omitempty,lt=1048577"`
@@ -178,6 +216,35 @@ func (c *Client) TemplateVersionVariables(ctx context.Context, version uuid.UUID
 	return variables, json.NewDecoder(res.Body).Decode(&variables)
 }
 
+// TemplateVersionDiagnostics returns the problems found in the source of a
+// template version when it was imported.
+func (c *Client) TemplateVersionDiagnostics(ctx context.Context, version uuid.UUID) ([]TemplateVersionDiagnostic, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/diagnostics", version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var diagnostics []TemplateVersionDiagnostic
+	return diagnostics, json.NewDecoder(res.Body).Decode(&diagnostics)
+}
+
+// TemplateVersionPresets returns the presets declared by a template version.
+func (c *Client) TemplateVersionPresets(ctx context.Context, version uuid.UUID) ([]Preset, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/presets", version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var presets []Preset
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
+
 // TemplateVersionLogsAfter streams logs for a template version that occurred after a specific log ID.
 func (c *Client) TemplateVersionLogsAfter(ctx context.Context, version uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/templateversions/%s/logs", version), after)
@@ -0,0 +1,150 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// UserTOTP reports whether a user has a TOTP second factor for password logins.
+type UserTOTP struct {
+	Enabled bool `json:"enabled"`
+	// Required is set when the deployment requires a second factor for all
+	// password logins.
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollment holds the secret for a new TOTP second factor. It is not used
+// for logins until it is confirmed with a valid code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is the otpauth:// URL of the secret, for authenticator apps that
+	// scan QR codes.
+	URL string `json:"url"`
+}
+
+type VerifyTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TOTPRecoveryCodes are only shown once, when enrollment is confirmed. Each
+// code can be used once in place of a TOTP code.
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ResetTOTPRequest is needed when users remove their own enabled second
+// factor. Admins resetting another user leave it empty.
+type ResetTOTPRequest struct {
+	// Code is a TOTP code or a recovery code.
+	Code string `json:"code"`
+}
+
+// LoginWithTOTPRequest finishes a password login that needs a second factor.
+type LoginWithTOTPRequest struct {
+	// Ticket is the totp_ticket from the password login.
+	Ticket string `json:"ticket" validate:"required"`
+	// Code is a TOTP code or a recovery code.
+	Code string `json:"code" validate:"required"`
+}
+
+type LoginTOTPEnrollRequest struct {
+	// Ticket is the totp_ticket from the password login.
+	Ticket string `json:"ticket" validate:"required"`
+}
+
+// LoginWithTOTPResponse contains a session token for the newly authenticated
+// user.
+type LoginWithTOTPResponse struct {
+	SessionToken string `json:"session_token"`
+	// RecoveryCodes are set when the login confirmed a required enrollment.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// LoginWithTOTP exchanges the ticket from LoginWithPassword and a TOTP or
+// recovery code for a session token.
+func (c *Client) LoginWithTOTP(ctx context.Context, req LoginWithTOTPRequest) (LoginWithTOTPResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/totp", req)
+	if err != nil {
+		return LoginWithTOTPResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithTOTPResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithTOTPResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginTOTPEnroll starts the enrollment that the deployment requires before a
+// user can finish logging in. The enrollment is confirmed by LoginWithTOTP.
+func (c *Client) LoginTOTPEnroll(ctx context.Context, req LoginTOTPEnrollRequest) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/totp/enroll", req)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// UserTOTP returns the TOTP status of the user.
+func (c *Client) UserTOTP(ctx context.Context, user string) (UserTOTP, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/totp", user), nil)
+	if err != nil {
+		return UserTOTP{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserTOTP{}, ReadBodyAsError(res)
+	}
+	var totp UserTOTP
+	return totp, json.NewDecoder(res.Body).Decode(&totp)
+}
+
+// EnrollTOTP starts enrolling the user in TOTP. The enrollment has to be
+// confirmed with VerifyTOTP.
+func (c *Client) EnrollTOTP(ctx context.Context, user string) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/totp", user), nil)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// VerifyTOTP confirms a TOTP enrollment and returns the recovery codes.
+func (c *Client) VerifyTOTP(ctx context.Context, user string, req VerifyTOTPRequest) (TOTPRecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/totp/verify", user), req)
+	if err != nil {
+		return TOTPRecoveryCodes{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TOTPRecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes TOTPRecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// ResetTOTP removes the user's TOTP second factor.
+func (c *Client) ResetTOTP(ctx context.Context, user string, req ResetTOTPRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/totp", user), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -56,6 +56,9 @@ type ReducedUser struct {
 	Status          UserStatus `json:"status" table:"status" enums:"active,suspended"`
 	LoginType       LoginType  `json:"login_type"`
 	ThemePreference string     `json:"theme_preference"`
+	// IsServiceAccount is set for non-human users that only authenticate
+	// with API tokens.
+	IsServiceAccount bool `json:"is_service_account,omitempty" table:"service account"`
 }
 
 // User represents a user in Coder.
@@ -122,6 +125,9 @@ type CreateUserRequest struct {
 	// Deprecated: Set UserLoginType=LoginTypeDisabled instead.
 	DisableLogin   bool      `json:"disable_login"`
 	OrganizationID uuid.UUID `json:"organization_id" validate:"" format:"uuid"`
+	// ServiceAccount creates a non-human user that cannot log in and only
+	// authenticates with API tokens. The login type must be empty or 'none'.
+	ServiceAccount bool `json:"service_account,omitempty"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -192,8 +198,15 @@ type LoginWithPasswordRequest struct {
 }
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
+// Users who need a TOTP second factor get a TOTPTicket instead, which is
+// exchanged for a session token with LoginWithTOTP.
 type LoginWithPasswordResponse struct {
-	SessionToken string `json:"session_token" validate:"required"`
+	SessionToken string `json:"session_token"`
+	TOTPTicket   string `json:"totp_ticket,omitempty"`
+	// TOTPEnrollmentRequired is set when the deployment requires a second
+	// factor that the user has not set up yet. The ticket can be used to
+	// enroll with LoginTOTPEnroll.
+	TOTPEnrollmentRequired bool `json:"totp_enrollment_required,omitempty"`
 }
 
 type OAuthConversionResponse struct {
@@ -412,6 +425,35 @@ func (c *Client) UpdateOrganizationMemberRoles(ctx context.Context, organization
 	return member, json.NewDecoder(res.Body).Decode(&member)
 }
 
+// PostOrganizationMember adds the user to the organization without any
+// organization roles.
+func (c *Client) PostOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) (OrganizationMember, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return OrganizationMember{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OrganizationMember{}, ReadBodyAsError(res)
+	}
+	var member OrganizationMember
+	return member, json.NewDecoder(res.Body).Decode(&member)
+}
+
+// DeleteOrganizationMember removes the user from the organization and from
+// all of the organization's groups.
+func (c *Client) DeleteOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // UserRoles returns all roles the user has
 func (c *Client) UserRoles(ctx context.Context, user string) (UserRoles, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/roles", user), nil)
@@ -434,7 +476,8 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 		return LoginWithPasswordResponse{}, err
 	}
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusCreated {
+	// Accepted means a second factor is needed to finish logging in.
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
 		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
 	}
 	var resp LoginWithPasswordResponse
@@ -0,0 +1,84 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type UserSessionType string
+
+const (
+	// UserSessionTypeSession is a login from the dashboard or the CLI.
+	UserSessionTypeSession UserSessionType = "session"
+	// UserSessionTypeToken is a token created with `coder tokens create`.
+	UserSessionTypeToken UserSessionType = "token"
+	// UserSessionTypeApp is a key created to access workspace apps.
+	UserSessionTypeApp UserSessionType = "app"
+)
+
+// UserSession is an unexpired API key of a user, such as a browser session,
+// a CLI login or a token.
+type UserSession struct {
+	APIKey
+	Type UserSessionType `json:"type" enums:"session,token,app"`
+	// IPAddress is the address the key was last used from.
+	IPAddress string `json:"ip_address"`
+	// UserAgent is the user agent of the client that created the key.
+	UserAgent string `json:"user_agent"`
+	// Impersonated is set for sessions an owner started to act as the user.
+	Impersonated bool `json:"impersonated"`
+	// Current is set for the session that made the request.
+	Current bool `json:"current"`
+}
+
+// RevokeUserSessionsResponse is returned after revoking several sessions.
+type RevokeUserSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// UserSessions returns the unexpired API keys of the user, most recently used
+// first.
+func (c *Client) UserSessions(ctx context.Context, user string) ([]UserSession, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/sessions", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sessions []UserSession
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// RevokeUserSession revokes one API key of the user, ending any requests
+// still using it on every replica.
+func (c *Client) RevokeUserSession(ctx context.Context, user string, id string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/sessions/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RevokeOtherUserSessions revokes all API keys of the user except the one
+// making the request. Revoking the sessions of another user logs them out
+// everywhere. Tokens are only revoked if includeTokens is set.
+func (c *Client) RevokeOtherUserSessions(ctx context.Context, user string, includeTokens bool) (RevokeUserSessionsResponse, error) {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/sessions?include_tokens=%t", user, includeTokens), nil)
+	if err != nil {
+		return RevokeUserSessionsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return RevokeUserSessionsResponse{}, ReadBodyAsError(res)
+	}
+	var resp RevokeUserSessionsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,291 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is the kind of event a webhook delivery reports.
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build.started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build.succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build.failed"
+	WebhookEventWorkspaceCreated        WebhookEvent = "workspace.created"
+	WebhookEventWorkspaceDeleted        WebhookEvent = "workspace.deleted"
+	WebhookEventTemplateVersionPushed   WebhookEvent = "template_version.pushed"
+	WebhookEventTemplateVersionPromoted WebhookEvent = "template_version.promoted"
+	WebhookEventUserCreated             WebhookEvent = "user.created"
+	WebhookEventUserSuspended           WebhookEvent = "user.suspended"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceBuildStarted,
+	WebhookEventWorkspaceBuildSucceeded,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventWorkspaceCreated,
+	WebhookEventWorkspaceDeleted,
+	WebhookEventTemplateVersionPushed,
+	WebhookEventTemplateVersionPromoted,
+	WebhookEventUserCreated,
+	WebhookEventUserSuspended,
+}
+
+// Valid reports whether e is a known event.
+func (e WebhookEvent) Valid() bool {
+	return slices.Contains(WebhookEvents, e)
+}
+
+// Headers set on every webhook request.
+const (
+	// WebhookEventHeader holds the event of the delivery.
+	WebhookEventHeader = "X-Coder-Event"
+	// WebhookDeliveryHeader holds the ID of the delivery. Redeliveries get
+	// a new ID, while the payload keeps the ID of the original event.
+	WebhookDeliveryHeader = "X-Coder-Delivery"
+	// WebhookSignatureHeader holds "sha256=" followed by the hex encoded
+	// HMAC-SHA256 of the request body, keyed with the webhook secret. It is
+	// only set if the webhook has a secret.
+	WebhookSignatureHeader = "X-Coder-Signature-256"
+)
+
+// Webhook is an endpoint that is sent a request whenever one of the events
+// it subscribes to happens.
+type Webhook struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+	URL  string    `json:"url"`
+	// Events the webhook is subscribed to. An empty list subscribes to
+	// every event.
+	Events []WebhookEvent `json:"events"`
+	// HasSecret is true if requests are signed. The secret itself is never
+	// returned.
+	HasSecret bool      `json:"has_secret"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateWebhookRequest struct {
+	Name   string         `json:"name" validate:"required"`
+	URL    string         `json:"url" validate:"required,http_url"`
+	Events []WebhookEvent `json:"events"`
+	// Secret is used to sign requests. Leave empty to send unsigned
+	// requests.
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookRequest changes the fields that are set and leaves the rest
+// unchanged. Setting Secret to an empty string stops signing requests.
+type UpdateWebhookRequest struct {
+	Name    *string         `json:"name,omitempty"`
+	URL     *string         `json:"url,omitempty" validate:"omitempty,http_url"`
+	Events  *[]WebhookEvent `json:"events,omitempty"`
+	Secret  *string         `json:"secret,omitempty"`
+	Enabled *bool           `json:"enabled,omitempty"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending          WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusLeased           WebhookDeliveryStatus = "leased"
+	WebhookDeliveryStatusSucceeded        WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusTemporaryFailure WebhookDeliveryStatus = "temporary_failure"
+	WebhookDeliveryStatusPermanentFailure WebhookDeliveryStatus = "permanent_failure"
+)
+
+// WebhookDelivery is a single event sent, or waiting to be sent, to a
+// webhook.
+type WebhookDelivery struct {
+	ID           uuid.UUID             `json:"id" format:"uuid"`
+	WebhookID    uuid.UUID             `json:"webhook_id" format:"uuid"`
+	Event        WebhookEvent          `json:"event"`
+	Payload      json.RawMessage       `json:"payload"`
+	Status       WebhookDeliveryStatus `json:"status"`
+	AttemptCount int32                 `json:"attempt_count"`
+	// ResponseStatus and ResponseBody describe the response to the last
+	// attempt, if the endpoint responded. ResponseBody is truncated.
+	ResponseStatus   int32      `json:"response_status,omitempty"`
+	ResponseBody     string     `json:"response_body,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt        time.Time  `json:"updated_at" format:"date-time"`
+	NextAttemptAfter *time.Time `json:"next_attempt_after,omitempty" format:"date-time"`
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty" format:"date-time"`
+}
+
+// WebhookPayload is the body of every webhook request. Data is one of the
+// Webhook*Data types, depending on the event.
+type WebhookPayload struct {
+	// ID identifies the event. It is shared by the deliveries of the event
+	// to every webhook and kept on redelivery, so receivers can use it to
+	// discard duplicates.
+	ID        uuid.UUID       `json:"id" format:"uuid"`
+	Event     WebhookEvent    `json:"event"`
+	Timestamp time.Time       `json:"timestamp" format:"date-time"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookWorkspaceBuildData is the data of workspace_build events.
+type WebhookWorkspaceBuildData struct {
+	BuildID           uuid.UUID           `json:"build_id" format:"uuid"`
+	BuildNumber       int32               `json:"build_number"`
+	Transition        WorkspaceTransition `json:"transition"`
+	Reason            BuildReason         `json:"reason"`
+	WorkspaceID       uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName     string              `json:"workspace_name"`
+	OwnerID           uuid.UUID           `json:"owner_id" format:"uuid"`
+	OwnerName         string              `json:"owner_name"`
+	TemplateID        uuid.UUID           `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID           `json:"template_version_id" format:"uuid"`
+	// Error is set for workspace_build.failed events.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookWorkspaceData is the data of workspace events.
+type WebhookWorkspaceData struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerID       uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerName     string    `json:"owner_name"`
+	TemplateID    uuid.UUID `json:"template_id" format:"uuid"`
+}
+
+// WebhookTemplateVersionData is the data of template_version events.
+type WebhookTemplateVersionData struct {
+	TemplateVersionID   uuid.UUID `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string    `json:"template_version_name"`
+	// TemplateID is the nil UUID for versions pushed before their template
+	// is created.
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// InitiatorID is the user that pushed or promoted the version.
+	InitiatorID   uuid.UUID `json:"initiator_id" format:"uuid"`
+	InitiatorName string    `json:"initiator_name"`
+}
+
+// WebhookUserData is the data of user events.
+type WebhookUserData struct {
+	UserID   uuid.UUID `json:"user_id" format:"uuid"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	// InitiatorID is the user that created or suspended the user, or the
+	// nil UUID if it happened automatically, for example on first login
+	// through OIDC.
+	InitiatorID uuid.UUID `json:"initiator_id" format:"uuid"`
+}
+
+// Webhooks returns every configured webhook.
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+// Webhook returns a webhook by ID.
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// CreateWebhook adds a webhook. It is enabled right away.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// UpdateWebhook changes the settings of a webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/webhooks/%s", id), req)
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log.
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the most recent deliveries of a webhook, newest
+// first. A limit of zero uses the server default.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil,
+		func(r *http.Request) {
+			if limit > 0 {
+				q := r.URL.Query()
+				q.Set("limit", fmt.Sprint(limit))
+				r.URL.RawQuery = q.Encode()
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
+
+// RedeliverWebhookDelivery queues a new delivery with the payload of an
+// earlier one.
+func (c *Client) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/webhooks/%s/deliveries/%s/redeliver", webhookID, deliveryID), nil)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WebhookDelivery{}, ReadBodyAsError(res)
+	}
+	var delivery WebhookDelivery
+	return delivery, json.NewDecoder(res.Body).Decode(&delivery)
+}
@@ -103,6 +103,32 @@ type WorkspaceBuildParameter struct {
 	Value string `json:"value"`
 }
 
+// WorkspaceBuildState summarizes the provisioner state stored for a
+// workspace build.
+type WorkspaceBuildState struct {
+	BuildID     uuid.UUID            `json:"build_id" format:"uuid"`
+	BuildNumber int32                `json:"build_number"`
+	Transition  WorkspaceTransition  `json:"transition" enums:"start,stop,delete"`
+	Status      ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	InitiatorID uuid.UUID            `json:"initiator_id" format:"uuid"`
+	CreatedAt   time.Time            `json:"created_at" format:"date-time"`
+	// Size is the size of the stored state in bytes. It is zero if the build
+	// has no state, or if the state was purged because the build is too old.
+	Size int32 `json:"size"`
+	// Hash is the hex-encoded SHA-256 checksum of the stored state.
+	Hash string `json:"hash"`
+}
+
+// WorkspaceBuildStateDiff lists the Terraform resource addresses that differ
+// between the provisioner state of two workspace builds.
+type WorkspaceBuildStateDiff struct {
+	FromBuildID uuid.UUID `json:"from_build_id" format:"uuid"`
+	ToBuildID   uuid.UUID `json:"to_build_id" format:"uuid"`
+	Added       []string  `json:"added"`
+	Removed     []string  `json:"removed"`
+	Changed     []string  `json:"changed"`
+}
+
 // WorkspaceBuild returns a single workspace build for a workspace.
 // If history is "", the latest version is returned.
 func (c *Client) WorkspaceBuild(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
@@ -149,6 +175,36 @@ func (c *Client) WorkspaceBuildState(ctx context.Context, build uuid.UUID) ([]by
 	return io.ReadAll(res.Body)
 }
 
+// WorkspaceBuildStateDiff compares the provisioner state of the build
+// against the state of the build with the ID "from".
+func (c *Client) WorkspaceBuildStateDiff(ctx context.Context, build uuid.UUID, from uuid.UUID) (WorkspaceBuildStateDiff, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/state/diff?from=%s", build, from), nil)
+	if err != nil {
+		return WorkspaceBuildStateDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildStateDiff{}, ReadBodyAsError(res)
+	}
+	var diff WorkspaceBuildStateDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
+
+// WorkspaceBuildStates returns a summary of the provisioner state stored for
+// every build of a workspace, newest first.
+func (c *Client) WorkspaceBuildStates(ctx context.Context, workspace uuid.UUID) ([]WorkspaceBuildState, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/builds/states", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var states []WorkspaceBuildState
+	return states, json.NewDecoder(res.Body).Decode(&states)
+}
+
 func (c *Client) WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx context.Context, username string, workspaceName string, buildNumber string) (WorkspaceBuild, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/workspace/%s/builds/%s", username, workspaceName, buildNumber), nil)
 	if err != nil {
@@ -0,0 +1,135 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspaceBulkAction is what a bulk operation does to every workspace.
+type WorkspaceBulkAction string
+
+const (
+	WorkspaceBulkActionStart  WorkspaceBulkAction = "start"
+	WorkspaceBulkActionStop   WorkspaceBulkAction = "stop"
+	WorkspaceBulkActionUpdate WorkspaceBulkAction = "update"
+	WorkspaceBulkActionDelete WorkspaceBulkAction = "delete"
+)
+
+// WorkspaceBulkOperationStatus is the status of an operation. Operations
+// returned by a dry run have the dry_run status and are never persisted.
+type WorkspaceBulkOperationStatus string
+
+const (
+	WorkspaceBulkOperationStatusRunning   WorkspaceBulkOperationStatus = "running"
+	WorkspaceBulkOperationStatusCompleted WorkspaceBulkOperationStatus = "completed"
+	WorkspaceBulkOperationStatusCanceled  WorkspaceBulkOperationStatus = "canceled"
+	WorkspaceBulkOperationStatusDryRun    WorkspaceBulkOperationStatus = "dry_run"
+)
+
+// Done reports whether the operation won't build any more workspaces.
+func (s WorkspaceBulkOperationStatus) Done() bool {
+	return s != WorkspaceBulkOperationStatusRunning
+}
+
+// WorkspaceBulkResultStatus is the outcome of an operation for a workspace.
+// Pending workspaces wait for earlier builds of the operation to finish.
+// Skipped workspaces weren't built, because the action had nothing to do or
+// couldn't be performed.
+type WorkspaceBulkResultStatus string
+
+const (
+	WorkspaceBulkResultStatusPending   WorkspaceBulkResultStatus = "pending"
+	WorkspaceBulkResultStatusRunning   WorkspaceBulkResultStatus = "running"
+	WorkspaceBulkResultStatusSucceeded WorkspaceBulkResultStatus = "succeeded"
+	WorkspaceBulkResultStatusFailed    WorkspaceBulkResultStatus = "failed"
+	WorkspaceBulkResultStatusSkipped   WorkspaceBulkResultStatus = "skipped"
+)
+
+// WorkspaceBulkOperation builds every workspace matching a search query.
+type WorkspaceBulkOperation struct {
+	ID          uuid.UUID           `json:"id" format:"uuid"`
+	InitiatorID uuid.UUID           `json:"initiator_id" format:"uuid"`
+	Action      WorkspaceBulkAction `json:"action" enums:"start,stop,update,delete"`
+	// Query is the workspace search query the workspaces were selected
+	// with.
+	Query string `json:"q"`
+	// Concurrency is the maximum number of builds in progress at once.
+	Concurrency int32                        `json:"concurrency"`
+	Status      WorkspaceBulkOperationStatus `json:"status" enums:"running,completed,canceled,dry_run"`
+	CreatedAt   time.Time                    `json:"created_at" format:"date-time"`
+	UpdatedAt   time.Time                    `json:"updated_at" format:"date-time"`
+	CompletedAt *time.Time                   `json:"completed_at,omitempty" format:"date-time"`
+	// Workspaces are sorted by owner and name.
+	Workspaces []WorkspaceBulkOperationWorkspace `json:"workspaces"`
+}
+
+// Counts returns the number of workspaces of the operation in every status.
+func (o WorkspaceBulkOperation) Counts() map[WorkspaceBulkResultStatus]int {
+	counts := map[WorkspaceBulkResultStatus]int{}
+	for _, ws := range o.Workspaces {
+		counts[ws.Status]++
+	}
+	return counts
+}
+
+// WorkspaceBulkOperationWorkspace is the outcome of an operation for a
+// single workspace.
+type WorkspaceBulkOperationWorkspace struct {
+	WorkspaceID   uuid.UUID                 `json:"workspace_id" format:"uuid"`
+	WorkspaceName string                    `json:"workspace_name"`
+	OwnerName     string                    `json:"owner_name"`
+	Status        WorkspaceBulkResultStatus `json:"status" enums:"pending,running,succeeded,failed,skipped"`
+	// BuildID is the build started for the workspace, if any.
+	BuildID *uuid.UUID `json:"build_id,omitempty" format:"uuid"`
+	// Error explains why the workspace failed or was skipped.
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+type CreateWorkspaceBulkOperationRequest struct {
+	Action WorkspaceBulkAction `json:"action" validate:"required,oneof=start stop update delete" enums:"start,stop,update,delete"`
+	// Query selects the workspaces, with the same syntax as the workspace
+	// list.
+	Query string `json:"q"`
+	// Concurrency is the maximum number of builds in progress at once.
+	// Defaults to 10.
+	Concurrency int32 `json:"concurrency,omitempty" validate:"min=0,max=100"`
+	// DryRun returns the workspaces the operation would build without
+	// building them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// CreateWorkspaceBulkOperation starts an operation on every workspace
+// matching the query, or previews it if the request is a dry run.
+func (c *Client) CreateWorkspaceBulkOperation(ctx context.Context, req CreateWorkspaceBulkOperationRequest) (WorkspaceBulkOperation, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaces/bulk", req)
+	if err != nil {
+		return WorkspaceBulkOperation{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
+		return WorkspaceBulkOperation{}, ReadBodyAsError(res)
+	}
+	var operation WorkspaceBulkOperation
+	return operation, json.NewDecoder(res.Body).Decode(&operation)
+}
+
+// WorkspaceBulkOperation returns the progress of an operation.
+func (c *Client) WorkspaceBulkOperation(ctx context.Context, id uuid.UUID) (WorkspaceBulkOperation, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/bulk/%s", id), nil)
+	if err != nil {
+		return WorkspaceBulkOperation{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBulkOperation{}, ReadBodyAsError(res)
+	}
+	var operation WorkspaceBulkOperation
+	return operation, json.NewDecoder(res.Body).Decode(&operation)
+}
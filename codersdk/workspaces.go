@@ -47,6 +47,11 @@ type Workspace struct {
 	TTLMillis                            *int64         `json:"ttl_ms,omitempty"`
 	LastUsedAt                           time.Time      `json:"last_used_at" format:"date-time"`
 
+	// TemplateReleaseChannel is the release channel of the template the owner
+	// is assigned to, if any. The template active version is the version of
+	// this channel.
+	TemplateReleaseChannel string `json:"template_release_channel,omitempty"`
+
 	// DeletingAt indicates the time at which the workspace will be permanently deleted.
 	// A workspace is eligible for deletion if it is dormant (a non-nil dormant_at value)
 	// and a value has been specified for time_til_dormant_autodelete on its template.
@@ -101,6 +106,9 @@ type CreateWorkspaceBuildRequest struct {
 	// This will overwrite any existing parameters with the same name.
 	// This will not delete old params not included in this list.
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+	// TemplateVersionPresetID selects a preset of the template version whose
+	// parameter values are used unless overridden by RichParameterValues.
+	TemplateVersionPresetID uuid.UUID `json:"template_version_preset_id,omitempty" format:"uuid"`
 
 	// Log level changes the default logging verbosity of a provider ("info" if empty).
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
@@ -404,11 +412,36 @@ func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// TransferWorkspaceOwnershipRequest is a request to give a workspace to
+// another user.
+type TransferWorkspaceOwnershipRequest struct {
+	// Owner is the ID or username of the new owner.
+	Owner string `json:"owner" validate:"required"`
+}
+
+// TransferWorkspaceOwnership changes the owner of the workspace by id. The
+// workspace is rebuilt with the identity of the new owner.
+func (c *Client) TransferWorkspaceOwnership(ctx context.Context, id uuid.UUID, req TransferWorkspaceOwnershipRequest) (Workspace, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/owner", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("transfer workspace ownership: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
 	// Template is a template name
 	Template string `json:"template,omitempty" typescript:"-"`
+	// Organization is the name or ID of an organization
+	Organization string `json:"organization,omitempty" typescript:"-"`
 	// Name will return partial matches
 	Name string `json:"name,omitempty" typescript:"-"`
 	// Status is a workspace status, which is really the status of the latest build
@@ -417,6 +450,9 @@ type WorkspaceFilter struct {
 	Offset int `json:"offset,omitempty" typescript:"-"`
 	// Limit is a limit on the number of workspaces returned.
 	Limit int `json:"limit,omitempty" typescript:"-"`
+	// Shared returns only the workspaces other users shared with the
+	// authenticated user.
+	Shared bool `json:"shared,omitempty" typescript:"-"`
 	// FilterQuery supports a raw filter query string
 	FilterQuery string `json:"q,omitempty"`
 }
@@ -440,6 +476,12 @@ func (f WorkspaceFilter) asRequestOption() RequestOption {
 		if f.Status != "" {
 			params = append(params, fmt.Sprintf("status:%q", f.Status))
 		}
+		if f.Organization != "" {
+			params = append(params, fmt.Sprintf("organization:%q", f.Organization))
+		}
+		if f.Shared {
+			params = append(params, "shared:true")
+		}
 		if f.FilterQuery != "" {
 			// If custom stuff is added, just add it on here.
 			params = append(params, f.FilterQuery)
@@ -494,8 +536,15 @@ func (c *Client) WorkspaceByOwnerAndName(ctx context.Context, owner string, name
 type WorkspaceQuota struct {
 	CreditsConsumed int `json:"credits_consumed"`
 	Budget          int `json:"budget"`
+	// WorkspaceCount is the number of workspaces the user owns in the
+	// organization.
+	WorkspaceCount int `json:"workspace_count"`
+	// WorkspaceLimit is the maximum number of workspaces the user can own,
+	// given by the groups of the user in the organization. 0 means no limit.
+	WorkspaceLimit int `json:"workspace_limit"`
 }
 
+// WorkspaceQuota returns the quota of the user in the default organization.
 func (c *Client) WorkspaceQuota(ctx context.Context, userID string) (WorkspaceQuota, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspace-quota/%s", userID), nil)
 	if err != nil {
@@ -509,6 +558,22 @@ func (c *Client) WorkspaceQuota(ctx context.Context, userID string) (WorkspaceQu
 	return quota, json.NewDecoder(res.Body).Decode(&quota)
 }
 
+// OrganizationWorkspaceQuota returns the quota of the user in the
+// organization. Only the groups and workspaces of the organization count
+// towards it.
+func (c *Client) OrganizationWorkspaceQuota(ctx context.Context, organizationID uuid.UUID, userID string) (WorkspaceQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/members/%s/workspace-quota", organizationID, userID), nil)
+	if err != nil {
+		return WorkspaceQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceQuota{}, ReadBodyAsError(res)
+	}
+	var quota WorkspaceQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
 type ResolveAutostartResponse struct {
 	ParameterMismatch bool `json:"parameter_mismatch"`
 }
@@ -550,6 +615,71 @@ func (c *Client) UnfavoriteWorkspace(ctx context.Context, workspaceID uuid.UUID)
 	return nil
 }
 
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleUse allows connecting to the workspace: its terminal, SSH
+	// and apps.
+	WorkspaceRoleUse WorkspaceRole = "use"
+	// WorkspaceRoleAdmin additionally allows building the workspace: starting,
+	// stopping and updating it. Its settings remain limited to the owner.
+	WorkspaceRoleAdmin   WorkspaceRole = "admin"
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+// WorkspaceACL lists the users and groups a workspace is shared with.
+type WorkspaceACL struct {
+	Users  []WorkspaceUser  `json:"users"`
+	Groups []WorkspaceGroup `json:"groups"`
+}
+
+type WorkspaceUser struct {
+	ReducedUser
+	Role WorkspaceRole `json:"role" enums:"admin,use"`
+}
+
+type WorkspaceGroup struct {
+	Group
+	Role WorkspaceRole `json:"role" enums:"admin,use"`
+}
+
+type UpdateWorkspaceACL struct {
+	// UserPerms is a mapping of user IDs or usernames to roles. An empty
+	// role stops sharing the workspace with the user.
+	UserPerms map[string]WorkspaceRole `json:"user_perms,omitempty" example:"4df59e74-c027-470b-ab4d-cbba8963a5e9:use"`
+	// GroupPerms is a mapping of group IDs or names to roles. An empty
+	// role stops sharing the workspace with the group.
+	GroupPerms map[string]WorkspaceRole `json:"group_perms,omitempty" example:"8bd26b20-f3e8-48be-a903-46bb920cf671:admin"`
+}
+
+// WorkspaceACL returns the users and groups the workspace is shared with.
+func (c *Client) WorkspaceACL(ctx context.Context, workspaceID uuid.UUID) (WorkspaceACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/acl", workspaceID), nil)
+	if err != nil {
+		return WorkspaceACL{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateWorkspaceACL shares the workspace with users and groups, or stops
+// sharing it.
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, workspaceID uuid.UUID, req UpdateWorkspaceACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/acl", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // WorkspaceNotifyChannel is the PostgreSQL NOTIFY
 // channel to listen for updates on. The payload is empty,
 // because the size of a workspace payload can be very large.
@@ -0,0 +1,92 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceSnapshot is a snapshot of the data of a workspace. It is taken by
+// the template during a stop build, and new workspaces of the same template
+// can be created from it.
+type WorkspaceSnapshot struct {
+	ID                uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID       uuid.UUID `json:"workspace_id" format:"uuid"`
+	Name              string    `json:"name"`
+	WorkspaceBuildID  uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	TemplateVersionID uuid.UUID `json:"template_version_id" format:"uuid"`
+	// Status is the status of the build that takes the snapshot. The snapshot
+	// can be restored once the build succeeded.
+	Status ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	// Artifacts are returned by the template as resource metadata with a
+	// "snapshot." prefixed key, e.g. the IDs of volume snapshots. They are
+	// passed to the builds of the workspaces restored from the snapshot.
+	Artifacts map[string]string `json:"artifacts"`
+	CreatedAt time.Time         `json:"created_at" format:"date-time"`
+}
+
+type CreateWorkspaceSnapshotRequest struct {
+	Name string `json:"name" validate:"required,snapshot_name"`
+}
+
+// WorkspaceSnapshots lists the snapshots of a workspace, newest first.
+func (c *Client) WorkspaceSnapshots(ctx context.Context, workspace uuid.UUID) ([]WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var snapshots []WorkspaceSnapshot
+	return snapshots, json.NewDecoder(res.Body).Decode(&snapshots)
+}
+
+// WorkspaceSnapshot returns the snapshot of a workspace with the given name.
+func (c *Client) WorkspaceSnapshot(ctx context.Context, workspace uuid.UUID, name string) (WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s", workspace, name), nil)
+	if err != nil {
+		return WorkspaceSnapshot{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceSnapshot{}, ReadBodyAsError(res)
+	}
+	var snapshot WorkspaceSnapshot
+	return snapshot, json.NewDecoder(res.Body).Decode(&snapshot)
+}
+
+// CreateWorkspaceSnapshot stops the workspace and tells its template to take a
+// snapshot. The snapshot can be restored once its build succeeded.
+func (c *Client) CreateWorkspaceSnapshot(ctx context.Context, workspace uuid.UUID, req CreateWorkspaceSnapshotRequest) (WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspace), req)
+	if err != nil {
+		return WorkspaceSnapshot{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSnapshot{}, ReadBodyAsError(res)
+	}
+	var snapshot WorkspaceSnapshot
+	return snapshot, json.NewDecoder(res.Body).Decode(&snapshot)
+}
+
+// DeleteWorkspaceSnapshot deletes the snapshot of a workspace with the given
+// name. The artifacts of the snapshot are not deleted, and workspaces restored
+// from it keep them.
+func (c *Client) DeleteWorkspaceSnapshot(ctx context.Context, workspace uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s", workspace, name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -16,15 +16,20 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":        {codersdk.AuditActionCreate},
+	"Template":         {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":  {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":             {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":        {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":   {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":           {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":          {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Webhook":          {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"CustomRole":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OIDCSyncRule":     {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"SCIMToken":        {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"UserLoginLockout": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -88,6 +93,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"deprecated":                        ActionTrack,
 		"max_port_sharing_level":            ActionTrack,
 		"activity_bump":                     ActionTrack,
+		"max_workspaces":                    ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -121,6 +127,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"quiet_hours_schedule": ActionTrack,
 		"theme_preference":     ActionIgnore,
 		"name":                 ActionTrack,
+		"is_service_account":   ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -138,6 +145,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"deleting_at":        ActionTrack,
 		"automatic_updates":  ActionTrack,
 		"favorite":           ActionTrack,
+		"user_acl":           ActionTrack,
+		"group_acl":          ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                      ActionIgnore,
@@ -158,14 +167,15 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"initiator_by_username":   ActionIgnore,
 	},
 	&database.AuditableGroup{}: {
-		"id":              ActionTrack,
-		"name":            ActionTrack,
-		"display_name":    ActionTrack,
-		"organization_id": ActionIgnore, // Never changes.
-		"avatar_url":      ActionTrack,
-		"quota_allowance": ActionTrack,
-		"members":         ActionTrack,
-		"source":          ActionIgnore,
+		"id":                      ActionTrack,
+		"name":                    ActionTrack,
+		"display_name":            ActionTrack,
+		"organization_id":         ActionIgnore, // Never changes.
+		"avatar_url":              ActionTrack,
+		"quota_allowance":         ActionTrack,
+		"members":                 ActionTrack,
+		"source":                  ActionIgnore,
+		"max_workspaces_per_user": ActionTrack,
 	},
 	&database.APIKey{}: {
 		"id":               ActionIgnore,
@@ -180,6 +190,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"ip_address":       ActionIgnore,
 		"scope":            ActionIgnore,
 		"token_name":       ActionIgnore,
+		"impersonator_id":  ActionTrack,
+		"user_agent":       ActionIgnore, // Already recorded on the audit log itself.
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -234,6 +246,53 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"app_id":         ActionIgnore,
 		"secret_prefix":  ActionIgnore,
 	},
+	&database.Webhook{}: {
+		"id":         ActionIgnore,
+		"name":       ActionTrack,
+		"url":        ActionTrack,
+		"events":     ActionTrack,
+		"secret":     ActionSecret,
+		"enabled":    ActionTrack,
+		"created_at": ActionIgnore,
+		"updated_at": ActionIgnore,
+	},
+	&database.CustomRole{}: {
+		"id":               ActionIgnore,
+		"name":             ActionTrack,
+		"display_name":     ActionTrack,
+		"organization_id":  ActionTrack,
+		"site_permissions": ActionTrack,
+		"org_permissions":  ActionTrack,
+		"user_permissions": ActionTrack,
+		"created_at":       ActionIgnore,
+		"updated_at":       ActionIgnore,
+	},
+	&database.OIDCSyncRule{}: {
+		"id":                 ActionIgnore,
+		"claim":              ActionTrack,
+		"pattern":            ActionTrack,
+		"site_roles":         ActionTrack,
+		"organization_id":    ActionTrack,
+		"organization_roles": ActionTrack,
+		"created_at":         ActionIgnore,
+		"updated_at":         ActionIgnore,
+	},
+	&database.SCIMToken{}: {
+		"id":             ActionIgnore,
+		"name":           ActionTrack,
+		"created_at":     ActionIgnore,
+		"last_used_at":   ActionIgnore,
+		"hashed_secret":  ActionSecret,
+		"display_secret": ActionTrack,
+		"secret_prefix":  ActionIgnore,
+	},
+	&database.AuditableUserLoginLockout{}: {
+		"user_id":         ActionTrack,
+		"username":        ActionTrack,
+		"failed_attempts": ActionTrack,
+		"created_at":      ActionIgnore,
+		"locked_until":    ActionTrack,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
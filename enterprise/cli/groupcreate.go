@@ -14,8 +14,9 @@ import (
 
 func (r *RootCmd) groupCreate() *serpent.Command {
 	var (
-		avatarURL   string
-		displayName string
+		avatarURL            string
+		displayName          string
+		maxWorkspacesPerUser int64
 	)
 
 	client := new(codersdk.Client)
@@ -35,9 +36,10 @@ func (r *RootCmd) groupCreate() *serpent.Command {
 			}
 
 			group, err := client.CreateGroup(ctx, org.ID, codersdk.CreateGroupRequest{
-				Name:        inv.Args[0],
-				DisplayName: displayName,
-				AvatarURL:   avatarURL,
+				Name:                 inv.Args[0],
+				DisplayName:          displayName,
+				AvatarURL:            avatarURL,
+				MaxWorkspacesPerUser: int(maxWorkspacesPerUser),
 			})
 			if err != nil {
 				return xerrors.Errorf("create group: %w", err)
@@ -62,6 +64,11 @@ func (r *RootCmd) groupCreate() *serpent.Command {
 			Env:         "CODER_DISPLAY_NAME",
 			Value:       serpent.StringOf(&displayName),
 		},
+		{
+			Flag:        "max-workspaces-per-user",
+			Description: "The maximum number of workspaces each member of the group can own. Members of several groups get the highest limit. 0 means the group sets no limit.",
+			Value:       serpent.Int64Of(&maxWorkspacesPerUser),
+		},
 	}
 
 	return cmd
@@ -17,11 +17,12 @@ import (
 
 func (r *RootCmd) groupEdit() *serpent.Command {
 	var (
-		avatarURL   string
-		name        string
-		displayName string
-		addUsers    []string
-		rmUsers     []string
+		avatarURL            string
+		name                 string
+		displayName          string
+		addUsers             []string
+		rmUsers              []string
+		maxWorkspacesPerUser int64
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -59,6 +60,11 @@ func (r *RootCmd) groupEdit() *serpent.Command {
 				req.DisplayName = &displayName
 			}
 
+			if inv.ParsedFlags().Lookup("max-workspaces-per-user").Changed {
+				limit := int(maxWorkspacesPerUser)
+				req.MaxWorkspacesPerUser = &limit
+			}
+
 			userRes, err := client.Users(ctx, codersdk.UsersRequest{})
 			if err != nil {
 				return xerrors.Errorf("get users: %w", err)
@@ -115,6 +121,11 @@ func (r *RootCmd) groupEdit() *serpent.Command {
 			Description:   "Remove users to the group. Accepts emails or IDs.",
 			Value:         serpent.StringArrayOf(&rmUsers),
 		},
+		{
+			Flag:        "max-workspaces-per-user",
+			Description: "Update the maximum number of workspaces each member of the group can own. 0 means the group sets no limit.",
+			Value:       serpent.Int64Of(&maxWorkspacesPerUser),
+		},
 	}
 
 	return cmd
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"time"
 
@@ -21,9 +22,9 @@ import (
 	"github.com/coder/coder/v2/cli/clilog"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/cli/cliutil"
-	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/provisioner/kubernetes"
 	"github.com/coder/coder/v2/provisioner/terraform"
 	"github.com/coder/coder/v2/provisionerd"
 	provisionerdproto "github.com/coder/coder/v2/provisionerd/proto"
@@ -66,11 +67,15 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 		logFilter      []string
 		name           string
 		rawTags        []string
+		engines        []string
+		engineDownload bool
 		pollInterval   time.Duration
 		pollJitter     time.Duration
 		preSharedKey   string
 		verbose        bool
 
+		moduleCacheSize int64
+
 		prometheusEnable  bool
 		prometheusAddress string
 	)
@@ -106,6 +111,23 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				return err
 			}
 
+			if len(engines) == 0 {
+				return xerrors.New("at least one engine must be specified")
+			}
+			var kubeConfig *kubernetes.Config
+			for _, engine := range engines {
+				if engine == string(codersdk.ProvisionerTypeKubernetes) {
+					kubeConfig, err = kubernetes.LoadConfig("")
+					if err != nil {
+						return xerrors.Errorf("load kubernetes config: %w", err)
+					}
+					continue
+				}
+				if !terraform.Engine(engine).Valid() {
+					return xerrors.Errorf("unknown engine %q, must be one of %q, %q or %q", engine, terraform.EngineTerraform, terraform.EngineOpenTofu, codersdk.ProvisionerTypeKubernetes)
+				}
+			}
+
 			logOpts := []clilog.Option{
 				clilog.WithFilter(logFilter...),
 				clilog.WithHuman(logHuman),
@@ -136,6 +158,11 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				tags[provisionersdk.TagScope] = provisionersdk.ScopeOrganization
 			}
 
+			orgID, err := r.provisionerDaemonOrganization(inv, client, preSharedKey)
+			if err != nil {
+				return err
+			}
+
 			err = os.MkdirAll(cacheDir, 0o700)
 			if err != nil {
 				return xerrors.Errorf("mkdir %q: %w", cacheDir, err)
@@ -146,38 +173,14 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				return err
 			}
 
-			terraformClient, terraformServer := drpc.MemTransportPipe()
-			go func() {
-				<-ctx.Done()
-				_ = terraformClient.Close()
-				_ = terraformServer.Close()
-			}()
-
-			errCh := make(chan error, 1)
-			go func() {
-				defer cancel()
-
-				err := terraform.Serve(ctx, &terraform.ServeOptions{
-					ServeOptions: &provisionersdk.ServeOptions{
-						Listener:      terraformServer,
-						Logger:        logger.Named("terraform"),
-						WorkDirectory: tempDir,
-					},
-					CachePath: cacheDir,
-				})
-				if err != nil && !xerrors.Is(err, context.Canceled) {
-					select {
-					case errCh <- err:
-					default:
-					}
-				}
-			}()
-
-			var metrics *provisionerd.Metrics
+			var (
+				metrics            *provisionerd.Metrics
+				prometheusRegistry *prometheus.Registry
+			)
 			if prometheusEnable {
 				logger.Info(ctx, "starting Prometheus endpoint", slog.F("address", prometheusAddress))
 
-				prometheusRegistry := prometheus.NewRegistry()
+				prometheusRegistry = prometheus.NewRegistry()
 				prometheusRegistry.MustRegister(collectors.NewGoCollector())
 				prometheusRegistry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 
@@ -191,21 +194,98 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 				defer closeFunc()
 			}
 
-			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", tags), slog.F("name", name))
+			var moduleCache *terraform.ModuleCache
+			if moduleCacheSize > 0 {
+				var reg prometheus.Registerer
+				if prometheusRegistry != nil {
+					reg = prometheusRegistry
+				}
+				moduleCache, err = terraform.NewModuleCache(
+					filepath.Join(cacheDir, "modules"), moduleCacheSize<<20, logger.Named("module_cache"), reg,
+				)
+				if err != nil {
+					return xerrors.Errorf("create module cache: %w", err)
+				}
+			}
+
+			connector := provisionerd.LocalProvisioners{}
+			provisioners := make([]codersdk.ProvisionerType, 0, len(engines))
+			engineVersions := map[codersdk.ProvisionerType][]string{}
+			errCh := make(chan error, 1)
+			for _, rawEngine := range engines {
+				engine := terraform.Engine(rawEngine)
+				// Terraform keeps using the cache directory itself so
+				// existing caches remain valid.
+				engineCacheDir := cacheDir
+				if engine != terraform.EngineTerraform {
+					engineCacheDir = filepath.Join(cacheDir, rawEngine)
+				}
+
+				provisionerClient, provisionerServer := drpc.MemTransportPipe()
+				go func() {
+					<-ctx.Done()
+					_ = provisionerClient.Close()
+					_ = provisionerServer.Close()
+				}()
+
+				go func() {
+					defer cancel()
 
-			connector := provisionerd.LocalProvisioners{
-				string(database.ProvisionerTypeTerraform): proto.NewDRPCProvisionerClient(terraformClient),
+					serveOptions := &provisionersdk.ServeOptions{
+						Listener:      provisionerServer,
+						Logger:        logger.Named(string(engine)),
+						WorkDirectory: tempDir,
+					}
+					var err error
+					if engine == terraform.Engine(codersdk.ProvisionerTypeKubernetes) {
+						err = kubernetes.Serve(ctx, &kubernetes.ServeOptions{
+							ServeOptions: serveOptions,
+							Config:       kubeConfig,
+						})
+					} else {
+						err = terraform.Serve(ctx, &terraform.ServeOptions{
+							ServeOptions:          serveOptions,
+							Engine:                engine,
+							CachePath:             engineCacheDir,
+							DisableEngineDownload: !engineDownload,
+							ModuleCache:           moduleCache,
+						})
+					}
+					if err != nil && !xerrors.Is(err, context.Canceled) {
+						select {
+						case errCh <- err:
+						default:
+						}
+					}
+				}()
+
+				connector[rawEngine] = proto.NewDRPCProvisionerClient(provisionerClient)
+				provisioners = append(provisioners, codersdk.ProvisionerType(rawEngine))
+
+				// Without downloads, only jobs of templates whose
+				// required_version an installed version satisfies are
+				// assigned to the daemon.
+				if engine.Valid() && !engineDownload {
+					versions, err := terraform.InstalledVersions(ctx, engine, engineCacheDir)
+					if err != nil {
+						return xerrors.Errorf("list %s versions: %w", rawEngine, err)
+					}
+					engineVersions[codersdk.ProvisionerType(rawEngine)] = versions
+				}
 			}
+
+			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", tags), slog.F("name", name), slog.F("engines", engines), slog.F("engine_versions", engineVersions), slog.F("organization_id", orgID))
+
 			id := uuid.New()
 			srv := provisionerd.New(func(ctx context.Context) (provisionerdproto.DRPCProvisionerDaemonClient, error) {
 				return client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
-					ID:   id,
-					Name: name,
-					Provisioners: []codersdk.ProvisionerType{
-						codersdk.ProvisionerTypeTerraform,
-					},
-					Tags:         tags,
-					PreSharedKey: preSharedKey,
+					ID:             id,
+					Name:           name,
+					Provisioners:   provisioners,
+					Tags:           tags,
+					EngineVersions: engineVersions,
+					PreSharedKey:   preSharedKey,
+					Organization:   orgID,
 				})
 			}, &provisionerd.Options{
 				Logger:         logger,
@@ -263,6 +343,27 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 			Description:   "Tags to filter provisioner jobs by.",
 			Value:         serpent.StringArrayOf(&rawTags),
 		},
+		{
+			Flag:        "engine",
+			Env:         "CODER_PROVISIONERD_ENGINES",
+			Description: "Engines to execute templates with. Templates are only assigned to daemons that run the engine they require. Accepted values are terraform, opentofu and kubernetes.",
+			Default:     string(terraform.EngineTerraform),
+			Value:       serpent.StringArrayOf(&engines),
+		},
+		{
+			Flag:        "engine-download",
+			Env:         "CODER_PROVISIONERD_ENGINE_DOWNLOAD",
+			Description: "Download the Terraform and OpenTofu versions that templates require with required_version when none is installed. When disabled, the daemon is only assigned jobs of templates that an installed version satisfies.",
+			Default:     "true",
+			Value:       serpent.BoolOf(&engineDownload),
+		},
+		{
+			Flag:        "module-cache-size",
+			Env:         "CODER_PROVISIONERD_MODULE_CACHE_SIZE",
+			Description: "Maximum size in megabytes of the cache of Terraform modules, which is stored in the cache directory. The least recently used modules are evicted once it is exceeded. Set to 0 to disable the cache.",
+			Default:     "1024",
+			Value:       serpent.Int64Of(&moduleCacheSize),
+		},
 		{
 			Flag:        "poll-interval",
 			Env:         "CODER_PROVISIONERD_POLL_INTERVAL",
@@ -343,3 +444,28 @@ func (r *RootCmd) provisionerDaemonStart() *serpent.Command {
 
 	return cmd
 }
+
+// provisionerDaemonOrganization returns the ID of the organization the
+// provisioner daemon serves jobs of, or uuid.Nil for the default organization
+// if none is selected. Without a session token the organization can't be
+// looked up by name, so it must be selected by its ID.
+func (r *RootCmd) provisionerDaemonOrganization(inv *serpent.Invocation, client *codersdk.Client, preSharedKey string) (uuid.UUID, error) {
+	selected, err := r.SelectedOrganization()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if selected == "" {
+		return uuid.Nil, nil
+	}
+	if id, err := uuid.Parse(selected); err == nil {
+		return id, nil
+	}
+	if preSharedKey != "" {
+		return uuid.Nil, xerrors.Errorf("organization %q must be selected by its ID when using a pre-shared key", selected)
+	}
+	org, err := agpl.CurrentOrganization(&r.RootCmd, inv, client)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return org.ID, nil
+}
@@ -59,6 +59,47 @@ func TestProvisionerDaemon_PSK(t *testing.T) {
 	require.Equal(t, proto.CurrentVersion.String(), daemons[0].APIVersion)
 }
 
+func TestProvisionerDaemon_Engine(t *testing.T) {
+	t.Parallel()
+
+	client, _ := coderdenttest.New(t, &coderdenttest.Options{
+		ProvisionerDaemonPSK: "provisionersftw",
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		},
+	})
+	inv, conf := newCLI(t, "provisionerd", "start", "--psk=provisionersftw", "--name=tofu-daemon", "--engine=opentofu")
+	err := conf.URL().Write(client.URL.String())
+	require.NoError(t, err)
+	pty := ptytest.New(t).Attach(inv)
+	ctx, cancel := context.WithTimeout(inv.Context(), testutil.WaitLong)
+	defer cancel()
+	clitest.Start(t, inv)
+	pty.ExpectMatchContext(ctx, "tofu-daemon")
+
+	var daemons []codersdk.ProvisionerDaemon
+	require.Eventually(t, func() bool {
+		daemons, err = client.ProvisionerDaemons(ctx)
+		if err != nil {
+			return false
+		}
+		return len(daemons) == 1
+	}, testutil.WaitShort, testutil.IntervalSlow)
+	require.Equal(t, []codersdk.ProvisionerType{codersdk.ProvisionerTypeOpenTofu}, daemons[0].Provisioners)
+}
+
+func TestProvisionerDaemon_UnknownEngine(t *testing.T) {
+	t.Parallel()
+
+	inv, conf := newCLI(t, "provisionerd", "start", "--psk=provisionersftw", "--engine=pulumi")
+	err := conf.URL().Write("http://localhost")
+	require.NoError(t, err)
+	err = inv.Run()
+	require.ErrorContains(t, err, `unknown engine "pulumi"`)
+}
+
 func TestProvisionerDaemon_SessionToken(t *testing.T) {
 	t.Parallel()
 	t.Run("ScopeUser", func(t *testing.T) {
@@ -106,6 +106,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	// This must happen before coderd initialization!
 	options.PostAuthAdditionalHeadersFunc = api.writeEntitlementWarningsHeader
 	api.AGPL = coderd.New(options.Options)
+	// coderd wraps the Authorizer to expand custom roles.
+	api.provisionerDaemonAuth.authorizer = options.Authorizer
 	defer func() {
 		if err != nil {
 			_ = api.Close()
@@ -152,6 +154,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		Optional:                      false,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		Sessions:                      api.AGPL.SessionTracker,
 	})
 	apiKeyMiddlewareOptional := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
 		DB:                            options.Database,
@@ -161,6 +164,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		Optional:                      true,
 		SessionTokenFunc:              nil, // Default behavior
 		PostAuthAdditionalHeadersFunc: options.PostAuthAdditionalHeadersFunc,
+		Sessions:                      api.AGPL.SessionTracker,
 	})
 
 	deploymentID, err := options.Database.GetDeploymentID(ctx)
@@ -190,6 +194,15 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Get("/", api.licenses)
 			r.Delete("/{id}", api.deleteLicense)
 		})
+		r.Route("/scim/tokens", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.scimEnabledMW,
+			)
+			r.Get("/", api.scimTokens)
+			r.Post("/", api.postSCIMToken)
+			r.Delete("/{token}", api.deleteSCIMToken)
+		})
 		r.Route("/applications/reconnecting-pty-signed-token", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.reconnectingPTYSignedToken)
@@ -245,15 +258,11 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/", api.groupByOrganization)
 			})
 		})
-		// TODO: provisioner daemons are not scoped to organizations in the database, so placing them
-		// under an organization route doesn't make sense.  In order to allow the /serve endpoint to
-		// work with a pre-shared key (PSK) without an API key, these routes will simply ignore the
-		// value of {organization}.  That is, the route will work with any organization ID, whether or
-		// not it exits.  This doesn't leak any information about the existence of organizations, so is
-		// fine from a security perspective, but might be a little surprising.
-		//
-		// We may in future decide to scope provisioner daemons to organizations, so we'll keep the API
-		// route as is.
+		// Provisioner daemons are scoped to the organization of the route: they
+		// only acquire jobs of the organization, and users need permission to
+		// create provisioner daemons in the organization. The pre-shared key
+		// (PSK) is not tied to an organization, so daemons authenticated with it
+		// may serve any organization.
 		r.Route("/organizations/{organization}/provisionerdaemons", func(r chi.Router) {
 			r.Use(
 				api.provisionerDaemonsEnabledMW,
@@ -290,6 +299,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Patch("/", api.patchGroup)
 			r.Delete("/", api.deleteGroup)
 		})
+		r.Route("/organizations/{organization}/members/{user}/workspace-quota", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				httpmw.ExtractOrganizationParam(api.Database),
+				httpmw.ExtractOrganizationMemberParam(api.Database),
+			)
+			r.Get("/", api.organizationMemberWorkspaceQuota)
+		})
 		r.Route("/workspace-quota", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -339,20 +356,33 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		})
 	})
 
-	if len(options.SCIMAPIKey) != 0 {
-		api.AGPL.RootHandler.Route("/scim/v2", func(r chi.Router) {
-			r.Use(
-				api.scimEnabledMW,
-			)
-			r.Post("/Users", api.scimPostUser)
-			r.Route("/Users", func(r chi.Router) {
-				r.Get("/", api.scimGetUsers)
-				r.Post("/", api.scimPostUser)
-				r.Get("/{id}", api.scimGetUser)
-				r.Patch("/{id}", api.scimPatchUser)
-			})
+	api.AGPL.RootHandler.Route("/scim/v2", func(r chi.Router) {
+		r.Use(
+			api.scimEnabledMW,
+			api.scimAuthMW,
+		)
+		r.Get("/ServiceProviderConfig", api.scimGetServiceProviderConfig)
+		r.Get("/Schemas", api.scimGetSchemas)
+		r.Get("/Schemas/{id}", api.scimGetSchema)
+		r.Get("/ResourceTypes", api.scimGetResourceTypes)
+		r.Get("/ResourceTypes/{name}", api.scimGetResourceType)
+		r.Post("/Users", api.scimPostUser)
+		r.Route("/Users", func(r chi.Router) {
+			r.Get("/", api.scimGetUsers)
+			r.Post("/", api.scimPostUser)
+			r.Get("/{id}", api.scimGetUser)
+			r.Put("/{id}", api.scimPutUser)
+			r.Patch("/{id}", api.scimPatchUser)
 		})
-	}
+		r.Route("/Groups", func(r chi.Router) {
+			r.Get("/", api.scimGetGroups)
+			r.Post("/", api.scimPostGroup)
+			r.Get("/{id}", api.scimGetGroup)
+			r.Put("/{id}", api.scimPutGroup)
+			r.Patch("/{id}", api.scimPatchGroup)
+			r.Delete("/{id}", api.scimDeleteGroup)
+		})
+	})
 
 	meshTLSConfig, err := replicasync.CreateDERPMeshTLSConfig(options.AccessURL.Hostname(), options.TLSCertificates)
 	if err != nil {
@@ -530,7 +560,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		api.Logger, len(api.replicaManager.AllPrimary()), len(api.ExternalAuthConfigs), api.LicenseKeys, map[codersdk.FeatureName]bool{
 			codersdk.FeatureAuditLog:                   api.AuditLogging,
 			codersdk.FeatureBrowserOnly:                api.BrowserOnly,
-			codersdk.FeatureSCIM:                       len(api.SCIMAPIKey) != 0,
+			codersdk.FeatureSCIM:                       true,
 			codersdk.FeatureMultipleExternalAuth:       len(api.ExternalAuthConfigs) > 1,
 			codersdk.FeatureTemplateRBAC:               api.RBAC,
 			codersdk.FeatureExternalTokenEncryption:    len(api.ExternalTokenEncryption) > 0,
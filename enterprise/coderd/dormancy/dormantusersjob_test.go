@@ -42,12 +42,19 @@ func TestCheckInactiveUsers(t *testing.T) {
 	suspendedUser2 := setupUser(ctx, t, db, "suspended-user-2@coder.com", database.UserStatusSuspended, time.Now().Add(-dormancyPeriod).Add(-time.Hour))
 	suspendedUser3 := setupUser(ctx, t, db, "suspended-user-3@coder.com", database.UserStatusSuspended, time.Now().Add(-dormancyPeriod).Add(-6*time.Hour))
 
+	// Service accounts are never marked dormant.
+	serviceAccount, err := db.InsertUser(ctx, database.InsertUserParams{ID: uuid.New(), LoginType: database.LoginTypeNone, Username: uuid.NewString()[:8], Email: "service-account@coder.com", IsServiceAccount: true})
+	require.NoError(t, err)
+	serviceAccount, err = db.UpdateUserStatus(ctx, database.UpdateUserStatusParams{ID: serviceAccount.ID, Status: database.UserStatusActive})
+	require.NoError(t, err)
+	serviceAccount, err = db.UpdateUserLastSeenAt(ctx, database.UpdateUserLastSeenAtParams{ID: serviceAccount.ID, LastSeenAt: time.Now().Add(-dormancyPeriod).Add(-time.Hour)})
+	require.NoError(t, err)
+
 	// Run the periodic job
 	closeFunc := dormancy.CheckInactiveUsersWithOptions(ctx, logger, db, interval, dormancyPeriod)
 	t.Cleanup(closeFunc)
 
 	var rows []database.GetUsersRow
-	var err error
 	require.Eventually(t, func() bool {
 		rows, err = db.GetUsers(ctx, database.GetUsersParams{})
 		if err != nil {
@@ -62,8 +69,8 @@ func TestCheckInactiveUsers(t *testing.T) {
 				suspended++
 			}
 		}
-		// 6 users in total, 3 dormant, 3 suspended
-		return len(rows) == 9 && dormant == 3 && suspended == 3
+		// 10 users in total, 3 dormant, 3 suspended
+		return len(rows) == 10 && dormant == 3 && suspended == 3
 	}, testutil.WaitShort, testutil.IntervalMedium)
 
 	allUsers := ignoreUpdatedAt(database.ConvertUserRows(rows))
@@ -79,6 +86,7 @@ func TestCheckInactiveUsers(t *testing.T) {
 		suspendedUser1,
 		suspendedUser2,
 		suspendedUser3,
+		serviceAccount,
 	}
 	require.ElementsMatch(t, allUsers, expectedUsers)
 }
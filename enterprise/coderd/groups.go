@@ -56,13 +56,22 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if req.MaxWorkspacesPerUser < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace limit.",
+			Validations: []codersdk.ValidationError{{Field: "max_workspaces_per_user", Detail: "Value must not be negative."}},
+		})
+		return
+	}
+
 	group, err := api.Database.InsertGroup(ctx, database.InsertGroupParams{
-		ID:             uuid.New(),
-		Name:           req.Name,
-		DisplayName:    req.DisplayName,
-		OrganizationID: org.ID,
-		AvatarURL:      req.AvatarURL,
-		QuotaAllowance: int32(req.QuotaAllowance),
+		ID:                   uuid.New(),
+		Name:                 req.Name,
+		DisplayName:          req.DisplayName,
+		OrganizationID:       org.ID,
+		AvatarURL:            req.AvatarURL,
+		QuotaAllowance:       int32(req.QuotaAllowance),
+		MaxWorkspacesPerUser: int32(req.MaxWorkspacesPerUser),
 	})
 	if database.IsUniqueViolation(err) {
 		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
@@ -139,6 +148,14 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.MaxWorkspacesPerUser != nil && *req.MaxWorkspacesPerUser < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace limit.",
+			Validations: []codersdk.ValidationError{{Field: "max_workspaces_per_user", Detail: "Value must not be negative."}},
+		})
+		return
+	}
+
 	users := make([]string, 0, len(req.AddUsers)+len(req.RemoveUsers))
 	users = append(users, req.AddUsers...)
 	users = append(users, req.RemoveUsers...)
@@ -202,11 +219,12 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		updateGroupParams := database.UpdateGroupByIDParams{
-			ID:             group.ID,
-			AvatarURL:      group.AvatarURL,
-			Name:           group.Name,
-			DisplayName:    group.DisplayName,
-			QuotaAllowance: group.QuotaAllowance,
+			ID:                   group.ID,
+			AvatarURL:            group.AvatarURL,
+			Name:                 group.Name,
+			DisplayName:          group.DisplayName,
+			QuotaAllowance:       group.QuotaAllowance,
+			MaxWorkspacesPerUser: group.MaxWorkspacesPerUser,
 		}
 
 		// TODO: Do we care about validating this?
@@ -219,6 +237,9 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		if req.QuotaAllowance != nil {
 			updateGroupParams.QuotaAllowance = int32(*req.QuotaAllowance)
 		}
+		if req.MaxWorkspacesPerUser != nil {
+			updateGroupParams.MaxWorkspacesPerUser = int32(*req.MaxWorkspacesPerUser)
+		}
 		if req.DisplayName != nil {
 			updateGroupParams.DisplayName = *req.DisplayName
 		}
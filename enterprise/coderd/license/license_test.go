@@ -291,6 +291,20 @@ func TestEntitlements(t *testing.T) {
 			LoginType: database.LoginTypePassword,
 		})
 		require.NoError(t, err)
+		// Service accounts do not use a seat.
+		serviceAccount, err := db.InsertUser(context.Background(), database.InsertUserParams{
+			ID:               uuid.New(),
+			Username:         "service-account",
+			LoginType:        database.LoginTypeNone,
+			IsServiceAccount: true,
+		})
+		require.NoError(t, err)
+		_, err = db.UpdateUserStatus(context.Background(), database.UpdateUserStatusParams{
+			ID:        serviceAccount.ID,
+			Status:    database.UserStatusActive,
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
 		db.InsertLicense(context.Background(), database.InsertLicenseParams{
 			JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
 				Features: license.Features{
@@ -20,7 +20,6 @@ import (
 	"storj.io/drpc/drpcserver"
 
 	"cdr.dev/slog"
-	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -63,7 +62,9 @@ func (api *API) provisionerDaemonsEnabledMW(next http.Handler) http.Handler {
 // @Router /organizations/{organization}/provisionerdaemons [get]
 func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	daemons, err := api.Database.GetProvisionerDaemons(ctx)
+	organization := httpmw.OrganizationParam(r)
+
+	daemons, err := api.Database.GetProvisionerDaemonsByOrganization(ctx, organization.ID)
 	if errors.Is(err, sql.ErrNoRows) {
 		err = nil
 	}
@@ -77,14 +78,6 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 	if daemons == nil {
 		daemons = []database.ProvisionerDaemon{}
 	}
-	daemons, err = coderd.AuthorizeFilter(api.AGPL.HTTPAuth, r, rbac.ActionRead, daemons)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching provisioner daemons.",
-			Detail:  err.Error(),
-		})
-		return
-	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.List(daemons, db2sdk.ProvisionerDaemon))
 }
@@ -95,8 +88,8 @@ type provisionerDaemonAuth struct {
 }
 
 // authorize returns mutated tags and true if the given HTTP request is authorized to access the provisioner daemon
-// protobuf API, and returns nil, false otherwise.
-func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]string) (map[string]string, bool) {
+// protobuf API of the organization, and returns nil, false otherwise.
+func (p *provisionerDaemonAuth) authorize(r *http.Request, orgID uuid.UUID, tags map[string]string) (map[string]string, bool) {
 	ctx := r.Context()
 	apiKey, ok := httpmw.APIKeyOptional(r)
 	if ok {
@@ -107,8 +100,8 @@ func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]strin
 			return tags, true
 		}
 		ua := httpmw.UserAuthorization(r)
-		if err := p.authorizer.Authorize(ctx, ua, rbac.ActionCreate, rbac.ResourceProvisionerDaemon); err == nil {
-			// User is allowed to create provisioner daemons
+		if err := p.authorizer.Authorize(ctx, ua, rbac.ActionCreate, rbac.ResourceProvisionerDaemon.InOrg(orgID)); err == nil {
+			// User is allowed to create provisioner daemons in the organization
 			return tags, true
 		}
 	}
@@ -168,6 +161,10 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			provisionersMap[codersdk.ProvisionerTypeEcho] = struct{}{}
 		case string(codersdk.ProvisionerTypeTerraform):
 			provisionersMap[codersdk.ProvisionerTypeTerraform] = struct{}{}
+		case string(codersdk.ProvisionerTypeOpenTofu):
+			provisionersMap[codersdk.ProvisionerTypeOpenTofu] = struct{}{}
+		case string(codersdk.ProvisionerTypeKubernetes):
+			provisionersMap[codersdk.ProvisionerTypeKubernetes] = struct{}{}
 		default:
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message: fmt.Sprintf("Unknown provisioner type %q", provisioner),
@@ -183,7 +180,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.Logger.Warn(ctx, "unnamed provisioner daemon")
 	}
 
-	tags, authorized := api.provisionerDaemonAuth.authorize(r, tags)
+	tags, authorized := api.provisionerDaemonAuth.authorize(r, organization.ID, tags)
 	if !authorized {
 		api.Logger.Warn(ctx, "unauthorized provisioner daemon serve request", slog.F("tags", tags))
 		httpapi.Write(ctx, rw, http.StatusForbidden,
@@ -204,11 +201,37 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	engineVersions := provisionerdserver.EngineVersions{}
+	for _, engineVersion := range r.URL.Query()["engine_version"] {
+		parts := strings.SplitN(engineVersion, "=", 2)
+		if len(parts) < 2 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid format for engine version %q. Provisioner and version must be separated with =.", engineVersion),
+			})
+			return
+		}
+		provisioner := database.ProvisionerType(parts[0])
+		engineVersions[provisioner] = append(engineVersions[provisioner], parts[1])
+	}
+	if err := engineVersions.Valid(); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Given engine versions are not acceptable to the service",
+			Validations: []codersdk.ValidationError{
+				{Field: "engine_versions", Detail: err.Error()},
+			},
+		})
+		return
+	}
+
 	provisioners := make([]database.ProvisionerType, 0, len(provisionersMap))
 	for p := range provisionersMap {
 		switch p {
 		case codersdk.ProvisionerTypeTerraform:
 			provisioners = append(provisioners, database.ProvisionerTypeTerraform)
+		case codersdk.ProvisionerTypeOpenTofu:
+			provisioners = append(provisioners, database.ProvisionerTypeOpentofu)
+		case codersdk.ProvisionerTypeKubernetes:
+			provisioners = append(provisioners, database.ProvisionerTypeKubernetes)
 		case codersdk.ProvisionerTypeEcho:
 			provisioners = append(provisioners, database.ProvisionerTypeEcho)
 		}
@@ -218,6 +241,7 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		slog.F("name", name),
 		slog.F("provisioners", provisioners),
 		slog.F("tags", tags),
+		slog.F("engine_versions", engineVersions),
 	)
 
 	authCtx := ctx
@@ -331,9 +355,12 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.AGPL.TemplateScheduleStore,
 		api.AGPL.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
+		api.NotificationsEnqueuer,
+		api.WebhookPublisher,
 		provisionerdserver.Options{
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
 			OIDCConfig:          api.OIDCConfig,
+			EngineVersions:      engineVersions,
 		},
 	)
 	if err != nil {
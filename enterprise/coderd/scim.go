@@ -1,17 +1,16 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/imulab/go-scim/pkg/v2/handlerutil"
-	scimjson "github.com/imulab/go-scim/pkg/v2/json"
-	"github.com/imulab/go-scim/pkg/v2/service"
-	"github.com/imulab/go-scim/pkg/v2/spec"
 	"golang.org/x/xerrors"
 
 	agpl "github.com/coder/coder/v2/coderd"
@@ -20,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
 )
 
 func (api *API) scimEnabledMW(next http.Handler) http.Handler {
@@ -37,66 +37,140 @@ func (api *API) scimEnabledMW(next http.Handler) http.Handler {
 	})
 }
 
-func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
-	hdr := []byte(r.Header.Get("Authorization"))
+// scimAuthMW rejects SCIM requests that are not authenticated with the SCIM
+// API key or a SCIM token.
+func (api *API) scimAuthMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ok, err := api.scimVerifyAuthHeader(r)
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+		if !ok {
+			rw.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			scim.WriteError(rw, scim.Errorf(http.StatusUnauthorized, "", "The Authorization header must contain a valid SCIM token."))
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
 
-	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
+// scimVerifyAuthHeader accepts the SCIM API key of the deployment, as is or as
+// a bearer token, and bearer SCIM tokens.
+func (api *API) scimVerifyAuthHeader(r *http.Request) (bool, error) {
+	hdr := r.Header.Get("Authorization")
+	token := hdr
+	if scheme, rest, ok := strings.Cut(hdr, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(rest)
+	}
+
+	if len(api.SCIMAPIKey) != 0 &&
+		(subtle.ConstantTimeCompare([]byte(hdr), api.SCIMAPIKey) == 1 ||
+			subtle.ConstantTimeCompare([]byte(token), api.SCIMAPIKey) == 1) {
+		return true, nil
+	}
+	if token == "" {
+		return false, nil
+	}
+	return api.verifySCIMToken(r, token)
 }
 
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
-//
-// @Summary SCIM 2.0: Get users
-// @ID scim-get-users
+// scimBaseURL is the URL SCIM resources are located relative to.
+func (api *API) scimBaseURL() string {
+	return api.AccessURL.JoinPath("/scim/v2").String()
+}
+
+// @Summary SCIM 2.0: Get service provider config
+// @ID scim-get-service-provider-config
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
-// @Success 200
-// @Router /scim/v2/Users [get]
-//
-//nolint:revive
-func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+// @Success 200 {object} scim.ServiceProviderConfig
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (api *API) scimGetServiceProviderConfig(rw http.ResponseWriter, _ *http.Request) {
+	scim.Write(rw, http.StatusOK, scim.NewServiceProviderConfig(api.scimBaseURL()))
+}
+
+// @Summary SCIM 2.0: Get schemas
+// @ID scim-get-schemas
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200 {object} scim.ListResponse
+// @Router /scim/v2/Schemas [get]
+func (api *API) scimGetSchemas(rw http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("filter") {
+		scim.WriteError(rw, scim.Errorf(http.StatusForbidden, "", "Schemas cannot be filtered."))
 		return
 	}
+	scim.Write(rw, http.StatusOK, scim.NewListResponse(scim.Schemas(api.scimBaseURL())))
+}
 
-	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
-		TotalResults: 0,
-		StartIndex:   1,
-		ItemsPerPage: 0,
-		Resources:    []scimjson.Serializable{},
-	})
+// @Summary SCIM 2.0: Get schema by ID
+// @ID scim-get-schema-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Schema URN"
+// @Success 200 {object} scim.Schema
+// @Router /scim/v2/Schemas/{id} [get]
+func (api *API) scimGetSchema(rw http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, schema := range scim.Schemas(api.scimBaseURL()) {
+		if strings.EqualFold(schema.ID, id) {
+			scim.Write(rw, http.StatusOK, schema)
+			return
+		}
+	}
+	scim.WriteError(rw, scim.Errorf(http.StatusNotFound, "", "Schema %q not found.", id))
 }
 
-// scimGetUser intentionally always returns an error saying the user wasn't found.
-// This is done to always force Okta to try and create the user, this way we
-// don't need to implement fetching users twice.
-//
-// @Summary SCIM 2.0: Get user by ID
-// @ID scim-get-user-by-id
+// @Summary SCIM 2.0: Get resource types
+// @ID scim-get-resource-types
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
-// @Param id path string true "User ID" format(uuid)
-// @Failure 404
-// @Router /scim/v2/Users/{id} [get]
-//
-//nolint:revive
-func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+// @Success 200 {object} scim.ListResponse
+// @Router /scim/v2/ResourceTypes [get]
+func (api *API) scimGetResourceTypes(rw http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("filter") {
+		scim.WriteError(rw, scim.Errorf(http.StatusForbidden, "", "Resource types cannot be filtered."))
 		return
 	}
+	scim.Write(rw, http.StatusOK, scim.NewListResponse(scim.ResourceTypes(api.scimBaseURL())))
+}
+
+// @Summary SCIM 2.0: Get resource type by name
+// @ID scim-get-resource-type-by-name
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param name path string true "Resource type name"
+// @Success 200 {object} scim.ResourceType
+// @Router /scim/v2/ResourceTypes/{name} [get]
+func (api *API) scimGetResourceType(rw http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	for _, resourceType := range scim.ResourceTypes(api.scimBaseURL()) {
+		if strings.EqualFold(resourceType.ID, name) {
+			scim.Write(rw, http.StatusOK, resourceType)
+			return
+		}
+	}
+	scim.WriteError(rw, scim.Errorf(http.StatusNotFound, "", "Resource type %q not found.", name))
+}
 
-	_ = handlerutil.WriteError(rw, spec.ErrNotFound)
+// SCIMReference references a group from a user, or a user from a group.
+type SCIMReference struct {
+	// Value is the ID of the referenced resource.
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
 }
 
 // We currently use our own struct instead of using the SCIM package. This was
 // done mostly because the SCIM package was almost impossible to use. We only
 // need these fields, so it was much simpler to use our own struct. This was
-// tested only with Okta.
+// tested with Okta and Azure AD.
 type SCIMUser struct {
 	Schemas  []string `json:"schemas"`
 	ID       string   `json:"id"`
@@ -105,17 +179,200 @@ type SCIMUser struct {
 		GivenName  string `json:"givenName"`
 		FamilyName string `json:"familyName"`
 	} `json:"name"`
-	Emails []struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Emails      []struct {
 		Primary bool   `json:"primary"`
 		Value   string `json:"value" format:"email"`
 		Type    string `json:"type"`
 		Display string `json:"display"`
 	} `json:"emails"`
-	Active bool          `json:"active"`
-	Groups []interface{} `json:"groups"`
-	Meta   struct {
-		ResourceType string `json:"resourceType"`
-	} `json:"meta"`
+	Active bool `json:"active"`
+	// Groups are read-only. Group memberships are changed with the Groups
+	// endpoints.
+	Groups []SCIMReference `json:"groups"`
+	Meta   scim.Meta       `json:"meta"`
+}
+
+// email returns the primary email of the user, or the first one if none is
+// marked as primary.
+func (u SCIMUser) email() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// realName returns the name of the user, from displayName or from the given
+// and family names.
+func (u SCIMUser) realName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+func (api *API) scimUser(user database.User, groups []database.Group) SCIMUser {
+	base := api.scimBaseURL()
+	sUser := SCIMUser{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          user.ID.String(),
+		UserName:    user.Username,
+		DisplayName: user.Name,
+		Active:      user.Status != database.UserStatusSuspended,
+		Groups:      []SCIMReference{},
+		Meta: scim.Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt.UTC().Format(time.RFC3339),
+			LastModified: user.UpdatedAt.UTC().Format(time.RFC3339),
+			Location:     base + "/Users/" + user.ID.String(),
+		},
+	}
+	// Coder stores a single name, so the given name is its first word.
+	givenName, familyName, _ := strings.Cut(user.Name, " ")
+	sUser.Name.GivenName = givenName
+	sUser.Name.FamilyName = familyName
+	sUser.Emails = append(sUser.Emails, struct {
+		Primary bool   `json:"primary"`
+		Value   string `json:"value" format:"email"`
+		Type    string `json:"type"`
+		Display string `json:"display"`
+	}{Primary: true, Value: user.Email, Type: "work"})
+	for _, group := range groups {
+		sUser.Groups = append(sUser.Groups, SCIMReference{
+			Value:   group.ID.String(),
+			Ref:     base + "/Groups/" + group.ID.String(),
+			Display: group.Name,
+		})
+	}
+	return sUser
+}
+
+// scimUserByID returns a user that is not deleted, with their groups.
+func (api *API) scimUserByID(ctx context.Context, rawID string) (SCIMUser, database.User, error) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return SCIMUser{}, database.User{}, scim.Errorf(http.StatusNotFound, "", "User %q not found.", rawID)
+	}
+	user, err := api.Database.GetUserByID(ctx, id)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && user.Deleted) {
+		return SCIMUser{}, database.User{}, scim.Errorf(http.StatusNotFound, "", "User %q not found.", rawID)
+	}
+	if err != nil {
+		return SCIMUser{}, database.User{}, xerrors.Errorf("get user: %w", err)
+	}
+	sUser, err := api.scimUserWithGroups(ctx, user)
+	return sUser, user, err
+}
+
+func (api *API) scimUserWithGroups(ctx context.Context, user database.User) (SCIMUser, error) {
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		return SCIMUser{}, xerrors.Errorf("get default organization: %w", err)
+	}
+	groups, err := api.Database.GetGroupsByOrganizationAndUserID(ctx, database.GetGroupsByOrganizationAndUserIDParams{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		return SCIMUser{}, xerrors.Errorf("get groups: %w", err)
+	}
+	return api.scimUser(user, scimVisibleGroups(groups)), nil
+}
+
+// writeSCIMResource writes a resource with the attributes the request asks
+// for.
+func writeSCIMResource(rw http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	resource, err := scim.ToResource(v)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	attributes, excluded := scim.ProjectionQuery(r)
+	scim.Write(rw, status, scim.Project(resource, attributes, excluded))
+}
+
+// scimGetUsers lists users. Filters are applied to the SCIM representation of
+// every user, so any attribute can be filtered on.
+//
+// @Summary SCIM 2.0: Get users
+// @ID scim-get-users
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter"
+// @Param startIndex query int false "Start index"
+// @Param count query int false "Count"
+// @Param attributes query string false "Attributes"
+// @Param excludedAttributes query string false "Excluded attributes"
+// @Success 200 {object} scim.ListResponse
+// @Router /scim/v2/Users [get]
+func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	query, err := scim.ParseListQuery(r)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	rows, err := api.Database.GetUsers(ctx, database.GetUsersParams{})
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	groups, err := api.scimGroups(ctx, org.ID)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	userGroups := make(map[uuid.UUID][]database.Group)
+	for _, group := range groups {
+		for _, member := range group.members {
+			userGroups[member.ID] = append(userGroups[member.ID], group.Group)
+		}
+	}
+
+	resources := make([]map[string]interface{}, 0, len(rows))
+	for _, user := range database.ConvertUserRows(rows) {
+		resource, err := scim.ToResource(api.scimUser(user, userGroups[user.ID]))
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+		resources = append(resources, resource)
+	}
+	scim.Write(rw, http.StatusOK, query.Apply(resources))
+}
+
+// @Summary SCIM 2.0: Get user by ID
+// @ID scim-get-user-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} coderd.SCIMUser
+// @Failure 404
+// @Router /scim/v2/Users/{id} [get]
+func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	sUser, _, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	writeSCIMResource(rw, r, http.StatusOK, sUser)
 }
 
 // scimPostUser creates a new user, or returns the existing user if it exists.
@@ -126,63 +383,54 @@ type SCIMUser struct {
 // @Produce json
 // @Tags Enterprise
 // @Param request body coderd.SCIMUser true "New user"
-// @Success 200 {object} coderd.SCIMUser
+// @Success 200 {object} coderd.SCIMUser "The user already exists"
+// @Success 201 {object} coderd.SCIMUser
 // @Router /scim/v2/Users [post]
 func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
-		return
-	}
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
 
 	var sUser SCIMUser
 	err := json.NewDecoder(r.Body).Decode(&sUser)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid user: %s", err))
 		return
 	}
 
-	email := ""
-	for _, e := range sUser.Emails {
-		if e.Primary {
-			email = e.Value
-			break
-		}
-	}
-
+	email := sUser.email()
 	if email == "" {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidEmail"})
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The user must have an email address."))
 		return
 	}
 
-	//nolint:gocritic
-	dbUser, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+	dbUser, err := api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
 		Email:    email,
 		Username: sUser.UserName,
 	})
 	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, err)
 		return
 	}
 	if err == nil {
-		sUser.ID = dbUser.ID.String()
-		sUser.UserName = dbUser.Username
-
 		if sUser.Active && dbUser.Status == database.UserStatusSuspended {
-			//nolint:gocritic
-			_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
+			dbUser, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
 				ID: dbUser.ID,
 				// The user will get transitioned to Active after logging in.
 				Status:    database.UserStatusDormant,
 				UpdatedAt: dbtime.Now(),
 			})
 			if err != nil {
-				_ = handlerutil.WriteError(rw, err)
+				scim.WriteError(rw, err)
 				return
 			}
 		}
 
-		httpapi.Write(ctx, rw, http.StatusOK, sUser)
+		existing, err := api.scimUserWithGroups(ctx, dbUser)
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+		scim.Write(rw, http.StatusOK, existing)
 		return
 	}
 
@@ -203,15 +451,13 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 	// TODO: This is a temporary solution that does not support multi-org
 	// 	deployments. This assumption places all new SCIM users into the
 	//	default organization.
-	//nolint:gocritic
-	defaultOrganization, err := api.Database.GetDefaultOrganization(dbauthz.AsSystemRestricted(ctx))
+	defaultOrganization, err := api.Database.GetDefaultOrganization(ctx)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, err)
 		return
 	}
 
-	//nolint:gocritic // needed for SCIM
-	dbUser, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
+	dbUser, _, err = api.AGPL.CreateUser(ctx, api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
 			Username:       sUser.UserName,
 			Email:          email,
@@ -220,75 +466,313 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		LoginType: database.LoginTypeOIDC,
 	})
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, err)
 		return
 	}
 
-	sUser.ID = dbUser.ID.String()
-	sUser.UserName = dbUser.Username
+	api.AGPL.PublishUserCreated(ctx, dbUser)
+
+	if name := sUser.realName(); name != "" && httpapi.UserRealNameValid(name) == nil {
+		dbUser, err = api.Database.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+			ID:        dbUser.ID,
+			Email:     dbUser.Email,
+			Username:  dbUser.Username,
+			AvatarURL: dbUser.AvatarURL,
+			UpdatedAt: dbtime.Now(),
+			Name:      name,
+		})
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+	created := api.scimUser(dbUser, nil)
+	rw.Header().Set("Location", created.Meta.Location)
+	scim.Write(rw, http.StatusCreated, created)
 }
 
-// scimPatchUser supports suspending and activating users only.
+// scimUserUpdate are the changes a PUT or PATCH request makes to a user. Nil
+// fields are unchanged. Usernames cannot be changed, since they are part of
+// workspace URLs.
+type scimUserUpdate struct {
+	active     *bool
+	email      *string
+	name       *string
+	givenName  *string
+	familyName *string
+}
+
+// scimPutUser replaces the attributes of a user.
 //
-// @Summary SCIM 2.0: Update user account
-// @ID scim-update-user-status
+// @Summary SCIM 2.0: Replace user
+// @ID scim-replace-user
 // @Security CoderSessionToken
+// @Accept application/scim+json
 // @Produce application/scim+json
 // @Tags Enterprise
 // @Param id path string true "User ID" format(uuid)
-// @Param request body coderd.SCIMUser true "Update user request"
-// @Success 200 {object} codersdk.User
-// @Router /scim/v2/Users/{id} [patch]
-func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+// @Param request body coderd.SCIMUser true "Replace user request"
+// @Success 200 {object} coderd.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (api *API) scimPutUser(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	_, dbUser, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
 		return
 	}
 
-	id := chi.URLParam(r, "id")
-
 	var sUser SCIMUser
-	err := json.NewDecoder(r.Body).Decode(&sUser)
+	err = json.NewDecoder(r.Body).Decode(&sUser)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid user: %s", err))
 		return
 	}
-	sUser.ID = id
 
-	uid, err := uuid.Parse(id)
+	update := scimUserUpdate{active: &sUser.Active}
+	if email := sUser.email(); email != "" {
+		update.email = &email
+	}
+	if name := sUser.realName(); name != "" {
+		update.name = &name
+	}
+	api.scimUpdateUser(ctx, rw, r, dbUser, update)
+}
+
+// scimPatchUser applies PATCH operations to the active, emails, displayName
+// and name attributes of a user. Operations on other attributes are ignored.
+// A SCIM user in the body instead of PATCH operations only changes whether
+// the user is active.
+//
+// @Summary SCIM 2.0: Update user account
+// @ID scim-update-user-status
+// @Security CoderSessionToken
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Param request body scim.PatchRequest true "Update user request"
+// @Success 200 {object} coderd.SCIMUser
+// @Router /scim/v2/Users/{id} [patch]
+func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	_, dbUser, err := api.scimUserByID(ctx, chi.URLParam(r, "id"))
 	if err != nil {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidId"})
+		scim.WriteError(rw, err)
 		return
 	}
 
-	//nolint:gocritic // needed for SCIM
-	dbUser, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), uid)
+	var body struct {
+		SCIMUser
+		Operations []scim.PatchOperation `json:"Operations"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid patch: %s", err))
+		return
+	}
+	if !scim.IsPatchRequest(body.Schemas) {
+		api.scimUpdateUser(ctx, rw, r, dbUser, scimUserUpdate{active: &body.Active})
 		return
 	}
 
-	var status database.UserStatus
-	if sUser.Active {
-		// The user will get transitioned to Active after logging in.
-		status = database.UserStatusDormant
-	} else {
-		status = database.UserStatusSuspended
+	var update scimUserUpdate
+	for _, op := range body.Operations {
+		err := update.apply(op)
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
 	}
+	api.scimUpdateUser(ctx, rw, r, dbUser, update)
+}
 
-	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
-		ID:        dbUser.ID,
-		Status:    status,
-		UpdatedAt: dbtime.Now(),
-	})
+// apply records the changes of a PATCH operation.
+func (u *scimUserUpdate) apply(op scim.PatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case "add", "replace", "remove":
+	default:
+		return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Unknown operation %q.", op.Op)
+	}
+
+	if op.Path == "" {
+		if kind == "remove" {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeNoTarget, "Remove operations must have a path.")
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of an operation without a path must be an object.")
+		}
+		for attribute, value := range values {
+			path, err := scim.ParsePath(attribute)
+			if err != nil {
+				return err
+			}
+			err = u.applyPath(path, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := scim.ParsePath(op.Path)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
-		return
+		return err
+	}
+	if kind == "remove" {
+		// None of the attributes that are stored can be removed, and
+		// removing the others is a no-op.
+		return nil
+	}
+	return u.applyPath(path, op.Value)
+}
+
+func (u *scimUserUpdate) applyPath(path scim.Path, value json.RawMessage) error {
+	str := func(dst **string) error {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of %s must be a string.", path.Attribute)
+		}
+		*dst = &s
+		return nil
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+	switch strings.ToLower(path.Attribute) {
+	case "active":
+		active, err := scim.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		u.active = &active
+	case "displayname":
+		return str(&u.name)
+	case "name":
+		switch strings.ToLower(path.SubAttribute) {
+		case "formatted":
+			return str(&u.name)
+		case "givenname":
+			return str(&u.givenName)
+		case "familyname":
+			return str(&u.familyName)
+		case "":
+			var name struct {
+				Formatted  *string `json:"formatted"`
+				GivenName  *string `json:"givenName"`
+				FamilyName *string `json:"familyName"`
+			}
+			if err := json.Unmarshal(value, &name); err != nil {
+				return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of name must be an object.")
+			}
+			if name.Formatted != nil {
+				u.name = name.Formatted
+			}
+			if name.GivenName != nil {
+				u.givenName = name.GivenName
+			}
+			if name.FamilyName != nil {
+				u.familyName = name.FamilyName
+			}
+		}
+	case "emails":
+		if path.SubAttribute != "" || path.ValueFilter != nil {
+			// Like emails[type eq "work"].value. Coder stores a single
+			// email address, so any email that is set becomes it.
+			if !strings.EqualFold(path.SubAttribute, "value") {
+				return nil
+			}
+			return str(&u.email)
+		}
+		var sUser SCIMUser
+		if err := json.Unmarshal([]byte(`{"emails":`+string(value)+`}`), &sUser); err != nil {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of emails must be an array of emails.")
+		}
+		if email := sUser.email(); email != "" {
+			u.email = &email
+		}
+	}
+	return nil
+}
+
+// scimUpdateUser applies the changes to the user and writes the updated user.
+func (api *API) scimUpdateUser(ctx context.Context, rw http.ResponseWriter, r *http.Request, dbUser database.User, update scimUserUpdate) {
+	if update.active != nil {
+		var status database.UserStatus
+		switch {
+		case *update.active && dbUser.Status == database.UserStatusSuspended:
+			// The user will get transitioned to Active after logging in.
+			status = database.UserStatusDormant
+		case !*update.active && dbUser.Status != database.UserStatusSuspended:
+			status = database.UserStatusSuspended
+		}
+		if status != "" {
+			var err error
+			dbUser, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+				ID:        dbUser.ID,
+				Status:    status,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				scim.WriteError(rw, err)
+				return
+			}
+		}
+	}
+
+	email, name := dbUser.Email, dbUser.Name
+	if update.email != nil && *update.email != "" {
+		email = *update.email
+	}
+	if update.givenName != nil || update.familyName != nil {
+		givenName, familyName, _ := strings.Cut(name, " ")
+		if update.givenName != nil {
+			givenName = *update.givenName
+		}
+		if update.familyName != nil {
+			familyName = *update.familyName
+		}
+		name = strings.TrimSpace(givenName + " " + familyName)
+	}
+	if update.name != nil {
+		name = *update.name
+	}
+	if name != dbUser.Name {
+		if err := httpapi.UserRealNameValid(name); err != nil {
+			scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "Invalid name %q: %s", name, err))
+			return
+		}
+	}
+
+	if email != dbUser.Email || name != dbUser.Name {
+		var err error
+		dbUser, err = api.Database.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+			ID:        dbUser.ID,
+			Email:     email,
+			Username:  dbUser.Username,
+			AvatarURL: dbUser.AvatarURL,
+			UpdatedAt: dbtime.Now(),
+			Name:      name,
+		})
+		if database.IsUniqueViolation(err) {
+			scim.WriteError(rw, scim.Errorf(http.StatusConflict, scim.ErrorTypeUniqueness, "The email %q is used by another user.", email))
+			return
+		}
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+	}
+
+	sUser, err := api.scimUserWithGroups(ctx, dbUser)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	writeSCIMResource(rw, r, http.StatusOK, sUser)
 }
@@ -0,0 +1,228 @@
+package scim
+
+// This file contains the discovery documents from RFC 7643 sections 5 to 7,
+// which describe what the service provider supports.
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type BulkConfig struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type FilterConfig struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SpecURI     string `json:"specUri,omitempty"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	DocumentationURI      string                 `json:"documentationUri,omitempty"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkConfig             `json:"bulk"`
+	Filter                FilterConfig           `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  Meta                   `json:"meta"`
+}
+
+// NewServiceProviderConfig returns the configuration of a service provider
+// with the base URL, like https://coder.example.com/scim/v2.
+func NewServiceProviderConfig(baseURL string) ServiceProviderConfig {
+	return ServiceProviderConfig{
+		Schemas:          []string{SchemaServiceProviderConfig},
+		DocumentationURI: "https://coder.com/docs/v2/latest/admin/auth#scim-enterprise",
+		Patch:            Supported{Supported: true},
+		Bulk:             BulkConfig{Supported: false},
+		Filter:           FilterConfig{Supported: true, MaxResults: MaxResults},
+		ChangePassword:   Supported{Supported: false},
+		Sort:             Supported{Supported: false},
+		ETag:             Supported{Supported: false},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with a SCIM token in the Authorization header.",
+			SpecURI:     "https://www.rfc-editor.org/rfc/rfc6750",
+			Primary:     true,
+		}},
+		Meta: Meta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     baseURL + "/ServiceProviderConfig",
+		},
+	}
+}
+
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        Meta     `json:"meta"`
+}
+
+// ResourceTypes returns the User and Group resource types.
+func ResourceTypes(baseURL string) []ResourceType {
+	return []ResourceType{
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "Coder users.",
+			Schema:      SchemaUser,
+			Meta: Meta{
+				ResourceType: "ResourceType",
+				Location:     baseURL + "/ResourceTypes/User",
+			},
+		},
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Groups of the default organization.",
+			Schema:      SchemaGroup,
+			Meta: Meta{
+				ResourceType: "ResourceType",
+				Location:     baseURL + "/ResourceTypes/Group",
+			},
+		},
+	}
+}
+
+type SchemaAttribute struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	MultiValued    bool              `json:"multiValued"`
+	Description    string            `json:"description"`
+	Required       bool              `json:"required"`
+	CaseExact      bool              `json:"caseExact"`
+	Mutability     string            `json:"mutability"`
+	Returned       string            `json:"returned"`
+	Uniqueness     string            `json:"uniqueness"`
+	SubAttributes  []SchemaAttribute `json:"subAttributes,omitempty"`
+	ReferenceTypes []string          `json:"referenceTypes,omitempty"`
+}
+
+type Schema struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Attributes  []SchemaAttribute `json:"attributes"`
+	Meta        Meta              `json:"meta"`
+}
+
+// attribute returns a single-valued, optional, read-write string attribute.
+func attribute(name, description string) SchemaAttribute {
+	return SchemaAttribute{
+		Name:        name,
+		Type:        "string",
+		Description: description,
+		Mutability:  "readWrite",
+		Returned:    "default",
+		Uniqueness:  "none",
+	}
+}
+
+// Schemas returns the User and Group schemas, limited to the attributes that
+// are stored.
+func Schemas(baseURL string) []Schema {
+	userName := attribute("userName", "The username of the user. Coder changes it to a valid username if needed, and it cannot be changed after the user is created.")
+	userName.Required = true
+	userName.Uniqueness = "server"
+
+	active := attribute("active", "Whether the user can log in. Inactive users are suspended.")
+	active.Type = "boolean"
+
+	emailValue := attribute("value", "The email address.")
+	emailType := attribute("type", "The type of email address, like work.")
+	emailPrimary := attribute("primary", "Whether this is the email address of the user in Coder.")
+	emailPrimary.Type = "boolean"
+	emails := attribute("emails", "The email addresses of the user. Coder stores the primary one.")
+	emails.MultiValued = true
+	emails.Type = "complex"
+	emails.SubAttributes = []SchemaAttribute{emailValue, emailType, emailPrimary}
+
+	name := attribute("name", "The name of the user.")
+	name.Type = "complex"
+	name.SubAttributes = []SchemaAttribute{
+		attribute("givenName", "The given name of the user."),
+		attribute("familyName", "The family name of the user."),
+	}
+
+	reference := func(name, description string, referenceTypes ...string) SchemaAttribute {
+		attr := attribute(name, description)
+		attr.MultiValued = true
+		attr.Type = "complex"
+		value := attribute("value", "The id of the referenced resource.")
+		value.Mutability = "immutable"
+		ref := attribute("$ref", "The URI of the referenced resource.")
+		ref.Type = "reference"
+		ref.Mutability = "immutable"
+		ref.ReferenceTypes = referenceTypes
+		display := attribute("display", "The name of the referenced resource.")
+		display.Mutability = "readOnly"
+		attr.SubAttributes = []SchemaAttribute{value, ref, display}
+		return attr
+	}
+	groups := reference("groups", "The groups the user is a member of. Change them with the Groups endpoint.", "Group")
+	groups.Mutability = "readOnly"
+	for i := range groups.SubAttributes {
+		groups.SubAttributes[i].Mutability = "readOnly"
+	}
+
+	groupName := attribute("displayName", "The name of the group.")
+	groupName.Required = true
+	groupName.Uniqueness = "server"
+
+	return []Schema{
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaUser,
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []SchemaAttribute{
+				userName,
+				name,
+				attribute("displayName", "The name of the user, shown in Coder."),
+				emails,
+				active,
+				groups,
+			},
+			Meta: Meta{
+				ResourceType: "Schema",
+				Location:     baseURL + "/Schemas/" + SchemaUser,
+			},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaGroup,
+			Name:        "Group",
+			Description: "Group",
+			Attributes: []SchemaAttribute{
+				groupName,
+				reference("members", "The users in the group.", "User"),
+			},
+			Meta: Meta{
+				ResourceType: "Schema",
+				Location:     baseURL + "/Schemas/" + SchemaGroup,
+			},
+		},
+	}
+}
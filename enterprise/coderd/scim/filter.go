@@ -0,0 +1,581 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Filter is a parsed filter expression from RFC 7644 section 3.4.2.2.
+//
+// Attribute names are case-insensitive and may be prefixed with the URN of
+// their schema. String comparisons are case-insensitive, which is what the
+// caseExact=false attributes of the core schemas require.
+type Filter struct {
+	root filterNode
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	userName eq "alice" and emails[type eq "work" and value co "@coder.com"]
+func ParseFilter(filter string) (*Filter, error) {
+	p, err := newParser(filter)
+	if err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return &Filter{root: root}, nil
+}
+
+// Match returns true if the resource matches the filter.
+func (f *Filter) Match(resource map[string]interface{}) bool {
+	return f.root.match(resource)
+}
+
+// Path is a parsed PATCH path from RFC 7644 section 3.5.2, such as
+// `members[value eq "2819c223"]` or `name.givenName`.
+type Path struct {
+	Attribute    string
+	SubAttribute string
+	// ValueFilter selects the values of a multi-valued attribute the
+	// operation applies to, nil if all values are selected.
+	ValueFilter *Filter
+}
+
+// ParsePath parses the path of a PATCH operation.
+func ParsePath(path string) (Path, error) {
+	p, err := newParser(path)
+	if err != nil {
+		return Path{}, invalidPath(err)
+	}
+	tok := p.next()
+	if tok.kind != tokenWord {
+		return Path{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidPath, "path %q must start with an attribute", path)
+	}
+	attr := parseAttrPath(tok.text)
+	parsed := Path{Attribute: attr.name, SubAttribute: attr.sub}
+	if p.peek().kind == tokenLBracket {
+		if attr.sub != "" {
+			return Path{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidPath, "path %q filters a sub-attribute", path)
+		}
+		p.next()
+		valueFilter, err := p.parseOr()
+		if err != nil {
+			return Path{}, invalidPath(err)
+		}
+		if p.next().kind != tokenRBracket {
+			return Path{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidPath, "path %q has an unterminated filter", path)
+		}
+		parsed.ValueFilter = &Filter{root: valueFilter}
+		// A sub-attribute may follow the filter, as in
+		// emails[type eq "work"].value.
+		if tok := p.peek(); tok.kind == tokenWord && strings.HasPrefix(tok.text, ".") {
+			p.next()
+			parsed.SubAttribute = strings.TrimPrefix(tok.text, ".")
+		}
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return Path{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidPath, "unexpected %q in path %q", tok.text, path)
+	}
+	return parsed, nil
+}
+
+func invalidPath(err error) error {
+	if scimErr, ok := err.(*Error); ok {
+		return Errorf(http.StatusBadRequest, ErrorTypeInvalidPath, "%s", scimErr.Detail)
+	}
+	return err
+}
+
+type filterNode interface {
+	match(resource map[string]interface{}) bool
+}
+
+type logicalNode struct {
+	and         bool
+	left, right filterNode
+}
+
+func (n logicalNode) match(resource map[string]interface{}) bool {
+	if n.and {
+		return n.left.match(resource) && n.right.match(resource)
+	}
+	return n.left.match(resource) || n.right.match(resource)
+}
+
+type notNode struct {
+	inner filterNode
+}
+
+func (n notNode) match(resource map[string]interface{}) bool {
+	return !n.inner.match(resource)
+}
+
+// valuePathNode matches if any value of a multi-valued attribute matches the
+// filter, as in emails[type eq "work"].
+type valuePathNode struct {
+	attribute string
+	filter    filterNode
+}
+
+func (n valuePathNode) match(resource map[string]interface{}) bool {
+	value, ok := Lookup(resource, n.attribute)
+	if !ok {
+		return false
+	}
+	for _, elem := range values(value) {
+		if obj, ok := elem.(map[string]interface{}); ok && n.filter.match(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareNode struct {
+	attr  attrPath
+	op    string
+	value interface{}
+}
+
+func (n compareNode) match(resource map[string]interface{}) bool {
+	actual := n.attr.resolve(resource)
+	if n.op == "pr" {
+		for _, value := range actual {
+			if present(value) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(actual) == 0 {
+		// A missing attribute is null.
+		actual = []interface{}{nil}
+	}
+	for _, value := range actual {
+		if compare(value, n.op, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// attrPath is an attribute, optionally with a sub-attribute, with the schema
+// URN removed.
+type attrPath struct {
+	name string
+	sub  string
+}
+
+func parseAttrPath(raw string) attrPath {
+	// The URN prefix contains dots and colons, as in
+	// urn:ietf:params:scim:schemas:core:2.0:User:name.givenName, so the
+	// attribute starts after the last colon.
+	if i := strings.LastIndex(raw, ":"); i >= 0 {
+		raw = raw[i+1:]
+	}
+	name, sub, _ := strings.Cut(raw, ".")
+	return attrPath{name: name, sub: sub}
+}
+
+// resolve returns the values of the attribute. Multi-valued attributes have
+// one value per element, and a multi-valued complex attribute without a
+// sub-attribute resolves to the value sub-attribute of its elements.
+func (a attrPath) resolve(resource map[string]interface{}) []interface{} {
+	value, ok := Lookup(resource, a.name)
+	if !ok || value == nil {
+		return nil
+	}
+	var resolved []interface{}
+	_, multiValued := value.([]interface{})
+	for _, elem := range values(value) {
+		obj, isObj := elem.(map[string]interface{})
+		switch {
+		case a.sub != "" && isObj:
+			if sub, ok := Lookup(obj, a.sub); ok {
+				resolved = append(resolved, values(sub)...)
+			}
+		case a.sub != "":
+		case multiValued && isObj:
+			if sub, ok := Lookup(obj, "value"); ok {
+				resolved = append(resolved, sub)
+			}
+		default:
+			resolved = append(resolved, elem)
+		}
+	}
+	return resolved
+}
+
+// Lookup returns the value of an attribute of a JSON object. Attribute names
+// are case-insensitive.
+func Lookup(obj map[string]interface{}, attribute string) (interface{}, bool) {
+	if value, ok := obj[attribute]; ok {
+		return value, true
+	}
+	for key, value := range obj {
+		if strings.EqualFold(key, attribute) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func values(value interface{}) []interface{} {
+	if arr, ok := value.([]interface{}); ok {
+		return arr
+	}
+	return []interface{}{value}
+}
+
+func present(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func compare(actual interface{}, op string, expected interface{}) bool {
+	if expected == nil || actual == nil {
+		switch op {
+		case "eq":
+			return actual == nil && expected == nil
+		case "ne":
+			return (actual == nil) != (expected == nil)
+		default:
+			return false
+		}
+	}
+
+	switch e := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return op == "ne"
+		}
+		return compareStrings(a, op, e)
+	case bool:
+		a, ok := actual.(bool)
+		if !ok {
+			return op == "ne"
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		}
+		return false
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return op == "ne"
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	}
+	return false
+}
+
+func compareStrings(actual, op, expected string) bool {
+	a, e := strings.ToLower(actual), strings.ToLower(expected)
+	switch op {
+	case "eq":
+		return a == e
+	case "ne":
+		return a != e
+	case "co":
+		return strings.Contains(a, e)
+	case "sw":
+		return strings.HasPrefix(a, e)
+	case "ew":
+		return strings.HasSuffix(a, e)
+	}
+
+	// Ordering compares dateTime attributes chronologically and other
+	// strings lexicographically.
+	c := strings.Compare(a, e)
+	at, aErr := time.Parse(time.RFC3339, actual)
+	et, eErr := time.Parse(time.RFC3339, expected)
+	if aErr == nil && eErr == nil {
+		c = at.Compare(et)
+	}
+	switch op {
+	case "gt":
+		return c > 0
+	case "ge":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "le":
+		return c <= 0
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func newParser(input string) (*parser, error) {
+	p := &parser{input: input}
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case c == '[':
+			p.tokens = append(p.tokens, token{kind: tokenLBracket, text: "["})
+			i++
+		case c == ']':
+			p.tokens = append(p.tokens, token{kind: tokenRBracket, text: "]"})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(input) && input[end] != '"' {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, p.errorf("unterminated string")
+			}
+			var s string
+			if err := json.Unmarshal([]byte(input[i:end+1]), &s); err != nil {
+				return nil, p.errorf("invalid string %s", input[i:end+1])
+			}
+			p.tokens = append(p.tokens, token{kind: tokenString, text: s})
+			i = end + 1
+		default:
+			end := i
+			for end < len(input) && !strings.ContainsRune(" \t\n\r()[]\"", rune(input[end])) {
+				end++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenWord, text: input[i:end]})
+			i = end
+		}
+	}
+	return p, nil
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	err := Errorf(http.StatusBadRequest, ErrorTypeInvalidFilter, format, args...)
+	err.Detail = "filter " + strconv.Quote(p.input) + ": " + err.Detail
+	return err
+}
+
+func (p *parser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (filterNode, error) {
+	if p.peekKeyword("not") {
+		p.next()
+		if p.peek().kind != tokenLParen {
+			return nil, p.errorf("expected ( after not")
+		}
+		inner, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *parser) parseAtom() (filterNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, p.errorf("expected )")
+		}
+		return inner, nil
+	case tokenWord:
+	case tokenEOF:
+		return nil, p.errorf("unexpected end of filter")
+	default:
+		return nil, p.errorf("expected an attribute, got %q", tok.text)
+	}
+
+	attr := parseAttrPath(tok.text)
+	if !validAttrName(attr.name) || (attr.sub != "" && !validAttrName(attr.sub)) {
+		return nil, p.errorf("invalid attribute %q", tok.text)
+	}
+	if p.peek().kind == tokenLBracket {
+		if attr.sub != "" {
+			return nil, p.errorf("cannot filter sub-attribute %q", tok.text)
+		}
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRBracket {
+			return nil, p.errorf("expected ]")
+		}
+		return valuePathNode{attribute: attr.name, filter: inner}, nil
+	}
+
+	opTok := p.next()
+	op := strings.ToLower(opTok.text)
+	if opTok.kind != tokenWord {
+		return nil, p.errorf("expected an operator after %q", tok.text)
+	}
+	switch op {
+	case "pr":
+		return compareNode{attr: attr, op: op}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, p.errorf("unknown operator %q", opTok.text)
+	}
+
+	valueTok := p.next()
+	var value interface{}
+	switch valueTok.kind {
+	case tokenString:
+		value = valueTok.text
+	case tokenWord:
+		switch strings.ToLower(valueTok.text) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			number, err := strconv.ParseFloat(valueTok.text, 64)
+			if err != nil {
+				return nil, p.errorf("invalid value %q", valueTok.text)
+			}
+			value = number
+		}
+	default:
+		return nil, p.errorf("expected a value after %q", opTok.text)
+	}
+	return compareNode{attr: attr, op: op, value: value}, nil
+}
+
+func validAttrName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '_' || r == '-'):
+		case i == 0 && r == '$':
+			// $ref
+		default:
+			return false
+		}
+	}
+	return true
+}
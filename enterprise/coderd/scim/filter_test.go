@@ -0,0 +1,127 @@
+package scim_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	user := map[string]interface{}{
+		"schemas":     []interface{}{scim.SchemaUser},
+		"id":          "2819c223",
+		"userName":    "Alice",
+		"displayName": "Alice Smith",
+		"active":      true,
+		"name": map[string]interface{}{
+			"givenName":  "Alice",
+			"familyName": "Smith",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "alice@coder.com", "type": "work", "primary": true},
+			map[string]interface{}{"value": "alice@example.com", "type": "home"},
+		},
+		"meta": map[string]interface{}{
+			"created": "2024-01-02T03:04:05Z",
+		},
+	}
+
+	for _, tc := range []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "alice"`, true},
+		{`USERNAME Eq "ALICE"`, true},
+		{`userName eq "bob"`, false},
+		{`userName ne "bob"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "alice"`, true},
+		{`displayName co "smi"`, true},
+		{`displayName sw "smith"`, false},
+		{`displayName ew "smith"`, true},
+		{`name.familyName eq "Smith"`, true},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`nickName pr`, false},
+		{`name pr`, true},
+		{`emails.value eq "alice@example.com"`, true},
+		{`emails[type eq "work" and value co "@coder.com"]`, true},
+		{`emails[type eq "home" and value co "@coder.com"]`, false},
+		{`userName eq "bob" or active eq true`, true},
+		{`userName eq "alice" and not (active eq true)`, false},
+		{`(userName eq "bob" or userName eq "alice") and name.givenName sw "a"`, true},
+		{`meta.created gt "2024-01-01T00:00:00Z"`, true},
+		{`meta.created lt "2024-01-02T03:04:05+01:00"`, false},
+		{`id eq null`, false},
+	} {
+		tc := tc
+		t.Run(tc.filter, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := scim.ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, f.Match(user))
+		})
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, filter := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName is "alice"`,
+		`userName eq "alice`,
+		`(userName eq "alice"`,
+		`userName eq "alice" and`,
+		`emails[type eq "work"`,
+		`userName eq "alice" active eq true`,
+	} {
+		filter := filter
+		t.Run(filter, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := scim.ParseFilter(filter)
+			var scimErr *scim.Error
+			require.ErrorAs(t, err, &scimErr)
+			assert.Equal(t, scim.ErrorTypeInvalidFilter, scimErr.Type)
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	path, err := scim.ParsePath("name.givenName")
+	require.NoError(t, err)
+	assert.Equal(t, "name", path.Attribute)
+	assert.Equal(t, "givenName", path.SubAttribute)
+	assert.Nil(t, path.ValueFilter)
+
+	path, err = scim.ParsePath(`members[value eq "2819c223"]`)
+	require.NoError(t, err)
+	assert.Equal(t, "members", path.Attribute)
+	assert.Empty(t, path.SubAttribute)
+	require.NotNil(t, path.ValueFilter)
+	assert.True(t, path.ValueFilter.Match(map[string]interface{}{"value": "2819c223"}))
+	assert.False(t, path.ValueFilter.Match(map[string]interface{}{"value": "902c246b"}))
+
+	path, err = scim.ParsePath(`emails[type eq "work"].value`)
+	require.NoError(t, err)
+	assert.Equal(t, "emails", path.Attribute)
+	assert.Equal(t, "value", path.SubAttribute)
+	require.NotNil(t, path.ValueFilter)
+
+	for _, invalid := range []string{``, `members[value eq "x"`, `name.givenName[value pr]`, `members[value]`} {
+		_, err := scim.ParsePath(invalid)
+		var scimErr *scim.Error
+		require.ErrorAs(t, err, &scimErr, invalid)
+		assert.Equal(t, scim.ErrorTypeInvalidPath, scimErr.Type, invalid)
+	}
+}
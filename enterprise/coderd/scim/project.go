@@ -0,0 +1,133 @@
+package scim
+
+import (
+	"strings"
+)
+
+// alwaysReturned are the attributes that are returned regardless of the
+// attributes and excludedAttributes parameters.
+var alwaysReturned = []string{"schemas", "id"}
+
+// Project applies the attributes and excludedAttributes parameters of a
+// request to a resource, as described in RFC 7644 section 3.9. Attributes may
+// name a sub-attribute, like name.givenName. If attributes is not empty,
+// excluded is ignored. The resource is not modified.
+func Project(resource map[string]interface{}, attributes, excluded []string) map[string]interface{} {
+	if len(attributes) > 0 {
+		projected := make(map[string]interface{})
+		for _, attribute := range alwaysReturned {
+			if value, ok := resource[attribute]; ok {
+				projected[attribute] = value
+			}
+		}
+		for _, raw := range attributes {
+			include(projected, resource, parseAttrPath(raw))
+		}
+		return projected
+	}
+
+	projected := make(map[string]interface{}, len(resource))
+	for key, value := range resource {
+		projected[key] = value
+	}
+	for _, raw := range excluded {
+		exclude(projected, parseAttrPath(raw))
+	}
+	return projected
+}
+
+func include(projected, resource map[string]interface{}, attr attrPath) {
+	key, ok := lookupKey(resource, attr.name)
+	if !ok {
+		return
+	}
+	value := resource[key]
+	if attr.sub == "" {
+		projected[key] = value
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		sub, ok := lookupKey(v, attr.sub)
+		if !ok {
+			return
+		}
+		dst, _ := projected[key].(map[string]interface{})
+		if dst == nil {
+			dst = make(map[string]interface{})
+			projected[key] = dst
+		}
+		dst[sub] = v[sub]
+	case []interface{}:
+		dst, _ := projected[key].([]interface{})
+		if dst == nil {
+			dst = make([]interface{}, len(v))
+			for i := range dst {
+				dst[i] = make(map[string]interface{})
+			}
+			projected[key] = dst
+		}
+		for i, elem := range v {
+			obj, ok := elem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if sub, ok := lookupKey(obj, attr.sub); ok {
+				dst[i].(map[string]interface{})[sub] = obj[sub]
+			}
+		}
+	}
+}
+
+func exclude(projected map[string]interface{}, attr attrPath) {
+	key, ok := lookupKey(projected, attr.name)
+	if !ok {
+		return
+	}
+	for _, always := range alwaysReturned {
+		if key == always {
+			return
+		}
+	}
+	if attr.sub == "" {
+		delete(projected, key)
+		return
+	}
+
+	without := func(obj map[string]interface{}) map[string]interface{} {
+		copied := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			if !strings.EqualFold(k, attr.sub) {
+				copied[k] = v
+			}
+		}
+		return copied
+	}
+	switch v := projected[key].(type) {
+	case map[string]interface{}:
+		projected[key] = without(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, elem := range v {
+			if obj, ok := elem.(map[string]interface{}); ok {
+				copied[i] = without(obj)
+			} else {
+				copied[i] = elem
+			}
+		}
+		projected[key] = copied
+	}
+}
+
+func lookupKey(obj map[string]interface{}, attribute string) (string, bool) {
+	if _, ok := obj[attribute]; ok {
+		return attribute, true
+	}
+	for key := range obj {
+		if strings.EqualFold(key, attribute) {
+			return key, true
+		}
+	}
+	return "", false
+}
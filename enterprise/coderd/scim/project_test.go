@@ -0,0 +1,113 @@
+package scim_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+
+	resource := map[string]interface{}{
+		"schemas":  []interface{}{scim.SchemaUser},
+		"id":       "2819c223",
+		"userName": "alice",
+		"name": map[string]interface{}{
+			"givenName":  "Alice",
+			"familyName": "Smith",
+		},
+		"emails": []interface{}{
+			map[string]interface{}{"value": "alice@coder.com", "type": "work"},
+		},
+	}
+
+	t.Run("Attributes", func(t *testing.T) {
+		t.Parallel()
+
+		projected := scim.Project(resource, []string{"USERNAME", "name.givenName", "emails.value"}, []string{"userName"})
+		assert.Equal(t, map[string]interface{}{
+			"schemas":  []interface{}{scim.SchemaUser},
+			"id":       "2819c223",
+			"userName": "alice",
+			"name":     map[string]interface{}{"givenName": "Alice"},
+			"emails": []interface{}{
+				map[string]interface{}{"value": "alice@coder.com"},
+			},
+		}, projected)
+	})
+
+	t.Run("Excluded", func(t *testing.T) {
+		t.Parallel()
+
+		projected := scim.Project(resource, nil, []string{"id", "emails", "name.familyName"})
+		assert.Equal(t, map[string]interface{}{
+			"schemas":  []interface{}{scim.SchemaUser},
+			"id":       "2819c223",
+			"userName": "alice",
+			"name":     map[string]interface{}{"givenName": "Alice"},
+		}, projected)
+		// The resource is not modified.
+		assert.Contains(t, resource, "emails")
+		assert.Contains(t, resource["name"], "familyName")
+	})
+}
+
+func TestListQuery(t *testing.T) {
+	t.Parallel()
+
+	resources := []map[string]interface{}{
+		{"id": "1", "userName": "alice"},
+		{"id": "2", "userName": "bob"},
+		{"id": "3", "userName": "carol"},
+	}
+
+	t.Run("Paginate", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := scim.ParseListQuery(httptest.NewRequest("GET", "/Users?startIndex=2&count=1", nil))
+		require.NoError(t, err)
+		res := query.Apply(resources)
+		assert.Equal(t, 3, res.TotalResults)
+		assert.Equal(t, 2, res.StartIndex)
+		assert.Equal(t, 1, res.ItemsPerPage)
+		require.Len(t, res.Resources, 1)
+		assert.Equal(t, "bob", res.Resources[0].(map[string]interface{})["userName"])
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := scim.ParseListQuery(httptest.NewRequest("GET", `/Users?filter=userName+ne+%22bob%22&attributes=id`, nil))
+		require.NoError(t, err)
+		res := query.Apply(resources)
+		assert.Equal(t, 2, res.TotalResults)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"id": "1"},
+			map[string]interface{}{"id": "3"},
+		}, res.Resources)
+	})
+
+	t.Run("PastEnd", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := scim.ParseListQuery(httptest.NewRequest("GET", "/Users?startIndex=10", nil))
+		require.NoError(t, err)
+		res := query.Apply(resources)
+		assert.Equal(t, 3, res.TotalResults)
+		assert.Empty(t, res.Resources)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := scim.ParseListQuery(httptest.NewRequest("GET", "/Users?count=many", nil))
+		var scimErr *scim.Error
+		require.ErrorAs(t, err, &scimErr)
+		assert.Equal(t, scim.ErrorTypeInvalidValue, scimErr.Type)
+	})
+}
@@ -0,0 +1,293 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7643 and RFC 7644) that
+// do not depend on how resources are stored: filters, PATCH paths, attribute
+// projection, list responses, errors and the discovery documents.
+//
+// Resources are handled as JSON objects, so a handler converts its resource to
+// a map with ToResource before filtering and projecting it.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// ContentType is the media type of SCIM requests and responses.
+	ContentType = "application/scim+json"
+
+	// MaxResults is the largest page a list request returns.
+	MaxResults = 1000
+)
+
+// Error types from RFC 7644 section 3.12.
+const (
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeTooMany       = "tooMany"
+	ErrorTypeUniqueness    = "uniqueness"
+	ErrorTypeMutability    = "mutability"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeInvalidValue  = "invalidValue"
+)
+
+// Error is a SCIM error response.
+type Error struct {
+	Status int
+	// Type is the scimType of the error. Only some 400 and 409 errors have
+	// one.
+	Type   string
+	Detail string
+}
+
+// Errorf returns an error with the given status, scimType and detail.
+func Errorf(status int, scimType string, format string, args ...interface{}) *Error {
+	return &Error{
+		Status: status,
+		Type:   scimType,
+		Detail: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("%d: %s", e.Status, e.Detail)
+	}
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Type, e.Detail)
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Schemas []string `json:"schemas"`
+		// The status is a string in SCIM.
+		Status string `json:"status"`
+		Type   string `json:"scimType,omitempty"`
+		Detail string `json:"detail,omitempty"`
+	}{
+		Schemas: []string{SchemaError},
+		Status:  strconv.Itoa(e.Status),
+		Type:    e.Type,
+		Detail:  e.Detail,
+	})
+}
+
+// Write writes a SCIM response.
+func Write(rw http.ResponseWriter, status int, response interface{}) {
+	rw.Header().Set("Content-Type", ContentType)
+	rw.WriteHeader(status)
+	enc := json.NewEncoder(rw)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(response)
+}
+
+// WriteError writes err as a SCIM error response. Errors that are not an
+// *Error are written as internal server errors.
+func WriteError(rw http.ResponseWriter, err error) {
+	var scimErr *Error
+	if !errors.As(err, &scimErr) {
+		scimErr = &Error{
+			Status: http.StatusInternalServerError,
+			Detail: err.Error(),
+		}
+	}
+	Write(rw, scimErr.Status, scimErr)
+}
+
+// Meta is the meta attribute of a resource.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// ListResponse is the response of a list or query request.
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// NewListResponse returns a list response with all the given resources.
+func NewListResponse[T any](resources []T) ListResponse {
+	res := ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    make([]interface{}, 0, len(resources)),
+	}
+	for _, resource := range resources {
+		res.Resources = append(res.Resources, resource)
+	}
+	return res
+}
+
+// ListQuery are the query parameters of a list request.
+type ListQuery struct {
+	// Filter is nil if the request has no filter.
+	Filter *Filter
+	// StartIndex is the 1-based index of the first result.
+	StartIndex int
+	// Count is the maximum number of results.
+	Count              int
+	Attributes         []string
+	ExcludedAttributes []string
+}
+
+// ParseListQuery reads the filter, pagination and projection parameters of a
+// list request.
+func ParseListQuery(r *http.Request) (ListQuery, error) {
+	values := r.URL.Query()
+	query := ListQuery{
+		StartIndex:         1,
+		Count:              MaxResults,
+		Attributes:         splitAttributes(values.Get("attributes")),
+		ExcludedAttributes: splitAttributes(values.Get("excludedAttributes")),
+	}
+	if filter := values.Get("filter"); filter != "" {
+		f, err := ParseFilter(filter)
+		if err != nil {
+			return ListQuery{}, err
+		}
+		query.Filter = f
+	}
+	if raw := values.Get("startIndex"); raw != "" {
+		startIndex, err := strconv.Atoi(raw)
+		if err != nil {
+			return ListQuery{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidValue, "startIndex %q is not an integer", raw)
+		}
+		// Values less than 1 are interpreted as 1.
+		if startIndex > 1 {
+			query.StartIndex = startIndex
+		}
+	}
+	if raw := values.Get("count"); raw != "" {
+		count, err := strconv.Atoi(raw)
+		if err != nil {
+			return ListQuery{}, Errorf(http.StatusBadRequest, ErrorTypeInvalidValue, "count %q is not an integer", raw)
+		}
+		// Negative values are interpreted as 0.
+		query.Count = min(max(count, 0), MaxResults)
+	}
+	return query, nil
+}
+
+// Apply filters, paginates and projects the resources.
+func (q ListQuery) Apply(resources []map[string]interface{}) ListResponse {
+	matched := make([]map[string]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		if q.Filter == nil || q.Filter.Match(resource) {
+			matched = append(matched, resource)
+		}
+	}
+
+	res := ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(matched),
+		StartIndex:   q.StartIndex,
+		Resources:    []interface{}{},
+	}
+	if q.StartIndex > len(matched) {
+		return res
+	}
+	page := matched[q.StartIndex-1:]
+	if len(page) > q.Count {
+		page = page[:q.Count]
+	}
+	for _, resource := range page {
+		res.Resources = append(res.Resources, Project(resource, q.Attributes, q.ExcludedAttributes))
+	}
+	res.ItemsPerPage = len(res.Resources)
+	return res
+}
+
+// ProjectionQuery reads the attributes and excludedAttributes parameters of
+// a request that returns a single resource.
+func ProjectionQuery(r *http.Request) (attributes []string, excluded []string) {
+	values := r.URL.Query()
+	return splitAttributes(values.Get("attributes")), splitAttributes(values.Get("excludedAttributes"))
+}
+
+func splitAttributes(raw string) []string {
+	var attributes []string
+	for _, attribute := range strings.Split(raw, ",") {
+		attribute = strings.TrimSpace(attribute)
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// ToResource converts a resource to its JSON object.
+func ToResource(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal resource: %w", err)
+	}
+	var resource map[string]interface{}
+	err = json.Unmarshal(data, &resource)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal resource: %w", err)
+	}
+	return resource, nil
+}
+
+// PatchRequest is the body of a PATCH request.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single operation of a PATCH request. Op is one of
+// "add", "remove" or "replace", case-insensitively.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// IsPatchRequest returns true if the schemas of a request body are the ones
+// of a PATCH request.
+func IsPatchRequest(schemas []string) bool {
+	for _, schema := range schemas {
+		if strings.EqualFold(schema, SchemaPatchOp) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBool decodes a boolean value. Some identity providers send booleans as
+// strings, like "False".
+func ParseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, Errorf(http.StatusBadRequest, ErrorTypeInvalidValue, "%s is not a boolean", string(raw))
+}
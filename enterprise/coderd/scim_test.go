@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -51,6 +53,49 @@ func setScimAuth(key []byte) func(*http.Request) {
 	}
 }
 
+// scimClient returns a client for a deployment with SCIM enabled, and the
+// SCIM API key of the deployment.
+func scimClient(t *testing.T) (*codersdk.Client, []byte) {
+	t.Helper()
+
+	scimAPIKey := []byte("hi")
+	client, _ := coderdenttest.New(t, &coderdenttest.Options{
+		SCIMAPIKey: scimAPIKey,
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			AccountID: "coolin",
+			Features: license.Features{
+				codersdk.FeatureSCIM:         1,
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	return client, scimAPIKey
+}
+
+// scimDo makes a SCIM request and decodes the response into out if it is not
+// nil. It returns the status code.
+func scimDo(ctx context.Context, t *testing.T, client *codersdk.Client, method, path string, body interface{}, key []byte, out interface{}) int {
+	t.Helper()
+
+	res, err := client.Request(ctx, method, path, body, setScimAuth(key))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+	}
+	return res.StatusCode
+}
+
+// scimCreateUser creates a user with SCIM.
+func scimCreateUser(ctx context.Context, t *testing.T, client *codersdk.Client, key []byte) coderd.SCIMUser {
+	t.Helper()
+
+	sUser := makeScimUser(t)
+	status := scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Users", sUser, key, &sUser)
+	require.Equal(t, http.StatusCreated, status)
+	return sUser
+}
+
 //nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
 func TestScim(t *testing.T) {
 	t.Parallel()
@@ -99,7 +144,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", struct{}{})
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -123,7 +168,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 
 			userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: sUser.Emails[0].Value})
 			require.NoError(t, err)
@@ -155,7 +200,12 @@ func TestScim(t *testing.T) {
 				res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 				require.NoError(t, err)
 				_ = res.Body.Close()
-				assert.Equal(t, http.StatusOK, res.StatusCode)
+				// Only the first request creates the user.
+				if i == 0 {
+					assert.Equal(t, http.StatusCreated, res.StatusCode)
+				} else {
+					assert.Equal(t, http.StatusOK, res.StatusCode)
+				}
 			}
 
 			userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: sUser.Emails[0].Value})
@@ -187,7 +237,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 			err = json.NewDecoder(res.Body).Decode(&sUser)
 			require.NoError(t, err)
 
@@ -237,7 +287,7 @@ func TestScim(t *testing.T) {
 			require.NoError(t, err)
 			_, _ = io.Copy(io.Discard, res.Body)
 			_ = res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 
 			userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: sUser.Emails[0].Value})
 			require.NoError(t, err)
@@ -296,7 +346,7 @@ func TestScim(t *testing.T) {
 			require.NoError(t, err)
 			_, _ = io.Copy(io.Discard, res.Body)
 			_ = res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -320,7 +370,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 
 			err = json.NewDecoder(res.Body).Decode(&sUser)
 			require.NoError(t, err)
@@ -339,4 +389,238 @@ func TestScim(t *testing.T) {
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
 	})
+
+	t.Run("getUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		sUser := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		var got coderd.SCIMUser
+		status := scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users/"+sUser.ID, nil, scimAPIKey, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, sUser.ID, got.ID)
+		assert.Equal(t, sUser.UserName, got.UserName)
+		assert.Equal(t, sUser.Emails[0].Value, got.Emails[0].Value)
+		assert.True(t, got.Active)
+		assert.Equal(t, "User", got.Meta.ResourceType)
+
+		var projected map[string]interface{}
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users/"+sUser.ID+"?attributes=userName", nil, scimAPIKey, &projected)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, sUser.UserName, projected["userName"])
+		assert.Equal(t, sUser.ID, projected["id"])
+		assert.NotContains(t, projected, "emails")
+
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users/"+uuid.NewString(), nil, scimAPIKey, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users/bob", nil, scimAPIKey, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("listUsers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		sUser := scimCreateUser(ctx, t, client, scimAPIKey)
+		_ = scimCreateUser(ctx, t, client, scimAPIKey)
+
+		var list struct {
+			TotalResults int               `json:"totalResults"`
+			Resources    []coderd.SCIMUser `json:"Resources"`
+		}
+		status := scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users", nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		// The two users and the first user of the deployment.
+		assert.Equal(t, 3, list.TotalResults)
+
+		filter := url.QueryEscape(fmt.Sprintf("userName eq %q", sUser.UserName))
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users?filter="+filter, nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+		assert.Equal(t, sUser.ID, list.Resources[0].ID)
+
+		filter = url.QueryEscape(fmt.Sprintf("emails[value eq %q] and active eq true", sUser.Emails[0].Value))
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users?filter="+filter, nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users?startIndex=2&count=1", nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Len(t, list.Resources, 1)
+
+		var scimErr struct {
+			SCIMType string `json:"scimType"`
+		}
+		filter = url.QueryEscape("userName eq")
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users?filter="+filter, nil, scimAPIKey, &scimErr)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalidFilter", scimErr.SCIMType)
+	})
+
+	t.Run("putUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		sUser := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		sUser.Active = false
+		sUser.DisplayName = "Alice Smith"
+		sUser.Emails[0].Value = "new-" + sUser.Emails[0].Value
+		var got coderd.SCIMUser
+		status := scimDo(ctx, t, client, http.MethodPut, "/scim/v2/Users/"+sUser.ID, sUser, scimAPIKey, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, got.Active)
+		assert.Equal(t, "Alice Smith", got.DisplayName)
+		assert.Equal(t, sUser.Emails[0].Value, got.Emails[0].Value)
+
+		user, err := client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+		assert.Equal(t, sUser.Emails[0].Value, user.Email)
+		assert.Equal(t, "Alice Smith", user.Name)
+
+		// Emails must stay unique.
+		other := scimCreateUser(ctx, t, client, scimAPIKey)
+		other.Emails[0].Value = sUser.Emails[0].Value
+		status = scimDo(ctx, t, client, http.MethodPut, "/scim/v2/Users/"+other.ID, other, scimAPIKey, nil)
+		assert.Equal(t, http.StatusConflict, status)
+	})
+
+	t.Run("patchOp", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		sUser := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		// Azure AD sends booleans as strings.
+		patch := json.RawMessage(`{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [
+				{"op": "Replace", "path": "active", "value": "False"},
+				{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "patched@coder.com"},
+				{"op": "replace", "value": {"name.givenName": "Patched"}},
+				{"op": "replace", "path": "externalId", "value": "ignored"}
+			]
+		}`)
+		var got coderd.SCIMUser
+		status := scimDo(ctx, t, client, http.MethodPatch, "/scim/v2/Users/"+sUser.ID, patch, scimAPIKey, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, got.Active)
+		assert.Equal(t, "patched@coder.com", got.Emails[0].Value)
+		assert.Equal(t, "Patched", got.Name.GivenName)
+
+		user, err := client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+		assert.Equal(t, "patched@coder.com", user.Email)
+
+		patch = json.RawMessage(`{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "move", "path": "active", "value": true}]
+		}`)
+		status = scimDo(ctx, t, client, http.MethodPatch, "/scim/v2/Users/"+sUser.ID, patch, scimAPIKey, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("bearerToken", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+
+		// The API key also works as a bearer token.
+		status := scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users", nil, append([]byte("Bearer "), scimAPIKey...), nil)
+		assert.Equal(t, http.StatusOK, status)
+
+		token, err := client.CreateSCIMToken(ctx, codersdk.CreateSCIMTokenRequest{Name: "okta"})
+		require.NoError(t, err)
+		bearer := []byte("Bearer " + token.Token)
+
+		sUser := makeScimUser(t)
+		status = scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Users", sUser, bearer, nil)
+		assert.Equal(t, http.StatusCreated, status)
+
+		tokens, err := client.SCIMTokens(ctx)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.True(t, tokens[0].LastUsedAt.Valid)
+
+		// A token that does not exist, and one with the wrong secret.
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users", nil, []byte("Bearer coder_nope_nope"), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users", nil, append(bearer, 'x'), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		err = client.DeleteSCIMToken(ctx, token.ID)
+		require.NoError(t, err)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users", nil, bearer, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+
+		res, err := client.Request(ctx, http.MethodGet, "/scim/v2/ServiceProviderConfig", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/scim+json", res.Header.Get("Content-Type"))
+		var config struct {
+			Patch struct {
+				Supported bool `json:"supported"`
+			} `json:"patch"`
+			Filter struct {
+				Supported bool `json:"supported"`
+			} `json:"filter"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+		assert.True(t, config.Patch.Supported)
+		assert.True(t, config.Filter.Supported)
+
+		var list struct {
+			TotalResults int `json:"totalResults"`
+		}
+		status := scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Schemas", nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, list.TotalResults)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/ResourceTypes", nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, list.TotalResults)
+
+		var schema struct {
+			ID string `json:"id"`
+		}
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Schemas/urn:ietf:params:scim:schemas:core:2.0:Group", nil, scimAPIKey, &schema)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "urn:ietf:params:scim:schemas:core:2.0:Group", schema.ID)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/ResourceTypes/User", nil, scimAPIKey, &schema)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "User", schema.ID)
+
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/ResourceTypes/Widget", nil, scimAPIKey, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Schemas?filter=id+pr", nil, scimAPIKey, nil)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
 }
@@ -0,0 +1,615 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+// SCIMGroup is a group of the default organization. The Everyone group is
+// not exposed, since its members cannot be changed.
+type SCIMGroup struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id"`
+	DisplayName string          `json:"displayName"`
+	Members     []SCIMReference `json:"members"`
+	Meta        scim.Meta       `json:"meta"`
+}
+
+type scimGroupWithMembers struct {
+	database.Group
+	members []database.User
+}
+
+// scimVisibleGroups removes the Everyone group.
+func scimVisibleGroups(groups []database.Group) []database.Group {
+	visible := make([]database.Group, 0, len(groups))
+	for _, group := range groups {
+		if group.ID == group.OrganizationID {
+			continue
+		}
+		visible = append(visible, group)
+	}
+	return visible
+}
+
+// scimGroups returns the groups of the organization that are visible to SCIM,
+// with their members.
+func (api *API) scimGroups(ctx context.Context, organizationID uuid.UUID) ([]scimGroupWithMembers, error) {
+	groups, err := api.Database.GetGroupsByOrganizationID(ctx, organizationID)
+	if err != nil {
+		return nil, xerrors.Errorf("get groups: %w", err)
+	}
+	withMembers := make([]scimGroupWithMembers, 0, len(groups))
+	for _, group := range scimVisibleGroups(groups) {
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get group members: %w", err)
+		}
+		withMembers = append(withMembers, scimGroupWithMembers{Group: group, members: members})
+	}
+	return withMembers, nil
+}
+
+// scimGroupByID returns a group of the default organization with its members.
+func (api *API) scimGroupByID(ctx context.Context, rawID string) (scimGroupWithMembers, error) {
+	notFound := scim.Errorf(http.StatusNotFound, "", "Group %q not found.", rawID)
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return scimGroupWithMembers{}, notFound
+	}
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		return scimGroupWithMembers{}, xerrors.Errorf("get default organization: %w", err)
+	}
+	group, err := api.Database.GetGroupByID(ctx, id)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return scimGroupWithMembers{}, notFound
+	}
+	if err != nil {
+		return scimGroupWithMembers{}, xerrors.Errorf("get group: %w", err)
+	}
+	if group.OrganizationID != org.ID || group.ID == org.ID {
+		return scimGroupWithMembers{}, notFound
+	}
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return scimGroupWithMembers{}, xerrors.Errorf("get group members: %w", err)
+	}
+	return scimGroupWithMembers{Group: group, members: members}, nil
+}
+
+func (api *API) scimGroup(group scimGroupWithMembers) SCIMGroup {
+	base := api.scimBaseURL()
+	sGroup := SCIMGroup{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMReference, 0, len(group.members)),
+		Meta: scim.Meta{
+			ResourceType: "Group",
+			Location:     base + "/Groups/" + group.ID.String(),
+		},
+	}
+	for _, member := range group.members {
+		sGroup.Members = append(sGroup.Members, SCIMReference{
+			Value:   member.ID.String(),
+			Ref:     base + "/Users/" + member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	return sGroup
+}
+
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter"
+// @Param startIndex query int false "Start index"
+// @Param count query int false "Count"
+// @Param attributes query string false "Attributes"
+// @Param excludedAttributes query string false "Excluded attributes"
+// @Success 200 {object} scim.ListResponse
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	query, err := scim.ParseListQuery(r)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	groups, err := api.scimGroups(ctx, org.ID)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	resources := make([]map[string]interface{}, 0, len(groups))
+	for _, group := range groups {
+		resource, err := scim.ToResource(api.scimGroup(group))
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+		resources = append(resources, resource)
+	}
+	scim.Write(rw, http.StatusOK, query.Apply(resources))
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	writeSCIMResource(rw, r, http.StatusOK, api.scimGroup(group))
+}
+
+// scimPostGroup creates a group in the default organization. Groups created
+// with SCIM are not changed by OIDC group sync.
+//
+// @Summary SCIM 2.0: Create group
+// @ID scim-create-group
+// @Security CoderSessionToken
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid group: %s", err))
+		return
+	}
+	if sGroup.DisplayName == "" {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The group must have a displayName."))
+		return
+	}
+	if sGroup.DisplayName == database.EveryoneGroup {
+		scim.WriteError(rw, scim.Errorf(http.StatusConflict, scim.ErrorTypeUniqueness, "The name %q is reserved.", database.EveryoneGroup))
+		return
+	}
+
+	org, err := api.Database.GetDefaultOrganization(ctx)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	memberIDs, err := api.scimMemberIDs(ctx, org.ID, sGroup.Members)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+
+	var group database.Group
+	err = api.Database.InTx(func(tx database.Store) error {
+		groups, err := tx.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+			OrganizationID: org.ID,
+			Source:         database.GroupSourceScim,
+			GroupNames:     []string{sGroup.DisplayName},
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		if len(groups) == 0 {
+			return scim.Errorf(http.StatusConflict, scim.ErrorTypeUniqueness, "A group named %q already exists.", sGroup.DisplayName)
+		}
+		group = groups[0]
+		return scimSetGroupMembers(ctx, tx, group.ID, nil, memberIDs)
+	}, nil)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+
+	created, err := api.scimGroupByID(ctx, group.ID.String())
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	sCreated := api.scimGroup(created)
+	rw.Header().Set("Location", sCreated.Meta.Location)
+	scim.Write(rw, http.StatusCreated, sCreated)
+}
+
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security CoderSessionToken
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err = json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid group: %s", err))
+		return
+	}
+	name := group.Name
+	if sGroup.DisplayName != "" {
+		name = sGroup.DisplayName
+	}
+	memberIDs, err := api.scimMemberIDs(ctx, group.OrganizationID, sGroup.Members)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	api.scimUpdateGroup(ctx, rw, r, group, name, memberIDs)
+}
+
+// scimPatchGroup applies PATCH operations to the displayName and members of a
+// group.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Accept application/scim+json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body scim.PatchRequest true "Update group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+
+	var req scim.PatchRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Invalid patch: %s", err))
+		return
+	}
+	if !scim.IsPatchRequest(req.Schemas) {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "The request must have the %s schema.", scim.SchemaPatchOp))
+		return
+	}
+
+	patch := scimGroupPatch{
+		name:    group.Name,
+		members: make([]SCIMReference, 0, len(group.members)),
+	}
+	for _, member := range group.members {
+		patch.members = append(patch.members, SCIMReference{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	for _, op := range req.Operations {
+		err := patch.apply(op)
+		if err != nil {
+			scim.WriteError(rw, err)
+			return
+		}
+	}
+
+	memberIDs, err := api.scimMemberIDs(ctx, group.OrganizationID, patch.members)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	api.scimUpdateGroup(ctx, rw, r, group, patch.name, memberIDs)
+}
+
+// scimGroupPatch is the state of a group while PATCH operations are applied.
+type scimGroupPatch struct {
+	name    string
+	members []SCIMReference
+}
+
+func (p *scimGroupPatch) apply(op scim.PatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case "add", "replace", "remove":
+	default:
+		return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "Unknown operation %q.", op.Op)
+	}
+
+	if op.Path == "" {
+		if kind == "remove" {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeNoTarget, "Remove operations must have a path.")
+		}
+		var value struct {
+			DisplayName *string          `json:"displayName"`
+			Members     *[]SCIMReference `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of an operation without a path must be a group.")
+		}
+		if value.DisplayName != nil {
+			p.name = *value.DisplayName
+		}
+		if value.Members != nil {
+			p.addMembers(kind == "replace", *value.Members)
+		}
+		return nil
+	}
+
+	path, err := scim.ParsePath(op.Path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path.Attribute) {
+	case "displayname":
+		if kind == "remove" {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeMutability, "The displayName of a group is required.")
+		}
+		if err := json.Unmarshal(op.Value, &p.name); err != nil {
+			return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of displayName must be a string.")
+		}
+	case "members":
+		if kind != "remove" {
+			if path.ValueFilter != nil {
+				return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidPath, "Members can only be filtered when they are removed.")
+			}
+			members, err := decodeSCIMReferences(op.Value)
+			if err != nil {
+				return err
+			}
+			p.addMembers(kind == "replace", members)
+			return nil
+		}
+		return p.removeMembers(path.ValueFilter, op.Value)
+	default:
+		return scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidPath, "The attribute %q of groups cannot be changed.", path.Attribute)
+	}
+	return nil
+}
+
+func (p *scimGroupPatch) addMembers(replace bool, members []SCIMReference) {
+	if replace {
+		p.members = nil
+	}
+	for _, member := range members {
+		exists := false
+		for _, existing := range p.members {
+			if strings.EqualFold(existing.Value, member.Value) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			p.members = append(p.members, member)
+		}
+	}
+}
+
+// removeMembers removes the members matching the filter, or the members in
+// the value. All members are removed if there is neither.
+func (p *scimGroupPatch) removeMembers(filter *scim.Filter, raw json.RawMessage) error {
+	var remove func(member SCIMReference) bool
+	switch {
+	case filter != nil:
+		remove = func(member SCIMReference) bool {
+			return filter.Match(map[string]interface{}{
+				"value":   member.Value,
+				"display": member.Display,
+			})
+		}
+	case len(raw) != 0 && string(raw) != "null":
+		members, err := decodeSCIMReferences(raw)
+		if err != nil {
+			return err
+		}
+		remove = func(member SCIMReference) bool {
+			for _, m := range members {
+				if strings.EqualFold(m.Value, member.Value) {
+					return true
+				}
+			}
+			return false
+		}
+	default:
+		remove = func(SCIMReference) bool { return true }
+	}
+
+	kept := make([]SCIMReference, 0, len(p.members))
+	for _, member := range p.members {
+		if !remove(member) {
+			kept = append(kept, member)
+		}
+	}
+	p.members = kept
+	return nil
+}
+
+// decodeSCIMReferences accepts a single reference or an array of them.
+func decodeSCIMReferences(raw json.RawMessage) ([]SCIMReference, error) {
+	var members []SCIMReference
+	if err := json.Unmarshal(raw, &members); err == nil {
+		return members, nil
+	}
+	var member SCIMReference
+	if err := json.Unmarshal(raw, &member); err != nil {
+		return nil, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The value of members must be an array of members.")
+	}
+	return []SCIMReference{member}, nil
+}
+
+// scimMemberIDs returns the IDs of the referenced users, which must be members
+// of the organization.
+func (api *API) scimMemberIDs(ctx context.Context, organizationID uuid.UUID, members []SCIMReference) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "Member %q is not a user.", member.Value)
+		}
+		_, err = api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: organizationID,
+			UserID:         id,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return nil, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "Member %q is not a user of the organization.", member.Value)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get organization member: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// scimUpdateGroup renames the group, sets its members and writes the updated
+// group.
+func (api *API) scimUpdateGroup(ctx context.Context, rw http.ResponseWriter, r *http.Request, group scimGroupWithMembers, name string, memberIDs []uuid.UUID) {
+	if name == "" {
+		scim.WriteError(rw, scim.Errorf(http.StatusBadRequest, scim.ErrorTypeInvalidValue, "The group must have a displayName."))
+		return
+	}
+	if name != group.Name && name == database.EveryoneGroup {
+		scim.WriteError(rw, scim.Errorf(http.StatusConflict, scim.ErrorTypeUniqueness, "The name %q is reserved.", database.EveryoneGroup))
+		return
+	}
+
+	current := make([]uuid.UUID, 0, len(group.members))
+	for _, member := range group.members {
+		current = append(current, member.ID)
+	}
+	err := api.Database.InTx(func(tx database.Store) error {
+		if name != group.Name {
+			_, err := tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+				ID:                   group.ID,
+				Name:                 name,
+				DisplayName:          group.DisplayName,
+				AvatarURL:            group.AvatarURL,
+				QuotaAllowance:       group.QuotaAllowance,
+				MaxWorkspacesPerUser: group.MaxWorkspacesPerUser,
+			})
+			if database.IsUniqueViolation(err) {
+				return scim.Errorf(http.StatusConflict, scim.ErrorTypeUniqueness, "A group named %q already exists.", name)
+			}
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+		}
+		return scimSetGroupMembers(ctx, tx, group.ID, current, memberIDs)
+	}, nil)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+
+	updated, err := api.scimGroupByID(ctx, group.ID.String())
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	writeSCIMResource(rw, r, http.StatusOK, api.scimGroup(updated))
+}
+
+// scimSetGroupMembers changes the members of a group from current to desired.
+func scimSetGroupMembers(ctx context.Context, tx database.Store, groupID uuid.UUID, current, desired []uuid.UUID) error {
+	currentSet := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		currentSet[id] = true
+	}
+	desiredSet := make(map[uuid.UUID]bool, len(desired))
+	for _, id := range desired {
+		if desiredSet[id] {
+			continue
+		}
+		desiredSet[id] = true
+		if currentSet[id] {
+			continue
+		}
+		err := tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			UserID:  id,
+			GroupID: groupID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group member: %w", err)
+		}
+	}
+	for _, id := range current {
+		if desiredSet[id] {
+			continue
+		}
+		err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  id,
+			GroupID: groupID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete group member: %w", err)
+		}
+	}
+	return nil
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // SCIM requests are authenticated by the SCIM token.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+
+	group, err := api.scimGroupByID(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	err = api.Database.DeleteGroupByID(ctx, group.ID)
+	if err != nil {
+		scim.WriteError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
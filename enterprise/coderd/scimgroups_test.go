@@ -0,0 +1,202 @@
+package coderd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func scimMemberIDs(group coderd.SCIMGroup) []string {
+	ids := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		alice := scimCreateUser(ctx, t, client, scimAPIKey)
+		bob := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		var group coderd.SCIMGroup
+		status := scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "engineers",
+			Members:     []coderd.SCIMReference{{Value: alice.ID}},
+		}, scimAPIKey, &group)
+		require.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, "engineers", group.DisplayName)
+		assert.Equal(t, []string{alice.ID}, scimMemberIDs(group))
+
+		sdkGroup, err := client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.GroupSourceSCIM, sdkGroup.Source)
+		require.Len(t, sdkGroup.Members, 1)
+		assert.Equal(t, alice.ID, sdkGroup.Members[0].ID.String())
+
+		// The user lists the groups they are a member of.
+		var sUser coderd.SCIMUser
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Users/"+alice.ID, nil, scimAPIKey, &sUser)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, sUser.Groups, 1)
+		assert.Equal(t, group.ID, sUser.Groups[0].Value)
+
+		status = scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "engineers"}, scimAPIKey, nil)
+		assert.Equal(t, http.StatusConflict, status)
+		status = scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: database.EveryoneGroup}, scimAPIKey, nil)
+		assert.Equal(t, http.StatusConflict, status)
+
+		// The Everyone group is not listed.
+		var list struct {
+			TotalResults int                `json:"totalResults"`
+			Resources    []coderd.SCIMGroup `json:"Resources"`
+		}
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Groups", nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+		filter := url.QueryEscape(`displayName eq "ENGINEERS"`)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Groups?filter="+filter, nil, scimAPIKey, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+		assert.Equal(t, group.ID, list.Resources[0].ID)
+
+		status = scimDo(ctx, t, client, http.MethodPut, "/scim/v2/Groups/"+group.ID, coderd.SCIMGroup{
+			DisplayName: "developers",
+			Members:     []coderd.SCIMReference{{Value: bob.ID}},
+		}, scimAPIKey, &group)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "developers", group.DisplayName)
+		assert.Equal(t, []string{bob.ID}, scimMemberIDs(group))
+
+		status = scimDo(ctx, t, client, http.MethodDelete, "/scim/v2/Groups/"+group.ID, nil, scimAPIKey, nil)
+		require.Equal(t, http.StatusNoContent, status)
+		status = scimDo(ctx, t, client, http.MethodGet, "/scim/v2/Groups/"+group.ID, nil, scimAPIKey, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		client, scimAPIKey := scimClient(t)
+		alice := scimCreateUser(ctx, t, client, scimAPIKey)
+		bob := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		var group coderd.SCIMGroup
+		status := scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "engineers"}, scimAPIKey, &group)
+		require.Equal(t, http.StatusCreated, status)
+
+		patch := func(operations string) (int, coderd.SCIMGroup) {
+			var patched coderd.SCIMGroup
+			body := json.RawMessage(fmt.Sprintf(`{
+				"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+				"Operations": %s
+			}`, operations))
+			status := scimDo(ctx, t, client, http.MethodPatch, "/scim/v2/Groups/"+group.ID, body, scimAPIKey, &patched)
+			return status, patched
+		}
+
+		status, group = patch(fmt.Sprintf(`[{"op": "Add", "path": "members", "value": [{"value": %q}, {"value": %q}]}]`, alice.ID, bob.ID))
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, []string{alice.ID, bob.ID}, scimMemberIDs(group))
+
+		status, group = patch(fmt.Sprintf(`[{"op": "remove", "path": "members[value eq \"%s\"]"}]`, alice.ID))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{bob.ID}, scimMemberIDs(group))
+
+		// Azure AD removes members with a value instead of a filter.
+		status, group = patch(fmt.Sprintf(`[
+			{"op": "add", "path": "members", "value": [{"value": %q}]},
+			{"op": "remove", "path": "members", "value": [{"value": %q}]}
+		]`, alice.ID, bob.ID))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{alice.ID}, scimMemberIDs(group))
+
+		status, group = patch(`[{"op": "replace", "value": {"displayName": "developers"}}]`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "developers", group.DisplayName)
+		assert.Equal(t, []string{alice.ID}, scimMemberIDs(group))
+
+		status, group = patch(`[{"op": "remove", "path": "members"}]`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, group.Members)
+
+		status, _ = patch(fmt.Sprintf(`[{"op": "add", "path": "members", "value": [{"value": %q}]}]`, uuid.NewString()))
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = patch(`[{"op": "replace", "path": "avatarUrl", "value": "x"}]`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("OIDCSyncKeepsMembers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		scimAPIKey := []byte("hi")
+		client, db, owner := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		alice := scimCreateUser(ctx, t, client, scimAPIKey)
+		bob := scimCreateUser(ctx, t, client, scimAPIKey)
+
+		var group coderd.SCIMGroup
+		status := scimDo(ctx, t, client, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "engineers",
+			Members:     []coderd.SCIMReference{{Value: alice.ID}},
+		}, scimAPIKey, &group)
+		require.Equal(t, http.StatusCreated, status)
+
+		// OIDC group sync removes users from all groups and adds them to the
+		// groups in their claims, which must leave SCIM groups alone.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		for _, user := range []coderd.SCIMUser{alice, bob} {
+			err := db.RemoveUserFromAllGroups(sysCtx, uuid.MustParse(user.ID))
+			require.NoError(t, err)
+			err = db.InsertUserGroupsByName(sysCtx, database.InsertUserGroupsByNameParams{
+				OrganizationID: owner.OrganizationID,
+				UserID:         uuid.MustParse(user.ID),
+				GroupNames:     []string{"engineers"},
+			})
+			require.NoError(t, err)
+		}
+
+		sdkGroup, err := client.Group(ctx, uuid.MustParse(group.ID))
+		require.NoError(t, err)
+		require.Len(t, sdkGroup.Members, 1)
+		assert.Equal(t, alice.ID, sdkGroup.Members[0].ID.String())
+	})
+}
@@ -0,0 +1,251 @@
+package coderd
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// @Summary Get SCIM tokens
+// @ID get-scim-tokens
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {array} codersdk.SCIMToken
+// @Router /scim/tokens [get]
+func (api *API) scimTokens(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tokens, err := api.Database.GetSCIMTokens(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching SCIM tokens.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	sdkTokens := make([]codersdk.SCIMToken, 0, len(tokens))
+	for _, token := range tokens {
+		sdkTokens = append(sdkTokens, convertSCIMToken(token))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sdkTokens)
+}
+
+// @Summary Create SCIM token
+// @Description The token is only returned once. Identity providers send it
+// @Description in the Authorization header of SCIM requests as a bearer token.
+// @ID create-scim-token
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.CreateSCIMTokenRequest true "Create SCIM token request"
+// @Success 201 {object} codersdk.SCIMTokenFull
+// @Router /scim/tokens [post]
+func (api *API) postSCIMToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.SCIMToken](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateSCIMTokenRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	secret, err := generateSCIMToken()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating SCIM token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	token, err := api.Database.InsertSCIMToken(ctx, database.InsertSCIMTokenParams{
+		ID:            uuid.New(),
+		Name:          req.Name,
+		CreatedAt:     dbtime.Now(),
+		SecretPrefix:  []byte(secret.prefix),
+		HashedSecret:  secret.hashed,
+		DisplaySecret: secret.formatted[len(secret.formatted)-6:],
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A SCIM token named %q already exists.", req.Name),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating SCIM token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = token
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.SCIMTokenFull{
+		ID:    token.ID,
+		Name:  token.Name,
+		Token: secret.formatted,
+	})
+}
+
+// @Summary Delete SCIM token
+// @Description Requests with the token are rejected from now on.
+// @ID delete-scim-token
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param token path string true "Token ID" format(uuid)
+// @Success 204
+// @Router /scim/tokens/{token} [delete]
+func (api *API) deleteSCIMToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := httpmw.ParseUUIDParam(rw, r, "token")
+	if !ok {
+		return
+	}
+	token, err := api.Database.GetSCIMTokenByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching SCIM token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	auditor := api.AGPL.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.SCIMToken](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = token
+
+	err = api.Database.DeleteSCIMToken(ctx, token.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting SCIM token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertSCIMToken(token database.SCIMToken) codersdk.SCIMToken {
+	return codersdk.SCIMToken{
+		ID:             token.ID,
+		Name:           token.Name,
+		CreatedAt:      token.CreatedAt,
+		LastUsedAt:     codersdk.NullTime{NullTime: token.LastUsedAt},
+		TokenTruncated: token.DisplaySecret,
+	}
+}
+
+type scimTokenSecret struct {
+	// formatted is the token the identity provider sends, coder_<prefix>_<secret>.
+	formatted string
+	// prefix identifies the token in the database.
+	prefix string
+	hashed []byte
+}
+
+func generateSCIMToken() (scimTokenSecret, error) {
+	prefix, err := cryptorand.String(10)
+	if err != nil {
+		return scimTokenSecret{}, err
+	}
+	secret, err := cryptorand.String(40)
+	if err != nil {
+		return scimTokenSecret{}, err
+	}
+	return scimTokenSecret{
+		formatted: fmt.Sprintf("coder_%s_%s", prefix, secret),
+		prefix:    prefix,
+		hashed:    hashSCIMSecret(secret),
+	}, nil
+}
+
+// hashSCIMSecret hashes the secret of a SCIM token. Tokens are checked on
+// every SCIM request, so they are hashed with SHA256 like API keys rather
+// than with a slow password hash.
+func hashSCIMSecret(secret string) []byte {
+	hashed := sha256.Sum256([]byte(secret))
+	return hashed[:]
+}
+
+// verifySCIMToken checks a coder_<prefix>_<secret> token against the
+// database and records that it was used.
+func (api *API) verifySCIMToken(r *http.Request, formatted string) (bool, error) {
+	parts := strings.Split(formatted, "_")
+	if len(parts) != 3 || parts[0] != "coder" || parts[1] == "" || parts[2] == "" {
+		return false, nil
+	}
+
+	//nolint:gocritic // The SCIM token is what authenticates the request.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	token, err := api.Database.GetSCIMTokenByPrefix(ctx, []byte(parts[1]))
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get scim token: %w", err)
+	}
+	if subtle.ConstantTimeCompare(token.HashedSecret, hashSCIMSecret(parts[2])) != 1 {
+		return false, nil
+	}
+
+	// Identity providers send bursts of requests, so the last use is only
+	// recorded once a minute.
+	now := dbtime.Now()
+	if !token.LastUsedAt.Valid || now.Sub(token.LastUsedAt.Time) > time.Minute {
+		err = api.Database.UpdateSCIMTokenLastUsedAt(ctx, database.UpdateSCIMTokenLastUsedAtParams{
+			ID:         token.ID,
+			LastUsedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return false, xerrors.Errorf("update scim token last used: %w", err)
+		}
+	}
+	return true, nil
+}
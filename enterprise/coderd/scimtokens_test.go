@@ -0,0 +1,101 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSCIMTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+
+		token, err := client.CreateSCIMToken(ctx, codersdk.CreateSCIMTokenRequest{Name: "okta"})
+		require.NoError(t, err)
+		assert.Equal(t, "okta", token.Name)
+		assert.Regexp(t, `^coder_[^_]+_[^_]+$`, token.Token)
+
+		tokens, err := client.SCIMTokens(ctx)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, token.ID, tokens[0].ID)
+		assert.False(t, tokens[0].LastUsedAt.Valid)
+		assert.Contains(t, token.Token, tokens[0].TokenTruncated)
+
+		_, err = client.CreateSCIMToken(ctx, codersdk.CreateSCIMTokenRequest{Name: "okta"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		err = client.DeleteSCIMToken(ctx, token.ID)
+		require.NoError(t, err)
+		tokens, err = client.SCIMTokens(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+
+		err = client.DeleteSCIMToken(ctx, uuid.New())
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		_, err := member.CreateSCIMToken(ctx, codersdk.CreateSCIMTokenRequest{Name: "okta"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.SCIMTokens(ctx)
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Unlicensed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM: 0,
+				},
+			},
+		})
+
+		_, err := client.SCIMTokens(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
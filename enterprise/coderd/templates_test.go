@@ -14,6 +14,8 @@ import (
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
@@ -78,6 +80,51 @@ func TestTemplates(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("DeprecatedNotifiesOwners", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer := &notificationstest.FakeEnqueuer{}
+		owner, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				NotificationsEnqueuer:    enqueuer,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAccessControl: 1,
+				},
+			},
+		})
+		client, _ := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID, rbac.RoleTemplateAdmin())
+		member, memberUser := coderdtest.CreateAnotherUser(t, owner, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		// Two workspaces for the same owner should produce one notification.
+		coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Use the new template"),
+		})
+		require.NoError(t, err)
+
+		sent := enqueuer.Sent(notifications.TemplateTemplateDeprecated)
+		require.Len(t, sent, 1)
+		require.Equal(t, memberUser.ID, sent[0].UserID)
+		require.Equal(t, template.Name, sent[0].Labels["template"])
+		require.Equal(t, "Use the new template", sent[0].Labels["message"])
+
+		// Updating the message again shouldn't notify anyone.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DeprecationMessage: ptr.Ref("Really, use the new template"),
+		})
+		require.NoError(t, err)
+		require.Len(t, enqueuer.Sent(notifications.TemplateTemplateDeprecated), 1)
+	})
+
 	t.Run("MaxPortShareLevel", func(t *testing.T) {
 		t.Parallel()
 
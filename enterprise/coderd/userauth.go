@@ -30,8 +30,9 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 		if err != nil {
 			return xerrors.Errorf("get user orgs: %w", err)
 		}
-		if len(orgs) != 1 {
-			return xerrors.Errorf("expected 1 org, got %d", len(orgs))
+		isDefault := make(map[uuid.UUID]bool, len(orgs))
+		for _, org := range orgs {
+			isDefault[org.ID] = org.IsDefault
 		}
 
 		// Delete all groups the user belongs to.
@@ -46,8 +47,21 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 		//	inefficient if there are a lot of organizations. There was deployments
 		//	on v1 with >100 orgs.
 		for orgID, groupNames := range orgGroupNames {
-			// Create the missing groups for each organization.
-			if createMissingGroups {
+			defaultOrg, ok := isDefault[orgID]
+			if !ok {
+				// A user cannot be in groups of an org they are not a member of.
+				logger.Warn(ctx, "user is not a member of the organization, skipping group assignment",
+					slog.F("org_id", orgID),
+					slog.F("user_id", userID),
+				)
+				continue
+			}
+
+			// Missing groups are only created in the default organization.
+			// Groups in other organizations must already exist, so an
+			// identity provider cannot create groups in every organization
+			// a user belongs to.
+			if createMissingGroups && defaultOrg {
 				// This is the system creating these additional groups, so we use the system restricted context.
 				// nolint:gocritic
 				created, err := tx.InsertMissingGroups(dbauthz.AsSystemRestricted(ctx), database.InsertMissingGroupsParams{
@@ -60,7 +74,7 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 				}
 				if len(created) > 0 {
 					logger.Debug(ctx, "auto created missing groups",
-						slog.F("org_id", orgID.ID),
+						slog.F("org_id", orgID),
 						slog.F("created", created),
 						slog.F("num", len(created)),
 					)
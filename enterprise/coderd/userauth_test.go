@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -181,6 +182,82 @@ func TestUserOIDC(t *testing.T) {
 			require.Error(t, err)
 			require.ErrorContains(t, err, "Cannot modify roles for OIDC users when role sync is enabled.")
 		})
+
+		// Sync rules add site roles without a role claim configured.
+		t.Run("SyncRules", func(t *testing.T) {
+			t.Parallel()
+
+			runner := setupOIDCTest(t, oidcTestConfig{
+				Config: func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+				},
+			})
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			_, err := runner.AdminClient.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+				Claim:     "groups",
+				Pattern:   "^platform-",
+				SiteRoles: []string{rbac.RoleTemplateAdmin()},
+			})
+			require.NoError(t, err)
+
+			_, resp := runner.Login(t, jwt.MapClaims{
+				"email":  "alice@coder.com",
+				"groups": []string{"everyone", "platform-eng"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertRoles(t, "alice", []string{rbac.RoleTemplateAdmin()})
+
+			// Rules don't take back roles when they stop matching.
+			_, resp = runner.Login(t, jwt.MapClaims{
+				"email":  "alice@coder.com",
+				"groups": []string{"everyone"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertRoles(t, "alice", []string{rbac.RoleTemplateAdmin()})
+		})
+
+		// Users no sync rule matches keep the site roles they were assigned
+		// manually.
+		t.Run("SyncRulesUnmatched", func(t *testing.T) {
+			t.Parallel()
+
+			runner := setupOIDCTest(t, oidcTestConfig{
+				Config: func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+				},
+			})
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			_, err := runner.AdminClient.CreateOIDCSyncRule(ctx, codersdk.CreateOIDCSyncRuleRequest{
+				Claim:     "groups",
+				Pattern:   "^platform-",
+				SiteRoles: []string{rbac.RoleTemplateAdmin()},
+			})
+			require.NoError(t, err)
+
+			claims := jwt.MapClaims{
+				"email":  "alice@coder.com",
+				"groups": []string{"everyone"},
+			}
+			_, resp := runner.Login(t, claims)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertRoles(t, "alice", []string{})
+
+			_, err = runner.AdminClient.UpdateUserRoles(ctx, "alice", codersdk.UpdateRoles{
+				Roles: []string{rbac.RoleOwner()},
+			})
+			require.NoError(t, err)
+
+			_, resp = runner.Login(t, claims)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertRoles(t, "alice", []string{rbac.RoleOwner()})
+
+			_, resp = runner.Login(t, jwt.MapClaims{
+				"email":  "alice@coder.com",
+				"groups": []string{"platform-eng"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertRoles(t, "alice", []string{rbac.RoleOwner(), rbac.RoleTemplateAdmin()})
+		})
 	})
 
 	t.Run("Groups", func(t *testing.T) {
@@ -362,6 +439,67 @@ func TestUserOIDC(t *testing.T) {
 			runner.AssertGroups(t, "alice", []string{groupName})
 		})
 
+		// Groups are synced in every organization the user is a member of,
+		// but missing groups are only created in the default organization.
+		t.Run("MultiOrganization", func(t *testing.T) {
+			t.Parallel()
+
+			const groupClaim = "custom-groups"
+			const orgClaim = "custom-orgs"
+			var oidcConfig *coderd.OIDCConfig
+			runner := setupOIDCTest(t, oidcTestConfig{
+				Config: func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+					cfg.GroupField = groupClaim
+					cfg.CreateMissingGroups = true
+					cfg.OrganizationField = orgClaim
+					cfg.OrganizationAssignDefault = true
+					oidcConfig = cfg
+				},
+			})
+
+			ctx := testutil.Context(t, testutil.WaitMedium)
+			defaultOrgID := runner.AdminUser.OrganizationIDs[0]
+			other, err := runner.AdminClient.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+				Name: "other",
+			})
+			require.NoError(t, err)
+			oidcConfig.OrganizationMapping = map[string][]uuid.UUID{
+				"other": {other.ID},
+			}
+			for _, orgID := range []uuid.UUID{defaultOrgID, other.ID} {
+				_, err := runner.AdminClient.CreateGroup(ctx, orgID, codersdk.CreateGroupRequest{
+					Name: "devs",
+				})
+				require.NoError(t, err)
+			}
+
+			_, resp := runner.Login(t, jwt.MapClaims{
+				"email":    "alice@coder.com",
+				groupClaim: []string{"devs", "make-me"},
+				orgClaim:   []string{"other"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			user, err := runner.AdminClient.User(ctx, "alice")
+			require.NoError(t, err)
+			userGroups := func(orgID uuid.UUID) []string {
+				groups, err := runner.AdminClient.GroupsByOrganization(ctx, orgID)
+				require.NoError(t, err)
+				names := []string{}
+				for _, g := range groups {
+					for _, mem := range g.Members {
+						if mem.ID == user.ID {
+							names = append(names, g.Name)
+						}
+					}
+				}
+				return names
+			}
+			require.ElementsMatch(t, []string{database.EveryoneGroup, "devs", "make-me"}, userGroups(defaultOrgID))
+			require.ElementsMatch(t, []string{database.EveryoneGroup, "devs"}, userGroups(other.ID))
+		})
+
 		// Some IDPs (ADFS) send the "string" type vs "[]string" if only
 		// 1 group exists.
 		t.Run("SingleRoleGroup", func(t *testing.T) {
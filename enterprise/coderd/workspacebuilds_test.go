@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -125,4 +126,68 @@ func TestWorkspaceBuild(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TemplateRequiresReleaseChannelVersion", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		ownerClient, owner := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAccessControl:              1,
+					codersdk.FeatureTemplateRBAC:               1,
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+
+		activeVersion := coderdtest.CreateTemplateVersion(t, ownerClient, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, ownerClient, activeVersion.ID)
+		template := coderdtest.CreateTemplate(t, ownerClient, owner.OrganizationID, activeVersion.ID)
+		template = coderdtest.UpdateTemplateMeta(t, ownerClient, template.ID, codersdk.UpdateTemplateMeta{
+			RequireActiveVersion: true,
+		})
+		require.True(t, template.RequireActiveVersion)
+		canaryVersion := coderdtest.UpdateTemplateVersion(t, ownerClient, owner.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, ownerClient, canaryVersion.ID)
+
+		// Members of the group are required to use the version of the
+		// channel instead of the active version.
+		canaryClient, canaryUser := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+		group := coderdtest.CreateGroup(t, ownerClient, owner.OrganizationID, "platform", canaryUser)
+		_, err := ownerClient.CreateTemplateReleaseChannel(ctx, template.ID, codersdk.CreateTemplateReleaseChannelRequest{
+			Name:              "canary",
+			TemplateVersionID: canaryVersion.ID,
+			GroupIDs:          []uuid.UUID{group.ID},
+		})
+		require.NoError(t, err)
+
+		_, err = canaryClient.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateVersionID: activeVersion.ID,
+			Name:              "active",
+		})
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		ws, err := canaryClient.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateVersionID: canaryVersion.ID,
+			Name:              "canary",
+		})
+		require.NoError(t, err)
+		require.Equal(t, canaryVersion.ID, ws.LatestBuild.TemplateVersionID)
+
+		// Other members still use the active version.
+		memberClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+		_, err = memberClient.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateVersionID: canaryVersion.ID,
+			Name:              "canary",
+		})
+		cerr, ok = codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -45,15 +46,22 @@ func (c *committer) CommitQuota(
 		consumed int64
 		budget   int64
 		permit   bool
+		message  string
 	)
 	err = c.Database.InTx(func(s database.Store) error {
 		var err error
-		consumed, err = s.GetQuotaConsumedForUser(ctx, workspace.OwnerID)
+		consumed, err = s.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+			OwnerID:        workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+		})
 		if err != nil {
 			return err
 		}
 
-		budget, err = s.GetQuotaAllowanceForUser(ctx, workspace.OwnerID)
+		budget, err = s.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+			UserID:         workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+		})
 		if err != nil {
 			return err
 		}
@@ -77,6 +85,22 @@ func (c *committer) CommitQuota(
 			return err
 		}
 
+		// Workspaces over a workspace limit can still be stopped, only
+		// starting them is rejected.
+		if netIncrease && nextBuild.Transition == database.WorkspaceTransitionStart {
+			message, err = workspaceLimitExceeded(ctx, s, workspace)
+			if err != nil {
+				return err
+			}
+			if message != "" {
+				c.Log.Debug(
+					ctx, "over workspace limit, rejecting",
+					slog.F("reason", message),
+				)
+				return nil
+			}
+		}
+
 		newConsumed := int64(request.DailyCost) + consumed
 		if newConsumed > budget && netIncrease {
 			c.Log.Debug(
@@ -109,10 +133,53 @@ func (c *committer) CommitQuota(
 		Ok:              permit,
 		CreditsConsumed: int32(consumed),
 		Budget:          int32(budget),
+		Message:         message,
 	}, nil
 }
 
+// workspaceLimitExceeded returns why the workspace exceeds the workspace limit
+// of its owner or its template, or an empty string if it doesn't. The
+// workspace itself is part of the counts, which may exceed the limits if they
+// were lowered after the workspaces were created.
+func workspaceLimitExceeded(ctx context.Context, db database.Store, workspace database.Workspace) (string, error) {
+	limit, err := db.GetWorkspaceLimitForUser(ctx, database.GetWorkspaceLimitForUserParams{
+		UserID:         workspace.OwnerID,
+		OrganizationID: workspace.OrganizationID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if limit > 0 {
+		count, err := db.GetWorkspaceCountForUser(ctx, database.GetWorkspaceCountForUserParams{
+			OwnerID:        workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			return "", err
+		}
+		if count > int64(limit) {
+			return fmt.Sprintf("The owner of the workspace has %d workspaces, which exceeds their limit of %d.", count, limit), nil
+		}
+	}
+
+	template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return "", err
+	}
+	if template.MaxWorkspaces > 0 {
+		count, err := db.GetWorkspaceCountForTemplate(ctx, template.ID)
+		if err != nil {
+			return "", err
+		}
+		if count > int64(template.MaxWorkspaces) {
+			return fmt.Sprintf("Template %q has %d workspaces, which exceeds its limit of %d.", template.Name, count, template.MaxWorkspaces), nil
+		}
+	}
+	return "", nil
+}
+
 // @Summary Get workspace quota by user
+// @Description Returns the quota of the user in the default organization.
 // @ID get-workspace-quota-by-user
 // @Security CoderSessionToken
 // @Produce json
@@ -121,6 +188,7 @@ func (c *committer) CommitQuota(
 // @Success 200 {object} codersdk.WorkspaceQuota
 // @Router /workspace-quota/{user} [get]
 func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
 	if !api.AGPL.Authorize(r, rbac.ActionRead, user) {
@@ -128,35 +196,111 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	organization, err := api.Database.GetDefaultOrganization(ctx)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching default organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.writeWorkspaceQuota(rw, r, user.ID, organization.ID)
+}
+
+// @Summary Get workspace quota by organization member
+// @ID get-workspace-quota-by-organization-member
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.WorkspaceQuota
+// @Router /organizations/{organization}/members/{user}/workspace-quota [get]
+func (api *API) organizationMemberWorkspaceQuota(rw http.ResponseWriter, r *http.Request) {
+	member := httpmw.OrganizationMemberParam(r)
+
+	if !api.AGPL.Authorize(r, rbac.ActionRead, member.OrganizationMember) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	api.writeWorkspaceQuota(rw, r, member.UserID, member.OrganizationID)
+}
+
+// writeWorkspaceQuota writes the quota of the user in the organization. Only
+// the groups and workspaces of the organization count towards it.
+func (api *API) writeWorkspaceQuota(rw http.ResponseWriter, r *http.Request, userID, organizationID uuid.UUID) {
+	ctx := r.Context()
+
 	api.entitlementsMu.RLock()
 	licensed := api.entitlements.Features[codersdk.FeatureTemplateRBAC].Enabled
 	api.entitlementsMu.RUnlock()
 
-	// There are no groups and thus no allowance if RBAC isn't licensed.
-	var quotaAllowance int64 = -1
+	// There are no groups and thus no allowance or limit if RBAC isn't
+	// licensed.
+	var (
+		quotaAllowance int64 = -1
+		workspaceLimit int32
+	)
 	if licensed {
 		var err error
-		quotaAllowance, err = api.Database.GetQuotaAllowanceForUser(r.Context(), user.ID)
+		quotaAllowance, err = api.Database.GetQuotaAllowanceForUser(ctx, database.GetQuotaAllowanceForUserParams{
+			UserID:         userID,
+			OrganizationID: organizationID,
+		})
 		if err != nil {
-			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Failed to get allowance",
 				Detail:  err.Error(),
 			})
 			return
 		}
+		workspaceLimit, err = api.Database.GetWorkspaceLimitForUser(ctx, database.GetWorkspaceLimitForUserParams{
+			UserID:         userID,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get workspace limit",
+				Detail:  err.Error(),
+			})
+			return
+		}
 	}
 
-	quotaConsumed, err := api.Database.GetQuotaConsumedForUser(r.Context(), user.ID)
+	quotaConsumed, err := api.Database.GetQuotaConsumedForUser(ctx, database.GetQuotaConsumedForUserParams{
+		OwnerID:        userID,
+		OrganizationID: organizationID,
+	})
 	if err != nil {
-		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Failed to get consumed",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceQuota{
+	workspaceCount, err := api.Database.GetWorkspaceCountForUser(ctx, database.GetWorkspaceCountForUserParams{
+		OwnerID:        userID,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace count",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceQuota{
 		CreditsConsumed: int(quotaConsumed),
 		Budget:          int(quotaAllowance),
+		WorkspaceCount:  int(workspaceCount),
+		WorkspaceLimit:  int(workspaceLimit),
 	})
 }
@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"sync"
 	"testing"
 
@@ -25,10 +27,8 @@ func verifyQuota(ctx context.Context, t *testing.T, client *codersdk.Client, con
 
 	got, err := client.WorkspaceQuota(ctx, codersdk.Me)
 	require.NoError(t, err)
-	require.EqualValues(t, codersdk.WorkspaceQuota{
-		Budget:          total,
-		CreditsConsumed: consumed,
-	}, got)
+	require.Equal(t, total, got.Budget)
+	require.Equal(t, consumed, got.CreditsConsumed)
 }
 
 func TestWorkspaceQuota(t *testing.T) {
@@ -233,6 +233,196 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, 4, 4)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 	})
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+
+		before, err := client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "other",
+		})
+		require.NoError(t, err)
+		// The 'Everyone' group of an organization shares its ID.
+		_, err = client.PatchGroup(ctx, other.ID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(5),
+		})
+		require.NoError(t, err)
+
+		// Allowances only apply to the organization of the group.
+		quota, err := client.OrganizationWorkspaceQuota(ctx, other.ID, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 5, quota.Budget)
+		verifyQuota(ctx, t, client, before.CreditsConsumed, before.Budget)
+	})
+
+	t.Run("WorkspaceLimit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			UserWorkspaceQuota: 1,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(10),
+		})
+		require.NoError(t, err)
+
+		// Members of several groups get the highest limit.
+		for i, limit := range []int{1, 2} {
+			group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+				Name:                 fmt.Sprintf("limit-%d", i),
+				MaxWorkspacesPerUser: limit,
+			})
+			require.NoError(t, err)
+			_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+				AddUsers: []string{user.UserID.String()},
+			})
+			require.NoError(t, err)
+		}
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  planWithCost(1),
+			ProvisionApply: applyWithCost(1),
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		var workspaces []codersdk.Workspace
+		for i := 0; i < 2; i++ {
+			workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+			workspaces = append(workspaces, workspace)
+			build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+			require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		}
+
+		quota, err := client.WorkspaceQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 2, quota.WorkspaceCount)
+		require.Equal(t, 2, quota.WorkspaceLimit)
+
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "over-limit",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "limit of 2 workspaces")
+
+		// Lowering the limit doesn't delete workspaces, but they can't be
+		// started until the owner is within the limit again.
+		groups, err := client.GroupsByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		for _, group := range groups {
+			if group.MaxWorkspacesPerUser == 0 {
+				continue
+			}
+			_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+				MaxWorkspacesPerUser: ptr.Ref(1),
+			})
+			require.NoError(t, err)
+		}
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspaces[0], database.WorkspaceTransitionStop)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusStopped, build.Status)
+
+		build = coderdtest.CreateWorkspaceBuild(t, client, workspaces[0], database.WorkspaceTransitionStart)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+
+		logs, closer, err := client.WorkspaceBuildLogsAfter(ctx, build.ID, 0)
+		require.NoError(t, err)
+		defer closer.Close()
+		var output []string
+		for log := range logs {
+			output = append(output, log.Output)
+		}
+		require.Contains(t, output, "The owner of the workspace has 2 workspaces, which exceeds their limit of 1. Failing.")
+
+		build = coderdtest.CreateWorkspaceBuild(t, client, workspaces[1], database.WorkspaceTransitionDelete)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusDeleted, build.Status)
+
+		build = coderdtest.CreateWorkspaceBuild(t, client, workspaces[0], database.WorkspaceTransitionStart)
+		build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+	})
+
+	t.Run("WorkspaceLimitTransfer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name:                 "limit",
+			MaxWorkspacesPerUser: 1,
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		})
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		owned := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, owned.LatestBuild.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		// Stopped workspaces count towards the limit of the new owner too.
+		_, err = client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+			Owner: member.Username,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "limit of 1 workspaces")
+
+		build = coderdtest.CreateWorkspaceBuild(t, client, owned, database.WorkspaceTransitionDelete)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		transferred, err := client.TransferWorkspaceOwnership(ctx, workspace.ID, codersdk.TransferWorkspaceOwnershipRequest{
+			Owner: member.Username,
+		})
+		require.NoError(t, err)
+		require.Equal(t, member.ID, transferred.OwnerID)
+	})
 }
 
 func planWithCost(cost int32) []*proto.Response {
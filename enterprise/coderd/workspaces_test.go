@@ -17,6 +17,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/coderd/rbac"
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
@@ -534,6 +536,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 			ticker        = make(chan time.Time)
 			statCh        = make(chan autobuild.Stats)
 			transitionTTL = time.Minute
+			enqueuer      = &notificationstest.FakeEnqueuer{}
 		)
 
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
@@ -542,6 +545,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 				IncludeProvisionerDaemon: true,
 				AutobuildStats:           statCh,
 				TemplateScheduleStore:    schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore()),
+				NotificationsEnqueuer:    enqueuer,
 			},
 			LicenseOptions: &coderdenttest.LicenseOptions{
 				Features: license.Features{codersdk.FeatureAdvancedTemplateScheduling: 1},
@@ -574,6 +578,11 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		ws = coderdtest.MustWorkspace(t, client, ws.ID)
 		// The workspace should be dormant.
 		require.NotNil(t, ws.DormantAt)
+		// The owner should be told their workspace is dormant.
+		sent := enqueuer.Sent(notifications.TemplateWorkspaceDormant)
+		require.Len(t, sent, 1)
+		require.Equal(t, ws.OwnerID, sent[0].UserID)
+		require.Equal(t, ws.Name, sent[0].Labels["workspace"])
 
 		// Wait for the autobuilder to stop the workspace.
 		_ = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
@@ -584,6 +593,10 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.Len(t, stats.Transitions, 1)
 		// The workspace should be scheduled for deletion.
 		require.Equal(t, stats.Transitions[ws.ID], database.WorkspaceTransitionDelete)
+		sent = enqueuer.Sent(notifications.TemplateWorkspaceDeleted)
+		require.Len(t, sent, 1)
+		require.Equal(t, ws.OwnerID, sent[0].UserID)
+		require.Equal(t, "it was dormant for longer than 1m0s", sent[0].Labels["reason"])
 
 		// Wait for the workspace to be deleted.
 		ws = coderdtest.MustWorkspace(t, client, ws.ID)
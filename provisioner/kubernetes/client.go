@@ -0,0 +1,307 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// fieldManager identifies the changes made by the provisioner in the managed
+// fields of objects.
+const fieldManager = "coder"
+
+// object is an arbitrary Kubernetes object.
+type object map[string]any
+
+func (o object) apiVersion() string { return stringField(o, "apiVersion") }
+func (o object) kind() string       { return stringField(o, "kind") }
+func (o object) name() string       { return stringField(o, "metadata", "name") }
+func (o object) namespace() string  { return stringField(o, "metadata", "namespace") }
+
+func (o object) annotations() map[string]string {
+	return stringMap(field(o, "metadata", "annotations"))
+}
+
+func (o object) ref() objectRef {
+	return objectRef{
+		APIVersion: o.apiVersion(),
+		Kind:       o.kind(),
+		Namespace:  o.namespace(),
+		Name:       o.name(),
+	}
+}
+
+// objectRef identifies an object.
+type objectRef struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r objectRef) String() string {
+	kind := strings.ToLower(r.Kind)
+	if group, _, ok := strings.Cut(r.APIVersion, "/"); ok {
+		kind += "." + group
+	}
+	if r.Namespace == "" {
+		return kind + "/" + r.Name
+	}
+	return kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// field returns the value at path in a decoded JSON object, or nil.
+func field(v any, path ...string) any {
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			if o, isObject := v.(object); isObject {
+				m = o
+			} else {
+				return nil
+			}
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func stringField(v any, path ...string) string {
+	s, _ := field(v, path...).(string)
+	return s
+}
+
+func stringMap(v any) map[string]string {
+	m, _ := v.(map[string]any)
+	out := make(map[string]string, len(m))
+	for key, value := range m {
+		if s, ok := value.(string); ok {
+			out[key] = s
+		}
+	}
+	return out
+}
+
+// apiResource is a resource served by the API server.
+type apiResource struct {
+	Name       string `json:"name"`
+	Namespaced bool   `json:"namespaced"`
+	Kind       string `json:"kind"`
+}
+
+// statusError is a failed request described by a Status object.
+type statusError struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func (e *statusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+	}
+	return e.Message
+}
+
+// notServedError indicates that the API server does not serve a kind, e.g.
+// because its custom resource definition does not exist.
+type notServedError struct {
+	apiVersion string
+	kind       string
+}
+
+func (e *notServedError) Error() string {
+	if e.kind == "" {
+		return fmt.Sprintf("the server does not serve %q", e.apiVersion)
+	}
+	return fmt.Sprintf("the server does not serve kind %q in %q", e.kind, e.apiVersion)
+}
+
+func isNotServed(err error) bool {
+	var notServed *notServedError
+	return xerrors.As(err, &notServed)
+}
+
+func isNotFound(err error) bool {
+	var status *statusError
+	return xerrors.As(err, &status) && status.Code == http.StatusNotFound
+}
+
+// client is a minimal client of the Kubernetes API that applies and deletes
+// arbitrary objects.
+type client struct {
+	config *Config
+	http   *http.Client
+
+	mu sync.Mutex
+	// resources caches discovered resources by group version.
+	resources map[string][]apiResource
+}
+
+func newClient(config *Config) *client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config.TLSConfig
+	return &client{
+		config:    config,
+		http:      &http.Client{Transport: transport},
+		resources: map[string][]apiResource{},
+	}
+}
+
+func (c *client) do(ctx context.Context, method, path, contentType string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.config.Host, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.BearerToken)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		status := &statusError{}
+		_ = json.Unmarshal(data, status)
+		status.Code = res.StatusCode
+		return status
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// resource discovers the resource of the kind in the API version.
+func (c *client) resource(ctx context.Context, apiVersion, kind string) (apiResource, error) {
+	c.mu.Lock()
+	resources, ok := c.resources[apiVersion]
+	c.mu.Unlock()
+	if !ok {
+		path := "/apis/" + apiVersion
+		if !strings.Contains(apiVersion, "/") {
+			path = "/api/" + apiVersion
+		}
+		var list struct {
+			Resources []apiResource `json:"resources"`
+		}
+		err := c.do(ctx, http.MethodGet, path, "", nil, &list)
+		if err != nil {
+			if isNotFound(err) {
+				return apiResource{}, &notServedError{apiVersion: apiVersion}
+			}
+			return apiResource{}, xerrors.Errorf("discover %q: %w", apiVersion, err)
+		}
+		resources = list.Resources
+		c.mu.Lock()
+		c.resources[apiVersion] = resources
+		c.mu.Unlock()
+	}
+	for _, r := range resources {
+		// Subresources like "pods/log" share the kind of their parent.
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			return r, nil
+		}
+	}
+	return apiResource{}, &notServedError{apiVersion: apiVersion, kind: kind}
+}
+
+// forget clears discovered resources, so that resources of definitions that
+// were just created are found.
+func (c *client) forget() {
+	c.mu.Lock()
+	c.resources = map[string][]apiResource{}
+	c.mu.Unlock()
+}
+
+func (c *client) path(ctx context.Context, ref objectRef) (string, error) {
+	r, err := c.resource(ctx, ref.APIVersion, ref.Kind)
+	if err != nil {
+		return "", err
+	}
+	path := "/apis/" + ref.APIVersion
+	if !strings.Contains(ref.APIVersion, "/") {
+		path = "/api/" + ref.APIVersion
+	}
+	if r.Namespaced {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = c.config.Namespace
+		}
+		if namespace == "" {
+			namespace = "default"
+		}
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+	return path + "/" + r.Name + "/" + url.PathEscape(ref.Name), nil
+}
+
+// apply creates or updates the object with server-side apply, and returns the
+// object as persisted by the server.
+func (c *client) apply(ctx context.Context, obj object, dryRun bool) (object, error) {
+	path, err := c.path(ctx, obj.ref())
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("fieldManager", fieldManager)
+	// The template is the source of truth for the fields it sets.
+	query.Set("force", "true")
+	if dryRun {
+		query.Set("dryRun", "All")
+	}
+	// JSON is valid YAML.
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var applied object
+	err = c.do(ctx, http.MethodPatch, path+"?"+query.Encode(), "application/apply-patch+yaml", body, &applied)
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// delete deletes the object and its dependents. Objects that do not exist
+// are ignored.
+func (c *client) delete(ctx context.Context, ref objectRef) error {
+	path, err := c.path(ctx, ref)
+	if err != nil {
+		// Objects of a kind that is no longer served are gone.
+		if isNotServed(err) {
+			return nil
+		}
+		return err
+	}
+	body, err := json.Marshal(map[string]any{
+		"kind":              "DeleteOptions",
+		"apiVersion":        "v1",
+		"propagationPolicy": "Background",
+	})
+	if err != nil {
+		return err
+	}
+	err = c.do(ctx, http.MethodDelete, path, "application/json", body, nil)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Config describes how to connect to a cluster.
+type Config struct {
+	// Host is the URL of the API server.
+	Host string
+	// BearerToken authenticates requests, unless the TLS config presents a
+	// client certificate.
+	BearerToken string
+	// TLSConfig is used for HTTPS connections to the API server.
+	TLSConfig *tls.Config
+	// Namespace is the namespace of objects that do not specify one.
+	Namespace string
+}
+
+// LoadConfig returns the config of the cluster the provisioner runs in. Outside
+// of a cluster, the current context of the kubeconfig file at path is used,
+// which defaults to $KUBECONFIG or ~/.kube/config.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		if host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"); host != "" && port != "" {
+			return inClusterConfig(host, port)
+		}
+		path = os.Getenv("KUBECONFIG")
+		// Only the first of multiple files is supported.
+		path, _, _ = strings.Cut(path, string(filepath.ListSeparator))
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, xerrors.Errorf("get home dir: %w", err)
+		}
+		path = filepath.Join(home, ".kube", "config")
+	}
+	return loadKubeconfig(path)
+}
+
+func inClusterConfig(host, port string) (*Config, error) {
+	token, err := os.ReadFile(filepath.Join(serviceAccountDir, "token"))
+	if err != nil {
+		return nil, xerrors.Errorf("read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, xerrors.Errorf("read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, xerrors.New("service account CA contains no certificates")
+	}
+	namespace, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		return nil, xerrors.Errorf("read service account namespace: %w", err)
+	}
+	return &Config{
+		Host:        "https://" + net.JoinHostPort(host, port),
+		BearerToken: strings.TrimSpace(string(token)),
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		},
+		Namespace: strings.TrimSpace(string(namespace)),
+	}, nil
+}
+
+// kubeconfig is the subset of the kubeconfig format needed to connect with a
+// token or client certificate.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			TLSServerName            string `yaml:"tls-server-name"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string         `yaml:"token"`
+			TokenFile             string         `yaml:"tokenFile"`
+			ClientCertificate     string         `yaml:"client-certificate"`
+			ClientCertificateData string         `yaml:"client-certificate-data"`
+			ClientKey             string         `yaml:"client-key"`
+			ClientKeyData         string         `yaml:"client-key-data"`
+			Exec                  map[string]any `yaml:"exec"`
+			AuthProvider          map[string]any `yaml:"auth-provider"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+func loadKubeconfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read kubeconfig: %w", err)
+	}
+	var kc kubeconfig
+	err = yaml.Unmarshal(data, &kc)
+	if err != nil {
+		return nil, xerrors.Errorf("decode kubeconfig %q: %w", path, err)
+	}
+	// Relative paths are relative to the kubeconfig file.
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(filepath.Dir(path), p)
+	}
+	// Data fields take precedence over files.
+	read := func(data, file string) ([]byte, error) {
+		if data != "" {
+			return base64.StdEncoding.DecodeString(data)
+		}
+		if file != "" {
+			return os.ReadFile(resolve(file))
+		}
+		return nil, nil
+	}
+
+	if kc.CurrentContext == "" {
+		return nil, xerrors.Errorf("kubeconfig %q has no current context", path)
+	}
+	var config Config
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext {
+			clusterName, userName, config.Namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, xerrors.Errorf("context %q not found in kubeconfig", kc.CurrentContext)
+	}
+
+	found = false
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		config.Host = c.Cluster.Server
+		tlsConfig.ServerName = c.Cluster.TLSServerName
+		//nolint:gosec // The user opted out of verification.
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		ca, err := read(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority)
+		if err != nil {
+			return nil, xerrors.Errorf("read certificate authority of cluster %q: %w", clusterName, err)
+		}
+		if len(ca) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, xerrors.Errorf("certificate authority of cluster %q contains no certificates", clusterName)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		break
+	}
+	if !found {
+		return nil, xerrors.Errorf("cluster %q not found in kubeconfig", clusterName)
+	}
+	if _, err := url.Parse(config.Host); err != nil || config.Host == "" {
+		return nil, xerrors.Errorf("cluster %q has an invalid server %q", clusterName, config.Host)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		if u.User.Exec != nil || u.User.AuthProvider != nil {
+			return nil, xerrors.Errorf("user %q authenticates with a plugin, which is not supported; use a token or client certificate", userName)
+		}
+		config.BearerToken = u.User.Token
+		if u.User.TokenFile != "" && config.BearerToken == "" {
+			token, err := os.ReadFile(resolve(u.User.TokenFile))
+			if err != nil {
+				return nil, xerrors.Errorf("read token of user %q: %w", userName, err)
+			}
+			config.BearerToken = strings.TrimSpace(string(token))
+		}
+		cert, err := read(u.User.ClientCertificateData, u.User.ClientCertificate)
+		if err != nil {
+			return nil, xerrors.Errorf("read client certificate of user %q: %w", userName, err)
+		}
+		key, err := read(u.User.ClientKeyData, u.User.ClientKey)
+		if err != nil {
+			return nil, xerrors.Errorf("read client key of user %q: %w", userName, err)
+		}
+		if len(cert) > 0 || len(key) > 0 {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, xerrors.Errorf("load client certificate of user %q: %w", userName, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+		break
+	}
+	config.TLSConfig = tlsConfig
+	return &config, nil
+}
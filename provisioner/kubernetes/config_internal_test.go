@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKubeconfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TokenFile", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("secret\n"), 0o600))
+		path := filepath.Join(dir, "config")
+		require.NoError(t, os.WriteFile(path, []byte(`
+current-context: dev
+contexts:
+  - name: other
+    context: {cluster: other, user: other}
+  - name: dev
+    context: {cluster: dev, user: dev, namespace: workspaces}
+clusters:
+  - name: dev
+    cluster:
+      server: https://kubernetes.example.com:6443
+      tls-server-name: kubernetes
+users:
+  - name: dev
+    user:
+      tokenFile: token
+`), 0o600))
+
+		config, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, "https://kubernetes.example.com:6443", config.Host)
+		require.Equal(t, "secret", config.BearerToken)
+		require.Equal(t, "workspaces", config.Namespace)
+		require.Equal(t, "kubernetes", config.TLSConfig.ServerName)
+	})
+
+	t.Run("Plugin", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "config")
+		require.NoError(t, os.WriteFile(path, []byte(`
+current-context: dev
+contexts:
+  - name: dev
+    context: {cluster: dev, user: dev}
+clusters:
+  - name: dev
+    cluster: {server: "https://kubernetes.example.com"}
+users:
+  - name: dev
+    user:
+      exec: {command: aws}
+`), 0o600))
+
+		_, err := LoadConfig(path)
+		require.ErrorContains(t, err, "plugin")
+	})
+
+	t.Run("MissingContext", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "config")
+		require.NoError(t, os.WriteFile(path, []byte("current-context: dev\n"), 0o600))
+
+		_, err := LoadConfig(path)
+		require.ErrorContains(t, err, `context "dev" not found`)
+	})
+}
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	for kind, expected := range map[string]string{
+		"Pod":                   "pod",
+		"ConfigMap":             "config_map",
+		"PersistentVolumeClaim": "persistent_volume_claim",
+		"CSIDriver":             "csi_driver",
+	} {
+		require.Equal(t, expected, snakeCase(kind))
+	}
+}
@@ -0,0 +1,255 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// crdEstablishTimeout is how long objects of a custom resource wait for the
+// definition applied in the same build to be served.
+const crdEstablishTimeout = 30 * time.Second
+
+// sessionContext returns a context that is canceled when the request is
+// canceled or completes.
+func sessionContext(sess *provisionersdk.Session, canceledOrComplete <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(sess.Context())
+	go func() {
+		select {
+		case <-canceledOrComplete:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func (*server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-chan struct{}) *proto.ParseComplete {
+	s, err := readSpec(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ParseErrorf("%s", err)
+	}
+	return &proto.ParseComplete{
+		TemplateVariables: s.templateVariables(),
+	}
+}
+
+func (s *server) Plan(sess *provisionersdk.Session, request *proto.PlanRequest, canceledOrComplete <-chan struct{}) *proto.PlanComplete {
+	ctx, cancel := sessionContext(sess, canceledOrComplete)
+	defer cancel()
+
+	sp, err := readSpec(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.PlanErrorf("%s", err)
+	}
+	st, err := readState(sess.Config.State)
+	if err != nil {
+		return provisionersdk.PlanErrorf("%s", err)
+	}
+	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY {
+		if len(st.Objects) == 0 {
+			sess.ProvisionLog(proto.LogLevel_INFO, "The workspace has no objects, there is nothing to do")
+		}
+		for _, ref := range st.Objects {
+			sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("%s will be deleted", ref))
+		}
+		return &proto.PlanComplete{Parameters: sp.richParameters(), Presets: sp.presets()}
+	}
+
+	data := newTemplateData(sp, request.Metadata, request.RichParameterValues, request.VariableValues)
+	objects, err := render(ctx, sess.WorkDirectory, data)
+	if err != nil {
+		return provisionersdk.PlanErrorf("%s", err)
+	}
+	resources, err := convertResources(objects, st.Agents, request.Metadata.GetCoderUrl())
+	if err != nil {
+		return provisionersdk.PlanErrorf("%s", err)
+	}
+	sortForApply(objects)
+
+	// Template imports do not build a workspace, so they are not checked
+	// against the cluster.
+	if request.Metadata.GetWorkspaceId() != "" {
+		for _, obj := range objects {
+			_, err := s.client.apply(ctx, obj, true)
+			switch {
+			case err == nil:
+				sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("%s will be applied", obj.ref()))
+			case isNotFound(err) || isNotServed(err):
+				// The namespace or custom resource definition of the
+				// object may be created by the same template.
+				sess.ProvisionLog(proto.LogLevel_WARN, fmt.Sprintf("%s cannot be checked: %s", obj.ref(), err))
+			default:
+				return provisionersdk.PlanErrorf("%s: %s", obj.ref(), err)
+			}
+		}
+		for _, ref := range st.Objects {
+			if !containsObject(objects, ref) {
+				sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("%s will be deleted", ref))
+			}
+		}
+	}
+
+	err = writePlan(sess.WorkDirectory, plan{Objects: objects, Agents: st.Agents})
+	if err != nil {
+		return provisionersdk.PlanErrorf("write plan: %s", err)
+	}
+	return &proto.PlanComplete{
+		Resources:  resources,
+		Parameters: sp.richParameters(),
+		Presets:    sp.presets(),
+	}
+}
+
+func (s *server) Apply(sess *provisionersdk.Session, request *proto.ApplyRequest, canceledOrComplete <-chan struct{}) *proto.ApplyComplete {
+	ctx, cancel := sessionContext(sess, canceledOrComplete)
+	defer cancel()
+
+	st, err := readState(sess.Config.State)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("%s", err)
+	}
+	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY {
+		for i := len(st.Objects) - 1; i >= 0; i-- {
+			ref := st.Objects[i]
+			err = s.client.delete(ctx, ref)
+			if err != nil {
+				st.Objects = st.Objects[:i+1]
+				return s.applyError(st, xerrors.Errorf("delete %s: %w", ref, err))
+			}
+			sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("Deleted %s", ref))
+		}
+		return s.applyComplete(&state{Version: stateVersion}, nil)
+	}
+
+	p, err := readPlan(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("%s", err)
+	}
+	resources, err := convertResources(p.Objects, p.Agents, request.Metadata.GetCoderUrl())
+	if err != nil {
+		return provisionersdk.ApplyErrorf("%s", err)
+	}
+	next := &state{Version: stateVersion, Agents: p.Agents}
+	// Objects of the previous build stay in the state until they are
+	// pruned, so that they are not leaked if the build fails.
+	failed := func(err error) *proto.ApplyComplete {
+		for _, ref := range st.Objects {
+			if !containsRef(next.Objects, ref) {
+				next.Objects = append(next.Objects, ref)
+			}
+		}
+		return s.applyError(next, err)
+	}
+
+	appliedDefinition := false
+	for _, obj := range p.Objects {
+		applied, err := s.applyObject(ctx, obj, appliedDefinition)
+		if err != nil {
+			return failed(xerrors.Errorf("apply %s: %w", obj.ref(), err))
+		}
+		ref := applied.ref()
+		if ref.Name == "" {
+			ref = obj.ref()
+		}
+		next.Objects = append(next.Objects, ref)
+		if obj.kind() == "CustomResourceDefinition" {
+			appliedDefinition = true
+			s.client.forget()
+		}
+		sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("Applied %s", ref))
+	}
+
+	// Objects that are no longer rendered are deleted in the reverse order
+	// they were applied.
+	for i := len(st.Objects) - 1; i >= 0; i-- {
+		ref := st.Objects[i]
+		if containsRef(next.Objects, ref) {
+			continue
+		}
+		err = s.client.delete(ctx, ref)
+		if err != nil {
+			st.Objects = st.Objects[:i+1]
+			return failed(xerrors.Errorf("delete %s: %w", ref, err))
+		}
+		sess.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("Deleted %s", ref))
+	}
+	return s.applyComplete(next, resources)
+}
+
+// applyObject applies the object. If a custom resource definition was
+// applied before it, objects of kinds that are not served yet are retried
+// until the definition is established.
+func (s *server) applyObject(ctx context.Context, obj object, waitForDefinition bool) (object, error) {
+	if !waitForDefinition {
+		return s.client.apply(ctx, obj, false)
+	}
+	ctx, cancel := context.WithTimeout(ctx, crdEstablishTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		applied, err := s.client.apply(ctx, obj, false)
+		if !isNotServed(err) {
+			return applied, err
+		}
+		s.logger.Debug(ctx, "waiting for custom resource definition", slog.F("object", obj.ref().String()))
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-ticker.C:
+			s.client.forget()
+		}
+	}
+}
+
+func (*server) applyComplete(st *state, resources []*proto.Resource) *proto.ApplyComplete {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("encode state: %s", err)
+	}
+	return &proto.ApplyComplete{
+		State:     data,
+		Resources: resources,
+	}
+}
+
+func (s *server) applyError(st *state, err error) *proto.ApplyComplete {
+	complete := s.applyComplete(st, nil)
+	if complete.Error == "" {
+		complete.Error = err.Error()
+	}
+	return complete
+}
+
+func containsObject(objects []object, ref objectRef) bool {
+	for _, obj := range objects {
+		other := obj.ref()
+		// Objects without a namespace are created in the default
+		// namespace, which is recorded in the state.
+		if other.Namespace == "" {
+			other.Namespace = ref.Namespace
+		}
+		if sameObject(other, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRef(refs []objectRef, ref objectRef) bool {
+	for _, other := range refs {
+		if sameObject(other, ref) {
+			return true
+		}
+	}
+	return false
+}
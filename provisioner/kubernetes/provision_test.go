@@ -0,0 +1,430 @@
+package kubernetes_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/provisioner/kubernetes"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// fakeCluster is an API server that serves a few core and apps resources,
+// and stores the objects applied to it.
+type fakeCluster struct {
+	mu       sync.Mutex
+	objects  map[string]map[string]any
+	requests int
+}
+
+var fakeResources = map[string][]map[string]any{
+	"/api/v1": {
+		{"name": "namespaces", "namespaced": false, "kind": "Namespace"},
+		{"name": "configmaps", "namespaced": true, "kind": "ConfigMap"},
+		{"name": "pods", "namespaced": true, "kind": "Pod"},
+		{"name": "pods/log", "namespaced": true, "kind": "Pod"},
+	},
+	"/apis/apps/v1": {
+		{"name": "deployments", "namespaced": true, "kind": "Deployment"},
+	},
+}
+
+func (c *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+
+	writeJSON := func(code int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	if r.Method == http.MethodGet {
+		resources, ok := fakeResources[r.URL.Path]
+		if !ok {
+			writeJSON(http.StatusNotFound, map[string]any{"kind": "Status", "code": http.StatusNotFound})
+			return
+		}
+		writeJSON(http.StatusOK, map[string]any{"resources": resources})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") != "application/apply-patch+yaml" || r.URL.Query().Get("fieldManager") != "coder" {
+			writeJSON(http.StatusUnsupportedMediaType, map[string]any{"message": "not server-side apply"})
+			return
+		}
+		var obj map[string]any
+		err := json.NewDecoder(r.Body).Decode(&obj)
+		if err != nil {
+			writeJSON(http.StatusBadRequest, map[string]any{"message": err.Error()})
+			return
+		}
+		metadata, _ := obj["metadata"].(map[string]any)
+		if _, namespace, ok := strings.Cut(r.URL.Path, "/namespaces/"); ok && strings.Count(namespace, "/") > 0 {
+			metadata["namespace"], _, _ = strings.Cut(namespace, "/")
+		}
+		metadata["uid"] = uuid.NewString()
+		if r.URL.Query().Get("dryRun") != "All" {
+			c.objects[r.URL.Path] = obj
+		}
+		writeJSON(http.StatusOK, obj)
+	case http.MethodDelete:
+		if _, ok := c.objects[r.URL.Path]; !ok {
+			writeJSON(http.StatusNotFound, map[string]any{"kind": "Status", "code": http.StatusNotFound, "message": "not found"})
+			return
+		}
+		delete(c.objects, r.URL.Path)
+		writeJSON(http.StatusOK, map[string]any{"kind": "Status"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (c *fakeCluster) object(path string) map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.objects[path]
+}
+
+func (c *fakeCluster) paths() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	paths := make([]string, 0, len(c.objects))
+	for path := range c.objects {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (c *fakeCluster) requestCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+func setupProvisioner(t *testing.T) (*fakeCluster, proto.DRPCProvisionerClient) {
+	t.Helper()
+	cluster := &fakeCluster{objects: map[string]map[string]any{}}
+	srv := httptest.NewServer(cluster)
+	t.Cleanup(srv.Close)
+
+	client, server := drpc.MemTransportPipe()
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	serverErr := make(chan error, 1)
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+		cancelFunc()
+		err := <-serverErr
+		if !errors.Is(err, context.Canceled) {
+			assert.NoError(t, err)
+		}
+	})
+	go func() {
+		serverErr <- kubernetes.Serve(ctx, &kubernetes.ServeOptions{
+			ServeOptions: &provisionersdk.ServeOptions{
+				Listener:      server,
+				Logger:        slogtest.Make(t, nil).Leveled(slog.LevelDebug),
+				WorkDirectory: t.TempDir(),
+			},
+			Config: &kubernetes.Config{
+				Host:      srv.URL,
+				Namespace: "coder",
+			},
+		})
+	}()
+	return cluster, proto.NewDRPCProvisionerClient(client)
+}
+
+func makeTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name: name,
+			Size: int64(len(content)),
+			Mode: 0o644,
+		})
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Flush())
+	return buffer.Bytes()
+}
+
+func configure(ctx context.Context, t *testing.T, client proto.DRPCProvisionerClient, files map[string]string, state []byte) proto.DRPCProvisioner_SessionClient {
+	t.Helper()
+	sess, err := client.Session(ctx)
+	require.NoError(t, err)
+	err = sess.Send(&proto.Request{Type: &proto.Request_Config{Config: &proto.Config{
+		TemplateSourceArchive: makeTar(t, files),
+		State:                 state,
+	}}})
+	require.NoError(t, err)
+	return sess
+}
+
+// recv returns the next response that is not a log.
+func recv(t *testing.T, sess proto.DRPCProvisioner_SessionClient) *proto.Response {
+	t.Helper()
+	for {
+		msg, err := sess.Recv()
+		require.NoError(t, err)
+		if log := msg.GetLog(); log != nil {
+			t.Log(log.Level.String(), log.Output)
+			continue
+		}
+		return msg
+	}
+}
+
+// build plans and applies a workspace build in a new session.
+func build(ctx context.Context, t *testing.T, client proto.DRPCProvisionerClient, files map[string]string, state []byte, metadata *proto.Metadata, parameters ...*proto.RichParameterValue) (*proto.PlanComplete, *proto.ApplyComplete) {
+	t.Helper()
+	sess := configure(ctx, t, client, files, state)
+	err := sess.Send(&proto.Request{Type: &proto.Request_Plan{Plan: &proto.PlanRequest{
+		Metadata:            metadata,
+		RichParameterValues: parameters,
+	}}})
+	require.NoError(t, err)
+	planned := recv(t, sess).GetPlan()
+	require.NotNil(t, planned)
+	require.Empty(t, planned.Error)
+
+	err = sess.Send(&proto.Request{Type: &proto.Request_Apply{Apply: &proto.ApplyRequest{Metadata: metadata}}})
+	require.NoError(t, err)
+	applied := recv(t, sess).GetApply()
+	require.NotNil(t, applied)
+	return planned, applied
+}
+
+const testSpec = `
+variables:
+  - name: image
+    default: ubuntu
+parameters:
+  - name: cpu
+    display_name: CPU
+    type: number
+    default: "2"
+    mutable: true
+presets:
+  - name: Large
+    parameters:
+      cpu: "8"
+    prebuilds: 2
+`
+
+const testDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coder-{{ .Workspace.ID }}
+  annotations:
+    coder.com/agents: dev
+spec:
+  replicas: {{ .Workspace.StartCount }}
+  template:
+    spec:
+      containers:
+        - name: dev
+          image: {{ .Variables.image | quote }}
+          resources:
+            limits:
+              cpu: {{ .Parameters.cpu | quote }}
+`
+
+const testConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings-{{ .Workspace.ID }}
+data:
+  owner: {{ .Workspace.Owner }}
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	_, client := setupProvisioner(t)
+
+	sess := configure(ctx, t, client, map[string]string{
+		"coder.yaml":      testSpec,
+		"deployment.yaml": testDeployment,
+	}, nil)
+	err := sess.Send(&proto.Request{Type: &proto.Request_Parse{Parse: &proto.ParseRequest{}}})
+	require.NoError(t, err)
+	parsed := recv(t, sess).GetParse()
+	require.NotNil(t, parsed)
+	require.Empty(t, parsed.Error)
+	require.Len(t, parsed.TemplateVariables, 1)
+	require.Equal(t, "image", parsed.TemplateVariables[0].Name)
+	require.Equal(t, "ubuntu", parsed.TemplateVariables[0].DefaultValue)
+	require.False(t, parsed.TemplateVariables[0].Required)
+}
+
+func TestParsePresetUnknownParameter(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	_, client := setupProvisioner(t)
+
+	sess := configure(ctx, t, client, map[string]string{
+		"coder.yaml": testSpec + `
+  - name: GPU
+    parameters:
+      gpus: "1"
+`,
+		"deployment.yaml": testDeployment,
+	}, nil)
+	err := sess.Send(&proto.Request{Type: &proto.Request_Parse{Parse: &proto.ParseRequest{}}})
+	require.NoError(t, err)
+	parsed := recv(t, sess).GetParse()
+	require.NotNil(t, parsed)
+	require.Contains(t, parsed.Error, `preset "GPU" sets parameter "gpus", which is not declared`)
+}
+
+func TestProvision(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TemplateImport", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cluster, client := setupProvisioner(t)
+
+		sess := configure(ctx, t, client, map[string]string{
+			"coder.yaml":      testSpec,
+			"deployment.yaml": testDeployment,
+		}, nil)
+		err := sess.Send(&proto.Request{Type: &proto.Request_Plan{Plan: &proto.PlanRequest{
+			Metadata: &proto.Metadata{
+				CoderUrl:            "https://coder.example.com",
+				WorkspaceTransition: proto.WorkspaceTransition_START,
+			},
+		}}})
+		require.NoError(t, err)
+		planned := recv(t, sess).GetPlan()
+		require.NotNil(t, planned)
+		require.Empty(t, planned.Error)
+		require.Len(t, planned.Resources, 1)
+		require.Equal(t, "kubernetes_deployment", planned.Resources[0].Type)
+		require.Len(t, planned.Resources[0].Agents, 1)
+		require.Equal(t, "dev", planned.Resources[0].Agents[0].Name)
+		require.Len(t, planned.Parameters, 1)
+		require.Equal(t, "cpu", planned.Parameters[0].Name)
+		require.Len(t, planned.Presets, 1)
+		require.Equal(t, "Large", planned.Presets[0].Name)
+		require.Equal(t, []*proto.PresetParameter{{Name: "cpu", Value: "8"}}, planned.Presets[0].Parameters)
+		require.EqualValues(t, 2, planned.Presets[0].Prebuilds)
+		require.Equal(t, 0, cluster.requestCount(), "template imports must not contact the cluster")
+	})
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cluster, client := setupProvisioner(t)
+
+		workspaceID := uuid.NewString()
+		metadata := &proto.Metadata{
+			CoderUrl:            "https://coder.example.com",
+			WorkspaceTransition: proto.WorkspaceTransition_START,
+			WorkspaceId:         workspaceID,
+			WorkspaceOwner:      "alice",
+		}
+		deploymentPath := "/apis/apps/v1/namespaces/coder/deployments/coder-" + workspaceID
+		configMapPath := "/api/v1/namespaces/coder/configmaps/settings-" + workspaceID
+		files := map[string]string{
+			"coder.yaml":      testSpec,
+			"deployment.yaml": testDeployment,
+			"configmap.yaml":  testConfigMap,
+		}
+
+		planned, applied := build(ctx, t, client, files, nil, metadata, &proto.RichParameterValue{Name: "cpu", Value: "4"})
+		require.Empty(t, applied.Error)
+		require.Len(t, planned.Resources, 2)
+		require.Equal(t, "kubernetes_config_map", applied.Resources[0].Type, "config maps are applied first")
+		require.Equal(t, "kubernetes_deployment", applied.Resources[1].Type)
+		agent := applied.Resources[1].Agents[0]
+		require.Equal(t, planned.Resources[1].Agents[0].Id, agent.Id)
+		require.Equal(t, planned.Resources[1].Agents[0].GetToken(), agent.GetToken())
+		require.ElementsMatch(t, []string{deploymentPath, configMapPath}, cluster.paths())
+
+		deployment := cluster.object(deploymentPath)
+		require.EqualValues(t, 1, deployment["spec"].(map[string]any)["replicas"])
+		container := deployment["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+		require.Equal(t, "ubuntu", container["image"])
+		require.Equal(t, "4", container["resources"].(map[string]any)["limits"].(map[string]any)["cpu"])
+		require.Contains(t, container["env"], map[string]any{"name": "CODER_AGENT_TOKEN", "value": agent.GetToken()})
+		require.Contains(t, container["env"], map[string]any{"name": "CODER_AGENT_URL", "value": "https://coder.example.com/"})
+		command := container["command"].([]any)
+		require.Equal(t, []any{"sh", "-c"}, command[:2])
+		require.Contains(t, command[2], "https://coder.example.com/bin/coder-linux-amd64")
+		require.Equal(t, "alice", cluster.object(configMapPath)["data"].(map[string]any)["owner"])
+
+		// Objects that are no longer rendered are deleted, and the agent
+		// keeps its token.
+		delete(files, "configmap.yaml")
+		metadata.WorkspaceTransition = proto.WorkspaceTransition_STOP
+		_, applied = build(ctx, t, client, files, applied.State, metadata)
+		require.Empty(t, applied.Error)
+		require.Equal(t, agent.GetToken(), applied.Resources[0].Agents[0].GetToken())
+		require.Equal(t, []string{deploymentPath}, cluster.paths())
+		require.EqualValues(t, 0, cluster.object(deploymentPath)["spec"].(map[string]any)["replicas"])
+
+		metadata.WorkspaceTransition = proto.WorkspaceTransition_DESTROY
+		_, applied = build(ctx, t, client, files, applied.State, metadata)
+		require.Empty(t, applied.Error)
+		require.Empty(t, cluster.paths())
+		var state struct {
+			Objects []any `json:"objects"`
+		}
+		require.NoError(t, json.Unmarshal(applied.State, &state))
+		require.Empty(t, state.Objects)
+	})
+
+	t.Run("ApplyFailureKeepsState", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cluster, client := setupProvisioner(t)
+
+		workspaceID := uuid.NewString()
+		metadata := &proto.Metadata{
+			CoderUrl:            "https://coder.example.com",
+			WorkspaceTransition: proto.WorkspaceTransition_START,
+			WorkspaceId:         workspaceID,
+		}
+		_, applied := build(ctx, t, client, map[string]string{"configmap.yaml": testConfigMap}, nil, metadata)
+		require.Empty(t, applied.Error)
+
+		// The kind is not served, so the build fails before the config map
+		// is pruned.
+		_, applied = build(ctx, t, client, map[string]string{"widget.yaml": `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`}, applied.State, metadata)
+		require.Contains(t, applied.Error, "does not serve")
+		require.Len(t, cluster.paths(), 1)
+		require.Contains(t, string(applied.State), "settings-"+workspaceID)
+	})
+}
@@ -0,0 +1,290 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	// agentsAnnotation lists the containers of a pod, or of the pod template
+	// of a workload, that run an agent.
+	agentsAnnotation = "coder.com/agents"
+	// agentArchAnnotation is the architecture of the nodes agents run on.
+	agentArchAnnotation = "coder.com/agent-arch"
+
+	stateVersion = 1
+	// planFile stores the objects planned for a workspace until they are
+	// applied. It is hidden, so it is never rendered as a manifest.
+	planFile = ".plan.json"
+)
+
+// state is the workspace state persisted between builds.
+type state struct {
+	Version int `json:"version"`
+	// Objects are the objects applied by the last build, in the order they
+	// were applied.
+	Objects []objectRef `json:"objects"`
+	// Agents keeps the ID and token of agents stable across builds.
+	Agents map[string]agentState `json:"agents"`
+}
+
+type agentState struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+func readState(data []byte) (*state, error) {
+	s := &state{Version: stateVersion, Agents: map[string]agentState{}}
+	if len(data) == 0 {
+		return s, nil
+	}
+	err := json.Unmarshal(data, s)
+	if err != nil {
+		return nil, xerrors.Errorf("decode state: %w", err)
+	}
+	if s.Version != stateVersion {
+		return nil, xerrors.Errorf("unsupported state version %d", s.Version)
+	}
+	if s.Agents == nil {
+		s.Agents = map[string]agentState{}
+	}
+	return s, nil
+}
+
+// plan is what Plan passes on to Apply. The objects are stored as they were
+// rendered, and agents are added to them again with the IDs and tokens
+// assigned during the plan.
+type plan struct {
+	Objects []object              `json:"objects"`
+	Agents  map[string]agentState `json:"agents"`
+}
+
+func writePlan(workdir string, p plan) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(workdir, planFile), data, 0o600)
+}
+
+func readPlan(workdir string) (*plan, error) {
+	data, err := os.ReadFile(filepath.Join(workdir, planFile))
+	if err != nil {
+		return nil, xerrors.Errorf("read plan: %w", err)
+	}
+	var p plan
+	err = json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, xerrors.Errorf("decode plan: %w", err)
+	}
+	if p.Agents == nil {
+		p.Agents = map[string]agentState{}
+	}
+	return &p, nil
+}
+
+// podSpec returns the pod spec of a pod or of the pod template of a
+// workload, or nil if the object has none.
+func podSpec(obj object) map[string]any {
+	var spec any
+	switch obj.kind() {
+	case "Pod":
+		spec = field(obj, "spec")
+	case "CronJob":
+		spec = field(obj, "spec", "jobTemplate", "spec", "template", "spec")
+	default:
+		spec = field(obj, "spec", "template", "spec")
+	}
+	m, _ := spec.(map[string]any)
+	return m
+}
+
+// agentAnnotations returns the annotations of the object, merged with those
+// of its pod template.
+func agentAnnotations(obj object) map[string]string {
+	annotations := obj.annotations()
+	var template map[string]string
+	switch obj.kind() {
+	case "Pod":
+	case "CronJob":
+		template = stringMap(field(obj, "spec", "jobTemplate", "spec", "template", "metadata", "annotations"))
+	default:
+		template = stringMap(field(obj, "spec", "template", "metadata", "annotations"))
+	}
+	for key, value := range template {
+		if _, ok := annotations[key]; !ok {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
+// convertResources returns the resources of the objects, with the agents
+// declared by their annotations. The containers of agents are modified to
+// run the agent, and agents that are not in agents are assigned an ID and
+// token there.
+func convertResources(objects []object, agents map[string]agentState, accessURL string) ([]*proto.Resource, error) {
+	if !strings.HasSuffix(accessURL, "/") {
+		accessURL += "/"
+	}
+	scripts := provisionersdk.AgentScriptEnv()
+	seen := map[string]objectRef{}
+	resources := make([]*proto.Resource, 0, len(objects))
+	for _, obj := range objects {
+		resource := &proto.Resource{
+			Name: obj.name(),
+			Type: "kubernetes_" + snakeCase(obj.kind()),
+		}
+		if namespace := obj.namespace(); namespace != "" {
+			resource.Metadata = append(resource.Metadata, &proto.Resource_Metadata{
+				Key:   "namespace",
+				Value: namespace,
+			})
+		}
+		resources = append(resources, resource)
+
+		annotations := agentAnnotations(obj)
+		if annotations[agentsAnnotation] == "" {
+			continue
+		}
+		spec := podSpec(obj)
+		if spec == nil {
+			return nil, xerrors.Errorf("%s: %s is only supported on pods and workloads", obj.ref(), agentsAnnotation)
+		}
+		arch := annotations[agentArchAnnotation]
+		if arch == "" {
+			arch = "amd64"
+		}
+		script, ok := scripts["CODER_AGENT_SCRIPT_linux_"+arch]
+		if !ok {
+			return nil, xerrors.Errorf("%s: unsupported agent architecture %q", obj.ref(), arch)
+		}
+		script = strings.ReplaceAll(script, "${ACCESS_URL}", accessURL)
+		script = strings.ReplaceAll(script, "${AUTH_TYPE}", "token")
+
+		containers, _ := spec["containers"].([]any)
+		for _, name := range strings.Split(annotations[agentsAnnotation], ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if other, ok := seen[name]; ok {
+				return nil, xerrors.Errorf("%s: agent %q is also declared by %s", obj.ref(), name, other)
+			}
+			seen[name] = obj.ref()
+
+			var container map[string]any
+			for _, c := range containers {
+				if m, ok := c.(map[string]any); ok && stringField(m, "name") == name {
+					container = m
+					break
+				}
+			}
+			if container == nil {
+				return nil, xerrors.Errorf("%s: agent container %q not found", obj.ref(), name)
+			}
+
+			agent, ok := agents[name]
+			if !ok {
+				agent = agentState{ID: uuid.NewString(), Token: uuid.NewString()}
+				agents[name] = agent
+			}
+			setEnv(container, "CODER_AGENT_TOKEN", agent.Token)
+			setEnv(container, "CODER_AGENT_URL", accessURL)
+			// Containers that do not set their own command start the
+			// agent. Otherwise, the image is expected to start it.
+			if container["command"] == nil && container["args"] == nil {
+				container["command"] = []any{"sh", "-c", script}
+			}
+
+			resource.Agents = append(resource.Agents, &proto.Agent{
+				Id:                       agent.ID,
+				Name:                     name,
+				Auth:                     &proto.Agent_Token{Token: agent.Token},
+				OperatingSystem:          "linux",
+				Architecture:             arch,
+				ConnectionTimeoutSeconds: 120,
+				DisplayApps:              provisionersdk.DefaultDisplayApps(),
+			})
+		}
+	}
+	return resources, nil
+}
+
+// setEnv sets an environment variable of a container, replacing any value set
+// by the template.
+func setEnv(container map[string]any, name, value string) {
+	env, _ := container["env"].([]any)
+	filtered := make([]any, 0, len(env)+1)
+	for _, e := range env {
+		if stringField(e, "name") != name {
+			filtered = append(filtered, e)
+		}
+	}
+	container["env"] = append(filtered, map[string]any{"name": name, "value": value})
+}
+
+// snakeCase converts a kind to the snake case used in resource types, e.g.
+// "PersistentVolumeClaim" to "persistent_volume_claim".
+func snakeCase(kind string) string {
+	var b strings.Builder
+	runes := []rune(kind)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Acronyms like "CSIDriver" stay together.
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// applyPriority orders kinds that other objects depend on first.
+var applyPriority = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 1,
+	"ServiceAccount":           2,
+	"Secret":                   3,
+	"ConfigMap":                3,
+	"PersistentVolumeClaim":    4,
+}
+
+// sortForApply sorts objects in the order they are applied. Objects of the
+// same priority keep the order of the template.
+func sortForApply(objects []object) {
+	priority := func(obj object) int {
+		if p, ok := applyPriority[obj.kind()]; ok {
+			return p
+		}
+		return len(applyPriority)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return priority(objects[i]) < priority(objects[j])
+	})
+}
+
+// sameObject reports whether two references identify the same object, even
+// if it was applied with another version of its API.
+func sameObject(a, b objectRef) bool {
+	group := func(apiVersion string) string {
+		if group, _, ok := strings.Cut(apiVersion, "/"); ok {
+			return group
+		}
+		return ""
+	}
+	return group(a.APIVersion) == group(b.APIVersion) && a.Kind == b.Kind &&
+		a.Namespace == b.Namespace && a.Name == b.Name
+}
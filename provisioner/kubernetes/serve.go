@@ -0,0 +1,42 @@
+package kubernetes
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+type ServeOptions struct {
+	*provisionersdk.ServeOptions
+
+	// Config describes the cluster workspaces are created in. If nil, it is
+	// loaded with LoadConfig.
+	Config *Config
+}
+
+// Serve starts a dRPC server on the provided transport speaking the
+// Kubernetes provisioner.
+func Serve(ctx context.Context, options *ServeOptions) error {
+	if options.Config == nil {
+		config, err := LoadConfig("")
+		if err != nil {
+			return xerrors.Errorf("load kubernetes config: %w", err)
+		}
+		options.Config = config
+	}
+	options.Logger.Info(ctx, "provisioning workspaces in kubernetes",
+		slog.F("host", options.Config.Host),
+		slog.F("namespace", options.Config.Namespace))
+	return provisionersdk.Serve(ctx, &server{
+		client: newClient(options.Config),
+		logger: options.Logger,
+	}, options.ServeOptions)
+}
+
+type server struct {
+	client *client
+	logger slog.Logger
+}
@@ -0,0 +1,462 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cli/safeexec"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	// specFile declares the variables and parameters of a template. It is
+	// not a manifest.
+	specFile          = "coder.yaml"
+	kustomizationFile = "kustomization.yaml"
+	// renderDir is where manifests are rendered to in the work directory
+	// before they are built with Kustomize.
+	renderDir = ".rendered"
+)
+
+// spec is the format of coder.yaml.
+type spec struct {
+	Variables  []specVariable  `yaml:"variables"`
+	Parameters []specParameter `yaml:"parameters"`
+	Presets    []specPreset    `yaml:"presets"`
+}
+
+type specVariable struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Type        string  `yaml:"type"`
+	Default     *string `yaml:"default"`
+	Sensitive   bool    `yaml:"sensitive"`
+}
+
+type specParameter struct {
+	Name        string `yaml:"name"`
+	DisplayName string `yaml:"display_name"`
+	Description string `yaml:"description"`
+	// Type is one of string, number, bool or list(string).
+	Type      string  `yaml:"type"`
+	Default   *string `yaml:"default"`
+	Mutable   bool    `yaml:"mutable"`
+	Ephemeral bool    `yaml:"ephemeral"`
+	Icon      string  `yaml:"icon"`
+	Order     int32   `yaml:"order"`
+	Options   []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Value       string `yaml:"value"`
+		Icon        string `yaml:"icon"`
+	} `yaml:"options"`
+	Validation struct {
+		Regex     string `yaml:"regex"`
+		Error     string `yaml:"error"`
+		Min       *int32 `yaml:"min"`
+		Max       *int32 `yaml:"max"`
+		Monotonic string `yaml:"monotonic"`
+	} `yaml:"validation"`
+}
+
+// specPreset is a named set of parameter values.
+type specPreset struct {
+	Name       string            `yaml:"name"`
+	Parameters map[string]string `yaml:"parameters"`
+	// Prebuilds is the number of workspaces kept built with the preset.
+	Prebuilds int32 `yaml:"prebuilds"`
+}
+
+func readSpec(workdir string) (*spec, error) {
+	data, err := os.ReadFile(filepath.Join(workdir, specFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &spec{}, nil
+		}
+		return nil, err
+	}
+	var s spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err = dec.Decode(&s)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, xerrors.Errorf("decode %s: %w", specFile, err)
+	}
+	for _, v := range s.Variables {
+		if v.Name == "" {
+			return nil, xerrors.Errorf("%s: variables must have a name", specFile)
+		}
+	}
+	parameters := map[string]bool{}
+	for _, p := range s.Parameters {
+		if p.Name == "" {
+			return nil, xerrors.Errorf("%s: parameters must have a name", specFile)
+		}
+		parameters[p.Name] = true
+	}
+	presets := map[string]bool{}
+	for _, p := range s.Presets {
+		if p.Name == "" {
+			return nil, xerrors.Errorf("%s: presets must have a name", specFile)
+		}
+		if presets[p.Name] {
+			return nil, xerrors.Errorf("%s: preset %q is declared more than once", specFile, p.Name)
+		}
+		presets[p.Name] = true
+		if p.Prebuilds < 0 {
+			return nil, xerrors.Errorf("%s: preset %q must not have a negative number of prebuilds", specFile, p.Name)
+		}
+		for name := range p.Parameters {
+			if !parameters[name] {
+				return nil, xerrors.Errorf("%s: preset %q sets parameter %q, which is not declared", specFile, p.Name, name)
+			}
+		}
+	}
+	return &s, nil
+}
+
+func (s *spec) templateVariables() []*proto.TemplateVariable {
+	variables := make([]*proto.TemplateVariable, 0, len(s.Variables))
+	for _, v := range s.Variables {
+		typ := v.Type
+		if typ == "" {
+			typ = "string"
+		}
+		variable := &proto.TemplateVariable{
+			Name:        v.Name,
+			Description: v.Description,
+			Type:        typ,
+			Required:    v.Default == nil,
+			Sensitive:   v.Sensitive,
+		}
+		if v.Default != nil {
+			variable.DefaultValue = *v.Default
+		}
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+func (s *spec) richParameters() []*proto.RichParameter {
+	parameters := make([]*proto.RichParameter, 0, len(s.Parameters))
+	for _, p := range s.Parameters {
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		parameter := &proto.RichParameter{
+			Name:                p.Name,
+			DisplayName:         p.DisplayName,
+			Description:         p.Description,
+			Type:                typ,
+			Mutable:             p.Mutable,
+			Ephemeral:           p.Ephemeral,
+			Icon:                p.Icon,
+			Order:               p.Order,
+			Required:            p.Default == nil,
+			ValidationRegex:     p.Validation.Regex,
+			ValidationError:     p.Validation.Error,
+			ValidationMin:       p.Validation.Min,
+			ValidationMax:       p.Validation.Max,
+			ValidationMonotonic: p.Validation.Monotonic,
+		}
+		if p.Default != nil {
+			parameter.DefaultValue = *p.Default
+		}
+		for _, o := range p.Options {
+			parameter.Options = append(parameter.Options, &proto.RichParameterOption{
+				Name:        o.Name,
+				Description: o.Description,
+				Value:       o.Value,
+				Icon:        o.Icon,
+			})
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+func (s *spec) presets() []*proto.Preset {
+	presets := make([]*proto.Preset, 0, len(s.Presets))
+	for _, p := range s.Presets {
+		preset := &proto.Preset{Name: p.Name, Prebuilds: p.Prebuilds}
+		for name, value := range p.Parameters {
+			preset.Parameters = append(preset.Parameters, &proto.PresetParameter{
+				Name:  name,
+				Value: value,
+			})
+		}
+		sort.Slice(preset.Parameters, func(i, j int) bool {
+			return preset.Parameters[i].Name < preset.Parameters[j].Name
+		})
+		presets = append(presets, preset)
+	}
+	return presets
+}
+
+// templateData is available to manifests as ".".
+type templateData struct {
+	Workspace struct {
+		ID         string
+		Name       string
+		Owner      string
+		OwnerID    string
+		OwnerName  string
+		OwnerEmail string
+		// Transition is "start", "stop" or "destroy".
+		Transition string
+		// Start is true if the workspace is started, which is convenient
+		// to scale workloads, e.g. "replicas: {{ .Workspace.StartCount }}".
+		Start      bool
+		StartCount int
+	}
+	Template struct {
+		ID      string
+		Name    string
+		Version string
+	}
+	AccessURL  string
+	Parameters map[string]string
+	Variables  map[string]string
+}
+
+func newTemplateData(s *spec, metadata *proto.Metadata, parameters []*proto.RichParameterValue, variables []*proto.VariableValue) templateData {
+	var data templateData
+	data.Workspace.ID = metadata.GetWorkspaceId()
+	data.Workspace.Name = metadata.GetWorkspaceName()
+	data.Workspace.Owner = metadata.GetWorkspaceOwner()
+	data.Workspace.OwnerID = metadata.GetWorkspaceOwnerId()
+	data.Workspace.OwnerName = metadata.GetWorkspaceOwnerName()
+	data.Workspace.OwnerEmail = metadata.GetWorkspaceOwnerEmail()
+	data.Workspace.Transition = strings.ToLower(metadata.GetWorkspaceTransition().String())
+	data.Workspace.Start = metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_START
+	if data.Workspace.Start {
+		data.Workspace.StartCount = 1
+	}
+	data.Template.ID = metadata.GetTemplateId()
+	data.Template.Name = metadata.GetTemplateName()
+	data.Template.Version = metadata.GetTemplateVersion()
+	data.AccessURL = metadata.GetCoderUrl()
+
+	data.Parameters = map[string]string{}
+	for _, p := range s.Parameters {
+		if p.Default != nil {
+			data.Parameters[p.Name] = *p.Default
+		}
+	}
+	for _, p := range parameters {
+		data.Parameters[p.Name] = p.Value
+	}
+	data.Variables = map[string]string{}
+	for _, v := range s.Variables {
+		if v.Default != nil {
+			data.Variables[v.Name] = *v.Default
+		}
+	}
+	for _, v := range variables {
+		data.Variables[v.Name] = v.Value
+	}
+	return data
+}
+
+var templateFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+	"default": func(def string, v any) any {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"trim":   strings.TrimSpace,
+	"b64enc": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"toJson": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func isManifest(path string) bool {
+	ext := filepath.Ext(path)
+	return (ext == ".yaml" || ext == ".yml" || ext == ".json") && filepath.Base(path) != specFile
+}
+
+// render renders the manifests of the template in workdir and returns the
+// objects they declare. If the template has a kustomization, the rendered
+// manifests are built with Kustomize.
+func render(ctx context.Context, workdir string, data templateData) ([]object, error) {
+	out := filepath.Join(workdir, renderDir)
+	err := os.RemoveAll(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(workdir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != workdir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("find manifests: %w", err)
+	}
+	sort.Strings(files)
+
+	var (
+		manifests []string
+		documents [][]byte
+	)
+	for _, path := range files {
+		rel, err := filepath.Rel(workdir, path)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// Other files are copied as they are, since a kustomization may
+		// refer to them.
+		if isManifest(path) {
+			tmpl, err := template.New(rel).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(content))
+			if err != nil {
+				return nil, xerrors.Errorf("parse %s: %w", rel, err)
+			}
+			var rendered bytes.Buffer
+			err = tmpl.Execute(&rendered, data)
+			if err != nil {
+				return nil, xerrors.Errorf("render %s: %w", rel, err)
+			}
+			content = rendered.Bytes()
+			manifests = append(manifests, path)
+			documents = append(documents, content)
+		}
+		target := filepath.Join(out, rel)
+		err = os.MkdirAll(filepath.Dir(target), 0o700)
+		if err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(target, content, 0o600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(out, kustomizationFile)); err == nil {
+		built, err := kustomize(ctx, out)
+		if err != nil {
+			return nil, err
+		}
+		documents = [][]byte{built}
+	}
+
+	var objects []object
+	for i, document := range documents {
+		decoded, err := decodeObjects(document)
+		if err != nil {
+			name := "kustomize output"
+			if len(documents) == len(manifests) {
+				name, _ = filepath.Rel(workdir, manifests[i])
+			}
+			return nil, xerrors.Errorf("decode %s: %w", name, err)
+		}
+		objects = append(objects, decoded...)
+	}
+	return objects, nil
+}
+
+// decodeObjects decodes the YAML documents in data. Lists are flattened into
+// their items.
+func decodeObjects(data []byte) ([]object, error) {
+	var objects []object
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			// Empty documents are left by conditional templates.
+			continue
+		}
+		// Round trip through JSON to normalize the types of values, e.g.
+		// integers.
+		normalized, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		var obj object
+		err = json.Unmarshal(normalized, &obj)
+		if err != nil {
+			return nil, err
+		}
+		items := []object{obj}
+		if list, ok := obj["items"].([]any); ok && strings.HasSuffix(obj.kind(), "List") {
+			items = items[:0]
+			for _, item := range list {
+				m, _ := item.(map[string]any)
+				items = append(items, object(m))
+			}
+		}
+		for _, item := range items {
+			if item.apiVersion() == "" || item.kind() == "" || item.name() == "" {
+				return nil, xerrors.New("objects must have an apiVersion, kind and metadata.name")
+			}
+			objects = append(objects, item)
+		}
+	}
+	return objects, nil
+}
+
+// kustomize builds the kustomization in dir with the kustomize binary, or
+// with kubectl if it is not installed.
+func kustomize(ctx context.Context, dir string) ([]byte, error) {
+	args := []string{"build", dir}
+	binary, err := safeexec.LookPath("kustomize")
+	if err != nil {
+		binary, err = safeexec.LookPath("kubectl")
+		if err != nil {
+			return nil, xerrors.New("the template has a kustomization, but neither kustomize nor kubectl is installed")
+		}
+		args = []string{"kustomize", dir}
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("kustomize: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -60,6 +60,11 @@ func CleanStaleTerraformPlugins(ctx context.Context, cachePath string, fs afero.
 			return err
 		}
 
+		// The module cache evicts modules itself.
+		if info.IsDir() && path == filepath.Join(cachePath, moduleCacheDirName) {
+			return filepath.SkipDir
+		}
+
 		if !filterFunc(path, info) {
 			return nil
 		}
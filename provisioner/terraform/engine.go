@@ -0,0 +1,237 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cli/safeexec"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// Engine is the command-line tool that executes a template's Terraform
+// configuration.
+type Engine string
+
+const (
+	EngineTerraform Engine = "terraform"
+	EngineOpenTofu  Engine = "opentofu"
+)
+
+// Valid returns whether the engine is known.
+func (e Engine) Valid() bool {
+	switch e {
+	case EngineTerraform, EngineOpenTofu:
+		return true
+	default:
+		return false
+	}
+}
+
+// displayName is the name of the engine used in messages.
+func (e Engine) displayName() string {
+	switch e {
+	case EngineOpenTofu:
+		return "OpenTofu"
+	default:
+		return "Terraform"
+	}
+}
+
+// command is the name of the engine's executable, without any extension.
+func (e Engine) command() string {
+	if e == EngineOpenTofu {
+		return "tofu"
+	}
+	return "terraform"
+}
+
+// binaryName is the file name of the engine's executable.
+func (e Engine) binaryName() string {
+	if runtime.GOOS == "windows" {
+		return e.command() + ".exe"
+	}
+	return e.command()
+}
+
+// defaultVersion is the version installed when no binary is available on the
+// system.
+func (e Engine) defaultVersion() *version.Version {
+	if e == EngineOpenTofu {
+		return OpenTofuVersion
+	}
+	return TerraformVersion
+}
+
+func (e Engine) minVersion() *version.Version {
+	if e == EngineOpenTofu {
+		return minOpenTofuVersion
+	}
+	return minTerraformVersion
+}
+
+// versionsDir is where versions of the engine that a template pinned are
+// installed, each in a directory named after the version.
+func versionsDir(cachePath string, engine Engine) string {
+	return filepath.Join(cachePath, "versions", string(engine))
+}
+
+// requiredVersion returns the constraints declared by the "required_version"
+// attributes of the module in workdir, or nil if there are none.
+func requiredVersion(workdir string) (version.Constraints, error) {
+	module, diags := tfconfig.LoadModule(workdir)
+	if diags.HasErrors() {
+		return nil, xerrors.Errorf("load module: %s", formatDiagnostics(workdir, diags))
+	}
+	return moduleRequiredVersion(module)
+}
+
+// moduleRequiredVersion returns the constraints declared by the
+// "required_version" attributes of the module, or nil if there are none.
+func moduleRequiredVersion(module *tfconfig.Module) (version.Constraints, error) {
+	if len(module.RequiredCore) == 0 {
+		return nil, nil
+	}
+	constraints, err := version.NewConstraint(strings.Join(module.RequiredCore, ","))
+	if err != nil {
+		return nil, xerrors.Errorf("parse required_version %q: %w", strings.Join(module.RequiredCore, ", "), err)
+	}
+	return constraints, nil
+}
+
+// InstalledVersions returns the versions of the engine a provisioner daemon
+// executes templates with without downloading them: the binary on the $PATH,
+// or the default version installed in its place, and the versions in the
+// cache. Daemons that do not download engine versions advertise them so they
+// are only assigned jobs of templates whose required_version they satisfy.
+func InstalledVersions(ctx context.Context, engine Engine, cachePath string) ([]string, error) {
+	defaultVersion := engine.defaultVersion()
+	binaryPath, err := safeexec.LookPath(engine.command())
+	if err == nil {
+		v, err := versionFromBinaryPath(ctx, binaryPath)
+		if err == nil && !v.LessThan(engine.minVersion()) {
+			defaultVersion = v
+		}
+	}
+
+	cached, err := cachedVersions(cachePath, engine)
+	if err != nil {
+		return nil, err
+	}
+	versions := []string{defaultVersion.String()}
+	for _, v := range cached {
+		if !v.Equal(defaultVersion) {
+			versions = append(versions, v.String())
+		}
+	}
+	return versions, nil
+}
+
+// cachedVersions returns the versions of the engine installed in the cache,
+// newest first.
+func cachedVersions(cachePath string, engine Engine) ([]*version.Version, error) {
+	dir := versionsDir(cachePath, engine)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("read %q: %w", dir, err)
+	}
+	var versions []*version.Version
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			continue
+		}
+		_, err = os.Stat(filepath.Join(dir, entry.Name(), engine.binaryName()))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(version.Collection(versions)))
+	return versions, nil
+}
+
+// resolveBinary returns the path of a binary satisfying the version constraints
+// declared by the template in workdir. The default binary is preferred,
+// followed by the newest satisfying version in the cache. If neither
+// satisfies the constraints, the newest satisfying release is installed into
+// the cache, unless downloads are disabled.
+func (s *server) resolveBinary(ctx context.Context, workdir string, sink logSink) (string, error) {
+	constraints, err := requiredVersion(workdir)
+	if err != nil {
+		return "", err
+	}
+
+	defaultPath, err := s.defaultBinaryPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	if constraints == nil {
+		return defaultPath, nil
+	}
+	defaultVersion, err := versionFromBinaryPath(ctx, defaultPath)
+	if err != nil {
+		return "", xerrors.Errorf("get %s version: %w", s.engine.displayName(), err)
+	}
+	if constraints.Check(defaultVersion) {
+		return defaultPath, nil
+	}
+
+	cached, err := cachedVersions(s.cachePath, s.engine)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range cached {
+		if constraints.Check(v) {
+			return filepath.Join(versionsDir(s.cachePath, s.engine), v.String(), s.engine.binaryName()), nil
+		}
+	}
+
+	if s.disableEngineDownload {
+		return "", xerrors.Errorf("no installed %s version satisfies required_version %q, and downloading it is disabled on this provisioner", s.engine.displayName(), constraints.String())
+	}
+	sink.ProvisionLog(proto.LogLevel_INFO, "Installing "+s.engine.displayName()+" to satisfy required_version "+constraints.String())
+	want, err := latestVersion(ctx, s.engine, constraints)
+	if err != nil {
+		return "", xerrors.Errorf("no installed %s version satisfies required_version %q, and one could not be downloaded: %w", s.engine.displayName(), constraints.String(), err)
+	}
+	s.logger.Info(ctx, "installing pinned version",
+		slog.F("engine", s.engine),
+		slog.F("constraints", constraints.String()),
+		slog.F("version", want.String()),
+	)
+	return InstallEngine(ctx, s.logger, s.engine, filepath.Join(versionsDir(s.cachePath, s.engine), want.String()), want)
+}
+
+// defaultBinaryPath returns the binary used by templates that do not
+// constrain the engine version. It is installed on first use if the daemon
+// was started without one.
+func (s *server) defaultBinaryPath(ctx context.Context) (string, error) {
+	s.defaultMut.Lock()
+	defer s.defaultMut.Unlock()
+	if s.defaultBinary != "" {
+		return s.defaultBinary, nil
+	}
+	want := s.engine.defaultVersion()
+	s.logger.Info(ctx, "installing default version", slog.F("engine", s.engine), slog.F("version", want.String()))
+	binPath, err := InstallEngine(ctx, s.logger, s.engine, filepath.Join(versionsDir(s.cachePath, s.engine), want.String()), want)
+	if err != nil {
+		return "", xerrors.Errorf("install %s: %w", s.engine.displayName(), err)
+	}
+	s.defaultBinary = binPath
+	return binPath, nil
+}
@@ -0,0 +1,138 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type discardSink struct{}
+
+func (discardSink) ProvisionLog(proto.LogLevel, string) {}
+
+// writeFakeEngine writes a script to path that reports the given version in
+// the format of "terraform version -json".
+func writeFakeEngine(t *testing.T, path, version string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	content := fmt.Sprintf(`#!/bin/sh
+cat <<-EOF
+{"terraform_version": "%s", "platform": "linux_amd64", "provider_selections": {}, "terraform_outdated": false}
+EOF`, version)
+	// #nosec
+	err := os.WriteFile(path, []byte(content), 0o770)
+	require.NoError(t, err)
+}
+
+func TestResolveBinary(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Dummy terraform executable on Windows requires sh which isn't very practical.")
+	}
+
+	setup := func(t *testing.T, engine Engine, requiredVersion string, cached ...string) (*server, string) {
+		t.Helper()
+		cachePath := t.TempDir()
+		defaultBinary := filepath.Join(t.TempDir(), engine.binaryName())
+		writeFakeEngine(t, defaultBinary, "1.6.2")
+		for _, v := range cached {
+			writeFakeEngine(t, filepath.Join(versionsDir(cachePath, engine), v, engine.binaryName()), v)
+		}
+
+		workdir := t.TempDir()
+		main := `resource "null_resource" "example" {}`
+		if requiredVersion != "" {
+			main = fmt.Sprintf("terraform {\n  required_version = %q\n}\n", requiredVersion) + main
+		}
+		err := os.WriteFile(filepath.Join(workdir, "main.tf"), []byte(main), 0o600)
+		require.NoError(t, err)
+
+		return &server{
+			engine:        engine,
+			defaultMut:    &sync.Mutex{},
+			defaultBinary: defaultBinary,
+			cachePath:     cachePath,
+			logger:        slogtest.Make(t, nil),
+		}, workdir
+	}
+
+	t.Run("Unconstrained", func(t *testing.T) {
+		t.Parallel()
+		srv, workdir := setup(t, EngineTerraform, "", "1.5.7")
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path, err := srv.resolveBinary(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, srv.defaultBinary, path)
+	})
+
+	t.Run("DefaultSatisfies", func(t *testing.T) {
+		t.Parallel()
+		srv, workdir := setup(t, EngineOpenTofu, "~> 1.6.0", "1.6.1")
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path, err := srv.resolveBinary(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, srv.defaultBinary, path)
+	})
+
+	t.Run("NewestCached", func(t *testing.T) {
+		t.Parallel()
+		srv, workdir := setup(t, EngineTerraform, ">= 1.4.0, < 1.6.0", "1.3.9", "1.4.6", "1.5.7", "1.7.0")
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path, err := srv.resolveBinary(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(versionsDir(srv.cachePath, EngineTerraform), "1.5.7", "terraform"), path)
+	})
+
+	t.Run("DownloadDisabled", func(t *testing.T) {
+		t.Parallel()
+		srv, workdir := setup(t, EngineTerraform, "~> 1.4.0", "1.5.7")
+		srv.disableEngineDownload = true
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := srv.resolveBinary(ctx, workdir, discardSink{})
+		require.ErrorContains(t, err, "downloading it is disabled")
+	})
+
+	t.Run("InvalidConstraint", func(t *testing.T) {
+		t.Parallel()
+		srv, workdir := setup(t, EngineTerraform, "not a version")
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := srv.resolveBinary(ctx, workdir, discardSink{})
+		require.ErrorContains(t, err, "parse required_version")
+	})
+}
+
+//nolint:paralleltest // Sets $PATH.
+func TestInstalledVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Dummy terraform executable on Windows requires sh which isn't very practical.")
+	}
+
+	cachePath := t.TempDir()
+	for _, v := range []string{"1.5.7", "1.6.2"} {
+		writeFakeEngine(t, filepath.Join(versionsDir(cachePath, EngineTerraform), v, "terraform"), v)
+	}
+	binDir := t.TempDir()
+	writeFakeEngine(t, filepath.Join(binDir, "terraform"), "1.6.2")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	versions, err := InstalledVersions(ctx, EngineTerraform, cachePath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.6.2", "1.5.7"}, versions)
+
+	// Without a binary on the $PATH, the default version is installed.
+	t.Setenv("PATH", t.TempDir())
+	versions, err = InstalledVersions(ctx, EngineOpenTofu, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, []string{OpenTofuVersion.String()}, versions)
+}
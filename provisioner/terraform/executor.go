@@ -153,11 +153,13 @@ func (e *executor) checkMinVersion(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if !v.GreaterThanOrEqual(minTerraformVersion) {
+	minVersion := e.server.engine.minVersion()
+	if !v.GreaterThanOrEqual(minVersion) {
 		return xerrors.Errorf(
-			"terraform version %q is too old. required >= %q",
+			"%s version %q is too old. required >= %q",
+			e.server.engine.command(),
 			v.String(),
-			minTerraformVersion.String())
+			minVersion.String())
 	}
 	return nil
 }
@@ -271,6 +273,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		Parameters:            state.Parameters,
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
+		Presets:               state.Presets,
 	}, nil
 }
 
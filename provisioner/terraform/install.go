@@ -1,9 +1,21 @@
 package terraform
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -21,16 +33,37 @@ var (
 	// NOTE: Keep this in sync with the version in scripts/Dockerfile.base.
 	// NOTE: Keep this in sync with the version in install.sh.
 	TerraformVersion = version.Must(version.NewVersion("1.6.6"))
+	// OpenTofuVersion is the version of OpenTofu used internally
+	// when OpenTofu is not available on the system.
+	OpenTofuVersion = version.Must(version.NewVersion("1.6.2"))
 
 	minTerraformVersion = version.Must(version.NewVersion("1.1.0"))
 	maxTerraformVersion = version.Must(version.NewVersion("1.6.9")) // use .9 to automatically allow patch releases
+	minOpenTofuVersion  = version.Must(version.NewVersion("1.6.0"))
 
 	terraformMinorVersionMismatch = xerrors.New("Terraform binary minor version mismatch.")
 )
 
+const (
+	openTofuReleasesURL = "https://github.com/opentofu/opentofu/releases/download"
+	openTofuVersionsURL = "https://get.opentofu.org/tofu/api.json"
+	// maxDownloadSize bounds the size of release archives we are willing to
+	// read into memory.
+	maxDownloadSize = 256 << 20
+)
+
 // Install implements a thread-safe, idempotent Terraform Install
 // operation.
 func Install(ctx context.Context, log slog.Logger, dir string, wantVersion *version.Version) (string, error) {
+	return InstallEngine(ctx, log, EngineTerraform, dir, wantVersion)
+}
+
+// InstallEngine implements a thread-safe, idempotent install of a version of
+// the engine into dir.
+func InstallEngine(ctx context.Context, log slog.Logger, engine Engine, dir string, wantVersion *version.Version) (string, error) {
+	if !engine.Valid() {
+		return "", xerrors.Errorf("unknown engine %q", engine)
+	}
 	err := os.MkdirAll(dir, 0o750)
 	if err != nil {
 		return "", err
@@ -47,28 +80,34 @@ func Install(ctx context.Context, log slog.Logger, dir string, wantVersion *vers
 	}
 	defer lock.Close()
 
-	binPath := filepath.Join(dir, product.Terraform.BinaryName())
+	binPath := filepath.Join(dir, engine.binaryName())
 
 	hasVersion, err := versionFromBinaryPath(ctx, binPath)
 	if err == nil && hasVersion.Equal(wantVersion) {
 		return binPath, err
 	}
 
-	installer := &releases.ExactVersion{
-		InstallDir: dir,
-		Product:    product.Terraform,
-		Version:    TerraformVersion,
-	}
-	installer.SetLogger(slog.Stdlib(ctx, log, slog.LevelDebug))
 	log.Debug(
 		ctx,
-		"installing terraform",
+		"installing "+engine.command(),
 		slog.F("prev_version", hasVersion),
 		slog.F("dir", dir),
-		slog.F("version", TerraformVersion),
+		slog.F("version", wantVersion),
 	)
 
-	path, err := installer.Install(ctx)
+	var path string
+	switch engine {
+	case EngineOpenTofu:
+		path, err = installOpenTofu(ctx, dir, wantVersion)
+	default:
+		installer := &releases.ExactVersion{
+			InstallDir: dir,
+			Product:    product.Terraform,
+			Version:    wantVersion,
+		}
+		installer.SetLogger(slog.Stdlib(ctx, log, slog.LevelDebug))
+		path, err = installer.Install(ctx)
+	}
 	if err != nil {
 		return "", xerrors.Errorf("install: %w", err)
 	}
@@ -81,3 +120,156 @@ func Install(ctx context.Context, log slog.Logger, dir string, wantVersion *vers
 
 	return path, nil
 }
+
+// latestVersion returns the newest stable release of the engine that
+// satisfies the constraints.
+func latestVersion(ctx context.Context, engine Engine, constraints version.Constraints) (*version.Version, error) {
+	var available []*version.Version
+	switch engine {
+	case EngineOpenTofu:
+		body, err := download(ctx, openTofuVersionsURL)
+		if err != nil {
+			return nil, xerrors.Errorf("list OpenTofu versions: %w", err)
+		}
+		var index struct {
+			Versions []struct {
+				ID string `json:"id"`
+			} `json:"versions"`
+		}
+		err = json.Unmarshal(body, &index)
+		if err != nil {
+			return nil, xerrors.Errorf("decode OpenTofu versions: %w", err)
+		}
+		for _, v := range index.Versions {
+			parsed, err := version.NewVersion(v.ID)
+			if err != nil {
+				continue
+			}
+			available = append(available, parsed)
+		}
+	default:
+		sources, err := (&releases.Versions{
+			Product:     product.Terraform,
+			Constraints: constraints,
+		}).List(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("list Terraform versions: %w", err)
+		}
+		for _, source := range sources {
+			if ev, ok := source.(*releases.ExactVersion); ok {
+				available = append(available, ev.Version)
+			}
+		}
+	}
+
+	var matching []*version.Version
+	for _, v := range available {
+		if v.Prerelease() != "" || !constraints.Check(v) {
+			continue
+		}
+		matching = append(matching, v)
+	}
+	if len(matching) == 0 {
+		return nil, xerrors.Errorf("no %s release satisfies %q", engine.displayName(), constraints.String())
+	}
+	sort.Sort(version.Collection(matching))
+	return matching[len(matching)-1], nil
+}
+
+// installOpenTofu downloads the OpenTofu release archive for the current
+// platform, verifies it against the published checksums and extracts the
+// binary into dir.
+func installOpenTofu(ctx context.Context, dir string, v *version.Version) (string, error) {
+	archiveName := fmt.Sprintf("tofu_%s_%s_%s.zip", v.String(), runtime.GOOS, runtime.GOARCH)
+	baseURL := fmt.Sprintf("%s/v%s/", openTofuReleasesURL, v.String())
+
+	sums, err := download(ctx, baseURL+fmt.Sprintf("tofu_%s_SHA256SUMS", v.String()))
+	if err != nil {
+		return "", xerrors.Errorf("download checksums: %w", err)
+	}
+	var wantSum string
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == archiveName {
+			wantSum = fields[0]
+			break
+		}
+	}
+	if wantSum == "" {
+		return "", xerrors.Errorf("no checksum published for %s", archiveName)
+	}
+
+	archive, err := download(ctx, baseURL+archiveName)
+	if err != nil {
+		return "", xerrors.Errorf("download %s: %w", archiveName, err)
+	}
+	gotSum := sha256.Sum256(archive)
+	if hex.EncodeToString(gotSum[:]) != wantSum {
+		return "", xerrors.Errorf("checksum mismatch for %s", archiveName)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return "", xerrors.Errorf("open %s: %w", archiveName, err)
+	}
+	binName := EngineOpenTofu.binaryName()
+	for _, file := range zr.File {
+		if file.Name != binName {
+			continue
+		}
+		binPath := filepath.Join(dir, binName)
+		err = extractFile(file, binPath)
+		if err != nil {
+			return "", xerrors.Errorf("extract %s: %w", binName, err)
+		}
+		return binPath, nil
+	}
+	return "", xerrors.Errorf("%s not found in %s", binName, archiveName)
+}
+
+// extractFile writes the zipped file to path, replacing any existing file
+// atomically.
+func extractFile(file *zip.File, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, io.LimitReader(src, maxDownloadSize))
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0o755) // #nosec
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("GET %s: unexpected status %s", url, res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxDownloadSize))
+}
@@ -0,0 +1,406 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisioner"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// Codes of the problems found by Lint.
+const (
+	LintSyntaxError             = "syntax-error"
+	LintAgentNotAttached        = "agent-not-attached"
+	LintAgentNotStarted         = "agent-not-started"
+	LintUnknownAgent            = "unknown-agent"
+	LintInvalidAppSlug          = "invalid-app-slug"
+	LintDuplicateAppSlug        = "duplicate-app-slug"
+	LintDuplicateParameterName  = "duplicate-parameter-name"
+	LintInvalidValidationRegex  = "invalid-validation-regex"
+	LintParameterDefaultInvalid = "parameter-default-not-an-option"
+)
+
+var lintFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+	},
+}
+
+var lintBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "agent_id"},
+		{Name: "count"},
+		{Name: "default"},
+		{Name: "for_each"},
+		{Name: "name"},
+		{Name: "slug"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "option"},
+		{Type: "validation"},
+	},
+}
+
+var lintOptionSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "value"}},
+}
+
+var lintValidationSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "regex"}},
+}
+
+// lintBlock is a resource or data source of the root module.
+type lintBlock struct {
+	mode    string
+	typ     string
+	name    string
+	rng     hcl.Range
+	content *hcl.BodyContent
+	// repeated is true if the block has count or for_each, so that static
+	// values may be repeated or omitted.
+	repeated bool
+}
+
+func (b *lintBlock) address() string {
+	if b.mode == "data" {
+		return "data." + b.typ + "." + b.name
+	}
+	return b.typ + "." + b.name
+}
+
+// Lint statically checks the root module of the template in dir for mistakes
+// that would otherwise only surface when a workspace is built. Only values
+// that are known without evaluating the configuration are checked.
+func Lint(dir string) ([]*proto.Diagnostic, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("read template directory: %w", err)
+	}
+	var (
+		parser      = hclparse.NewParser()
+		diagnostics []*proto.Diagnostic
+		blocks      []*lintBlock
+		// references holds the coder_agent references of blocks that can
+		// run an agent, by agent name and referenced attribute. The whole
+		// agent is referenced by an empty attribute.
+		references = map[string]map[string]bool{}
+		// Syntax other than HCL can not be walked for references.
+		walkable = true
+	)
+	diagnose := func(severity proto.Diagnostic_Severity, code string, rng hcl.Range, summary, detail string) {
+		filename, err := filepath.Rel(dir, rng.Filename)
+		if err != nil {
+			filename = rng.Filename
+		}
+		diagnostics = append(diagnostics, &proto.Diagnostic{
+			Severity: severity,
+			Code:     code,
+			Summary:  summary,
+			Detail:   detail,
+			Filename: filename,
+			Line:     int32(rng.Start.Line),
+		})
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		var (
+			file  *hcl.File
+			diags hcl.Diagnostics
+		)
+		switch {
+		case strings.HasSuffix(name, ".tf"):
+			file, diags = parser.ParseHCLFile(path)
+		case strings.HasSuffix(name, ".tf.json"):
+			file, diags = parser.ParseJSONFile(path)
+			walkable = false
+		default:
+			continue
+		}
+		if diags.HasErrors() {
+			for _, diag := range diags {
+				rng := hcl.Range{Filename: path}
+				if diag.Subject != nil {
+					rng = *diag.Subject
+				}
+				diagnose(proto.Diagnostic_ERROR, LintSyntaxError, rng, diag.Summary, diag.Detail)
+			}
+			continue
+		}
+
+		content, _, _ := file.Body.PartialContent(lintFileSchema)
+		for _, block := range content.Blocks {
+			blockContent, _, _ := block.Body.PartialContent(lintBlockSchema)
+			b := &lintBlock{
+				mode:    block.Type,
+				typ:     block.Labels[0],
+				name:    block.Labels[1],
+				rng:     block.DefRange,
+				content: blockContent,
+			}
+			_, hasCount := blockContent.Attributes["count"]
+			_, hasForEach := blockContent.Attributes["for_each"]
+			b.repeated = hasCount || hasForEach
+			blocks = append(blocks, b)
+		}
+
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			// Coder resources reference agents without running them.
+			if (block.Type == "resource" || block.Type == "data") && len(block.Labels) > 0 && strings.HasPrefix(block.Labels[0], "coder_") {
+				continue
+			}
+			_ = hclsyntax.VisitAll(block.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+				expr, ok := node.(hclsyntax.Expression)
+				if !ok {
+					return nil
+				}
+				for _, traversal := range expr.Variables() {
+					agent, attribute, ok := agentReference(traversal)
+					if !ok {
+						continue
+					}
+					// Modules commonly take the agent ID to add apps, which
+					// does not run the agent.
+					if block.Type == "module" && attribute != "init_script" && attribute != "token" {
+						continue
+					}
+					if references[agent] == nil {
+						references[agent] = map[string]bool{}
+					}
+					references[agent][attribute] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	agents := map[string]bool{}
+	for _, b := range blocks {
+		if b.mode == "resource" && b.typ == "coder_agent" {
+			agents[b.name] = true
+		}
+	}
+
+	appSlugs := map[string]*lintBlock{}
+	parameterNames := map[string]*lintBlock{}
+	for _, b := range blocks {
+		switch {
+		case b.mode == "resource" && b.typ == "coder_agent" && walkable:
+			refs := references[b.name]
+			if len(refs) == 0 {
+				diagnose(proto.Diagnostic_WARNING, LintAgentNotAttached, b.rng,
+					fmt.Sprintf("%s is not used by any resource", b.address()),
+					"The agent and its apps are only shown for the resource that references it. Pass its init_script or token to the resource that runs the agent.")
+			} else if !refs[""] && !refs["init_script"] && !refs["token"] {
+				diagnose(proto.Diagnostic_WARNING, LintAgentNotStarted, b.rng,
+					fmt.Sprintf("%s is never started", b.address()),
+					"Neither the init_script nor the token of the agent is referenced, so the agent can not connect. Run its init_script in the resource that runs the agent.")
+			}
+
+		case b.mode == "resource" && (b.typ == "coder_app" || b.typ == "coder_script" || b.typ == "coder_env" || b.typ == "coder_agent_instance"):
+			if attr, ok := b.content.Attributes["agent_id"]; ok && walkable {
+				for _, traversal := range attr.Expr.Variables() {
+					agent, _, ok := agentReference(traversal)
+					if ok && !agents[agent] {
+						diagnose(proto.Diagnostic_ERROR, LintUnknownAgent, attr.Range,
+							fmt.Sprintf("%s refers to coder_agent.%s, which is not declared", b.address(), agent),
+							"Declare the agent in the template, or refer to an existing coder_agent.")
+					}
+				}
+			}
+			if b.typ != "coder_app" {
+				continue
+			}
+			attr, ok := b.content.Attributes["slug"]
+			if !ok {
+				continue
+			}
+			slug, ok := literalValue(attr.Expr)
+			if !ok {
+				continue
+			}
+			if !provisioner.AppSlugRegex.MatchString(slug) {
+				diagnose(proto.Diagnostic_ERROR, LintInvalidAppSlug, attr.Range,
+					fmt.Sprintf("%s has an invalid slug %q", b.address(), slug),
+					"Slugs must be lowercase letters, numbers and single hyphens, and must not start or end with a hyphen.")
+				continue
+			}
+			if other, ok := appSlugs[slug]; ok {
+				diagnoseDuplicate(diagnose, LintDuplicateAppSlug, attr.Range, b, other,
+					fmt.Sprintf("%s has the same slug %q as %s", b.address(), slug, other.address()),
+					"App slugs must be unique in a template.")
+				continue
+			}
+			appSlugs[slug] = b
+
+		case b.mode == "data" && b.typ == "coder_parameter":
+			lintParameter(b, parameterNames, diagnose)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Filename != diagnostics[j].Filename {
+			return diagnostics[i].Filename < diagnostics[j].Filename
+		}
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+	return diagnostics, nil
+}
+
+type diagnoseFunc func(severity proto.Diagnostic_Severity, code string, rng hcl.Range, summary, detail string)
+
+// diagnoseDuplicate reports a duplicate value. Blocks with count or for_each
+// may never exist at the same time, so they are only warned about.
+func diagnoseDuplicate(diagnose diagnoseFunc, code string, rng hcl.Range, b, other *lintBlock, summary, detail string) {
+	severity := proto.Diagnostic_ERROR
+	if b.repeated || other.repeated {
+		severity = proto.Diagnostic_WARNING
+		detail += " This is only valid if the blocks are never created together."
+	}
+	diagnose(severity, code, rng, summary, detail)
+}
+
+func lintParameter(b *lintBlock, names map[string]*lintBlock, diagnose diagnoseFunc) {
+	if attr, ok := b.content.Attributes["name"]; ok {
+		if name, ok := literalValue(attr.Expr); ok {
+			if other, ok := names[name]; ok {
+				diagnoseDuplicate(diagnose, LintDuplicateParameterName, attr.Range, b, other,
+					fmt.Sprintf("%s has the same name %q as %s", b.address(), name, other.address()),
+					"Parameter names must be unique in a template.")
+			} else {
+				names[name] = b
+			}
+		}
+	}
+
+	var (
+		options      []string
+		knownOptions = true
+	)
+	for _, block := range b.content.Blocks {
+		switch block.Type {
+		case "option":
+			content, _, _ := block.Body.PartialContent(lintOptionSchema)
+			value, ok := "", false
+			if attr, exists := content.Attributes["value"]; exists {
+				value, ok = literalValue(attr.Expr)
+			}
+			if !ok {
+				knownOptions = false
+				continue
+			}
+			options = append(options, value)
+		case "validation":
+			content, _, _ := block.Body.PartialContent(lintValidationSchema)
+			attr, ok := content.Attributes["regex"]
+			if !ok {
+				continue
+			}
+			regex, ok := literalValue(attr.Expr)
+			if !ok {
+				continue
+			}
+			if _, err := regexp.Compile(regex); err != nil {
+				diagnose(proto.Diagnostic_ERROR, LintInvalidValidationRegex, attr.Range,
+					fmt.Sprintf("%s has an invalid validation regex", b.address()),
+					err.Error())
+			}
+		}
+	}
+
+	attr, ok := b.content.Attributes["default"]
+	if !ok || len(options) == 0 || !knownOptions {
+		return
+	}
+	value, ok := literalValue(attr.Expr)
+	if !ok {
+		return
+	}
+	for _, option := range options {
+		if option == value {
+			return
+		}
+	}
+	diagnose(proto.Diagnostic_ERROR, LintParameterDefaultInvalid, attr.Range,
+		fmt.Sprintf("%s has a default %q that is not one of its options", b.address(), value),
+		"The default value of a parameter with options must be the value of one of them.")
+}
+
+// agentReference returns the name and referenced attribute of a reference
+// to a coder_agent.
+func agentReference(traversal hcl.Traversal) (name, attribute string, ok bool) {
+	if traversal.RootName() != "coder_agent" || len(traversal) < 2 {
+		return "", "", false
+	}
+	nameStep, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", "", false
+	}
+	if len(traversal) > 2 {
+		// Skip the index of agents with count or for_each.
+		next := traversal[2:]
+		if _, isIndex := next[0].(hcl.TraverseIndex); isIndex {
+			next = next[1:]
+		}
+		if len(next) > 0 {
+			if step, isAttr := next[0].(hcl.TraverseAttr); isAttr {
+				attribute = step.Name
+			}
+		}
+	}
+	return nameStep.Name, attribute, true
+}
+
+// literalValue returns the value of an expression that can be evaluated
+// without any variables, as it would be converted to a string.
+func literalValue(expr hcl.Expression) (string, bool) {
+	if len(expr.Variables()) > 0 {
+		return "", false
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return "", false
+	}
+	switch value.Type() {
+	case cty.String:
+		return value.AsString(), true
+	case cty.Number:
+		return value.AsBigFloat().Text('f', -1), true
+	case cty.Bool:
+		if value.True() {
+			return "true", true
+		}
+		return "false", true
+	default:
+		return "", false
+	}
+}
+
+// FormatLintDiagnostic formats a diagnostic found by Lint on a single line.
+func FormatLintDiagnostic(d *proto.Diagnostic) string {
+	severity := "WARN"
+	if d.Severity == proto.Diagnostic_ERROR {
+		severity = "ERROR"
+	}
+	return fmt.Sprintf("%s: %s (%s:%d)", severity, d.Summary, d.Filename, d.Line)
+}
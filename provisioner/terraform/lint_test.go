@@ -0,0 +1,251 @@
+package terraform_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	type diagnostic struct {
+		Severity proto.Diagnostic_Severity
+		Code     string
+		Filename string
+		Line     int32
+	}
+
+	testCases := []struct {
+		Name     string
+		Files    map[string]string
+		Expected []diagnostic
+	}{
+		{
+			Name: "valid",
+			Files: map[string]string{
+				"main.tf": `resource "coder_agent" "dev" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+  slug     = "code-server"
+}
+
+data "coder_parameter" "region" {
+  name    = "region"
+  default = "eu"
+  option {
+    name  = "Europe"
+    value = "eu"
+  }
+  validation {
+    regex = "^[a-z]+$"
+  }
+}
+
+resource "docker_container" "dev" {
+  entrypoint = ["sh", "-c", coder_agent.dev.init_script]
+}`,
+			},
+		},
+		{
+			Name: "syntax-error",
+			Files: map[string]string{
+				"main.tf": "resource \"coder_agent\" {\n",
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_ERROR, terraform.LintSyntaxError, "main.tf", 1},
+			},
+		},
+		{
+			Name: "agent-not-attached",
+			Files: map[string]string{
+				"main.tf": `resource "coder_agent" "dev" {
+  os   = "linux"
+  arch = "amd64"
+}`,
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_WARNING, terraform.LintAgentNotAttached, "main.tf", 1},
+			},
+		},
+		{
+			Name: "agent-not-started",
+			Files: map[string]string{
+				"main.tf": `resource "coder_agent" "dev" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+resource "docker_container" "dev" {
+  labels = { agent = coder_agent.dev.id }
+}`,
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_WARNING, terraform.LintAgentNotStarted, "main.tf", 1},
+			},
+		},
+		{
+			Name: "agent-used-by-module",
+			Files: map[string]string{
+				"main.tf": `resource "coder_agent" "dev" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+module "code-server" {
+  source   = "registry.coder.com/modules/code-server/coder"
+  agent_id = coder_agent.dev.id
+}
+
+module "vm" {
+  source    = "./vm"
+  user_data = coder_agent.dev.init_script
+}`,
+			},
+		},
+		{
+			Name: "agent-with-count",
+			Files: map[string]string{
+				"main.tf": `resource "coder_agent" "dev" {
+  count = 1
+  os    = "linux"
+  arch  = "amd64"
+}
+
+resource "docker_container" "dev" {
+  count = 1
+  env   = ["CODER_AGENT_TOKEN=${coder_agent.dev[0].token}"]
+}`,
+			},
+		},
+		{
+			Name: "unknown-agent",
+			Files: map[string]string{
+				"main.tf": `resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+  slug     = "code"
+}`,
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_ERROR, terraform.LintUnknownAgent, "main.tf", 2},
+			},
+		},
+		{
+			Name: "app-slugs",
+			Files: map[string]string{
+				"apps.tf": `resource "coder_app" "a" {
+  slug = "code"
+}
+
+resource "coder_app" "b" {
+  slug = "code"
+}
+
+resource "coder_app" "c" {
+  count = 1
+  slug  = "code"
+}
+
+resource "coder_app" "d" {
+  slug = "Not_Valid"
+}
+
+resource "coder_app" "e" {
+  slug = var.slug
+}
+
+resource "coder_app" "f" {
+  slug = var.slug
+}`,
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_ERROR, terraform.LintDuplicateAppSlug, "apps.tf", 6},
+				{proto.Diagnostic_WARNING, terraform.LintDuplicateAppSlug, "apps.tf", 11},
+				{proto.Diagnostic_ERROR, terraform.LintInvalidAppSlug, "apps.tf", 15},
+			},
+		},
+		{
+			Name: "parameters",
+			Files: map[string]string{
+				"a.tf": `data "coder_parameter" "a" {
+  name = "region"
+}`,
+				"b.tf": `data "coder_parameter" "b" {
+  name    = "region"
+  default = "us"
+  option {
+    name  = "Europe"
+    value = "eu"
+  }
+}
+
+data "coder_parameter" "c" {
+  name = "size"
+  validation {
+    regex = "^[a-z"
+  }
+}
+
+data "coder_parameter" "d" {
+  name    = "cpu"
+  default = 2
+  option {
+    name  = "Two"
+    value = 2
+  }
+}
+
+data "coder_parameter" "e" {
+  name    = "image"
+  default = "ubuntu"
+  option {
+    name  = "Image"
+    value = var.image
+  }
+}`,
+			},
+			Expected: []diagnostic{
+				{proto.Diagnostic_ERROR, terraform.LintDuplicateParameterName, "b.tf", 2},
+				{proto.Diagnostic_ERROR, terraform.LintParameterDefaultInvalid, "b.tf", 3},
+				{proto.Diagnostic_ERROR, terraform.LintInvalidValidationRegex, "b.tf", 13},
+			},
+		},
+		{
+			Name: "json",
+			Files: map[string]string{
+				"main.tf.json": `{"resource": {"coder_agent": {"dev": {"os": "linux", "arch": "amd64"}}}}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range testCase.Files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			}
+
+			diagnostics, err := terraform.Lint(dir)
+			require.NoError(t, err)
+
+			got := make([]diagnostic, 0, len(diagnostics))
+			for _, d := range diagnostics {
+				require.NotEmpty(t, d.Summary)
+				got = append(got, diagnostic{d.Severity, d.Code, d.Filename, d.Line})
+			}
+			require.ElementsMatch(t, testCase.Expected, got)
+		})
+	}
+}
@@ -0,0 +1,658 @@
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	// moduleCacheDirName is the directory of the module cache when it is
+	// placed in a plugin cache directory. Provider hostnames always contain
+	// a dot, so it cannot collide with a plugin.
+	moduleCacheDirName = "modules"
+	// moduleManifestName is the file where Terraform records the modules it
+	// installed into .terraform/modules.
+	moduleManifestName = "modules.json"
+	moduleEntryName    = "module.json"
+	modulePackageName  = "package"
+)
+
+// DefaultModuleCacheSize is the size the module cache is bounded by if none
+// is specified.
+const DefaultModuleCacheSize int64 = 1 << 30
+
+// ModuleCache is a cache of remote modules shared by provisioner sessions, so
+// that "terraform init" only downloads modules it has not seen before.
+//
+// Modules are addressed by the source and version of the module call that
+// installed them. Only calls that pin an exact version, commit or tag are
+// cached, since the module a version range, branch or plain source resolves
+// to can change between builds.
+//
+// The cache may be shared by multiple processes. Once it grows beyond its
+// maximum size, the least recently used modules are evicted.
+type ModuleCache struct {
+	dir     string
+	maxSize int64
+	logger  slog.Logger
+	metrics moduleCacheMetrics
+}
+
+type moduleCacheMetrics struct {
+	hits      prometheus.Counter
+	misses    prometheus.Counter
+	evictions prometheus.Counter
+	size      prometheus.Gauge
+}
+
+// NewModuleCache creates a module cache in dir. If maxSize is zero,
+// DefaultModuleCacheSize is used. Metrics are registered with reg, if set.
+func NewModuleCache(dir string, maxSize int64, logger slog.Logger, reg prometheus.Registerer) (*ModuleCache, error) {
+	if maxSize == 0 {
+		maxSize = DefaultModuleCacheSize
+	}
+	if maxSize < 0 {
+		return nil, xerrors.Errorf("invalid module cache size %d", maxSize)
+	}
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("mkdir %q: %w", dir, err)
+	}
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+	auto := promauto.With(reg)
+	c := &ModuleCache{
+		dir:     dir,
+		maxSize: maxSize,
+		logger:  logger,
+		metrics: moduleCacheMetrics{
+			hits: auto.NewCounter(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "provisionerd",
+				Name:      "module_cache_hits_total",
+				Help:      "The number of modules restored from the module cache.",
+			}),
+			misses: auto.NewCounter(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "provisionerd",
+				Name:      "module_cache_misses_total",
+				Help:      "The number of cacheable modules that had to be downloaded.",
+			}),
+			evictions: auto.NewCounter(prometheus.CounterOpts{
+				Namespace: "coderd",
+				Subsystem: "provisionerd",
+				Name:      "module_cache_evictions_total",
+				Help:      "The number of modules evicted from the module cache.",
+			}),
+			size: auto.NewGauge(prometheus.GaugeOpts{
+				Namespace: "coderd",
+				Subsystem: "provisionerd",
+				Name:      "module_cache_size_bytes",
+				Help:      "The size of the modules in the module cache.",
+			}),
+		},
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.size.Set(float64(entries.size()))
+	return c, nil
+}
+
+// moduleManifest is the format of .terraform/modules/modules.json.
+type moduleManifest struct {
+	Modules []moduleManifestRecord `json:"Modules"`
+}
+
+type moduleManifestRecord struct {
+	Key     string `json:"Key"`
+	Source  string `json:"Source"`
+	Version string `json:"Version,omitempty"`
+	Dir     string `json:"Dir"`
+}
+
+// moduleCacheEntry describes a cached module package.
+type moduleCacheEntry struct {
+	// Source and Version are as recorded in the module manifest by
+	// Terraform, which normalizes them.
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+	// Dir is the directory of the module relative to the package, which is
+	// not the package itself for sources with a subdirectory.
+	Dir  string `json:"dir"`
+	Size int64  `json:"size"`
+
+	key     string
+	lastUse time.Time
+}
+
+type moduleCacheEntries []moduleCacheEntry
+
+func (e moduleCacheEntries) size() int64 {
+	var size int64
+	for _, entry := range e {
+		size += entry.Size
+	}
+	return size
+}
+
+// moduleCacheKey addresses the package installed for a module call.
+func moduleCacheKey(call *tfconfig.ModuleCall) string {
+	sum := sha256.Sum256([]byte(call.Source + "\n" + exactModuleVersion(call.Version)))
+	return hex.EncodeToString(sum[:])
+}
+
+// exactModuleVersion returns the version a module version constraint pins, or
+// an empty string if it allows more than one version.
+func exactModuleVersion(constraint string) string {
+	v, err := version.NewVersion(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(constraint), "=")))
+	if err != nil {
+		return ""
+	}
+	return v.String()
+}
+
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") ||
+		strings.HasPrefix(source, ".\\") || strings.HasPrefix(source, "..\\")
+}
+
+// pinnedRefRegex matches the refs that identify a single revision: full
+// commit SHA-1 or SHA-256 hashes, and semantic version tags.
+var pinnedRefRegex = regexp.MustCompile(`^([0-9a-fA-F]{40}|[0-9a-fA-F]{64}|v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)$`)
+
+// moduleSourceRef returns the value of the ref query parameter of a remote
+// module source, or an empty string if it has none.
+func moduleSourceRef(source string) string {
+	_, query, ok := strings.Cut(source, "?")
+	if !ok {
+		return ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get("ref")
+}
+
+// isCacheableModuleCall returns whether the call pins the module it installs.
+// Registry modules need an exact version, and other remote sources a ref that
+// is a full commit hash or a semantic version tag. Branches and abbreviated
+// hashes can resolve to different commits between builds.
+func isCacheableModuleCall(call *tfconfig.ModuleCall) bool {
+	if isLocalModuleSource(call.Source) {
+		return false
+	}
+	if call.Version != "" {
+		return exactModuleVersion(call.Version) != ""
+	}
+	return pinnedRefRegex.MatchString(moduleSourceRef(call.Source))
+}
+
+// moduleCalls returns the module calls of the module in dir, ordered by name.
+func moduleCalls(dir string) ([]*tfconfig.ModuleCall, error) {
+	module, diags := tfconfig.LoadModule(dir)
+	if diags.HasErrors() {
+		return nil, xerrors.Errorf("load module: %s", formatDiagnostics(dir, diags))
+	}
+	calls := make([]*tfconfig.ModuleCall, 0, len(module.ModuleCalls))
+	for _, call := range module.ModuleCalls {
+		calls = append(calls, call)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Name < calls[j].Name
+	})
+	return calls, nil
+}
+
+func moduleKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func modulesDir(workdir string) string {
+	return filepath.Join(workdir, ".terraform", "modules")
+}
+
+// modulesInstalled returns whether modules were installed in workdir.
+func modulesInstalled(workdir string) bool {
+	_, err := os.Stat(filepath.Join(modulesDir(workdir), moduleManifestName))
+	return err == nil
+}
+
+// restore installs the cached modules called by the configuration in workdir
+// and records them in the module manifest, which "terraform init" trusts to
+// skip downloading them. Nothing is restored if modules were already
+// installed.
+func (c *ModuleCache) restore(ctx context.Context, workdir string, logr logSink) error {
+	if modulesInstalled(workdir) {
+		return nil
+	}
+
+	lock, err := c.lock(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	var (
+		records []moduleManifestRecord
+		hits    int
+		misses  int
+	)
+	var walk func(dir, parent string) error
+	walk = func(dir, parent string) error {
+		calls, err := moduleCalls(dir)
+		if err != nil {
+			return err
+		}
+		for _, call := range calls {
+			key := moduleKey(parent, call.Name)
+			if isLocalModuleSource(call.Source) {
+				err = walk(filepath.Join(dir, call.Source), key)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if !isCacheableModuleCall(call) {
+				continue
+			}
+			entry, ok, err := c.entry(moduleCacheKey(call))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				misses++
+				continue
+			}
+			packageDir := filepath.Join(modulesDir(workdir), key)
+			err = copyDir(filepath.Join(c.dir, entry.key, modulePackageName), packageDir)
+			if err != nil {
+				return xerrors.Errorf("restore module %q: %w", key, err)
+			}
+			// Refresh the entry to record the use for eviction.
+			now := time.Now()
+			_ = os.Chtimes(filepath.Join(c.dir, entry.key, moduleEntryName), now, now)
+
+			moduleDir := filepath.Join(packageDir, entry.Dir)
+			relDir, err := filepath.Rel(workdir, moduleDir)
+			if err != nil {
+				return err
+			}
+			records = append(records, moduleManifestRecord{
+				Key:     key,
+				Source:  entry.Source,
+				Version: entry.Version,
+				Dir:     filepath.ToSlash(relDir),
+			})
+			hits++
+			err = walk(moduleDir, key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = walk(workdir, "")
+	c.metrics.hits.Add(float64(hits))
+	c.metrics.misses.Add(float64(misses))
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(moduleManifest{Modules: records})
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(modulesDir(workdir), moduleManifestName), data, 0o600)
+	if err != nil {
+		return xerrors.Errorf("write module manifest: %w", err)
+	}
+	logr.ProvisionLog(proto.LogLevel_DEBUG, fmt.Sprintf("Restored %d module(s) from cache", hits))
+	c.logger.Debug(ctx, "restored modules from cache", slog.F("hits", hits), slog.F("misses", misses))
+	return nil
+}
+
+// store adds the cacheable modules that "terraform init" installed in workdir
+// to the cache, and evicts the least recently used modules if the cache grew
+// beyond its maximum size. It must not be called if modules were installed
+// before "terraform init" ran, since they are not what the calls resolve to.
+func (c *ModuleCache) store(ctx context.Context, workdir string) error {
+	data, err := os.ReadFile(filepath.Join(modulesDir(workdir), moduleManifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The configuration does not call any modules.
+			return nil
+		}
+		return xerrors.Errorf("read module manifest: %w", err)
+	}
+	var manifest moduleManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return xerrors.Errorf("decode module manifest: %w", err)
+	}
+	records := make(map[string]moduleManifestRecord, len(manifest.Modules))
+	for _, record := range manifest.Modules {
+		records[record.Key] = record
+	}
+
+	type candidate struct {
+		key    string
+		call   *tfconfig.ModuleCall
+		record moduleManifestRecord
+	}
+	var candidates []candidate
+	var walk func(dir, parent string) error
+	walk = func(dir, parent string) error {
+		calls, err := moduleCalls(dir)
+		if err != nil {
+			return err
+		}
+		for _, call := range calls {
+			key := moduleKey(parent, call.Name)
+			record, ok := records[key]
+			if !ok {
+				continue
+			}
+			if isCacheableModuleCall(call) {
+				candidates = append(candidates, candidate{key: key, call: call, record: record})
+			}
+			err = walk(filepath.Join(workdir, filepath.FromSlash(record.Dir)), key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = walk(workdir, "")
+	if err != nil {
+		return err
+	}
+
+	var stored int
+	for _, cand := range candidates {
+		cacheKey := moduleCacheKey(cand.call)
+		_, ok, err := c.entry(cacheKey)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		packageDir := filepath.Join(modulesDir(workdir), cand.key)
+		relDir, err := filepath.Rel(packageDir, filepath.Join(workdir, filepath.FromSlash(cand.record.Dir)))
+		if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+			// The module was not installed as its own package, which is
+			// only expected for local sources.
+			continue
+		}
+		err = c.add(ctx, cacheKey, packageDir, moduleCacheEntry{
+			Source:  cand.record.Source,
+			Version: cand.record.Version,
+			Dir:     relDir,
+		})
+		if err != nil {
+			return xerrors.Errorf("cache module %q: %w", cand.key, err)
+		}
+		stored++
+	}
+	if stored == 0 {
+		return nil
+	}
+	c.logger.Debug(ctx, "stored modules in cache", slog.F("count", stored))
+	return c.evict(ctx)
+}
+
+// add copies the package into the cache under key.
+func (c *ModuleCache) add(ctx context.Context, key, packageDir string, entry moduleCacheEntry) error {
+	// Copy outside of the lock, so sessions restoring modules are not blocked
+	// on large packages.
+	tmpDir, err := os.MkdirTemp(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	err = copyDir(packageDir, filepath.Join(tmpDir, modulePackageName))
+	if err != nil {
+		return err
+	}
+	entry.Size, err = dirSize(filepath.Join(tmpDir, modulePackageName))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(tmpDir, moduleEntryName), data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	lock, err := c.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	err = os.Rename(tmpDir, filepath.Join(c.dir, key))
+	if err != nil {
+		if _, statErr := os.Stat(filepath.Join(c.dir, key, moduleEntryName)); statErr == nil {
+			// Another session stored the module first.
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// evict removes the least recently used modules until the cache fits its
+// maximum size.
+func (c *ModuleCache) evict(ctx context.Context) error {
+	lock, err := c.lock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	// Packages are copied into temporary directories before they are
+	// added, which remain if the process is killed meanwhile.
+	tmpDirs, err := filepath.Glob(filepath.Join(c.dir, ".tmp-*"))
+	if err != nil {
+		return err
+	}
+	for _, tmpDir := range tmpDirs {
+		info, err := os.Stat(tmpDir)
+		if err == nil && time.Since(info.ModTime()) > time.Hour {
+			_ = os.RemoveAll(tmpDir)
+		}
+	}
+
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUse.Before(entries[j].lastUse)
+	})
+	size := entries.size()
+	for _, entry := range entries {
+		if size <= c.maxSize {
+			break
+		}
+		err = os.RemoveAll(filepath.Join(c.dir, entry.key))
+		if err != nil {
+			return xerrors.Errorf("evict module %q: %w", entry.Source, err)
+		}
+		c.logger.Debug(ctx, "evicted module from cache",
+			slog.F("source", entry.Source),
+			slog.F("version", entry.Version),
+			slog.F("last_use", entry.lastUse),
+		)
+		c.metrics.evictions.Inc()
+		size -= entry.Size
+	}
+	c.metrics.size.Set(float64(size))
+	return nil
+}
+
+func (c *ModuleCache) entry(key string) (moduleCacheEntry, bool, error) {
+	path := filepath.Join(c.dir, key, moduleEntryName)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return moduleCacheEntry{}, false, nil
+		}
+		return moduleCacheEntry{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return moduleCacheEntry{}, false, err
+	}
+	var entry moduleCacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return moduleCacheEntry{}, false, xerrors.Errorf("decode %q: %w", path, err)
+	}
+	entry.key = key
+	entry.lastUse = info.ModTime()
+	return entry, true, nil
+}
+
+func (c *ModuleCache) entries() (moduleCacheEntries, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, xerrors.Errorf("read module cache: %w", err)
+	}
+	var entries moduleCacheEntries
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+		entry, ok, err := c.entry(dirEntry.Name())
+		if err != nil || !ok {
+			// Entries are written atomically, so this is not expected
+			// unless the cache was modified by hand.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// lock acquires the cache lock, which guards the cache against concurrent use
+// by other processes.
+func (c *ModuleCache) lock(ctx context.Context, exclusive bool) (*flock.Flock, error) {
+	lock := flock.New(filepath.Join(c.dir, "lock"))
+	var (
+		ok  bool
+		err error
+	)
+	if exclusive {
+		ok, err = lock.TryLockContext(ctx, 100*time.Millisecond)
+	} else {
+		ok, err = lock.TryRLockContext(ctx, 100*time.Millisecond)
+	}
+	if !ok {
+		return nil, xerrors.Errorf("could not acquire flock for module cache: %w", err)
+	}
+	return lock, nil
+}
+
+// copyDir copies the directory tree at src to dst, preserving file modes and
+// symlinks.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,237 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	ptestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+const moduleCacheTestConfig = `
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}
+
+module "unpinned" {
+  source = "hashicorp/vault/aws"
+}
+
+module "local" {
+  source = "./local"
+}
+`
+
+const moduleCacheTestLocalConfig = `
+module "network" {
+  source = "git::https://example.com/network.git//modules/vpc?ref=v1.2.0"
+}
+`
+
+// writeModuleCacheWorkdir writes a configuration calling cacheable and
+// uncacheable modules. If installed is set, the modules are installed as
+// "terraform init" would.
+func writeModuleCacheWorkdir(t *testing.T, installed bool) string {
+	t.Helper()
+	workdir := t.TempDir()
+	writeFile(t, filepath.Join(workdir, "main.tf"), moduleCacheTestConfig)
+	writeFile(t, filepath.Join(workdir, "local", "main.tf"), moduleCacheTestLocalConfig)
+	if !installed {
+		return workdir
+	}
+
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", "consul", "main.tf"), `output "consul" {}`)
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", "unpinned", "main.tf"), `output "vault" {}`)
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", "local.network", "modules", "vpc", "main.tf"), `output "vpc" {}`)
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", "local.network", "README.md"), "network")
+	manifest, err := json.Marshal(moduleManifest{Modules: []moduleManifestRecord{
+		{Key: "", Source: "", Dir: "."},
+		{Key: "consul", Source: "registry.terraform.io/hashicorp/consul/aws", Version: "0.1.0", Dir: ".terraform/modules/consul"},
+		{Key: "unpinned", Source: "registry.terraform.io/hashicorp/vault/aws", Version: "0.5.0", Dir: ".terraform/modules/unpinned"},
+		{Key: "local", Source: "./local", Dir: "local"},
+		{Key: "local.network", Source: "git::https://example.com/network.git//modules/vpc?ref=v1.2.0", Dir: ".terraform/modules/local.network/modules/vpc"},
+	}})
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", moduleManifestName), string(manifest))
+	return workdir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readModuleManifest(t *testing.T, workdir string) map[string]moduleManifestRecord {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(workdir, ".terraform", "modules", moduleManifestName))
+	require.NoError(t, err)
+	var manifest moduleManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	records := map[string]moduleManifestRecord{}
+	for _, record := range manifest.Modules {
+		records[record.Key] = record
+	}
+	return records
+}
+
+func TestModuleCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StoreAndRestore", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		cache, err := NewModuleCache(t.TempDir(), 0, slogtest.Make(t, nil), nil)
+		require.NoError(t, err)
+
+		err = cache.store(ctx, writeModuleCacheWorkdir(t, true))
+		require.NoError(t, err)
+		entries, err := cache.entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 2, "only pinned modules are cached")
+		require.Equal(t, float64(entries.size()), ptestutil.ToFloat64(cache.metrics.size))
+
+		workdir := writeModuleCacheWorkdir(t, false)
+		err = cache.restore(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, float64(2), ptestutil.ToFloat64(cache.metrics.hits))
+		require.Equal(t, float64(0), ptestutil.ToFloat64(cache.metrics.misses))
+
+		records := readModuleManifest(t, workdir)
+		require.Equal(t, map[string]moduleManifestRecord{
+			"consul":        {Key: "consul", Source: "registry.terraform.io/hashicorp/consul/aws", Version: "0.1.0", Dir: ".terraform/modules/consul"},
+			"local.network": {Key: "local.network", Source: "git::https://example.com/network.git//modules/vpc?ref=v1.2.0", Dir: ".terraform/modules/local.network/modules/vpc"},
+		}, records)
+		require.FileExists(t, filepath.Join(workdir, ".terraform", "modules", "consul", "main.tf"))
+		require.FileExists(t, filepath.Join(workdir, ".terraform", "modules", "local.network", "README.md"))
+		require.FileExists(t, filepath.Join(workdir, ".terraform", "modules", "local.network", "modules", "vpc", "main.tf"))
+		require.NoDirExists(t, filepath.Join(workdir, ".terraform", "modules", "unpinned"))
+	})
+
+	t.Run("Miss", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		cache, err := NewModuleCache(t.TempDir(), 0, slogtest.Make(t, nil), nil)
+		require.NoError(t, err)
+
+		workdir := writeModuleCacheWorkdir(t, false)
+		err = cache.restore(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, float64(2), ptestutil.ToFloat64(cache.metrics.misses))
+		require.NoFileExists(t, filepath.Join(workdir, ".terraform", "modules", moduleManifestName))
+	})
+
+	t.Run("AlreadyInstalled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		cache, err := NewModuleCache(t.TempDir(), 0, slogtest.Make(t, nil), nil)
+		require.NoError(t, err)
+		require.NoError(t, cache.store(ctx, writeModuleCacheWorkdir(t, true)))
+
+		workdir := writeModuleCacheWorkdir(t, true)
+		before := readModuleManifest(t, workdir)
+		err = cache.restore(ctx, workdir, discardSink{})
+		require.NoError(t, err)
+		require.Equal(t, before, readModuleManifest(t, workdir))
+		require.Equal(t, float64(0), ptestutil.ToFloat64(cache.metrics.hits))
+	})
+
+	t.Run("EvictLeastRecentlyUsed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		reg := prometheus.NewRegistry()
+		// Only fits one of the two modules.
+		cache, err := NewModuleCache(t.TempDir(), 20, slogtest.Make(t, nil), reg)
+		require.NoError(t, err)
+
+		workdir := t.TempDir()
+		writeFile(t, filepath.Join(workdir, "main.tf"), `
+module "old" {
+  source  = "example/old/aws"
+  version = "1.0.0"
+}
+`)
+		writeFile(t, filepath.Join(workdir, ".terraform", "modules", "old", "main.tf"), `output "old" {}`)
+		writeFile(t, filepath.Join(workdir, ".terraform", "modules", moduleManifestName),
+			`{"Modules":[{"Key":"old","Source":"registry.terraform.io/example/old/aws","Version":"1.0.0","Dir":".terraform/modules/old"}]}`)
+		require.NoError(t, cache.store(ctx, workdir))
+		oldKey := moduleCacheKey(&tfconfig.ModuleCall{Source: "example/old/aws", Version: "1.0.0"})
+		past := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(cache.dir, oldKey, moduleEntryName), past, past))
+
+		workdir = t.TempDir()
+		writeFile(t, filepath.Join(workdir, "main.tf"), `
+module "new" {
+  source  = "example/new/aws"
+  version = "1.0.0"
+}
+`)
+		writeFile(t, filepath.Join(workdir, ".terraform", "modules", "new", "main.tf"), `output "new" {}`)
+		writeFile(t, filepath.Join(workdir, ".terraform", "modules", moduleManifestName),
+			`{"Modules":[{"Key":"new","Source":"registry.terraform.io/example/new/aws","Version":"1.0.0","Dir":".terraform/modules/new"}]}`)
+		require.NoError(t, cache.store(ctx, workdir))
+
+		entries, err := cache.entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "registry.terraform.io/example/new/aws", entries[0].Source)
+		require.Equal(t, float64(1), ptestutil.ToFloat64(cache.metrics.evictions))
+		require.Equal(t, float64(entries.size()), ptestutil.ToFloat64(cache.metrics.size))
+	})
+}
+
+func TestIsCacheableModuleCall(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		source    string
+		version   string
+		cacheable bool
+	}{
+		{source: "./local"},
+		{source: "../local"},
+		{source: "hashicorp/consul/aws"},
+		{source: "hashicorp/consul/aws", version: "0.1.0", cacheable: true},
+		{source: "hashicorp/consul/aws", version: "= 0.1.0", cacheable: true},
+		{source: "hashicorp/consul/aws", version: "~> 0.1"},
+		{source: "hashicorp/consul/aws", version: ">= 0.1.0, < 0.2.0"},
+		{source: "app.terraform.io/example/consul/aws"},
+		{source: "github.com/hashicorp/example"},
+		{source: "github.com/hashicorp/example?ref=v1.0.0", cacheable: true},
+		{source: "git::https://example.com/vpc.git"},
+		{source: "git::https://example.com/vpc.git?ref=51d462976d84fdea54b47d80dcabbf680badcdb8", cacheable: true},
+		{source: "git::https://example.com/vpc.git//modules/vpc?ref=v1.2.0-rc.1", cacheable: true},
+		{source: "git::https://example.com/vpc.git?depth=1&ref=51d462976d84fdea54b47d80dcabbf680badcdb8", cacheable: true},
+		// Branches and abbreviated commits can move between builds.
+		{source: "github.com/hashicorp/example?ref=main"},
+		{source: "git::https://example.com/vpc.git?ref=release/v1.0.0"},
+		{source: "git::https://example.com/vpc.git?ref=51d4629"},
+		{source: "git::https://example.com/vpc.git?ref="},
+		// Only the ref query parameter pins the module.
+		{source: "git::https://example.com/ref=v1.0.0/vpc.git"},
+		{source: "git::https://example.com/vpc.git?sshkey=ref=v1.0.0"},
+		{source: "git::https://example.com/vpc.git?noref=v1.0.0"},
+		{source: "git@github.com:hashicorp/example.git"},
+		{source: "git@github.com:hashicorp/example.git?ref=v1.0.0", cacheable: true},
+		{source: "https://example.com/vpc-module.zip"},
+		{source: "s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip"},
+	} {
+		require.Equal(t, tc.cacheable, isCacheableModuleCall(&tfconfig.ModuleCall{Source: tc.source, Version: tc.version}), tc.source+" "+tc.version)
+	}
+
+	// Calls pinning the same version share the cached module.
+	require.Equal(t,
+		moduleCacheKey(&tfconfig.ModuleCall{Source: "hashicorp/consul/aws", Version: "0.1.0"}),
+		moduleCacheKey(&tfconfig.ModuleCall{Source: "hashicorp/consul/aws", Version: "= 0.1.0"}),
+	)
+}
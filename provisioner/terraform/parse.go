@@ -28,6 +28,17 @@ func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-
 		return provisionersdk.ParseErrorf("load module: %s", formatDiagnostics(sess.WorkDirectory, diags))
 	}
 
+	// Jobs of the template are only assigned to provisioner daemons able to
+	// run an engine version satisfying the constraint.
+	constraints, err := moduleRequiredVersion(module)
+	if err != nil {
+		return provisionersdk.ParseErrorf("%s", err)
+	}
+	var engineVersionConstraint string
+	if constraints != nil {
+		engineVersionConstraint = constraints.String()
+	}
+
 	// Sort variables by (filename, line) to make the ordering consistent
 	variables := make([]*tfconfig.Variable, 0, len(module.Variables))
 	for _, v := range module.Variables {
@@ -40,14 +51,32 @@ func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-
 	var templateVariables []*proto.TemplateVariable
 
 	for _, v := range variables {
+		if v.Name == snapshotVariable || v.Name == snapshotArtifactsVariable {
+			continue
+		}
 		mv, err := convertTerraformVariable(v)
 		if err != nil {
 			return provisionersdk.ParseErrorf("can't convert the Terraform variable to a managed one: %s", err)
 		}
 		templateVariables = append(templateVariables, mv)
 	}
+
+	diagnostics, err := Lint(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ParseErrorf("lint template: %s", err)
+	}
+	for _, d := range diagnostics {
+		level := proto.LogLevel_WARN
+		if d.Severity == proto.Diagnostic_ERROR {
+			level = proto.LogLevel_ERROR
+		}
+		sess.ProvisionLog(level, FormatLintDiagnostic(d))
+	}
+
 	return &proto.ParseComplete{
-		TemplateVariables: templateVariables,
+		TemplateVariables:       templateVariables,
+		Diagnostics:             diagnostics,
+		EngineVersionConstraint: engineVersionConstraint,
 	}
 }
 
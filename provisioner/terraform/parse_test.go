@@ -201,6 +201,50 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "snapshot-variables",
+			Files: map[string]string{
+				"main.tf": `variable "A" {
+				default = "wow"
+			}
+			variable "coder_workspace_snapshot" {
+				type    = bool
+				default = false
+			}
+			variable "coder_workspace_snapshot_artifacts" {
+				type    = map(string)
+				default = {}
+			}`,
+			},
+			Response: &proto.ParseComplete{
+				TemplateVariables: []*proto.TemplateVariable{
+					{
+						Name:         "A",
+						DefaultValue: "wow",
+					},
+				},
+			},
+		},
+		{
+			Name: "required-version",
+			Files: map[string]string{
+				"main.tf": `terraform {
+					required_version = "~> 1.5.0"
+				}`,
+			},
+			Response: &proto.ParseComplete{
+				EngineVersionConstraint: "~> 1.5.0",
+			},
+		},
+		{
+			Name: "invalid-required-version",
+			Files: map[string]string{
+				"main.tf": `terraform {
+					required_version = "not a version"
+				}`,
+			},
+			ErrorContains: "parse required_version",
+		},
 	}
 
 	for _, testCase := range testCases {
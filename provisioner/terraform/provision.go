@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +22,14 @@ import (
 
 const staleTerraformPluginRetention = 30 * 24 * time.Hour
 
+// Terraform variables set by Coder on workspace builds. Templates declare them
+// to take snapshots of a workspace and to restore a workspace from one. They
+// are not template variables, so they're skipped when parsing templates.
+const (
+	snapshotVariable          = "coder_workspace_snapshot"
+	snapshotArtifactsVariable = "coder_workspace_snapshot_artifacts"
+)
+
 func (s *server) setupContexts(parent context.Context, canceledOrComplete <-chan struct{}) (
 	ctx context.Context, cancel func(), killCtx context.Context, kill func(),
 ) {
@@ -71,12 +80,6 @@ func (s *server) Plan(
 	defer cancel()
 	defer kill()
 
-	e := s.executor(sess.WorkDirectory)
-	if err := e.checkMinVersion(ctx); err != nil {
-		return provisionersdk.PlanErrorf(err.Error())
-	}
-	logTerraformEnvVars(sess)
-
 	// If we're destroying, exit early if there's no state. This is necessary to
 	// avoid any cases where a workspace is "locked out" of terraform due to
 	// e.g. bad template param values and cannot be deleted. This is just for
@@ -87,6 +90,16 @@ func (s *server) Plan(
 		return &proto.PlanComplete{}
 	}
 
+	binaryPath, err := s.resolveBinary(ctx, sess.WorkDirectory, sess)
+	if err != nil {
+		return provisionersdk.PlanErrorf(err.Error())
+	}
+	e := s.executor(sess.WorkDirectory, binaryPath)
+	if err := e.checkMinVersion(ctx); err != nil {
+		return provisionersdk.PlanErrorf(err.Error())
+	}
+	logTerraformEnvVars(sess)
+
 	statefilePath := getStateFilePath(sess.WorkDirectory)
 	if len(sess.Config.State) > 0 {
 		err := os.WriteFile(statefilePath, sess.Config.State, 0o600)
@@ -95,11 +108,24 @@ func (s *server) Plan(
 		}
 	}
 
-	err := CleanStaleTerraformPlugins(sess.Context(), s.cachePath, afero.NewOsFs(), time.Now(), s.logger)
+	err = CleanStaleTerraformPlugins(sess.Context(), s.cachePath, afero.NewOsFs(), time.Now(), s.logger)
 	if err != nil {
 		return provisionersdk.PlanErrorf("unable to clean stale Terraform plugins: %s", err)
 	}
 
+	// Modules shipped in the template source archive, e.g. for offline use,
+	// are used as they are. They may differ from what their calls resolve to,
+	// so they must not be cached for other templates calling the same module.
+	storeModules := s.moduleCache != nil && !modulesInstalled(sess.WorkDirectory)
+	if storeModules {
+		// A failure only costs downloading the modules, so it must not fail
+		// the build.
+		err = s.moduleCache.restore(ctx, sess.WorkDirectory, sess)
+		if err != nil {
+			s.logger.Warn(ctx, "unable to restore modules from cache", slog.Error(err))
+		}
+	}
+
 	s.logger.Debug(ctx, "running initialization")
 	err = e.init(ctx, killCtx, sess)
 	if err != nil {
@@ -108,6 +134,15 @@ func (s *server) Plan(
 	}
 	s.logger.Debug(ctx, "ran initialization")
 
+	if storeModules {
+		// Template imports plan as well, so the modules of a template
+		// version are cached before any workspace is built from it.
+		err = s.moduleCache.store(ctx, sess.WorkDirectory)
+		if err != nil {
+			s.logger.Warn(ctx, "unable to store modules in cache", slog.Error(err))
+		}
+	}
+
 	env, err := provisionEnv(sess.Config, request.Metadata, request.RichParameterValues, request.ExternalAuthProviders)
 	if err != nil {
 		return provisionersdk.PlanErrorf("setup env: %s", err)
@@ -137,12 +172,6 @@ func (s *server) Apply(
 	defer cancel()
 	defer kill()
 
-	e := s.executor(sess.WorkDirectory)
-	if err := e.checkMinVersion(ctx); err != nil {
-		return provisionersdk.ApplyErrorf(err.Error())
-	}
-	logTerraformEnvVars(sess)
-
 	// Exit early if there is no plan file. This is necessary to
 	// avoid any cases where a workspace is "locked out" of terraform due to
 	// e.g. bad template param values and cannot be deleted. This is just for
@@ -153,6 +182,18 @@ func (s *server) Apply(
 		return &proto.ApplyComplete{}
 	}
 
+	// The plan was created in this session, so the binary resolves from the
+	// cache without downloading.
+	binaryPath, err := s.resolveBinary(ctx, sess.WorkDirectory, sess)
+	if err != nil {
+		return provisionersdk.ApplyErrorf(err.Error())
+	}
+	e := s.executor(sess.WorkDirectory, binaryPath)
+	if err := e.checkMinVersion(ctx); err != nil {
+		return provisionersdk.ApplyErrorf(err.Error())
+	}
+	logTerraformEnvVars(sess)
+
 	// Earlier in the session, Plan() will have written the state file and the plan file.
 	statefilePath := getStateFilePath(sess.WorkDirectory)
 	env, err := provisionEnv(sess.Config, request.Metadata, nil, nil)
@@ -212,6 +253,20 @@ func provisionEnv(
 	for key, value := range provisionersdk.AgentScriptEnv() {
 		env = append(env, key+"="+value)
 	}
+	// Snapshot variables are passed through the environment so templates that
+	// don't declare them are unaffected.
+	snapshotArtifacts := metadata.GetWorkspaceSnapshotArtifacts()
+	if snapshotArtifacts == nil {
+		snapshotArtifacts = map[string]string{}
+	}
+	snapshotArtifactsJSON, err := json.Marshal(snapshotArtifacts)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal snapshot artifacts: %w", err)
+	}
+	env = append(env,
+		"TF_VAR_"+snapshotVariable+"="+strconv.FormatBool(metadata.GetWorkspaceSnapshot()),
+		"TF_VAR_"+snapshotArtifactsVariable+"="+string(snapshotArtifactsJSON),
+	)
 	for _, param := range richParams {
 		env = append(env, provider.ParameterEnvironmentVariable(param.Name)+"="+param.Value)
 	}
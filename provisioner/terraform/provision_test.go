@@ -389,6 +389,37 @@ func TestProvision(t *testing.T) {
 			},
 			ExpectLogContains: "nothing to do",
 		},
+		{
+			Name: "workspace-snapshot",
+			Files: map[string]string{
+				"main.tf": `variable "coder_workspace_snapshot" {
+					type    = bool
+					default = false
+				}
+
+				variable "coder_workspace_snapshot_artifacts" {
+					type    = map(string)
+					default = {}
+				}
+
+				resource "null_resource" "snapshot" {
+					count = var.coder_workspace_snapshot && lookup(var.coder_workspace_snapshot_artifacts, "home", "") == "snap-1" ? 1 : 0
+				}`,
+			},
+			Metadata: &proto.Metadata{
+				WorkspaceTransition: proto.WorkspaceTransition_STOP,
+				WorkspaceSnapshot:   true,
+				WorkspaceSnapshotArtifacts: map[string]string{
+					"home": "snap-1",
+				},
+			},
+			Response: &proto.PlanComplete{
+				Resources: []*proto.Resource{{
+					Name: "snapshot",
+					Type: "null_resource",
+				}},
+			},
+		},
 		{
 			Name: "rich-parameter-with-value",
 			Files: map[string]string{
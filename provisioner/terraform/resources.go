@@ -2,11 +2,13 @@ package terraform
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/awalterschulze/gographviz"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/mitchellh/mapstructure"
+	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/terraform-provider-coder/provider"
@@ -115,6 +117,17 @@ type resourceMetadataAttributes struct {
 	Items      []resourceMetadataItem `mapstructure:"item"`
 }
 
+// A mapping of attributes on the "coder_workspace_preset" data source.
+type workspacePresetAttributes struct {
+	Name       string                               `mapstructure:"name"`
+	Parameters map[string]string                    `mapstructure:"parameters"`
+	Prebuilds  []workspacePresetPrebuildsAttributes `mapstructure:"prebuilds"`
+}
+
+type workspacePresetPrebuildsAttributes struct {
+	Instances int32 `mapstructure:"instances"`
+}
+
 type resourceMetadataItem struct {
 	Key       string `mapstructure:"key"`
 	Value     string `mapstructure:"value"`
@@ -126,6 +139,7 @@ type State struct {
 	Resources             []*proto.Resource
 	Parameters            []*proto.RichParameter
 	ExternalAuthProviders []*proto.ExternalAuthProviderResource
+	Presets               []*proto.Preset
 }
 
 // ConvertState consumes Terraform state and a GraphViz representation
@@ -150,6 +164,8 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 
 	// Extra array to preserve the order of rich parameters.
 	tfResourcesRichParameters := make([]*tfjson.StateResource, 0)
+	// Presets are listed in the order they are declared, too.
+	tfResourcesPresets := make([]*tfjson.StateResource, 0)
 
 	var findTerraformResources func(mod *tfjson.StateModule)
 	findTerraformResources = func(mod *tfjson.StateModule) {
@@ -160,6 +176,9 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			if resource.Type == "coder_parameter" {
 				tfResourcesRichParameters = append(tfResourcesRichParameters, resource)
 			}
+			if resource.Type == "coder_workspace_preset" {
+				tfResourcesPresets = append(tfResourcesPresets, resource)
+			}
 
 			label := convertAddressToLabel(resource.Address)
 			if tfResourcesByLabel[label] == nil {
@@ -698,6 +717,43 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 		)
 	}
 
+	presets := make([]*proto.Preset, 0, len(tfResourcesPresets))
+	for _, resource := range tfResourcesPresets {
+		var attrs workspacePresetAttributes
+		err = mapstructure.Decode(resource.AttributeValues, &attrs)
+		if err != nil {
+			return nil, xerrors.Errorf("decode map values for coder_workspace_preset.%s: %w", resource.Name, err)
+		}
+		if slice.ContainsCompare(presets, &proto.Preset{Name: attrs.Name}, func(a, b *proto.Preset) bool {
+			return a.Name == b.Name
+		}) {
+			return nil, xerrors.Errorf("coder_workspace_preset names must be unique but %q appears multiple times", attrs.Name)
+		}
+		protoPreset := &proto.Preset{Name: attrs.Name}
+		if len(attrs.Prebuilds) > 0 {
+			if attrs.Prebuilds[0].Instances < 0 {
+				return nil, xerrors.Errorf("coder_workspace_preset %q must not have a negative number of prebuilt instances", attrs.Name)
+			}
+			protoPreset.Prebuilds = attrs.Prebuilds[0].Instances
+		}
+		names := maps.Keys(attrs.Parameters)
+		sort.Strings(names)
+		for _, name := range names {
+			// A preset that sets an unknown parameter could never be used to
+			// build a workspace, so refuse the template instead.
+			if !slice.ContainsCompare(parameters, &proto.RichParameter{Name: name}, func(a, b *proto.RichParameter) bool {
+				return a.Name == b.Name
+			}) {
+				return nil, xerrors.Errorf("coder_workspace_preset %q sets parameter %q, which is not declared by a coder_parameter", attrs.Name, name)
+			}
+			protoPreset.Parameters = append(protoPreset.Parameters, &proto.PresetParameter{
+				Name:  name,
+				Value: attrs.Parameters[name],
+			})
+		}
+		presets = append(presets, protoPreset)
+	}
+
 	// A map is used to ensure we don't have duplicates!
 	externalAuthProvidersMap := map[string]*proto.ExternalAuthProviderResource{}
 	for _, tfResources := range tfResourcesByLabel {
@@ -732,6 +788,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 		Resources:             resources,
 		Parameters:            parameters,
 		ExternalAuthProviders: externalAuthProviders,
+		Presets:               presets,
 	}, nil
 }
 
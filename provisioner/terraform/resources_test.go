@@ -773,6 +773,117 @@ func TestParameterValidation(t *testing.T) {
 	require.ErrorContains(t, err, "coder_parameter names must be unique but \"identical-0\", \"identical-1\" and \"identical-2\" appear multiple times")
 }
 
+func TestPresets(t *testing.T) {
+	t.Parallel()
+
+	// nolint:dogsled
+	_, filename, _, _ := runtime.Caller(0)
+
+	// Presets are declared alongside the parameters they set, so load the
+	// rich-parameters state file and add presets to it.
+	dir := filepath.Join(filepath.Dir(filename), "testdata", "rich-parameters")
+	tfPlanGraph, err := os.ReadFile(filepath.Join(dir, "rich-parameters.tfplan.dot"))
+	require.NoError(t, err)
+	loadModule := func(t *testing.T, presets ...map[string]interface{}) *tfjson.StateModule {
+		t.Helper()
+		tfPlanRaw, err := os.ReadFile(filepath.Join(dir, "rich-parameters.tfplan.json"))
+		require.NoError(t, err)
+		var tfPlan tfjson.Plan
+		err = json.Unmarshal(tfPlanRaw, &tfPlan)
+		require.NoError(t, err)
+		module := tfPlan.PriorState.Values.RootModule
+		for i, preset := range presets {
+			name := fmt.Sprintf("preset_%d", i)
+			module.Resources = append(module.Resources, &tfjson.StateResource{
+				Address:         "data.coder_workspace_preset." + name,
+				Mode:            tfjson.DataResourceMode,
+				Type:            "coder_workspace_preset",
+				Name:            name,
+				ProviderName:    "registry.terraform.io/coder/coder",
+				AttributeValues: preset,
+			})
+		}
+		return module
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		module := loadModule(t, map[string]interface{}{
+			"name": "Large",
+			"parameters": map[string]interface{}{
+				"number_example": "8",
+				"Sample":         "large",
+			},
+			"prebuilds": []interface{}{
+				map[string]interface{}{"instances": float64(3)},
+			},
+		}, map[string]interface{}{
+			"name": "Small",
+			"parameters": map[string]interface{}{
+				"number_example": "2",
+			},
+		})
+		state, err := terraform.ConvertState([]*tfjson.StateModule{module}, string(tfPlanGraph))
+		require.NoError(t, err)
+		require.Len(t, state.Presets, 2)
+		// Presets keep the order they are declared in, and their
+		// parameters are sorted by name.
+		require.Equal(t, "Large", state.Presets[0].Name)
+		require.Equal(t, []*proto.PresetParameter{
+			{Name: "Sample", Value: "large"},
+			{Name: "number_example", Value: "8"},
+		}, state.Presets[0].Parameters)
+		require.EqualValues(t, 3, state.Presets[0].Prebuilds)
+		require.Equal(t, "Small", state.Presets[1].Name)
+		require.Equal(t, []*proto.PresetParameter{
+			{Name: "number_example", Value: "2"},
+		}, state.Presets[1].Parameters)
+		require.Zero(t, state.Presets[1].Prebuilds)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		t.Parallel()
+
+		module := loadModule(t, map[string]interface{}{
+			"name": "Small",
+		}, map[string]interface{}{
+			"name": "Small",
+		})
+		state, err := terraform.ConvertState([]*tfjson.StateModule{module}, string(tfPlanGraph))
+		require.Nil(t, state)
+		require.ErrorContains(t, err, "coder_workspace_preset names must be unique but \"Small\" appears multiple times")
+	})
+
+	t.Run("UnknownParameter", func(t *testing.T) {
+		t.Parallel()
+
+		module := loadModule(t, map[string]interface{}{
+			"name": "GPU",
+			"parameters": map[string]interface{}{
+				"gpus": "1",
+			},
+		})
+		state, err := terraform.ConvertState([]*tfjson.StateModule{module}, string(tfPlanGraph))
+		require.Nil(t, state)
+		require.ErrorContains(t, err, "coder_workspace_preset \"GPU\" sets parameter \"gpus\", which is not declared by a coder_parameter")
+	})
+
+	t.Run("NegativePrebuilds", func(t *testing.T) {
+		t.Parallel()
+
+		module := loadModule(t, map[string]interface{}{
+			"name": "Small",
+			"prebuilds": []interface{}{
+				map[string]interface{}{"instances": float64(-1)},
+			},
+		})
+		state, err := terraform.ConvertState([]*tfjson.StateModule{module}, string(tfPlanGraph))
+		require.Nil(t, state)
+		require.ErrorContains(t, err, "coder_workspace_preset \"Small\" must not have a negative number of prebuilt instances")
+	})
+}
+
 func TestInstanceTypeAssociation(t *testing.T) {
 	t.Parallel()
 	type tc struct {
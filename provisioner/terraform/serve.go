@@ -19,12 +19,23 @@ import (
 type ServeOptions struct {
 	*provisionersdk.ServeOptions
 
-	// BinaryPath specifies the "terraform" binary to use.
+	// Engine specifies whether templates are executed with Terraform or
+	// OpenTofu. Defaults to Terraform.
+	Engine Engine
+	// BinaryPath specifies the "terraform" or "tofu" binary to use for
+	// templates that do not constrain the version with "required_version".
 	// If omitted, the $PATH will attempt to find it.
 	BinaryPath string
 	// CachePath must not be used by multiple processes at once.
 	CachePath string
-	Tracer    trace.Tracer
+	// DisableEngineDownload prevents installing the engine versions that
+	// templates require with "required_version". Jobs of such templates fail
+	// unless a satisfying version is installed in CachePath.
+	DisableEngineDownload bool
+	// ModuleCache is shared with other provisioners to avoid downloading
+	// modules for every session. If nil, modules are always downloaded.
+	ModuleCache *ModuleCache
+	Tracer      trace.Tracer
 
 	// ExitTimeout defines how long we will wait for a running Terraform
 	// command to exit (cleanly) if the provision was stopped. This
@@ -39,42 +50,42 @@ type ServeOptions struct {
 	ExitTimeout time.Duration
 }
 
-func absoluteBinaryPath(ctx context.Context, logger slog.Logger) (string, error) {
-	binaryPath, err := safeexec.LookPath("terraform")
+func absoluteBinaryPath(ctx context.Context, logger slog.Logger, engine Engine) (string, error) {
+	binaryPath, err := safeexec.LookPath(engine.command())
 	if err != nil {
-		return "", xerrors.Errorf("Terraform binary not found: %w", err)
+		return "", xerrors.Errorf("%s binary not found: %w", engine.displayName(), err)
 	}
 
 	// If the "coder" binary is in the same directory as
-	// the "terraform" binary, "terraform" is returned.
+	// the engine binary, its name alone is returned.
 	//
 	// We must resolve the absolute path for other processes
 	// to execute this properly!
 	absoluteBinary, err := filepath.Abs(binaryPath)
 	if err != nil {
-		return "", xerrors.Errorf("Terraform binary absolute path not found: %w", err)
+		return "", xerrors.Errorf("%s binary absolute path not found: %w", engine.displayName(), err)
 	}
 
-	// Checking the installed version of Terraform.
+	// Checking the installed version of the engine.
 	installedVersion, err := versionFromBinaryPath(ctx, absoluteBinary)
 	if err != nil {
-		return "", xerrors.Errorf("Terraform binary get version failed: %w", err)
+		return "", xerrors.Errorf("%s binary get version failed: %w", engine.displayName(), err)
 	}
 
-	logger.Info(ctx, "detected terraform version",
+	logger.Info(ctx, "detected "+engine.command()+" version",
 		slog.F("installed_version", installedVersion.String()),
-		slog.F("min_version", minTerraformVersion.String()),
+		slog.F("min_version", engine.minVersion().String()),
 		slog.F("max_version", maxTerraformVersion.String()))
 
-	if installedVersion.LessThan(minTerraformVersion) {
-		logger.Warn(ctx, "installed terraform version too old, will download known good version to cache")
+	if installedVersion.LessThan(engine.minVersion()) {
+		logger.Warn(ctx, "installed "+engine.command()+" version too old, will download known good version to cache")
 		return "", terraformMinorVersionMismatch
 	}
 
 	// Warn if the installed version is newer than what we've decided is the max.
 	// We used to ignore it and download our own version but this makes it easier
 	// to test out newer versions of Terraform.
-	if installedVersion.GreaterThanOrEqual(maxTerraformVersion) {
+	if engine == EngineTerraform && installedVersion.GreaterThanOrEqual(maxTerraformVersion) {
 		logger.Warn(ctx, "installed terraform version newer than expected, you may experience bugs",
 			slog.F("installed_version", installedVersion.String()),
 			slog.F("max_version", maxTerraformVersion.String()))
@@ -85,8 +96,14 @@ func absoluteBinaryPath(ctx context.Context, logger slog.Logger) (string, error)
 
 // Serve starts a dRPC server on the provided transport speaking Terraform provisioner.
 func Serve(ctx context.Context, options *ServeOptions) error {
+	if options.Engine == "" {
+		options.Engine = EngineTerraform
+	}
+	if !options.Engine.Valid() {
+		return xerrors.Errorf("unknown engine %q", options.Engine)
+	}
 	if options.BinaryPath == "" {
-		absoluteBinary, err := absoluteBinaryPath(ctx, options.Logger)
+		absoluteBinary, err := absoluteBinaryPath(ctx, options.Logger, options.Engine)
 		if err != nil {
 			// This is an early exit to prevent extra execution in case the context is canceled.
 			// It generally happens in unit tests since this method is asynchronous and
@@ -94,7 +111,17 @@ func Serve(ctx context.Context, options *ServeOptions) error {
 			if xerrors.Is(err, context.Canceled) {
 				return xerrors.Errorf("absolute binary context canceled: %w", err)
 			}
-
+		}
+		switch {
+		case err == nil:
+			options.BinaryPath = absoluteBinary
+		case options.Engine == EngineOpenTofu:
+			// Most daemons never run an OpenTofu template, so the download
+			// is deferred until the first job needs it.
+			options.Logger.Info(ctx, "no usable tofu binary found, it will be downloaded to the cache dir when first used",
+				slog.F("opentofu_version", OpenTofuVersion.String()),
+				slog.F("cache_dir", options.CachePath))
+		default:
 			options.Logger.Warn(ctx, "no usable terraform binary found, downloading to cache dir",
 				slog.F("terraform_version", TerraformVersion.String()),
 				slog.F("cache_dir", options.CachePath))
@@ -103,8 +130,6 @@ func Serve(ctx context.Context, options *ServeOptions) error {
 				return xerrors.Errorf("install terraform: %w", err)
 			}
 			options.BinaryPath = binPath
-		} else {
-			options.BinaryPath = absoluteBinary
 		}
 	}
 	if options.Tracer == nil {
@@ -114,22 +139,32 @@ func Serve(ctx context.Context, options *ServeOptions) error {
 		options.ExitTimeout = unhanger.HungJobExitTimeout
 	}
 	return provisionersdk.Serve(ctx, &server{
-		execMut:     &sync.Mutex{},
-		binaryPath:  options.BinaryPath,
-		cachePath:   options.CachePath,
-		logger:      options.Logger,
-		tracer:      options.Tracer,
-		exitTimeout: options.ExitTimeout,
+		execMut:               &sync.Mutex{},
+		engine:                options.Engine,
+		defaultMut:            &sync.Mutex{},
+		defaultBinary:         options.BinaryPath,
+		cachePath:             options.CachePath,
+		disableEngineDownload: options.DisableEngineDownload,
+		moduleCache:           options.ModuleCache,
+		logger:                options.Logger,
+		tracer:                options.Tracer,
+		exitTimeout:           options.ExitTimeout,
 	}, options.ServeOptions)
 }
 
 type server struct {
-	execMut     *sync.Mutex
-	binaryPath  string
-	cachePath   string
-	logger      slog.Logger
-	tracer      trace.Tracer
-	exitTimeout time.Duration
+	execMut *sync.Mutex
+	engine  Engine
+	// defaultMut guards defaultBinary, which is installed lazily when the
+	// daemon was started without a binary.
+	defaultMut            *sync.Mutex
+	defaultBinary         string
+	cachePath             string
+	disableEngineDownload bool
+	moduleCache           *ModuleCache
+	logger                slog.Logger
+	tracer                trace.Tracer
+	exitTimeout           time.Duration
 }
 
 func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
@@ -138,11 +173,11 @@ func (s *server) startTrace(ctx context.Context, name string, opts ...trace.Span
 	))...)
 }
 
-func (s *server) executor(workdir string) *executor {
+func (s *server) executor(workdir, binaryPath string) *executor {
 	return &executor{
 		server:     s,
 		mut:        s.execMut,
-		binaryPath: s.binaryPath,
+		binaryPath: binaryPath,
 		cachePath:  s.cachePath,
 		workdir:    workdir,
 		logger:     s.logger.Named("executor"),
@@ -517,7 +517,7 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 		Stage:     "Parsing template parameters",
 		CreatedAt: time.Now().UnixMilli(),
 	})
-	templateVariables, readme, err := r.runTemplateImportParse(ctx)
+	parse, err := r.runTemplateImportParse(ctx)
 	if err != nil {
 		return nil, r.failedJobf("run parse: %s", err)
 	}
@@ -525,15 +525,28 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 	// Once Terraform template variables are parsed, the runner can pass variables
 	// to store in database and filter valid ones.
 	updateResponse, err := r.update(ctx, &proto.UpdateJobRequest{
-		JobId:              r.job.JobId,
-		TemplateVariables:  templateVariables,
-		UserVariableValues: r.job.GetTemplateImport().GetUserVariableValues(),
-		Readme:             readme,
+		JobId:               r.job.JobId,
+		TemplateVariables:   parse.TemplateVariables,
+		UserVariableValues:  r.job.GetTemplateImport().GetUserVariableValues(),
+		Readme:              parse.Readme,
+		TemplateDiagnostics: parse.Diagnostics,
 	})
 	if err != nil {
 		return nil, r.failedJobf("update job: %s", err)
 	}
 
+	// Static errors in the template would only fail workspace builds, so
+	// refuse the version. Warnings are stored with it instead.
+	var templateErrors []string
+	for _, d := range parse.Diagnostics {
+		if d.Severity == sdkproto.Diagnostic_ERROR {
+			templateErrors = append(templateErrors, fmt.Sprintf("%s:%d: %s", d.Filename, d.Line, d.Summary))
+		}
+	}
+	if len(templateErrors) > 0 {
+		return nil, r.failedJobf("template has errors:\n%s", strings.Join(templateErrors, "\n"))
+	}
+
 	// Determine persistent resources
 	r.queueLog(ctx, &proto.Log{
 		Source:    proto.LogSource_PROVISIONER_DAEMON,
@@ -579,26 +592,26 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 				RichParameters:             startProvision.Parameters,
 				ExternalAuthProvidersNames: externalAuthProviderNames,
 				ExternalAuthProviders:      startProvision.ExternalAuthProviders,
+				Presets:                    startProvision.Presets,
+				EngineVersionConstraint:    parse.EngineVersionConstraint,
 			},
 		},
 	}, nil
 }
 
-// Parses template variables and README from source.
-func (r *Runner) runTemplateImportParse(ctx context.Context) (
-	vars []*sdkproto.TemplateVariable, readme []byte, err error,
-) {
+// Parses template variables, README and diagnostics from source.
+func (r *Runner) runTemplateImportParse(ctx context.Context) (*sdkproto.ParseComplete, error) {
 	ctx, span := r.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
-	err = r.session.Send(&sdkproto.Request{Type: &sdkproto.Request_Parse{Parse: &sdkproto.ParseRequest{}}})
+	err := r.session.Send(&sdkproto.Request{Type: &sdkproto.Request_Parse{Parse: &sdkproto.ParseRequest{}}})
 	if err != nil {
-		return nil, nil, xerrors.Errorf("parse source: %w", err)
+		return nil, xerrors.Errorf("parse source: %w", err)
 	}
 	for {
 		msg, err := r.session.Recv()
 		if err != nil {
-			return nil, nil, xerrors.Errorf("recv parse source: %w", err)
+			return nil, xerrors.Errorf("recv parse source: %w", err)
 		}
 		switch msgType := msg.Type.(type) {
 		case *sdkproto.Response_Log:
@@ -619,15 +632,17 @@ func (r *Runner) runTemplateImportParse(ctx context.Context) (
 			r.logger.Debug(context.Background(), "parse complete",
 				slog.F("template_variables", pc.TemplateVariables),
 				slog.F("readme_len", len(pc.Readme)),
+				slog.F("diagnostics", len(pc.Diagnostics)),
+				slog.F("engine_version_constraint", pc.EngineVersionConstraint),
 				slog.F("error", pc.Error),
 			)
 			if pc.Error != "" {
-				return nil, nil, xerrors.Errorf("parse error: %s", pc.Error)
+				return nil, xerrors.Errorf("parse error: %s", pc.Error)
 			}
 
-			return msgType.Parse.TemplateVariables, msgType.Parse.Readme, nil
+			return pc, nil
 		default:
-			return nil, nil, xerrors.Errorf("invalid message type %q received from provisioner",
+			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
 				reflect.TypeOf(msg.Type).String())
 		}
 	}
@@ -637,6 +652,7 @@ type templateImportProvision struct {
 	Resources             []*sdkproto.Resource
 	Parameters            []*sdkproto.RichParameter
 	ExternalAuthProviders []*sdkproto.ExternalAuthProviderResource
+	Presets               []*sdkproto.Preset
 }
 
 // Performs a dry-run provision when importing a template.
@@ -728,6 +744,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 				Resources:             c.Resources,
 				Parameters:            c.Parameters,
 				ExternalAuthProviders: c.ExternalAuthProviders,
+				Presets:               c.Presets,
 			}, nil
 		default:
 			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
@@ -889,11 +906,15 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 	}
 
 	if !resp.Ok {
+		output := "This build would exceed your quota. Failing."
+		if resp.Message != "" {
+			output = resp.Message + " Failing."
+		}
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_WARN,
 			CreatedAt: time.Now().UnixMilli(),
-			Output:    "This build would exceed your quota. Failing.",
+			Output:    output,
 			Stage:     stage,
 		})
 		return r.failedWorkspaceBuildf("insufficient quota")
@@ -451,6 +451,7 @@ const createTemplateVersionTar = async (
       templateVariables: [],
       error: "",
       readme: new Uint8Array(),
+      diagnostics: [],
       ...response.parse,
     } as ParseComplete;
     tar.addFile(
@@ -555,6 +556,7 @@ const createTemplateVersionTar = async (
       resources: [],
       parameters: [],
       externalAuthProviders: [],
+      presets: [],
       ...response.plan,
     } as PlanComplete;
     response.plan.resources = response.plan.resources?.map(fillResource);
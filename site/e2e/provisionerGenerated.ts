@@ -77,6 +77,25 @@ export interface RichParameterValue {
   value: string;
 }
 
+/**
+ * Preset is a named set of parameter values declared by a template, which
+ * users can pick instead of entering each value.
+ */
+export interface Preset {
+  name: string;
+  parameters: PresetParameter[];
+  /**
+   * prebuilds is the number of workspaces kept built with this preset,
+   * ready to be claimed by users.
+   */
+  prebuilds: number;
+}
+
+export interface PresetParameter {
+  name: string;
+  value: string;
+}
+
 /** VariableValue holds the key/value mapping of a Terraform variable. */
 export interface VariableValue {
   name: string;
@@ -231,6 +250,18 @@ export interface Metadata {
   templateId: string;
   workspaceOwnerName: string;
   workspaceOwnerGroups: string[];
+  /** workspace_snapshot is set on stop builds that snapshot the workspace. */
+  workspaceSnapshot: boolean;
+  /**
+   * workspace_snapshot_artifacts are the artifacts of the snapshot the
+   * workspace was created from.
+   */
+  workspaceSnapshotArtifacts: { [key: string]: string };
+}
+
+export interface Metadata_WorkspaceSnapshotArtifactsEntry {
+  key: string;
+  value: string;
 }
 
 /** Config represents execution configuration shared by all subsequent requests in the Session */
@@ -242,6 +273,24 @@ export interface Config {
   provisionerLogLevel: string;
 }
 
+/** Diagnostic is a problem found in the source of a template. */
+export interface Diagnostic {
+  severity: Diagnostic_Severity;
+  summary: string;
+  detail: string;
+  /** filename is relative to the root of the template. */
+  filename: string;
+  line: number;
+  /** code identifies the check that found the problem. */
+  code: string;
+}
+
+export enum Diagnostic_Severity {
+  WARNING = 0,
+  ERROR = 1,
+  UNRECOGNIZED = -1,
+}
+
 /** ParseRequest consumes source-code to produce inputs. */
 export interface ParseRequest {}
 
@@ -250,6 +299,7 @@ export interface ParseComplete {
   error: string;
   templateVariables: TemplateVariable[];
   readme: Uint8Array;
+  diagnostics: Diagnostic[];
 }
 
 /** PlanRequest asks the provisioner to plan what resources & parameters it will create */
@@ -266,6 +316,7 @@ export interface PlanComplete {
   resources: Resource[];
   parameters: RichParameter[];
   externalAuthProviders: ExternalAuthProviderResource[];
+  presets: Preset[];
 }
 
 /**
@@ -429,6 +480,39 @@ export const RichParameterValue = {
   },
 };
 
+export const Preset = {
+  encode(
+    message: Preset,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    for (const v of message.parameters) {
+      PresetParameter.encode(v!, writer.uint32(18).fork()).ldelim();
+    }
+    if (message.prebuilds !== 0) {
+      writer.uint32(24).int32(message.prebuilds);
+    }
+    return writer;
+  },
+};
+
+export const PresetParameter = {
+  encode(
+    message: PresetParameter,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name);
+    }
+    if (message.value !== "") {
+      writer.uint32(18).string(message.value);
+    }
+    return writer;
+  },
+};
+
 export const VariableValue = {
   encode(
     message: VariableValue,
@@ -836,6 +920,32 @@ export const Metadata = {
     for (const v of message.workspaceOwnerGroups) {
       writer.uint32(114).string(v!);
     }
+    if (message.workspaceSnapshot === true) {
+      writer.uint32(120).bool(message.workspaceSnapshot);
+    }
+    Object.entries(message.workspaceSnapshotArtifacts).forEach(
+      ([key, value]) => {
+        Metadata_WorkspaceSnapshotArtifactsEntry.encode(
+          { key: key as any, value },
+          writer.uint32(130).fork(),
+        ).ldelim();
+      },
+    );
+    return writer;
+  },
+};
+
+export const Metadata_WorkspaceSnapshotArtifactsEntry = {
+  encode(
+    message: Metadata_WorkspaceSnapshotArtifactsEntry,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.key !== "") {
+      writer.uint32(10).string(message.key);
+    }
+    if (message.value !== "") {
+      writer.uint32(18).string(message.value);
+    }
     return writer;
   },
 };
@@ -858,6 +968,33 @@ export const Config = {
   },
 };
 
+export const Diagnostic = {
+  encode(
+    message: Diagnostic,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.severity !== 0) {
+      writer.uint32(8).int32(message.severity);
+    }
+    if (message.summary !== "") {
+      writer.uint32(18).string(message.summary);
+    }
+    if (message.detail !== "") {
+      writer.uint32(26).string(message.detail);
+    }
+    if (message.filename !== "") {
+      writer.uint32(34).string(message.filename);
+    }
+    if (message.line !== 0) {
+      writer.uint32(40).int32(message.line);
+    }
+    if (message.code !== "") {
+      writer.uint32(50).string(message.code);
+    }
+    return writer;
+  },
+};
+
 export const ParseRequest = {
   encode(
     _: ParseRequest,
@@ -881,6 +1018,9 @@ export const ParseComplete = {
     if (message.readme.length !== 0) {
       writer.uint32(26).bytes(message.readme);
     }
+    for (const v of message.diagnostics) {
+      Diagnostic.encode(v!, writer.uint32(34).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -926,6 +1066,9 @@ export const PlanComplete = {
         writer.uint32(34).fork(),
       ).ldelim();
     }
+    for (const v of message.presets) {
+      Preset.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -125,6 +125,26 @@ export const login = async (
   return response.data;
 };
 
+export const loginWithTOTP = async (
+  req: TypesGen.LoginWithTOTPRequest,
+): Promise<TypesGen.LoginWithTOTPResponse> => {
+  const response = await axios.post<TypesGen.LoginWithTOTPResponse>(
+    "/api/v2/users/login/totp",
+    req,
+  );
+  return response.data;
+};
+
+export const loginTOTPEnroll = async (
+  req: TypesGen.LoginTOTPEnrollRequest,
+): Promise<TypesGen.TOTPEnrollment> => {
+  const response = await axios.post<TypesGen.TOTPEnrollment>(
+    "/api/v2/users/login/totp/enroll",
+    req,
+  );
+  return response.data;
+};
+
 export const convertToOAUTH = async (request: TypesGen.ConvertLoginRequest) => {
   const response = await axios.post<TypesGen.OAuthConversionResponse>(
     "/api/v2/users/me/convert-login",
@@ -8,6 +8,7 @@ import * as API from "api/api";
 import type {
   AuthorizationRequest,
   GetUsersResponse,
+  LoginWithTOTPRequest,
   UpdateUserPasswordRequest,
   UpdateUserProfileRequest,
   UpdateUserAppearanceSettingsRequest,
@@ -157,15 +158,20 @@ export const login = (
     mutationFn: async (credentials: { email: string; password: string }) =>
       loginFn({ ...credentials, authorization }),
     onSuccess: async (data: Awaited<ReturnType<typeof loginFn>>) => {
-      queryClient.setQueryData(["me"], data.user);
-      queryClient.setQueryData(
-        getAuthorizationKey(authorization),
-        data.permissions,
-      );
+      if ("totp" in data) {
+        // The login is only finished once the second factor is checked.
+        return;
+      }
+      setSignedInUser(queryClient, authorization, data);
     },
   };
 };
 
+export type TOTPChallenge = {
+  ticket: string;
+  enrollmentRequired: boolean;
+};
+
 const loginFn = async ({
   email,
   password,
@@ -175,7 +181,54 @@ const loginFn = async ({
   password: string;
   authorization: AuthorizationRequest;
 }) => {
-  await API.login(email, password);
+  const response = await API.login(email, password);
+  if (response.totp_ticket) {
+    const totp: TOTPChallenge = {
+      ticket: response.totp_ticket,
+      enrollmentRequired: Boolean(response.totp_enrollment_required),
+    };
+    return { totp };
+  }
+  return fetchSignedInUser(authorization);
+};
+
+export const loginWithTOTP = (
+  authorization: AuthorizationRequest,
+  queryClient: QueryClient,
+) => {
+  return {
+    mutationFn: async (req: LoginWithTOTPRequest) =>
+      loginWithTOTPFn({ req, authorization }),
+    onSuccess: async (data: Awaited<ReturnType<typeof loginWithTOTPFn>>) => {
+      if (data.recoveryCodes.length > 0) {
+        // The login page shows the recovery codes first, and refetches the
+        // user once they have been saved.
+        return;
+      }
+      setSignedInUser(queryClient, authorization, data);
+    },
+  };
+};
+
+const loginWithTOTPFn = async ({
+  req,
+  authorization,
+}: {
+  req: LoginWithTOTPRequest;
+  authorization: AuthorizationRequest;
+}) => {
+  const response = await API.loginWithTOTP(req);
+  const data = await fetchSignedInUser(authorization);
+  return { ...data, recoveryCodes: response.recovery_codes ?? [] };
+};
+
+export const loginTOTPEnroll = () => {
+  return {
+    mutationFn: API.loginTOTPEnroll,
+  };
+};
+
+const fetchSignedInUser = async (authorization: AuthorizationRequest) => {
   const [user, permissions] = await Promise.all([
     API.getAuthenticatedUser(),
     API.checkAuthorization(authorization),
@@ -186,6 +239,18 @@ const loginFn = async ({
   };
 };
 
+const setSignedInUser = (
+  queryClient: QueryClient,
+  authorization: AuthorizationRequest,
+  data: Awaited<ReturnType<typeof fetchSignedInUser>>,
+) => {
+  queryClient.setQueryData(["me"], data.user);
+  queryClient.setQueryData(
+    getAuthorizationKey(authorization),
+    data.permissions,
+  );
+};
+
 export const logout = (queryClient: QueryClient) => {
   return {
     mutationFn: API.logout,
@@ -104,6 +104,7 @@ export interface AuditLog {
   readonly resource_link: string;
   readonly is_deleted: boolean;
   readonly user?: User;
+  readonly impersonator?: MinimalUser;
 }
 
 // From codersdk/audit.go
@@ -178,6 +179,16 @@ export interface ConvertLoginRequest {
   readonly password: string;
 }
 
+// From codersdk/roles.go
+export interface CreateCustomRoleRequest {
+  readonly name: string;
+  readonly display_name: string;
+  readonly organization_id?: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string;
@@ -210,6 +221,16 @@ export interface CreateGroupRequest {
   readonly display_name: string;
   readonly avatar_url: string;
   readonly quota_allowance: number;
+  readonly max_workspaces_per_user: number;
+}
+
+// From codersdk/oidcsyncrules.go
+export interface CreateOIDCSyncRuleRequest {
+  readonly claim: string;
+  readonly pattern: string;
+  readonly site_roles: string[];
+  readonly organization_id?: string;
+  readonly organization_roles: string[];
 }
 
 // From codersdk/users.go
@@ -217,6 +238,19 @@ export interface CreateOrganizationRequest {
   readonly name: string;
 }
 
+// From codersdk/scim.go
+export interface CreateSCIMTokenRequest {
+  readonly name: string;
+}
+
+// From codersdk/templatereleasechannels.go
+export interface CreateTemplateReleaseChannelRequest {
+  readonly name: string;
+  readonly template_version_id: string;
+  readonly user_ids?: string[];
+  readonly group_ids?: string[];
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string;
@@ -283,6 +317,15 @@ export interface CreateUserRequest {
   readonly login_type: LoginType;
   readonly disable_login: boolean;
   readonly organization_id: string;
+  readonly service_account?: boolean;
+}
+
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly name: string;
+  readonly url: string;
+  readonly events: WebhookEvent[];
+  readonly secret: string;
 }
 
 // From codersdk/workspaces.go
@@ -293,9 +336,18 @@ export interface CreateWorkspaceBuildRequest {
   readonly state?: string;
   readonly orphan?: boolean;
   readonly rich_parameter_values?: WorkspaceBuildParameter[];
+  readonly template_version_preset_id?: string;
   readonly log_level?: ProvisionerLogLevel;
 }
 
+// From codersdk/workspacebulk.go
+export interface CreateWorkspaceBulkOperationRequest {
+  readonly action: WorkspaceBulkAction;
+  readonly q: string;
+  readonly concurrency?: number;
+  readonly dry_run?: boolean;
+}
+
 // From codersdk/workspaceproxy.go
 export interface CreateWorkspaceProxyRequest {
   readonly name: string;
@@ -312,6 +364,26 @@ export interface CreateWorkspaceRequest {
   readonly ttl_ms?: number;
   readonly rich_parameter_values?: WorkspaceBuildParameter[];
   readonly automatic_updates?: AutomaticUpdates;
+  readonly template_version_preset_id?: string;
+  readonly snapshot_id?: string;
+}
+
+// From codersdk/workspacesnapshots.go
+export interface CreateWorkspaceSnapshotRequest {
+  readonly name: string;
+}
+
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly id: string;
+  readonly name: string;
+  readonly display_name: string;
+  readonly organization_id?: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+  readonly created_at: string;
+  readonly updated_at: string;
 }
 
 // From codersdk/deployment.go
@@ -428,6 +500,7 @@ export interface DeploymentValues {
   readonly experiments?: string[];
   readonly update_check?: boolean;
   readonly max_token_lifetime?: number;
+  readonly max_service_account_token_lifetime?: number;
   readonly swagger?: SwaggerConfig;
   readonly logging?: LoggingConfig;
   readonly dangerous?: DangerousConfig;
@@ -435,6 +508,8 @@ export interface DeploymentValues {
   readonly max_session_expiry?: number;
   readonly disable_session_expiry_refresh?: boolean;
   readonly disable_password_auth?: boolean;
+  readonly login_limit?: LoginLimitConfig;
+  readonly require_totp?: boolean;
   readonly support?: SupportConfig;
   readonly external_auth?: ExternalAuthConfig[];
   readonly config_ssh?: SSHConfig;
@@ -447,6 +522,7 @@ export interface DeploymentValues {
   readonly allow_workspace_renames?: boolean;
   readonly healthcheck?: HealthcheckConfig;
   readonly cli_upgrade_message?: string;
+  readonly notifications?: NotificationsConfig;
   readonly config?: string;
   readonly write_config?: boolean;
   readonly address?: string;
@@ -585,6 +661,7 @@ export interface Group {
   readonly avatar_url: string;
   readonly quota_allowance: number;
   readonly source: GroupSource;
+  readonly max_workspaces_per_user: number;
 }
 
 // From codersdk/workspaceapps.go
@@ -600,6 +677,25 @@ export interface HealthcheckConfig {
   readonly threshold_database: number;
 }
 
+// From codersdk/impersonation.go
+export interface ImpersonateUserRequest {
+  readonly lifetime: number;
+}
+
+// From codersdk/impersonation.go
+export interface ImpersonateUserResponse extends ImpersonationSession {
+  readonly key: string;
+}
+
+// From codersdk/impersonation.go
+export interface ImpersonationSession {
+  readonly id: string;
+  readonly user_id: string;
+  readonly impersonator_id: string;
+  readonly created_at: string;
+  readonly expires_at: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface IssueReconnectingPTYSignedTokenRequest {
   readonly url: string;
@@ -652,6 +748,21 @@ export interface LoggingConfig {
   readonly stackdriver: string;
 }
 
+// From codersdk/deployment.go
+export interface LoginLimitConfig {
+  readonly failure_window: number;
+  readonly free_attempts: number;
+  readonly base_delay: number;
+  readonly max_delay: number;
+  readonly lockout_threshold: number;
+  readonly lockout_duration: number;
+}
+
+// From codersdk/totp.go
+export interface LoginTOTPEnrollRequest {
+  readonly ticket: string;
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string;
@@ -661,6 +772,20 @@ export interface LoginWithPasswordRequest {
 // From codersdk/users.go
 export interface LoginWithPasswordResponse {
   readonly session_token: string;
+  readonly totp_ticket?: string;
+  readonly totp_enrollment_required?: boolean;
+}
+
+// From codersdk/totp.go
+export interface LoginWithTOTPRequest {
+  readonly ticket: string;
+  readonly code: string;
+}
+
+// From codersdk/totp.go
+export interface LoginWithTOTPResponse {
+  readonly session_token: string;
+  readonly recovery_codes?: string[];
 }
 
 // From codersdk/users.go
@@ -670,6 +795,39 @@ export interface MinimalUser {
   readonly avatar_url: string;
 }
 
+// From codersdk/notifications.go
+export interface NotificationPreference {
+  readonly template: string;
+  readonly description: string;
+  readonly disabled: boolean;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly method: string;
+  readonly dispatch_timeout: number;
+  readonly fetch_interval: number;
+  readonly max_send_attempts: number;
+  readonly retry_interval: number;
+  readonly email: NotificationsEmailConfig;
+  readonly webhook: NotificationsWebhookConfig;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string;
+  readonly smarthost: string;
+  readonly hello: string;
+  readonly username: string;
+  readonly password: string;
+  readonly force_tls: boolean;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsWebhookConfig {
+  readonly endpoint: string;
+}
+
 // From codersdk/oauth2.go
 export interface OAuth2AppEndpoints {
   readonly authorization: string;
@@ -757,11 +915,51 @@ export interface OIDCConfig {
   readonly user_role_field: string;
   readonly user_role_mapping: Record<string, string[]>;
   readonly user_roles_default: string[];
+  readonly organization_field: string;
+  readonly organization_mapping: Record<string, string[]>;
+  readonly organization_assign_default: boolean;
   readonly sign_in_text: string;
   readonly icon_url: string;
   readonly signups_disabled_text: string;
 }
 
+// From codersdk/oidcsyncrules.go
+export interface OIDCSyncDryRunOrganization {
+  readonly organization_id: string;
+  readonly roles_synced: boolean;
+  readonly roles: string[];
+}
+
+// From codersdk/oidcsyncrules.go
+export interface OIDCSyncDryRunRequest {
+  readonly claims: Record<string, any>;
+  readonly rules?: CreateOIDCSyncRuleRequest[];
+}
+
+// From codersdk/oidcsyncrules.go
+export interface OIDCSyncDryRunResponse {
+  readonly error?: string;
+  readonly matched_rules: number[];
+  readonly role_sync_enabled: boolean;
+  readonly site_roles: string[];
+  readonly group_sync_enabled: boolean;
+  readonly groups: string[];
+  readonly organization_sync_enabled: boolean;
+  readonly organizations: OIDCSyncDryRunOrganization[];
+}
+
+// From codersdk/oidcsyncrules.go
+export interface OIDCSyncRule {
+  readonly id: string;
+  readonly claim: string;
+  readonly pattern: string;
+  readonly site_roles: string[];
+  readonly organization_id?: string;
+  readonly organization_roles: string[];
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/organizations.go
 export interface Organization {
   readonly id: string;
@@ -795,6 +993,7 @@ export interface PatchGroupRequest {
   readonly display_name?: string;
   readonly avatar_url?: string;
   readonly quota_allowance?: number;
+  readonly max_workspaces_per_user?: number;
 }
 
 // From codersdk/templateversions.go
@@ -812,6 +1011,13 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean;
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean;
+  readonly resource_type: RBACResource;
+  readonly action: string;
+}
+
 // From codersdk/oauth2.go
 export interface PostOAuth2ProviderAppRequest {
   readonly name: string;
@@ -825,6 +1031,20 @@ export interface PprofConfig {
   readonly address: string;
 }
 
+// From codersdk/templateversions.go
+export interface Preset {
+  readonly id: string;
+  readonly name: string;
+  readonly parameters: PresetParameter[];
+  readonly prebuilds: number;
+}
+
+// From codersdk/templateversions.go
+export interface PresetParameter {
+  readonly name: string;
+  readonly value: string;
+}
+
 // From codersdk/deployment.go
 export interface PrometheusConfig {
   readonly enable: boolean;
@@ -842,6 +1062,8 @@ export interface ProvisionerConfig {
   readonly daemon_poll_jitter: number;
   readonly force_cancel_interval: number;
   readonly daemon_psk: string;
+  readonly module_cache_size: number;
+  readonly state_retention: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -916,6 +1138,7 @@ export interface ReducedUser extends MinimalUser {
   readonly status: UserStatus;
   readonly login_type: LoginType;
   readonly theme_preference: string;
+  readonly is_service_account?: boolean;
 }
 
 // From codersdk/workspaceproxy.go
@@ -945,6 +1168,11 @@ export interface Replica {
   readonly database_latency: number;
 }
 
+// From codersdk/totp.go
+export interface ResetTOTPRequest {
+  readonly code: string;
+}
+
 // From codersdk/workspaces.go
 export interface ResolveAutostartResponse {
   readonly parameter_mismatch: boolean;
@@ -957,12 +1185,33 @@ export interface Response {
   readonly validations?: ValidationError[];
 }
 
+// From codersdk/usersessions.go
+export interface RevokeUserSessionsResponse {
+  readonly revoked: number;
+}
+
 // From codersdk/roles.go
 export interface Role {
   readonly name: string;
   readonly display_name: string;
 }
 
+// From codersdk/scim.go
+export interface SCIMToken {
+  readonly id: string;
+  readonly name: string;
+  readonly created_at: string;
+  readonly last_used_at?: string;
+  readonly token_truncated: string;
+}
+
+// From codersdk/scim.go
+export interface SCIMTokenFull {
+  readonly id: string;
+  readonly name: string;
+  readonly token: string;
+}
+
 // From codersdk/deployment.go
 export interface SSHConfig {
   readonly DeploymentName: string;
@@ -1024,6 +1273,17 @@ export interface TLSConfig {
   readonly allow_insecure_ciphers: boolean;
 }
 
+// From codersdk/totp.go
+export interface TOTPEnrollment {
+  readonly secret: string;
+  readonly url: string;
+}
+
+// From codersdk/totp.go
+export interface TOTPRecoveryCodes {
+  readonly recovery_codes: string[];
+}
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
   readonly enable: boolean;
@@ -1061,6 +1321,7 @@ export interface Template {
   readonly time_til_dormant_autodelete_ms: number;
   readonly require_active_version: boolean;
   readonly max_port_share_level: WorkspaceAgentPortShareLevel;
+  readonly max_workspaces: number;
 }
 
 // From codersdk/templates.go
@@ -1163,11 +1424,30 @@ export interface TemplateParameterValue {
   readonly count: number;
 }
 
+// From codersdk/templatereleasechannels.go
+export interface TemplateReleaseChannel {
+  readonly id: string;
+  readonly template_id: string;
+  readonly name: string;
+  readonly template_version_id: string;
+  readonly template_version_name: string;
+  readonly user_ids: string[];
+  readonly group_ids: string[];
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
   readonly role: TemplateRole;
 }
 
+// From codersdk/templatereleasechannels.go
+export interface TemplateUserActiveVersion {
+  readonly template_version_id: string;
+  readonly release_channel?: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersion {
   readonly id: string;
@@ -1184,6 +1464,16 @@ export interface TemplateVersion {
   readonly warnings?: TemplateVersionWarning[];
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionDiagnostic {
+  readonly severity: TemplateVersionDiagnosticSeverity;
+  readonly code: string;
+  readonly summary: string;
+  readonly detail: string;
+  readonly filename: string;
+  readonly line: number;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionExternalAuth {
   readonly id: string;
@@ -1258,6 +1548,11 @@ export interface TraceConfig {
   readonly data_dog: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface TransferWorkspaceOwnershipRequest {
+  readonly owner: string;
+}
+
 // From codersdk/templates.go
 export interface TransitionStats {
   readonly P50?: number;
@@ -1283,6 +1578,23 @@ export interface UpdateCheckResponse {
   readonly url: string;
 }
 
+// From codersdk/roles.go
+export interface UpdateCustomRoleRequest {
+  readonly display_name: string;
+  readonly site_permissions: Permission[];
+  readonly organization_permissions: Permission[];
+  readonly user_permissions: Permission[];
+}
+
+// From codersdk/oidcsyncrules.go
+export interface UpdateOIDCSyncRuleRequest {
+  readonly claim: string;
+  readonly pattern: string;
+  readonly site_roles: string[];
+  readonly organization_id?: string;
+  readonly organization_roles: string[];
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[];
@@ -1316,6 +1628,14 @@ export interface UpdateTemplateMeta {
   readonly deprecation_message?: string;
   readonly disable_everyone_group_access: boolean;
   readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
+  readonly max_workspaces?: number;
+}
+
+// From codersdk/templatereleasechannels.go
+export interface UpdateTemplateReleaseChannelRequest {
+  readonly template_version_id?: string;
+  readonly user_ids?: string[];
+  readonly group_ids?: string[];
 }
 
 // From codersdk/users.go
@@ -1323,6 +1643,11 @@ export interface UpdateUserAppearanceSettingsRequest {
   readonly theme_preference: string;
 }
 
+// From codersdk/notifications.go
+export interface UpdateUserNotificationPreferences {
+  readonly template_disabled_map: Record<string, boolean>;
+}
+
 // From codersdk/users.go
 export interface UpdateUserPasswordRequest {
   readonly old_password: string;
@@ -1340,6 +1665,21 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string;
 }
 
+// From codersdk/webhooks.go
+export interface UpdateWebhookRequest {
+  readonly name?: string;
+  readonly url?: string;
+  readonly events?: WebhookEvent[];
+  readonly secret?: string;
+  readonly enabled?: boolean;
+}
+
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+  readonly user_perms?: Record<string, WorkspaceRole>;
+  readonly group_perms?: Record<string, WorkspaceRole>;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
   readonly automatic_updates: AutomaticUpdates;
@@ -1448,6 +1788,14 @@ export interface UserLatencyInsightsResponse {
   readonly report: UserLatencyInsightsReport;
 }
 
+// From codersdk/loginlockouts.go
+export interface UserLoginLockout {
+  readonly user_id: string;
+  readonly failed_attempts: number;
+  readonly created_at: string;
+  readonly locked_until: string;
+}
+
 // From codersdk/users.go
 export interface UserLoginType {
   readonly login_type: LoginType;
@@ -1481,6 +1829,22 @@ export interface UserRoles {
   readonly organization_roles: Record<string, string[]>;
 }
 
+// From codersdk/usersessions.go
+export interface UserSession extends APIKey {
+  readonly type: UserSessionType;
+  readonly ip_address: string;
+  readonly user_agent: string;
+  readonly impersonated: boolean;
+  readonly current: boolean;
+}
+
+// From codersdk/totp.go
+export interface UserTOTP {
+  readonly enabled: boolean;
+  readonly required: boolean;
+  readonly recovery_codes_remaining: number;
+}
+
 // From codersdk/users.go
 export interface UsersRequest extends Pagination {
   readonly q?: string;
@@ -1498,6 +1862,90 @@ export interface VariableValue {
   readonly value: string;
 }
 
+// From codersdk/totp.go
+export interface VerifyTOTPRequest {
+  readonly code: string;
+}
+
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string;
+  readonly name: string;
+  readonly url: string;
+  readonly events: WebhookEvent[];
+  readonly has_secret: boolean;
+  readonly enabled: boolean;
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string;
+  readonly webhook_id: string;
+  readonly event: WebhookEvent;
+  readonly payload: Record<string, string>;
+  readonly status: WebhookDeliveryStatus;
+  readonly attempt_count: number;
+  readonly response_status?: number;
+  readonly response_body?: string;
+  readonly error?: string;
+  readonly created_at: string;
+  readonly updated_at: string;
+  readonly next_attempt_after?: string;
+  readonly delivered_at?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string;
+  readonly event: WebhookEvent;
+  readonly timestamp: string;
+  readonly data: Record<string, string>;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookTemplateVersionData {
+  readonly template_version_id: string;
+  readonly template_version_name: string;
+  readonly template_id: string;
+  readonly organization_id: string;
+  readonly initiator_id: string;
+  readonly initiator_name: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookUserData {
+  readonly user_id: string;
+  readonly username: string;
+  readonly email: string;
+  readonly initiator_id: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceBuildData {
+  readonly build_id: string;
+  readonly build_number: number;
+  readonly transition: WorkspaceTransition;
+  readonly reason: BuildReason;
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_id: string;
+  readonly owner_name: string;
+  readonly template_id: string;
+  readonly template_version_id: string;
+  readonly error?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceData {
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_id: string;
+  readonly owner_name: string;
+  readonly template_id: string;
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string;
@@ -1520,6 +1968,7 @@ export interface Workspace {
   readonly autostart_schedule?: string;
   readonly ttl_ms?: number;
   readonly last_used_at: string;
+  readonly template_release_channel?: string;
   readonly deleting_at?: string;
   readonly dormant_at?: string;
   readonly health: WorkspaceHealth;
@@ -1528,6 +1977,12 @@ export interface Workspace {
   readonly favorite: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+  readonly users: WorkspaceUser[];
+  readonly groups: WorkspaceGroup[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgent {
   readonly id: string;
@@ -1696,11 +2151,57 @@ export interface WorkspaceBuildParameter {
   readonly value: string;
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildState {
+  readonly build_id: string;
+  readonly build_number: number;
+  readonly transition: WorkspaceTransition;
+  readonly status: ProvisionerJobStatus;
+  readonly initiator_id: string;
+  readonly created_at: string;
+  readonly size: number;
+  readonly hash: string;
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildStateDiff {
+  readonly from_build_id: string;
+  readonly to_build_id: string;
+  readonly added: string[];
+  readonly removed: string[];
+  readonly changed: string[];
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly since?: string;
 }
 
+// From codersdk/workspacebulk.go
+export interface WorkspaceBulkOperation {
+  readonly id: string;
+  readonly initiator_id: string;
+  readonly action: WorkspaceBulkAction;
+  readonly q: string;
+  readonly concurrency: number;
+  readonly status: WorkspaceBulkOperationStatus;
+  readonly created_at: string;
+  readonly updated_at: string;
+  readonly completed_at?: string;
+  readonly workspaces: WorkspaceBulkOperationWorkspace[];
+}
+
+// From codersdk/workspacebulk.go
+export interface WorkspaceBulkOperationWorkspace {
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_name: string;
+  readonly status: WorkspaceBulkResultStatus;
+  readonly build_id?: string;
+  readonly error?: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/deployment.go
 export interface WorkspaceConnectionLatencyMS {
   readonly P50: number;
@@ -1724,6 +2225,11 @@ export interface WorkspaceFilter {
   readonly q?: string;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceGroup extends Group {
+  readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceHealth {
   readonly healthy: boolean;
@@ -1763,6 +2269,8 @@ export interface WorkspaceProxyStatus {
 export interface WorkspaceQuota {
   readonly credits_consumed: number;
   readonly budget: number;
+  readonly workspace_count: number;
+  readonly workspace_limit: number;
 }
 
 // From codersdk/workspacebuilds.go
@@ -1787,6 +2295,23 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean;
 }
 
+// From codersdk/workspacesnapshots.go
+export interface WorkspaceSnapshot {
+  readonly id: string;
+  readonly workspace_id: string;
+  readonly name: string;
+  readonly workspace_build_id: string;
+  readonly template_version_id: string;
+  readonly status: ProvisionerJobStatus;
+  readonly artifacts: Record<string, string>;
+  readonly created_at: string;
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends ReducedUser {
+  readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string;
@@ -1799,8 +2324,23 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export type APIKeyScope =
+  | "all"
+  | "application_connect"
+  | "template:push"
+  | "template:read"
+  | "user:read"
+  | "workspace:read"
+  | "workspace:start-stop";
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "template:push",
+  "template:read",
+  "user:read",
+  "workspace:read",
+  "workspace:start-stop",
+];
 
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace";
@@ -1935,8 +2475,8 @@ export const FeatureNames: FeatureName[] = [
 ];
 
 // From codersdk/groups.go
-export type GroupSource = "oidc" | "user";
-export const GroupSources: GroupSource[] = ["oidc", "user"];
+export type GroupSource = "oidc" | "scim" | "user";
+export const GroupSources: GroupSource[] = ["oidc", "scim", "user"];
 
 // From codersdk/insights.go
 export type InsightsReportInterval = "day" | "week";
@@ -2019,8 +2559,13 @@ export type ProvisionerStorageMethod = "file";
 export const ProvisionerStorageMethods: ProvisionerStorageMethod[] = ["file"];
 
 // From codersdk/organizations.go
-export type ProvisionerType = "echo" | "terraform";
-export const ProvisionerTypes: ProvisionerType[] = ["echo", "terraform"];
+export type ProvisionerType = "echo" | "kubernetes" | "opentofu" | "terraform";
+export const ProvisionerTypes: ProvisionerType[] = [
+  "echo",
+  "kubernetes",
+  "opentofu",
+  "terraform",
+];
 
 // From codersdk/workspaceproxy.go
 export type ProxyHealthStatus =
@@ -2042,6 +2587,7 @@ export type RBACResource =
   | "assign_org_role"
   | "assign_role"
   | "audit_log"
+  | "custom_role"
   | "debug_info"
   | "deployment_config"
   | "deployment_stats"
@@ -2067,6 +2613,7 @@ export const RBACResources: RBACResource[] = [
   "assign_org_role",
   "assign_role",
   "audit_log",
+  "custom_role",
   "debug_info",
   "deployment_config",
   "deployment_stats",
@@ -2092,32 +2639,42 @@ export const RBACResources: RBACResource[] = [
 export type ResourceType =
   | "api_key"
   | "convert_login"
+  | "custom_role"
   | "git_ssh_key"
   | "group"
   | "health_settings"
   | "license"
   | "oauth2_provider_app"
   | "oauth2_provider_app_secret"
+  | "oidc_sync_rule"
   | "organization"
+  | "scim_token"
   | "template"
   | "template_version"
   | "user"
+  | "user_login_lockout"
+  | "webhook"
   | "workspace"
   | "workspace_build"
   | "workspace_proxy";
 export const ResourceTypes: ResourceType[] = [
   "api_key",
   "convert_login",
+  "custom_role",
   "git_ssh_key",
   "group",
   "health_settings",
   "license",
   "oauth2_provider_app",
   "oauth2_provider_app_secret",
+  "oidc_sync_rule",
   "organization",
+  "scim_token",
   "template",
   "template_version",
   "user",
+  "user_login_lockout",
+  "webhook",
   "workspace",
   "workspace_build",
   "workspace_proxy",
@@ -2146,12 +2703,21 @@ export const TemplateInsightsSections: TemplateInsightsSection[] = [
 export type TemplateRole = "" | "admin" | "use";
 export const TemplateRoles: TemplateRole[] = ["", "admin", "use"];
 
+// From codersdk/templateversions.go
+export type TemplateVersionDiagnosticSeverity = "error" | "warning";
+export const TemplateVersionDiagnosticSeverities: TemplateVersionDiagnosticSeverity[] =
+  ["error", "warning"];
+
 // From codersdk/templateversions.go
 export type TemplateVersionWarning = "UNSUPPORTED_WORKSPACES";
 export const TemplateVersionWarnings: TemplateVersionWarning[] = [
   "UNSUPPORTED_WORKSPACES",
 ];
 
+// From codersdk/usersessions.go
+export type UserSessionType = "app" | "session" | "token";
+export const UserSessionTypes: UserSessionType[] = ["app", "session", "token"];
+
 // From codersdk/users.go
 export type UserStatus = "active" | "dormant" | "suspended";
 export const UserStatuses: UserStatus[] = ["active", "dormant", "suspended"];
@@ -2163,6 +2729,44 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ];
 
+// From codersdk/webhooks.go
+export type WebhookDeliveryStatus =
+  | "leased"
+  | "pending"
+  | "permanent_failure"
+  | "succeeded"
+  | "temporary_failure";
+export const WebhookDeliveryStatuses: WebhookDeliveryStatus[] = [
+  "leased",
+  "pending",
+  "permanent_failure",
+  "succeeded",
+  "temporary_failure",
+];
+
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template_version.promoted"
+  | "template_version.pushed"
+  | "user.created"
+  | "user.suspended"
+  | "workspace.created"
+  | "workspace.deleted"
+  | "workspace_build.failed"
+  | "workspace_build.started"
+  | "workspace_build.succeeded";
+export const WebhookEvents: WebhookEvent[] = [
+  "template_version.promoted",
+  "template_version.pushed",
+  "user.created",
+  "user.suspended",
+  "workspace.created",
+  "workspace.deleted",
+  "workspace_build.failed",
+  "workspace_build.started",
+  "workspace_build.succeeded",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -2238,6 +2842,47 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ];
 
+// From codersdk/workspacebulk.go
+export type WorkspaceBulkAction = "delete" | "start" | "stop" | "update";
+export const WorkspaceBulkActions: WorkspaceBulkAction[] = [
+  "delete",
+  "start",
+  "stop",
+  "update",
+];
+
+// From codersdk/workspacebulk.go
+export type WorkspaceBulkOperationStatus =
+  | "canceled"
+  | "completed"
+  | "dry_run"
+  | "running";
+export const WorkspaceBulkOperationStatuses: WorkspaceBulkOperationStatus[] = [
+  "canceled",
+  "completed",
+  "dry_run",
+  "running",
+];
+
+// From codersdk/workspacebulk.go
+export type WorkspaceBulkResultStatus =
+  | "failed"
+  | "pending"
+  | "running"
+  | "skipped"
+  | "succeeded";
+export const WorkspaceBulkResultStatuses: WorkspaceBulkResultStatus[] = [
+  "failed",
+  "pending",
+  "running",
+  "skipped",
+  "succeeded",
+];
+
+// From codersdk/workspaces.go
+export type WorkspaceRole = "" | "admin" | "use";
+export const WorkspaceRoles: WorkspaceRole[] = ["", "admin", "use"];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
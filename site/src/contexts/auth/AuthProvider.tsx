@@ -12,8 +12,10 @@ import {
   authMethods,
   hasFirstUser,
   login,
+  loginWithTOTP,
   logout,
   me,
+  type TOTPChallenge,
   updateProfile as updateProfileOptions,
 } from "api/queries/users";
 import type {
@@ -39,7 +41,11 @@ export type AuthContextValue = {
   signInError: unknown;
   updateProfileError: unknown;
   signOut: () => void;
-  signIn: (email: string, password: string) => Promise<void>;
+  signIn: (
+    email: string,
+    password: string,
+  ) => Promise<TOTPChallenge | undefined>;
+  signInWithTOTP: (ticket: string, code: string) => Promise<string[]>;
   updateProfile: (data: UpdateUserProfileRequest) => void;
 };
 
@@ -61,6 +67,9 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
   const loginMutation = useMutation(
     login({ checks: permissionsToCheck }, queryClient),
   );
+  const totpLoginMutation = useMutation(
+    loginWithTOTP({ checks: permissionsToCheck }, queryClient),
+  );
   const logoutMutation = useMutation(logout(queryClient));
   const updateProfileMutation = useMutation({
     ...updateProfileOptions("me"),
@@ -84,7 +93,7 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
   const isConfiguringTheFirstUser =
     !hasFirstUserQuery.isLoading && !hasFirstUserQuery.data;
   const isSignedIn = userQuery.isSuccess && userQuery.data !== undefined;
-  const isSigningIn = loginMutation.isLoading;
+  const isSigningIn = loginMutation.isLoading || totpLoginMutation.isLoading;
   const isUpdatingProfile = updateProfileMutation.isLoading;
 
   const signOut = useCallback(() => {
@@ -93,9 +102,22 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
 
   const signIn = useCallback(
     async (email: string, password: string) => {
-      await loginMutation.mutateAsync({ email, password });
+      totpLoginMutation.reset();
+      const data = await loginMutation.mutateAsync({ email, password });
+      return "totp" in data ? data.totp : undefined;
+    },
+    [loginMutation, totpLoginMutation],
+  );
+
+  // Finishes a login that needs a second factor. Returns the recovery codes
+  // when the login also confirmed a required enrollment, so they can be shown
+  // to the user.
+  const signInWithTOTP = useCallback(
+    async (ticket: string, code: string) => {
+      const data = await totpLoginMutation.mutateAsync({ ticket, code });
+      return data.recoveryCodes;
     },
-    [loginMutation],
+    [totpLoginMutation],
   );
 
   const updateProfile = useCallback(
@@ -117,11 +139,12 @@ export const AuthProvider: FC<PropsWithChildren> = ({ children }) => {
         isUpdatingProfile,
         signOut,
         signIn,
+        signInWithTOTP,
         updateProfile,
         user: userQuery.data,
         permissions: permissionsQuery.data as Permissions | undefined,
         authMethods: authMethodsQuery.data,
-        signInError: loginMutation.error,
+        signInError: totpLoginMutation.error ?? loginMutation.error,
         updateProfileError: updateProfileMutation.error,
         organizationId: userQuery.data?.organization_ids[0],
       }}
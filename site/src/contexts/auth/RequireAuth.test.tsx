@@ -50,6 +50,7 @@ const createAuthWrapper = (override: Partial<AuthContextValue>) => {
     updateProfileError: undefined,
     signOut: jest.fn(),
     signIn: jest.fn(),
+    signInWithTOTP: jest.fn(),
     updateProfile: jest.fn(),
     ...override,
   };
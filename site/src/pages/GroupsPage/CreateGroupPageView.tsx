@@ -38,6 +38,7 @@ export const CreateGroupPageView: FC<CreateGroupPageViewProps> = ({
       display_name: "",
       avatar_url: "",
       quota_allowance: 0,
+      max_workspaces_per_user: 0,
     },
     validationSchema,
     onSubmit,
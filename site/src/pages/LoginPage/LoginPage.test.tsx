@@ -2,6 +2,8 @@ import { fireEvent, screen } from "@testing-library/react";
 import userEvent from "@testing-library/user-event";
 import { HttpResponse, http } from "msw";
 import { createMemoryRouter } from "react-router-dom";
+import type { LoginWithTOTPRequest } from "api/typesGenerated";
+import { MockUser } from "testHelpers/entities";
 import {
   render,
   renderWithRouter,
@@ -11,6 +13,33 @@ import { server } from "testHelpers/server";
 import { LoginPage } from "./LoginPage";
 import { Language } from "./SignInForm";
 
+const renderLoginRoutes = () => {
+  renderWithRouter(
+    createMemoryRouter(
+      [
+        {
+          path: "/login",
+          element: <LoginPage />,
+        },
+        {
+          path: "/",
+          element: <h1>Workspaces</h1>,
+        },
+      ],
+      { initialEntries: ["/login"] },
+    ),
+  );
+};
+
+const signInWithPassword = async () => {
+  await waitForLoaderToBeRemoved();
+  const email = screen.getByLabelText(Language.emailLabel);
+  const password = screen.getByLabelText(Language.passwordLabel);
+  await userEvent.type(email, "test@coder.com");
+  await userEvent.type(password, "password");
+  fireEvent.click(await screen.findByText(Language.passwordSignIn));
+};
+
 describe("LoginPage", () => {
   beforeEach(() => {
     server.use(
@@ -76,4 +105,109 @@ describe("LoginPage", () => {
     // Then
     await screen.findByText("Setup");
   });
+
+  it("asks for a TOTP code after the password", async () => {
+    // Given
+    let signedIn = false;
+    server.use(
+      http.post("/api/v2/users/login", () => {
+        return HttpResponse.json(
+          { session_token: "", totp_ticket: "ticket" },
+          { status: 202 },
+        );
+      }),
+      http.post("/api/v2/users/login/totp", async ({ request }) => {
+        const req = (await request.json()) as LoginWithTOTPRequest;
+        if (req.ticket !== "ticket" || req.code !== "123456") {
+          return HttpResponse.json(
+            { message: "Incorrect authentication code." },
+            { status: 401 },
+          );
+        }
+        signedIn = true;
+        return HttpResponse.json({ session_token: "token" }, { status: 201 });
+      }),
+      http.get("/api/v2/users/me", () => {
+        if (!signedIn) {
+          return HttpResponse.json({ message: "no user" }, { status: 401 });
+        }
+        return HttpResponse.json(MockUser);
+      }),
+    );
+
+    // When
+    renderLoginRoutes();
+    await signInWithPassword();
+    const code = await screen.findByLabelText(Language.totpCodeLabel);
+    await userEvent.type(code, "000000");
+    fireEvent.click(screen.getByText(Language.totpSignIn));
+
+    // Then
+    await screen.findByText("Incorrect authentication code.");
+
+    // When
+    await userEvent.clear(code);
+    await userEvent.type(code, "123456");
+    fireEvent.click(screen.getByText(Language.totpSignIn));
+
+    // Then
+    await screen.findByText("Workspaces");
+  });
+
+  it("shows the recovery codes after a required enrollment", async () => {
+    // Given
+    let signedIn = false;
+    server.use(
+      http.post("/api/v2/users/login", () => {
+        return HttpResponse.json(
+          {
+            session_token: "",
+            totp_ticket: "ticket",
+            totp_enrollment_required: true,
+          },
+          { status: 202 },
+        );
+      }),
+      http.post("/api/v2/users/login/totp/enroll", () => {
+        return HttpResponse.json(
+          { secret: "JBSWY3DPEHPK3PXP", url: "otpauth://totp/Coder" },
+          { status: 201 },
+        );
+      }),
+      http.post("/api/v2/users/login/totp", () => {
+        signedIn = true;
+        return HttpResponse.json(
+          {
+            session_token: "token",
+            recovery_codes: ["aaaa-bbbb", "cccc-dddd"],
+          },
+          { status: 201 },
+        );
+      }),
+      http.get("/api/v2/users/me", () => {
+        if (!signedIn) {
+          return HttpResponse.json({ message: "no user" }, { status: 401 });
+        }
+        return HttpResponse.json(MockUser);
+      }),
+    );
+
+    // When
+    renderLoginRoutes();
+    await signInWithPassword();
+    await screen.findByText("JBSWY3DPEHPK3PXP");
+    const code = screen.getByLabelText(Language.totpCodeLabel);
+    await userEvent.type(code, "123456");
+    fireEvent.click(screen.getByText(Language.totpSignIn));
+
+    // Then
+    await screen.findByText("aaaa-bbbb");
+    expect(screen.queryByText("Workspaces")).toBeNull();
+
+    // When
+    fireEvent.click(screen.getByText(Language.recoveryCodesContinue));
+
+    // Then
+    await screen.findByText("Workspaces");
+  });
 });
@@ -1,6 +1,8 @@
-import type { FC } from "react";
+import { type FC, useState } from "react";
 import { Helmet } from "react-helmet-async";
+import { useMutation, useQueryClient } from "react-query";
 import { Navigate, useLocation, useNavigate } from "react-router-dom";
+import { loginTOTPEnroll, me, type TOTPChallenge } from "api/queries/users";
 import { useAuthContext } from "contexts/auth/AuthProvider";
 import { getApplicationName } from "utils/appearance";
 import { retrieveRedirect } from "utils/redirect";
@@ -13,6 +15,7 @@ export const LoginPage: FC = () => {
     isSignedIn,
     isConfiguringTheFirstUser,
     signIn,
+    signInWithTOTP,
     isSigningIn,
     authMethods,
     signInError,
@@ -20,6 +23,10 @@ export const LoginPage: FC = () => {
   const redirectTo = retrieveRedirect(location.search);
   const applicationName = getApplicationName();
   const navigate = useNavigate();
+  const queryClient = useQueryClient();
+  const [totpChallenge, setTOTPChallenge] = useState<TOTPChallenge>();
+  const [recoveryCodes, setRecoveryCodes] = useState<readonly string[]>();
+  const enrollMutation = useMutation(loginTOTPEnroll());
 
   if (isSignedIn) {
     // If the redirect is going to a workspace application, and we
@@ -61,13 +68,39 @@ export const LoginPage: FC = () => {
       </Helmet>
       <LoginPageView
         authMethods={authMethods}
-        error={signInError}
+        error={enrollMutation.error ?? signInError}
         isLoading={isLoading}
         isSigningIn={isSigningIn}
         onSignIn={async ({ email, password }) => {
-          await signIn(email, password);
+          const challenge = await signIn(email, password);
+          if (challenge) {
+            setTOTPChallenge(challenge);
+            if (challenge.enrollmentRequired) {
+              enrollMutation.mutate({ ticket: challenge.ticket });
+            }
+            return;
+          }
           navigate("/");
         }}
+        totpChallenge={totpChallenge}
+        totpEnrollment={enrollMutation.data}
+        recoveryCodes={recoveryCodes}
+        onSignInWithTOTP={async (code) => {
+          if (!totpChallenge) {
+            return;
+          }
+          const codes = await signInWithTOTP(totpChallenge.ticket, code);
+          if (codes.length > 0) {
+            setRecoveryCodes(codes);
+          }
+        }}
+        onCancelTOTP={() => {
+          setTOTPChallenge(undefined);
+          enrollMutation.reset();
+        }}
+        onContinue={async () => {
+          await queryClient.invalidateQueries(me().queryKey);
+        }}
       />
     </>
   );
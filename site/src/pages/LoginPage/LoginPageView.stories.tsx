@@ -63,3 +63,49 @@ export const SigningIn: Story = {
     authMethods: MockAuthMethodsPasswordOnly,
   },
 };
+
+export const TOTPCode: Story = {
+  args: {
+    authMethods: MockAuthMethodsPasswordOnly,
+    totpChallenge: { ticket: "ticket", enrollmentRequired: false },
+  },
+};
+
+export const TOTPCodeError: Story = {
+  args: {
+    error: mockApiError({
+      message: "Incorrect authentication code.",
+    }),
+    authMethods: MockAuthMethodsPasswordOnly,
+    totpChallenge: { ticket: "ticket", enrollmentRequired: false },
+  },
+};
+
+export const TOTPEnrollment: Story = {
+  args: {
+    authMethods: MockAuthMethodsPasswordOnly,
+    totpChallenge: { ticket: "ticket", enrollmentRequired: true },
+    totpEnrollment: {
+      secret: "JBSWY3DPEHPK3PXP",
+      url: "otpauth://totp/Coder:admin@coder.example.com?secret=JBSWY3DPEHPK3PXP&issuer=Coder",
+    },
+  },
+};
+
+export const TOTPRecoveryCodes: Story = {
+  args: {
+    authMethods: MockAuthMethodsPasswordOnly,
+    recoveryCodes: [
+      "a1b2-c3d4",
+      "e5f6-a7b8",
+      "c9d0-e1f2",
+      "a3b4-c5d6",
+      "e7f8-a9b0",
+      "c1d2-e3f4",
+      "a5b6-c7d8",
+      "e9f0-a1b2",
+      "c3d4-e5f6",
+      "a7b8-c9d0",
+    ],
+  },
+};
@@ -1,12 +1,15 @@
 import type { Interpolation, Theme } from "@emotion/react";
 import type { FC } from "react";
 import { useLocation } from "react-router-dom";
-import type { AuthMethods } from "api/typesGenerated";
+import type { TOTPChallenge } from "api/queries/users";
+import type { AuthMethods, TOTPEnrollment } from "api/typesGenerated";
 import { CoderIcon } from "components/Icons/CoderIcon";
 import { Loader } from "components/Loader/Loader";
 import { getApplicationName, getLogoURL } from "utils/appearance";
 import { retrieveRedirect } from "utils/redirect";
 import { SignInForm } from "./SignInForm";
+import { TOTPRecoveryCodes } from "./TOTPRecoveryCodes";
+import { TOTPSignInForm } from "./TOTPSignInForm";
 
 export interface LoginPageViewProps {
   authMethods: AuthMethods | undefined;
@@ -14,6 +17,13 @@ export interface LoginPageViewProps {
   isLoading: boolean;
   isSigningIn: boolean;
   onSignIn: (credentials: { email: string; password: string }) => void;
+  // Set once the password was accepted but a second factor is needed.
+  totpChallenge?: TOTPChallenge;
+  totpEnrollment?: TOTPEnrollment;
+  recoveryCodes?: readonly string[];
+  onSignInWithTOTP: (code: string) => void;
+  onCancelTOTP: () => void;
+  onContinue: () => void;
 }
 
 export const LoginPageView: FC<LoginPageViewProps> = ({
@@ -22,6 +32,12 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
   isLoading,
   isSigningIn,
   onSignIn,
+  totpChallenge,
+  totpEnrollment,
+  recoveryCodes,
+  onSignInWithTOTP,
+  onCancelTOTP,
+  onContinue,
 }) => {
   const location = useLocation();
   const redirectTo = retrieveRedirect(location.search);
@@ -52,6 +68,20 @@ export const LoginPageView: FC<LoginPageViewProps> = ({
         {applicationLogo}
         {isLoading ? (
           <Loader />
+        ) : recoveryCodes ? (
+          <TOTPRecoveryCodes
+            recoveryCodes={recoveryCodes}
+            onContinue={onContinue}
+          />
+        ) : totpChallenge ? (
+          <TOTPSignInForm
+            enrollmentRequired={totpChallenge.enrollmentRequired}
+            enrollment={totpEnrollment}
+            isSigningIn={isSigningIn}
+            error={error}
+            onSubmit={onSignInWithTOTP}
+            onCancel={onCancelTOTP}
+          />
         ) : (
           <SignInForm
             authMethods={authMethods}
@@ -15,6 +15,20 @@ export const Language = {
   passwordSignIn: "Sign In",
   githubSignIn: "GitHub",
   oidcSignIn: "OpenID Connect",
+  totpTitle: "Two-factor authentication",
+  totpDescription:
+    "Enter the code from your authenticator app, or one of your recovery codes.",
+  totpEnrollDescription:
+    "Two-factor authentication is required. Add this secret to your authenticator app, then enter the code it shows.",
+  totpEnrollLink: "Open in authenticator app",
+  totpCodeLabel: "Authentication code",
+  totpCodeRequired: "Please enter a code.",
+  totpSignIn: "Verify",
+  totpBack: "Back",
+  recoveryCodesTitle: "Save your recovery codes",
+  recoveryCodesDescription:
+    "Each code can be used once instead of a code from your authenticator app. They will not be shown again.",
+  recoveryCodesContinue: "Continue",
 };
 
 const styles = {
@@ -0,0 +1,80 @@
+import type { Interpolation, Theme } from "@emotion/react";
+import Button from "@mui/material/Button";
+import type { FC } from "react";
+import { CopyButton } from "components/CopyButton/CopyButton";
+import { Stack } from "components/Stack/Stack";
+import { MONOSPACE_FONT_FAMILY } from "theme/constants";
+import { Language } from "./SignInForm";
+
+export interface TOTPRecoveryCodesProps {
+  recoveryCodes: readonly string[];
+  onContinue: () => void;
+}
+
+/**
+ * Shows the recovery codes of an enrollment that was confirmed while logging
+ * in. They are only returned once, so the login waits until the user has
+ * saved them.
+ */
+export const TOTPRecoveryCodes: FC<TOTPRecoveryCodesProps> = ({
+  recoveryCodes,
+  onContinue,
+}) => {
+  return (
+    <div css={styles.root}>
+      <h1 css={styles.title}>{Language.recoveryCodesTitle}</h1>
+      <Stack spacing={2.5}>
+        <p css={styles.description}>{Language.recoveryCodesDescription}</p>
+        <div css={styles.codes}>
+          <ul css={styles.list}>
+            {recoveryCodes.map((code) => (
+              <li key={code}>{code}</li>
+            ))}
+          </ul>
+          <CopyButton text={recoveryCodes.join("\n")} />
+        </div>
+        <Button size="xlarge" fullWidth onClick={onContinue}>
+          {Language.recoveryCodesContinue}
+        </Button>
+      </Stack>
+    </div>
+  );
+};
+
+const styles = {
+  root: {
+    width: "100%",
+  },
+  title: {
+    fontSize: 32,
+    fontWeight: 400,
+    margin: 0,
+    marginBottom: 32,
+    lineHeight: 1,
+  },
+  description: (theme) => ({
+    margin: 0,
+    fontSize: 14,
+    color: theme.palette.text.secondary,
+    textAlign: "left",
+  }),
+  codes: (theme) => ({
+    display: "flex",
+    alignItems: "flex-start",
+    padding: 8,
+    borderRadius: 8,
+    border: `1px solid ${theme.experimental.l1.outline}`,
+  }),
+  list: {
+    flexGrow: 1,
+    margin: 0,
+    padding: "0 8px",
+    listStyle: "none",
+    display: "grid",
+    gridTemplateColumns: "1fr 1fr",
+    gap: 4,
+    fontFamily: MONOSPACE_FONT_FAMILY,
+    fontSize: 14,
+    textAlign: "left",
+  },
+} satisfies Record<string, Interpolation<Theme>>;
@@ -0,0 +1,127 @@
+import type { Interpolation, Theme } from "@emotion/react";
+import LoadingButton from "@mui/lab/LoadingButton";
+import Button from "@mui/material/Button";
+import Link from "@mui/material/Link";
+import TextField from "@mui/material/TextField";
+import { useFormik } from "formik";
+import type { FC } from "react";
+import * as Yup from "yup";
+import type { TOTPEnrollment } from "api/typesGenerated";
+import { ErrorAlert } from "components/Alert/ErrorAlert";
+import { CodeExample } from "components/CodeExample/CodeExample";
+import { Loader } from "components/Loader/Loader";
+import { Stack } from "components/Stack/Stack";
+import { getFormHelpers, onChangeTrimmed } from "utils/formUtils";
+import { Language } from "./SignInForm";
+
+export interface TOTPSignInFormProps {
+  // Set when the deployment requires a second factor the user has not
+  // enrolled in yet.
+  enrollmentRequired: boolean;
+  enrollment?: TOTPEnrollment;
+  isSigningIn: boolean;
+  error?: unknown;
+  onSubmit: (code: string) => void;
+  onCancel: () => void;
+}
+
+export const TOTPSignInForm: FC<TOTPSignInFormProps> = ({
+  enrollmentRequired,
+  enrollment,
+  isSigningIn,
+  error,
+  onSubmit,
+  onCancel,
+}) => {
+  const validationSchema = Yup.object({
+    code: Yup.string().trim().required(Language.totpCodeRequired),
+  });
+
+  const form = useFormik({
+    initialValues: {
+      code: "",
+    },
+    validationSchema,
+    onSubmit: ({ code }) => onSubmit(code),
+    validateOnBlur: false,
+  });
+  const getFieldHelpers = getFormHelpers(form);
+
+  return (
+    <div css={styles.root}>
+      <h1 css={styles.title}>{Language.totpTitle}</h1>
+
+      {Boolean(error) && (
+        <div css={styles.alert}>
+          <ErrorAlert error={error} />
+        </div>
+      )}
+
+      {enrollmentRequired ? (
+        enrollment ? (
+          <Stack spacing={1} css={styles.description}>
+            <p>{Language.totpEnrollDescription}</p>
+            <CodeExample code={enrollment.secret} secret={false} />
+            <Link href={enrollment.url}>{Language.totpEnrollLink}</Link>
+          </Stack>
+        ) : (
+          !error && <Loader />
+        )
+      ) : (
+        <p css={styles.description}>{Language.totpDescription}</p>
+      )}
+
+      <form onSubmit={form.handleSubmit}>
+        <Stack spacing={2.5}>
+          <TextField
+            {...getFieldHelpers("code")}
+            onChange={onChangeTrimmed(form)}
+            autoFocus
+            autoComplete="one-time-code"
+            fullWidth
+            label={Language.totpCodeLabel}
+            disabled={enrollmentRequired && !enrollment}
+          />
+          <LoadingButton
+            size="xlarge"
+            loading={isSigningIn}
+            disabled={enrollmentRequired && !enrollment}
+            fullWidth
+            type="submit"
+          >
+            {Language.totpSignIn}
+          </LoadingButton>
+          <Button size="large" variant="text" fullWidth onClick={onCancel}>
+            {Language.totpBack}
+          </Button>
+        </Stack>
+      </form>
+    </div>
+  );
+};
+
+const styles = {
+  root: {
+    width: "100%",
+  },
+  title: {
+    fontSize: 32,
+    fontWeight: 400,
+    margin: 0,
+    marginBottom: 32,
+    lineHeight: 1,
+  },
+  alert: {
+    marginBottom: 32,
+  },
+  description: (theme) => ({
+    margin: "0 0 24px",
+    fontSize: 14,
+    color: theme.palette.text.secondary,
+    textAlign: "left",
+
+    "& p": {
+      margin: 0,
+    },
+  }),
+} satisfies Record<string, Interpolation<Theme>>;
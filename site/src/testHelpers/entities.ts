@@ -503,6 +503,7 @@ export const MockTemplate: TypesGen.Template = {
   deprecated: false,
   deprecation_message: "",
   max_port_share_level: "public",
+  max_workspaces: 0,
 };
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -2259,6 +2260,8 @@ export const MockAuditLogUnsuccessfulLoginKnownUser: TypesGen.AuditLog = {
 export const MockWorkspaceQuota: TypesGen.WorkspaceQuota = {
   credits_consumed: 0,
   budget: 100,
+  workspace_count: 0,
+  workspace_limit: 0,
 };
 
 export const MockGroup: TypesGen.Group = {
@@ -2270,6 +2273,7 @@ export const MockGroup: TypesGen.Group = {
   members: [MockUser, MockUser2],
   quota_allowance: 5,
   source: "user",
+  max_workspaces_per_user: 0,
 };
 
 const everyOneGroup = (organizationId: string): TypesGen.Group => ({
@@ -2281,6 +2285,7 @@ const everyOneGroup = (organizationId: string): TypesGen.Group => ({
   avatar_url: "",
   quota_allowance: 0,
   source: "user",
+  max_workspaces_per_user: 0,
 });
 
 export const MockTemplateACL: TypesGen.TemplateACL = {